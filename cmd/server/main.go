@@ -57,6 +57,11 @@ func main() {
 	r := router.NewRouter(db, cfg)
 	r.SetupRoutes()
 
+	// Arka plan işlerini başlat (rezervasyon süre kontrolü vb.)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	r.StartWorkers(workerCtx)
+
 	// Graceful shutdown için kanal oluştur
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -75,6 +80,7 @@ func main() {
 	// Shutdown sinyalini bekle
 	<-shutdown
 	logger.Info("Graceful shutdown başlatılıyor...")
+	stopWorkers()
 
 	// Shutdown timeout context'i oluştur
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.AppConfig.ShutdownTimeout)*time.Second)
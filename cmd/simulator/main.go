@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/simulator"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+/*
+=======KULLANIM=======
+# 1-5 id'li motorları çalıştır; cihaz anahtarları admin token'ı ile üretilir ve keys.txt'ye yazılır
+go run cmd/simulator/main.go -bikes 5 -admin-token <jwt> -keys-file keys.txt
+
+# Kayıtlı anahtarlarla belirli motorları GeoJSON güzergah üzerinde çalıştır
+go run cmd/simulator/main.go -ids 3,7 -keys-file keys.txt -route route.geojson -speed 25
+
+# Her 2 dakikada rastgele bir motora 30 saniyelik arıza enjekte et
+go run cmd/simulator/main.go -bikes 5 -keys-file keys.txt -faults lost_connection,lock_jam -fault-every 2m -fault-duration 30s
+
+Çalışırken standart girişten komut verilebilir:
+  fault <id> <arıza>    arızayı başlatır (low_battery, lost_connection, tamper, lock_jam)
+  clear <id> [arıza]    arızayı (verilmezse tüm arızaları) giderir
+  status                motorların durumunu yazdırır
+*/
+
+func main() {
+	var (
+		server        = flag.String("server", "http://localhost:3005/api/v1", "API adresi (önek dahil)")
+		bikes         = flag.Int("bikes", 1, "Çalıştırılacak motor sayısı (-ids verilmezse)")
+		firstID       = flag.Int64("first-id", 1, "-bikes ile kullanılan ilk motor id'si")
+		ids           = flag.String("ids", "", "Virgülle ayrılmış motor id'leri")
+		keysFile      = flag.String("keys-file", "", "Her satırda '<motor_id> <cihaz_anahtarı>' olan dosya")
+		adminToken    = flag.String("admin-token", "", "Anahtarı olmayan motorlar için yeni anahtar üretmekte kullanılan admin JWT'si")
+		routeFile     = flag.String("route", "", "GeoJSON LineString güzergah dosyası (verilmezse -center etrafında daire)")
+		center        = flag.String("center", "41.0082,28.9784", "Dairesel güzergahın merkezi (enlem,boylam)")
+		radius        = flag.Float64("radius", 500, "Dairesel güzergahın yarıçapı (metre)")
+		speed         = flag.Float64("speed", 20, "Kilidi açık motorun hızı (km/s)")
+		interval      = flag.Duration("interval", 5*time.Second, "Telemetri gönderme aralığı")
+		faults        = flag.String("faults", "", "Rastgele enjekte edilecek arızalar (virgülle ayrılmış)")
+		faultEvery    = flag.Duration("fault-every", 0, "Rastgele arıza enjeksiyon aralığı (0 kapalı)")
+		faultDuration = flag.Duration("fault-duration", 30*time.Second, "Enjekte edilen arızanın süresi")
+	)
+	flag.Parse()
+
+	motorbikeIDs, err := parseIDs(*ids, *bikes, *firstID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	route, err := loadRoute(*routeFile, *center, *radius)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var randomFaults []simulator.Fault
+	if *faults != "" {
+		for _, name := range strings.Split(*faults, ",") {
+			f, err := simulator.ParseFault(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			randomFaults = append(randomFaults, f)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := simulator.NewClient(*server, nil)
+	keys, err := provisionKeys(ctx, client, motorbikeIDs, *keysFile, *adminToken)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Aynı güzergahı paylaşan motorlar güzergah boyunca eşit aralıklarla başlar
+	fleet := simulator.NewFleet()
+	spacing := route.Length() / float64(len(motorbikeIDs))
+	for i, id := range motorbikeIDs {
+		bike := simulator.NewBike(id, keys[id], route, *speed)
+		bike.SetPosition(float64(i) * spacing)
+		fleet.Add(bike)
+	}
+
+	var wg sync.WaitGroup
+	for _, bike := range fleet.Bikes() {
+		wg.Add(1)
+		go func(bike *simulator.Bike) {
+			defer wg.Done()
+			simulator.RunBike(ctx, client, bike, *interval, log.Printf)
+		}(bike)
+	}
+	log.Printf("%d motor çalışıyor (%s)", len(motorbikeIDs), *server)
+
+	if *faultEvery > 0 && len(randomFaults) > 0 {
+		go injectFaults(ctx, fleet, randomFaults, *faultEvery, *faultDuration)
+	}
+	go readCommands(fleet)
+
+	<-ctx.Done()
+	wg.Wait()
+	log.Println("Simülatör durduruldu")
+}
+
+func parseIDs(ids string, count int, firstID int64) ([]int64, error) {
+	if ids == "" {
+		if count < 1 {
+			return nil, fmt.Errorf("en az bir motor gerekli")
+		}
+		result := make([]int64, count)
+		for i := range result {
+			result[i] = firstID + int64(i)
+		}
+		return result, nil
+	}
+
+	var result []int64
+	for _, s := range strings.Split(ids, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("geçersiz motor id'si %q", s)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+func loadRoute(path, center string, radius float64) (simulator.Route, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return simulator.LoadRoute(data)
+	}
+
+	parts := strings.Split(center, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("merkez 'enlem,boylam' biçiminde olmalı")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz enlem: %w", err)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz boylam: %w", err)
+	}
+	return simulator.CircleRoute(geo.Point{Lat: lat, Lng: lng}, radius, 36), nil
+}
+
+// provisionKeys anahtarları dosyadan okur; eksik olanları admin token'ı ile üretip dosyaya geri yazar
+func provisionKeys(ctx context.Context, client *simulator.Client, ids []int64, keysFile, adminToken string) (map[int64]string, error) {
+	keys := map[int64]string{}
+	if keysFile != "" {
+		if err := readKeys(keysFile, keys); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	provisioned := false
+	for _, id := range ids {
+		if keys[id] != "" {
+			continue
+		}
+		if adminToken == "" {
+			return nil, fmt.Errorf("motor %d için cihaz anahtarı yok; -keys-file veya -admin-token verin", id)
+		}
+		key, err := client.RotateDeviceKey(ctx, adminToken, id)
+		if err != nil {
+			return nil, fmt.Errorf("motor %d için anahtar üretilemedi: %w", id, err)
+		}
+		keys[id] = key
+		provisioned = true
+		log.Printf("motor %d için yeni cihaz anahtarı üretildi", id)
+	}
+
+	if provisioned && keysFile != "" {
+		if err := writeKeys(keysFile, keys); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+func readKeys(path string, keys map[int64]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("%s: geçersiz satır %q", path, line)
+		}
+		id, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: geçersiz motor id'si %q", path, fields[0])
+		}
+		keys[id] = fields[1]
+	}
+	return scanner.Err()
+}
+
+func writeKeys(path string, keys map[int64]string) error {
+	ids := make([]int64, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%d %s\n", id, keys[id])
+	}
+	return os.WriteFile(path, []byte(b.String()), 0600)
+}
+
+func injectFaults(ctx context.Context, fleet *simulator.Fleet, faults []simulator.Fault, every, duration time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		bikes := fleet.Bikes()
+		bike := bikes[rand.Intn(len(bikes))]
+		fault := faults[rand.Intn(len(faults))]
+		bike.SetFault(fault)
+		log.Printf("motor %d: %s arızası başladı (%s)", bike.ID, fault, duration)
+
+		time.AfterFunc(duration, func() {
+			bike.ClearFault(fault)
+			log.Printf("motor %d: %s arızası giderildi", bike.ID, fault)
+		})
+	}
+}
+
+func readCommands(fleet *simulator.Fleet) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "status":
+			for _, bike := range fleet.Bikes() {
+				log.Printf("motor %d: online=%t kilit=%s batarya=%%%d devre_dışı=%t arızalar=%v",
+					bike.ID, bike.Online(), bike.LockStatus(), bike.Battery(), bike.Disabled(), bike.Faults())
+			}
+		case "fault", "clear":
+			if len(fields) < 2 {
+				log.Printf("kullanım: %s <id> [arıza]", fields[0])
+				continue
+			}
+			id, err := strconv.ParseInt(fields[1], 10, 64)
+			bike := fleet.Bike(id)
+			if err != nil || bike == nil {
+				log.Printf("motor bulunamadı: %s", fields[1])
+				continue
+			}
+
+			var fault simulator.Fault
+			if len(fields) > 2 {
+				if fault, err = simulator.ParseFault(fields[2]); err != nil {
+					log.Print(err)
+					continue
+				}
+			}
+			if fields[0] == "clear" {
+				bike.ClearFault(fault)
+				log.Printf("motor %d: arıza giderildi %s", id, fault)
+				continue
+			}
+			if fault == "" {
+				log.Printf("kullanım: fault <id> <arıza>")
+				continue
+			}
+			bike.SetFault(fault)
+			log.Printf("motor %d: %s arızası başladı", id, fault)
+		default:
+			log.Printf("bilinmeyen komut %q (fault, clear, status)", fields[0])
+		}
+	}
+}
@@ -5,15 +5,31 @@ import (
 	"github.com/joho/godotenv"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
-	AppConfig        AppConfig
-	DBConfig         DBConfig
-	RedisConfig      RedisConfig
-	JWTConfig        JWTConfig
-	MonitoringConfig MonitoringConfig
-	MailConfig       MailConfig
+	AppConfig         AppConfig
+	DBConfig          DBConfig
+	RedisConfig       RedisConfig
+	JWTConfig         JWTConfig
+	MonitoringConfig  MonitoringConfig
+	MailConfig        MailConfig
+	PricingConfig     PricingConfig
+	ReservationConfig ReservationConfig
+	DeviceConfig      DeviceConfig
+	PaymentConfig     PaymentConfig
+	ReferralConfig    ReferralConfig
+	PassConfig        PassConfig
+	InvoiceConfig     InvoiceConfig
+	DisputeConfig     DisputeConfig
+	RideConfig        RideConfig
+	StorageConfig     StorageConfig
+	MotorbikeConfig   MotorbikeConfig
+	MaintenanceConfig MaintenanceConfig
+	IssueConfig       IssueConfig
+	TaskConfig        TaskConfig
+	AuditConfig       AuditConfig
 }
 
 type AppConfig struct {
@@ -81,6 +97,113 @@ type MailConfig struct {
 	FromEmail    string
 }
 
+type PricingConfig struct {
+	Timezone string // gece ve hafta sonu tarifeleri bu saat dilimine göre uygulanır
+}
+
+type ReservationConfig struct {
+	HoldMinutes           int   // motorun rezervasyonla tutulacağı süre
+	Fee                   int64 // kuruş cinsinden, sürüşe dönüşürse sürüş ücretine eklenir
+	ExpiryIntervalSeconds int   // süresi dolan rezervasyonları tarayan worker'ın çalışma aralığı
+}
+
+type DeviceConfig struct {
+	CommandAckTimeoutSeconds    int    // cihaza gönderilen komutun onayı için beklenen süre, dolarsa komut tekrar gönderilir
+	CommandMaxAttempts          int    // bir komutun cihaza en fazla kaç kez gönderileceği
+	CommandTTLSeconds           int    // komutun toplam geçerlilik süresi, dolarsa komut iptal edilir
+	CommandSweepIntervalSeconds int    // zaman aşımına uğrayan komutları tarayan worker'ın çalışma aralığı
+	CommandLongPollMaxSeconds   int    // cihazın yeni komut için en fazla ne kadar bekleyebileceği
+	LockController              string // none: kilit yalnızca veritabanında tutulur, device: kilit komutları cihaza gönderilir
+	LockTimeoutSeconds          int    // kilit açma/kapama komutunun onayı için sürüş akışının bekleyeceği süre
+	OnlineWindowSeconds         int    // bu süre içinde telemetri göndermeyen cihaz çevrimdışı sayılır
+}
+
+type PaymentConfig struct {
+	Provider                   string // fake: ağ bağlantısı olmadan çalışan sahte sağlayıcı
+	HoldAmount                 int64  // sürüş başlarken karttan bloke edilen tutar (kuruş)
+	WebhookSecret              string // sağlayıcının webhook imzalarını doğrulamak için paylaşılan anahtar
+	FakeBehavior               string // sahte sağlayıcının cevabı: succeed, decline veya timeout
+	ProviderTimeoutSeconds     int    // sağlayıcıya yapılan tek bir çağrı için beklenen en uzun süre
+	SettleSweepIntervalSeconds int    // ödemesi yarıda kalıp ending durumunda kalan sürüşleri payment_failed yapan worker'ın çalışma aralığı
+}
+
+type ReferralConfig struct {
+	ReferrerCredit int64 // davet edilen kullanıcı ilk sürüşünü tamamlayınca davet edene yüklenen kredi (kuruş)
+	RefereeCredit  int64 // davet edilen kullanıcıya ilk sürüşünden sonra yüklenen kredi (kuruş)
+}
+
+type PassConfig struct {
+	RenewalIntervalSeconds int // dönemi biten abonelikleri yenileyen worker'ın çalışma aralığı
+}
+
+type InvoiceConfig struct {
+	CompanyName           string // fiş ve faturalarda görünen şirket unvanı
+	CompanyAddress        string
+	CompanyTaxOffice      string
+	CompanyTaxNumber      string
+	CompanyEmail          string
+	VATRatePct            int // fiyatlara dahil KDV oranı
+	WorkerIntervalSeconds int // aylık faturaları kesen ve gönderilemeyen e-postaları tekrar deneyen worker'ın çalışma aralığı
+}
+
+type DisputeConfig struct {
+	WindowDays     int    // sürüş bittikten sonra itiraz açılabilecek gün sayısı
+	MaxPhotos      int    // bir itiraza eklenebilecek en fazla fotoğraf
+	MaxPhotoSizeMB int    // fotoğraf başına en büyük dosya boyutu
+	SupportEmail   string // boş değilse yeni itirazlar bu adrese bildirilir
+}
+
+type RideConfig struct {
+	MaxPauseMinutes           int // park modunda geçirilebilecek en uzun süre, dolunca sürüş otomatik bitirilir
+	PauseSweepIntervalSeconds int // park modu süresi dolan sürüşleri bitiren worker'ın çalışma aralığı
+	PhotoMaxSizeMB            int // sürüş sonu park fotoğrafının en büyük dosya boyutu
+}
+
+type MotorbikeConfig struct {
+	PhotoMaxSizeMB int // galeri fotoğrafı başına en büyük dosya boyutu
+	MaxPhotos      int // bir motorun galerisindeki en fazla fotoğraf
+}
+
+type MaintenanceConfig struct {
+	ScheduleIntervalSeconds int // koruyucu bakım planlarını kontrol eden worker'ın çalışma aralığı
+}
+
+type IssueConfig struct {
+	FlagThreshold       int // motoru bakıma alan, farklı kullanıcılardan gelen açık bildirim sayısı
+	FlagWindowHours     int // bildirimlerin sayıldığı süre
+	FlagIntervalSeconds int // kullanımdayken bakıma alınamayan motorları tekrar deneyen worker'ın çalışma aralığı
+	MaxPhotos           int // bir bildirime eklenebilecek en fazla fotoğraf
+	PhotoMaxSizeMB      int // fotoğraf başına en büyük dosya boyutu
+}
+
+type TaskConfig struct {
+	LowBatteryPercent       int // bu seviyenin altındaki müsait motorlar için batarya değişimi görevi açılır
+	IdleHours               int // bu süre boyunca kiralanmayan müsait motorlar için konum değişikliği görevi açılır
+	GenerateIntervalSeconds int // otomatik görev üreten worker'ın çalışma aralığı
+	RebalanceSLAMinutes     int // görev türlerine göre varsayılan tamamlanma süreleri
+	BatterySwapSLAMinutes   int
+	CollectSLAMinutes       int
+	InspectSLAMinutes       int
+	MaxPhotos               int // görev tamamlanırken eklenebilecek en fazla fotoğraf
+	PhotoMaxSizeMB          int // fotoğraf başına en büyük dosya boyutu
+}
+
+type AuditConfig struct {
+	ExportMaxRows int // tek seferde CSV olarak dışa aktarılabilecek en fazla denetim kaydı
+}
+
+type StorageConfig struct {
+	Driver        string // local veya s3
+	LocalDir      string // local sürücüde dosyaların saklandığı dizin
+	S3Endpoint    string // S3 uyumlu servis adresi, örn. http://localhost:9000 (MinIO)
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	URLSecret     string // dosya bağlantılarını imzalayan anahtar
+	URLTTLMinutes int    // imzalı bağlantının geçerlilik süresi
+}
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		return nil, err
@@ -142,6 +265,98 @@ func LoadConfig() (*Config, error) {
 			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 			FromEmail:    getEnv("SMTP_FROM_EMAIL", ""),
 		},
+		PricingConfig: PricingConfig{
+			Timezone: getEnv("PRICING_TIMEZONE", "Europe/Istanbul"),
+		},
+		ReservationConfig: ReservationConfig{
+			HoldMinutes:           getEnvAsInt("RESERVATION_HOLD_MINUTES", 10),
+			Fee:                   int64(getEnvAsInt("RESERVATION_FEE", 0)),
+			ExpiryIntervalSeconds: getEnvAsInt("RESERVATION_EXPIRY_INTERVAL_SECONDS", 30),
+		},
+		DeviceConfig: DeviceConfig{
+			CommandAckTimeoutSeconds:    getEnvAsInt("DEVICE_COMMAND_ACK_TIMEOUT_SECONDS", 15),
+			CommandMaxAttempts:          getEnvAsInt("DEVICE_COMMAND_MAX_ATTEMPTS", 3),
+			CommandTTLSeconds:           getEnvAsInt("DEVICE_COMMAND_TTL_SECONDS", 300),
+			CommandSweepIntervalSeconds: getEnvAsInt("DEVICE_COMMAND_SWEEP_INTERVAL_SECONDS", 5),
+			CommandLongPollMaxSeconds:   getEnvAsInt("DEVICE_COMMAND_LONG_POLL_MAX_SECONDS", 30),
+			LockController:              getEnv("LOCK_CONTROLLER", "none"),
+			LockTimeoutSeconds:          getEnvAsInt("LOCK_TIMEOUT_SECONDS", 20),
+			OnlineWindowSeconds:         getEnvAsInt("DEVICE_ONLINE_WINDOW_SECONDS", 120),
+		},
+		PaymentConfig: PaymentConfig{
+			Provider:                   getEnv("PAYMENT_PROVIDER", "fake"),
+			HoldAmount:                 int64(getEnvAsInt("PAYMENT_HOLD_AMOUNT", 5000)),
+			WebhookSecret:              getEnv("PAYMENT_WEBHOOK_SECRET", "local-webhook-secret"),
+			FakeBehavior:               getEnv("PAYMENT_FAKE_BEHAVIOR", "succeed"),
+			ProviderTimeoutSeconds:     getEnvAsInt("PAYMENT_PROVIDER_TIMEOUT_SECONDS", 10),
+			SettleSweepIntervalSeconds: getEnvAsInt("PAYMENT_SETTLE_SWEEP_INTERVAL_SECONDS", 60),
+		},
+		ReferralConfig: ReferralConfig{
+			ReferrerCredit: int64(getEnvAsInt("REFERRAL_REFERRER_CREDIT", 2500)),
+			RefereeCredit:  int64(getEnvAsInt("REFERRAL_REFEREE_CREDIT", 2500)),
+		},
+		PassConfig: PassConfig{
+			RenewalIntervalSeconds: getEnvAsInt("PASS_RENEWAL_INTERVAL_SECONDS", 300),
+		},
+		InvoiceConfig: InvoiceConfig{
+			CompanyName:           getEnv("INVOICE_COMPANY_NAME", "Motorbike Rental"),
+			CompanyAddress:        getEnv("INVOICE_COMPANY_ADDRESS", ""),
+			CompanyTaxOffice:      getEnv("INVOICE_COMPANY_TAX_OFFICE", ""),
+			CompanyTaxNumber:      getEnv("INVOICE_COMPANY_TAX_NUMBER", ""),
+			CompanyEmail:          getEnv("INVOICE_COMPANY_EMAIL", ""),
+			VATRatePct:            getEnvAsInt("INVOICE_VAT_RATE_PCT", 20),
+			WorkerIntervalSeconds: getEnvAsInt("INVOICE_WORKER_INTERVAL_SECONDS", 3600),
+		},
+		DisputeConfig: DisputeConfig{
+			WindowDays:     getEnvAsInt("DISPUTE_WINDOW_DAYS", 30),
+			MaxPhotos:      getEnvAsInt("DISPUTE_MAX_PHOTOS", 5),
+			MaxPhotoSizeMB: getEnvAsInt("DISPUTE_MAX_PHOTO_SIZE_MB", 5),
+			SupportEmail:   getEnv("DISPUTE_SUPPORT_EMAIL", ""),
+		},
+		RideConfig: RideConfig{
+			MaxPauseMinutes:           getEnvAsInt("RIDE_MAX_PAUSE_MINUTES", 30),
+			PauseSweepIntervalSeconds: getEnvAsInt("RIDE_PAUSE_SWEEP_INTERVAL_SECONDS", 60),
+			PhotoMaxSizeMB:            getEnvAsInt("RIDE_PHOTO_MAX_SIZE_MB", 10),
+		},
+		StorageConfig: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:      getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			S3Endpoint:    getEnv("STORAGE_S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:      getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("STORAGE_S3_BUCKET", "motorbike-rental"),
+			S3AccessKey:   getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("STORAGE_S3_SECRET_KEY", ""),
+			URLSecret:     getEnv("STORAGE_URL_SECRET", "local-storage-secret"),
+			URLTTLMinutes: getEnvAsInt("STORAGE_URL_TTL_MINUTES", 15),
+		},
+		MotorbikeConfig: MotorbikeConfig{
+			PhotoMaxSizeMB: getEnvAsInt("MOTORBIKE_PHOTO_MAX_SIZE_MB", 10),
+			MaxPhotos:      getEnvAsInt("MOTORBIKE_MAX_PHOTOS", 10),
+		},
+		MaintenanceConfig: MaintenanceConfig{
+			ScheduleIntervalSeconds: getEnvAsInt("MAINTENANCE_SCHEDULE_INTERVAL_SECONDS", 3600),
+		},
+		IssueConfig: IssueConfig{
+			FlagThreshold:       getEnvAsInt("ISSUE_FLAG_THRESHOLD", 3),
+			FlagWindowHours:     getEnvAsInt("ISSUE_FLAG_WINDOW_HOURS", 24),
+			FlagIntervalSeconds: getEnvAsInt("ISSUE_FLAG_INTERVAL_SECONDS", 60),
+			MaxPhotos:           getEnvAsInt("ISSUE_MAX_PHOTOS", 5),
+			PhotoMaxSizeMB:      getEnvAsInt("ISSUE_PHOTO_MAX_SIZE_MB", 10),
+		},
+		TaskConfig: TaskConfig{
+			LowBatteryPercent:       getEnvAsInt("TASK_LOW_BATTERY_PERCENT", 20),
+			IdleHours:               getEnvAsInt("TASK_IDLE_HOURS", 72),
+			GenerateIntervalSeconds: getEnvAsInt("TASK_GENERATE_INTERVAL_SECONDS", 300),
+			RebalanceSLAMinutes:     getEnvAsInt("TASK_REBALANCE_SLA_MINUTES", 240),
+			BatterySwapSLAMinutes:   getEnvAsInt("TASK_BATTERY_SWAP_SLA_MINUTES", 120),
+			CollectSLAMinutes:       getEnvAsInt("TASK_COLLECT_SLA_MINUTES", 60),
+			InspectSLAMinutes:       getEnvAsInt("TASK_INSPECT_SLA_MINUTES", 480),
+			MaxPhotos:               getEnvAsInt("TASK_MAX_PHOTOS", 5),
+			PhotoMaxSizeMB:          getEnvAsInt("TASK_PHOTO_MAX_SIZE_MB", 10),
+		},
+		AuditConfig: AuditConfig{
+			ExportMaxRows: getEnvAsInt("AUDIT_EXPORT_MAX_ROWS", 50000),
+		},
 	}
 
 	return config, nil
@@ -184,3 +399,132 @@ func (c *DBConfig) GetDSN() string {
 func (c *RedisConfig) GetAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+func (c *ReservationConfig) GetHoldDuration() time.Duration {
+	return time.Duration(c.HoldMinutes) * time.Minute
+}
+
+func (c *ReservationConfig) GetExpiryInterval() time.Duration {
+	return time.Duration(c.ExpiryIntervalSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetCommandAckTimeout() time.Duration {
+	return time.Duration(c.CommandAckTimeoutSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetCommandTTL() time.Duration {
+	return time.Duration(c.CommandTTLSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetCommandSweepInterval() time.Duration {
+	return time.Duration(c.CommandSweepIntervalSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetCommandLongPollMax() time.Duration {
+	return time.Duration(c.CommandLongPollMaxSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetLockTimeout() time.Duration {
+	return time.Duration(c.LockTimeoutSeconds) * time.Second
+}
+
+func (c *DeviceConfig) GetOnlineWindow() time.Duration {
+	return time.Duration(c.OnlineWindowSeconds) * time.Second
+}
+
+// GetLocation tarife saat dilimini döner, geçersizse UTC kullanılır
+func (c *PricingConfig) GetLocation() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (c *PaymentConfig) GetProviderTimeout() time.Duration {
+	return time.Duration(c.ProviderTimeoutSeconds) * time.Second
+}
+
+func (c *PaymentConfig) GetSettleSweepInterval() time.Duration {
+	return time.Duration(c.SettleSweepIntervalSeconds) * time.Second
+}
+
+func (c *PassConfig) GetRenewalInterval() time.Duration {
+	return time.Duration(c.RenewalIntervalSeconds) * time.Second
+}
+
+func (c *InvoiceConfig) GetWorkerInterval() time.Duration {
+	return time.Duration(c.WorkerIntervalSeconds) * time.Second
+}
+
+func (c *DisputeConfig) GetWindow() time.Duration {
+	return time.Duration(c.WindowDays) * 24 * time.Hour
+}
+
+func (c *DisputeConfig) GetMaxPhotoSize() int64 {
+	return int64(c.MaxPhotoSizeMB) << 20
+}
+
+func (c *RideConfig) GetMaxPause() time.Duration {
+	return time.Duration(c.MaxPauseMinutes) * time.Minute
+}
+
+func (c *RideConfig) GetPauseSweepInterval() time.Duration {
+	return time.Duration(c.PauseSweepIntervalSeconds) * time.Second
+}
+
+func (c *RideConfig) GetPhotoMaxSize() int64 {
+	return int64(c.PhotoMaxSizeMB) << 20
+}
+
+func (c *StorageConfig) GetURLTTL() time.Duration {
+	return time.Duration(c.URLTTLMinutes) * time.Minute
+}
+
+func (c *MotorbikeConfig) GetPhotoMaxSize() int64 {
+	return int64(c.PhotoMaxSizeMB) << 20
+}
+
+func (c *MaintenanceConfig) GetScheduleInterval() time.Duration {
+	return time.Duration(c.ScheduleIntervalSeconds) * time.Second
+}
+
+func (c *IssueConfig) GetFlagWindow() time.Duration {
+	return time.Duration(c.FlagWindowHours) * time.Hour
+}
+
+func (c *IssueConfig) GetFlagInterval() time.Duration {
+	return time.Duration(c.FlagIntervalSeconds) * time.Second
+}
+
+func (c *IssueConfig) GetPhotoMaxSize() int64 {
+	return int64(c.PhotoMaxSizeMB) << 20
+}
+
+func (c *TaskConfig) GetIdleAfter() time.Duration {
+	return time.Duration(c.IdleHours) * time.Hour
+}
+
+func (c *TaskConfig) GetGenerateInterval() time.Duration {
+	return time.Duration(c.GenerateIntervalSeconds) * time.Second
+}
+
+func (c *TaskConfig) GetRebalanceSLA() time.Duration {
+	return time.Duration(c.RebalanceSLAMinutes) * time.Minute
+}
+
+func (c *TaskConfig) GetBatterySwapSLA() time.Duration {
+	return time.Duration(c.BatterySwapSLAMinutes) * time.Minute
+}
+
+func (c *TaskConfig) GetCollectSLA() time.Duration {
+	return time.Duration(c.CollectSLAMinutes) * time.Minute
+}
+
+func (c *TaskConfig) GetInspectSLA() time.Duration {
+	return time.Duration(c.InspectSLAMinutes) * time.Minute
+}
+
+func (c *TaskConfig) GetPhotoMaxSize() int64 {
+	return int64(c.PhotoMaxSizeMB) << 20
+}
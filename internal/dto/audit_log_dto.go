@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+type AuditLogResponse struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ActorID    *int64          `json:"actor_id"`
+	ActorRole  string          `json:"actor_role,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type,omitempty"`
+	TargetID   string          `json:"target_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Method     string          `json:"method,omitempty"`
+	Path       string          `json:"path,omitempty"`
+	StatusCode int             `json:"status_code,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	PrevHash   string          `json:"prev_hash,omitempty"`
+	Hash       string          `json:"hash"`
+}
+
+func (dto AuditLogResponse) ToResponseModel(m model.AuditLog) AuditLogResponse {
+	dto.ID = m.ID
+	dto.CreatedAt = m.CreatedAt
+	dto.ActorID = m.ActorID
+	dto.ActorRole = string(m.ActorRole)
+	dto.Action = m.Action
+	dto.TargetType = m.TargetType
+	dto.TargetID = m.TargetID
+	dto.Before = m.Before
+	dto.After = m.After
+	dto.Method = m.Method
+	dto.Path = m.Path
+	dto.StatusCode = m.StatusCode
+	dto.IP = m.IP
+	dto.UserAgent = m.UserAgent
+	dto.RequestID = m.RequestID
+	dto.PrevHash = m.PrevHash
+	dto.Hash = m.Hash
+	return dto
+}
+
+type AuditLogListResponse struct {
+	AuditLogs  []AuditLogResponse     `json:"audit_logs"`
+	Pagination map[string]interface{} `json:"pagination"`
+}
+
+type AuditVerificationResponse struct {
+	Checked    int    `json:"checked"`
+	Valid      bool   `json:"valid"`
+	BrokenAtID int64  `json:"broken_at_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+func (dto AuditVerificationResponse) ToResponseModel(m model.AuditVerification) AuditVerificationResponse {
+	dto.Checked = m.Checked
+	dto.Valid = m.Valid
+	dto.BrokenAtID = m.BrokenAtID
+	dto.Reason = m.Reason
+	return dto
+}
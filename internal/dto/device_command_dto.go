@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Admin'in motora gönderdiği komut
+type EnqueueCommandRequest struct {
+	Type string `json:"type" validate:"required,oneof=lock unlock beep disable"`
+}
+
+// Cihazın komut sonucunu bildirdiği istek
+type CommandAckRequest struct {
+	Success *bool  `json:"success" validate:"required"`
+	Message string `json:"message" validate:"max=500"`
+}
+
+// Cihaza teslim edilen komut
+type DeviceCommandDelivery struct {
+	ID          int64      `json:"id"`
+	Type        string     `json:"type"`
+	Attempt     int        `json:"attempt"`
+	AckDeadline *time.Time `json:"ack_deadline"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+func (dto DeviceCommandDelivery) ToResponseModel(m model.DeviceCommand) DeviceCommandDelivery {
+	dto.ID = m.ID
+	dto.Type = string(m.Type)
+	dto.Attempt = m.Attempts
+	dto.AckDeadline = m.AckDeadline
+	dto.ExpiresAt = m.ExpiresAt
+	return dto
+}
+
+type DeviceCommandEventResponse struct {
+	Status    string    `json:"status"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DeviceCommandResponse struct {
+	ID          int64                        `json:"id"`
+	MotorbikeID int64                        `json:"motorbike_id"`
+	Type        string                       `json:"type"`
+	Status      string                       `json:"status"`
+	Attempts    int                          `json:"attempts"`
+	MaxAttempts int                          `json:"max_attempts"`
+	ExpiresAt   time.Time                    `json:"expires_at"`
+	SentAt      *time.Time                   `json:"sent_at"`
+	AckDeadline *time.Time                   `json:"ack_deadline"`
+	CompletedAt *time.Time                   `json:"completed_at"`
+	LastError   string                       `json:"last_error,omitempty"`
+	RequestedBy *int64                       `json:"requested_by"`
+	RideID      *int64                       `json:"ride_id"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	Events      []DeviceCommandEventResponse `json:"events"`
+}
+
+func (dto DeviceCommandResponse) ToResponseModel(m model.DeviceCommand) DeviceCommandResponse {
+	dto.ID = m.ID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.Type = string(m.Type)
+	dto.Status = string(m.Status)
+	dto.Attempts = m.Attempts
+	dto.MaxAttempts = m.MaxAttempts
+	dto.ExpiresAt = m.ExpiresAt
+	dto.SentAt = m.SentAt
+	dto.AckDeadline = m.AckDeadline
+	dto.CompletedAt = m.CompletedAt
+	dto.LastError = m.LastError
+	dto.RequestedBy = m.RequestedBy
+	dto.RideID = m.RideID
+	dto.CreatedAt = m.CreatedAt
+	dto.Events = make([]DeviceCommandEventResponse, len(m.Events))
+	for i, e := range m.Events {
+		dto.Events[i] = DeviceCommandEventResponse{Status: string(e.Status), Note: e.Note, CreatedAt: e.CreatedAt}
+	}
+	return dto
+}
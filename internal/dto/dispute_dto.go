@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Sürüşe itiraz isteği; multipart form olarak gönderilir, fotoğraflar "photos" alanındadır
+type OpenDisputeRequest struct {
+	Reason          string `json:"reason" form:"reason" validate:"required,oneof=overcharged bike_issue end_ride_issue unauthorized other"`
+	Description     string `json:"description" form:"description" validate:"required,max=2000"`
+	RequestedAmount int64  `json:"requested_amount" form:"requested_amount" validate:"gte=0"` // kuruş, 0 ise belirtilmedi
+}
+
+// İtirazı incelemeye alma, geri çekme ve reddetme isteği. Ret için not zorunludur.
+type DisputeNoteRequest struct {
+	Note string `json:"note" validate:"max=2000"`
+}
+
+type ApproveDisputeRequest struct {
+	Amount      int64  `json:"amount" validate:"required,gt=0"` // iade edilecek tutar (kuruş)
+	Destination string `json:"destination" validate:"required,oneof=wallet card"`
+	Note        string `json:"note" validate:"max=2000"`
+}
+
+type DisputePhotoResponse struct {
+	ID          int64     `json:"id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (dto DisputePhotoResponse) ToResponseModel(m model.DisputePhoto) DisputePhotoResponse {
+	dto.ID = m.ID
+	dto.ContentType = m.ContentType
+	dto.Size = m.Size
+	dto.URL = fmt.Sprintf("/api/v1/disputes/%d/photos/%d", m.DisputeID, m.ID)
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+type DisputeEventResponse struct {
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    int64     `json:"actor_id"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (dto DisputeEventResponse) ToResponseModel(m model.DisputeEvent) DisputeEventResponse {
+	dto.FromStatus = string(m.FromStatus)
+	dto.ToStatus = string(m.ToStatus)
+	dto.ActorID = m.ActorID
+	dto.Note = m.Note
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+type DisputeResponse struct {
+	ID                int64                  `json:"id"`
+	RideID            int64                  `json:"ride_id"`
+	UserID            int64                  `json:"user_id"`
+	Reason            string                 `json:"reason"`
+	Description       string                 `json:"description"`
+	RequestedAmount   int64                  `json:"requested_amount"`
+	Status            string                 `json:"status"`
+	ReviewerID        *int64                 `json:"reviewer_id"`
+	ResolutionNote    string                 `json:"resolution_note,omitempty"`
+	ResolvedAt        *time.Time             `json:"resolved_at"`
+	RefundAmount      int64                  `json:"refund_amount"`
+	RefundDestination string                 `json:"refund_destination,omitempty"`
+	Photos            []DisputePhotoResponse `json:"photos,omitempty"`
+	Events            []DisputeEventResponse `json:"events,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+func (dto DisputeResponse) ToResponseModel(m model.Dispute) DisputeResponse {
+	dto.ID = m.ID
+	dto.RideID = m.RideID
+	dto.UserID = m.UserID
+	dto.Reason = string(m.Reason)
+	dto.Description = m.Description
+	dto.RequestedAmount = m.RequestedAmount
+	dto.Status = string(m.Status)
+	dto.ReviewerID = m.ReviewerID
+	dto.ResolutionNote = m.ResolutionNote
+	dto.ResolvedAt = m.ResolvedAt
+	dto.RefundAmount = m.RefundAmount
+	dto.RefundDestination = string(m.RefundDestination)
+	for _, photo := range m.Photos {
+		dto.Photos = append(dto.Photos, DisputePhotoResponse{}.ToResponseModel(photo))
+	}
+	for _, event := range m.Events {
+		dto.Events = append(dto.Events, DisputeEventResponse{}.ToResponseModel(event))
+	}
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
+
+type DisputeListResponse struct {
+	Disputes   []DisputeResponse      `json:"disputes"`
+	Pagination map[string]interface{} `json:"pagination"`
+}
+
+type RideAdjustmentResponse struct {
+	ID                   int64     `json:"id"`
+	RideID               int64     `json:"ride_id"`
+	UserID               int64     `json:"user_id"`
+	DisputeID            *int64    `json:"dispute_id"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	Destination          string    `json:"destination"`
+	LedgerTransactionID  int64     `json:"ledger_transaction_id"`
+	ProviderRefundID     string    `json:"provider_refund_id,omitempty"`
+	ProviderRefundStatus string    `json:"provider_refund_status,omitempty"`
+	Reason               string    `json:"reason"`
+	CreatedBy            int64     `json:"created_by"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+func (dto RideAdjustmentResponse) ToResponseModel(m model.RideAdjustment) RideAdjustmentResponse {
+	dto.ID = m.ID
+	dto.RideID = m.RideID
+	dto.UserID = m.UserID
+	dto.DisputeID = m.DisputeID
+	dto.Amount = m.Amount
+	dto.Currency = m.Currency
+	dto.Destination = string(m.Destination)
+	dto.LedgerTransactionID = m.LedgerTransactionID
+	dto.ProviderRefundID = m.ProviderRefundID
+	dto.ProviderRefundStatus = string(m.ProviderRefundStatus)
+	dto.Reason = m.Reason
+	dto.CreatedBy = m.CreatedBy
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
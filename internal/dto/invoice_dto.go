@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+type InvoiceResponse struct {
+	ID          int64      `json:"id"`
+	Number      string     `json:"number"`
+	Kind        string     `json:"kind"`
+	RideID      *int64     `json:"ride_id,omitempty"`
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+	NetAmount   int64      `json:"net_amount"`
+	VATRatePct  int        `json:"vat_rate_pct"`
+	VATAmount   int64      `json:"vat_amount"`
+	Total       int64      `json:"total"`
+	Currency    string     `json:"currency"`
+	EmailedAt   *time.Time `json:"emailed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (dto InvoiceResponse) ToResponseModel(m model.Invoice) InvoiceResponse {
+	dto.ID = m.ID
+	dto.Number = m.Number
+	dto.Kind = string(m.Kind)
+	dto.RideID = m.RideID
+	dto.PeriodStart = m.PeriodStart
+	dto.PeriodEnd = m.PeriodEnd
+	dto.NetAmount = m.NetAmount
+	dto.VATRatePct = m.VATRatePct
+	dto.VATAmount = m.VATAmount
+	dto.Total = m.Total
+	dto.Currency = m.Currency
+	dto.EmailedAt = m.EmailedAt
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Arıza bildirimi isteği; multipart form olarak gönderilir, fotoğraflar "photos" alanındadır
+type ReportIssueRequest struct {
+	Category    string `json:"category" form:"category" validate:"required,oneof=brakes tyre battery bodywork electrical other"`
+	Severity    string `json:"severity" form:"severity" validate:"required,oneof=low medium high critical"`
+	Description string `json:"description" form:"description" validate:"required,max=2000"`
+	RideID      int64  `json:"ride_id" form:"ride_id" validate:"gte=0"` // 0 ise belirtilmedi
+}
+
+type LinkIssueRideRequest struct {
+	RideID int64 `json:"ride_id" validate:"required,gt=0"`
+}
+
+type MarkIssueDuplicateRequest struct {
+	DuplicateOfID int64  `json:"duplicate_of_id" validate:"required,gt=0"`
+	Note          string `json:"note" validate:"max=2000"`
+}
+
+// Bildirimi sonuçlandırma isteği; açıklama bildirene e-postayla gönderilir
+type ResolveIssueRequest struct {
+	Note string `json:"note" validate:"required,max=2000"`
+}
+
+type IssuePhotoResponse struct {
+	ID           int64     `json:"id"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	URLExpiresAt time.Time `json:"url_expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (dto IssuePhotoResponse) ToResponseModel(m model.IssuePhoto, url, thumbnailURL string, expiresAt time.Time) IssuePhotoResponse {
+	dto.ID = m.ID
+	dto.ContentType = m.ContentType
+	dto.Size = m.Size
+	dto.Width = m.Width
+	dto.Height = m.Height
+	dto.URL = url
+	dto.ThumbnailURL = thumbnailURL
+	dto.URLExpiresAt = expiresAt
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+// Photos yalnızca detayda doldurulur
+type IssueResponse struct {
+	ID             int64                `json:"id"`
+	MotorbikeID    int64                `json:"motorbike_id"`
+	UserID         int64                `json:"user_id"`
+	RideID         *int64               `json:"ride_id"`
+	Category       string               `json:"category"`
+	Severity       string               `json:"severity"`
+	Description    string               `json:"description"`
+	Status         string               `json:"status"`
+	DuplicateOfID  *int64               `json:"duplicate_of_id"`
+	WorkOrderID    *int64               `json:"work_order_id"`
+	ResolvedBy     *int64               `json:"resolved_by"`
+	ResolutionNote string               `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time           `json:"resolved_at"`
+	Photos         []IssuePhotoResponse `json:"photos,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+func (dto IssueResponse) ToResponseModel(m model.Issue) IssueResponse {
+	dto.ID = m.ID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.UserID = m.UserID
+	dto.RideID = m.RideID
+	dto.Category = string(m.Category)
+	dto.Severity = string(m.Severity)
+	dto.Description = m.Description
+	dto.Status = string(m.Status)
+	dto.DuplicateOfID = m.DuplicateOfID
+	dto.WorkOrderID = m.WorkOrderID
+	dto.ResolvedBy = m.ResolvedBy
+	dto.ResolutionNote = m.ResolutionNote
+	dto.ResolvedAt = m.ResolvedAt
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
+
+type IssueListResponse struct {
+	Issues     []IssueResponse        `json:"issues"`
+	Pagination map[string]interface{} `json:"pagination"`
+}
@@ -0,0 +1,195 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// İş emri açma isteği. Öncelik verilmezse normal kabul edilir.
+type OpenWorkOrderRequest struct {
+	MotorbikeID  int64  `json:"motorbike_id" validate:"required,gt=0"`
+	Type         string `json:"type" validate:"required,oneof=tyre brakes battery bodywork inspection"`
+	Priority     string `json:"priority" validate:"omitempty,oneof=low normal high urgent"`
+	Description  string `json:"description" validate:"required,max=2000"`
+	TechnicianID *int64 `json:"technician_id" validate:"omitempty,gt=0"`
+}
+
+// Kapanmamış iş emrini güncelleme isteği; teknisyen boş gönderilirse ataması kaldırılır
+type UpdateWorkOrderRequest struct {
+	Priority     string `json:"priority" validate:"required,oneof=low normal high urgent"`
+	Description  string `json:"description" validate:"required,max=2000"`
+	TechnicianID *int64 `json:"technician_id" validate:"omitempty,gt=0"`
+}
+
+// Tutarlar kuruş cinsindendir
+type WorkOrderPartRequest struct {
+	Name       string `json:"name" validate:"required,max=255"`
+	PartNumber string `json:"part_number" validate:"max=64"`
+	Quantity   int    `json:"quantity" validate:"required,gt=0"`
+	UnitCost   int64  `json:"unit_cost" validate:"min=0"`
+}
+
+type CompleteWorkOrderRequest struct {
+	Parts         []WorkOrderPartRequest `json:"parts" validate:"max=50,dive"`
+	LabourMinutes int                    `json:"labour_minutes" validate:"min=0"`
+	LabourCost    int64                  `json:"labour_cost" validate:"min=0"`
+	Note          string                 `json:"note" validate:"max=2000"`
+}
+
+func (dto CompleteWorkOrderRequest) ToPartModels() []model.WorkOrderPart {
+	parts := make([]model.WorkOrderPart, len(dto.Parts))
+	for i, part := range dto.Parts {
+		parts[i] = model.WorkOrderPart{
+			Name:       part.Name,
+			PartNumber: part.PartNumber,
+			Quantity:   part.Quantity,
+			UnitCost:   part.UnitCost,
+		}
+	}
+	return parts
+}
+
+// İş emrini iptal etme isteği, gerekçe zorunludur
+type WorkOrderNoteRequest struct {
+	Note string `json:"note" validate:"max=2000"`
+}
+
+type WorkOrderPartResponse struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	PartNumber string `json:"part_number,omitempty"`
+	Quantity   int    `json:"quantity"`
+	UnitCost   int64  `json:"unit_cost"`
+}
+
+func (dto WorkOrderPartResponse) ToResponseModel(m model.WorkOrderPart) WorkOrderPartResponse {
+	dto.ID = m.ID
+	dto.Name = m.Name
+	dto.PartNumber = m.PartNumber
+	dto.Quantity = m.Quantity
+	dto.UnitCost = m.UnitCost
+	return dto
+}
+
+type WorkOrderResponse struct {
+	ID             int64                   `json:"id"`
+	MotorbikeID    int64                   `json:"motorbike_id"`
+	Type           string                  `json:"type"`
+	Priority       string                  `json:"priority"`
+	Status         string                  `json:"status"`
+	Description    string                  `json:"description"`
+	TechnicianID   *int64                  `json:"technician_id"`
+	ScheduleID     *int64                  `json:"schedule_id"`
+	OdometerMeters *int64                  `json:"odometer_meters"`
+	RideCount      int                     `json:"ride_count"`
+	LabourMinutes  int                     `json:"labour_minutes"`
+	LabourCost     int64                   `json:"labour_cost"`
+	PartsCost      int64                   `json:"parts_cost"`
+	Cost           int64                   `json:"cost"`
+	Currency       string                  `json:"currency"`
+	ResolutionNote string                  `json:"resolution_note,omitempty"`
+	Parts          []WorkOrderPartResponse `json:"parts,omitempty"`
+	OpenedBy       *int64                  `json:"opened_by"`
+	ClosedBy       *int64                  `json:"closed_by"`
+	StartedAt      *time.Time              `json:"started_at"`
+	ClosedAt       *time.Time              `json:"closed_at"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+func (dto WorkOrderResponse) ToResponseModel(m model.WorkOrder) WorkOrderResponse {
+	dto.ID = m.ID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.Type = string(m.Type)
+	dto.Priority = string(m.Priority)
+	dto.Status = string(m.Status)
+	dto.Description = m.Description
+	dto.TechnicianID = m.TechnicianID
+	dto.ScheduleID = m.ScheduleID
+	dto.OdometerMeters = m.OdometerMeters
+	dto.RideCount = m.RideCount
+	dto.LabourMinutes = m.LabourMinutes
+	dto.LabourCost = m.LabourCost
+	dto.PartsCost = m.PartsCost
+	dto.Cost = m.Cost
+	dto.Currency = m.Currency
+	dto.ResolutionNote = m.ResolutionNote
+	for _, part := range m.Parts {
+		dto.Parts = append(dto.Parts, WorkOrderPartResponse{}.ToResponseModel(part))
+	}
+	dto.OpenedBy = m.OpenedBy
+	dto.ClosedBy = m.ClosedBy
+	dto.StartedAt = m.StartedAt
+	dto.ClosedAt = m.ClosedAt
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
+
+type WorkOrderListResponse struct {
+	WorkOrders []WorkOrderResponse    `json:"work_orders"`
+	Pagination map[string]interface{} `json:"pagination"`
+}
+
+// Koruyucu bakım planı. Kilometre veya sürüş sayısı aralığından en az biri sıfırdan büyük olmalıdır.
+type CreateMaintenanceScheduleRequest struct {
+	Name           string `json:"name" validate:"required,max=255"`
+	Type           string `json:"type" validate:"required,oneof=tyre brakes battery bodywork inspection"`
+	MotorbikeModel string `json:"motorbike_model" validate:"omitempty,max=255"`
+	IntervalMeters int64  `json:"interval_meters" validate:"min=0"`
+	IntervalRides  int    `json:"interval_rides" validate:"min=0"`
+	Priority       string `json:"priority" validate:"omitempty,oneof=low normal high urgent"`
+	IsActive       *bool  `json:"is_active"`
+}
+
+func (dto CreateMaintenanceScheduleRequest) ToDBModel(m model.MaintenanceSchedule) model.MaintenanceSchedule {
+	m.Name = dto.Name
+	m.Type = model.MaintenanceType(dto.Type)
+	m.MotorbikeModel = dto.MotorbikeModel
+	m.IntervalMeters = dto.IntervalMeters
+	m.IntervalRides = dto.IntervalRides
+	m.Priority = model.WorkOrderPriority(dto.Priority)
+	m.IsActive = dto.IsActive == nil || *dto.IsActive
+
+	return m
+}
+
+type UpdateMaintenanceScheduleRequest struct {
+	CreateMaintenanceScheduleRequest
+}
+
+func (dto UpdateMaintenanceScheduleRequest) ToDBModel(m model.MaintenanceSchedule) model.MaintenanceSchedule {
+	if dto.IsActive == nil {
+		isActive := m.IsActive
+		dto.IsActive = &isActive
+	}
+	return dto.CreateMaintenanceScheduleRequest.ToDBModel(m)
+}
+
+type MaintenanceScheduleResponse struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	MotorbikeModel string    `json:"motorbike_model,omitempty"`
+	IntervalMeters int64     `json:"interval_meters"`
+	IntervalRides  int       `json:"interval_rides"`
+	Priority       string    `json:"priority"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (dto MaintenanceScheduleResponse) ToResponseModel(m model.MaintenanceSchedule) MaintenanceScheduleResponse {
+	dto.ID = m.ID
+	dto.Name = m.Name
+	dto.Type = string(m.Type)
+	dto.MotorbikeModel = m.MotorbikeModel
+	dto.IntervalMeters = m.IntervalMeters
+	dto.IntervalRides = m.IntervalRides
+	dto.Priority = string(m.Priority)
+	dto.IsActive = m.IsActive
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
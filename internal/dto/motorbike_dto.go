@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"math"
+
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 )
 
@@ -71,40 +73,80 @@ func (dto UpdateMotorbikeRequest) ToPhotoModels(motorbikeID int) []model.Motorbi
 	return photos
 }
 
-// Fotoğraf detayları için dto
+// Fotoğraf detayları için dto. Depoya yüklenen fotoğrafların bağlantıları süreli imzalıdır, eski kayıtlarda
+// photo_url ve thumbnail_url kayıttaki harici adrestir.
 type PhotoDetailDto struct {
-	ID          int    `json:"id"`
-	MotorbikeID int64  `json:"motorbike_id"`
-	PhotoURL    string `json:"photo_url"`
+	ID           int64  `json:"id"`
+	MotorbikeID  int64  `json:"motorbike_id"`
+	PhotoURL     string `json:"photo_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Position     int    `json:"position"`
+	IsPrimary    bool   `json:"is_primary"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+}
+
+func (dto PhotoDetailDto) ToResponseModel(m model.MotorbikePhoto, url, thumbnailURL string) PhotoDetailDto {
+	dto.ID = m.ID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.PhotoURL = url
+	dto.ThumbnailURL = thumbnailURL
+	dto.Position = m.Position
+	dto.IsPrimary = m.IsPrimary
+	dto.Width = m.Width
+	dto.Height = m.Height
+	return dto
 }
 
+// Listelerde yalnızca kapak fotoğrafının küçük resmi döner
+type CoverPhotoDto struct {
+	ID           int64  `json:"id"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// Galeri sıralaması; motorun tüm fotoğrafları yeni sırasıyla gönderilir
+type ReorderPhotosRequest struct {
+	PhotoIDs []int64 `json:"photo_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// Photos yalnızca detayda, CoverPhoto yalnızca listelerde doldurulur
 type MotorbikeResponse struct {
 	ID                int64            `json:"id"`
 	Model             string           `json:"model"`
 	LocationLatitude  float64          `json:"location_latitude"`
 	LocationLongitude float64          `json:"location_longitude"`
 	Status            string           `json:"status"`
-	Photos            []PhotoDetailDto `json:"photos"`
+	Photos            []PhotoDetailDto `json:"photos,omitempty"`
+	CoverPhoto        *CoverPhotoDto   `json:"cover_photo,omitempty"`
 	LockStatus        string           `json:"lock_status"`
 }
 
 func (dto MotorbikeResponse) ToResponseModel(m model.Motorbike) MotorbikeResponse {
-	var photoDTOs []PhotoDetailDto
-	for _, photo := range m.Photos {
-		photoDTOs = append(photoDTOs, PhotoDetailDto{
-			ID:          int(photo.ID),
-			MotorbikeID: photo.MotorbikeID,
-			PhotoURL:    photo.PhotoURL,
-		})
-	}
-
 	dto.ID = m.ID
 	dto.Model = m.Model
 	dto.LocationLatitude = m.LocationLatitude
 	dto.LocationLongitude = m.LocationLongitude
 	dto.Status = string(m.Status)
 	dto.LockStatus = string(m.LockStatus)
-	dto.Photos = photoDTOs
 
 	return dto
 }
+
+// Yakındaki motorlar araması -> /motorbike/nearby?lat=41.01&lng=28.97&radius_m=500&limit=20
+type NearbyMotorbikeQuery struct {
+	Lat     *float64 `query:"lat" validate:"required,min=-90,max=90"`
+	Lng     *float64 `query:"lng" validate:"required,min=-180,max=180"`
+	RadiusM float64  `query:"radius_m" validate:"omitempty,gt=0,max=10000"`
+	Limit   int      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type NearbyMotorbikeResponse struct {
+	MotorbikeResponse
+	DistanceMeters float64 `json:"distance_m"`
+}
+
+func (dto NearbyMotorbikeResponse) ToResponseModel(m model.Motorbike, distanceMeters float64) NearbyMotorbikeResponse {
+	dto.MotorbikeResponse = MotorbikeResponse{}.ToResponseModel(m)
+	dto.DistanceMeters = math.Round(distanceMeters)
+	return dto
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+// Tutarlar kuruş. period: day, week veya month
+type CreatePassProductRequest struct {
+	Name                  string `json:"name" validate:"required,max=255"`
+	Description           string `json:"description" validate:"omitempty,max=1000"`
+	Period                string `json:"period" validate:"required,oneof=day week month"`
+	Price                 int64  `json:"price" validate:"required,min=1"`
+	Currency              string `json:"currency" validate:"omitempty,len=3"`
+	IncludedMinutesPerDay int    `json:"included_minutes_per_day" validate:"min=0,max=1440"`
+	WaiveUnlockFee        bool   `json:"waive_unlock_fee"`
+	IsActive              *bool  `json:"is_active"`
+}
+
+func (dto CreatePassProductRequest) ToDBModel(m model.PassProduct) model.PassProduct {
+	m.Name = dto.Name
+	m.Description = dto.Description
+	m.Period = model.PassPeriod(dto.Period)
+	m.Price = dto.Price
+	m.Currency = dto.Currency
+	if m.Currency == "" {
+		m.Currency = money.DefaultCurrency
+	}
+	m.IncludedMinutesPerDay = dto.IncludedMinutesPerDay
+	m.WaiveUnlockFee = dto.WaiveUnlockFee
+	m.IsActive = dto.IsActive == nil || *dto.IsActive
+
+	return m
+}
+
+type UpdatePassProductRequest struct {
+	CreatePassProductRequest
+}
+
+func (dto UpdatePassProductRequest) ToDBModel(m model.PassProduct) model.PassProduct {
+	if dto.IsActive == nil {
+		isActive := m.IsActive
+		dto.IsActive = &isActive
+	}
+	return dto.CreatePassProductRequest.ToDBModel(m)
+}
+
+type PassProductResponse struct {
+	ID                    int64     `json:"id"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+	Name                  string    `json:"name"`
+	Description           string    `json:"description,omitempty"`
+	Period                string    `json:"period"`
+	Price                 int64     `json:"price"`
+	Currency              string    `json:"currency"`
+	IncludedMinutesPerDay int       `json:"included_minutes_per_day"`
+	WaiveUnlockFee        bool      `json:"waive_unlock_fee"`
+	IsActive              bool      `json:"is_active"`
+}
+
+func (dto PassProductResponse) ToResponseModel(m model.PassProduct) PassProductResponse {
+	dto.ID = m.ID
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	dto.Name = m.Name
+	dto.Description = m.Description
+	dto.Period = string(m.Period)
+	dto.Price = m.Price
+	dto.Currency = m.Currency
+	dto.IncludedMinutesPerDay = m.IncludedMinutesPerDay
+	dto.WaiveUnlockFee = m.WaiveUnlockFee
+	dto.IsActive = m.IsActive
+	return dto
+}
+
+type PurchasePassRequest struct {
+	ProductID int64 `json:"product_id" validate:"required"`
+	AutoRenew *bool `json:"auto_renew"` // varsayılan true
+}
+
+type SubscriptionResponse struct {
+	ID                 int64                `json:"id"`
+	Status             string               `json:"status"`
+	CurrentPeriodStart time.Time            `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time            `json:"current_period_end"`
+	AutoRenew          bool                 `json:"auto_renew"`
+	CancelledAt        *time.Time           `json:"cancelled_at"`
+	RemainingMinutes   *int                 `json:"remaining_minutes_today,omitempty"`
+	Product            *PassProductResponse `json:"product,omitempty"`
+}
+
+func (dto SubscriptionResponse) ToResponseModel(m model.Subscription) SubscriptionResponse {
+	dto.ID = m.ID
+	dto.Status = string(m.Status)
+	dto.CurrentPeriodStart = m.CurrentPeriodStart
+	dto.CurrentPeriodEnd = m.CurrentPeriodEnd
+	dto.AutoRenew = m.AutoRenew
+	dto.CancelledAt = m.CancelledAt
+	if m.Product != nil {
+		product := PassProductResponse{}.ToResponseModel(*m.Product)
+		dto.Product = &product
+	}
+	return dto
+}
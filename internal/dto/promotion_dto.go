@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+// Tutarlar kuruş, yüzde indirimlerinde discount_value 1-100 arasıdır. Limitlerde 0 sınırsız anlamına gelir.
+type CreatePromotionRequest struct {
+	Code            string     `json:"code" validate:"required,max=32"`
+	Description     string     `json:"description" validate:"omitempty,max=500"`
+	DiscountType    string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	DiscountValue   int64      `json:"discount_value" validate:"required,min=1"`
+	MaxDiscount     int64      `json:"max_discount" validate:"min=0"`
+	Currency        string     `json:"currency" validate:"omitempty,len=3"`
+	FirstRideOnly   bool       `json:"first_ride_only"`
+	PerUserLimit    int        `json:"per_user_limit" validate:"min=0"`
+	TotalLimit      int        `json:"total_limit" validate:"min=0"`
+	MotorbikeModels []string   `json:"motorbike_models" validate:"omitempty,dive,required,max=255"`
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+	IsActive        *bool      `json:"is_active"`
+}
+
+func (dto CreatePromotionRequest) ToDBModel(m model.Promotion) model.Promotion {
+	m.Code = dto.Code
+	m.Description = dto.Description
+	m.DiscountType = model.DiscountType(dto.DiscountType)
+	m.DiscountValue = dto.DiscountValue
+	m.MaxDiscount = dto.MaxDiscount
+	m.Currency = dto.Currency
+	if m.Currency == "" {
+		m.Currency = money.DefaultCurrency
+	}
+	m.FirstRideOnly = dto.FirstRideOnly
+	m.PerUserLimit = dto.PerUserLimit
+	m.TotalLimit = dto.TotalLimit
+	m.MotorbikeModels = dto.MotorbikeModels
+	m.ValidFrom = dto.ValidFrom
+	m.ValidUntil = dto.ValidUntil
+	m.IsActive = dto.IsActive == nil || *dto.IsActive
+
+	return m
+}
+
+type UpdatePromotionRequest struct {
+	CreatePromotionRequest
+}
+
+func (dto UpdatePromotionRequest) ToDBModel(m model.Promotion) model.Promotion {
+	if dto.IsActive == nil {
+		isActive := m.IsActive
+		dto.IsActive = &isActive
+	}
+	return dto.CreatePromotionRequest.ToDBModel(m)
+}
+
+type PromotionResponse struct {
+	ID              int64      `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Code            string     `json:"code"`
+	Description     string     `json:"description,omitempty"`
+	DiscountType    string     `json:"discount_type"`
+	DiscountValue   int64      `json:"discount_value"`
+	MaxDiscount     int64      `json:"max_discount"`
+	Currency        string     `json:"currency"`
+	FirstRideOnly   bool       `json:"first_ride_only"`
+	PerUserLimit    int        `json:"per_user_limit"`
+	TotalLimit      int        `json:"total_limit"`
+	RedemptionCount int        `json:"redemption_count"`
+	MotorbikeModels []string   `json:"motorbike_models,omitempty"`
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+	IsActive        bool       `json:"is_active"`
+}
+
+func (dto PromotionResponse) ToResponseModel(m model.Promotion) PromotionResponse {
+	dto.ID = m.ID
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	dto.Code = m.Code
+	dto.Description = m.Description
+	dto.DiscountType = string(m.DiscountType)
+	dto.DiscountValue = m.DiscountValue
+	dto.MaxDiscount = m.MaxDiscount
+	dto.Currency = m.Currency
+	dto.FirstRideOnly = m.FirstRideOnly
+	dto.PerUserLimit = m.PerUserLimit
+	dto.TotalLimit = m.TotalLimit
+	dto.RedemptionCount = m.RedemptionCount
+	dto.MotorbikeModels = m.MotorbikeModels
+	dto.ValidFrom = m.ValidFrom
+	dto.ValidUntil = m.ValidUntil
+	dto.IsActive = m.IsActive
+	return dto
+}
+
+type ApplyPromotionRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type PromotionRedemptionResponse struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	RideID    int64     `json:"ride_id"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (dto PromotionRedemptionResponse) ToResponseModel(m model.PromotionRedemption) PromotionRedemptionResponse {
+	dto.ID = m.ID
+	dto.UserID = m.UserID
+	dto.RideID = m.RideID
+	dto.Amount = m.Amount
+	dto.Currency = m.Currency
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+type PromotionUsageResponse struct {
+	Usage       model.PromotionUsage          `json:"usage"`
+	Redemptions []PromotionRedemptionResponse `json:"redemptions"`
+	Pagination  map[string]interface{}        `json:"pagination"`
+}
+
+type PromotionUsageReportResponse struct {
+	Promotions []model.PromotionUsage `json:"promotions"`
+	Referrals  model.ReferralStats    `json:"referrals"`
+}
+
+type ReferralResponse struct {
+	Code           string              `json:"code"`
+	ReferrerCredit int64               `json:"referrer_credit"` // davet ettiğiniz kullanıcı ilk sürüşünü tamamlayınca size yüklenir
+	RefereeCredit  int64               `json:"referee_credit"`  // davet ettiğiniz kullanıcıya yüklenir
+	Stats          model.ReferralStats `json:"stats"`
+}
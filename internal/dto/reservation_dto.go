@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+type ReservationResponse struct {
+	ID               int64     `json:"id"`
+	UserID           int64     `json:"user_id"`
+	MotorbikeID      int64     `json:"motorbike_id"`
+	Status           string    `json:"status"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+	RideID           *int64    `json:"ride_id"`
+	Fee              int64     `json:"fee"`
+	Currency         string    `json:"currency"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (dto ReservationResponse) ToResponseModel(m model.Reservation) ReservationResponse {
+	dto.ID = m.ID
+	dto.UserID = m.UserID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.Status = m.Status.String()
+	dto.ExpiresAt = m.ExpiresAt
+	dto.RideID = m.RideID
+	dto.Fee = m.Fee
+	dto.Currency = m.Currency
+	dto.CreatedAt = m.CreatedAt
+	if m.Status == model.ReservationActive {
+		if remaining := time.Until(m.ExpiresAt); remaining > 0 {
+			dto.RemainingSeconds = int64(remaining.Seconds())
+		}
+	}
+	return dto
+}
@@ -5,63 +5,120 @@ import (
 	"time"
 )
 
-type CreateRideRequest struct {
-	UserID      int64      `json:"user_id" validate:"required"`
-	MotorbikeID int64      `json:"motorbike_id" validate:"required"`
-	StartTime   time.Time  `json:"start_time" validate:"required"`
-	EndTime     *time.Time `json:"end_time" validate:"required"`
-	Duration    string     `json:"duration" validate:"required"`
-	Cost        float64    `json:"cost" validate:"required"`
-}
-
-func (dto CreateRideRequest) ToDBModel(m model.Ride) model.Ride {
-	m.UserID = dto.UserID
-	m.MotorbikeID = dto.MotorbikeID
-	m.StartTime = dto.StartTime
-	m.EndTime = dto.EndTime
-	m.Duration = dto.Duration
-	m.Cost = dto.Cost
-	return m
-}
-
-type UpdateRideRequest struct {
-	UserID      int64      `json:"user_id"`
-	MotorbikeID int64      `json:"motorbike_id"`
-	StartTime   time.Time  `json:"start_time"`
-	EndTime     *time.Time `json:"end_time"`
-	Duration    string     `json:"duration"`
-	Cost        float64    `json:"cost"`
-}
-
-func (dto UpdateRideRequest) ToDBModel(m model.Ride) model.Ride {
-	m.UserID = dto.UserID
-	m.MotorbikeID = dto.MotorbikeID
-	m.StartTime = dto.StartTime
-	m.EndTime = dto.EndTime
-	m.Duration = dto.Duration
-	m.Cost = dto.Cost
-	return m
+// Sürüş başlatma isteği, kullanıcı token'dan alınır
+type StartRideRequest struct {
+	MotorbikeID int64 `json:"motorbike_id" validate:"required"`
 }
 
 type RideResponse struct {
-	ID          int64           `json:"id"`
-	UserID      int64           `json:"user_id"`
-	MotorbikeID int64           `json:"motorbike_id"`
-	StartTime   time.Time       `json:"start_time"`
-	EndTime     *time.Time      `json:"end_time"`
-	Duration    string          `json:"duration"`
-	Cost        float64         `json:"cost"`
-	Motorbike   model.Motorbike `json:"motorbike"`
+	ID          int64            `json:"id"`
+	UserID      int64            `json:"user_id"`
+	MotorbikeID int64            `json:"motorbike_id"`
+	Status      model.RideStatus `json:"status"`
+	StartTime   time.Time        `json:"start_time"`
+	EndTime     *time.Time       `json:"end_time"`
+	Duration    int64            `json:"duration"` // saniye
+	Cost        int64            `json:"cost"`
+	Currency    string           `json:"currency"`
+	Motorbike   model.Motorbike  `json:"motorbike"`
+
+	DistanceMeters int64   `json:"distance_meters"`
+	MaxSpeedKmh    float64 `json:"max_speed_kmh"`
+	AvgSpeedKmh    float64 `json:"avg_speed_kmh"`
+	IdleSeconds    int64   `json:"idle_seconds"`
 }
 
 func (dto RideResponse) ToResponseModel(m model.Ride) RideResponse {
 	dto.ID = m.ID
 	dto.UserID = m.UserID
 	dto.MotorbikeID = m.MotorbikeID
+	dto.Status = m.Status
 	dto.StartTime = m.StartTime
 	dto.EndTime = m.EndTime
 	dto.Duration = m.Duration
 	dto.Cost = m.Cost
+	dto.Currency = m.Currency
 	dto.Motorbike = m.Motorbike
+	dto.DistanceMeters = m.DistanceMeters
+	dto.MaxSpeedKmh = m.MaxSpeedKmh
+	dto.AvgSpeedKmh = m.AvgSpeedKmh
+	dto.IdleSeconds = m.IdleSeconds
+	return dto
+}
+
+type RideEventResponse struct {
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    *int64    `json:"actor_id"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (dto RideEventResponse) ToResponseModel(m model.RideEvent) RideEventResponse {
+	dto.FromStatus = string(m.FromStatus)
+	dto.ToStatus = string(m.ToStatus)
+	dto.ActorID = m.ActorID
+	dto.Note = m.Note
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+// Park fotoğrafı; bağlantılar süreli olarak imzalanır ve url_expires_at sonrasında geçersiz olur
+type RidePhotoResponse struct {
+	ID           int64     `json:"id"`
+	RideID       int64     `json:"ride_id"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	URLExpiresAt time.Time `json:"url_expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (dto RidePhotoResponse) ToResponseModel(m model.RidePhoto, url, thumbnailURL string, expiresAt time.Time) RidePhotoResponse {
+	dto.ID = m.ID
+	dto.RideID = m.RideID
+	dto.ContentType = m.ContentType
+	dto.Size = m.Size
+	dto.Width = m.Width
+	dto.Height = m.Height
+	dto.URL = url
+	dto.ThumbnailURL = thumbnailURL
+	dto.URLExpiresAt = expiresAt
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+// Fiyat teklifi isteği; motor modeli boşsa varsayılan tarife kullanılır
+type EstimateFareRequest struct {
+	MotorbikeModel  string     `json:"motorbike_model"`
+	DurationMinutes int        `json:"duration_minutes" validate:"required,gt=0,lte=10080"`
+	StartTime       *time.Time `json:"start_time"`
+}
+
+type LocationResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type ActiveRideResponse struct {
+	Ride           RideResponse           `json:"ride"`
+	ElapsedSeconds int64                  `json:"elapsed_seconds"`
+	CurrentFare    PriceBreakdownResponse `json:"current_fare"`
+	Location       LocationResponse       `json:"location"`
+	Parking        ParkingResponse        `json:"parking"`
+}
+
+func (dto ActiveRideResponse) ToResponseModel(ride model.Ride, motorbike model.Motorbike, elapsed time.Duration, fare model.RidePriceBreakdown) ActiveRideResponse {
+	ride.Motorbike = motorbike
+	dto.Ride = RideResponse{}.ToResponseModel(ride)
+	dto.ElapsedSeconds = int64(elapsed.Seconds())
+	dto.CurrentFare = PriceBreakdownResponse{}.ToResponseModel(fare)
+	dto.Location = LocationResponse{
+		Latitude:  motorbike.LocationLatitude,
+		Longitude: motorbike.LocationLongitude,
+	}
 	return dto
 }
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+// Uygulamanın devam eden sürüş için toplu gönderdiği GPS noktaları
+type RecordRouteRequest struct {
+	Points []RoutePointRequest `json:"points" validate:"required,min=1,max=500,dive"`
+}
+
+type RoutePointRequest struct {
+	RecordedAt time.Time `json:"recorded_at" validate:"required"`
+	Latitude   *float64  `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude  *float64  `json:"longitude" validate:"required,min=-180,max=180"`
+	SpeedKmh   *float64  `json:"speed_kmh" validate:"omitempty,min=0,max=300"`
+}
+
+func (dto RecordRouteRequest) ToDBModels() []model.RidePoint {
+	points := make([]model.RidePoint, len(dto.Points))
+	for i, p := range dto.Points {
+		points[i] = model.RidePoint{
+			RecordedAt: p.RecordedAt,
+			Latitude:   *p.Latitude,
+			Longitude:  *p.Longitude,
+			SpeedKmh:   p.SpeedKmh,
+		}
+	}
+	return points
+}
+
+// RideRouteTrack sürüş noktalarını GeoJSON/GPX çıktısı için ize çevirir
+func RideRouteTrack(points []model.RidePoint) []geo.TrackPoint {
+	track := make([]geo.TrackPoint, len(points))
+	for i, p := range points {
+		track[i] = geo.TrackPoint{Point: geo.Point{Lat: p.Latitude, Lng: p.Longitude}, Time: p.RecordedAt}
+	}
+	return track
+}
+
+// RideRouteProperties GeoJSON Feature'ın properties alanında dönen sürüş ve rota özeti
+func RideRouteProperties(ride model.Ride) map[string]any {
+	return map[string]any{
+		"ride_id":         ride.ID,
+		"start_time":      ride.StartTime,
+		"end_time":        ride.EndTime,
+		"distance_meters": ride.DistanceMeters,
+		"max_speed_kmh":   ride.MaxSpeedKmh,
+		"avg_speed_kmh":   ride.AvgSpeedKmh,
+		"idle_seconds":    ride.IdleSeconds,
+	}
+}
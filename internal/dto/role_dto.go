@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=100"`
+}
+
+func (dto CreateRoleRequest) ToDBModel(m model.RoleDefinition) model.RoleDefinition {
+	m.Name = model.Role(dto.Name)
+	m.Description = dto.Description
+	m.Permissions = toPermissions(dto.Permissions)
+	return m
+}
+
+// Yetkiler verilen liste ile değiştirilir; boş liste rolün tüm yetkilerini kaldırır
+type UpdateRoleRequest struct {
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=100"`
+}
+
+func (dto UpdateRoleRequest) ToDBModel(m model.RoleDefinition) model.RoleDefinition {
+	m.Description = dto.Description
+	m.Permissions = toPermissions(dto.Permissions)
+	return m
+}
+
+func toPermissions(names []string) []model.Permission {
+	permissions := make([]model.Permission, len(names))
+	for i, name := range names {
+		permissions[i] = model.Permission(name)
+	}
+	return permissions
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (dto PermissionResponse) ToResponseModel(m model.PermissionDefinition) PermissionResponse {
+	dto.Name = string(m.Name)
+	dto.Description = m.Description
+	return dto
+}
+
+type RoleResponse struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	System      bool      `json:"system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (dto RoleResponse) ToResponseModel(m model.RoleDefinition) RoleResponse {
+	dto.Name = string(m.Name)
+	dto.Description = m.Description
+	dto.System = m.Name.IsSystem()
+	dto.Permissions = make([]string, len(m.Permissions))
+	for i, permission := range m.Permissions {
+		dto.Permissions[i] = string(permission)
+	}
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"time"
+)
+
+// Tutarlar para biriminin en küçük biriminde (kuruş) gönderilir
+type CreateTariffRequest struct {
+	Name                 string `json:"name" validate:"required,max=255"`
+	MotorbikeModel       string `json:"motorbike_model" validate:"omitempty,max=255"`
+	Currency             string `json:"currency" validate:"omitempty,len=3"`
+	UnlockFee            int64  `json:"unlock_fee" validate:"min=0"`
+	PerMinuteRate        int64  `json:"per_minute_rate" validate:"min=0"`
+	PausePerMinuteRate   int64  `json:"pause_per_minute_rate" validate:"min=0"`
+	MinimumCharge        int64  `json:"minimum_charge" validate:"min=0"`
+	DailyCap             int64  `json:"daily_cap" validate:"min=0"`
+	FreeMinutes          int    `json:"free_minutes" validate:"min=0"`
+	NightMultiplierPct   int    `json:"night_multiplier_pct" validate:"min=0,max=1000"`
+	NightStartHour       int    `json:"night_start_hour" validate:"min=0,max=23"`
+	NightEndHour         int    `json:"night_end_hour" validate:"min=0,max=23"`
+	WeekendMultiplierPct int    `json:"weekend_multiplier_pct" validate:"min=0,max=1000"`
+	IsActive             *bool  `json:"is_active"`
+}
+
+func (dto CreateTariffRequest) ToDBModel(m model.Tariff) model.Tariff {
+	m.Name = dto.Name
+	m.MotorbikeModel = dto.MotorbikeModel
+	m.Currency = dto.Currency
+	if m.Currency == "" {
+		m.Currency = money.DefaultCurrency
+	}
+	m.UnlockFee = dto.UnlockFee
+	m.PerMinuteRate = dto.PerMinuteRate
+	m.PausePerMinuteRate = dto.PausePerMinuteRate
+	m.MinimumCharge = dto.MinimumCharge
+	m.DailyCap = dto.DailyCap
+	m.FreeMinutes = dto.FreeMinutes
+	m.NightMultiplierPct = defaultPercent(dto.NightMultiplierPct)
+	m.NightStartHour = dto.NightStartHour
+	m.NightEndHour = dto.NightEndHour
+	m.WeekendMultiplierPct = defaultPercent(dto.WeekendMultiplierPct)
+	m.IsActive = dto.IsActive == nil || *dto.IsActive
+
+	return m
+}
+
+type UpdateTariffRequest struct {
+	CreateTariffRequest
+}
+
+func (dto UpdateTariffRequest) ToDBModel(m model.Tariff) model.Tariff {
+	if dto.IsActive == nil {
+		isActive := m.IsActive
+		dto.IsActive = &isActive
+	}
+	return dto.CreateTariffRequest.ToDBModel(m)
+}
+
+type TariffResponse struct {
+	ID                   int64     `json:"id"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	Name                 string    `json:"name"`
+	MotorbikeModel       string    `json:"motorbike_model,omitempty"`
+	Currency             string    `json:"currency"`
+	UnlockFee            int64     `json:"unlock_fee"`
+	PerMinuteRate        int64     `json:"per_minute_rate"`
+	PausePerMinuteRate   int64     `json:"pause_per_minute_rate"`
+	MinimumCharge        int64     `json:"minimum_charge"`
+	DailyCap             int64     `json:"daily_cap"`
+	FreeMinutes          int       `json:"free_minutes"`
+	NightMultiplierPct   int       `json:"night_multiplier_pct"`
+	NightStartHour       int       `json:"night_start_hour"`
+	NightEndHour         int       `json:"night_end_hour"`
+	WeekendMultiplierPct int       `json:"weekend_multiplier_pct"`
+	IsActive             bool      `json:"is_active"`
+}
+
+func (dto TariffResponse) ToResponseModel(m model.Tariff) TariffResponse {
+	dto.ID = m.ID
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	dto.Name = m.Name
+	dto.MotorbikeModel = m.MotorbikeModel
+	dto.Currency = m.Currency
+	dto.UnlockFee = m.UnlockFee
+	dto.PerMinuteRate = m.PerMinuteRate
+	dto.PausePerMinuteRate = m.PausePerMinuteRate
+	dto.MinimumCharge = m.MinimumCharge
+	dto.DailyCap = m.DailyCap
+	dto.FreeMinutes = m.FreeMinutes
+	dto.NightMultiplierPct = m.NightMultiplierPct
+	dto.NightStartHour = m.NightStartHour
+	dto.NightEndHour = m.NightEndHour
+	dto.WeekendMultiplierPct = m.WeekendMultiplierPct
+	dto.IsActive = m.IsActive
+	return dto
+}
+
+type PriceBreakdownResponse struct {
+	RideID          int64             `json:"ride_id"`
+	TariffID        int64             `json:"tariff_id"`
+	Currency        string            `json:"currency"`
+	TotalMinutes    int               `json:"total_minutes"`
+	BillableMinutes int               `json:"billable_minutes"`
+	Lines           []model.PriceLine `json:"lines"`
+	Total           int64             `json:"total"`
+	TotalFormatted  string            `json:"total_formatted"`
+}
+
+func (dto PriceBreakdownResponse) ToResponseModel(m model.RidePriceBreakdown) PriceBreakdownResponse {
+	dto.RideID = m.RideID
+	dto.TariffID = m.TariffID
+	dto.Currency = m.Currency
+	dto.TotalMinutes = m.TotalMinutes
+	dto.BillableMinutes = m.BillableMinutes
+	dto.Lines = m.Lines
+	dto.Total = m.Total
+	dto.TotalFormatted = money.Format(m.Total, m.Currency)
+	return dto
+}
+
+// Çarpan gönderilmezse (0) tarife farkı uygulanmaz
+func defaultPercent(pct int) int {
+	if pct == 0 {
+		return 100
+	}
+	return pct
+}
@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"math"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Görev açma isteği. Son tarih verilmezse görev türünün SLA süresi kullanılır.
+type CreateTaskRequest struct {
+	Type         string     `json:"type" validate:"required,oneof=rebalance battery_swap collect inspect"`
+	MotorbikeID  int64      `json:"motorbike_id" validate:"required,gt=0"`
+	TargetZoneID *int64     `json:"target_zone_id" validate:"omitempty,gt=0"`
+	Note         string     `json:"note" validate:"max=2000"`
+	AssigneeID   *int64     `json:"assignee_id" validate:"omitempty,gt=0"`
+	DueAt        *time.Time `json:"due_at"`
+}
+
+// Kapanmamış görevi güncelleme isteği; hedef alan boş gönderilirse kaldırılır, son tarih boşsa değişmez
+type UpdateTaskRequest struct {
+	TargetZoneID *int64     `json:"target_zone_id" validate:"omitempty,gt=0"`
+	Note         string     `json:"note" validate:"max=2000"`
+	DueAt        *time.Time `json:"due_at"`
+}
+
+type AssignTaskRequest struct {
+	OperatorID int64 `json:"operator_id" validate:"required,gt=0"`
+}
+
+type TaskNoteRequest struct {
+	Note string `json:"note" validate:"max=2000"`
+}
+
+// Görev tamamlama isteği; multipart form olarak gönderilir, fotoğraflar "photos" alanındadır
+type CompleteTaskRequest struct {
+	Note string `json:"note" form:"note" validate:"max=2000"`
+}
+
+// Yakındaki görevler araması -> /ops/tasks/nearby?lat=41.01&lng=28.97&radius_m=2000&limit=20
+type NearbyTaskQuery struct {
+	Lat     *float64 `query:"lat" validate:"required,min=-90,max=90"`
+	Lng     *float64 `query:"lng" validate:"required,min=-180,max=180"`
+	RadiusM float64  `query:"radius_m" validate:"omitempty,gt=0,max=50000"`
+	Limit   int      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type TaskPhotoResponse struct {
+	ID           int64     `json:"id"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	URLExpiresAt time.Time `json:"url_expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (dto TaskPhotoResponse) ToResponseModel(m model.TaskPhoto, url, thumbnailURL string, expiresAt time.Time) TaskPhotoResponse {
+	dto.ID = m.ID
+	dto.ContentType = m.ContentType
+	dto.Size = m.Size
+	dto.Width = m.Width
+	dto.Height = m.Height
+	dto.URL = url
+	dto.ThumbnailURL = thumbnailURL
+	dto.URLExpiresAt = expiresAt
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+// Photos yalnızca detayda doldurulur
+type TaskResponse struct {
+	ID             int64               `json:"id"`
+	Type           string              `json:"type"`
+	Source         string              `json:"source"`
+	Status         string              `json:"status"`
+	MotorbikeID    int64               `json:"motorbike_id"`
+	Latitude       float64             `json:"latitude"`
+	Longitude      float64             `json:"longitude"`
+	TargetZoneID   *int64              `json:"target_zone_id"`
+	Note           string              `json:"note,omitempty"`
+	AssigneeID     *int64              `json:"assignee_id"`
+	DueAt          time.Time           `json:"due_at"`
+	Overdue        bool                `json:"overdue"`
+	ClaimedAt      *time.Time          `json:"claimed_at"`
+	ResolutionNote string              `json:"resolution_note,omitempty"`
+	CreatedBy      *int64              `json:"created_by"`
+	ClosedBy       *int64              `json:"closed_by"`
+	ClosedAt       *time.Time          `json:"closed_at"`
+	Photos         []TaskPhotoResponse `json:"photos,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func (dto TaskResponse) ToResponseModel(m model.Task) TaskResponse {
+	dto.ID = m.ID
+	dto.Type = string(m.Type)
+	dto.Source = string(m.Source)
+	dto.Status = string(m.Status)
+	dto.MotorbikeID = m.MotorbikeID
+	dto.Latitude = m.Latitude
+	dto.Longitude = m.Longitude
+	dto.TargetZoneID = m.TargetZoneID
+	dto.Note = m.Note
+	dto.AssigneeID = m.AssigneeID
+	dto.DueAt = m.DueAt
+	dto.Overdue = m.IsOverdue(time.Now())
+	dto.ClaimedAt = m.ClaimedAt
+	dto.ResolutionNote = m.ResolutionNote
+	dto.CreatedBy = m.CreatedBy
+	dto.ClosedBy = m.ClosedBy
+	dto.ClosedAt = m.ClosedAt
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
+
+type NearbyTaskResponse struct {
+	TaskResponse
+	DistanceMeters float64 `json:"distance_m"`
+}
+
+func (dto NearbyTaskResponse) ToResponseModel(m model.Task, distanceMeters float64) NearbyTaskResponse {
+	dto.TaskResponse = TaskResponse{}.ToResponseModel(m)
+	dto.DistanceMeters = math.Round(distanceMeters)
+	return dto
+}
+
+type TaskListResponse struct {
+	Tasks      []TaskResponse         `json:"tasks"`
+	Pagination map[string]interface{} `json:"pagination"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Cihazın tek istekte gönderdiği ölçüm paketi
+type TelemetryBatchRequest struct {
+	Samples []TelemetrySampleRequest `json:"samples" validate:"required,min=1,max=500,dive"`
+}
+
+type TelemetrySampleRequest struct {
+	RecordedAt     time.Time `json:"recorded_at" validate:"required"`
+	Latitude       *float64  `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude      *float64  `json:"longitude" validate:"required,min=-180,max=180"`
+	SpeedKmh       float64   `json:"speed_kmh" validate:"min=0,max=300"`
+	BatteryLevel   *int      `json:"battery_level" validate:"omitempty,min=0,max=100"`
+	FuelLevel      *int      `json:"fuel_level" validate:"omitempty,min=0,max=100"`
+	OdometerMeters *int64    `json:"odometer_meters" validate:"omitempty,min=0"`
+	LockStatus     string    `json:"lock_status" validate:"omitempty,oneof=locked unlocked"`
+}
+
+func (dto TelemetryBatchRequest) ToDBModels() []model.MotorbikeTelemetry {
+	samples := make([]model.MotorbikeTelemetry, len(dto.Samples))
+	for i, s := range dto.Samples {
+		samples[i] = model.MotorbikeTelemetry{
+			RecordedAt:     s.RecordedAt,
+			Latitude:       *s.Latitude,
+			Longitude:      *s.Longitude,
+			SpeedKmh:       s.SpeedKmh,
+			BatteryLevel:   s.BatteryLevel,
+			FuelLevel:      s.FuelLevel,
+			OdometerMeters: s.OdometerMeters,
+			LockStatus:     model.LockStatus(s.LockStatus),
+		}
+	}
+	return samples
+}
+
+type TelemetryResponse struct {
+	ID             int64     `json:"id"`
+	MotorbikeID    int64     `json:"motorbike_id"`
+	RecordedAt     time.Time `json:"recorded_at"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	SpeedKmh       float64   `json:"speed_kmh"`
+	BatteryLevel   *int      `json:"battery_level"`
+	FuelLevel      *int      `json:"fuel_level"`
+	OdometerMeters *int64    `json:"odometer_meters"`
+	LockStatus     string    `json:"lock_status,omitempty"`
+}
+
+func (dto TelemetryResponse) ToResponseModel(m model.MotorbikeTelemetry) TelemetryResponse {
+	dto.ID = m.ID
+	dto.MotorbikeID = m.MotorbikeID
+	dto.RecordedAt = m.RecordedAt
+	dto.Latitude = m.Latitude
+	dto.Longitude = m.Longitude
+	dto.SpeedKmh = m.SpeedKmh
+	dto.BatteryLevel = m.BatteryLevel
+	dto.FuelLevel = m.FuelLevel
+	dto.OdometerMeters = m.OdometerMeters
+	dto.LockStatus = string(m.LockStatus)
+	return dto
+}
+
+type DeviceKeyResponse struct {
+	MotorbikeID int64  `json:"motorbike_id"`
+	DeviceKey   string `json:"device_key"`
+}
@@ -13,6 +13,8 @@ type CreateUserRequest struct {
 	Password  string       `json:"password" validate:"required,min=3,max=100"`
 	Status    model.Status `json:"status" validate:"omitempty,oneof=active inactive"`
 	Role      model.Role   `json:"role"`
+	// ReferralCode kayıt sırasında girilen davet kodu
+	ReferralCode string `json:"referral_code" validate:"omitempty,max=16"`
 }
 
 func (dto CreateUserRequest) ToDBModel(m model.User) model.User {
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+type WalletResponse struct {
+	UserID   int64  `json:"user_id"`
+	Balance  int64  `json:"balance"` // kuruş, borçlu cüzdanda eksi olabilir
+	Currency string `json:"currency"`
+}
+
+func (dto WalletResponse) ToResponseModel(m model.Wallet) WalletResponse {
+	dto.UserID = m.UserID
+	dto.Balance = m.Balance
+	dto.Currency = m.Currency
+	return dto
+}
+
+type WalletTransactionResponse struct {
+	TransactionID int64     `json:"transaction_id"`
+	Kind          string    `json:"kind"`
+	Description   string    `json:"description,omitempty"`
+	RideID        *int64    `json:"ride_id"`
+	Amount        int64     `json:"amount"` // cüzdana giren tutar pozitif, çıkan tutar negatif
+	BalanceAfter  int64     `json:"balance_after"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (dto WalletTransactionResponse) ToResponseModel(m model.WalletTransaction) WalletTransactionResponse {
+	dto.TransactionID = m.TransactionID
+	dto.Kind = string(m.Kind)
+	dto.Description = m.Description
+	dto.RideID = m.RideID
+	dto.Amount = m.Amount
+	dto.BalanceAfter = m.BalanceAfter
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
+
+type WalletTransactionListResponse struct {
+	Transactions []WalletTransactionResponse `json:"transactions"`
+	Pagination   map[string]interface{}      `json:"pagination"`
+}
+
+// Admin'in cüzdana yazdığı hareket. Düzeltmede tutar işaretlidir, diğer türlerde pozitif olmalıdır.
+type WalletAdjustmentRequest struct {
+	Kind        string `json:"kind" validate:"required,oneof=top_up refund promo_credit adjustment"`
+	Amount      int64  `json:"amount" validate:"required"`
+	Description string `json:"description" validate:"required,max=500"`
+	RideID      *int64 `json:"ride_id"`
+}
+
+type LedgerTransactionResponse struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description,omitempty"`
+	RideID      *int64    `json:"ride_id"`
+	CreatedBy   *int64    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (dto LedgerTransactionResponse) ToResponseModel(m model.LedgerTransaction) LedgerTransactionResponse {
+	dto.ID = m.ID
+	dto.Kind = string(m.Kind)
+	dto.Currency = m.Currency
+	dto.Description = m.Description
+	dto.RideID = m.RideID
+	dto.CreatedBy = m.CreatedBy
+	dto.CreatedAt = m.CreatedAt
+	return dto
+}
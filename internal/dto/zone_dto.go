@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Geometry GeoJSON Polygon, MultiPolygon veya Feature olarak gönderilir. Koordinatlar [boylam, enlem] sırasındadır.
+type CreateZoneRequest struct {
+	Name        string          `json:"name" validate:"required,max=255"`
+	Type        string          `json:"type" validate:"required,oneof=operating no_parking slow preferred_parking"`
+	Geometry    json.RawMessage `json:"geometry" validate:"required"`
+	Enforcement string          `json:"enforcement" validate:"omitempty,oneof=reject surcharge"`
+	Surcharge   int64           `json:"surcharge" validate:"min=0"`
+	MaxSpeedKmh int             `json:"max_speed_kmh" validate:"min=0,max=100"`
+	IsActive    *bool           `json:"is_active"`
+}
+
+func (dto CreateZoneRequest) ToDBModel(m model.Zone) model.Zone {
+	m.Name = dto.Name
+	m.Type = model.ZoneType(dto.Type)
+	m.Geometry = dto.Geometry
+	m.Enforcement = model.ZoneEnforcement(dto.Enforcement)
+	if m.Enforcement == "" {
+		m.Enforcement = model.ZoneReject
+	}
+	m.Surcharge = dto.Surcharge
+	m.MaxSpeedKmh = dto.MaxSpeedKmh
+	m.IsActive = dto.IsActive == nil || *dto.IsActive
+	return m
+}
+
+type UpdateZoneRequest struct {
+	CreateZoneRequest
+}
+
+type ZoneResponse struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Geometry    json.RawMessage `json:"geometry"`
+	Enforcement string          `json:"enforcement"`
+	Surcharge   int64           `json:"surcharge"`
+	MaxSpeedKmh int             `json:"max_speed_kmh"`
+	IsActive    bool            `json:"is_active"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (dto ZoneResponse) ToResponseModel(m model.Zone) ZoneResponse {
+	dto.ID = m.ID
+	dto.Name = m.Name
+	dto.Type = m.Type.String()
+	dto.Geometry = m.Geometry
+	dto.Enforcement = string(m.Enforcement)
+	dto.Surcharge = m.Surcharge
+	dto.MaxSpeedKmh = m.MaxSpeedKmh
+	dto.IsActive = m.IsActive
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+	return dto
+}
+
+type ParkingResponse struct {
+	Allowed         bool   `json:"allowed"`
+	Reason          string `json:"reason,omitempty"`
+	PreferredZoneID *int64 `json:"preferred_zone_id,omitempty"`
+}
+
+func (dto ParkingResponse) ToResponseModel(allowed bool, reason string, preferred *model.Zone) ParkingResponse {
+	dto.Allowed = allowed
+	dto.Reason = reason
+	if preferred != nil {
+		dto.PreferredZoneID = &preferred.ID
+	}
+	return dto
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogHandler struct {
+	service       *service.AuditService
+	exportMaxRows int
+}
+
+func NewAuditLogHandler(s *service.AuditService, exportMaxRows int) *AuditLogHandler {
+	return &AuditLogHandler{service: s, exportMaxRows: exportMaxRows}
+}
+
+// List -> GET /admin/audit-logs?actor_id=&action=&target_type=&target_id=&from=2024-09-04T10:00:00Z&to=&page=1&page_size=10
+func (h *AuditLogHandler) List(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		return err
+	}
+
+	logs, err := h.service.List(c.Context(), filter, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.AuditLogResponse, len(logs))
+	for i, item := range logs {
+		resp[i] = dto.AuditLogResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, dto.AuditLogListResponse{
+		AuditLogs:  resp,
+		Pagination: query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// Export filtreye uyan kayıtları eskiden yeniye CSV olarak indirir -> GET /admin/audit-logs/export?actor_id=&from=&to=
+func (h *AuditLogHandler) Export(c *fiber.Ctx) error {
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		return err
+	}
+
+	logs, err := h.service.Export(c.Context(), filter, h.exportMaxRows)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"id", "created_at", "actor_id", "actor_role", "action", "target_type", "target_id",
+		"before", "after", "method", "path", "status_code", "ip", "user_agent", "request_id", "prev_hash", "hash"})
+	for _, item := range logs {
+		actorID := ""
+		if item.ActorID != nil {
+			actorID = strconv.FormatInt(*item.ActorID, 10)
+		}
+		statusCode := ""
+		if item.StatusCode != 0 {
+			statusCode = strconv.Itoa(item.StatusCode)
+		}
+		_ = w.Write([]string{
+			strconv.FormatInt(item.ID, 10), item.CreatedAt.UTC().Format(time.RFC3339Nano), actorID, string(item.ActorRole),
+			csvSafe(item.Action), csvSafe(item.TargetType), csvSafe(item.TargetID), string(item.Before), string(item.After),
+			item.Method, csvSafe(item.Path), statusCode, csvSafe(item.IP), csvSafe(item.UserAgent), csvSafe(item.RequestID),
+			item.PrevHash, item.Hash,
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	c.Attachment(fmt.Sprintf("audit-logs-%s.csv", time.Now().UTC().Format("20060102-150405")))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
+
+// Verify hash zincirini baştan sona doğrular -> GET /admin/audit-logs/verify
+func (h *AuditLogHandler) Verify(c *fiber.Ctx) error {
+	result, err := h.service.Verify(c.Context())
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.AuditVerificationResponse{}.ToResponseModel(*result))
+}
+
+func parseAuditLogFilter(c *fiber.Ctx) (model.AuditLogFilter, error) {
+	filter := model.AuditLogFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+	if raw := c.Query("actor_id"); raw != "" {
+		actorID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return filter, errorx.WrapMsg(errorx.ErrInvalidRequest, "actor_id geçersiz")
+		}
+		filter.ActorID = actorID
+	}
+
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if filter.From, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, errorx.WrapMsg(errorx.ErrInvalidRequest, "from formatı geçersiz. Beklenen format: RFC3339")
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if filter.To, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, errorx.WrapMsg(errorx.ErrInvalidRequest, "to formatı geçersiz. Beklenen format: RFC3339")
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errorx.WrapMsg(errorx.ErrInvalidRequest, "from, to'dan önce olmalıdır")
+	}
+	return filter, nil
+}
+
+// csvSafe istemciden gelen metinlerin tablo programlarında formül olarak çalıştırılmasını engeller
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
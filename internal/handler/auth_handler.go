@@ -45,9 +45,9 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 
 	user := req.ToDBModel(model.User{})
 
-	err := h.authService.Register(c.Context(), user)
+	err := h.authService.Register(c.Context(), user, req.ReferralCode)
 	if err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
+		return errorx.FromError(errorx.ErrInternal, err)
 	}
 
 	resp := dto.RegisterResponse{
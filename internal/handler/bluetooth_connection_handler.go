@@ -7,16 +7,15 @@ import (
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
 	"github.com/gofiber/fiber/v2"
-	"time"
 )
 
 type BluetoothConnectionHandler struct {
-	service          *service.BluetoothConnectionService
-	motorbikeService *service.MotorbikeService
+	service     *service.BluetoothConnectionService
+	rideService *service.RideService
 }
 
-func NewBluetoothConnectionHandler(s *service.BluetoothConnectionService, m *service.MotorbikeService) *BluetoothConnectionHandler {
-	return &BluetoothConnectionHandler{service: s, motorbikeService: m}
+func NewBluetoothConnectionHandler(s *service.BluetoothConnectionService, r *service.RideService) *BluetoothConnectionHandler {
+	return &BluetoothConnectionHandler{service: s, rideService: r}
 }
 
 func (h *BluetoothConnectionHandler) GetMyConnections(ctx *fiber.Ctx) error {
@@ -37,6 +36,9 @@ func (h *BluetoothConnectionHandler) GetMyConnections(ctx *fiber.Ctx) error {
 	return response.Success(ctx, bluetoothConnection)
 }
 
+// Connect motora bağlanır ve sürüşü başlatır. Motorun rezerve edilmesi, sürüşün ve bağlantı kaydının
+// oluşturulması RideService.StartRide içinde tek transaction ile yapılır. Cihaza ulaşılabilirlik kontrolü ve
+// kilidin açılması da StartRide içinde LockController üzerinden yapılır.
 func (h *BluetoothConnectionHandler) Connect(ctx *fiber.Ctx) error {
 	var req dto.ConnectRequest
 	if err := ctx.BodyParser(&req); err != nil {
@@ -48,38 +50,12 @@ func (h *BluetoothConnectionHandler) Connect(ctx *fiber.Ctx) error {
 		return errorx.WrapMsg(errorx.ErrInvalidRequest, " Kullanıcı bulunamadı")
 	}
 
-	motor, err := h.motorbikeService.GetByID(ctx.Context(), req.MotorbikeID)
+	ride, err := h.rideService.StartRide(ctx.Context(), userID, req.MotorbikeID)
 	if err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Motorbisiklet bulunamadı")
-	}
-
-	// Motorbike'ın durumu 'Available' mı kontrol et
-	if motor.Status != model.BikeAvailable {
-		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu Motorbisiklet şu anda müsait değil!")
-	}
-
-	var bluetoothConnection model.BluetoothConnection
-	bluetoothConnectionOK := true // todo: burada ardinuo bağlantısı kontrol edilecek. Ardinuo dan gelen true olsun şimdilik
-	if bluetoothConnectionOK {
-		bluetoothConnection = req.ToDBModel(model.BluetoothConnection{})
-		// bağlandığı için lock statusu değiştir
-		motor.LockStatus = model.Unlocked
-	}
-
-	bluetoothConnection.UserID = userID
-
-	if err = h.service.Create(ctx.Context(), &bluetoothConnection); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
-	}
-
-	motor.Status = model.BikeRented
-
-	err = h.motorbikeService.Update(ctx.Context(), *motor)
-	if err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+		return err
 	}
 
-	return response.Success(ctx, nil, "Bluetooth bağlantısı başarıyla kuruldu")
+	return response.Success(ctx, dto.RideResponse{}.ToResponseModel(*ride), "Bluetooth bağlantısı başarıyla kuruldu")
 }
 
 func (h *BluetoothConnectionHandler) Create(ctx *fiber.Ctx) error {
@@ -164,6 +140,7 @@ func (h *BluetoothConnectionHandler) List(ctx *fiber.Ctx) error {
 	return response.Success(ctx, bluetoothConnection)
 }
 
+// Disconnect kullanıcının motorla bağlantısını keser; motor kilitli bırakılıp kiralamaya açılır -> POST /bluetooth/disconnect
 func (h *BluetoothConnectionHandler) Disconnect(ctx *fiber.Ctx) error {
 	var req dto.ConnectRequest
 	if err := ctx.BodyParser(&req); err != nil {
@@ -175,36 +152,8 @@ func (h *BluetoothConnectionHandler) Disconnect(ctx *fiber.Ctx) error {
 		return errorx.WrapMsg(errorx.ErrInvalidRequest, " Kullanıcı bulunamadı")
 	}
 
-	connection, err := h.service.GetByMotorbikeID(ctx.Context(), req.MotorbikeID)
-	if err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Bluetooth bağlantısı bulunamadı")
-	}
-
-	// zaten bağlantı koptuysa..
-	if connection.DisconnectedAt != nil {
-		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Zaten bağlantı kopmuş!")
-	}
-
-	bluetoothDisconnectOK := true // todo: bağlantı kesildi mi?? burada ardinuo bağlantısı kontrol edilecek. Ardinuo dan gelen true olsun şimdilik
-	if bluetoothDisconnectOK {
-		now := time.Now()
-		connection.DisconnectedAt = &now
-		if err = h.service.Update(ctx.Context(), *connection); err != nil {
-			return errorx.WrapMsg(errorx.ErrInternal, "Bağlantı kesilemedi!")
-		}
-	}
-
-	motor, err := h.motorbikeService.GetByID(ctx.Context(), connection.MotorbikeID)
-	if err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
-	}
-
-	motor.Status = model.BikeAvailable
-	motor.LockStatus = model.Locked
-
-	err = h.motorbikeService.Update(ctx.Context(), *motor)
-	if err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+	if err := h.rideService.Disconnect(ctx.Context(), userID, req.MotorbikeID); err != nil {
+		return err
 	}
 
 	return response.Success(ctx, nil, "Bluetooth bağlantısı başarıyla kesildi")
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeviceCommandHandler struct {
+	service     *service.DeviceCommandService
+	longPollMax time.Duration
+}
+
+func NewDeviceCommandHandler(s *service.DeviceCommandService, longPollMax time.Duration) *DeviceCommandHandler {
+	return &DeviceCommandHandler{service: s, longPollMax: longPollMax}
+}
+
+// Enqueue admin'in motora komut göndermesi -> /motorbike/:id/commands
+func (h *DeviceCommandHandler) Enqueue(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.EnqueueCommandRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+
+	command, err := h.service.Enqueue(c.Context(), service.EnqueueCommand{
+		MotorbikeID: int64(id),
+		Type:        model.DeviceCommandType(req.Type),
+		RequestedBy: &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DeviceCommandResponse{}.ToResponseModel(*command), "Komut kuyruğa eklendi")
+}
+
+func (h *DeviceCommandHandler) ListByMotorbike(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	commands, err := h.service.ListByMotorbikeID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.DeviceCommandResponse, len(commands))
+	for i, item := range commands {
+		resp[i] = dto.DeviceCommandResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+func (h *DeviceCommandHandler) GetByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	command, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DeviceCommandResponse{}.ToResponseModel(*command))
+}
+
+// Poll cihazın bekleyen komutlarını alması -> /devices/commands?wait=25
+// wait saniye cinsindendir; komut yoksa istek bu süre kadar açık tutulur (en fazla DEVICE_COMMAND_LONG_POLL_MAX_SECONDS)
+func (h *DeviceCommandHandler) Poll(c *fiber.Ctx) error {
+	wait := time.Duration(c.QueryInt("wait", 0)) * time.Second
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > h.longPollMax {
+		wait = h.longPollMax
+	}
+
+	motorbikeID := c.Locals("motorbikeID").(int64)
+
+	commands, err := h.service.Poll(c.Context(), motorbikeID, wait)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.DeviceCommandDelivery, len(commands))
+	for i, item := range commands {
+		resp[i] = dto.DeviceCommandDelivery{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// Ack cihazın komut sonucunu bildirmesi -> /devices/commands/:id/ack
+func (h *DeviceCommandHandler) Ack(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.CommandAckRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	motorbikeID := c.Locals("motorbikeID").(int64)
+
+	command, err := h.service.Ack(c.Context(), motorbikeID, int64(id), *req.Success, req.Message)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DeviceCommandDelivery{}.ToResponseModel(*command))
+}
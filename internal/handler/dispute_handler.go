@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+const disputeUploadDir = "uploads/disputes"
+
+// disputePhotoTypes itiraz fotoğrafı olarak kabul edilen içerik türleri ve kaydedildikleri dosya uzantıları.
+// İçerik türü istemcinin bildirdiği başlıktan değil dosyanın ilk baytlarından belirlenir.
+var disputePhotoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type DisputeHandler struct {
+	service       *service.DisputeService
+	maxPhotos     int
+	maxPhotoBytes int64
+}
+
+func NewDisputeHandler(s *service.DisputeService, maxPhotos int, maxPhotoBytes int64) *DisputeHandler {
+	return &DisputeHandler{service: s, maxPhotos: maxPhotos, maxPhotoBytes: maxPhotoBytes}
+}
+
+// Open kullanıcının sürüşüne itiraz açması -> POST /rides/:id/disputes (multipart: reason, description, requested_amount, photos)
+func (h *DisputeHandler) Open(c *fiber.Ctx) error {
+	rideID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.OpenDisputeRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	photos, err := h.savePhotos(c, rideID)
+	if err != nil {
+		return err
+	}
+
+	dispute, err := h.service.Open(c.Context(), service.OpenDisputeInput{
+		RideID:          int64(rideID),
+		UserID:          c.Locals("userID").(int64),
+		Reason:          model.DisputeReason(req.Reason),
+		Description:     req.Description,
+		RequestedAmount: req.RequestedAmount,
+		Photos:          photos,
+	})
+	if err != nil {
+		removePhotos(photos)
+		return err
+	}
+
+	return response.Success(c, dto.DisputeResponse{}.ToResponseModel(*dispute), "İtirazınız alındı")
+}
+
+// ListMyDisputes giriş yapmış kullanıcının itirazları -> GET /disputes/me
+func (h *DisputeHandler) ListMyDisputes(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	disputes, err := h.service.ListByUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.DisputeResponse, len(disputes))
+	for i, item := range disputes {
+		resp[i] = dto.DisputeResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// Get itirazı fotoğrafları ve durum geçmişiyle döner -> GET /disputes/:id (disputes.read yetkisi olan roller tüm itirazları görür)
+func (h *DisputeHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	dispute, err := h.service.Get(c.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.DisputeResponse{}.ToResponseModel(*dispute))
+}
+
+// GetPhoto itiraz fotoğrafını döner -> GET /disputes/:id/photos/:photoID
+func (h *DisputeHandler) GetPhoto(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	photoID, err := c.ParamsInt("photoID")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	photo, err := h.service.GetPhoto(c.Context(), int64(id), int64(photoID), userID, role)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, photo.ContentType)
+	return c.SendFile(photo.Path)
+}
+
+// Withdraw kullanıcının itirazını geri çekmesi -> POST /disputes/:id/withdraw
+func (h *DisputeHandler) Withdraw(c *fiber.Ctx) error {
+	return h.transition(c, "İtiraz geri çekildi", h.service.Withdraw)
+}
+
+// List destek kuyruğu -> GET /disputes?status=&reason=&user_id=&ride_id=&page=1&page_size=10
+func (h *DisputeHandler) List(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	filter := model.DisputeFilter{
+		Status: model.DisputeStatus(c.Query("status")),
+		Reason: model.DisputeReason(c.Query("reason")),
+		UserID: int64(c.QueryInt("user_id")),
+		RideID: int64(c.QueryInt("ride_id")),
+	}
+
+	disputes, err := h.service.List(c.Context(), filter, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.DisputeResponse, len(disputes))
+	for i, item := range disputes {
+		resp[i] = dto.DisputeResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, dto.DisputeListResponse{
+		Disputes:   resp,
+		Pagination: query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// Review itirazı incelemeye alır -> POST /disputes/:id/review
+func (h *DisputeHandler) Review(c *fiber.Ctx) error {
+	return h.transition(c, "İtiraz incelemeye alındı", h.service.StartReview)
+}
+
+// Reject itirazı reddeder -> POST /disputes/:id/reject {note}
+func (h *DisputeHandler) Reject(c *fiber.Ctx) error {
+	return h.transition(c, "İtiraz reddedildi", h.service.Reject)
+}
+
+// Approve itirazı onaylayıp iadeyi yapar -> POST /disputes/:id/approve {amount, destination, note}
+func (h *DisputeHandler) Approve(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.ApproveDisputeRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	dispute, err := h.service.Approve(c.Context(), service.ApproveDisputeInput{
+		DisputeID:   int64(id),
+		AdminID:     c.Locals("userID").(int64),
+		Amount:      req.Amount,
+		Destination: model.RefundDestination(req.Destination),
+		Note:        req.Note,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DisputeResponse{}.ToResponseModel(*dispute), "İtiraz onaylandı, iade yapıldı")
+}
+
+// ListRideAdjustments sürüşe yapılan iadeler -> GET /rides/:id/adjustments
+func (h *DisputeHandler) ListRideAdjustments(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	adjustments, err := h.service.ListRideAdjustments(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.RideAdjustmentResponse, len(adjustments))
+	for i, item := range adjustments {
+		resp[i] = dto.RideAdjustmentResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// transition notla yapılan durum değişikliklerini (geri çekme, incelemeye alma, ret) işler
+func (h *DisputeHandler) transition(c *fiber.Ctx, message string,
+	fn func(ctx context.Context, id, actorID int64, note string) (*model.Dispute, error)) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.DisputeNoteRequest
+	if len(c.Body()) > 0 {
+		if err = c.BodyParser(&req); err != nil {
+			return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+		}
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	dispute, err := fn(c.Context(), int64(id), c.Locals("userID").(int64), req.Note)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DisputeResponse{}.ToResponseModel(*dispute), message)
+}
+
+// savePhotos multipart istekteki fotoğrafları doğrulayıp diske kaydeder. Herhangi bir fotoğraf geçersizse hiçbiri kaydedilmez.
+func (h *DisputeHandler) savePhotos(c *fiber.Ctx, rideID int) ([]model.DisputePhoto, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return nil, nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	files := form.File["photos"]
+	if len(files) > h.maxPhotos {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("En fazla %d fotoğraf eklenebilir", h.maxPhotos))
+	}
+
+	types := make([]string, len(files))
+	for i, file := range files {
+		if file.Size > h.maxPhotoBytes {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Fotoğraf en fazla %d MB olabilir", h.maxPhotoBytes>>20))
+		}
+		if types[i], err = detectPhotoType(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = os.MkdirAll(disputeUploadDir, os.ModePerm); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrInternal, "Yükleme klasörü oluşturulamadı")
+	}
+	photos := make([]model.DisputePhoto, 0, len(files))
+	for i, file := range files {
+		path := filepath.Join(disputeUploadDir, fmt.Sprintf("ride_%d_%d_%d%s", rideID, time.Now().UnixNano(), i, disputePhotoTypes[types[i]]))
+		if err = c.SaveFile(file, path); err != nil {
+			removePhotos(photos)
+			return nil, errorx.WrapMsg(errorx.ErrInternal, "Fotoğraf kaydedilemedi")
+		}
+		photos = append(photos, model.DisputePhoto{Path: path, ContentType: types[i], Size: file.Size})
+	}
+	return photos, nil
+}
+
+// detectPhotoType dosyanın içerik türünü ilk baytlarından belirler ve desteklenmeyen türleri reddeder
+func detectPhotoType(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf okunamadı")
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	contentType := http.DetectContentType(head[:n])
+	if _, ok := disputePhotoTypes[contentType]; !ok {
+		return "", errorx.WrapMsg(errorx.ErrInvalidRequest, "Yalnızca JPEG, PNG veya WebP fotoğraf yüklenebilir")
+	}
+	return contentType, nil
+}
+
+func removePhotos(photos []model.DisputePhoto) {
+	for _, photo := range photos {
+		_ = os.Remove(photo.Path)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"io"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FileHandler struct {
+	service *service.FileService
+}
+
+func NewFileHandler(s *service.FileService) *FileHandler {
+	return &FileHandler{service: s}
+}
+
+// Get imzalı bağlantıdaki dosyayı döner -> GET /files/*?expires=&signature=
+// Kimlik doğrulaması istemez; erişim bağlantının imzası ve süresiyle sınırlıdır.
+func (h *FileHandler) Get(c *fiber.Ctx) error {
+	body, object, err := h.service.Open(c.Context(), c.Params("*"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, object.ContentType)
+	// Bağlantı süreli olduğu için tarayıcı önbelleği de bağlantının süresiyle sınırlı tutulur
+	if expires, err := strconv.ParseInt(c.Query("expires"), 10, 64); err == nil {
+		if maxAge := expires - c.Context().Time().Unix(); maxAge > 0 {
+			c.Set(fiber.HeaderCacheControl, "private, max-age="+strconv.FormatInt(maxAge, 10))
+		}
+	}
+	return c.SendStream(body, int(object.Size))
+}
+
+// readFormFile multipart dosyasını belleğe okur
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
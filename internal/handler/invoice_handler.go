@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+const mimePDF = "application/pdf"
+
+type InvoiceHandler struct {
+	service *service.InvoiceService
+}
+
+func NewInvoiceHandler(s *service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: s}
+}
+
+// GetRideReceipt sürüşün fişini PDF veya HTML olarak döner -> GET /rides/:id/receipt?format=pdf|html
+func (h *InvoiceHandler) GetRideReceipt(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	format, err := documentFormat(c)
+	if err != nil {
+		return err
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	invoice, err := h.service.GetRideReceipt(c.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+	return sendInvoice(c, invoice, format)
+}
+
+// ListMyInvoices giriş yapmış kullanıcının fiş ve faturaları -> GET /invoices
+func (h *InvoiceHandler) ListMyInvoices(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	invoices, err := h.service.ListByUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.InvoiceResponse, 0, len(invoices))
+	for _, invoice := range invoices {
+		resp = append(resp, dto.InvoiceResponse{}.ToResponseModel(invoice))
+	}
+	return response.Success(c, resp)
+}
+
+// GetInvoice belgeyi PDF veya HTML olarak döner -> GET /invoices/:id?format=pdf|html
+func (h *InvoiceHandler) GetInvoice(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	format, err := documentFormat(c)
+	if err != nil {
+		return err
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	invoice, err := h.service.Get(c.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+	return sendInvoice(c, invoice, format)
+}
+
+// documentFormat istenen belge formatını format parametresinden, yoksa Accept header'ından belirler
+func documentFormat(c *fiber.Ctx) (string, error) {
+	if format := c.Query("format"); format != "" {
+		if format != "pdf" && format != "html" {
+			return "", errorx.WrapMsg(errorx.ErrInvalidRequest, "format pdf veya html olmalıdır")
+		}
+		return format, nil
+	}
+	switch c.Accepts(mimePDF, fiber.MIMETextHTML) {
+	case fiber.MIMETextHTML:
+		return "html", nil
+	case "":
+		return "", errorx.WrapMsg(errorx.ErrInvalidRequest, "Desteklenen formatlar: application/pdf, text/html")
+	default:
+		return "pdf", nil
+	}
+}
+
+func sendInvoice(c *fiber.Ctx, invoice *model.Invoice, format string) error {
+	if format == "html" {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(invoice.HTML)
+	}
+	c.Attachment(invoice.Filename("pdf"))
+	c.Set(fiber.HeaderContentType, mimePDF)
+	return c.Send(invoice.PDF)
+}
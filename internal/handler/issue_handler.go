@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type IssueHandler struct {
+	service      *service.IssueService
+	fileService  *service.FileService
+	maxPhotoSize int64
+}
+
+func NewIssueHandler(s *service.IssueService, fileService *service.FileService, maxPhotoSize int64) *IssueHandler {
+	return &IssueHandler{service: s, fileService: fileService, maxPhotoSize: maxPhotoSize}
+}
+
+// Report motor için arıza bildirimi -> POST /motorbike/:id/issues (multipart: category, severity, description, ride_id, photos)
+func (h *IssueHandler) Report(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.ReportIssueRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var photos [][]byte
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraflar yüklenemedi")
+		}
+		files := form.File["photos"]
+		photos = make([][]byte, len(files))
+		for i, file := range files {
+			// Boyut servis tarafından da kontrol edilir; burada büyük dosyaların belleğe okunması önlenir
+			if file.Size > h.maxPhotoSize {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, h.maxPhotoSize>>20))
+			}
+			if photos[i], err = readFormFile(file); err != nil {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf okunamadı")
+			}
+		}
+	}
+
+	input := service.ReportIssueInput{
+		MotorbikeID: int64(motorbikeID),
+		UserID:      c.Locals("userID").(int64),
+		Category:    model.IssueCategory(req.Category),
+		Severity:    model.IssueSeverity(req.Severity),
+		Description: req.Description,
+		Photos:      photos,
+	}
+	if req.RideID != 0 {
+		input.RideID = &req.RideID
+	}
+	issue, err := h.service.Report(c.Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, h.issueResponse(*issue), "Bildiriminiz alındı, teşekkür ederiz")
+}
+
+// ListMyIssues giriş yapmış kullanıcının bildirimleri -> GET /issues/me
+func (h *IssueHandler) ListMyIssues(c *fiber.Ctx) error {
+	issues, err := h.service.ListByUser(c.Context(), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.IssueResponse, len(issues))
+	for i, item := range issues {
+		resp[i] = dto.IssueResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// Get bildirimi fotoğraflarıyla döner -> GET /issues/:id (issues.read yetkisi olan roller tüm bildirimleri görür)
+func (h *IssueHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	issue, err := h.service.Get(c.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, h.issueResponse(*issue))
+}
+
+// List triyaj kuyruğu -> GET /issues?status=&category=&severity=&motorbike_id=&page=1&page_size=10
+func (h *IssueHandler) List(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	filter := model.IssueFilter{
+		Status:      model.IssueStatus(c.Query("status")),
+		Category:    model.IssueCategory(c.Query("category")),
+		Severity:    model.IssueSeverity(c.Query("severity")),
+		MotorbikeID: int64(c.QueryInt("motorbike_id")),
+	}
+
+	issues, err := h.service.List(c.Context(), filter, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.IssueResponse, len(issues))
+	for i, item := range issues {
+		resp[i] = dto.IssueResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, dto.IssueListResponse{
+		Issues:     resp,
+		Pagination: query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// LinkRide bildirimi sürüşe bağlar -> POST /issues/:id/ride {ride_id}
+func (h *IssueHandler) LinkRide(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.LinkIssueRideRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	issue, err := h.service.LinkRide(c.Context(), int64(id), req.RideID)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.IssueResponse{}.ToResponseModel(*issue), "Bildirim sürüşe bağlandı")
+}
+
+// MarkDuplicate bildirimi başka bir bildirimin kopyası olarak kapatır -> POST /issues/:id/duplicate {duplicate_of_id, note}
+func (h *IssueHandler) MarkDuplicate(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.MarkIssueDuplicateRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	issue, err := h.service.MarkDuplicate(c.Context(), int64(id), c.Locals("userID").(int64), req.DuplicateOfID, req.Note)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.IssueResponse{}.ToResponseModel(*issue), "Bildirim kopya olarak işaretlendi")
+}
+
+// Resolve bildirimi sonuçlandırır ve bildirenleri bilgilendirir -> POST /issues/:id/resolve {note}
+func (h *IssueHandler) Resolve(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.ResolveIssueRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	issue, err := h.service.Resolve(c.Context(), int64(id), c.Locals("userID").(int64), req.Note)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.IssueResponse{}.ToResponseModel(*issue), "Bildirim sonuçlandı")
+}
+
+func (h *IssueHandler) issueResponse(issue model.Issue) dto.IssueResponse {
+	resp := dto.IssueResponse{}.ToResponseModel(issue)
+	for _, photo := range issue.Photos {
+		url, expiresAt := h.fileService.SignedURL(photo.Key)
+		thumbnailURL, _ := h.fileService.SignedURL(photo.ThumbnailKey)
+		resp.Photos = append(resp.Photos, dto.IssuePhotoResponse{}.ToResponseModel(photo, url, thumbnailURL, expiresAt))
+	}
+	return resp
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MaintenanceHandler struct {
+	service *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(s *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{service: s}
+}
+
+// OpenWorkOrder iş emri açar ve motoru bakıma alır -> POST /maintenance/work-orders
+func (h *MaintenanceHandler) OpenWorkOrder(c *fiber.Ctx) error {
+	var req dto.OpenWorkOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	openedBy := c.Locals("userID").(int64)
+	order, err := h.service.Open(c.Context(), service.OpenWorkOrderInput{
+		MotorbikeID:  req.MotorbikeID,
+		Type:         model.MaintenanceType(req.Type),
+		Priority:     model.WorkOrderPriority(req.Priority),
+		Description:  req.Description,
+		TechnicianID: req.TechnicianID,
+		OpenedBy:     &openedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order), "İş emri açıldı, motor bakıma alındı")
+}
+
+// ListWorkOrders -> GET /maintenance/work-orders?status=&type=&motorbike_id=&technician_id=&page=1&page_size=10
+func (h *MaintenanceHandler) ListWorkOrders(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	filter := model.WorkOrderFilter{
+		Status:       model.WorkOrderStatus(c.Query("status")),
+		Type:         model.MaintenanceType(c.Query("type")),
+		MotorbikeID:  int64(c.QueryInt("motorbike_id")),
+		TechnicianID: int64(c.QueryInt("technician_id")),
+	}
+
+	orders, err := h.service.List(c.Context(), filter, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.WorkOrderResponse, len(orders))
+	for i, item := range orders {
+		resp[i] = dto.WorkOrderResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, dto.WorkOrderListResponse{
+		WorkOrders: resp,
+		Pagination: query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// GetWorkOrder iş emrini kullanılan parçalarla döner -> GET /maintenance/work-orders/:id
+func (h *MaintenanceHandler) GetWorkOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	order, err := h.service.Get(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order))
+}
+
+// UpdateWorkOrder -> PUT /maintenance/work-orders/:id {priority, description, technician_id}
+func (h *MaintenanceHandler) UpdateWorkOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdateWorkOrderRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	order, err := h.service.Update(c.Context(), service.UpdateWorkOrderInput{
+		ID:           int64(id),
+		Priority:     model.WorkOrderPriority(req.Priority),
+		Description:  req.Description,
+		TechnicianID: req.TechnicianID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order), "İş emri güncellendi")
+}
+
+// StartWorkOrder -> POST /maintenance/work-orders/:id/start
+func (h *MaintenanceHandler) StartWorkOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	order, err := h.service.Start(c.Context(), int64(id), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order), "İş emrine başlandı")
+}
+
+// CompleteWorkOrder -> POST /maintenance/work-orders/:id/complete {parts, labour_minutes, labour_cost, note}
+func (h *MaintenanceHandler) CompleteWorkOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.CompleteWorkOrderRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	order, err := h.service.Complete(c.Context(), service.CompleteWorkOrderInput{
+		ID:            int64(id),
+		ActorID:       c.Locals("userID").(int64),
+		Parts:         req.ToPartModels(),
+		LabourMinutes: req.LabourMinutes,
+		LabourCost:    req.LabourCost,
+		Note:          req.Note,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order), "İş emri tamamlandı")
+}
+
+// CancelWorkOrder -> POST /maintenance/work-orders/:id/cancel {note}
+func (h *MaintenanceHandler) CancelWorkOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.WorkOrderNoteRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	order, err := h.service.Cancel(c.Context(), int64(id), c.Locals("userID").(int64), req.Note)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WorkOrderResponse{}.ToResponseModel(*order), "İş emri iptal edildi")
+}
+
+// ServiceHistory motorun servis geçmişi -> GET /motorbike/:id/service-history
+func (h *MaintenanceHandler) ServiceHistory(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	orders, err := h.service.History(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.WorkOrderResponse, len(orders))
+	for i, item := range orders {
+		resp[i] = dto.WorkOrderResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+func (h *MaintenanceHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req dto.CreateMaintenanceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	schedule := req.ToDBModel(model.MaintenanceSchedule{})
+	if err := h.service.CreateSchedule(c.Context(), &schedule); err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.MaintenanceScheduleResponse{}.ToResponseModel(schedule), "Bakım planı oluşturuldu")
+}
+
+func (h *MaintenanceHandler) GetSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	schedule, err := h.service.GetSchedule(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.MaintenanceScheduleResponse{}.ToResponseModel(*schedule))
+}
+
+func (h *MaintenanceHandler) UpdateSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdateMaintenanceScheduleRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	current, err := h.service.GetSchedule(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.UpdateSchedule(c.Context(), req.ToDBModel(*current)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Bakım planı güncellendi")
+}
+
+func (h *MaintenanceHandler) DeleteSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.service.DeleteSchedule(c.Context(), int64(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Bakım planı silindi")
+}
+
+func (h *MaintenanceHandler) ListSchedules(c *fiber.Ctx) error {
+	resp, err := h.service.ListSchedules(c.Context())
+	if err != nil {
+		return err
+	}
+
+	schedules := make([]dto.MaintenanceScheduleResponse, len(resp))
+	for i, item := range resp {
+		schedules[i] = dto.MaintenanceScheduleResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, schedules)
+}
@@ -1,20 +1,27 @@
 package handler
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
 	"github.com/gofiber/fiber/v2"
 )
 
 type MotorbikeHandler struct {
-	service *service.MotorbikeService
+	service      *service.MotorbikeService
+	photoService *service.MotorbikePhotoService
+	fileService  *service.FileService
+	maxPhotoSize int64
 }
 
-func NewMotorbikeHandler(s *service.MotorbikeService) *MotorbikeHandler {
-	return &MotorbikeHandler{service: s}
+func NewMotorbikeHandler(s *service.MotorbikeService, photoService *service.MotorbikePhotoService, fileService *service.FileService, maxPhotoSize int64) *MotorbikeHandler {
+	return &MotorbikeHandler{service: s, photoService: photoService, fileService: fileService, maxPhotoSize: maxPhotoSize}
 }
 
 func (h *MotorbikeHandler) Create(c *fiber.Ctx) error {
@@ -43,7 +50,13 @@ func (h *MotorbikeHandler) GetByID(c *fiber.Ctx) error {
 		return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
 	}
 
+	photos, err := h.photoService.List(c.Context(), resp.ID)
+	if err != nil {
+		return err
+	}
+
 	motorbike := dto.MotorbikeResponse{}.ToResponseModel(*resp)
+	motorbike.Photos = h.photoDetails(photos)
 
 	return response.Success(c, motorbike)
 }
@@ -93,9 +106,9 @@ func (h *MotorbikeHandler) List(c *fiber.Ctx) error {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
 
-	motorbikes := make([]dto.MotorbikeResponse, len(resp))
-	for i, item := range resp {
-		motorbikes[i] = dto.MotorbikeResponse{}.ToResponseModel(item)
+	motorbikes, err := h.listResponse(c.Context(), resp)
+	if err != nil {
+		return err
 	}
 	return response.Success(c, motorbikes)
 }
@@ -110,9 +123,56 @@ func (h *MotorbikeHandler) GetAvailableMotors(c *fiber.Ctx) error {
 		return response.Success(c, nil, "Müsait motor bulunamadı!")
 	}
 
-	motorbikes := make([]dto.MotorbikeResponse, len(resp))
+	motorbikes, err := h.listResponse(c.Context(), resp)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, motorbikes)
+}
+
+// Varsayılan arama yarıçapı ve sonuç sayısı
+const (
+	defaultNearbyRadiusMeters = 1000
+	defaultNearbyLimit        = 20
+)
+
+func (h *MotorbikeHandler) GetNearbyMotors(c *fiber.Ctx) error {
+	var query dto.NearbyMotorbikeQuery
+	if err := c.QueryParser(&query); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(query); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	radius := query.RadiusM
+	if radius == 0 {
+		radius = defaultNearbyRadiusMeters
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultNearbyLimit
+	}
+
+	resp, err := h.service.FindNearby(c.Context(), geo.Point{Lat: *query.Lat, Lng: *query.Lng}, radius, limit)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, len(resp))
 	for i, item := range resp {
-		motorbikes[i] = dto.MotorbikeResponse{}.ToResponseModel(item)
+		ids[i] = item.Motorbike.ID
+	}
+	covers, err := h.photoService.Covers(c.Context(), ids)
+	if err != nil {
+		return err
+	}
+
+	motorbikes := make([]dto.NearbyMotorbikeResponse, len(resp))
+	for i, item := range resp {
+		motorbikes[i] = dto.NearbyMotorbikeResponse{}.ToResponseModel(item.Motorbike, item.DistanceMeters)
+		motorbikes[i].CoverPhoto = h.coverPhoto(covers, item.Motorbike.ID)
 	}
 	return response.Success(c, motorbikes)
 }
@@ -127,9 +187,9 @@ func (h *MotorbikeHandler) GetMaintenanceMotors(c *fiber.Ctx) error {
 		return response.Success(c, nil, "Bakımda motor yok!")
 	}
 
-	motorbikes := make([]dto.MotorbikeResponse, len(resp))
-	for i, item := range resp {
-		motorbikes[i] = dto.MotorbikeResponse{}.ToResponseModel(item)
+	motorbikes, err := h.listResponse(c.Context(), resp)
+	if err != nil {
+		return err
 	}
 
 	return response.Success(c, motorbikes)
@@ -145,29 +205,163 @@ func (h *MotorbikeHandler) GetRentedMotors(c *fiber.Ctx) error {
 		return response.Success(c, nil, "Kiralanmış motor yok!")
 	}
 
-	motorbikes := make([]dto.MotorbikeResponse, len(resp))
-	for i, item := range resp {
-		motorbikes[i] = dto.MotorbikeResponse{}.ToResponseModel(item)
+	motorbikes, err := h.listResponse(c.Context(), resp)
+	if err != nil {
+		return err
 	}
 
 	return response.Success(c, motorbikes)
 }
 
+// GetPhotosByID motorun galerisi -> GET /motorbike/motorbike-photos/:id
 func (h *MotorbikeHandler) GetPhotosByID(c *fiber.Ctx) error {
-	motorbikeID := c.Params("id")
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
 
-	photos, err := h.service.GetPhotosByID(c.Context(), motorbikeID)
+	photos, err := h.photoService.List(c.Context(), int64(motorbikeID))
 	if err != nil {
 		return errorx.WrapMsg(errorx.ErrInternal, "Fotoğraflar getirilirken bir hata oluştu!")
 	}
-	photoDetails := make([]dto.PhotoDetailDto, len(photos))
-	for i, photo := range photos {
-		photoDetails[i] = dto.PhotoDetailDto{
-			ID:          int(photo.ID),
-			MotorbikeID: photo.MotorbikeID,
-			PhotoURL:    photo.PhotoURL,
+
+	return response.Success(c, h.photoDetails(photos))
+}
+
+// UploadPhotos galeriye fotoğraf ekler -> POST /motorbike/:id/photos (multipart: photos)
+func (h *MotorbikeHandler) UploadPhotos(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraflar yüklenemedi")
+	}
+	files := form.File["photos"]
+	data := make([][]byte, len(files))
+	for i, file := range files {
+		// Boyut servis tarafından da kontrol edilir; burada büyük dosyaların belleğe okunması önlenir
+		if file.Size > h.maxPhotoSize {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, h.maxPhotoSize>>20))
 		}
+		if data[i], err = readFormFile(file); err != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf okunamadı")
+		}
+	}
+
+	photos, err := h.photoService.Upload(c.Context(), int64(motorbikeID), data)
+	if err != nil {
+		return err
 	}
 
-	return response.Success(c, photoDetails)
+	return response.Success(c, h.photoDetails(photos), "Fotoğraflar yüklendi")
+}
+
+// ReorderPhotos galeriyi yeniden sıralar -> PUT /motorbike/:id/photos/order
+func (h *MotorbikeHandler) ReorderPhotos(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.ReorderPhotosRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	photos, err := h.photoService.Reorder(c.Context(), int64(motorbikeID), req.PhotoIDs)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, h.photoDetails(photos), "Fotoğraf sırası güncellendi")
+}
+
+// SetPrimaryPhoto fotoğrafı kapak yapar -> PUT /motorbike/:id/photos/:photoID/primary
+func (h *MotorbikeHandler) SetPrimaryPhoto(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	photoID, err := c.ParamsInt("photoID")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	photos, err := h.photoService.SetPrimary(c.Context(), int64(motorbikeID), int64(photoID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, h.photoDetails(photos), "Kapak fotoğrafı güncellendi")
+}
+
+// DeletePhoto fotoğrafı galeriden siler -> DELETE /motorbike/:id/photos/:photoID
+func (h *MotorbikeHandler) DeletePhoto(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	photoID, err := c.ParamsInt("photoID")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.photoService.Delete(c.Context(), int64(motorbikeID), int64(photoID)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Fotoğraf silindi")
+}
+
+// listResponse motor listelerini kapak fotoğraflarının küçük resimleriyle birlikte döner
+func (h *MotorbikeHandler) listResponse(ctx context.Context, motorbikes []model.Motorbike) ([]dto.MotorbikeResponse, error) {
+	ids := make([]int64, len(motorbikes))
+	for i, item := range motorbikes {
+		ids[i] = item.ID
+	}
+	covers, err := h.photoService.Covers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.MotorbikeResponse, len(motorbikes))
+	for i, item := range motorbikes {
+		resp[i] = dto.MotorbikeResponse{}.ToResponseModel(item)
+		resp[i].CoverPhoto = h.coverPhoto(covers, item.ID)
+	}
+	return resp, nil
+}
+
+func (h *MotorbikeHandler) coverPhoto(covers map[int64]model.MotorbikePhoto, motorbikeID int64) *dto.CoverPhotoDto {
+	photo, ok := covers[motorbikeID]
+	if !ok {
+		return nil
+	}
+	_, thumbnailURL := h.photoURLs(photo)
+	return &dto.CoverPhotoDto{ID: photo.ID, ThumbnailURL: thumbnailURL}
+}
+
+func (h *MotorbikeHandler) photoDetails(photos []model.MotorbikePhoto) []dto.PhotoDetailDto {
+	details := make([]dto.PhotoDetailDto, len(photos))
+	for i, photo := range photos {
+		url, thumbnailURL := h.photoURLs(photo)
+		details[i] = dto.PhotoDetailDto{}.ToResponseModel(photo, url, thumbnailURL)
+	}
+	return details
+}
+
+// photoURLs depodaki fotoğraflar için imzalı bağlantıları, eski kayıtlar için kayıttaki adresi döner
+func (h *MotorbikeHandler) photoURLs(photo model.MotorbikePhoto) (string, string) {
+	if photo.StorageKey == "" {
+		return photo.PhotoURL, photo.PhotoURL
+	}
+	url, _ := h.fileService.SignedURL(photo.StorageKey)
+	thumbnailURL, _ := h.fileService.SignedURL(photo.ThumbnailKey)
+	return url, thumbnailURL
 }
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PassHandler struct {
+	service *service.PassService
+}
+
+func NewPassHandler(s *service.PassService) *PassHandler {
+	return &PassHandler{service: s}
+}
+
+// ListProducts satıştaki abonelik paketleri -> GET /passes
+func (h *PassHandler) ListProducts(c *fiber.Ctx) error {
+	return h.listProducts(c, true)
+}
+
+// ListAllProducts satıştan kaldırılanlar dahil tüm paketler -> GET /passes/products
+func (h *PassHandler) ListAllProducts(c *fiber.Ctx) error {
+	return h.listProducts(c, false)
+}
+
+// Purchase giriş yapmış kullanıcıya paket satın alır -> POST /passes/purchase
+func (h *PassHandler) Purchase(c *fiber.Ctx) error {
+	var req dto.PurchasePassRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	autoRenew := req.AutoRenew == nil || *req.AutoRenew
+
+	subscription, err := h.service.Purchase(c.Context(), userID, req.ProductID, autoRenew)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.SubscriptionResponse{}.ToResponseModel(*subscription), "Abonelik satın alındı")
+}
+
+// GetMyPass kullanıcının aktif aboneliği ve bugün kalan dahil dakikaları -> GET /passes/me
+func (h *PassHandler) GetMyPass(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	status, err := h.service.GetActive(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := dto.SubscriptionResponse{}.ToResponseModel(*status.Subscription)
+	resp.RemainingMinutes = &status.RemainingMinutes
+	return response.Success(c, resp)
+}
+
+// CancelAutoRenew aboneliğin otomatik yenilemesini kapatır -> DELETE /passes/me/auto-renew
+func (h *PassHandler) CancelAutoRenew(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	subscription, err := h.service.CancelAutoRenew(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.SubscriptionResponse{}.ToResponseModel(*subscription), "Otomatik yenileme kapatıldı, abonelik dönem sonuna kadar geçerli")
+}
+
+func (h *PassHandler) CreateProduct(c *fiber.Ctx) error {
+	var req dto.CreatePassProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	product := req.ToDBModel(model.PassProduct{})
+
+	if err := h.service.CreateProduct(c.Context(), &product); err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PassProductResponse{}.ToResponseModel(product), "Abonelik paketi başarıyla oluşturuldu")
+}
+
+func (h *PassHandler) GetProduct(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	resp, err := h.service.GetProduct(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PassProductResponse{}.ToResponseModel(*resp))
+}
+
+func (h *PassHandler) UpdateProduct(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdatePassProductRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	currentProduct, err := h.service.GetProduct(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	product := req.ToDBModel(*currentProduct)
+
+	if err = h.service.UpdateProduct(c.Context(), product); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Abonelik paketi başarıyla güncellendi")
+}
+
+func (h *PassHandler) DeleteProduct(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.service.DeleteProduct(c.Context(), int64(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Abonelik paketi başarıyla silindi")
+}
+
+func (h *PassHandler) listProducts(c *fiber.Ctx, activeOnly bool) error {
+	resp, err := h.service.ListProducts(c.Context(), activeOnly)
+	if err != nil {
+		return err
+	}
+
+	products := make([]dto.PassProductResponse, len(resp))
+	for i, item := range resp {
+		products[i] = dto.PassProductResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, products)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentSignatureHeader ödeme sağlayıcısının webhook imzasını gönderdiği header
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+type PaymentHandler struct {
+	service *service.PaymentService
+}
+
+func NewPaymentHandler(s *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{service: s}
+}
+
+// PayRide ödemesi alınamamış sürüşün ödemesini tekrar dener -> POST /rides/:id/pay
+func (h *PaymentHandler) PayRide(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	ride, err := h.service.RetryRide(c.Context(), int64(id), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.RideResponse{}.ToResponseModel(*ride), "Ödeme alındı")
+}
+
+// Webhook ödeme sağlayıcısının bildirimlerini alır -> POST /payments/webhook
+func (h *PaymentHandler) Webhook(c *fiber.Ctx) error {
+	if err := h.service.HandleWebhook(c.Context(), c.Body(), c.Get(PaymentSignatureHeader)); err != nil {
+		return err
+	}
+
+	return response.SuccessNoData(c)
+}
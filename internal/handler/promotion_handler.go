@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandler struct {
+	service   *service.PromotionService
+	referrals *service.ReferralService
+}
+
+func NewPromotionHandler(s *service.PromotionService, referrals *service.ReferralService) *PromotionHandler {
+	return &PromotionHandler{service: s, referrals: referrals}
+}
+
+func (h *PromotionHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreatePromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	promotion := req.ToDBModel(model.Promotion{})
+
+	if err := h.service.Create(c.Context(), &promotion); err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PromotionResponse{}.ToResponseModel(promotion), "Kampanya başarıyla oluşturuldu")
+}
+
+func (h *PromotionHandler) GetByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	resp, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PromotionResponse{}.ToResponseModel(*resp))
+}
+
+func (h *PromotionHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdatePromotionRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	currentPromotion, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	promotion := req.ToDBModel(*currentPromotion)
+
+	if err = h.service.Update(c.Context(), promotion); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Kampanya başarıyla güncellendi")
+}
+
+func (h *PromotionHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.service.Delete(c.Context(), int64(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Kampanya başarıyla silindi")
+}
+
+func (h *PromotionHandler) List(c *fiber.Ctx) error {
+	resp, err := h.service.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	promotions := make([]dto.PromotionResponse, len(resp))
+	for i, item := range resp {
+		promotions[i] = dto.PromotionResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, promotions)
+}
+
+// UsageReport tüm kampanyaların ve davetlerin kullanım özeti -> GET /promotions/usage
+func (h *PromotionHandler) UsageReport(c *fiber.Ctx) error {
+	usage, err := h.service.UsageReport(c.Context())
+	if err != nil {
+		return err
+	}
+
+	referrals, err := h.referrals.Summary(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PromotionUsageReportResponse{
+		Promotions: usage,
+		Referrals:  *referrals,
+	})
+}
+
+// Usage kampanyanın kullanım özeti ve kullanımları -> GET /promotions/:id/usage?page=1&page_size=10
+func (h *PromotionHandler) Usage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	usage, resp, err := h.service.Usage(c.Context(), int64(id), &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	redemptions := make([]dto.PromotionRedemptionResponse, len(resp))
+	for i, item := range resp {
+		redemptions[i] = dto.PromotionRedemptionResponse{}.ToResponseModel(item)
+	}
+
+	return response.Success(c, dto.PromotionUsageResponse{
+		Usage:       *usage,
+		Redemptions: redemptions,
+		Pagination:  query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// ApplyToRide kampanya kodunu kullanıcının devam eden sürüşüne ekler -> POST /rides/:id/promo-code
+func (h *PromotionHandler) ApplyToRide(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.ApplyPromotionRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	promotion, err := h.service.ApplyToRide(c.Context(), int64(id), userID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.PromotionResponse{}.ToResponseModel(*promotion), "Kampanya kodu sürüşe eklendi, indirim sürüş bitince uygulanır")
+}
+
+// GetMyReferral kullanıcının davet kodu ve davet özeti -> GET /users/me/referral
+func (h *PromotionHandler) GetMyReferral(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	code, err := h.referrals.GetCode(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.referrals.Stats(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	referrerCredit, refereeCredit := h.referrals.Credits()
+	return response.Success(c, dto.ReferralResponse{
+		Code:           code.Code,
+		ReferrerCredit: referrerCredit,
+		RefereeCredit:  refereeCredit,
+		Stats:          *stats,
+	})
+}
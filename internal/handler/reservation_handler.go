@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReservationHandler struct {
+	service *service.ReservationService
+}
+
+func NewReservationHandler(s *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: s}
+}
+
+// Reserve motoru giriş yapmış kullanıcı adına rezerve eder -> POST /motorbike/:id/reserve
+func (h *ReservationHandler) Reserve(c *fiber.Ctx) error {
+	motorbikeID, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+
+	reservation, err := h.service.Reserve(c.Context(), userID, int64(motorbikeID))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.ReservationResponse{}.ToResponseModel(*reservation), "Motor rezerve edildi")
+}
+
+func (h *ReservationHandler) Cancel(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := c.Locals("userID").(int64)
+	role := c.Locals("role").(model.Role)
+
+	if err = h.service.Cancel(c.Context(), int64(id), userID, role); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Rezervasyon iptal edildi")
+}
+
+func (h *ReservationHandler) GetMyActive(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	reservation, err := h.service.GetMyActive(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.ReservationResponse{}.ToResponseModel(*reservation))
+}
+
+func (h *ReservationHandler) ListMyReservations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int64)
+
+	resp, err := h.service.ListByUserID(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	reservations := make([]dto.ReservationResponse, len(resp))
+	for i, item := range resp {
+		reservations[i] = dto.ReservationResponse{}.ToResponseModel(item)
+	}
+
+	return response.Success(c, reservations)
+}
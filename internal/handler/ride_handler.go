@@ -1,8 +1,7 @@
 package handler
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -10,33 +9,51 @@ import (
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	mimeGeoJSON = "application/geo+json"
+	mimeGPX     = "application/gpx+xml"
+)
+
 type RideHandler struct {
-	rideService *service.RideService
+	rideService  *service.RideService
+	photoService *service.RidePhotoService
+	fileService  *service.FileService
+	maxPhotoSize int64
 }
 
-func NewRideHandler(rideService *service.RideService) *RideHandler {
+func NewRideHandler(rideService *service.RideService, photoService *service.RidePhotoService, fileService *service.FileService, maxPhotoSize int64) *RideHandler {
 	return &RideHandler{
-		rideService: rideService,
+		rideService:  rideService,
+		photoService: photoService,
+		fileService:  fileService,
+		maxPhotoSize: maxPhotoSize,
 	}
 }
 
-func (h *RideHandler) Create(c *fiber.Ctx) error {
-	var req dto.CreateRideRequest
+func (h *RideHandler) StartRide(c *fiber.Ctx) error {
+	var req dto.StartRideRequest
 	if err := c.BodyParser(&req); err != nil {
 		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
 	}
 
-	ride := req.ToDBModel(model.Ride{})
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
 
-	if err := h.rideService.Create(c.Context(), &ride); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
+	userID := c.Locals("userID").(int64)
+
+	ride, err := h.rideService.StartRide(c.Context(), userID, req.MotorbikeID)
+	if err != nil {
+		return err
 	}
 
-	return response.Success(c, nil, "Ride başarıyla oluşturuldu")
+	return response.Success(c, dto.RideResponse{}.ToResponseModel(*ride), "Sürüş başlatıldı")
 }
 
 func (h *RideHandler) GetByID(c *fiber.Ctx) error {
@@ -55,31 +72,6 @@ func (h *RideHandler) GetByID(c *fiber.Ctx) error {
 	return response.Success(c, ride)
 }
 
-func (h *RideHandler) Update(c *fiber.Ctx) error {
-	id, err := c.ParamsInt("id")
-	if err != nil {
-		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
-	}
-
-	var req dto.UpdateRideRequest
-	if err = c.BodyParser(&req); err != nil {
-		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
-	}
-
-	currentRide, err := h.rideService.GetByID(c.Context(), int64(id))
-	if err != nil {
-		return err
-	}
-
-	ride := req.ToDBModel(*currentRide)
-
-	if err = h.rideService.Update(c.Context(), ride); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
-	}
-
-	return response.Success(c, nil, "Ride başarıyla güncellendi")
-}
-
 func (h *RideHandler) Delete(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
@@ -175,37 +167,252 @@ func (h *RideHandler) FinishRide(ctx *fiber.Ctx) error {
 		return err // zaten wrap edilmiş şekilde dönüyor
 	}
 
-	return response.Success(ctx, dto.RideResponse{}.ToResponseModel(*ride), "Sürüş Bitirildi! Ücret: "+strconv.FormatFloat(ride.Cost, 'f', 2, 64)+" TL")
+	message := "Sürüş Bitirildi! Ücret: " + money.Format(ride.Cost, ride.Currency)
+	if ride.Status == model.RidePaymentFailed {
+		message += ". Ödeme alınamadı, yeni sürüş başlatmadan önce ödemeyi tamamlayın"
+	}
+	return response.Success(ctx, dto.RideResponse{}.ToResponseModel(*ride), message)
+}
+
+func (h *RideHandler) PauseRide(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := ctx.Locals("userID").(int64)
+
+	ride, err := h.rideService.PauseRide(ctx.Context(), int64(id), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(ctx, dto.RideResponse{}.ToResponseModel(*ride), "Sürüş park moduna alındı")
+}
+
+func (h *RideHandler) ResumeRide(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := ctx.Locals("userID").(int64)
+
+	ride, err := h.rideService.ResumeRide(ctx.Context(), int64(id), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(ctx, dto.RideResponse{}.ToResponseModel(*ride), "Sürüşe devam ediliyor")
+}
+
+func (h *RideHandler) GetPriceBreakdown(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := ctx.Locals("userID").(int64)
+	role := ctx.Locals("role").(model.Role)
+
+	breakdown, err := h.rideService.GetPriceBreakdown(ctx.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(ctx, dto.PriceBreakdownResponse{}.ToResponseModel(*breakdown))
+}
+
+// ListEvents sürüşün durum geçmişi -> GET /rides/:id/events (rides.read yetkisi olan roller tüm sürüşleri, kullanıcı kendi sürüşünü görür)
+func (h *RideHandler) ListEvents(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := ctx.Locals("userID").(int64)
+	role := ctx.Locals("role").(model.Role)
+
+	events, err := h.rideService.ListEvents(ctx.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.RideEventResponse, len(events))
+	for i, item := range events {
+		resp[i] = dto.RideEventResponse{}.ToResponseModel(item)
+	}
+	return response.Success(ctx, resp)
+}
+
+func (h *RideHandler) GetActiveRide(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(int64)
+
+	active, err := h.rideService.GetActiveRide(ctx.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := dto.ActiveRideResponse{}.ToResponseModel(*active.Ride, *active.Motorbike, active.Elapsed, *active.Fare)
+	resp.Parking = dto.ParkingResponse{}.ToResponseModel(active.Parking.Allowed, active.Parking.Reason, active.Parking.PreferredZone)
+
+	return response.Success(ctx, resp)
+}
+
+func (h *RideHandler) EstimateFare(ctx *fiber.Ctx) error {
+	var req dto.EstimateFareRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	estimate, err := h.rideService.EstimateFare(ctx.Context(), req.MotorbikeModel, time.Duration(req.DurationMinutes)*time.Minute, req.StartTime)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(ctx, dto.PriceBreakdownResponse{}.ToResponseModel(*estimate))
 }
 
+func (h *RideHandler) RecordRoute(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.RecordRouteRequest
+	if err = ctx.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	userID := ctx.Locals("userID").(int64)
+
+	if err = h.rideService.RecordRoute(ctx.Context(), int64(id), userID, req.ToDBModels()); err != nil {
+		return err
+	}
+
+	return response.Success(ctx, nil, "Rota noktaları kaydedildi")
+}
+
+// GetRoute sürüş rotasını Accept header'ına göre GeoJSON (varsayılan) veya GPX olarak döner.
+// ?format=gpx|geojson ile header'sız istemciler de format seçebilir.
+func (h *RideHandler) GetRoute(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		switch ctx.Accepts(mimeGeoJSON, fiber.MIMEApplicationJSON, mimeGPX) {
+		case mimeGPX:
+			format = "gpx"
+		case "":
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Desteklenen formatlar: application/geo+json, application/gpx+xml")
+		default:
+			format = "geojson"
+		}
+	}
+
+	userID := ctx.Locals("userID").(int64)
+	role := ctx.Locals("role").(model.Role)
+
+	route, err := h.rideService.GetRoute(ctx.Context(), int64(id), userID, role)
+	if err != nil {
+		return err
+	}
+
+	ride := *route.Ride
+	route.Summary.Apply(&ride)
+	track := dto.RideRouteTrack(route.Points)
+
+	var body []byte
+	switch format {
+	case "gpx":
+		body, err = geo.MarshalGPX("Sürüş #"+strconv.FormatInt(ride.ID, 10), track)
+		ctx.Attachment("ride-" + strconv.FormatInt(ride.ID, 10) + ".gpx")
+		ctx.Set(fiber.HeaderContentType, mimeGPX)
+	case "geojson":
+		body, err = geo.MarshalLineString(track, dto.RideRouteProperties(ride))
+		ctx.Set(fiber.HeaderContentType, mimeGeoJSON)
+	default:
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "format gpx veya geojson olmalıdır")
+	}
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).Send(body)
+}
+
+// AddRidePhoto sürüş sonu park fotoğrafını kaydeder ve motorla bağlantıyı kapatır -> POST /rides/photo/:id (multipart: photo)
 func (h *RideHandler) AddRidePhoto(ctx *fiber.Ctx) error {
 	rideID, err := ctx.ParamsInt("id")
 	if err != nil {
 		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
 	}
 
-	photo, err := ctx.FormFile("photo")
+	file, err := ctx.FormFile("photo")
 	if err != nil {
 		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf yüklenemedi")
 	}
+	// Boyut servis tarafından da kontrol edilir; burada büyük dosyanın belleğe okunması önlenir
+	if file.Size > h.maxPhotoSize {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Fotoğraf en fazla %d MB olabilir", h.maxPhotoSize>>20))
+	}
+	data, err := readFormFile(file)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf okunamadı")
+	}
+
+	userID := ctx.Locals("userID").(int64)
+
+	photo, err := h.photoService.Upload(ctx.Context(), int64(rideID), userID, data)
+	if err != nil {
+		return err
+	}
 
-	// Dosya kaydedileceği yolu oluştur
-	dir := "uploads/rides"
-	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Yükleme klasörü oluşturulamadı")
+	// Motorun kilitlenip kilitlenmediğini kontrol et ve motorla bağlantıyı kapat
+	if err = h.rideService.HandleAfterPhotoUpload(ctx.Context(), int64(rideID), userID); err != nil {
+		return err
 	}
-	filePath := filepath.Join(dir, "ride_id_"+strconv.Itoa(rideID)+"_name_"+photo.Filename)
 
-	if err = ctx.SaveFile(photo, filePath); err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Fotoğraf kaydedilemedi")
+	return response.Success(ctx, h.photoResponse(*photo), "Fotoğraf yüklendi, motorla bağlantı kapatıldı.")
+}
+
+// ListPhotos sürüşün park fotoğrafları, bağlantılar süreli imzalıdır -> GET /rides/:id/photos
+func (h *RideHandler) ListPhotos(ctx *fiber.Ctx) error {
+	rideID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
 	}
 
-	// Motorun kilitlenip kilitlenmediğini kontrol et
-	if err = h.rideService.HandleAfterPhotoUpload(ctx.Context(), rideID); err != nil {
+	userID := ctx.Locals("userID").(int64)
+	role := ctx.Locals("role").(model.Role)
+
+	photos, err := h.photoService.List(ctx.Context(), int64(rideID), userID, role)
+	if err != nil {
 		return err
 	}
 
-	return response.Success(ctx, nil, "Fotoğraf yüklendi ve motor bağlantısı kesildi.")
+	resp := make([]dto.RidePhotoResponse, len(photos))
+	for i, photo := range photos {
+		resp[i] = h.photoResponse(photo)
+	}
+	return response.Success(ctx, resp)
+}
+
+func (h *RideHandler) photoResponse(photo model.RidePhoto) dto.RidePhotoResponse {
+	url, expiresAt := h.fileService.SignedURL(photo.Key)
+	thumbnailURL, _ := h.fileService.SignedURL(photo.ThumbnailKey)
+	return dto.RidePhotoResponse{}.ToResponseModel(photo, url, thumbnailURL, expiresAt)
 }
 
 func (h *RideHandler) ListByDateRange(ctx *fiber.Ctx) error {
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	service *service.RBACService
+}
+
+func NewRoleHandler(s *service.RBACService) *RoleHandler {
+	return &RoleHandler{service: s}
+}
+
+// ListPermissions yetki kataloğu -> GET /permissions
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.service.ListPermissions(c.Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		resp[i] = dto.PermissionResponse{}.ToResponseModel(permission)
+	}
+	return response.Success(c, resp)
+}
+
+// List -> GET /roles
+func (h *RoleHandler) List(c *fiber.Ctx) error {
+	roles, err := h.service.ListRoles(c.Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.RoleResponse, len(roles))
+	for i, role := range roles {
+		resp[i] = dto.RoleResponse{}.ToResponseModel(role)
+	}
+	return response.Success(c, resp)
+}
+
+// Get -> GET /roles/:name
+func (h *RoleHandler) Get(c *fiber.Ctx) error {
+	role, err := h.service.GetRole(c.Context(), model.Role(c.Params("name")))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.RoleResponse{}.ToResponseModel(*role))
+}
+
+// GetMyPermissions giriş yapan kullanıcının rolü ve yetkileri -> GET /users/me/permissions
+func (h *RoleHandler) GetMyPermissions(c *fiber.Ctx) error {
+	role, err := h.service.GetRole(c.Context(), c.Locals("role").(model.Role))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.RoleResponse{}.ToResponseModel(*role))
+}
+
+// Create -> POST /roles {name, description, permissions}
+func (h *RoleHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	role := req.ToDBModel(model.RoleDefinition{})
+	if err := h.service.CreateRole(c.Context(), &role); err != nil {
+		return err
+	}
+	return response.Success(c, dto.RoleResponse{}.ToResponseModel(role), "Rol oluşturuldu")
+}
+
+// Update -> PUT /roles/:name {description, permissions}
+func (h *RoleHandler) Update(c *fiber.Ctx) error {
+	var req dto.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	role := req.ToDBModel(model.RoleDefinition{Name: model.Role(c.Params("name"))})
+	if err := h.service.UpdateRole(c.Context(), &role); err != nil {
+		return err
+	}
+
+	updated, err := h.service.GetRole(c.Context(), role.Name)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.RoleResponse{}.ToResponseModel(*updated), "Rol güncellendi")
+}
+
+// Delete -> DELETE /roles/:name
+func (h *RoleHandler) Delete(c *fiber.Ctx) error {
+	if err := h.service.DeleteRole(c.Context(), model.Role(c.Params("name"))); err != nil {
+		return err
+	}
+	return response.Success(c, nil, "Rol silindi")
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TariffHandler struct {
+	service *service.TariffService
+}
+
+func NewTariffHandler(s *service.TariffService) *TariffHandler {
+	return &TariffHandler{service: s}
+}
+
+func (h *TariffHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateTariffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	tariff := req.ToDBModel(model.Tariff{})
+
+	if err := h.service.Create(c.Context(), &tariff); err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.TariffResponse{}.ToResponseModel(tariff), "Tarife başarıyla oluşturuldu")
+}
+
+func (h *TariffHandler) GetByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	resp, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.TariffResponse{}.ToResponseModel(*resp))
+}
+
+func (h *TariffHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdateTariffRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	currentTariff, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	tariff := req.ToDBModel(*currentTariff)
+
+	if err = h.service.Update(c.Context(), tariff); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Tarife başarıyla güncellendi")
+}
+
+func (h *TariffHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.service.Delete(c.Context(), int64(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Tarife başarıyla silindi")
+}
+
+func (h *TariffHandler) List(c *fiber.Ctx) error {
+	resp, err := h.service.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	tariffs := make([]dto.TariffResponse, len(resp))
+	for i, item := range resp {
+		tariffs[i] = dto.TariffResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, tariffs)
+}
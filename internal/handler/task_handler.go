@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaskHandler struct {
+	service      *service.TaskService
+	fileService  *service.FileService
+	maxPhotoSize int64
+}
+
+func NewTaskHandler(s *service.TaskService, fileService *service.FileService, maxPhotoSize int64) *TaskHandler {
+	return &TaskHandler{service: s, fileService: fileService, maxPhotoSize: maxPhotoSize}
+}
+
+// Varsayılan görev arama yarıçapı ve sonuç sayısı
+const (
+	defaultNearbyTaskRadiusMeters = 3000
+	defaultNearbyTaskLimit        = 20
+)
+
+// Nearby operatörün yakınındaki açık görevler -> GET /ops/tasks/nearby?lat=&lng=&radius_m=&limit=
+func (h *TaskHandler) Nearby(c *fiber.Ctx) error {
+	var req dto.NearbyTaskQuery
+	if err := c.QueryParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	radius := req.RadiusM
+	if radius == 0 {
+		radius = defaultNearbyTaskRadiusMeters
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultNearbyTaskLimit
+	}
+
+	operatorID := c.Locals("userID").(int64)
+	nearby, err := h.service.FindNearby(c.Context(), operatorID, geo.Point{Lat: *req.Lat, Lng: *req.Lng}, radius, limit)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.NearbyTaskResponse, len(nearby))
+	for i, item := range nearby {
+		resp[i] = dto.NearbyTaskResponse{}.ToResponseModel(item.Task, item.DistanceMeters)
+	}
+	return response.Success(c, resp)
+}
+
+// ListMyTasks operatöre atanmış ve operatörün üstlendiği görevler -> GET /ops/tasks/me
+func (h *TaskHandler) ListMyTasks(c *fiber.Ctx) error {
+	tasks, err := h.service.ListByOperator(c.Context(), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.TaskResponse, len(tasks))
+	for i, item := range tasks {
+		resp[i] = dto.TaskResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// Get görevi tamamlanma fotoğraflarıyla döner -> GET /ops/tasks/:id
+func (h *TaskHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	task, err := h.service.Get(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, h.taskResponse(*task))
+}
+
+// Claim -> POST /ops/tasks/:id/claim
+func (h *TaskHandler) Claim(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	task, err := h.service.Claim(c.Context(), int64(id), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev üstlenildi")
+}
+
+// Release -> POST /ops/tasks/:id/release
+func (h *TaskHandler) Release(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	task, err := h.service.Release(c.Context(), int64(id), c.Locals("userID").(int64))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev bırakıldı")
+}
+
+// Complete görevi fotoğraflı kanıtla tamamlar -> POST /ops/tasks/:id/complete (multipart: note, photos)
+func (h *TaskHandler) Complete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.CompleteTaskRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var photos [][]byte
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraflar yüklenemedi")
+		}
+		files := form.File["photos"]
+		photos = make([][]byte, len(files))
+		for i, file := range files {
+			// Boyut servis tarafından da kontrol edilir; burada büyük dosyaların belleğe okunması önlenir
+			if file.Size > h.maxPhotoSize {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, h.maxPhotoSize>>20))
+			}
+			if photos[i], err = readFormFile(file); err != nil {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf okunamadı")
+			}
+		}
+	}
+
+	task, err := h.service.Complete(c.Context(), service.CompleteTaskInput{
+		ID:         int64(id),
+		OperatorID: c.Locals("userID").(int64),
+		Note:       req.Note,
+		Photos:     photos,
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, h.taskResponse(*task), "Görev tamamlandı")
+}
+
+// Create -> POST /ops/tasks {type, motorbike_id, target_zone_id, note, assignee_id, due_at}
+func (h *TaskHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateTaskRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	createdBy := c.Locals("userID").(int64)
+	task, err := h.service.Create(c.Context(), service.CreateTaskInput{
+		Type:         model.TaskType(req.Type),
+		MotorbikeID:  req.MotorbikeID,
+		TargetZoneID: req.TargetZoneID,
+		Note:         req.Note,
+		AssigneeID:   req.AssigneeID,
+		DueAt:        req.DueAt,
+		CreatedBy:    &createdBy,
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev oluşturuldu")
+}
+
+// List -> GET /ops/tasks?status=&type=&motorbike_id=&assignee_id=&overdue=true&page=1&page_size=10
+func (h *TaskHandler) List(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	filter := model.TaskFilter{
+		Status:      model.TaskStatus(c.Query("status")),
+		Type:        model.TaskType(c.Query("type")),
+		MotorbikeID: int64(c.QueryInt("motorbike_id")),
+		AssigneeID:  int64(c.QueryInt("assignee_id")),
+		Overdue:     c.QueryBool("overdue"),
+	}
+
+	tasks, err := h.service.List(c.Context(), filter, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.TaskResponse, len(tasks))
+	for i, item := range tasks {
+		resp[i] = dto.TaskResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, dto.TaskListResponse{
+		Tasks:      resp,
+		Pagination: query.GetPaginationResponse(params.Pagination),
+	})
+}
+
+// Update -> PUT /ops/tasks/:id {target_zone_id, note, due_at}
+func (h *TaskHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdateTaskRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	input := service.UpdateTaskInput{ID: int64(id), TargetZoneID: req.TargetZoneID, Note: req.Note}
+	if req.DueAt != nil {
+		input.DueAt = *req.DueAt
+	}
+	task, err := h.service.Update(c.Context(), input)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev güncellendi")
+}
+
+// Assign -> POST /ops/tasks/:id/assign {operator_id}
+func (h *TaskHandler) Assign(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.AssignTaskRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	task, err := h.service.Assign(c.Context(), int64(id), req.OperatorID)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev atandı")
+}
+
+// Cancel -> POST /ops/tasks/:id/cancel {note}
+func (h *TaskHandler) Cancel(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.TaskNoteRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	task, err := h.service.Cancel(c.Context(), int64(id), c.Locals("userID").(int64), req.Note)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, dto.TaskResponse{}.ToResponseModel(*task), "Görev iptal edildi")
+}
+
+func (h *TaskHandler) taskResponse(task model.Task) dto.TaskResponse {
+	resp := dto.TaskResponse{}.ToResponseModel(task)
+	for _, photo := range task.Photos {
+		url, expiresAt := h.fileService.SignedURL(photo.Key)
+		thumbnailURL, _ := h.fileService.SignedURL(photo.ThumbnailKey)
+		resp.Photos = append(resp.Photos, dto.TaskPhotoResponse{}.ToResponseModel(photo, url, thumbnailURL, expiresAt))
+	}
+	return resp
+}
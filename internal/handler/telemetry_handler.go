@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TelemetryHandler struct {
+	service          *service.TelemetryService
+	motorbikeService *service.MotorbikeService
+}
+
+func NewTelemetryHandler(s *service.TelemetryService, m *service.MotorbikeService) *TelemetryHandler {
+	return &TelemetryHandler{service: s, motorbikeService: m}
+}
+
+// Ingest cihazdan gelen ölçüm paketini kaydeder, motor DeviceAuthMiddleware ile belirlenir
+func (h *TelemetryHandler) Ingest(c *fiber.Ctx) error {
+	var req dto.TelemetryBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	motorbikeID := c.Locals("motorbikeID").(int64)
+
+	if err := h.service.Ingest(c.Context(), motorbikeID, req.ToDBModels()); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Telemetri kaydedildi")
+}
+
+// ListByMotorbike motorun ölçümlerini getirir -> /motorbike/:id/telemetry?from=2024-09-04T10:00:00Z&to=2024-09-04T12:00:00Z
+// from/to verilmezse son 1 saat getirilir
+func (h *TelemetryHandler) ListByMotorbike(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "to formatı geçersiz. Beklenen format: RFC3339")
+		}
+	}
+	from := to.Add(-time.Hour)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "from formatı geçersiz. Beklenen format: RFC3339")
+		}
+	}
+
+	samples, err := h.service.List(c.Context(), int64(id), from, to)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]dto.TelemetryResponse, len(samples))
+	for i, item := range samples {
+		resp[i] = dto.TelemetryResponse{}.ToResponseModel(item)
+	}
+	return response.Success(c, resp)
+}
+
+// RotateDeviceKey motor için yeni cihaz anahtarı üretir, anahtar yalnızca bu yanıtta gösterilir
+func (h *TelemetryHandler) RotateDeviceKey(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	key, err := h.motorbikeService.RotateDeviceKey(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.DeviceKeyResponse{MotorbikeID: int64(id), DeviceKey: key}, "Cihaz anahtarı oluşturuldu. Bu anahtar tekrar gösterilmeyecek")
+}
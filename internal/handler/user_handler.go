@@ -14,10 +14,11 @@ import (
 
 type UserHandler struct {
 	service *service.UserService
+	rbac    *service.RBACService
 }
 
-func NewUserHandler(s *service.UserService) *UserHandler {
-	return &UserHandler{service: s}
+func NewUserHandler(s *service.UserService, rbac *service.RBACService) *UserHandler {
+	return &UserHandler{service: s, rbac: rbac}
 }
 
 func (h *UserHandler) Create(c *fiber.Ctx) error {
@@ -27,6 +28,15 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 	}
 
 	user := req.ToDBModel(model.User{})
+	if err := h.rbac.ValidateRole(c.Context(), user.Role); err != nil {
+		return err
+	}
+	// Kullanıcı dışında bir rol vermek rol yönetimi yetkisi gerektirir
+	if user.Role != model.UserRole {
+		if err := h.rbac.Authorize(c.Context(), c.Locals("role").(model.Role), model.PermRolesManage); err != nil {
+			return err
+		}
+	}
 	if user.Password == "" { // when admin create a new user, password is empty. so we set default password
 		// maybe we can use a link to send a mail to the user to set a password
 		// todo: send email to user to set a password
@@ -90,6 +100,26 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 	}
 
 	user := req.ToDBModel(model.User{})
+	// Gönderilmeyen rol ve durum değişmez
+	if req.Role == "" {
+		user.Role = currentUser.Role
+	}
+	if req.Status == "" {
+		user.Status = currentUser.Status
+	}
+	if user.Role != currentUser.Role {
+		if err = h.rbac.ValidateRole(c.Context(), user.Role); err != nil {
+			return err
+		}
+		if err = h.rbac.Authorize(c.Context(), c.Locals("role").(model.Role), model.PermRolesManage); err != nil {
+			return err
+		}
+	}
+	if user.Status != currentUser.Status {
+		if err = h.rbac.Authorize(c.Context(), c.Locals("role").(model.Role), model.PermUsersBan); err != nil {
+			return err
+		}
+	}
 	user.ID = id
 	// Eğer şifre değiştirilmek isteniyorsa
 	if req.NewPassword != "" {
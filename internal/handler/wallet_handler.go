@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WalletHandler struct {
+	service *service.WalletService
+}
+
+func NewWalletHandler(s *service.WalletService) *WalletHandler {
+	return &WalletHandler{service: s}
+}
+
+// GetMyWallet giriş yapmış kullanıcının bakiyesi -> GET /users/me/wallet
+func (h *WalletHandler) GetMyWallet(c *fiber.Ctx) error {
+	return h.getWallet(c, c.Locals("userID").(int64))
+}
+
+// ListMyTransactions giriş yapmış kullanıcının cüzdan hareketleri -> GET /users/me/wallet/transactions?page=1&page_size=10
+func (h *WalletHandler) ListMyTransactions(c *fiber.Ctx) error {
+	return h.listTransactions(c, c.Locals("userID").(int64))
+}
+
+func (h *WalletHandler) GetUserWallet(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	return h.getWallet(c, int64(id))
+}
+
+func (h *WalletHandler) ListUserTransactions(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+	return h.listTransactions(c, int64(id))
+}
+
+// Adjust admin'in kullanıcının cüzdanına hareket yazması -> POST /users/:id/wallet/adjustments
+func (h *WalletHandler) Adjust(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.WalletAdjustmentRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	adminID := c.Locals("userID").(int64)
+
+	transaction, err := h.service.Adjust(c.Context(), service.WalletPosting{
+		UserID:      int64(id),
+		Kind:        model.LedgerTransactionKind(req.Kind),
+		Amount:      req.Amount,
+		Description: req.Description,
+		RideID:      req.RideID,
+		CreatedBy:   &adminID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.LedgerTransactionResponse{}.ToResponseModel(*transaction), "Cüzdan hareketi kaydedildi")
+}
+
+func (h *WalletHandler) getWallet(c *fiber.Ctx, userID int64) error {
+	wallet, err := h.service.GetWallet(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.WalletResponse{}.ToResponseModel(*wallet))
+}
+
+func (h *WalletHandler) listTransactions(c *fiber.Ctx, userID int64) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	resp, err := h.service.ListTransactions(c.Context(), userID, &params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	transactions := make([]dto.WalletTransactionResponse, len(resp))
+	for i, item := range resp {
+		transactions[i] = dto.WalletTransactionResponse{}.ToResponseModel(item)
+	}
+
+	return response.Success(c, dto.WalletTransactionListResponse{
+		Transactions: transactions,
+		Pagination:   query.GetPaginationResponse(params.Pagination),
+	})
+}
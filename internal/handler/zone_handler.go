@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ZoneHandler struct {
+	service *service.ZoneService
+}
+
+func NewZoneHandler(s *service.ZoneService) *ZoneHandler {
+	return &ZoneHandler{service: s}
+}
+
+func (h *ZoneHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateZoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	zone := req.ToDBModel(model.Zone{})
+
+	if err := h.service.Create(c.Context(), &zone); err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.ZoneResponse{}.ToResponseModel(zone), "Alan başarıyla oluşturuldu")
+}
+
+func (h *ZoneHandler) GetByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	resp, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.ZoneResponse{}.ToResponseModel(*resp))
+}
+
+func (h *ZoneHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	var req dto.UpdateZoneRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = validate.Struct(req); err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	currentZone, err := h.service.GetByID(c.Context(), int64(id))
+	if err != nil {
+		return err
+	}
+
+	zone := req.ToDBModel(*currentZone)
+
+	if err = h.service.Update(c.Context(), zone); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Alan başarıyla güncellendi")
+}
+
+func (h *ZoneHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInvalidRequest, err)
+	}
+
+	if err = h.service.Delete(c.Context(), int64(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Alan başarıyla silindi")
+}
+
+func (h *ZoneHandler) List(c *fiber.Ctx) error {
+	resp, err := h.service.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, toZoneResponses(resp))
+}
+
+// ListActive kullanıcı haritası için aktif alanları döner
+func (h *ZoneHandler) ListActive(c *fiber.Ctx) error {
+	resp, err := h.service.ListActive(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, toZoneResponses(resp))
+}
+
+// CheckParking verilen konumda sürüş bitirilebilir mi kontrol eder -> /zones/parking-check?lat=41.01&lng=28.97
+func (h *ZoneHandler) CheckParking(c *fiber.Ctx) error {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "lat ve lng parametreleri zorunludur")
+	}
+	point := geo.Point{Lat: lat, Lng: lng}
+
+	decision, err := h.service.CheckParking(c.Context(), point)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, dto.ParkingResponse{}.ToResponseModel(decision.Allowed, decision.Reason, decision.PreferredZone))
+}
+
+func toZoneResponses(zones []model.Zone) []dto.ZoneResponse {
+	resp := make([]dto.ZoneResponse, len(zones))
+	for i, item := range zones {
+		resp[i] = dto.ZoneResponse{}.ToResponseModel(item)
+	}
+	return resp
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditAPIPrefix hedef türü çıkarılırken route'tan atılan ön ek
+const auditAPIPrefix = "/api/v1/"
+
+// AuditRecorder denetim kaydını zincire ekler
+type AuditRecorder interface {
+	Record(ctx context.Context, entry model.AuditLog) error
+}
+
+// AuditMiddleware veri değiştiren (POST, PUT, PATCH, DELETE) her isteği, sonucu ile birlikte denetim kaydına yazar.
+// IP ve user agent context'e eklenir; böylece servislerin aynı istek içinde yazdığı değişiklik kayıtları da
+// bu bilgileri ve request id'yi taşır. Cihazlardan gelen telemetri ve komut onayları kaydedilmez.
+func AuditMiddleware(recorder AuditRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("client_ip", c.IP())
+		c.Locals("user_agent", c.Get(fiber.HeaderUserAgent))
+
+		err := c.Next()
+
+		if !isMutatingMethod(c.Method()) || c.Locals("motorbikeID") != nil {
+			return err
+		}
+
+		entry := model.AuditLog{
+			Action:     c.Method() + " " + c.Route().Path,
+			Method:     c.Method(),
+			Path:       c.Path(),
+			StatusCode: c.Response().StatusCode(),
+		}
+		if err != nil {
+			entry.StatusCode = errorStatusCode(err)
+		}
+		entry.TargetType, entry.TargetID = auditTarget(c)
+
+		if recordErr := recorder.Record(c.Context(), entry); recordErr != nil {
+			logger.Error("İstek denetim kaydı yazılamadı (%s %s): %v", c.Method(), c.Path(), recordErr)
+		}
+		return err
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// errorStatusCode handler'ın döndüğü hatanın istemciye yansıyacak durum kodunu bulur
+func errorStatusCode(err error) int {
+	var appErr *errorx.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// auditTarget route'taki ilk parametreyi hedef kabul eder; hedef türü parametreden önceki yoldur.
+// Örn. "/api/v1/users/:id/wallet/adjustments" için ("users", id), "/api/v1/ops/tasks/:id/claim" için ("ops/tasks", id).
+func auditTarget(c *fiber.Ctx) (string, string) {
+	route := c.Route()
+	if len(route.Params) == 0 {
+		return "", ""
+	}
+
+	var resource []string
+	for _, segment := range strings.Split(strings.TrimPrefix(route.Path, auditAPIPrefix), "/") {
+		if strings.HasPrefix(segment, ":") || segment == "*" {
+			break
+		}
+		if segment != "" {
+			resource = append(resource, segment)
+		}
+	}
+	return strings.Join(resource, "/"), c.Params(route.Params[0])
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/jwt"
@@ -38,15 +40,22 @@ func AuthMiddleware() fiber.Handler {
 	}
 }
 
-func AdminOnly() fiber.Handler {
+// PermissionAuthorizer rolün yetkiye sahip olmadığı durumda hata döner
+type PermissionAuthorizer interface {
+	Authorize(ctx context.Context, role model.Role, permission model.Permission) error
+}
+
+// RequirePermission isteği yapan kullanıcının rolü verilen yetkiye sahip değilse isteği reddeder.
+// AuthMiddleware'den sonra kullanılmalıdır.
+func RequirePermission(authorizer PermissionAuthorizer, permission model.Permission) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals("role")
 		if role == nil {
 			return errorx.WrapMsg(errorx.ErrUnauthorized, "Yetkilendirme bilgisi bulunamadı")
 		}
 
-		if role.(model.Role) != model.AdminRole {
-			return errorx.WrapMsg(errorx.ErrForbidden, "Bu işlem için admin yetkisi gerekli")
+		if err := authorizer.Authorize(c.Context(), role.(model.Role), permission); err != nil {
+			return err
 		}
 
 		return c.Next()
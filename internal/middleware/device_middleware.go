@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeviceKeyHeader motor üzerindeki cihazın kimlik doğrulamada kullandığı header
+const DeviceKeyHeader = "X-Device-Key"
+
+// DeviceAuthenticator cihaz anahtarını motora çözümler
+type DeviceAuthenticator interface {
+	AuthenticateDevice(ctx context.Context, key string) (*model.Motorbike, error)
+}
+
+// DeviceAuthMiddleware cihazdan gelen istekleri motor bazında doğrular ve motorbikeID'yi context'e ekler
+func DeviceAuthMiddleware(devices DeviceAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		motorbike, err := devices.AuthenticateDevice(c.Context(), c.Get(DeviceKeyHeader))
+		if err != nil {
+			return err
+		}
+
+		c.Locals("motorbikeID", motorbike.ID)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AuditLog yönetim işlemlerinin ve durum değişikliklerinin değiştirilemez kaydıdır. Her kayıt bir öncekinin
+// özetini içerir (hash zinciri); aradaki bir kaydın değiştirilmesi veya silinmesi zincirin doğrulanmasıyla fark edilir.
+type AuditLog struct {
+	bun.BaseModel `bun:"table:audit_logs,alias:al"`
+
+	ID         int64           `bun:"id,pk,autoincrement"`
+	CreatedAt  time.Time       `bun:"created_at,notnull"`
+	ActorID    *int64          `bun:"actor_id"`
+	ActorRole  Role            `bun:"actor_role,nullzero"`
+	Action     string          `bun:"action,notnull"`       // ör. "users.update" veya HTTP istekleri için "PUT /api/v1/users/:id"
+	TargetType string          `bun:"target_type,nullzero"` // route'taki kaynak adı, ör. "users", "rides"
+	TargetID   string          `bun:"target_id,nullzero"`
+	Before     json.RawMessage `bun:"before,type:jsonb,nullzero"` // güncellemede yalnızca değişen alanlar, silmede kaydın tamamı
+	After      json.RawMessage `bun:"after,type:jsonb,nullzero"`  // güncellemede yalnızca değişen alanlar, oluşturmada kaydın tamamı
+	Method     string          `bun:"method,nullzero"`
+	Path       string          `bun:"path,nullzero"`
+	StatusCode int             `bun:"status_code,nullzero"`
+	IP         string          `bun:"ip,nullzero"`
+	UserAgent  string          `bun:"user_agent,nullzero"`
+	RequestID  string          `bun:"request_id,nullzero"`
+	PrevHash   string          `bun:"prev_hash,nullzero"`
+	Hash       string          `bun:"hash,notnull"`
+}
+
+// AuditLogFilter boş alanlar filtrelenmez
+type AuditLogFilter struct {
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+}
+
+// AuditVerification hash zincirinin doğrulama sonucu
+type AuditVerification struct {
+	Checked    int
+	Valid      bool
+	BrokenAtID int64  // zincirin kırıldığı ilk kayıt
+	Reason     string // zincir kırıksa nedeni
+}
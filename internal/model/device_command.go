@@ -0,0 +1,71 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type DeviceCommandType string
+
+const (
+	CommandLock    DeviceCommandType = "lock"
+	CommandUnlock  DeviceCommandType = "unlock"
+	CommandBeep    DeviceCommandType = "beep"
+	CommandDisable DeviceCommandType = "disable" // motoru uzaktan devre dışı bırakır (çalıntı, arıza vb.)
+)
+
+type DeviceCommandStatus string
+
+const (
+	CommandPending      DeviceCommandStatus = "pending"      // cihaza gönderilmeyi bekliyor
+	CommandSent         DeviceCommandStatus = "sent"         // cihaza iletildi, onay bekleniyor
+	CommandAcknowledged DeviceCommandStatus = "acknowledged" // cihaz komutu uyguladığını bildirdi
+	CommandFailed       DeviceCommandStatus = "failed"       // deneme hakkı bitti
+	CommandExpired      DeviceCommandStatus = "expired"      // geçerlilik süresi doldu
+)
+
+// DeviceCommand motor cihazına gönderilen uzaktan komut. Cihaz komutları sorgulayarak alır,
+// uyguladıktan sonra onay gönderir. Onay süresi içinde gelmeyen komutlar deneme hakkı bitene kadar tekrar gönderilir.
+type DeviceCommand struct {
+	BaseModel `bun:"table:device_commands,alias:dc"`
+
+	MotorbikeID int64               `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	Type        DeviceCommandType   `json:"type" bun:"type,notnull"`
+	Status      DeviceCommandStatus `json:"status" bun:"status,notnull"`
+	Attempts    int                 `json:"attempts" bun:"attempts,notnull"`
+	MaxAttempts int                 `json:"max_attempts" bun:"max_attempts,notnull"`
+	ExpiresAt   time.Time           `json:"expires_at" bun:"expires_at,notnull"`
+	SentAt      *time.Time          `json:"sent_at" bun:"sent_at"`
+	AckDeadline *time.Time          `json:"ack_deadline" bun:"ack_deadline"` // son gönderimin onay için son anı
+	CompletedAt *time.Time          `json:"completed_at" bun:"completed_at"`
+	LastError   string              `json:"last_error,omitempty" bun:"last_error,nullzero"`
+	RequestedBy *int64              `json:"requested_by" bun:"requested_by"` // komutu oluşturan kullanıcı, sistem komutlarında boş
+	RideID      *int64              `json:"ride_id" bun:"ride_id"`
+
+	Events []DeviceCommandEvent `json:"events,omitempty" bun:"rel:has-many,join:id=command_id"`
+}
+
+// DeviceCommandEvent komutun durum geçmişi
+type DeviceCommandEvent struct {
+	bun.BaseModel `bun:"table:device_command_events,alias:dce"`
+
+	ID        int64               `json:"id" bun:",pk,autoincrement"`
+	CreatedAt time.Time           `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	CommandID int64               `json:"command_id" bun:"command_id,notnull"`
+	Status    DeviceCommandStatus `json:"status" bun:"status,notnull"`
+	Note      string              `json:"note,omitempty" bun:"note,nullzero"`
+}
+
+// IsFinal komutun son durumuna ulaşıp ulaşmadığını döner
+func (c DeviceCommand) IsFinal() bool {
+	return c.Status == CommandAcknowledged || c.Status == CommandFailed || c.Status == CommandExpired
+}
+
+func (t DeviceCommandType) IsValid() bool {
+	switch t {
+	case CommandLock, CommandUnlock, CommandBeep, CommandDisable:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type DisputeReason string
+
+const (
+	DisputeOvercharged  DisputeReason = "overcharged"    // ücret beklenenden yüksek
+	DisputeBikeIssue    DisputeReason = "bike_issue"     // motor arızası nedeniyle sürüş yarım kaldı veya uzadı
+	DisputeEndRideIssue DisputeReason = "end_ride_issue" // sürüş uygulamadan bitirilemedi
+	DisputeUnauthorized DisputeReason = "unauthorized"   // sürüşü kullanıcı başlatmadı
+	DisputeOther        DisputeReason = "other"
+)
+
+func (r DisputeReason) IsValid() bool {
+	switch r {
+	case DisputeOvercharged, DisputeBikeIssue, DisputeEndRideIssue, DisputeUnauthorized, DisputeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+type DisputeStatus string
+
+const (
+	DisputeOpen      DisputeStatus = "open"      // kullanıcı açtı, destek ekibi henüz ilgilenmedi
+	DisputeInReview  DisputeStatus = "in_review" // bir admin inceliyor
+	DisputeApproved  DisputeStatus = "approved"  // iade yapıldı
+	DisputeRejected  DisputeStatus = "rejected"
+	DisputeWithdrawn DisputeStatus = "withdrawn" // kullanıcı geri çekti
+)
+
+// disputeTransitions itirazın geçebileceği durumlar. Onaylanan, reddedilen veya geri çekilen itiraz kapanır.
+var disputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeOpen:     {DisputeInReview, DisputeApproved, DisputeRejected, DisputeWithdrawn},
+	DisputeInReview: {DisputeApproved, DisputeRejected, DisputeWithdrawn},
+}
+
+// CanTransitionTo itirazın next durumuna geçip geçemeyeceğini döner
+func (s DisputeStatus) CanTransitionTo(next DisputeStatus) bool {
+	for _, allowed := range disputeTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed itirazın sonuçlanıp sonuçlanmadığını döner
+func (s DisputeStatus) IsClosed() bool {
+	return len(disputeTransitions[s]) == 0
+}
+
+func (s DisputeStatus) IsValid() bool {
+	switch s {
+	case DisputeOpen, DisputeInReview, DisputeApproved, DisputeRejected, DisputeWithdrawn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dispute kullanıcının tamamlanmış bir sürüşün ücretine itirazı. Bir sürüş için aynı anda tek açık itiraz olabilir.
+// Tutarlar kuruş cinsindendir.
+type Dispute struct {
+	BaseModel `bun:"table:disputes,alias:d"`
+
+	RideID          int64         `json:"ride_id" bun:"ride_id,notnull"`
+	UserID          int64         `json:"user_id" bun:"user_id,notnull"`
+	Reason          DisputeReason `json:"reason" bun:"reason,notnull"`
+	Description     string        `json:"description" bun:"description,notnull"`
+	RequestedAmount int64         `json:"requested_amount" bun:"requested_amount,notnull"` // kullanıcının talep ettiği iade, 0 ise belirtilmedi
+	Status          DisputeStatus `json:"status" bun:"status,notnull"`
+	ReviewerID      *int64        `json:"reviewer_id" bun:"reviewer_id"` // itirazı inceleyen veya sonuçlandıran admin
+	ResolutionNote  string        `json:"resolution_note,omitempty" bun:"resolution_note,nullzero"`
+	ResolvedAt      *time.Time    `json:"resolved_at" bun:"resolved_at"`
+
+	// Onaylanan iade ve nereye yapıldığı
+	RefundAmount      int64             `json:"refund_amount" bun:"refund_amount,notnull"`
+	RefundDestination RefundDestination `json:"refund_destination,omitempty" bun:"refund_destination,nullzero"`
+
+	Photos []DisputePhoto `json:"photos,omitempty" bun:"rel:has-many,join:id=dispute_id"`
+	Events []DisputeEvent `json:"events,omitempty" bun:"rel:has-many,join:id=dispute_id"`
+}
+
+// DisputeFilter destek kuyruğunun filtreleri; boş alanlar filtrelenmez
+type DisputeFilter struct {
+	Status DisputeStatus
+	Reason DisputeReason
+	UserID int64
+	RideID int64
+}
+
+// DisputePhoto itiraza eklenen fotoğraf. Dosya diskte saklanır, kayıt yalnızca yolu tutar.
+type DisputePhoto struct {
+	bun.BaseModel `bun:"table:dispute_photos,alias:dp"`
+
+	ID          int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt   time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	DisputeID   int64     `json:"dispute_id" bun:"dispute_id,notnull"`
+	Path        string    `json:"-" bun:"path,notnull"`
+	ContentType string    `json:"content_type" bun:"content_type,notnull"`
+	Size        int64     `json:"size" bun:"size,notnull"`
+}
+
+// DisputeEvent itirazın durum geçmişi. İlk kayıt itirazın açılmasıdır (FromStatus boş).
+type DisputeEvent struct {
+	bun.BaseModel `bun:"table:dispute_events,alias:de"`
+
+	ID         int64         `json:"id" bun:",pk,autoincrement"`
+	CreatedAt  time.Time     `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	DisputeID  int64         `json:"dispute_id" bun:"dispute_id,notnull"`
+	FromStatus DisputeStatus `json:"from_status,omitempty" bun:"from_status,nullzero"`
+	ToStatus   DisputeStatus `json:"to_status" bun:"to_status,notnull"`
+	ActorID    int64         `json:"actor_id" bun:"actor_id,notnull"` // durumu değiştiren kullanıcı veya admin
+	Note       string        `json:"note,omitempty" bun:"note,nullzero"`
+}
+
+type RefundDestination string
+
+const (
+	RefundToWallet RefundDestination = "wallet" // iade cüzdana bakiye olarak yüklenir
+	RefundToCard   RefundDestination = "card"   // sürüşte karttan tahsil edilen tutar karta iade edilir
+)
+
+// ProviderRefundStatus karta yapılan iadenin sağlayıcıdaki durumu. İade kaydı pending olarak yazılır, sağlayıcı çağrısından
+// sonra succeeded veya failed olur; pending veya failed kalan kayıtlar sağlayıcıyla mutabakat gerektirir.
+type ProviderRefundStatus string
+
+const (
+	ProviderRefundPending   ProviderRefundStatus = "pending"
+	ProviderRefundSucceeded ProviderRefundStatus = "succeeded"
+	ProviderRefundFailed    ProviderRefundStatus = "failed"
+)
+
+// RideAdjustment sürüş ücretinde yapılan değişikliğin değiştirilemez kaydı. Her kayıt deftere yazılan işlemi ve
+// varsa ödeme sağlayıcısındaki iadeyi, işlemi yapan admin ile birlikte tutar. Amount iade edilen tutardır. Kayıttan
+// yalnızca bekleyen karta iadenin sonucu güncellenebilir.
+type RideAdjustment struct {
+	bun.BaseModel `bun:"table:ride_adjustments,alias:ra"`
+
+	ID                   int64                `json:"id" bun:",pk,autoincrement"`
+	CreatedAt            time.Time            `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	RideID               int64                `json:"ride_id" bun:"ride_id,notnull"`
+	UserID               int64                `json:"user_id" bun:"user_id,notnull"`
+	DisputeID            *int64               `json:"dispute_id" bun:"dispute_id"`
+	Amount               int64                `json:"amount" bun:"amount,notnull"`
+	Currency             string               `json:"currency" bun:"currency,notnull"`
+	Destination          RefundDestination    `json:"destination" bun:"destination,notnull"`
+	LedgerTransactionID  int64                `json:"ledger_transaction_id" bun:"ledger_transaction_id,notnull"`
+	ProviderRefundID     string               `json:"provider_refund_id,omitempty" bun:"provider_refund_id,nullzero"`
+	ProviderRefundStatus ProviderRefundStatus `json:"provider_refund_status,omitempty" bun:"provider_refund_status,nullzero"` // yalnızca karta iadelerde
+	Reason               string               `json:"reason" bun:"reason,notnull"`
+	CreatedBy            int64                `json:"created_by" bun:"created_by,notnull"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type InvoiceKind string
+
+const (
+	InvoiceReceipt InvoiceKind = "receipt" // tamamlanan her sürüş için kesilen fiş
+	InvoiceMonthly InvoiceKind = "monthly" // kullanıcının bir aydaki sürüşlerini toplayan fatura
+)
+
+// NumberPrefix belge numarasının ön eki, örn: FIS-2026-000042
+func (k InvoiceKind) NumberPrefix() string {
+	if k == InvoiceMonthly {
+		return "FTR"
+	}
+	return "FIS"
+}
+
+// Invoice kullanıcıya kesilen fiş veya aylık fatura. Tutarlar KDV dahil fiyatlardan ayrıştırılır ve kuruş cinsindendir.
+// Üretilen HTML ve PDF belge kesildiği haliyle saklanır; sonradan tarife veya şirket bilgisi değişse de aynı belge sunulur.
+type Invoice struct {
+	bun.BaseModel `bun:"table:invoices,alias:inv"`
+
+	ID          int64       `json:"id" bun:",pk,autoincrement"`
+	CreatedAt   time.Time   `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	Number      string      `json:"number" bun:"number,notnull,unique"`
+	Kind        InvoiceKind `json:"kind" bun:"kind,notnull"`
+	UserID      int64       `json:"user_id" bun:"user_id,notnull"`
+	RideID      *int64      `json:"ride_id,omitempty" bun:"ride_id"`
+	PeriodStart *time.Time  `json:"period_start,omitempty" bun:"period_start"` // aylık faturanın kapsadığı ay
+	PeriodEnd   *time.Time  `json:"period_end,omitempty" bun:"period_end"`
+	NetAmount   int64       `json:"net_amount" bun:"net_amount,notnull"`
+	VATRatePct  int         `json:"vat_rate_pct" bun:"vat_rate_pct,notnull"`
+	VATAmount   int64       `json:"vat_amount" bun:"vat_amount,notnull"`
+	Total       int64       `json:"total" bun:"total,notnull"`
+	Currency    string      `json:"currency" bun:"currency,notnull"`
+	HTML        string      `json:"-" bun:"html,notnull"`
+	PDF         []byte      `json:"-" bun:"pdf,type:bytea,notnull"`
+
+	// E-posta gönderimi başarısız olursa worker tarafından tekrar denenir
+	EmailedAt     *time.Time `json:"emailed_at" bun:"emailed_at"`
+	EmailAttempts int        `json:"-" bun:"email_attempts,notnull"`
+	EmailError    string     `json:"-" bun:"email_error,nullzero"`
+}
+
+// Filename belgenin indirilirken kullanılacak dosya adı
+func (i *Invoice) Filename(ext string) string {
+	return i.Number + "." + ext
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type IssueCategory string
+
+const (
+	IssueBrakes     IssueCategory = "brakes"
+	IssueTyre       IssueCategory = "tyre"
+	IssueBattery    IssueCategory = "battery"
+	IssueBodywork   IssueCategory = "bodywork" // kaza, çizik, kırık parça
+	IssueElectrical IssueCategory = "electrical"
+	IssueOther      IssueCategory = "other"
+)
+
+func (c IssueCategory) IsValid() bool {
+	switch c {
+	case IssueBrakes, IssueTyre, IssueBattery, IssueBodywork, IssueElectrical, IssueOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaintenanceType bildirim motoru bakıma aldığında açılacak iş emrinin türünü döner
+func (c IssueCategory) MaintenanceType() MaintenanceType {
+	switch c {
+	case IssueBrakes:
+		return MaintenanceBrakes
+	case IssueTyre:
+		return MaintenanceTyre
+	case IssueBattery:
+		return MaintenanceBattery
+	case IssueBodywork:
+		return MaintenanceBodywork
+	default:
+		return MaintenanceInspection
+	}
+}
+
+type IssueSeverity string
+
+const (
+	SeverityLow      IssueSeverity = "low"
+	SeverityMedium   IssueSeverity = "medium"
+	SeverityHigh     IssueSeverity = "high"
+	SeverityCritical IssueSeverity = "critical" // motor güvenli değil, tek bildirimle bakıma alınır
+)
+
+func (s IssueSeverity) IsValid() bool {
+	switch s {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkOrderPriority bildirimden açılan iş emrinin önceliğini döner
+func (s IssueSeverity) WorkOrderPriority() WorkOrderPriority {
+	switch s {
+	case SeverityCritical:
+		return PriorityUrgent
+	case SeverityHigh:
+		return PriorityHigh
+	default:
+		return PriorityNormal
+	}
+}
+
+type IssueStatus string
+
+const (
+	IssueOpen      IssueStatus = "open"      // kullanıcı bildirdi, henüz sonuçlanmadı
+	IssueResolved  IssueStatus = "resolved"  // arıza giderildi veya incelendi, kullanıcıya bildirildi
+	IssueDuplicate IssueStatus = "duplicate" // aynı arızanın başka bir bildirimiyle birleştirildi
+)
+
+// IsClosed bildirimin sonuçlanıp sonuçlanmadığını döner
+func (s IssueStatus) IsClosed() bool {
+	return s != IssueOpen
+}
+
+func (s IssueStatus) IsValid() bool {
+	switch s {
+	case IssueOpen, IssueResolved, IssueDuplicate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Issue kullanıcının motorda gördüğü arıza veya hasar bildirimi. Kritik bir bildirim ya da kısa sürede farklı
+// kullanıcılardan gelen birden fazla bildirim motoru bakıma alır; açılan veya katılınan iş emri WorkOrderID'de tutulur.
+type Issue struct {
+	BaseModel `bun:"table:issues,alias:i"`
+
+	MotorbikeID    int64         `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	UserID         int64         `json:"user_id" bun:"user_id,notnull"`
+	RideID         *int64        `json:"ride_id" bun:"ride_id"` // bildirimin ilgili olduğu sürüş
+	Category       IssueCategory `json:"category" bun:"category,notnull"`
+	Severity       IssueSeverity `json:"severity" bun:"severity,notnull"`
+	Description    string        `json:"description" bun:"description,notnull"`
+	Status         IssueStatus   `json:"status" bun:"status,notnull"`
+	DuplicateOfID  *int64        `json:"duplicate_of_id" bun:"duplicate_of_id"`
+	WorkOrderID    *int64        `json:"work_order_id" bun:"work_order_id"`
+	ResolvedBy     *int64        `json:"resolved_by" bun:"resolved_by"`
+	ResolutionNote string        `json:"resolution_note,omitempty" bun:"resolution_note,nullzero"`
+	ResolvedAt     *time.Time    `json:"resolved_at" bun:"resolved_at"`
+
+	Photos []IssuePhoto `json:"photos,omitempty" bun:"rel:has-many,join:id=issue_id"`
+}
+
+// IssueFilter triyaj kuyruğunun filtreleri; boş alanlar filtrelenmez
+type IssueFilter struct {
+	Status      IssueStatus
+	Category    IssueCategory
+	Severity    IssueSeverity
+	MotorbikeID int64
+}
+
+// IssuePhoto bildirime eklenen fotoğraf. Dosya ve küçük resmi BlobStore'da saklanır, kayıt yalnızca anahtarlarını tutar.
+type IssuePhoto struct {
+	bun.BaseModel `bun:"table:issue_photos,alias:ip"`
+
+	ID           int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt    time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	IssueID      int64     `json:"issue_id" bun:"issue_id,notnull"`
+	Key          string    `json:"-" bun:"key,notnull"`
+	ThumbnailKey string    `json:"-" bun:"thumbnail_key,notnull"`
+	ContentType  string    `json:"content_type" bun:"content_type,notnull"`
+	Size         int64     `json:"size" bun:"size,notnull"`
+	Width        int       `json:"width" bun:"width,notnull"`
+	Height       int       `json:"height" bun:"height,notnull"`
+}
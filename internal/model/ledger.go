@@ -0,0 +1,114 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type LedgerAccountType string
+
+const (
+	AccountUserWallet LedgerAccountType = "user_wallet" // kullanıcının ön ödemeli bakiyesi
+	AccountSystem     LedgerAccountType = "system"      // paranın geldiği veya gittiği şirket hesapları
+)
+
+// Sistem hesap kodları. Her para birimi için ayrı hesap tutulur.
+const (
+	SystemAccountCash        = "cash"         // kart, havale gibi dış kaynaklardan gelen para
+	SystemAccountRideRevenue = "ride_revenue" // sürüş gelirleri
+	SystemAccountPromotions  = "promotions"   // kampanya ve promosyon giderleri
+	SystemAccountAdjustments = "adjustments"  // admin düzeltmeleri
+	SystemAccountPassRevenue = "pass_revenue" // abonelik satışları
+)
+
+type LedgerTransactionKind string
+
+const (
+	LedgerTopUp        LedgerTransactionKind = "top_up"
+	LedgerRideCharge   LedgerTransactionKind = "ride_charge"
+	LedgerRefund       LedgerTransactionKind = "refund"
+	LedgerPromoCredit  LedgerTransactionKind = "promo_credit"
+	LedgerAdjustment   LedgerTransactionKind = "adjustment"
+	LedgerPassPurchase LedgerTransactionKind = "pass_purchase"
+)
+
+// LedgerAccount çift taraflı muhasebe defterindeki hesap. Bakiye tutulmaz, hesabın kayıtlarının toplamıdır.
+type LedgerAccount struct {
+	bun.BaseModel `bun:"table:ledger_accounts,alias:la"`
+
+	ID        int64             `json:"id" bun:",pk,autoincrement"`
+	CreatedAt time.Time         `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	Type      LedgerAccountType `json:"type" bun:"type,notnull"`
+	Code      string            `json:"code,omitempty" bun:"code,nullzero"` // sistem hesaplarında dolu
+	UserID    *int64            `json:"user_id,omitempty" bun:"user_id"`    // kullanıcı cüzdanlarında dolu
+	Currency  string            `json:"currency" bun:"currency,notnull"`
+}
+
+// LedgerTransaction tek bir para hareketi. Kayıtların toplamı her zaman sıfırdır; bir hesaptan çıkan tutar
+// diğerine girer. İşlemler ve kayıtlar değiştirilmez, hatalar ters kayıtla düzeltilir.
+type LedgerTransaction struct {
+	bun.BaseModel `bun:"table:ledger_transactions,alias:lt"`
+
+	ID          int64                 `json:"id" bun:",pk,autoincrement"`
+	CreatedAt   time.Time             `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	Kind        LedgerTransactionKind `json:"kind" bun:"kind,notnull"`
+	Currency    string                `json:"currency" bun:"currency,notnull"`
+	Description string                `json:"description,omitempty" bun:"description,nullzero"`
+	RideID      *int64                `json:"ride_id,omitempty" bun:"ride_id"`
+	CreatedBy   *int64                `json:"created_by,omitempty" bun:"created_by"` // işlemi yapan admin, sistem işlemlerinde boş
+
+	Entries []LedgerEntry `json:"entries,omitempty" bun:"rel:has-many,join:id=transaction_id"`
+}
+
+// LedgerEntry işlemin bir hesaba etkisi. Pozitif tutar hesabın bakiyesini artırır, negatif tutar azaltır.
+type LedgerEntry struct {
+	bun.BaseModel `bun:"table:ledger_entries,alias:le"`
+
+	ID            int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt     time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	TransactionID int64     `json:"transaction_id" bun:"transaction_id,notnull"`
+	AccountID     int64     `json:"account_id" bun:"account_id,notnull"`
+	Amount        int64     `json:"amount" bun:"amount,notnull"`
+}
+
+// Wallet kullanıcının kayıtlardan hesaplanan bakiyesi
+type Wallet struct {
+	UserID   int64  `json:"user_id"`
+	Balance  int64  `json:"balance"`
+	Currency string `json:"currency"`
+}
+
+// WalletTransaction cüzdan hareketi: işlemin kullanıcı hesabına etkisi ve sonrasındaki bakiye
+type WalletTransaction struct {
+	TransactionID int64                 `json:"transaction_id" bun:"transaction_id"`
+	Kind          LedgerTransactionKind `json:"kind" bun:"kind"`
+	Description   string                `json:"description" bun:"description"`
+	RideID        *int64                `json:"ride_id" bun:"ride_id"`
+	Amount        int64                 `json:"amount" bun:"amount"`
+	BalanceAfter  int64                 `json:"balance_after" bun:"balance_after"`
+	CreatedAt     time.Time             `json:"created_at" bun:"created_at"`
+}
+
+// IsBalanced işlemin en az iki kaydı olup olmadığını, sıfır tutarlı kayıt içermediğini ve kayıtların toplamının sıfır olduğunu kontrol eder
+func (t LedgerTransaction) IsBalanced() bool {
+	if len(t.Entries) < 2 {
+		return false
+	}
+	var sum int64
+	for _, e := range t.Entries {
+		if e.Amount == 0 {
+			return false
+		}
+		sum += e.Amount
+	}
+	return sum == 0
+}
+
+func (k LedgerTransactionKind) IsValid() bool {
+	switch k {
+	case LedgerTopUp, LedgerRideCharge, LedgerRefund, LedgerPromoCredit, LedgerAdjustment, LedgerPassPurchase:
+		return true
+	default:
+		return false
+	}
+}
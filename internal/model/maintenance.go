@@ -0,0 +1,181 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type MaintenanceType string
+
+const (
+	MaintenanceTyre       MaintenanceType = "tyre"
+	MaintenanceBrakes     MaintenanceType = "brakes"
+	MaintenanceBattery    MaintenanceType = "battery"
+	MaintenanceBodywork   MaintenanceType = "bodywork"
+	MaintenanceInspection MaintenanceType = "inspection" // belirli bir parçaya bağlanamayan arızalar için genel kontrol
+)
+
+func (t MaintenanceType) IsValid() bool {
+	switch t {
+	case MaintenanceTyre, MaintenanceBrakes, MaintenanceBattery, MaintenanceBodywork, MaintenanceInspection:
+		return true
+	default:
+		return false
+	}
+}
+
+type WorkOrderPriority string
+
+const (
+	PriorityLow    WorkOrderPriority = "low"
+	PriorityNormal WorkOrderPriority = "normal"
+	PriorityHigh   WorkOrderPriority = "high"
+	PriorityUrgent WorkOrderPriority = "urgent" // motor güvenli değil, hemen ilgilenilmeli
+)
+
+// priorityRanks önceliklerin aciliyet sırası, büyük olan daha acildir
+var priorityRanks = map[WorkOrderPriority]int{PriorityLow: 0, PriorityNormal: 1, PriorityHigh: 2, PriorityUrgent: 3}
+
+// Exceeds önceliğin other'dan daha acil olup olmadığını döner
+func (p WorkOrderPriority) Exceeds(other WorkOrderPriority) bool {
+	return priorityRanks[p] > priorityRanks[other]
+}
+
+func (p WorkOrderPriority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+type WorkOrderStatus string
+
+const (
+	WorkOrderOpen       WorkOrderStatus = "open"        // açıldı, motor bakıma alındı
+	WorkOrderInProgress WorkOrderStatus = "in_progress" // teknisyen çalışmaya başladı
+	WorkOrderCompleted  WorkOrderStatus = "completed"   // iş bitti, parça ve işçilik kaydedildi
+	WorkOrderCancelled  WorkOrderStatus = "cancelled"
+)
+
+// workOrderTransitions iş emrinin geçebileceği durumlar. Tamamlanan veya iptal edilen iş emri kapanır.
+var workOrderTransitions = map[WorkOrderStatus][]WorkOrderStatus{
+	WorkOrderOpen:       {WorkOrderInProgress, WorkOrderCompleted, WorkOrderCancelled},
+	WorkOrderInProgress: {WorkOrderCompleted, WorkOrderCancelled},
+}
+
+// OpenWorkOrderStatuses motoru bakımda tutan, kapanmamış iş emri durumları
+var OpenWorkOrderStatuses = []WorkOrderStatus{WorkOrderOpen, WorkOrderInProgress}
+
+// CanTransitionTo iş emrinin next durumuna geçip geçemeyeceğini döner
+func (s WorkOrderStatus) CanTransitionTo(next WorkOrderStatus) bool {
+	for _, allowed := range workOrderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed iş emrinin kapanıp kapanmadığını döner
+func (s WorkOrderStatus) IsClosed() bool {
+	return len(workOrderTransitions[s]) == 0
+}
+
+func (s WorkOrderStatus) IsValid() bool {
+	switch s {
+	case WorkOrderOpen, WorkOrderInProgress, WorkOrderCompleted, WorkOrderCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkOrder motor için açılan bakım iş emri. Açık iş emri olan motor bakımda kalır; son iş emri kapandığında müsait olur.
+// Kilometre ve sürüş sayısı iş emri açıldığı andaki değerlerdir ve koruyucu bakım planlarında bir sonraki bakımın
+// ne zaman geleceğini belirler. Tutarlar kuruş cinsindendir.
+type WorkOrder struct {
+	BaseModel `bun:"table:work_orders,alias:wo"`
+
+	MotorbikeID    int64             `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	Type           MaintenanceType   `json:"type" bun:"type,notnull"`
+	Priority       WorkOrderPriority `json:"priority" bun:"priority,notnull"`
+	Status         WorkOrderStatus   `json:"status" bun:"status,notnull"`
+	Description    string            `json:"description" bun:"description,notnull"`
+	TechnicianID   *int64            `json:"technician_id" bun:"technician_id"`
+	ScheduleID     *int64            `json:"schedule_id" bun:"schedule_id"` // koruyucu bakım planından açıldıysa
+	OdometerMeters *int64            `json:"odometer_meters" bun:"odometer_meters"`
+	RideCount      int               `json:"ride_count" bun:"ride_count,notnull"`
+	LabourMinutes  int               `json:"labour_minutes" bun:"labour_minutes,notnull"`
+	LabourCost     int64             `json:"labour_cost" bun:"labour_cost,notnull"`
+	PartsCost      int64             `json:"parts_cost" bun:"parts_cost,notnull"`
+	Cost           int64             `json:"cost" bun:"cost,notnull"` // işçilik + parçalar
+	Currency       string            `json:"currency" bun:"currency,notnull"`
+	ResolutionNote string            `json:"resolution_note,omitempty" bun:"resolution_note,nullzero"`
+	OpenedBy       *int64            `json:"opened_by" bun:"opened_by"` // plandan açılan iş emirlerinde boş
+	ClosedBy       *int64            `json:"closed_by" bun:"closed_by"`
+	StartedAt      *time.Time        `json:"started_at" bun:"started_at"`
+	ClosedAt       *time.Time        `json:"closed_at" bun:"closed_at"`
+
+	Parts []WorkOrderPart `json:"parts,omitempty" bun:"rel:has-many,join:id=work_order_id"`
+}
+
+// WorkOrderFilter iş emri listesinin filtreleri; boş alanlar filtrelenmez
+type WorkOrderFilter struct {
+	Status       WorkOrderStatus
+	Type         MaintenanceType
+	MotorbikeID  int64
+	TechnicianID int64
+}
+
+// WorkOrderPart iş emrinde kullanılan parça
+type WorkOrderPart struct {
+	bun.BaseModel `bun:"table:work_order_parts,alias:wop"`
+
+	ID          int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt   time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	WorkOrderID int64     `json:"work_order_id" bun:"work_order_id,notnull"`
+	Name        string    `json:"name" bun:"name,notnull"`
+	PartNumber  string    `json:"part_number,omitempty" bun:"part_number,nullzero"`
+	Quantity    int       `json:"quantity" bun:"quantity,notnull"`
+	UnitCost    int64     `json:"unit_cost" bun:"unit_cost,notnull"`
+}
+
+// MaintenanceSchedule koruyucu bakım planı. Motorun aynı türdeki son tamamlanan bakımından bu yana kat ettiği mesafe
+// IntervalMeters'a veya bitirdiği sürüş sayısı IntervalRides'a ulaştığında otomatik iş emri açılır. Sıfır olan aralık
+// kullanılmaz. MotorbikeModel boşsa plan tüm modellere uygulanır.
+type MaintenanceSchedule struct {
+	BaseModel `bun:"table:maintenance_schedules,alias:ms"`
+
+	Name           string            `json:"name" bun:"name,notnull"`
+	Type           MaintenanceType   `json:"type" bun:"type,notnull"`
+	MotorbikeModel string            `json:"motorbike_model,omitempty" bun:"motorbike_model,nullzero"`
+	IntervalMeters int64             `json:"interval_meters" bun:"interval_meters,notnull"`
+	IntervalRides  int               `json:"interval_rides" bun:"interval_rides,notnull"`
+	Priority       WorkOrderPriority `json:"priority" bun:"priority,notnull"`
+	IsActive       bool              `json:"is_active" bun:"is_active,notnull"`
+}
+
+// AppliesTo planın motora uygulanıp uygulanmadığını döner
+func (s MaintenanceSchedule) AppliesTo(motorbike Motorbike) bool {
+	return s.IsActive && (s.MotorbikeModel == "" || s.MotorbikeModel == motorbike.Model)
+}
+
+// IsDue plana göre motorun bakım zamanının gelip gelmediğini döner. last motorun aynı türdeki son tamamlanan iş emridir;
+// hiç bakım yapılmadıysa nil verilir ve aralıklar sıfırdan sayılır. Kilometresi bilinmeyen motorda yalnızca sürüş sayısına bakılır.
+func (s MaintenanceSchedule) IsDue(odometerMeters *int64, rideCount int, last *WorkOrder) bool {
+	var sinceMeters int64
+	sinceRides := rideCount
+	if last != nil {
+		sinceRides -= last.RideCount
+	}
+	if odometerMeters != nil {
+		sinceMeters = *odometerMeters
+		if last != nil && last.OdometerMeters != nil {
+			sinceMeters -= *last.OdometerMeters
+		}
+	}
+	return (s.IntervalMeters > 0 && sinceMeters >= s.IntervalMeters) || (s.IntervalRides > 0 && sinceRides >= s.IntervalRides)
+}
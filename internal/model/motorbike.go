@@ -1,11 +1,18 @@
 package model
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
 type MotorBikeStatus string
 
 const (
 	BikeAvailable     MotorBikeStatus = "available"
 	BikeInMaintenance MotorBikeStatus = "maintenance"
 	BikeRented        MotorBikeStatus = "rented"
+	BikeReserved      MotorBikeStatus = "reserved"
 )
 
 type LockStatus string
@@ -25,13 +32,38 @@ type Motorbike struct {
 	Photos            []MotorbikePhoto `json:"photos" bun:"rel:has-many,join:id=motorbike_id"`
 	Status            MotorBikeStatus  `json:"status" bun:"status,type:motorbike_status"`
 	LockStatus        LockStatus       `json:"lock_status" bun:"lock_status,type:lock_status"`
+	BatteryLevel      *int             `json:"battery_level" bun:"battery_level"`     // yüzde, cihazdan gelen son değer
+	LastSeenAt        *time.Time       `json:"last_seen_at" bun:"last_seen_at"`       // cihazdan son telemetri zamanı
+	OdometerMeters    *int64           `json:"odometer_meters" bun:"odometer_meters"` // cihazdan gelen son kilometre
+	DeviceKeyHash     string           `json:"-" bun:"device_key_hash,nullzero"`
 }
 
+// MotorbikePhoto motorun galeri fotoğrafı. Yüklenen fotoğraflar ve küçük resimleri BlobStore'da saklanır (StorageKey);
+// eski kayıtlar yalnızca harici bir PhotoURL tutar. Galeri Position'a göre sıralanır, kapak fotoğrafı IsPrimary ile işaretlenir.
 type MotorbikePhoto struct {
 	BaseModel `bun:"table:motorbike_photos,alias:mp"`
 
-	MotorbikeID int64  `json:"motorbike_id" bun:"motorbike_id,notnull"`
-	PhotoURL    string `json:"photo_url" bun:"photo_url,notnull"`
+	MotorbikeID  int64  `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	PhotoURL     string `json:"photo_url,omitempty" bun:"photo_url,nullzero"`
+	StorageKey   string `json:"-" bun:"storage_key,nullzero"`
+	ThumbnailKey string `json:"-" bun:"thumbnail_key,nullzero"`
+	ContentType  string `json:"content_type,omitempty" bun:"content_type,nullzero"`
+	Size         int64  `json:"size,omitempty" bun:"size,nullzero"`
+	Width        int    `json:"width,omitempty" bun:"width,nullzero"`
+	Height       int    `json:"height,omitempty" bun:"height,nullzero"`
+	Position     int    `json:"position" bun:"position,notnull"`
+	IsPrimary    bool   `json:"is_primary" bun:"is_primary,notnull"`
+}
+
+// SetDeviceKey cihaz anahtarının özetini saklar. Anahtarın kendisi yalnızca üretildiğinde admin'e gösterilir.
+func (m *Motorbike) SetDeviceKey(key string) {
+	m.DeviceKeyHash = HashDeviceKey(key)
+}
+
+// HashDeviceKey cihaz anahtarını veritabanında aranabilir şekilde özetler
+func HashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
 }
 
 func (Motorbike) TableName() string {
@@ -50,6 +82,8 @@ func (r MotorBikeStatus) String() string {
 		return "maintenance"
 	case BikeRented:
 		return "rented"
+	case BikeReserved:
+		return "reserved"
 	default:
 		return "unknown"
 	}
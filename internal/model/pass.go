@@ -0,0 +1,99 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type PassPeriod string
+
+const (
+	PassDaily   PassPeriod = "day"
+	PassWeekly  PassPeriod = "week"
+	PassMonthly PassPeriod = "month"
+)
+
+// End periyodun start anında başlayan döneminin bitiş zamanını döner
+func (p PassPeriod) End(start time.Time) time.Time {
+	switch p {
+	case PassWeekly:
+		return start.AddDate(0, 0, 7)
+	case PassMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// PassProduct admin'in tanımladığı abonelik paketi. Abonelik süresince her gün IncludedMinutesPerDay kadar dakika
+// ücretlendirilmez; WaiveUnlockFee ise kilit açma ücreti alınmaz. Tutarlar kuruş.
+type PassProduct struct {
+	BaseModel `bun:"table:pass_products,alias:pp"`
+
+	Name                  string     `json:"name" bun:"name,notnull"`
+	Description           string     `json:"description" bun:"description,nullzero"`
+	Period                PassPeriod `json:"period" bun:"period,notnull"`
+	Price                 int64      `json:"price" bun:"price,notnull"`
+	Currency              string     `json:"currency" bun:"currency,notnull"`
+	IncludedMinutesPerDay int        `json:"included_minutes_per_day" bun:"included_minutes_per_day,notnull"`
+	WaiveUnlockFee        bool       `json:"waive_unlock_fee" bun:"waive_unlock_fee,notnull"`
+	IsActive              bool       `json:"is_active" bun:"is_active,notnull"` // pasif paketler satın alınamaz, mevcut abonelikler yenilenmez
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive  SubscriptionStatus = "active"
+	SubscriptionExpired SubscriptionStatus = "expired"
+)
+
+// Subscription kullanıcının satın aldığı abonelik. Otomatik yenileme açıksa dönem sonunda ücret tekrar alınır;
+// kapatılırsa abonelik dönem sonuna kadar geçerli kalır.
+type Subscription struct {
+	BaseModel `bun:"table:subscriptions,alias:sub"`
+
+	UserID             int64              `json:"user_id" bun:"user_id,notnull"`
+	ProductID          int64              `json:"product_id" bun:"product_id,notnull"`
+	Status             SubscriptionStatus `json:"status" bun:"status,notnull"`
+	CurrentPeriodStart time.Time          `json:"current_period_start" bun:"current_period_start,notnull"`
+	CurrentPeriodEnd   time.Time          `json:"current_period_end" bun:"current_period_end,notnull"`
+	AutoRenew          bool               `json:"auto_renew" bun:"auto_renew,notnull"`
+	CancelledAt        *time.Time         `json:"cancelled_at" bun:"cancelled_at"` // otomatik yenilemenin kapatıldığı zaman
+	RenewalFailure     string             `json:"renewal_failure,omitempty" bun:"renewal_failure,nullzero"`
+
+	Product *PassProduct `json:"product,omitempty" bun:"rel:belongs-to,join:product_id=id"`
+}
+
+// IsActiveAt aboneliğin verilen anda geçerli olup olmadığını döner
+func (s Subscription) IsActiveAt(t time.Time) bool {
+	return s.Status == SubscriptionActive && !t.Before(s.CurrentPeriodStart) && t.Before(s.CurrentPeriodEnd)
+}
+
+// SubscriptionCharge aboneliğin bir dönemi için alınan ücret. Ücret cüzdan defterine pass_purchase olarak yazılır.
+type SubscriptionCharge struct {
+	bun.BaseModel `bun:"table:subscription_charges,alias:sc"`
+
+	ID             int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt      time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	SubscriptionID int64     `json:"subscription_id" bun:"subscription_id,notnull"`
+	UserID         int64     `json:"user_id" bun:"user_id,notnull"`
+	Amount         int64     `json:"amount" bun:"amount,notnull"`
+	CardAmount     int64     `json:"card_amount" bun:"card_amount,notnull"` // tutarın karttan tahsil edilen kısmı
+	Currency       string    `json:"currency" bun:"currency,notnull"`
+	PeriodStart    time.Time `json:"period_start" bun:"period_start,notnull"`
+	PeriodEnd      time.Time `json:"period_end" bun:"period_end,notnull"`
+	TransactionID  int64     `json:"transaction_id" bun:"transaction_id,notnull"`
+}
+
+// PassUsage abonelikten bir sürüşte kullanılan dakikalar. Günlük hak, sürüşün başladığı günün kullanımlarından düşülür.
+type PassUsage struct {
+	bun.BaseModel `bun:"table:pass_usages,alias:pu"`
+
+	ID             int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt      time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	SubscriptionID int64     `json:"subscription_id" bun:"subscription_id,notnull"`
+	RideID         int64     `json:"ride_id" bun:"ride_id,notnull"`
+	UsageDate      time.Time `json:"usage_date" bun:"usage_date,type:date,notnull"`
+	Minutes        int       `json:"minutes" bun:"minutes,notnull"`
+	UnlockWaived   bool      `json:"unlock_waived" bun:"unlock_waived,notnull"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// RidePaymentStatus sürüşün kart ödemesinin durumu
+type RidePaymentStatus string
+
+const (
+	PaymentAuthorized RidePaymentStatus = "authorized" // sürüş başında provizyon alındı
+	PaymentCaptured   RidePaymentStatus = "captured"   // cüzdanın karşılamadığı tutar karttan tahsil edildi
+	PaymentVoided     RidePaymentStatus = "voided"     // kart kullanılmadan provizyon kaldırıldı
+	PaymentFailed     RidePaymentStatus = "failed"     // tahsilat yapılamadı veya sağlayıcı sonradan reddetti
+	PaymentSettling   RidePaymentStatus = "settling"   // kullanıcı ödemeyi tekrar deniyor; deneme bitene kadar yenisi başlatılamaz
+)
+
+// RidePayment sürüşün ödeme sağlayıcısındaki provizyon ve tahsilat kaydı. Tutarlar kuruş cinsindendir.
+type RidePayment struct {
+	BaseModel `bun:"table:ride_payments,alias:rp"`
+
+	RideID           int64             `json:"ride_id" bun:"ride_id,notnull"`
+	UserID           int64             `json:"user_id" bun:"user_id,notnull"`
+	Provider         string            `json:"provider" bun:"provider,notnull"`
+	AuthorizationID  string            `json:"authorization_id" bun:"authorization_id,notnull"`
+	AuthorizedAmount int64             `json:"authorized_amount" bun:"authorized_amount,notnull"`
+	CaptureID        string            `json:"capture_id,omitempty" bun:"capture_id,nullzero"`
+	CapturedAmount   int64             `json:"captured_amount" bun:"captured_amount,notnull"`
+	Currency         string            `json:"currency" bun:"currency,notnull"`
+	Status           RidePaymentStatus `json:"status" bun:"status,notnull"`
+	FailureReason    string            `json:"failure_reason,omitempty" bun:"failure_reason,nullzero"`
+	RetryCount       int               `json:"retry_count" bun:"retry_count,notnull"` // ödemenin tekrar denenme sayısı, sağlayıcıya giden referansa eklenir
+}
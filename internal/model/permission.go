@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Permission route'ları koruyan yetkinin adıdır, "<kaynak>.<işlem>" biçimindedir
+type Permission string
+
+const (
+	PermUsersRead     Permission = "users.read"
+	PermUsersWrite    Permission = "users.write"
+	PermUsersBan      Permission = "users.ban"
+	PermUsersDelete   Permission = "users.delete"
+	PermWalletsAdjust Permission = "wallets.adjust"
+
+	PermRidesRead      Permission = "rides.read"
+	PermRidesDelete    Permission = "rides.delete"
+	PermRidesRefund    Permission = "rides.refund"
+	PermDisputesRead   Permission = "disputes.read"
+	PermDisputesReview Permission = "disputes.review"
+
+	PermReservationsCancel Permission = "reservations.cancel"
+
+	PermMotorbikesRead    Permission = "motorbikes.read"
+	PermMotorbikesWrite   Permission = "motorbikes.write"
+	PermMotorbikesCommand Permission = "motorbikes.command"
+	PermMaintenanceRead   Permission = "maintenance.read"
+	PermMaintenanceWrite  Permission = "maintenance.write"
+	PermIssuesRead        Permission = "issues.read"
+	PermIssuesTriage      Permission = "issues.triage"
+	PermTasksWork         Permission = "tasks.work"
+	PermTasksManage       Permission = "tasks.manage"
+	PermBluetoothRead     Permission = "bluetooth.read"
+	PermBluetoothWrite    Permission = "bluetooth.write"
+
+	PermTariffsRead     Permission = "tariffs.read"
+	PermTariffsWrite    Permission = "tariffs.write"
+	PermPromotionsRead  Permission = "promotions.read"
+	PermPromotionsWrite Permission = "promotions.write"
+	PermPassesRead      Permission = "passes.read"
+	PermPassesWrite     Permission = "passes.write"
+	PermZonesRead       Permission = "zones.read"
+	PermZonesWrite      Permission = "zones.write"
+
+	PermRolesManage Permission = "roles.manage"
+	PermAuditRead   Permission = "audit.read"
+)
+
+// IsSuperuser admin rolünün tüm yetkilere sahip olduğunu belirtir; admin'in yetkileri veritabanında tutulmaz
+func (r Role) IsSuperuser() bool {
+	return r == AdminRole
+}
+
+// IsSystem kod tarafından kullanılan ve silinemeyen rolleri belirtir
+func (r Role) IsSystem() bool {
+	switch r {
+	case AdminRole, UserRole, OperatorRole:
+		return true
+	default:
+		return false
+	}
+}
+
+// PermissionDefinition yetki kataloğundaki bir kayıttır, migration ile eklenir
+type PermissionDefinition struct {
+	bun.BaseModel `bun:"table:permissions,alias:p"`
+
+	Name        Permission `bun:"name,pk"`
+	Description string     `bun:"description"`
+}
+
+// RoleDefinition kullanıcılara atanabilen roldür. Permissions role_permissions tablosundan doldurulur.
+type RoleDefinition struct {
+	bun.BaseModel `bun:"table:roles,alias:r"`
+
+	Name        Role         `json:"name" bun:"name,pk"`
+	Description string       `json:"description" bun:"description"`
+	Permissions []Permission `json:"permissions" bun:"-"`
+	CreatedAt   time.Time    `json:"created_at" bun:"created_at,nullzero,default:current_timestamp"`
+	UpdatedAt   time.Time    `json:"updated_at" bun:"updated_at,nullzero,default:current_timestamp"`
+}
+
+type RolePermission struct {
+	bun.BaseModel `bun:"table:role_permissions,alias:rp"`
+
+	Role       Role       `bun:"role,pk"`
+	Permission Permission `bun:"permission,pk"`
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"strings"
+	"time"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage" // sürüş ücretinin yüzdesi kadar indirim
+	DiscountFixed      DiscountType = "fixed"      // sabit tutarda indirim
+)
+
+// Promotion kullanıcının sürüşe uyguladığı kampanya kodu. Limitlerde 0 sınırsız anlamına gelir.
+type Promotion struct {
+	BaseModel `bun:"table:promotions,alias:pr"`
+
+	Code            string       `json:"code" bun:"code,notnull"`
+	Description     string       `json:"description" bun:"description,nullzero"`
+	DiscountType    DiscountType `json:"discount_type" bun:"discount_type,notnull"`
+	DiscountValue   int64        `json:"discount_value" bun:"discount_value,notnull"` // yüzde (1-100) veya kuruş
+	MaxDiscount     int64        `json:"max_discount" bun:"max_discount,notnull"`     // yüzde indirimlerinde üst sınır (kuruş)
+	Currency        string       `json:"currency" bun:"currency,notnull"`
+	FirstRideOnly   bool         `json:"first_ride_only" bun:"first_ride_only,notnull"`
+	PerUserLimit    int          `json:"per_user_limit" bun:"per_user_limit,notnull"`
+	TotalLimit      int          `json:"total_limit" bun:"total_limit,notnull"`
+	RedemptionCount int          `json:"redemption_count" bun:"redemption_count,notnull"`
+	MotorbikeModels []string     `json:"motorbike_models" bun:"motorbike_models,array"` // boşsa tüm modellerde geçerli
+	ValidFrom       *time.Time   `json:"valid_from" bun:"valid_from"`
+	ValidUntil      *time.Time   `json:"valid_until" bun:"valid_until"`
+	IsActive        bool         `json:"is_active" bun:"is_active,notnull"`
+}
+
+// PromotionRedemption promosyonun bir sürüşte kullanılması. Sürüş bitirilirken indirimle birlikte yazılır.
+type PromotionRedemption struct {
+	bun.BaseModel `bun:"table:promotion_redemptions,alias:prr"`
+
+	ID          int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt   time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	PromotionID int64     `json:"promotion_id" bun:"promotion_id,notnull"`
+	UserID      int64     `json:"user_id" bun:"user_id,notnull"`
+	RideID      int64     `json:"ride_id" bun:"ride_id,notnull"`
+	Amount      int64     `json:"amount" bun:"amount,notnull"` // uygulanan indirim (kuruş)
+	Currency    string    `json:"currency" bun:"currency,notnull"`
+}
+
+// PromotionUsage promosyonun kullanım raporu
+type PromotionUsage struct {
+	PromotionID   int64      `json:"promotion_id" bun:"promotion_id"`
+	Code          string     `json:"code" bun:"code"`
+	Redemptions   int        `json:"redemptions" bun:"redemptions"`
+	UniqueUsers   int        `json:"unique_users" bun:"unique_users"`
+	TotalDiscount int64      `json:"total_discount" bun:"total_discount"`
+	Currency      string     `json:"currency" bun:"currency"`
+	LastUsedAt    *time.Time `json:"last_used_at" bun:"last_used_at"`
+}
+
+// NormalizePromotionCode kodları büyük harfe çevirir, kodlar büyük/küçük harf duyarsızdır
+func NormalizePromotionCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// AppliesToModel promosyonun motor modelinde geçerli olup olmadığını döner
+func (p Promotion) AppliesToModel(motorbikeModel string) bool {
+	if len(p.MotorbikeModels) == 0 {
+		return true
+	}
+	for _, m := range p.MotorbikeModels {
+		if strings.EqualFold(m, motorbikeModel) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAt promosyonun verilen anda aktif ve geçerlilik aralığında olup olmadığını döner
+func (p Promotion) IsValidAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && t.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !t.Before(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// Discount tutar üzerinden uygulanacak indirimi hesaplar, indirim tutarı aşamaz
+func (p Promotion) Discount(amount int64) int64 {
+	var discount int64
+	switch p.DiscountType {
+	case DiscountPercentage:
+		discount = amount * p.DiscountValue / 100
+		if p.MaxDiscount > 0 && discount > p.MaxDiscount {
+			discount = p.MaxDiscount
+		}
+	case DiscountFixed:
+		discount = p.DiscountValue
+	}
+	return max(0, min(discount, amount))
+}
+
+type ReferralStatus string
+
+const (
+	ReferralPending  ReferralStatus = "pending"  // davet edilen kullanıcı henüz sürüş tamamlamadı
+	ReferralRewarded ReferralStatus = "rewarded" // iki kullanıcıya da kredi verildi
+)
+
+// ReferralCode kullanıcının arkadaşlarını davet ederken paylaştığı kod
+type ReferralCode struct {
+	bun.BaseModel `bun:"table:referral_codes,alias:rc"`
+
+	ID        int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	UserID    int64     `json:"user_id" bun:"user_id,notnull"`
+	Code      string    `json:"code" bun:"code,notnull"`
+}
+
+// Referral davet eden ile davet edilen kullanıcı arasındaki bağ. Davet edilen kullanıcı ilk sürüşünü
+// tamamladığında iki tarafa da cüzdan kredisi verilir.
+type Referral struct {
+	BaseModel `bun:"table:referrals,alias:rf"`
+
+	ReferrerID     int64          `json:"referrer_id" bun:"referrer_id,notnull"`
+	RefereeID      int64          `json:"referee_id" bun:"referee_id,notnull"`
+	Status         ReferralStatus `json:"status" bun:"status,notnull"`
+	ReferrerCredit int64          `json:"referrer_credit" bun:"referrer_credit,notnull"`
+	RefereeCredit  int64          `json:"referee_credit" bun:"referee_credit,notnull"`
+	RideID         *int64         `json:"ride_id" bun:"ride_id"` // ödülü tetikleyen sürüş
+	RewardedAt     *time.Time     `json:"rewarded_at" bun:"rewarded_at"`
+}
+
+// ReferralStats davet raporu
+type ReferralStats struct {
+	Invited       int   `json:"invited" bun:"invited"`
+	Rewarded      int   `json:"rewarded" bun:"rewarded"`
+	CreditsIssued int64 `json:"credits_issued" bun:"credits_issued"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationConverted ReservationStatus = "converted" // sürüşe dönüştü
+	ReservationCancelled ReservationStatus = "cancelled"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// Reservation kullanıcı motora yürürken motoru belirli bir süre için tutar.
+// Fee, rezervasyon sürüşe dönüşürse sürüş ücretine eklenir.
+type Reservation struct {
+	BaseModel `bun:"table:reservations,alias:res"`
+
+	UserID      int64             `json:"user_id" bun:"user_id,notnull"`
+	MotorbikeID int64             `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	Status      ReservationStatus `json:"status" bun:"status,notnull"`
+	ExpiresAt   time.Time         `json:"expires_at" bun:"expires_at,notnull"`
+	EndedAt     *time.Time        `json:"ended_at" bun:"ended_at"`
+	RideID      *int64            `json:"ride_id" bun:"ride_id"`
+	Fee         int64             `json:"fee" bun:"fee,notnull"`
+	Currency    string            `json:"currency" bun:"currency,notnull"`
+}
+
+// IsExpired rezervasyonun süresinin verilen anda dolup dolmadığını döner
+func (r Reservation) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+func (r ReservationStatus) String() string {
+	switch r {
+	case ReservationActive:
+		return "active"
+	case ReservationConverted:
+		return "converted"
+	case ReservationCancelled:
+		return "cancelled"
+	case ReservationExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
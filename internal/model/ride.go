@@ -2,18 +2,128 @@ package model
 
 import (
 	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type RideStatus string
+
+const (
+	RideReserved      RideStatus = "reserved"       // motor sürüşe ayrıldı, provizyon ve kilit açma bekleniyor
+	RideActive        RideStatus = "active"         // motor kullanıcıda, sürüş devam ediyor
+	RidePaused        RideStatus = "paused"         // kullanıcı sürüşü bitirmeden motoru kilitleyip ara verdi
+	RideEnding        RideStatus = "ending"         // sürüş kapatıldı, ücret cüzdandan düşüldü, kart tahsilatı bekleniyor
+	RideCompleted     RideStatus = "completed"      // ücret cüzdandan ve gerekirse karttan alındı
+	RideCancelled     RideStatus = "cancelled"      // provizyon alınamadığı veya kilit açılamadığı için sürüş başlamadı
+	RidePaymentFailed RideStatus = "payment_failed" // ücretin bir kısmı tahsil edilemedi, kullanıcı borcunu ödeyene kadar yeni sürüş başlatamaz
 )
 
+// rideTransitions sürüşün geçebileceği durumlar. Tamamlanan sürüşün kart tahsilatı sağlayıcı tarafından sonradan
+// reddedilebildiği için completed -> payment_failed geçişi de vardır; iptal edilen sürüş kapanır.
+var rideTransitions = map[RideStatus][]RideStatus{
+	RideReserved:      {RideActive, RideCancelled},
+	RideActive:        {RidePaused, RideEnding},
+	RidePaused:        {RideActive, RideEnding},
+	RideEnding:        {RideCompleted, RidePaymentFailed},
+	RideCompleted:     {RidePaymentFailed},
+	RidePaymentFailed: {RideCompleted},
+}
+
+// OngoingRideStatuses motoru kullanıcıda tutan, bitirilmemiş sürüş durumları
+var OngoingRideStatuses = []RideStatus{RideReserved, RideActive, RidePaused}
+
+// EndedRideStatuses bitirilip ücretlendirilmiş sürüş durumları
+var EndedRideStatuses = []RideStatus{RideEnding, RideCompleted, RidePaymentFailed}
+
+// CanTransitionTo sürüşün next durumuna geçip geçemeyeceğini döner
+func (s RideStatus) CanTransitionTo(next RideStatus) bool {
+	for _, allowed := range rideTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOngoing sürüşün henüz bitirilmediğini döner
+func (s RideStatus) IsOngoing() bool {
+	return s == RideReserved || s == RideActive || s == RidePaused
+}
+
+// HasEnded sürüşün bitirilip ücretinin hesaplandığını döner
+func (s RideStatus) HasEnded() bool {
+	return s == RideEnding || s == RideCompleted || s == RidePaymentFailed
+}
+
 type Ride struct {
 	BaseModel `bun:"table:rides"`
 
 	UserID      int64      `json:"user_id"`
 	MotorbikeID int64      `json:"motorbike_id"`
+	Status      RideStatus `json:"status" bun:"status,notnull"`
 	StartTime   time.Time  `json:"start_time" bun:"default:current_timestamp"`
 	EndTime     *time.Time `json:"end_time"`
-	Duration    string     `json:"duration"`
-	Cost        float64    `json:"cost"`
+	Duration    int64      `json:"duration"` // saniye
+	Cost        int64      `json:"cost"`     // para biriminin en küçük biriminde (kuruş)
+	Currency    string     `json:"currency" bun:"currency,nullzero"`
+
+	PromotionID *int64 `json:"promotion_id" bun:"promotion_id"` // kullanıcının sürüşe eklediği kampanya kodu, bitirilirken uygulanır
+
+	// Rota özeti, sürüş bitirilirken kaydedilen GPS noktalarından hesaplanır
+	DistanceMeters int64   `json:"distance_meters"`
+	MaxSpeedKmh    float64 `json:"max_speed_kmh"`
+	AvgSpeedKmh    float64 `json:"avg_speed_kmh"` // duraksamalar hariç ortalama hız
+	IdleSeconds    int64   `json:"idle_seconds"`
 
 	User      User      `bun:"rel:belongs-to,join:user_id=id"`
 	Motorbike Motorbike `bun:"rel:belongs-to,join:motorbike_id=id"`
 }
+
+// RideEvent sürüşün durum geçişi, kayıtlar değiştirilemez
+type RideEvent struct {
+	bun.BaseModel `bun:"table:ride_events,alias:rev"`
+
+	ID         int64      `json:"id" bun:",pk,autoincrement"`
+	CreatedAt  time.Time  `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	RideID     int64      `json:"ride_id" bun:"ride_id,notnull"`
+	FromStatus RideStatus `json:"from_status,omitempty" bun:"from_status,nullzero"`
+	ToStatus   RideStatus `json:"to_status" bun:"to_status,notnull"`
+	ActorID    *int64     `json:"actor_id" bun:"actor_id"` // sürüşü başlatan/bitiren kullanıcı; ödeme sonucu gibi sistem geçişlerinde boş
+	Note       string     `json:"note,omitempty" bun:"note,nullzero"`
+}
+
+// RidePause sürüş sırasında park moduna alınan aralık; EndedAt devam eden park modunda boştur
+type RidePause struct {
+	bun.BaseModel `bun:"table:ride_pauses,alias:rpa"`
+
+	ID        int64      `json:"id" bun:",pk,autoincrement"`
+	CreatedAt time.Time  `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	RideID    int64      `json:"ride_id" bun:"ride_id,notnull"`
+	StartedAt time.Time  `json:"started_at" bun:"started_at,notnull"`
+	EndedAt   *time.Time `json:"ended_at" bun:"ended_at"`
+}
+
+// RidePhoto sürüş sonunda motorun park edildiği yerin fotoğrafı. Dosya ve küçük resmi BlobStore'da saklanır,
+// kayıt yalnızca anahtarlarını tutar; fotoğraflar süreli imzalı bağlantılarla sunulur.
+type RidePhoto struct {
+	bun.BaseModel `bun:"table:ride_photos,alias:rph"`
+
+	ID           int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt    time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	RideID       int64     `json:"ride_id" bun:"ride_id,notnull"`
+	UserID       int64     `json:"user_id" bun:"user_id,notnull"`
+	Key          string    `json:"-" bun:"key,notnull"`
+	ThumbnailKey string    `json:"-" bun:"thumbnail_key,notnull"`
+	ContentType  string    `json:"content_type" bun:"content_type,notnull"`
+	Size         int64     `json:"size" bun:"size,notnull"`
+	Width        int       `json:"width" bun:"width,notnull"`
+	Height       int       `json:"height" bun:"height,notnull"`
+}
+
+// EndOr park modunun bitişini, hâlâ sürüyorsa verilen zamanı döner
+func (p RidePause) EndOr(now time.Time) time.Time {
+	if p.EndedAt != nil {
+		return *p.EndedAt
+	}
+	return now
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type RoutePointSource string
+
+const (
+	RoutePointFromApp    RoutePointSource = "app"
+	RoutePointFromDevice RoutePointSource = "device"
+)
+
+// RidePoint sürüş sırasında kaydedilen tek bir GPS noktası. Yüksek hacimli olduğu için soft delete kullanılmaz.
+type RidePoint struct {
+	bun.BaseModel `bun:"table:ride_points,alias:rp"`
+
+	ID         int64            `json:"id" bun:",pk,autoincrement"`
+	CreatedAt  time.Time        `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	RideID     int64            `json:"ride_id" bun:"ride_id,notnull"`
+	RecordedAt time.Time        `json:"recorded_at" bun:"recorded_at,notnull"`
+	Latitude   float64          `json:"latitude" bun:"latitude,notnull"`
+	Longitude  float64          `json:"longitude" bun:"longitude,notnull"`
+	SpeedKmh   *float64         `json:"speed_kmh" bun:"speed_kmh"` // cihaz/uygulama tarafından bildirilen hız
+	Source     RoutePointSource `json:"source" bun:"source,notnull"`
+}
@@ -0,0 +1,76 @@
+package model
+
+// Tariff sürüş ücretlendirme kuralları. Tutarlar para biriminin en küçük biriminde tutulur.
+// MotorbikeModel boş ise varsayılan tarifedir, dolu ise o modeldeki motorlar için varsayılanı ezer.
+type Tariff struct {
+	BaseModel `bun:"table:tariffs,alias:t"`
+
+	Name                 string `json:"name" bun:"name,notnull"`
+	MotorbikeModel       string `json:"motorbike_model" bun:"motorbike_model,nullzero"`
+	Currency             string `json:"currency" bun:"currency,notnull"`
+	UnlockFee            int64  `json:"unlock_fee" bun:"unlock_fee,notnull"`
+	PerMinuteRate        int64  `json:"per_minute_rate" bun:"per_minute_rate,notnull"`
+	PausePerMinuteRate   int64  `json:"pause_per_minute_rate" bun:"pause_per_minute_rate,notnull"` // park modunda geçen dakikalar için
+	MinimumCharge        int64  `json:"minimum_charge" bun:"minimum_charge,notnull"`
+	DailyCap             int64  `json:"daily_cap" bun:"daily_cap,notnull"` // 0 ise üst sınır yok
+	FreeMinutes          int    `json:"free_minutes" bun:"free_minutes,notnull"`
+	NightMultiplierPct   int    `json:"night_multiplier_pct" bun:"night_multiplier_pct,notnull"` // 100 = değişiklik yok
+	NightStartHour       int    `json:"night_start_hour" bun:"night_start_hour,notnull"`
+	NightEndHour         int    `json:"night_end_hour" bun:"night_end_hour,notnull"`
+	WeekendMultiplierPct int    `json:"weekend_multiplier_pct" bun:"weekend_multiplier_pct,notnull"`
+	IsActive             bool   `json:"is_active" bun:"is_active,notnull"`
+}
+
+// IsNightHour saatin tarifedeki gece aralığına düşüp düşmediğini döner. Aralık gece yarısını geçebilir (22 -> 6).
+func (t Tariff) IsNightHour(hour int) bool {
+	if t.NightStartHour == t.NightEndHour {
+		return false
+	}
+	if t.NightStartHour < t.NightEndHour {
+		return hour >= t.NightStartHour && hour < t.NightEndHour
+	}
+	return hour >= t.NightStartHour || hour < t.NightEndHour
+}
+
+// Fiyat dökümü satır kodları
+const (
+	PriceLineUnlockFee        = "unlock_fee"
+	PriceLineReservationFee   = "reservation_fee"
+	PriceLineTime             = "time"
+	PriceLineNightSurcharge   = "night_surcharge"
+	PriceLineWeekendSurcharge = "weekend_surcharge"
+	PriceLineDailyCap         = "daily_cap"
+	PriceLineMinimumCharge    = "minimum_charge"
+	PriceLineParkingSurcharge = "parking_surcharge"
+	PriceLinePromotion        = "promotion"
+	PriceLinePass             = "pass"
+	PriceLinePause            = "pause"
+)
+
+// PriceLine fiyat dökümündeki tek bir kalem. İndirimler negatif tutarla gösterilir.
+type PriceLine struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity,omitempty"`
+	UnitAmount  int64  `json:"unit_amount,omitempty"`
+	Amount      int64  `json:"amount"`
+}
+
+// RidePriceBreakdown bir sürüşün ücretinin nasıl hesaplandığını kalem kalem saklar
+type RidePriceBreakdown struct {
+	BaseModel `bun:"table:ride_price_breakdowns,alias:rpb"`
+
+	RideID          int64       `json:"ride_id" bun:"ride_id,notnull"`
+	TariffID        int64       `json:"tariff_id" bun:"tariff_id,notnull"`
+	Currency        string      `json:"currency" bun:"currency,notnull"`
+	TotalMinutes    int         `json:"total_minutes" bun:"total_minutes,notnull"`
+	BillableMinutes int         `json:"billable_minutes" bun:"billable_minutes,notnull"`
+	Lines           []PriceLine `json:"lines" bun:"lines,type:jsonb,notnull"`
+	Total           int64       `json:"total" bun:"total,notnull"`
+}
+
+// AddLine dökümüne kalem ekler ve toplamı günceller
+func (b *RidePriceBreakdown) AddLine(line PriceLine) {
+	b.Lines = append(b.Lines, line)
+	b.Total += line.Amount
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TaskType string
+
+const (
+	TaskRebalance   TaskType = "rebalance"    // motoru hedef alana taşı
+	TaskBatterySwap TaskType = "battery_swap" // bataryayı değiştir
+	TaskCollect     TaskType = "collect"      // hatalı park edilmiş motoru topla
+	TaskInspect     TaskType = "inspect"      // motoru yerinde kontrol et
+)
+
+func (t TaskType) IsValid() bool {
+	switch t {
+	case TaskRebalance, TaskBatterySwap, TaskCollect, TaskInspect:
+		return true
+	default:
+		return false
+	}
+}
+
+type TaskSource string
+
+const (
+	TaskSourceManual     TaskSource = "manual"      // admin tarafından açıldı
+	TaskSourceLowBattery TaskSource = "low_battery" // batarya seviyesi düşük motor için otomatik açıldı
+	TaskSourceIdle       TaskSource = "idle"        // uzun süredir kiralanmayan motor için otomatik açıldı
+)
+
+type TaskStatus string
+
+const (
+	TaskOpen      TaskStatus = "open"      // operatör bekliyor; atanmışsa yalnızca atanan operatör üstlenebilir
+	TaskClaimed   TaskStatus = "claimed"   // operatör görevi üstlendi
+	TaskCompleted TaskStatus = "completed" // fotoğraflı kanıtla tamamlandı
+	TaskCancelled TaskStatus = "cancelled"
+)
+
+// taskTransitions görevin geçebileceği durumlar. Üstlenilen görev bırakılırsa tekrar açılır.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskOpen:    {TaskClaimed, TaskCancelled},
+	TaskClaimed: {TaskOpen, TaskCompleted, TaskCancelled},
+}
+
+// CanTransitionTo görevin next durumuna geçip geçemeyeceğini döner
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	for _, allowed := range taskTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed görevin kapanıp kapanmadığını döner
+func (s TaskStatus) IsClosed() bool {
+	return len(taskTransitions[s]) == 0
+}
+
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case TaskOpen, TaskClaimed, TaskCompleted, TaskCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Task saha ekibine verilen görev. Konum görev açıldığında motorun bulunduğu noktadır ve yakındaki görevlerin
+// aranmasında kullanılır. DueAt görevin SLA'ya göre en geç tamamlanması gereken zamandır. Konum değişikliği
+// görevinde hedef alan verilmişse görev ancak motor bu alanın içindeyken tamamlanabilir.
+type Task struct {
+	BaseModel `bun:"table:tasks,alias:t"`
+
+	Type           TaskType   `json:"type" bun:"type,notnull"`
+	Source         TaskSource `json:"source" bun:"source,notnull"`
+	Status         TaskStatus `json:"status" bun:"status,notnull"`
+	MotorbikeID    int64      `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	Latitude       float64    `json:"latitude" bun:"latitude,notnull"`
+	Longitude      float64    `json:"longitude" bun:"longitude,notnull"`
+	TargetZoneID   *int64     `json:"target_zone_id" bun:"target_zone_id"`
+	Note           string     `json:"note,omitempty" bun:"note,nullzero"`
+	AssigneeID     *int64     `json:"assignee_id" bun:"assignee_id"` // admin atadıysa veya operatör üstlendiyse
+	DueAt          time.Time  `json:"due_at" bun:"due_at,notnull"`
+	ClaimedAt      *time.Time `json:"claimed_at" bun:"claimed_at"`
+	ResolutionNote string     `json:"resolution_note,omitempty" bun:"resolution_note,nullzero"`
+	CreatedBy      *int64     `json:"created_by" bun:"created_by"` // otomatik açılan görevlerde boş
+	ClosedBy       *int64     `json:"closed_by" bun:"closed_by"`   // tamamlayan operatör veya iptal eden admin
+	ClosedAt       *time.Time `json:"closed_at" bun:"closed_at"`
+
+	Photos []TaskPhoto `json:"photos,omitempty" bun:"rel:has-many,join:id=task_id"`
+}
+
+// IsOverdue görevin SLA süresini aşıp aşmadığını döner
+func (t Task) IsOverdue(now time.Time) bool {
+	return !t.Status.IsClosed() && now.After(t.DueAt)
+}
+
+// TaskFilter görev listesinin filtreleri; boş alanlar filtrelenmez
+type TaskFilter struct {
+	Status      TaskStatus
+	Type        TaskType
+	MotorbikeID int64
+	AssigneeID  int64
+	Overdue     bool // yalnızca süresi geçmiş kapanmamış görevler
+}
+
+// TaskPhoto görevin tamamlandığını gösteren fotoğraf. Dosya ve küçük resmi BlobStore'da saklanır.
+type TaskPhoto struct {
+	bun.BaseModel `bun:"table:task_photos,alias:tp"`
+
+	ID           int64     `json:"id" bun:",pk,autoincrement"`
+	CreatedAt    time.Time `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	TaskID       int64     `json:"task_id" bun:"task_id,notnull"`
+	Key          string    `json:"-" bun:"key,notnull"`
+	ThumbnailKey string    `json:"-" bun:"thumbnail_key,notnull"`
+	ContentType  string    `json:"content_type" bun:"content_type,notnull"`
+	Size         int64     `json:"size" bun:"size,notnull"`
+	Width        int       `json:"width" bun:"width,notnull"`
+	Height       int       `json:"height" bun:"height,notnull"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// MotorbikeTelemetry cihazın gönderdiği tek bir ölçüm. Yüksek hacimli olduğu için soft delete kullanılmaz.
+type MotorbikeTelemetry struct {
+	bun.BaseModel `bun:"table:motorbike_telemetry,alias:mt"`
+
+	ID             int64      `json:"id" bun:",pk,autoincrement"`
+	CreatedAt      time.Time  `json:"created_at" bun:",nullzero,default:current_timestamp"`
+	MotorbikeID    int64      `json:"motorbike_id" bun:"motorbike_id,notnull"`
+	RecordedAt     time.Time  `json:"recorded_at" bun:"recorded_at,notnull"` // cihaz saatine göre ölçüm zamanı
+	Latitude       float64    `json:"latitude" bun:"latitude,notnull"`
+	Longitude      float64    `json:"longitude" bun:"longitude,notnull"`
+	SpeedKmh       float64    `json:"speed_kmh" bun:"speed_kmh,notnull"`
+	BatteryLevel   *int       `json:"battery_level" bun:"battery_level"`
+	FuelLevel      *int       `json:"fuel_level" bun:"fuel_level"`
+	OdometerMeters *int64     `json:"odometer_meters" bun:"odometer_meters"`
+	LockStatus     LockStatus `json:"lock_status" bun:"lock_status,nullzero"`
+}
@@ -9,9 +9,11 @@ import (
 type Role string
 type Status string
 
+// Sistem rolleri; diğer roller admin tarafından roles tablosunda tanımlanır
 const (
-	AdminRole Role = "admin"
-	UserRole  Role = "user"
+	AdminRole    Role = "admin"
+	UserRole     Role = "user"
+	OperatorRole Role = "operator" // saha ekibi; motorların yerini değiştirir, batarya değiştirir, görevleri tamamlar
 )
 
 const (
@@ -28,7 +30,7 @@ type User struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Phone     string `json:"phone" bun:",unique,notnull"`
-	Role      Role   `json:"role" bun:"type:user_role,notnull,default:'user'"`
+	Role      Role   `json:"role" bun:"type:varchar(50),notnull,default:'user'"`
 	Status    Status `json:"status" bun:"type:user_status,notnull,default:'active'"`
 
 	LastLogin time.Time `json:"last_login" bun:",nullzero"`
@@ -0,0 +1,48 @@
+package model
+
+import "encoding/json"
+
+type ZoneType string
+
+const (
+	ZoneOperating        ZoneType = "operating"         // sürüşlerin bitirilebileceği hizmet alanı
+	ZoneNoParking        ZoneType = "no_parking"        // park edilemeyen alan
+	ZoneSlow             ZoneType = "slow"              // hız sınırı uygulanan alan
+	ZonePreferredParking ZoneType = "preferred_parking" // önerilen park noktası
+)
+
+type ZoneEnforcement string
+
+const (
+	ZoneReject    ZoneEnforcement = "reject"    // sürüş bitirilemez
+	ZoneSurcharge ZoneEnforcement = "surcharge" // sürüş bitirilir, ek ücret alınır
+)
+
+// Zone admin tarafından tanımlanan coğrafi alan. Geometry GeoJSON (Polygon, MultiPolygon veya Feature) olarak saklanır.
+// Enforcement ve Surcharge, operasyon alanı dışında veya park yasağı olan alanda bitirilen sürüşlere uygulanır.
+type Zone struct {
+	BaseModel `bun:"table:zones,alias:z"`
+
+	Name        string          `json:"name" bun:"name,notnull"`
+	Type        ZoneType        `json:"type" bun:"type,notnull"`
+	Geometry    json.RawMessage `json:"geometry" bun:"geometry,type:jsonb,notnull"`
+	Enforcement ZoneEnforcement `json:"enforcement" bun:"enforcement,notnull"`
+	Surcharge   int64           `json:"surcharge" bun:"surcharge,notnull"`         // kuruş
+	MaxSpeedKmh int             `json:"max_speed_kmh" bun:"max_speed_kmh,notnull"` // yalnızca yavaş alanlar için
+	IsActive    bool            `json:"is_active" bun:"is_active,notnull"`
+}
+
+func (r ZoneType) String() string {
+	switch r {
+	case ZoneOperating:
+		return "operating"
+	case ZoneNoParking:
+		return "no_parking"
+	case ZoneSlow:
+		return "slow"
+	case ZonePreferredParking:
+		return "preferred_parking"
+	default:
+		return "unknown"
+	}
+}
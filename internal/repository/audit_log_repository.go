@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+// auditChainLockKey denetim zincirine eklemeleri sıralayan advisory lock anahtarı
+const auditChainLockKey = 727001
+
+type IAuditLogRepository interface {
+	LockChain(ctx context.Context) error
+	GetLast(ctx context.Context) (*model.AuditLog, error)
+	Create(ctx context.Context, log *model.AuditLog) error
+	List(ctx context.Context, filter model.AuditLogFilter, pagination *query.Pagination) ([]model.AuditLog, error)
+	ListAfter(ctx context.Context, filter model.AuditLogFilter, afterID int64, limit int) ([]model.AuditLog, error)
+}
+
+type AuditLogRepository struct {
+	db *bun.DB
+}
+
+func NewAuditLogRepository(db *bun.DB) IAuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// LockChain transaction sonuna kadar zincire başka kayıt eklenmesini engeller, transaction içinde kullanılmalıdır
+func (r *AuditLogRepository) LockChain(ctx context.Context) error {
+	_, err := dbFromContext(ctx, r.db).ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", auditChainLockKey)
+	return err
+}
+
+// GetLast zincirin son kaydını getirir, kayıt yoksa nil döner
+func (r *AuditLogRepository) GetLast(ctx context.Context) (*model.AuditLog, error) {
+	var log model.AuditLog
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&log).Order("id DESC").Limit(1).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &log, err
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(log).Exec(ctx)
+	return err
+}
+
+// List kayıtları yeniden eskiye sayfalı getirir
+func (r *AuditLogRepository) List(ctx context.Context, filter model.AuditLogFilter, pagination *query.Pagination) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	q := applyAuditLogFilter(dbFromContext(ctx, r.db).NewSelect().Model(&logs), filter)
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+	if err := query.ApplyPagination(q.Order("al.id DESC"), *pagination).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ListAfter verilen id'den sonraki kayıtları eskiden yeniye getirir; dışa aktarma ve zincir doğrulaması parça parça okur
+func (r *AuditLogRepository) ListAfter(ctx context.Context, filter model.AuditLogFilter, afterID int64, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	err := applyAuditLogFilter(dbFromContext(ctx, r.db).NewSelect().Model(&logs), filter).
+		Where("al.id > ?", afterID).
+		Order("al.id ASC").
+		Limit(limit).
+		Scan(ctx)
+	return logs, err
+}
+
+func applyAuditLogFilter(q *bun.SelectQuery, filter model.AuditLogFilter) *bun.SelectQuery {
+	if filter.ActorID != 0 {
+		q = q.Where("al.actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		q = q.Where("al.action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		q = q.Where("al.target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		q = q.Where("al.target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("al.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("al.created_at < ?", filter.To)
+	}
+	return q
+}
@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/uptrace/bun"
 )
@@ -10,10 +14,11 @@ type IBluetoothConnectionRepository interface {
 	Create(ctx context.Context, conn *model.BluetoothConnection) error
 	GetByID(ctx context.Context, id int64) (*model.BluetoothConnection, error)
 	GetByUserID(ctx context.Context, id int64) ([]model.BluetoothConnection, error)
-	GetByMotorbikeID(ctx context.Context, id int64) (*model.BluetoothConnection, error)
+	GetOpenByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.BluetoothConnection, error)
 	Update(ctx context.Context, conn *model.BluetoothConnection) error
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context) ([]model.BluetoothConnection, error)
+	CloseOpenByMotorbikeID(ctx context.Context, motorbikeID int64, at time.Time) error
 }
 
 type BluetoothConnectionRepository struct {
@@ -25,40 +30,60 @@ func NewBluetoothConnectionRepository(db *bun.DB) IBluetoothConnectionRepository
 }
 
 func (r *BluetoothConnectionRepository) Create(ctx context.Context, conn *model.BluetoothConnection) error {
-	_, err := r.db.NewInsert().Model(conn).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(conn).Exec(ctx)
 	return err
 }
 
 func (r *BluetoothConnectionRepository) GetByID(ctx context.Context, id int64) (*model.BluetoothConnection, error) {
 	var conn model.BluetoothConnection
-	err := r.db.NewSelect().Model(&conn).Where("id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&conn).Where("id = ?", id).Scan(ctx)
 	return &conn, err
 }
 
 func (r *BluetoothConnectionRepository) GetByUserID(ctx context.Context, id int64) ([]model.BluetoothConnection, error) {
 	var conn []model.BluetoothConnection
-	err := r.db.NewSelect().Model(&conn).Where("user_id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&conn).Where("user_id = ?", id).Scan(ctx)
 	return conn, err
 }
 
-func (r *BluetoothConnectionRepository) GetByMotorbikeID(ctx context.Context, id int64) (*model.BluetoothConnection, error) {
+// GetOpenByMotorbikeID motorun kapatılmamış en son bağlantısını getirir, yoksa nil döner
+func (r *BluetoothConnectionRepository) GetOpenByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.BluetoothConnection, error) {
 	var conn model.BluetoothConnection
-	err := r.db.NewSelect().Model(&conn).Where("motorbike_id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&conn).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("disconnected_at IS NULL").
+		Order("connected_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	return &conn, err
 }
 
 func (r *BluetoothConnectionRepository) Update(ctx context.Context, conn *model.BluetoothConnection) error {
-	_, err := r.db.NewUpdate().Model(conn).WherePK().Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(conn).WherePK().Exec(ctx)
 	return err
 }
 
 func (r *BluetoothConnectionRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().Model((*model.BluetoothConnection)(nil)).Where("id = ?", id).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.BluetoothConnection)(nil)).Where("id = ?", id).Exec(ctx)
 	return err
 }
 
 func (r *BluetoothConnectionRepository) List(ctx context.Context) ([]model.BluetoothConnection, error) {
 	var conn []model.BluetoothConnection
-	err := r.db.NewSelect().Model(&conn).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&conn).Scan(ctx)
 	return conn, err
 }
+
+// CloseOpenByMotorbikeID motorun kapatılmamış bağlantı kayıtlarını verilen zamanla kapatır
+func (r *BluetoothConnectionRepository) CloseOpenByMotorbikeID(ctx context.Context, motorbikeID int64, at time.Time) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.BluetoothConnection)(nil)).
+		Set("disconnected_at = ?", at).
+		Where("motorbike_id = ? AND disconnected_at IS NULL", motorbikeID).
+		Exec(ctx)
+	return err
+}
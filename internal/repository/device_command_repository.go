@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IDeviceCommandRepository interface {
+	Create(ctx context.Context, command *model.DeviceCommand) error
+	GetByID(ctx context.Context, id int64) (*model.DeviceCommand, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.DeviceCommand, error)
+	ListDeliverable(ctx context.Context, motorbikeID int64, now time.Time) ([]model.DeviceCommand, error)
+	ListTimedOut(ctx context.Context, now time.Time, limit int) ([]model.DeviceCommand, error)
+	ListByMotorbikeID(ctx context.Context, motorbikeID int64, limit int) ([]model.DeviceCommand, error)
+	Update(ctx context.Context, command *model.DeviceCommand) error
+	AddEvent(ctx context.Context, event *model.DeviceCommandEvent) error
+}
+
+type DeviceCommandRepository struct {
+	db *bun.DB
+}
+
+func NewDeviceCommandRepository(db *bun.DB) IDeviceCommandRepository {
+	return &DeviceCommandRepository{db: db}
+}
+
+func (r *DeviceCommandRepository) Create(ctx context.Context, command *model.DeviceCommand) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(command).Exec(ctx)
+	return err
+}
+
+func (r *DeviceCommandRepository) GetByID(ctx context.Context, id int64) (*model.DeviceCommand, error) {
+	var command model.DeviceCommand
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&command).
+		Relation("Events", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("dce.id ASC")
+		}).
+		Where("dc.id = ?", id).
+		Scan(ctx)
+	return &command, err
+}
+
+// GetByIDForUpdate komutu satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *DeviceCommandRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.DeviceCommand, error) {
+	var command model.DeviceCommand
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&command).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &command, err
+}
+
+// ListDeliverable motora gönderilecek komutları oluşturulma sırasıyla kilitleyerek getirir: bekleyen komutlar ve
+// onay süresi dolmuş, deneme hakkı kalan gönderilmiş komutlar. Aynı motoru sorgulayan başka bir istek
+// kilitli satırları atlar, böylece bir komut aynı anda iki kez teslim edilmez.
+func (r *DeviceCommandRepository) ListDeliverable(ctx context.Context, motorbikeID int64, now time.Time) ([]model.DeviceCommand, error) {
+	var commands []model.DeviceCommand
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&commands).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("expires_at > ?", now).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("status = ?", model.CommandPending).
+				WhereOr("status = ? AND ack_deadline <= ? AND attempts < max_attempts", model.CommandSent, now)
+		}).
+		Order("created_at ASC", "id ASC").
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	return commands, err
+}
+
+// ListTimedOut süresi dolmuş veya son denemesinin onay süresi geçmiş, henüz kapatılmamış komutları getirir
+func (r *DeviceCommandRepository) ListTimedOut(ctx context.Context, now time.Time, limit int) ([]model.DeviceCommand, error) {
+	var commands []model.DeviceCommand
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&commands).
+		Where("status IN (?)", bun.In([]model.DeviceCommandStatus{model.CommandPending, model.CommandSent})).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("expires_at <= ?", now).
+				WhereOr("status = ? AND ack_deadline <= ? AND attempts >= max_attempts", model.CommandSent, now)
+		}).
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx)
+	return commands, err
+}
+
+// ListByMotorbikeID motorun son komutlarını durum geçmişiyle birlikte yeniden eskiye getirir
+func (r *DeviceCommandRepository) ListByMotorbikeID(ctx context.Context, motorbikeID int64, limit int) ([]model.DeviceCommand, error) {
+	var commands []model.DeviceCommand
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&commands).
+		Relation("Events", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("dce.id ASC")
+		}).
+		Where("dc.motorbike_id = ?", motorbikeID).
+		Order("dc.id DESC").
+		Limit(limit).
+		Scan(ctx)
+	return commands, err
+}
+
+func (r *DeviceCommandRepository) Update(ctx context.Context, command *model.DeviceCommand) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(command).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *DeviceCommandRepository) AddEvent(ctx context.Context, event *model.DeviceCommandEvent) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(event).Exec(ctx)
+	return err
+}
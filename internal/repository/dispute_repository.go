@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type IDisputeRepository interface {
+	Create(ctx context.Context, dispute *model.Dispute) error
+	GetByID(ctx context.Context, id int64) (*model.Dispute, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Dispute, error)
+	GetActiveByRideID(ctx context.Context, rideID int64) (*model.Dispute, error)
+	Update(ctx context.Context, dispute *model.Dispute) error
+	List(ctx context.Context, filter model.DisputeFilter, pagination *query.Pagination) ([]model.Dispute, error)
+	ListByUserID(ctx context.Context, userID int64) ([]model.Dispute, error)
+	CreatePhotos(ctx context.Context, photos []model.DisputePhoto) error
+	GetPhoto(ctx context.Context, disputeID, photoID int64) (*model.DisputePhoto, error)
+	CreateEvent(ctx context.Context, event *model.DisputeEvent) error
+	CreateAdjustment(ctx context.Context, adjustment *model.RideAdjustment) error
+	CompleteAdjustmentRefund(ctx context.Context, id int64, status model.ProviderRefundStatus, refundID string) error
+	ListAdjustmentsByRideID(ctx context.Context, rideID int64) ([]model.RideAdjustment, error)
+}
+
+type DisputeRepository struct {
+	db *bun.DB
+}
+
+func NewDisputeRepository(db *bun.DB) IDisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+func (r *DisputeRepository) Create(ctx context.Context, dispute *model.Dispute) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(dispute).Exec(ctx)
+	return err
+}
+
+// GetByID itirazı fotoğrafları ve durum geçmişiyle birlikte getirir
+func (r *DisputeRepository) GetByID(ctx context.Context, id int64) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&dispute).
+		Relation("Photos", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("dp.id ASC")
+		}).
+		Relation("Events", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("de.id ASC")
+		}).
+		Where("d.id = ?", id).
+		Scan(ctx)
+	return &dispute, err
+}
+
+// GetByIDForUpdate itirazı satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *DisputeRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&dispute).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &dispute, err
+}
+
+// GetActiveByRideID sürüşün sonuçlanmamış itirazını getirir, yoksa nil döner
+func (r *DisputeRepository) GetActiveByRideID(ctx context.Context, rideID int64) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&dispute).
+		Where("ride_id = ?", rideID).
+		Where("status IN (?)", bun.In([]model.DisputeStatus{model.DisputeOpen, model.DisputeInReview})).
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &dispute, err
+}
+
+func (r *DisputeRepository) Update(ctx context.Context, dispute *model.Dispute) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(dispute).ExcludeColumn("created_at").WherePK().Exec(ctx)
+	return err
+}
+
+// List destek kuyruğunu eskiden yeniye sayfalı getirir, böylece en uzun bekleyen itiraz başta olur
+func (r *DisputeRepository) List(ctx context.Context, filter model.DisputeFilter, pagination *query.Pagination) ([]model.Dispute, error) {
+	var disputes []model.Dispute
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&disputes)
+	if filter.Status != "" {
+		q = q.Where("d.status = ?", filter.Status)
+	}
+	if filter.Reason != "" {
+		q = q.Where("d.reason = ?", filter.Reason)
+	}
+	if filter.UserID != 0 {
+		q = q.Where("d.user_id = ?", filter.UserID)
+	}
+	if filter.RideID != 0 {
+		q = q.Where("d.ride_id = ?", filter.RideID)
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+	if err := query.ApplyPagination(q.Order("d.created_at ASC", "d.id ASC"), *pagination).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// ListByUserID kullanıcının itirazlarını yeniden eskiye getirir
+func (r *DisputeRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Dispute, error) {
+	var disputes []model.Dispute
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&disputes).
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return disputes, err
+}
+
+func (r *DisputeRepository) CreatePhotos(ctx context.Context, photos []model.DisputePhoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&photos).Exec(ctx)
+	return err
+}
+
+// GetPhoto itirazın fotoğrafını getirir, fotoğraf bu itiraza ait değilse nil döner
+func (r *DisputeRepository) GetPhoto(ctx context.Context, disputeID, photoID int64) (*model.DisputePhoto, error) {
+	var photo model.DisputePhoto
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&photo).
+		Where("id = ?", photoID).
+		Where("dispute_id = ?", disputeID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &photo, err
+}
+
+func (r *DisputeRepository) CreateEvent(ctx context.Context, event *model.DisputeEvent) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(event).Exec(ctx)
+	return err
+}
+
+func (r *DisputeRepository) CreateAdjustment(ctx context.Context, adjustment *model.RideAdjustment) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(adjustment).Exec(ctx)
+	return err
+}
+
+// CompleteAdjustmentRefund bekleyen karta iadenin sağlayıcıdaki sonucunu yazar. Sonucu yazılmış kayıt değiştirilmez.
+func (r *DisputeRepository) CompleteAdjustmentRefund(ctx context.Context, id int64, status model.ProviderRefundStatus, refundID string) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.RideAdjustment)(nil)).
+		Set("provider_refund_status = ?", status).
+		Set("provider_refund_id = NULLIF(?, '')", refundID).
+		Where("id = ?", id).
+		Where("provider_refund_status = ?", model.ProviderRefundPending).
+		Exec(ctx)
+	return err
+}
+
+func (r *DisputeRepository) ListAdjustmentsByRideID(ctx context.Context, rideID int64) ([]model.RideAdjustment, error) {
+	var adjustments []model.RideAdjustment
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&adjustments).
+		Where("ride_id = ?", rideID).
+		Order("id ASC").
+		Scan(ctx)
+	return adjustments, err
+}
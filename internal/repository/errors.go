@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// ErrDuplicate kayıt bir unique kısıtını ihlal ettiğinde döner, ör. motorun veya kullanıcının ikinci bitirilmemiş sürüşü
+var ErrDuplicate = errors.New("kayıt zaten mevcut")
+
+// uniqueViolation Postgres'in unique kısıt ihlalini (23505) kısıt adıyla birlikte ErrDuplicate'e çevirir,
+// diğer hataları olduğu gibi döner
+func uniqueViolation(err error) error {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+		return fmt.Errorf("%w: %s", ErrDuplicate, pgErr.Field('n'))
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IInvoiceRepository interface {
+	Create(ctx context.Context, invoice *model.Invoice) error
+	GetByID(ctx context.Context, id int64) (*model.Invoice, error)
+	GetByRideID(ctx context.Context, rideID int64) (*model.Invoice, error)
+	GetMonthly(ctx context.Context, userID int64, periodStart time.Time) (*model.Invoice, error)
+	ListByUserID(ctx context.Context, userID int64) ([]model.Invoice, error)
+	ListUnsent(ctx context.Context, maxAttempts, limit int) ([]model.Invoice, error)
+	UpdateEmailStatus(ctx context.Context, invoice *model.Invoice) error
+	NextNumber(ctx context.Context, kind model.InvoiceKind) (int64, error)
+}
+
+type InvoiceRepository struct {
+	db *bun.DB
+}
+
+func NewInvoiceRepository(db *bun.DB) IInvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *model.Invoice) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(invoice).Exec(ctx)
+	return err
+}
+
+func (r *InvoiceRepository) GetByID(ctx context.Context, id int64) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&invoice).Where("id = ?", id).Scan(ctx)
+	return &invoice, err
+}
+
+// GetByRideID sürüşün fişini getirir, fiş kesilmemişse nil döner
+func (r *InvoiceRepository) GetByRideID(ctx context.Context, rideID int64) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&invoice).
+		Where("kind = ?", model.InvoiceReceipt).
+		Where("ride_id = ?", rideID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &invoice, err
+}
+
+// GetMonthly kullanıcının periodStart ile başlayan aya ait faturasını getirir, fatura kesilmemişse nil döner
+func (r *InvoiceRepository) GetMonthly(ctx context.Context, userID int64, periodStart time.Time) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&invoice).
+		Where("kind = ?", model.InvoiceMonthly).
+		Where("user_id = ?", userID).
+		Where("period_start = ?", periodStart).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &invoice, err
+}
+
+// ListByUserID kullanıcının belgelerini belge içerikleri olmadan, yeniden eskiye getirir
+func (r *InvoiceRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&invoices).
+		ExcludeColumn("html", "pdf").
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return invoices, err
+}
+
+// ListUnsent e-postası gönderilemeyen ve deneme hakkı kalan belgeleri getirir
+func (r *InvoiceRepository) ListUnsent(ctx context.Context, maxAttempts, limit int) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&invoices).
+		Where("emailed_at IS NULL").
+		Where("email_attempts < ?", maxAttempts).
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx)
+	return invoices, err
+}
+
+func (r *InvoiceRepository) UpdateEmailStatus(ctx context.Context, invoice *model.Invoice) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model(invoice).
+		Column("emailed_at", "email_attempts", "email_error").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// NextNumber belge türünün sıradaki numarasını veritabanı sequence'ından alır
+func (r *InvoiceRepository) NextNumber(ctx context.Context, kind model.InvoiceKind) (int64, error) {
+	sequence := "receipt_number_seq"
+	if kind == model.InvoiceMonthly {
+		sequence = "invoice_number_seq"
+	}
+	var number int64
+	err := dbFromContext(ctx, r.db).NewSelect().ColumnExpr("nextval(?)", sequence).Scan(ctx, &number)
+	return number, err
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type IIssueRepository interface {
+	Create(ctx context.Context, issue *model.Issue) error
+	GetByID(ctx context.Context, id int64) (*model.Issue, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Issue, error)
+	Update(ctx context.Context, issue *model.Issue) error
+	List(ctx context.Context, filter model.IssueFilter, pagination *query.Pagination) ([]model.Issue, error)
+	ListByUserID(ctx context.Context, userID int64) ([]model.Issue, error)
+	ListDuplicates(ctx context.Context, issueID int64) ([]model.Issue, error)
+	ReassignDuplicates(ctx context.Context, fromID, toID int64) error
+	GetOpenByUser(ctx context.Context, motorbikeID, userID int64, category model.IssueCategory) (*model.Issue, error)
+	ListUnflaggedByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.Issue, error)
+	ListUnflaggedMotorbikeIDs(ctx context.Context) ([]int64, error)
+	SetWorkOrder(ctx context.Context, issueIDs []int64, workOrderID int64) error
+	CreatePhotos(ctx context.Context, photos []model.IssuePhoto) error
+}
+
+type IssueRepository struct {
+	db *bun.DB
+}
+
+func NewIssueRepository(db *bun.DB) IIssueRepository {
+	return &IssueRepository{db: db}
+}
+
+func (r *IssueRepository) Create(ctx context.Context, issue *model.Issue) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(issue).Exec(ctx)
+	return err
+}
+
+// GetByID bildirimi fotoğraflarıyla birlikte getirir
+func (r *IssueRepository) GetByID(ctx context.Context, id int64) (*model.Issue, error) {
+	var issue model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&issue).
+		Relation("Photos", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("ip.id ASC")
+		}).
+		Where("i.id = ?", id).
+		Scan(ctx)
+	return &issue, err
+}
+
+// GetByIDForUpdate bildirimi satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *IssueRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Issue, error) {
+	var issue model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&issue).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &issue, err
+}
+
+func (r *IssueRepository) Update(ctx context.Context, issue *model.Issue) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(issue).ExcludeColumn("created_at").WherePK().Exec(ctx)
+	return err
+}
+
+// List triyaj kuyruğunu en ağır bildirim, sonra en eski bildirim başta olacak şekilde sayfalı getirir
+func (r *IssueRepository) List(ctx context.Context, filter model.IssueFilter, pagination *query.Pagination) ([]model.Issue, error) {
+	var issues []model.Issue
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&issues)
+	if filter.Status != "" {
+		q = q.Where("i.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		q = q.Where("i.category = ?", filter.Category)
+	}
+	if filter.Severity != "" {
+		q = q.Where("i.severity = ?", filter.Severity)
+	}
+	if filter.MotorbikeID != 0 {
+		q = q.Where("i.motorbike_id = ?", filter.MotorbikeID)
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+	severity := "CASE i.severity WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END"
+	if err := query.ApplyPagination(q.OrderExpr(severity).Order("i.created_at ASC", "i.id ASC"), *pagination).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// ListByUserID kullanıcının bildirimlerini yeniden eskiye getirir
+func (r *IssueRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Issue, error) {
+	var issues []model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&issues).
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return issues, err
+}
+
+// ListDuplicates bildirimin kopyası olarak işaretlenen bildirimleri getirir
+func (r *IssueRepository) ListDuplicates(ctx context.Context, issueID int64) ([]model.Issue, error) {
+	var issues []model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&issues).
+		Where("duplicate_of_id = ?", issueID).
+		Order("id ASC").
+		Scan(ctx)
+	return issues, err
+}
+
+// ReassignDuplicates fromID'nin kopyalarını toID'ye bağlar
+func (r *IssueRepository) ReassignDuplicates(ctx context.Context, fromID, toID int64) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.Issue)(nil)).
+		Set("duplicate_of_id = ?", toID).
+		Where("duplicate_of_id = ?", fromID).
+		Exec(ctx)
+	return err
+}
+
+// GetOpenByUser kullanıcının motor için aynı kategorideki açık bildirimini getirir, yoksa nil döner
+func (r *IssueRepository) GetOpenByUser(ctx context.Context, motorbikeID, userID int64, category model.IssueCategory) (*model.Issue, error) {
+	var issue model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&issue).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("user_id = ?", userID).
+		Where("category = ?", category).
+		Where("status = ?", model.IssueOpen).
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &issue, err
+}
+
+// ListUnflaggedByMotorbikeID motorun henüz bir iş emrine bağlanmamış açık bildirimlerini eskiden yeniye getirir
+func (r *IssueRepository) ListUnflaggedByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.Issue, error) {
+	var issues []model.Issue
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&issues).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("status = ?", model.IssueOpen).
+		Where("work_order_id IS NULL").
+		Order("id ASC").
+		Scan(ctx)
+	return issues, err
+}
+
+// ListUnflaggedMotorbikeIDs iş emrine bağlanmamış açık bildirimi olan motorları getirir
+func (r *IssueRepository) ListUnflaggedMotorbikeIDs(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Issue)(nil)).
+		ColumnExpr("DISTINCT motorbike_id").
+		Where("status = ?", model.IssueOpen).
+		Where("work_order_id IS NULL").
+		Scan(ctx, &ids)
+	return ids, err
+}
+
+func (r *IssueRepository) SetWorkOrder(ctx context.Context, issueIDs []int64, workOrderID int64) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.Issue)(nil)).
+		Set("work_order_id = ?", workOrderID).
+		Where("id IN (?)", bun.In(issueIDs)).
+		Exec(ctx)
+	return err
+}
+
+func (r *IssueRepository) CreatePhotos(ctx context.Context, photos []model.IssuePhoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&photos).Exec(ctx)
+	return err
+}
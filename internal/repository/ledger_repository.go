@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type ILedgerRepository interface {
+	GetUserAccount(ctx context.Context, userID int64) (*model.LedgerAccount, error)
+	EnsureUserAccount(ctx context.Context, userID int64, currency string) (*model.LedgerAccount, error)
+	EnsureSystemAccount(ctx context.Context, code, currency string) (*model.LedgerAccount, error)
+	CreateTransaction(ctx context.Context, transaction *model.LedgerTransaction) error
+	GetBalance(ctx context.Context, accountID int64) (int64, error)
+	ListAccountTransactions(ctx context.Context, accountID int64, pagination *query.Pagination) ([]model.WalletTransaction, error)
+}
+
+type LedgerRepository struct {
+	db *bun.DB
+}
+
+func NewLedgerRepository(db *bun.DB) ILedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// GetUserAccount kullanıcının cüzdan hesabını getirir, henüz hesap açılmadıysa nil döner
+func (r *LedgerRepository) GetUserAccount(ctx context.Context, userID int64) (*model.LedgerAccount, error) {
+	var accounts []model.LedgerAccount
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&accounts).
+		Where("type = ?", model.AccountUserWallet).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	return &accounts[0], nil
+}
+
+// EnsureUserAccount kullanıcının cüzdan hesabını yoksa oluşturur ve satır kilidiyle getirir.
+// Aynı cüzdana yazan işlemler bu kilitle sıralanır, transaction içinde kullanılmalıdır.
+func (r *LedgerRepository) EnsureUserAccount(ctx context.Context, userID int64, currency string) (*model.LedgerAccount, error) {
+	account := &model.LedgerAccount{Type: model.AccountUserWallet, UserID: &userID, Currency: currency}
+	return r.ensure(ctx, account, true, "type = ? AND user_id = ?", model.AccountUserWallet, userID)
+}
+
+// EnsureSystemAccount para birimine ait sistem hesabını yoksa oluşturur ve getirir. Sistem hesapları
+// bütün işlemlerde ortak olduğu için kilitlenmez; bakiyeleri yalnızca raporlamada kullanılır.
+func (r *LedgerRepository) EnsureSystemAccount(ctx context.Context, code, currency string) (*model.LedgerAccount, error) {
+	account := &model.LedgerAccount{Type: model.AccountSystem, Code: code, Currency: currency}
+	return r.ensure(ctx, account, false, "type = ? AND code = ? AND currency = ?", model.AccountSystem, code, currency)
+}
+
+func (r *LedgerRepository) ensure(ctx context.Context, account *model.LedgerAccount, lock bool, where string, args ...interface{}) (*model.LedgerAccount, error) {
+	db := dbFromContext(ctx, r.db)
+	if _, err := db.NewInsert().Model(account).On("CONFLICT DO NOTHING").Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var existing model.LedgerAccount
+	q := db.NewSelect().Model(&existing).Where(where, args...)
+	if lock {
+		q = q.For("UPDATE")
+	}
+	err := q.Scan(ctx)
+	return &existing, err
+}
+
+// CreateTransaction işlemi kayıtlarıyla birlikte ekler. Kayıtların toplamı veritabanında da kontrol edilir.
+func (r *LedgerRepository) CreateTransaction(ctx context.Context, transaction *model.LedgerTransaction) error {
+	db := dbFromContext(ctx, r.db)
+	if _, err := db.NewInsert().Model(transaction).Exec(ctx); err != nil {
+		return err
+	}
+	for i := range transaction.Entries {
+		transaction.Entries[i].TransactionID = transaction.ID
+	}
+	_, err := db.NewInsert().Model(&transaction.Entries).Exec(ctx)
+	return err
+}
+
+// GetBalance hesabın bakiyesini kayıtlarının toplamından hesaplar
+func (r *LedgerRepository) GetBalance(ctx context.Context, accountID int64) (int64, error) {
+	var balance int64
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.LedgerEntry)(nil)).
+		ColumnExpr("COALESCE(SUM(amount), 0)").
+		Where("account_id = ?", accountID).
+		Scan(ctx, &balance)
+	return balance, err
+}
+
+// ListAccountTransactions hesabın hareketlerini yeniden eskiye, her hareketten sonraki bakiyeyle birlikte sayfalı getirir
+func (r *LedgerRepository) ListAccountTransactions(ctx context.Context, accountID int64, pagination *query.Pagination) ([]model.WalletTransaction, error) {
+	db := dbFromContext(ctx, r.db)
+	q := db.NewSelect().
+		TableExpr("ledger_entries AS le").
+		Join("JOIN ledger_transactions AS lt ON lt.id = le.transaction_id").
+		ColumnExpr("lt.id AS transaction_id, lt.kind, COALESCE(lt.description, '') AS description, lt.ride_id").
+		ColumnExpr("le.amount, lt.created_at").
+		ColumnExpr("SUM(le.amount) OVER (ORDER BY le.id) AS balance_after").
+		Where("le.account_id = ?", accountID)
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+
+	// Bakiye tüm hareketler üzerinden hesaplandıktan sonra sayfalanır
+	page := db.NewSelect().
+		TableExpr("(?) AS wt", q).
+		ColumnExpr("wt.*").
+		OrderExpr("wt.transaction_id DESC")
+
+	var transactions []model.WalletTransaction
+	err := query.ApplyPagination(page, *pagination).Scan(ctx, &transactions)
+	return transactions, err
+}
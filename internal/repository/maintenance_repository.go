@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type IMaintenanceRepository interface {
+	CreateWorkOrder(ctx context.Context, order *model.WorkOrder) error
+	GetWorkOrder(ctx context.Context, id int64) (*model.WorkOrder, error)
+	GetWorkOrderForUpdate(ctx context.Context, id int64) (*model.WorkOrder, error)
+	UpdateWorkOrder(ctx context.Context, order *model.WorkOrder) error
+	ListWorkOrders(ctx context.Context, filter model.WorkOrderFilter, pagination *query.Pagination) ([]model.WorkOrder, error)
+	ListWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error)
+	ListOpenWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error)
+	GetLastCompletedWorkOrder(ctx context.Context, motorbikeID int64, orderType model.MaintenanceType) (*model.WorkOrder, error)
+	ReplaceParts(ctx context.Context, workOrderID int64, parts []model.WorkOrderPart) error
+
+	CreateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error
+	GetSchedule(ctx context.Context, id int64) (*model.MaintenanceSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error
+	DeleteSchedule(ctx context.Context, id int64) error
+	ListSchedules(ctx context.Context, activeOnly bool) ([]model.MaintenanceSchedule, error)
+}
+
+type MaintenanceRepository struct {
+	db *bun.DB
+}
+
+func NewMaintenanceRepository(db *bun.DB) IMaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+func (r *MaintenanceRepository) CreateWorkOrder(ctx context.Context, order *model.WorkOrder) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(order).Exec(ctx)
+	return err
+}
+
+// GetWorkOrder iş emrini kullanılan parçalarla birlikte getirir
+func (r *MaintenanceRepository) GetWorkOrder(ctx context.Context, id int64) (*model.WorkOrder, error) {
+	var order model.WorkOrder
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&order).
+		Relation("Parts", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("wop.id ASC")
+		}).
+		Where("wo.id = ?", id).
+		Scan(ctx)
+	return &order, err
+}
+
+// GetWorkOrderForUpdate iş emrini satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *MaintenanceRepository) GetWorkOrderForUpdate(ctx context.Context, id int64) (*model.WorkOrder, error) {
+	var order model.WorkOrder
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&order).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &order, err
+}
+
+func (r *MaintenanceRepository) UpdateWorkOrder(ctx context.Context, order *model.WorkOrder) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(order).ExcludeColumn("created_at").WherePK().Exec(ctx)
+	return err
+}
+
+// ListWorkOrders iş emirlerini önceliğe, sonra açılış sırasına göre sayfalı getirir
+func (r *MaintenanceRepository) ListWorkOrders(ctx context.Context, filter model.WorkOrderFilter, pagination *query.Pagination) ([]model.WorkOrder, error) {
+	var orders []model.WorkOrder
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&orders)
+	if filter.Status != "" {
+		q = q.Where("wo.status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		q = q.Where("wo.type = ?", filter.Type)
+	}
+	if filter.MotorbikeID != 0 {
+		q = q.Where("wo.motorbike_id = ?", filter.MotorbikeID)
+	}
+	if filter.TechnicianID != 0 {
+		q = q.Where("wo.technician_id = ?", filter.TechnicianID)
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+	priority := "CASE wo.priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END"
+	if err := query.ApplyPagination(q.OrderExpr(priority).Order("wo.created_at ASC", "wo.id ASC"), *pagination).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListWorkOrdersByMotorbikeID motorun servis geçmişini parçalarla birlikte yeniden eskiye getirir
+func (r *MaintenanceRepository) ListWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error) {
+	var orders []model.WorkOrder
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&orders).
+		Relation("Parts", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("wop.id ASC")
+		}).
+		Where("wo.motorbike_id = ?", motorbikeID).
+		Order("wo.created_at DESC", "wo.id DESC").
+		Scan(ctx)
+	return orders, err
+}
+
+// ListOpenWorkOrdersByMotorbikeID motorun kapanmamış iş emirlerini getirir
+func (r *MaintenanceRepository) ListOpenWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error) {
+	var orders []model.WorkOrder
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&orders).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("status IN (?)", bun.In(model.OpenWorkOrderStatuses)).
+		Order("id ASC").
+		Scan(ctx)
+	return orders, err
+}
+
+// GetLastCompletedWorkOrder motorun verilen türdeki son tamamlanan iş emrini getirir, yoksa nil döner
+func (r *MaintenanceRepository) GetLastCompletedWorkOrder(ctx context.Context, motorbikeID int64, orderType model.MaintenanceType) (*model.WorkOrder, error) {
+	var order model.WorkOrder
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&order).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("type = ?", orderType).
+		Where("status = ?", model.WorkOrderCompleted).
+		Order("closed_at DESC", "id DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &order, err
+}
+
+// ReplaceParts iş emrinin parça listesini verilen listeyle değiştirir
+func (r *MaintenanceRepository) ReplaceParts(ctx context.Context, workOrderID int64, parts []model.WorkOrderPart) error {
+	db := dbFromContext(ctx, r.db)
+	if _, err := db.NewDelete().Model((*model.WorkOrderPart)(nil)).Where("work_order_id = ?", workOrderID).Exec(ctx); err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	_, err := db.NewInsert().Model(&parts).Exec(ctx)
+	return err
+}
+
+func (r *MaintenanceRepository) CreateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(schedule).Exec(ctx)
+	return err
+}
+
+func (r *MaintenanceRepository) GetSchedule(ctx context.Context, id int64) (*model.MaintenanceSchedule, error) {
+	var schedule model.MaintenanceSchedule
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&schedule).Where("id = ?", id).Scan(ctx)
+	return &schedule, err
+}
+
+func (r *MaintenanceRepository) UpdateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(schedule).ExcludeColumn("created_at").WherePK().Exec(ctx)
+	return err
+}
+
+func (r *MaintenanceRepository) DeleteSchedule(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.MaintenanceSchedule)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (r *MaintenanceRepository) ListSchedules(ctx context.Context, activeOnly bool) ([]model.MaintenanceSchedule, error) {
+	var schedules []model.MaintenanceSchedule
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&schedules)
+	if activeOnly {
+		q = q.Where("is_active")
+	}
+	err := q.Order("id ASC").Scan(ctx)
+	return schedules, err
+}
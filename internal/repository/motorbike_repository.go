@@ -3,17 +3,26 @@ package repository
 import (
 	"context"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
 	"github.com/uptrace/bun"
 )
 
 type IMotorbikeRepository interface {
 	Create(ctx context.Context, motorbike *model.Motorbike) error
 	GetByID(ctx context.Context, id int64) (*model.Motorbike, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Motorbike, error)
+	GetByDeviceKeyHash(ctx context.Context, hash string) (*model.Motorbike, error)
 	Update(ctx context.Context, motorbike *model.Motorbike) error
+	UpdateColumns(ctx context.Context, motorbike *model.Motorbike, columns ...string) error
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context) ([]model.Motorbike, error)
 	GetMotorsForStatus(ctx context.Context, status string) ([]model.Motorbike, error)
-	GetPhotosByID(ctx context.Context, motorbikeID string) ([]model.MotorbikePhoto, error)
+	ListAvailableInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Motorbike, error)
+	ListPhotos(ctx context.Context, motorbikeID int64) ([]model.MotorbikePhoto, error)
+	ListPrimaryPhotos(ctx context.Context, motorbikeIDs []int64) ([]model.MotorbikePhoto, error)
+	CreatePhotos(ctx context.Context, photos []model.MotorbikePhoto) error
+	UpdatePhotoColumns(ctx context.Context, photo *model.MotorbikePhoto, columns ...string) error
+	DeletePhoto(ctx context.Context, photoID int64) error
 }
 
 type MotorbikeRepository struct {
@@ -25,45 +34,112 @@ func NewMotorbikeRepository(db *bun.DB) IMotorbikeRepository {
 }
 
 func (r *MotorbikeRepository) Create(ctx context.Context, motorbike *model.Motorbike) error {
-	_, err := r.db.NewInsert().Model(motorbike).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(motorbike).Exec(ctx)
 	return err
 }
 
 func (r *MotorbikeRepository) GetByID(ctx context.Context, id int64) (*model.Motorbike, error) {
 	var motorbike model.Motorbike
-	err := r.db.NewSelect().Model(&motorbike).Where("id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&motorbike).Where("id = ?", id).Scan(ctx)
 	return &motorbike, err
 }
 
+// GetByIDForUpdate motoru satır kilidiyle (SELECT ... FOR UPDATE) getirir, transaction içinde kullanılmalıdır
+func (r *MotorbikeRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Motorbike, error) {
+	var motorbike model.Motorbike
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&motorbike).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &motorbike, err
+}
+
+func (r *MotorbikeRepository) GetByDeviceKeyHash(ctx context.Context, hash string) (*model.Motorbike, error) {
+	var motorbike model.Motorbike
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&motorbike).Where("device_key_hash = ?", hash).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return &motorbike, nil
+}
+
 func (r *MotorbikeRepository) Update(ctx context.Context, motorbike *model.Motorbike) error {
-	_, err := r.db.NewUpdate().Model(motorbike).WherePK().Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(motorbike).WherePK().Exec(ctx)
+	return err
+}
+
+// UpdateColumns yalnızca verilen kolonları günceller; eşzamanlı güncellenen diğer alanların ezilmesini önler
+func (r *MotorbikeRepository) UpdateColumns(ctx context.Context, motorbike *model.Motorbike, columns ...string) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(motorbike).Column(columns...).WherePK().Exec(ctx)
 	return err
 }
 
 func (r *MotorbikeRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().Model((*model.Motorbike)(nil)).Where("id = ?", id).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.Motorbike)(nil)).Where("id = ?", id).Exec(ctx)
 	return err
 }
 
 func (r *MotorbikeRepository) List(ctx context.Context) ([]model.Motorbike, error) {
 	var motorbikes []model.Motorbike
-	err := r.db.NewSelect().Model(&motorbikes).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&motorbikes).Scan(ctx)
 	return motorbikes, err
 }
 
 func (r *MotorbikeRepository) GetMotorsForStatus(ctx context.Context, status string) ([]model.Motorbike, error) {
 	var motorbikes []model.Motorbike
-	if err := r.db.NewSelect().Model(&motorbikes).Where("status = ?", status).Scan(ctx); err != nil {
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&motorbikes).Where("status = ?", status).Scan(ctx); err != nil {
 		return nil, err
 	}
 	return motorbikes, nil
 }
 
-func (r *MotorbikeRepository) GetPhotosByID(ctx context.Context, motorbikeID string) ([]model.MotorbikePhoto, error) {
-	var motorbikePhotos []model.MotorbikePhoto
-	if err := r.db.NewSelect().Model(&motorbikePhotos).Where("motorbike_id = ?", motorbikeID).Scan(ctx); err != nil {
+// ListAvailableInBounds dikdörtgen alan içindeki müsait motorları getirir (konum indeksini kullanan ön filtre)
+func (r *MotorbikeRepository) ListAvailableInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Motorbike, error) {
+	var motorbikes []model.Motorbike
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&motorbikes).
+		Where("status = ?", model.BikeAvailable).
+		Where("location_latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat).
+		Where("location_longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng).
+		Scan(ctx)
+	if err != nil {
 		return nil, err
 	}
+	return motorbikes, nil
+}
+
+// ListPhotos motorun galerisini sırasına göre getirir
+func (r *MotorbikeRepository) ListPhotos(ctx context.Context, motorbikeID int64) ([]model.MotorbikePhoto, error) {
+	var photos []model.MotorbikePhoto
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&photos).
+		Where("motorbike_id = ?", motorbikeID).
+		Order("position ASC", "id ASC").
+		Scan(ctx)
+	return photos, err
+}
 
-	return motorbikePhotos, nil
+// ListPrimaryPhotos verilen motorların kapak fotoğraflarını getirir; kapağı olmayan motorlar sonuçta yer almaz
+func (r *MotorbikeRepository) ListPrimaryPhotos(ctx context.Context, motorbikeIDs []int64) ([]model.MotorbikePhoto, error) {
+	var photos []model.MotorbikePhoto
+	if len(motorbikeIDs) == 0 {
+		return photos, nil
+	}
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&photos).
+		Where("motorbike_id IN (?)", bun.In(motorbikeIDs)).
+		Where("is_primary").
+		Scan(ctx)
+	return photos, err
+}
+
+func (r *MotorbikeRepository) CreatePhotos(ctx context.Context, photos []model.MotorbikePhoto) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&photos).Exec(ctx)
+	return err
+}
+
+func (r *MotorbikeRepository) UpdatePhotoColumns(ctx context.Context, photo *model.MotorbikePhoto, columns ...string) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(photo).Column(columns...).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *MotorbikeRepository) DeletePhoto(ctx context.Context, photoID int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.MotorbikePhoto)(nil)).Where("id = ?", photoID).Exec(ctx)
+	return err
 }
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IPassRepository interface {
+	CreateProduct(ctx context.Context, product *model.PassProduct) error
+	GetProductByID(ctx context.Context, id int64) (*model.PassProduct, error)
+	UpdateProduct(ctx context.Context, product *model.PassProduct) error
+	DeleteProduct(ctx context.Context, id int64) error
+	ListProducts(ctx context.Context, activeOnly bool) ([]model.PassProduct, error)
+	CreateSubscription(ctx context.Context, subscription *model.Subscription) error
+	GetSubscriptionByIDForUpdate(ctx context.Context, id int64) (*model.Subscription, error)
+	GetActiveByUserID(ctx context.Context, userID int64) (*model.Subscription, error)
+	GetActiveByUserIDForUpdate(ctx context.Context, userID int64) (*model.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscription *model.Subscription) error
+	ListDueForRenewal(ctx context.Context, now time.Time, limit int) ([]model.Subscription, error)
+	CreateCharge(ctx context.Context, charge *model.SubscriptionCharge) error
+	CreateUsage(ctx context.Context, usage *model.PassUsage) error
+	SumUsageMinutes(ctx context.Context, subscriptionID int64, day time.Time) (int, error)
+}
+
+type PassRepository struct {
+	db *bun.DB
+}
+
+func NewPassRepository(db *bun.DB) IPassRepository {
+	return &PassRepository{db: db}
+}
+
+func (r *PassRepository) CreateProduct(ctx context.Context, product *model.PassProduct) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(product).Exec(ctx)
+	return err
+}
+
+func (r *PassRepository) GetProductByID(ctx context.Context, id int64) (*model.PassProduct, error) {
+	var product model.PassProduct
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&product).Where("id = ?", id).Scan(ctx)
+	return &product, err
+}
+
+func (r *PassRepository) UpdateProduct(ctx context.Context, product *model.PassProduct) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(product).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *PassRepository) DeleteProduct(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.PassProduct)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (r *PassRepository) ListProducts(ctx context.Context, activeOnly bool) ([]model.PassProduct, error) {
+	var products []model.PassProduct
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&products).Order("price ASC")
+	if activeOnly {
+		q = q.Where("is_active = ?", true)
+	}
+	err := q.Scan(ctx)
+	return products, err
+}
+
+func (r *PassRepository) CreateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(subscription).Exec(ctx)
+	return err
+}
+
+// GetSubscriptionByIDForUpdate aboneliği paketiyle birlikte satır kilidiyle getirir
+func (r *PassRepository) GetSubscriptionByIDForUpdate(ctx context.Context, id int64) (*model.Subscription, error) {
+	var subscription model.Subscription
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&subscription).
+		Relation("Product").
+		Where("sub.id = ?", id).
+		For("UPDATE OF sub").
+		Scan(ctx)
+	return &subscription, err
+}
+
+// GetActiveByUserID kullanıcının aktif aboneliğini paketiyle getirir, yoksa nil döner
+func (r *PassRepository) GetActiveByUserID(ctx context.Context, userID int64) (*model.Subscription, error) {
+	return r.getActive(ctx, userID, false)
+}
+
+// GetActiveByUserIDForUpdate aktif aboneliği satır kilidiyle getirir; aynı gün içindeki sürüşlerin
+// dahil dakikaları bu kilitle sırayla düşülür
+func (r *PassRepository) GetActiveByUserIDForUpdate(ctx context.Context, userID int64) (*model.Subscription, error) {
+	return r.getActive(ctx, userID, true)
+}
+
+func (r *PassRepository) getActive(ctx context.Context, userID int64, forUpdate bool) (*model.Subscription, error) {
+	var subscriptions []model.Subscription
+	q := dbFromContext(ctx, r.db).NewSelect().
+		Model(&subscriptions).
+		Relation("Product").
+		Where("sub.user_id = ?", userID).
+		Where("sub.status = ?", model.SubscriptionActive).
+		Limit(1)
+	if forUpdate {
+		q = q.For("UPDATE OF sub")
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+	return &subscriptions[0], nil
+}
+
+func (r *PassRepository) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(subscription).WherePK().Exec(ctx)
+	return err
+}
+
+// ListDueForRenewal dönemi bitmiş ama hâlâ aktif görünen abonelikleri getirir
+func (r *PassRepository) ListDueForRenewal(ctx context.Context, now time.Time, limit int) ([]model.Subscription, error) {
+	var subscriptions []model.Subscription
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&subscriptions).
+		Where("status = ?", model.SubscriptionActive).
+		Where("current_period_end <= ?", now).
+		Order("current_period_end ASC").
+		Limit(limit).
+		Scan(ctx)
+	return subscriptions, err
+}
+
+func (r *PassRepository) CreateCharge(ctx context.Context, charge *model.SubscriptionCharge) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(charge).Exec(ctx)
+	return err
+}
+
+func (r *PassRepository) CreateUsage(ctx context.Context, usage *model.PassUsage) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(usage).Exec(ctx)
+	return err
+}
+
+// SumUsageMinutes aboneliğin verilen gündeki sürüşlerde kullanılan dahil dakikalarının toplamını döner
+func (r *PassRepository) SumUsageMinutes(ctx context.Context, subscriptionID int64, day time.Time) (int, error) {
+	var total int
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.PassUsage)(nil)).
+		ColumnExpr("COALESCE(SUM(minutes), 0)").
+		Where("subscription_id = ?", subscriptionID).
+		Where("usage_date = ?", day.Format(time.DateOnly)).
+		Scan(ctx, &total)
+	return total, err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type IPromotionRepository interface {
+	Create(ctx context.Context, promotion *model.Promotion) error
+	GetByID(ctx context.Context, id int64) (*model.Promotion, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*model.Promotion, error)
+	Update(ctx context.Context, promotion *model.Promotion) error
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context) ([]model.Promotion, error)
+	IncrementRedemptionCount(ctx context.Context, id int64) error
+	CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error)
+	CreateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error
+	ListRedemptions(ctx context.Context, promotionID int64, pagination *query.Pagination) ([]model.PromotionRedemption, error)
+	UsageReport(ctx context.Context, promotionID int64) ([]model.PromotionUsage, error)
+}
+
+type PromotionRepository struct {
+	db *bun.DB
+}
+
+func NewPromotionRepository(db *bun.DB) IPromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, promotion *model.Promotion) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(promotion).Exec(ctx)
+	return err
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id int64) (*model.Promotion, error) {
+	var promotion model.Promotion
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&promotion).Where("id = ?", id).Scan(ctx)
+	return &promotion, err
+}
+
+// GetByIDForUpdate promosyonu satır kilidiyle getirir; toplam kullanım limiti bu kilitle korunur
+func (r *PromotionRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Promotion, error) {
+	var promotion model.Promotion
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&promotion).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &promotion, err
+}
+
+// GetByCode kodu verilen promosyonu getirir, yoksa nil döner
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*model.Promotion, error) {
+	var promotions []model.Promotion
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&promotions).Where("code = ?", code).Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(promotions) == 0 {
+		return nil, nil
+	}
+	return &promotions[0], nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, promotion *model.Promotion) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(promotion).ExcludeColumn("redemption_count").WherePK().Exec(ctx)
+	return err
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.Promotion)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (r *PromotionRepository) List(ctx context.Context) ([]model.Promotion, error) {
+	var promotions []model.Promotion
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&promotions).Order("id DESC").Scan(ctx)
+	return promotions, err
+}
+
+func (r *PromotionRepository) IncrementRedemptionCount(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.Promotion)(nil)).
+		Set("redemption_count = redemption_count + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *PromotionRepository) CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error) {
+	return dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.PromotionRedemption)(nil)).
+		Where("promotion_id = ?", promotionID).
+		Where("user_id = ?", userID).
+		Count(ctx)
+}
+
+func (r *PromotionRepository) CreateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(redemption).Exec(ctx)
+	return err
+}
+
+// ListRedemptions promosyonun kullanımlarını yeniden eskiye sayfalı getirir
+func (r *PromotionRepository) ListRedemptions(ctx context.Context, promotionID int64, pagination *query.Pagination) ([]model.PromotionRedemption, error) {
+	var redemptions []model.PromotionRedemption
+	q := dbFromContext(ctx, r.db).NewSelect().
+		Model(&redemptions).
+		Where("promotion_id = ?", promotionID)
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+
+	err := query.ApplyPagination(q.Order("id DESC"), *pagination).Scan(ctx)
+	return redemptions, err
+}
+
+// UsageReport promosyonların kullanım özetini getirir. promotionID 0 ise tüm promosyonlar döner.
+func (r *PromotionRepository) UsageReport(ctx context.Context, promotionID int64) ([]model.PromotionUsage, error) {
+	q := dbFromContext(ctx, r.db).NewSelect().
+		TableExpr("promotions AS pr").
+		Join("LEFT JOIN promotion_redemptions AS prr ON prr.promotion_id = pr.id").
+		ColumnExpr("pr.id AS promotion_id, pr.code, pr.currency").
+		ColumnExpr("COUNT(prr.id) AS redemptions").
+		ColumnExpr("COUNT(DISTINCT prr.user_id) AS unique_users").
+		ColumnExpr("COALESCE(SUM(prr.amount), 0) AS total_discount").
+		ColumnExpr("MAX(prr.created_at) AS last_used_at").
+		Where("pr.deleted_at IS NULL").
+		GroupExpr("pr.id, pr.code, pr.currency").
+		OrderExpr("pr.id DESC")
+	if promotionID != 0 {
+		q = q.Where("pr.id = ?", promotionID)
+	}
+
+	var usage []model.PromotionUsage
+	err := q.Scan(ctx, &usage)
+	return usage, err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IReferralRepository interface {
+	GetCodeByUserID(ctx context.Context, userID int64) (*model.ReferralCode, error)
+	GetCode(ctx context.Context, code string) (*model.ReferralCode, error)
+	CreateCode(ctx context.Context, code *model.ReferralCode) error
+	Create(ctx context.Context, referral *model.Referral) error
+	GetPendingByRefereeIDForUpdate(ctx context.Context, refereeID int64) (*model.Referral, error)
+	Update(ctx context.Context, referral *model.Referral) error
+	Stats(ctx context.Context, referrerID int64) (*model.ReferralStats, error)
+}
+
+type ReferralRepository struct {
+	db *bun.DB
+}
+
+func NewReferralRepository(db *bun.DB) IReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+// GetCodeByUserID kullanıcının davet kodunu getirir, henüz oluşturulmadıysa nil döner
+func (r *ReferralRepository) GetCodeByUserID(ctx context.Context, userID int64) (*model.ReferralCode, error) {
+	return r.getCode(ctx, "user_id = ?", userID)
+}
+
+// GetCode davet kodunu getirir, yoksa nil döner
+func (r *ReferralRepository) GetCode(ctx context.Context, code string) (*model.ReferralCode, error) {
+	return r.getCode(ctx, "code = ?", code)
+}
+
+func (r *ReferralRepository) getCode(ctx context.Context, where string, arg interface{}) (*model.ReferralCode, error) {
+	var codes []model.ReferralCode
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&codes).Where(where, arg).Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	return &codes[0], nil
+}
+
+func (r *ReferralRepository) CreateCode(ctx context.Context, code *model.ReferralCode) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(code).Exec(ctx)
+	return err
+}
+
+func (r *ReferralRepository) Create(ctx context.Context, referral *model.Referral) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(referral).Exec(ctx)
+	return err
+}
+
+// GetPendingByRefereeIDForUpdate davet edilen kullanıcının ödülü verilmemiş davetini satır kilidiyle getirir, yoksa nil döner
+func (r *ReferralRepository) GetPendingByRefereeIDForUpdate(ctx context.Context, refereeID int64) (*model.Referral, error) {
+	var referrals []model.Referral
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&referrals).
+		Where("referee_id = ?", refereeID).
+		Where("status = ?", model.ReferralPending).
+		For("UPDATE").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(referrals) == 0 {
+		return nil, nil
+	}
+	return &referrals[0], nil
+}
+
+func (r *ReferralRepository) Update(ctx context.Context, referral *model.Referral) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(referral).WherePK().Exec(ctx)
+	return err
+}
+
+// Stats davetlerin özetini getirir. referrerID 0 ise tüm davetler üzerinden hesaplanır.
+func (r *ReferralRepository) Stats(ctx context.Context, referrerID int64) (*model.ReferralStats, error) {
+	q := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Referral)(nil)).
+		ColumnExpr("COUNT(*) AS invited").
+		ColumnExpr("COUNT(*) FILTER (WHERE status = ?) AS rewarded", model.ReferralRewarded).
+		ColumnExpr("COALESCE(SUM(referrer_credit + referee_credit) FILTER (WHERE status = ?), 0) AS credits_issued", model.ReferralRewarded)
+	if referrerID != 0 {
+		q = q.Where("referrer_id = ?", referrerID)
+	}
+
+	var stats model.ReferralStats
+	err := q.Scan(ctx, &stats)
+	return &stats, err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IReservationRepository interface {
+	Create(ctx context.Context, reservation *model.Reservation) error
+	GetByID(ctx context.Context, id int64) (*model.Reservation, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Reservation, error)
+	GetActiveByUserID(ctx context.Context, userID int64) (*model.Reservation, error)
+	GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Reservation, error)
+	GetByRideID(ctx context.Context, rideID int64) (*model.Reservation, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Reservation, error)
+	ListByUserID(ctx context.Context, userID int64) ([]model.Reservation, error)
+	Update(ctx context.Context, reservation *model.Reservation) error
+}
+
+type ReservationRepository struct {
+	db *bun.DB
+}
+
+func NewReservationRepository(db *bun.DB) IReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+func (r *ReservationRepository) Create(ctx context.Context, reservation *model.Reservation) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(reservation).Exec(ctx)
+	return err
+}
+
+func (r *ReservationRepository) GetByID(ctx context.Context, id int64) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&reservation).Where("id = ?", id).Scan(ctx)
+	return &reservation, err
+}
+
+// GetByIDForUpdate rezervasyonu satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *ReservationRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&reservation).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &reservation, err
+}
+
+// GetActiveByUserID kullanıcının aktif rezervasyonunu getirir, yoksa nil döner
+func (r *ReservationRepository) GetActiveByUserID(ctx context.Context, userID int64) (*model.Reservation, error) {
+	return r.getActive(ctx, "user_id = ?", userID)
+}
+
+// GetActiveByMotorbikeID motorun aktif rezervasyonunu getirir, yoksa nil döner
+func (r *ReservationRepository) GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Reservation, error) {
+	return r.getActive(ctx, "motorbike_id = ?", motorbikeID)
+}
+
+func (r *ReservationRepository) getActive(ctx context.Context, where string, arg int64) (*model.Reservation, error) {
+	var reservations []model.Reservation
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&reservations).
+		Where(where, arg).
+		Where("status = ?", model.ReservationActive).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		return nil, nil
+	}
+	return &reservations[0], nil
+}
+
+// GetByRideID sürüşe dönüşen rezervasyonu getirir, yoksa nil döner
+func (r *ReservationRepository) GetByRideID(ctx context.Context, rideID int64) (*model.Reservation, error) {
+	var reservations []model.Reservation
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&reservations).Where("ride_id = ?", rideID).Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		return nil, nil
+	}
+	return &reservations[0], nil
+}
+
+// ListExpired süresi dolmuş ama hâlâ aktif görünen rezervasyonları getirir
+func (r *ReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Reservation, error) {
+	var reservations []model.Reservation
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&reservations).
+		Where("status = ?", model.ReservationActive).
+		Where("expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return reservations, err
+}
+
+func (r *ReservationRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Reservation, error) {
+	var reservations []model.Reservation
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&reservations).Where("user_id = ?", userID).Order("id DESC").Scan(ctx)
+	return reservations, err
+}
+
+func (r *ReservationRepository) Update(ctx context.Context, reservation *model.Reservation) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(reservation).WherePK().Exec(ctx)
+	return err
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IRidePaymentRepository interface {
+	Create(ctx context.Context, payment *model.RidePayment) error
+	GetByRideID(ctx context.Context, rideID int64) (*model.RidePayment, error)
+	GetByAuthorizationID(ctx context.Context, provider, authorizationID string) (*model.RidePayment, error)
+	Update(ctx context.Context, payment *model.RidePayment) error
+}
+
+type RidePaymentRepository struct {
+	db *bun.DB
+}
+
+func NewRidePaymentRepository(db *bun.DB) IRidePaymentRepository {
+	return &RidePaymentRepository{db: db}
+}
+
+func (r *RidePaymentRepository) Create(ctx context.Context, payment *model.RidePayment) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(payment).Exec(ctx)
+	return err
+}
+
+// GetByRideID sürüşün ödeme kaydını getirir, yoksa nil döner
+func (r *RidePaymentRepository) GetByRideID(ctx context.Context, rideID int64) (*model.RidePayment, error) {
+	return r.getOne(ctx, "ride_id = ?", rideID)
+}
+
+// GetByAuthorizationID sağlayıcının provizyon kimliğine ait ödeme kaydını getirir, yoksa nil döner
+func (r *RidePaymentRepository) GetByAuthorizationID(ctx context.Context, provider, authorizationID string) (*model.RidePayment, error) {
+	return r.getOne(ctx, "provider = ? AND authorization_id = ?", provider, authorizationID)
+}
+
+func (r *RidePaymentRepository) getOne(ctx context.Context, where string, args ...interface{}) (*model.RidePayment, error) {
+	var payments []model.RidePayment
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&payments).Where(where, args...).Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, nil
+	}
+	return &payments[0], nil
+}
+
+func (r *RidePaymentRepository) Update(ctx context.Context, payment *model.RidePayment) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(payment).WherePK().Exec(ctx)
+	return err
+}
@@ -4,17 +4,43 @@ import (
 	"context"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/uptrace/bun"
+	"time"
 )
 
 type IRideRepository interface {
 	Create(ctx context.Context, ride *model.Ride) error
 	GetByID(ctx context.Context, id int64) (*model.Ride, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Ride, error)
+	GetActiveByUserID(ctx context.Context, userID int64) (*model.Ride, error)
+	GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Ride, error)
 	Update(ctx context.Context, ride *model.Ride) error
+	UpdateStatus(ctx context.Context, id int64, status model.RideStatus) error
+	CreateEvent(ctx context.Context, event *model.RideEvent) error
+	ListEvents(ctx context.Context, rideID int64) ([]model.RideEvent, error)
+	HasPaymentFailedByUserID(ctx context.Context, userID int64) (bool, error)
+	SetPromotion(ctx context.Context, id int64, promotionID *int64) error
+	CountCompletedByUserID(ctx context.Context, userID int64) (int, error)
+	CountEndedByMotorbikeID(ctx context.Context, motorbikeID int64) (int, error)
+	ListCompletedByUserIDBetween(ctx context.Context, userID int64, from, to time.Time) ([]model.Ride, error)
+	ListUserIDsWithCompletedRidesBetween(ctx context.Context, from, to time.Time) ([]int64, error)
+	ListMotorbikeIDsRiddenSince(ctx context.Context, since time.Time) ([]int64, error)
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context) (*[]model.Ride, error)
 	ListByUserID(ctx context.Context, userID int64) ([]model.Ride, error)
 	ListByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.Ride, error)
 	ListByDateRange(ctx context.Context, startTime, endTime string) ([]model.Ride, error)
+	SavePriceBreakdown(ctx context.Context, breakdown *model.RidePriceBreakdown) error
+	GetPriceBreakdown(ctx context.Context, rideID int64) (*model.RidePriceBreakdown, error)
+	CreateRoutePoints(ctx context.Context, points []model.RidePoint) error
+	ListRoutePoints(ctx context.Context, rideID int64) ([]model.RidePoint, error)
+	CreatePause(ctx context.Context, pause *model.RidePause) error
+	GetOpenPause(ctx context.Context, rideID int64) (*model.RidePause, error)
+	UpdatePause(ctx context.Context, pause *model.RidePause) error
+	ListPauses(ctx context.Context, rideID int64) ([]model.RidePause, error)
+	ListOpenPausesStartedBefore(ctx context.Context, before time.Time, limit int) ([]model.RidePause, error)
+	ListEndingBefore(ctx context.Context, before time.Time, limit int) ([]model.Ride, error)
+	CreatePhoto(ctx context.Context, photo *model.RidePhoto) error
+	ListPhotos(ctx context.Context, rideID int64) ([]model.RidePhoto, error)
 }
 
 type RideRepository struct {
@@ -25,50 +51,314 @@ func NewRideRepository(db *bun.DB) IRideRepository {
 	return &RideRepository{db: db}
 }
 
+// Create sürüşü kaydeder. Motorun veya kullanıcının bitirilmemiş başka bir sürüşü varsa uq_rides_active_motorbike /
+// uq_rides_active_user indeksleri kaydı reddeder ve ErrDuplicate döner.
 func (r *RideRepository) Create(ctx context.Context, ride *model.Ride) error {
-	_, err := r.db.NewInsert().Model(ride).Exec(ctx)
-	return err
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(ride).Exec(ctx)
+	return uniqueViolation(err)
 }
 
 func (r *RideRepository) GetByID(ctx context.Context, id int64) (*model.Ride, error) {
 	var ride model.Ride
-	if err := r.db.NewSelect().Model(&ride).Relation("Motorbike").Where("ride.id = ?", id).Scan(ctx); err != nil {
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&ride).Relation("Motorbike").Where("ride.id = ?", id).Scan(ctx); err != nil {
 		return nil, err
 	}
 
 	return &ride, nil
 }
 
+// GetByIDForUpdate sürüşü satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *RideRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Ride, error) {
+	var ride model.Ride
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&ride).Where("id = ?", id).For("UPDATE").Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return &ride, nil
+}
+
+// GetActiveByUserID kullanıcının bitmemiş sürüşünü getirir, yoksa nil döner
+func (r *RideRepository) GetActiveByUserID(ctx context.Context, userID int64) (*model.Ride, error) {
+	var rides []model.Ride
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).
+		Where("user_id = ?", userID).
+		Where("status IN (?)", bun.In(model.OngoingRideStatuses)).
+		Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(rides) == 0 {
+		return nil, nil
+	}
+
+	return &rides[0], nil
+}
+
+// GetActiveByMotorbikeID motorun bitmemiş sürüşünü getirir, yoksa nil döner
+func (r *RideRepository) GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Ride, error) {
+	var rides []model.Ride
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("status IN (?)", bun.In(model.OngoingRideStatuses)).
+		Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(rides) == 0 {
+		return nil, nil
+	}
+
+	return &rides[0], nil
+}
+
 func (r *RideRepository) Update(ctx context.Context, ride *model.Ride) error {
-	_, err := r.db.NewUpdate().Model(ride).WherePK().Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(ride).WherePK().Exec(ctx)
 	return err
 }
 
+// UpdateStatus yalnızca sürüşün durumunu günceller
+func (r *RideRepository) UpdateStatus(ctx context.Context, id int64, status model.RideStatus) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.Ride)(nil)).
+		Set("status = ?", status).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *RideRepository) CreateEvent(ctx context.Context, event *model.RideEvent) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(event).Exec(ctx)
+	return err
+}
+
+// ListEvents sürüşün durum geçişlerini oluşturulma sırasına göre getirir
+func (r *RideRepository) ListEvents(ctx context.Context, rideID int64) ([]model.RideEvent, error) {
+	var events []model.RideEvent
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&events).
+		Where("ride_id = ?", rideID).
+		Order("id ASC").
+		Scan(ctx)
+	return events, err
+}
+
+// HasPaymentFailedByUserID kullanıcının ödemesi alınamamış sürüşü olup olmadığını döner
+func (r *RideRepository) HasPaymentFailedByUserID(ctx context.Context, userID int64) (bool, error) {
+	return dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Ride)(nil)).
+		Where("user_id = ?", userID).
+		Where("status = ?", model.RidePaymentFailed).
+		Exists(ctx)
+}
+
+// SetPromotion sürüşe eklenen kampanya kodunu günceller, nil ise kodu kaldırır
+func (r *RideRepository) SetPromotion(ctx context.Context, id int64, promotionID *int64) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*model.Ride)(nil)).
+		Set("promotion_id = ?", promotionID).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// CountCompletedByUserID kullanıcının bitirdiği sürüş sayısını döner
+func (r *RideRepository) CountCompletedByUserID(ctx context.Context, userID int64) (int, error) {
+	return dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Ride)(nil)).
+		Where("user_id = ?", userID).
+		Where("status IN (?)", bun.In(model.EndedRideStatuses)).
+		Count(ctx)
+}
+
+// CountEndedByMotorbikeID motorla bitirilmiş sürüş sayısını döner
+func (r *RideRepository) CountEndedByMotorbikeID(ctx context.Context, motorbikeID int64) (int, error) {
+	return dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Ride)(nil)).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("status IN (?)", bun.In(model.EndedRideStatuses)).
+		Count(ctx)
+}
+
+// ListCompletedByUserIDBetween kullanıcının bitiş zamanı [from, to) aralığındaki sürüşlerini bitiş sırasına göre getirir
+func (r *RideRepository) ListCompletedByUserIDBetween(ctx context.Context, userID int64, from, to time.Time) ([]model.Ride, error) {
+	var rides []model.Ride
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&rides).
+		Where("user_id = ?", userID).
+		Where("end_time >= ? AND end_time < ?", from, to).
+		Order("end_time ASC").
+		Scan(ctx)
+	return rides, err
+}
+
+// ListUserIDsWithCompletedRidesBetween bitiş zamanı [from, to) aralığında sürüşü olan kullanıcıları getirir
+func (r *RideRepository) ListUserIDsWithCompletedRidesBetween(ctx context.Context, from, to time.Time) ([]int64, error) {
+	var userIDs []int64
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Ride)(nil)).
+		ColumnExpr("DISTINCT user_id").
+		Where("end_time >= ? AND end_time < ?", from, to).
+		Order("user_id ASC").
+		Scan(ctx, &userIDs)
+	return userIDs, err
+}
+
+// ListMotorbikeIDsRiddenSince since'den sonra başlayan veya biten sürüşü olan motorları getirir
+func (r *RideRepository) ListMotorbikeIDsRiddenSince(ctx context.Context, since time.Time) ([]int64, error) {
+	var motorbikeIDs []int64
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.Ride)(nil)).
+		ColumnExpr("DISTINCT motorbike_id").
+		Where("start_time >= ? OR end_time >= ?", since, since).
+		Scan(ctx, &motorbikeIDs)
+	return motorbikeIDs, err
+}
+
 func (r *RideRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().Model((*model.Ride)(nil)).Where("id = ?", id).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.Ride)(nil)).Where("id = ?", id).Exec(ctx)
 	return err
 }
 
 func (r *RideRepository) List(ctx context.Context) (*[]model.Ride, error) {
 	var rides []model.Ride
-	err := r.db.NewSelect().Model(&rides).Relation("Motorbike").Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).Relation("Motorbike").Scan(ctx)
 	return &rides, err
 }
 
 func (r *RideRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Ride, error) {
 	var rides []model.Ride
-	err := r.db.NewSelect().Model(&rides).Relation("Motorbike").Where("user_id = ?", userID).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).Relation("Motorbike").Where("user_id = ?", userID).Scan(ctx)
 	return rides, err
 }
 
 func (r *RideRepository) ListByDateRange(ctx context.Context, startTime, endTime string) ([]model.Ride, error) {
 	var rides []model.Ride
-	err := r.db.NewSelect().Model(&rides).Relation("Motorbike").Where("start_time >= ? AND end_time <= ?", startTime, endTime).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).Relation("Motorbike").Where("start_time >= ? AND end_time <= ?", startTime, endTime).Scan(ctx)
 	return rides, err
 }
 
 func (r *RideRepository) ListByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.Ride, error) {
 	var rides []model.Ride
-	err := r.db.NewSelect().Model(&rides).Relation("Motorbike").Where("motorbike_id = ?", motorbikeID).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&rides).Relation("Motorbike").Where("motorbike_id = ?", motorbikeID).Scan(ctx)
+	return rides, err
+}
+
+// SavePriceBreakdown sürüşün fiyat dökümünü kaydeder, varsa üzerine yazar
+func (r *RideRepository) SavePriceBreakdown(ctx context.Context, breakdown *model.RidePriceBreakdown) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().
+		Model(breakdown).
+		On("CONFLICT (ride_id) DO UPDATE").
+		Set("tariff_id = EXCLUDED.tariff_id").
+		Set("currency = EXCLUDED.currency").
+		Set("total_minutes = EXCLUDED.total_minutes").
+		Set("billable_minutes = EXCLUDED.billable_minutes").
+		Set("lines = EXCLUDED.lines").
+		Set("total = EXCLUDED.total").
+		Exec(ctx)
+	return err
+}
+
+func (r *RideRepository) GetPriceBreakdown(ctx context.Context, rideID int64) (*model.RidePriceBreakdown, error) {
+	var breakdown model.RidePriceBreakdown
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&breakdown).Where("ride_id = ?", rideID).Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return &breakdown, nil
+}
+
+func (r *RideRepository) CreateRoutePoints(ctx context.Context, points []model.RidePoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&points).Exec(ctx)
+	return err
+}
+
+// ListRoutePoints sürüşün GPS noktalarını kayıt zamanına göre sıralı getirir
+func (r *RideRepository) ListRoutePoints(ctx context.Context, rideID int64) ([]model.RidePoint, error) {
+	var points []model.RidePoint
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&points).
+		Where("ride_id = ?", rideID).
+		Order("recorded_at ASC", "id ASC").
+		Scan(ctx)
+	return points, err
+}
+
+func (r *RideRepository) CreatePause(ctx context.Context, pause *model.RidePause) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(pause).Exec(ctx)
+	return err
+}
+
+// GetOpenPause sürüşün devam eden park modunu getirir, yoksa nil döner
+func (r *RideRepository) GetOpenPause(ctx context.Context, rideID int64) (*model.RidePause, error) {
+	var pauses []model.RidePause
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&pauses).
+		Where("ride_id = ? AND ended_at IS NULL", rideID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pauses) == 0 {
+		return nil, nil
+	}
+	return &pauses[0], nil
+}
+
+func (r *RideRepository) UpdatePause(ctx context.Context, pause *model.RidePause) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(pause).WherePK().ExcludeColumn("created_at").Exec(ctx)
+	return err
+}
+
+// ListPauses sürüşün park modu aralıklarını başlangıç sırasına göre getirir
+func (r *RideRepository) ListPauses(ctx context.Context, rideID int64) ([]model.RidePause, error) {
+	var pauses []model.RidePause
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&pauses).
+		Where("ride_id = ?", rideID).
+		Order("started_at ASC").
+		Scan(ctx)
+	return pauses, err
+}
+
+// ListOpenPausesStartedBefore verilen zamandan önce başlamış ve hâlâ süren park modlarını en eskisi başta getirir
+func (r *RideRepository) ListOpenPausesStartedBefore(ctx context.Context, before time.Time, limit int) ([]model.RidePause, error) {
+	var pauses []model.RidePause
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&pauses).
+		Where("ended_at IS NULL AND started_at <= ?", before).
+		Order("started_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return pauses, err
+}
+
+// ListEndingBefore before anından önce ending durumuna geçip hâlâ o durumda kalan sürüşleri getirir
+func (r *RideRepository) ListEndingBefore(ctx context.Context, before time.Time, limit int) ([]model.Ride, error) {
+	var rides []model.Ride
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&rides).
+		Where("ride.status = ?", model.RideEnding).
+		Where("EXISTS (SELECT 1 FROM ride_events AS rev WHERE rev.ride_id = ride.id AND rev.to_status = ? AND rev.created_at <= ?)", model.RideEnding, before).
+		Order("ride.id ASC").
+		Limit(limit).
+		Scan(ctx)
 	return rides, err
 }
+
+func (r *RideRepository) CreatePhoto(ctx context.Context, photo *model.RidePhoto) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(photo).Exec(ctx)
+	return err
+}
+
+// ListPhotos sürüşün fotoğraflarını yüklenme sırasına göre getirir
+func (r *RideRepository) ListPhotos(ctx context.Context, rideID int64) ([]model.RidePhoto, error) {
+	var photos []model.RidePhoto
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&photos).
+		Where("ride_id = ?", rideID).
+		Order("id ASC").
+		Scan(ctx)
+	return photos, err
+}
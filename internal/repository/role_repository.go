@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/cache"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/uptrace/bun"
+)
+
+const (
+	rolePermissionsCacheKeyPrefix = "role:permissions:"
+	rolePermissionsCacheDuration  = time.Hour
+)
+
+type IRoleRepository interface {
+	ListPermissions(ctx context.Context) ([]model.PermissionDefinition, error)
+	ListRoles(ctx context.Context) ([]model.RoleDefinition, error)
+	GetRole(ctx context.Context, name model.Role) (*model.RoleDefinition, error)
+	CreateRole(ctx context.Context, role *model.RoleDefinition) error
+	UpdateRole(ctx context.Context, role *model.RoleDefinition) error
+	DeleteRole(ctx context.Context, name model.Role) error
+	CountUsers(ctx context.Context, name model.Role) (int, error)
+	GetRolePermissions(ctx context.Context, name model.Role) ([]model.Permission, error)
+	SetRolePermissions(ctx context.Context, name model.Role, permissions []model.Permission) error
+	ClearPermissionCache(ctx context.Context, name model.Role)
+}
+
+type RoleRepository struct {
+	db *bun.DB
+}
+
+func NewRoleRepository(db *bun.DB) IRoleRepository {
+	return &RoleRepository{db: db}
+}
+
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]model.PermissionDefinition, error) {
+	var permissions []model.PermissionDefinition
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&permissions).Order("name ASC").Scan(ctx)
+	return permissions, err
+}
+
+// ListRoles rolleri yetkileriyle birlikte getirir
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]model.RoleDefinition, error) {
+	var roles []model.RoleDefinition
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&roles).Order("name ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	var rolePermissions []model.RolePermission
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&rolePermissions).Order("role ASC", "permission ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	byRole := make(map[model.Role][]model.Permission)
+	for _, rp := range rolePermissions {
+		byRole[rp.Role] = append(byRole[rp.Role], rp.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = byRole[roles[i].Name]
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) GetRole(ctx context.Context, name model.Role) (*model.RoleDefinition, error) {
+	var role model.RoleDefinition
+	if err := dbFromContext(ctx, r.db).NewSelect().Model(&role).Where("name = ?", name).Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	permissions, err := r.listRolePermissions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = permissions
+	return &role, nil
+}
+
+func (r *RoleRepository) CreateRole(ctx context.Context, role *model.RoleDefinition) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(role).Exec(ctx)
+	return err
+}
+
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *model.RoleDefinition) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(role).Column("description").WherePK().Exec(ctx)
+	return err
+}
+
+// DeleteRole rolü siler, rolün yetkileri cascade ile silinir
+func (r *RoleRepository) DeleteRole(ctx context.Context, name model.Role) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.RoleDefinition)(nil)).Where("name = ?", name).Exec(ctx)
+	return err
+}
+
+// CountUsers role sahip kullanıcı sayısını döner (silinmiş kullanıcılar dahil, çünkü kayıtları rolü referans eder)
+func (r *RoleRepository) CountUsers(ctx context.Context, name model.Role) (int, error) {
+	return dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.User)(nil)).
+		WhereAllWithDeleted().
+		Where("role = ?", name).
+		Count(ctx)
+}
+
+// GetRolePermissions rolün yetkilerini önce cache'den, yoksa veritabanından getirir
+func (r *RoleRepository) GetRolePermissions(ctx context.Context, name model.Role) ([]model.Permission, error) {
+	cacheKey := rolePermissionsCacheKeyPrefix + string(name)
+	var permissions []model.Permission
+	if err := cache.Get(ctx, cacheKey, &permissions); err == nil {
+		return permissions, nil
+	}
+
+	permissions, err := r.listRolePermissions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cache.Set(ctx, cacheKey, permissions, rolePermissionsCacheDuration); err != nil {
+		// Cache hatası yetki kontrolünü engellemesin, bir sonraki istekte veritabanından okunur
+		logger.Error("Rol yetkileri cache'e yazılamadı: %v", err)
+	}
+	return permissions, nil
+}
+
+// SetRolePermissions rolün yetkilerini verilen liste ile değiştirir. Transaction içinde kullanılmalı;
+// cache commit sonrasında ClearPermissionCache ile temizlenmelidir.
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, name model.Role, permissions []model.Permission) error {
+	db := dbFromContext(ctx, r.db)
+	if _, err := db.NewDelete().Model((*model.RolePermission)(nil)).Where("role = ?", name).Exec(ctx); err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]model.RolePermission, len(permissions))
+	for i, permission := range permissions {
+		rows[i] = model.RolePermission{Role: name, Permission: permission}
+	}
+	_, err := db.NewInsert().Model(&rows).Exec(ctx)
+	return err
+}
+
+func (r *RoleRepository) ClearPermissionCache(ctx context.Context, name model.Role) {
+	cache.Delete(ctx, rolePermissionsCacheKeyPrefix+string(name))
+}
+
+func (r *RoleRepository) listRolePermissions(ctx context.Context, name model.Role) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model((*model.RolePermission)(nil)).
+		Column("permission").
+		Where("role = ?", name).
+		Order("permission ASC").
+		Scan(ctx, &permissions)
+	return permissions, err
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type ITariffRepository interface {
+	Create(ctx context.Context, tariff *model.Tariff) error
+	GetByID(ctx context.Context, id int64) (*model.Tariff, error)
+	GetActiveForModel(ctx context.Context, motorbikeModel string) (*model.Tariff, error)
+	Update(ctx context.Context, tariff *model.Tariff) error
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context) ([]model.Tariff, error)
+}
+
+type TariffRepository struct {
+	db *bun.DB
+}
+
+func NewTariffRepository(db *bun.DB) ITariffRepository {
+	return &TariffRepository{db: db}
+}
+
+func (r *TariffRepository) Create(ctx context.Context, tariff *model.Tariff) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(tariff).Exec(ctx)
+	return err
+}
+
+func (r *TariffRepository) GetByID(ctx context.Context, id int64) (*model.Tariff, error) {
+	var tariff model.Tariff
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&tariff).Where("id = ?", id).Scan(ctx)
+	return &tariff, err
+}
+
+// GetActiveForModel motor modeline özel aktif tarifeyi, yoksa varsayılan aktif tarifeyi getirir
+func (r *TariffRepository) GetActiveForModel(ctx context.Context, motorbikeModel string) (*model.Tariff, error) {
+	var tariff model.Tariff
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&tariff).
+		Where("is_active = true").
+		Where("(motorbike_model = ? OR motorbike_model IS NULL)", motorbikeModel).
+		OrderExpr("motorbike_model NULLS LAST").
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	return &tariff, err
+}
+
+func (r *TariffRepository) Update(ctx context.Context, tariff *model.Tariff) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(tariff).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *TariffRepository) Delete(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.Tariff)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (r *TariffRepository) List(ctx context.Context) ([]model.Tariff, error) {
+	var tariffs []model.Tariff
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&tariffs).Order("id ASC").Scan(ctx)
+	return tariffs, err
+}
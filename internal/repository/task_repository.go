@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/uptrace/bun"
+)
+
+type ITaskRepository interface {
+	Create(ctx context.Context, task *model.Task) error
+	GetByID(ctx context.Context, id int64) (*model.Task, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Task, error)
+	Update(ctx context.Context, task *model.Task) error
+	List(ctx context.Context, filter model.TaskFilter, pagination *query.Pagination) ([]model.Task, error)
+	ListByAssigneeID(ctx context.Context, assigneeID int64) ([]model.Task, error)
+	ListOpenInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Task, error)
+	GetLatestByMotorbikeID(ctx context.Context, motorbikeID int64, taskType model.TaskType) (*model.Task, error)
+	CreatePhotos(ctx context.Context, photos []model.TaskPhoto) error
+}
+
+type TaskRepository struct {
+	db *bun.DB
+}
+
+func NewTaskRepository(db *bun.DB) ITaskRepository {
+	return &TaskRepository{db: db}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *model.Task) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(task).Exec(ctx)
+	return err
+}
+
+// GetByID görevi fotoğraflarıyla birlikte getirir
+func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*model.Task, error) {
+	var task model.Task
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&task).
+		Relation("Photos", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("tp.id ASC")
+		}).
+		Where("t.id = ?", id).
+		Scan(ctx)
+	return &task, err
+}
+
+// GetByIDForUpdate görevi satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *TaskRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Task, error) {
+	var task model.Task
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&task).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	return &task, err
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *model.Task) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(task).ExcludeColumn("created_at").WherePK().Exec(ctx)
+	return err
+}
+
+// List görevleri en yakın son tarih başta olacak şekilde sayfalı getirir
+func (r *TaskRepository) List(ctx context.Context, filter model.TaskFilter, pagination *query.Pagination) ([]model.Task, error) {
+	var tasks []model.Task
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&tasks)
+	if filter.Status != "" {
+		q = q.Where("t.status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		q = q.Where("t.type = ?", filter.Type)
+	}
+	if filter.MotorbikeID != 0 {
+		q = q.Where("t.motorbike_id = ?", filter.MotorbikeID)
+	}
+	if filter.AssigneeID != 0 {
+		q = q.Where("t.assignee_id = ?", filter.AssigneeID)
+	}
+	if filter.Overdue {
+		q = q.Where("t.status IN (?)", bun.In([]model.TaskStatus{model.TaskOpen, model.TaskClaimed})).
+			Where("t.due_at < ?", time.Now())
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, pagination); err != nil {
+		return nil, err
+	}
+	if err := query.ApplyPagination(q.Order("t.due_at ASC", "t.id ASC"), *pagination).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListByAssigneeID operatöre atanmış veya operatörün üstlendiği kapanmamış görevleri en yakın son tarih başta getirir
+func (r *TaskRepository) ListByAssigneeID(ctx context.Context, assigneeID int64) ([]model.Task, error) {
+	var tasks []model.Task
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&tasks).
+		Where("assignee_id = ?", assigneeID).
+		Where("status IN (?)", bun.In([]model.TaskStatus{model.TaskOpen, model.TaskClaimed})).
+		Order("due_at ASC", "id ASC").
+		Scan(ctx)
+	return tasks, err
+}
+
+// ListOpenInBounds dikdörtgen alan içindeki açık görevleri getirir (konum indeksini kullanan ön filtre)
+func (r *TaskRepository) ListOpenInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Task, error) {
+	var tasks []model.Task
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&tasks).
+		Where("status = ?", model.TaskOpen).
+		Where("latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat).
+		Where("longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetLatestByMotorbikeID motor için açılan verilen türdeki son görevi getirir, yoksa nil döner
+func (r *TaskRepository) GetLatestByMotorbikeID(ctx context.Context, motorbikeID int64, taskType model.TaskType) (*model.Task, error) {
+	var task model.Task
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&task).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("type = ?", taskType).
+		Order("created_at DESC", "id DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &task, err
+}
+
+func (r *TaskRepository) CreatePhotos(ctx context.Context, photos []model.TaskPhoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&photos).Exec(ctx)
+	return err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type ITelemetryRepository interface {
+	CreateBatch(ctx context.Context, samples []model.MotorbikeTelemetry) error
+	ListByMotorbikeID(ctx context.Context, motorbikeID int64, from, to time.Time, limit int) ([]model.MotorbikeTelemetry, error)
+}
+
+type TelemetryRepository struct {
+	db *bun.DB
+}
+
+func NewTelemetryRepository(db *bun.DB) ITelemetryRepository {
+	return &TelemetryRepository{db: db}
+}
+
+func (r *TelemetryRepository) CreateBatch(ctx context.Context, samples []model.MotorbikeTelemetry) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(&samples).Exec(ctx)
+	return err
+}
+
+// ListByMotorbikeID motorun verilen zaman aralığındaki ölçümlerini eskiden yeniye sıralı getirir
+func (r *TelemetryRepository) ListByMotorbikeID(ctx context.Context, motorbikeID int64, from, to time.Time, limit int) ([]model.MotorbikeTelemetry, error) {
+	var samples []model.MotorbikeTelemetry
+	err := dbFromContext(ctx, r.db).NewSelect().
+		Model(&samples).
+		Where("motorbike_id = ?", motorbikeID).
+		Where("recorded_at >= ?", from).
+		Where("recorded_at <= ?", to).
+		Order("recorded_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return samples, err
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"github.com/uptrace/bun"
+)
+
+type txContextKey struct{}
+
+// ITransactionManager birden fazla repository işlemini tek bir transaction içinde çalıştırır
+type ITransactionManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactionManager struct {
+	db *bun.DB
+}
+
+func NewTransactionManager(db *bun.DB) ITransactionManager {
+	return &TransactionManager{db: db}
+}
+
+// WithTx fn'i bir transaction içinde çalıştırır. fn hata dönerse transaction geri alınır.
+// fn'e verilen context'i kullanan repository metodları aynı transaction'a katılır.
+func (m *TransactionManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Zaten bir transaction içindeysek yenisini açma
+	if _, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return fn(ctx)
+	}
+
+	return m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// dbFromContext context'te aktif bir transaction varsa onu, yoksa veritabanı bağlantısını döner
+func dbFromContext(ctx context.Context, db *bun.DB) bun.IDB {
+	if tx, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
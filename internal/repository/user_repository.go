@@ -19,6 +19,7 @@ const (
 type IUserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	GetByID(ctx context.Context, id int64) (*model.User, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
 	Delete(ctx context.Context, id int64) error
@@ -36,7 +37,7 @@ func NewUserRepository(db *bun.DB) IUserRepository {
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
-	_, err := r.db.NewInsert().Model(user).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(user).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("veritabanı insert hatası: %v", err)
 	}
@@ -54,7 +55,18 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, error) {
 	user := model.User{}
-	err := r.db.NewSelect().Model(&user).Where("id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&user).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetByIDForUpdate kullanıcıyı satır kilidiyle getirir, transaction içinde kullanılmalıdır
+func (r *UserRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.User, error) {
+	user := model.User{}
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&user).Where("id = ?", id).For("UPDATE").Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +76,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, er
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.NewSelect().Model(&user).Where("email = ?", email).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&user).Where("email = ?", email).Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +87,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	user.UpdatedAt = time.Now()
 	// Sadece değişen alanları güncelle
-	_, err := r.db.NewUpdate().
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
 		Model(user).
 		WherePK().
 		Column("email", "phone", "first_name", "last_name", "password_hash", "role", "status", "updated_at").
@@ -97,7 +109,7 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().Model((*model.User)(nil)).Where("id = ?", id).Exec(ctx)
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.User)(nil)).Where("id = ?", id).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -113,7 +125,7 @@ func (r *UserRepository) Delete(ctx context.Context, id int64) error {
 
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, id int64) error {
 	user := &model.User{BaseModel: model.BaseModel{ID: id}}
-	_, err := r.db.NewUpdate().
+	_, err := dbFromContext(ctx, r.db).NewUpdate().
 		Model(user).
 		Column("last_login").
 		WherePK().
@@ -144,7 +156,7 @@ func (r *UserRepository) List(ctx context.Context) ([]model.User, error) {
 		return users, nil
 	}
 
-	err = r.db.NewSelect().Model(&users).Scan(ctx)
+	err = dbFromContext(ctx, r.db).NewSelect().Model(&users).Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +172,7 @@ func (r *UserRepository) List(ctx context.Context) ([]model.User, error) {
 }
 
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	exists, err := r.db.NewSelect().
+	exists, err := dbFromContext(ctx, r.db).NewSelect().
 		Model((*model.User)(nil)).
 		Where("email = ?", email).
 		Exists(ctx)
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type IZoneRepository interface {
+	Create(ctx context.Context, zone *model.Zone) error
+	GetByID(ctx context.Context, id int64) (*model.Zone, error)
+	Update(ctx context.Context, zone *model.Zone) error
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context) ([]model.Zone, error)
+	ListActive(ctx context.Context) ([]model.Zone, error)
+}
+
+type ZoneRepository struct {
+	db *bun.DB
+}
+
+func NewZoneRepository(db *bun.DB) IZoneRepository {
+	return &ZoneRepository{db: db}
+}
+
+func (r *ZoneRepository) Create(ctx context.Context, zone *model.Zone) error {
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(zone).Exec(ctx)
+	return err
+}
+
+func (r *ZoneRepository) GetByID(ctx context.Context, id int64) (*model.Zone, error) {
+	var zone model.Zone
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&zone).Where("id = ?", id).Scan(ctx)
+	return &zone, err
+}
+
+func (r *ZoneRepository) Update(ctx context.Context, zone *model.Zone) error {
+	_, err := dbFromContext(ctx, r.db).NewUpdate().Model(zone).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *ZoneRepository) Delete(ctx context.Context, id int64) error {
+	_, err := dbFromContext(ctx, r.db).NewDelete().Model((*model.Zone)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (r *ZoneRepository) List(ctx context.Context) ([]model.Zone, error) {
+	var zones []model.Zone
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&zones).Order("id ASC").Scan(ctx)
+	return zones, err
+}
+
+func (r *ZoneRepository) ListActive(ctx context.Context) ([]model.Zone, error) {
+	var zones []model.Zone
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&zones).Where("is_active = true").Order("id ASC").Scan(ctx)
+	return zones, err
+}
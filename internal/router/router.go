@@ -1,27 +1,34 @@
 package router
 
 import (
+	"context"
+	"fmt"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/config"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/handler"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/middleware"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/monitoring"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 
 	"github.com/uptrace/bun"
 	"time"
 )
 
 type Router struct {
-	app *fiber.App
-	db  *bun.DB
-	cfg *config.Config
+	app     *fiber.App
+	db      *bun.DB
+	cfg     *config.Config
+	workers []func(ctx context.Context) // SetupRoutes'ta kaydedilir, StartWorkers ile başlatılır
 }
 
 var prometheusEndpoint string
@@ -62,6 +69,10 @@ func (r *Router) SetupRoutes() {
 			if c.Path() == prometheusEndpoint {
 				return "metrics_no_limit"
 			}
+			// Aynı ağın arkasındaki cihazlar birbirini sınırlamasın diye cihaz istekleri anahtara göre sayılır
+			if deviceKey := c.Get("X-Device-Key"); deviceKey != "" {
+				return "device:" + model.HashDeviceKey(deviceKey) + ":" + c.Path()
+			}
 			// Her route'u ayrı ayrı sınırla (örneğin: "/users", "/users/:id", "/auth/login")
 			return c.IP() + ":" + c.Path()
 		},
@@ -70,6 +81,9 @@ func (r *Router) SetupRoutes() {
 	// Prometheus Middleware ekleyelim
 	r.app.Use(monitoring.PrometheusMiddleware())
 
+	// Her isteğe X-Request-ID verilir; denetim kayıtları aynı isteğe ait kayıtları bununla ilişkilendirir
+	r.app.Use(requestid.New())
+
 	// API versiyonu
 	api := r.app.Group("/api")
 	v1 := api.Group("/v1")
@@ -88,20 +102,237 @@ func (r *Router) SetupRoutes() {
 	rideRepo := repository.NewRideRepository(r.db)
 	motorbikeRepo := repository.NewMotorbikeRepository(r.db)
 	bluetoothRepo := repository.NewBluetoothConnectionRepository(r.db)
+	tariffRepo := repository.NewTariffRepository(r.db)
+	reservationRepo := repository.NewReservationRepository(r.db)
+	zoneRepo := repository.NewZoneRepository(r.db)
+	telemetryRepo := repository.NewTelemetryRepository(r.db)
+	commandRepo := repository.NewDeviceCommandRepository(r.db)
+	ledgerRepo := repository.NewLedgerRepository(r.db)
+	ridePaymentRepo := repository.NewRidePaymentRepository(r.db)
+	promotionRepo := repository.NewPromotionRepository(r.db)
+	referralRepo := repository.NewReferralRepository(r.db)
+	passRepo := repository.NewPassRepository(r.db)
+	invoiceRepo := repository.NewInvoiceRepository(r.db)
+	disputeRepo := repository.NewDisputeRepository(r.db)
+	maintenanceRepo := repository.NewMaintenanceRepository(r.db)
+	issueRepo := repository.NewIssueRepository(r.db)
+	taskRepo := repository.NewTaskRepository(r.db)
+	roleRepo := repository.NewRoleRepository(r.db)
+	auditLogRepo := repository.NewAuditLogRepository(r.db)
+	txManager := repository.NewTransactionManager(r.db)
 
 	// Service'ler
-	authService := service.NewAuthService(authRepo, userRepo)
-	userService := service.NewUserService(userRepo)
-	rideService := service.NewRideService(rideRepo, motorbikeRepo)
-	motorbikeService := service.NewMotorbikeService(motorbikeRepo)
+	fareCalculator := service.NewFareCalculator(r.cfg.PricingConfig.GetLocation())
+	commandService := service.NewDeviceCommandService(service.DeviceCommandServiceDeps{
+		CommandRepo:   commandRepo,
+		MotorbikeRepo: motorbikeRepo,
+		TxManager:     txManager,
+		AckTimeout:    r.cfg.DeviceConfig.GetCommandAckTimeout(),
+		MaxAttempts:   r.cfg.DeviceConfig.CommandMaxAttempts,
+		TTL:           r.cfg.DeviceConfig.GetCommandTTL(),
+	})
+	auditService := service.NewAuditService(auditLogRepo, txManager)
+	userService := service.NewUserService(userRepo, auditService)
+	rbacService := service.NewRBACService(roleRepo, txManager, auditService)
+	walletService := service.NewWalletService(ledgerRepo, userRepo, txManager, auditService)
+	referralService := service.NewReferralService(referralRepo, walletService, r.cfg.ReferralConfig.ReferrerCredit, r.cfg.ReferralConfig.RefereeCredit)
+	authService := service.NewAuthService(authRepo, userRepo, referralService, txManager)
+	promotionService := service.NewPromotionService(promotionRepo, rideRepo, motorbikeRepo, auditService)
+	paymentService := service.NewPaymentService(newPaymentProvider(r.cfg.PaymentConfig), ridePaymentRepo, rideRepo, walletService, txManager,
+		r.cfg.PaymentConfig.HoldAmount, r.cfg.PaymentConfig.GetProviderTimeout())
+	passService := service.NewPassService(passRepo, walletService, paymentService, txManager, r.cfg.PricingConfig.GetLocation(), auditService)
+	// SMTP gönderici adresi tanımlı değilse belgeler e-postayla gönderilmez
+	var mailer service.Mailer
+	if r.cfg.MailConfig.FromEmail != "" {
+		mailer = emailPkg
+	}
+	invoiceService := service.NewInvoiceService(service.InvoiceServiceDeps{
+		InvoiceRepo: invoiceRepo,
+		RideRepo:    rideRepo,
+		UserRepo:    userRepo,
+		Mailer:      mailer,
+		Company: service.CompanyInfo{
+			Name:      r.cfg.InvoiceConfig.CompanyName,
+			Address:   r.cfg.InvoiceConfig.CompanyAddress,
+			TaxOffice: r.cfg.InvoiceConfig.CompanyTaxOffice,
+			TaxNumber: r.cfg.InvoiceConfig.CompanyTaxNumber,
+			Email:     r.cfg.InvoiceConfig.CompanyEmail,
+		},
+		VATRatePct:  r.cfg.InvoiceConfig.VATRatePct,
+		Location:    r.cfg.PricingConfig.GetLocation(),
+		Permissions: rbacService,
+	})
+	disputeService := service.NewDisputeService(service.DisputeServiceDeps{
+		DisputeRepo:  disputeRepo,
+		RideRepo:     rideRepo,
+		UserRepo:     userRepo,
+		TxManager:    txManager,
+		Wallet:       walletService,
+		Payments:     paymentService,
+		Mailer:       mailer,
+		SupportEmail: r.cfg.DisputeConfig.SupportEmail,
+		Window:       r.cfg.DisputeConfig.GetWindow(),
+		Permissions:  rbacService,
+		Audit:        auditService,
+	})
+	var lockController service.LockController = service.NoopLockController{}
+	if r.cfg.DeviceConfig.LockController == "device" {
+		lockController = service.NewDeviceLockController(commandService, motorbikeRepo, r.cfg.DeviceConfig.GetLockTimeout(), r.cfg.DeviceConfig.GetOnlineWindow())
+	}
+	rideService := service.NewRideService(service.RideServiceDeps{
+		RideRepo:        rideRepo,
+		MotorbikeRepo:   motorbikeRepo,
+		UserRepo:        userRepo,
+		ConnectionRepo:  bluetoothRepo,
+		TariffRepo:      tariffRepo,
+		ReservationRepo: reservationRepo,
+		ZoneRepo:        zoneRepo,
+		TxManager:       txManager,
+		FareCalculator:  fareCalculator,
+		Commands:        commandService,
+		Locks:           lockController,
+		Wallet:          walletService,
+		Payments:        paymentService,
+		Promotions:      promotionService,
+		Referrals:       referralService,
+		Passes:          passService,
+		Invoices:        invoiceService,
+		Audit:           auditService,
+		Permissions:     rbacService,
+		MaxPause:        r.cfg.RideConfig.GetMaxPause(),
+	})
+	blobStore := newBlobStore(r.cfg.StorageConfig)
+	fileService := service.NewFileService(blobStore, storage.NewURLSigner(r.cfg.StorageConfig.URLSecret, "/api/v1/files", r.cfg.StorageConfig.GetURLTTL()))
+	ridePhotoService := service.NewRidePhotoService(rideRepo, blobStore, r.cfg.RideConfig.GetPhotoMaxSize(), rbacService)
+	motorbikePhotoService := service.NewMotorbikePhotoService(motorbikeRepo, txManager, blobStore,
+		r.cfg.MotorbikeConfig.GetPhotoMaxSize(), r.cfg.MotorbikeConfig.MaxPhotos)
+	motorbikeService := service.NewMotorbikeService(motorbikeRepo, auditService)
 	bluetoothService := service.NewBluetoothConnectionService(bluetoothRepo)
+	tariffService := service.NewTariffService(tariffRepo, auditService)
+	zoneService := service.NewZoneService(zoneRepo, auditService)
+	telemetryService := service.NewTelemetryService(telemetryRepo, motorbikeRepo, rideRepo, txManager)
+	maintenanceService := service.NewMaintenanceService(service.MaintenanceServiceDeps{
+		MaintenanceRepo: maintenanceRepo,
+		MotorbikeRepo:   motorbikeRepo,
+		RideRepo:        rideRepo,
+		UserRepo:        userRepo,
+		TxManager:       txManager,
+		Permissions:     rbacService,
+		Audit:           auditService,
+	})
+	issueService := service.NewIssueService(service.IssueServiceDeps{
+		IssueRepo:     issueRepo,
+		MotorbikeRepo: motorbikeRepo,
+		RideRepo:      rideRepo,
+		UserRepo:      userRepo,
+		TxManager:     txManager,
+		Maintenance:   maintenanceService,
+		Store:         blobStore,
+		Mailer:        mailer,
+		MaxPhotos:     r.cfg.IssueConfig.MaxPhotos,
+		MaxPhotoBytes: r.cfg.IssueConfig.GetPhotoMaxSize(),
+		FlagThreshold: r.cfg.IssueConfig.FlagThreshold,
+		FlagWindow:    r.cfg.IssueConfig.GetFlagWindow(),
+		Permissions:   rbacService,
+		Audit:         auditService,
+	})
+	taskService := service.NewTaskService(service.TaskServiceDeps{
+		TaskRepo:      taskRepo,
+		MotorbikeRepo: motorbikeRepo,
+		RideRepo:      rideRepo,
+		UserRepo:      userRepo,
+		ZoneRepo:      zoneRepo,
+		TxManager:     txManager,
+		Permissions:   rbacService,
+		Audit:         auditService,
+		Store:         blobStore,
+		MaxPhotos:     r.cfg.TaskConfig.MaxPhotos,
+		MaxPhotoBytes: r.cfg.TaskConfig.GetPhotoMaxSize(),
+		SLAs: map[model.TaskType]time.Duration{
+			model.TaskRebalance:   r.cfg.TaskConfig.GetRebalanceSLA(),
+			model.TaskBatterySwap: r.cfg.TaskConfig.GetBatterySwapSLA(),
+			model.TaskCollect:     r.cfg.TaskConfig.GetCollectSLA(),
+			model.TaskInspect:     r.cfg.TaskConfig.GetInspectSLA(),
+		},
+		LowBatteryPercent: r.cfg.TaskConfig.LowBatteryPercent,
+		IdleAfter:         r.cfg.TaskConfig.GetIdleAfter(),
+	})
+	reservationService := service.NewReservationService(service.ReservationServiceDeps{
+		ReservationRepo: reservationRepo,
+		MotorbikeRepo:   motorbikeRepo,
+		UserRepo:        userRepo,
+		RideRepo:        rideRepo,
+		TxManager:       txManager,
+		HoldDuration:    r.cfg.ReservationConfig.GetHoldDuration(),
+		Fee:             r.cfg.ReservationConfig.Fee,
+		Permissions:     rbacService,
+		Audit:           auditService,
+	})
+
+	// Arka plan işleri
+	r.workers = append(r.workers, func(ctx context.Context) {
+		reservationService.RunExpiryWorker(ctx, r.cfg.ReservationConfig.GetExpiryInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		commandService.RunTimeoutWorker(ctx, r.cfg.DeviceConfig.GetCommandSweepInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		paymentService.RunSettlementWorker(ctx, r.cfg.PaymentConfig.GetSettleSweepInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		passService.RunRenewalWorker(ctx, r.cfg.PassConfig.GetRenewalInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		invoiceService.RunWorker(ctx, r.cfg.InvoiceConfig.GetWorkerInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		rideService.RunPauseWorker(ctx, r.cfg.RideConfig.GetPauseSweepInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		maintenanceService.RunScheduleWorker(ctx, r.cfg.MaintenanceConfig.GetScheduleInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		issueService.RunFlagWorker(ctx, r.cfg.IssueConfig.GetFlagInterval())
+	})
+	r.workers = append(r.workers, func(ctx context.Context) {
+		taskService.RunGenerateWorker(ctx, r.cfg.TaskConfig.GetGenerateInterval())
+	})
 
 	// Handler'lar
 	authHandler := handler.NewAuthHandler(authService, emailPkg)
-	userHandler := handler.NewUserHandler(userService)
-	rideHandler := handler.NewRideHandler(rideService)
-	motorbikeHandler := handler.NewMotorbikeHandler(motorbikeService)
-	bluetoothHandler := handler.NewBluetoothConnectionHandler(bluetoothService, motorbikeService)
+	userHandler := handler.NewUserHandler(userService, rbacService)
+	roleHandler := handler.NewRoleHandler(rbacService)
+	walletHandler := handler.NewWalletHandler(walletService)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	promotionHandler := handler.NewPromotionHandler(promotionService, referralService)
+	passHandler := handler.NewPassHandler(passService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+	disputeHandler := handler.NewDisputeHandler(disputeService, r.cfg.DisputeConfig.MaxPhotos, r.cfg.DisputeConfig.GetMaxPhotoSize())
+	rideHandler := handler.NewRideHandler(rideService, ridePhotoService, fileService, r.cfg.RideConfig.GetPhotoMaxSize())
+	fileHandler := handler.NewFileHandler(fileService)
+	motorbikeHandler := handler.NewMotorbikeHandler(motorbikeService, motorbikePhotoService, fileService, r.cfg.MotorbikeConfig.GetPhotoMaxSize())
+	bluetoothHandler := handler.NewBluetoothConnectionHandler(bluetoothService, rideService)
+	tariffHandler := handler.NewTariffHandler(tariffService)
+	reservationHandler := handler.NewReservationHandler(reservationService)
+	zoneHandler := handler.NewZoneHandler(zoneService)
+	telemetryHandler := handler.NewTelemetryHandler(telemetryService, motorbikeService)
+	commandHandler := handler.NewDeviceCommandHandler(commandService, r.cfg.DeviceConfig.GetCommandLongPollMax())
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+	issueHandler := handler.NewIssueHandler(issueService, fileService, r.cfg.IssueConfig.GetPhotoMaxSize())
+	taskHandler := handler.NewTaskHandler(taskService, fileService, r.cfg.TaskConfig.GetPhotoMaxSize())
+	auditLogHandler := handler.NewAuditLogHandler(auditService, r.cfg.AuditConfig.ExportMaxRows)
+
+	// Yönetim route'ları rolün yetkisini ister; admin tüm yetkilere sahiptir, diğer rollerin yetkileri
+	// veritabanında tanımlanır ve Redis'te önbelleklenir.
+	requirePermission := func(permission model.Permission) fiber.Handler {
+		return middleware.RequirePermission(rbacService, permission)
+	}
+
+	// Veri değiştiren tüm istekler denetim kaydına yazılır. Route'lardan önce eklenmelidir.
+	r.app.Use(middleware.AuditMiddleware(auditService))
+
+	// Not: Her grupta normal kullanıcı route'ları yönetim route'larından önce tanımlanır.
+	// Yönetim grubunun middleware'i aynı prefix'e bağlandığı için sonradan tanımlanan tüm route'ları da yakalar.
 
 	// Auth routes
 	auth := v1.Group("/auth")
@@ -120,67 +351,299 @@ func (r *Router) SetupRoutes() {
 	userProfile.Use(middleware.AuthMiddleware()) // Sadece authentication gerekli
 	userProfile.Get("/", userHandler.GetProfile)
 	userProfile.Put("/", userHandler.UpdateProfile)
+	userProfile.Get("/wallet", walletHandler.GetMyWallet)
+	userProfile.Get("/wallet/transactions", walletHandler.ListMyTransactions) // ?page=1&page_size=10
+	userProfile.Get("/referral", promotionHandler.GetMyReferral)              // davet kodu ve davet özeti
+	userProfile.Get("/permissions", roleHandler.GetMyPermissions)             // rol ve yetkiler
 
-	// Admin only routes
+	// Yönetim route'ları
 	adminUsers := users.Group("/")
-	adminUsers.Use(middleware.AuthMiddleware(), middleware.AdminOnly()) // Admin yetkisi gerekli
-	adminUsers.Post("/", userHandler.Create)
-	adminUsers.Get("/", userHandler.List)
-	adminUsers.Get("/:id", userHandler.GetByID)
-	adminUsers.Put("/:id", userHandler.Update)
-	adminUsers.Delete("/:id", userHandler.Delete)
+	adminUsers.Use(middleware.AuthMiddleware())
+	adminUsers.Post("/", requirePermission(model.PermUsersWrite), userHandler.Create) // rol vermek roles.manage ister
+	adminUsers.Get("/", requirePermission(model.PermUsersRead), userHandler.List)
+	adminUsers.Get("/:id", requirePermission(model.PermUsersRead), userHandler.GetByID)
+	adminUsers.Put("/:id", requirePermission(model.PermUsersWrite), userHandler.Update) // durum değiştirmek users.ban, rol değiştirmek roles.manage ister
+	adminUsers.Delete("/:id", requirePermission(model.PermUsersDelete), userHandler.Delete)
+	adminUsers.Get("/:id/wallet", requirePermission(model.PermUsersRead), walletHandler.GetUserWallet)
+	adminUsers.Get("/:id/wallet/transactions", requirePermission(model.PermUsersRead), walletHandler.ListUserTransactions)
+	adminUsers.Post("/:id/wallet/adjustments", requirePermission(model.PermWalletsAdjust), walletHandler.Adjust) // top_up, refund, promo_credit, adjustment
+
+	// Role routes
+	roles := v1.Group("/roles")
+	roles.Use(middleware.AuthMiddleware(), requirePermission(model.PermRolesManage))
+	roles.Post("/", roleHandler.Create) // {name, description, permissions}
+	roles.Get("/", roleHandler.List)
+	roles.Get("/:name", roleHandler.Get)
+	roles.Put("/:name", roleHandler.Update) // yetkiler verilen liste ile değiştirilir
+	roles.Delete("/:name", roleHandler.Delete)
+	v1.Get("/permissions", middleware.AuthMiddleware(), requirePermission(model.PermRolesManage), roleHandler.ListPermissions)
 
 	// Ride routes
 	rides := v1.Group("/rides")
+	userRides := rides.Group("/")
+	userRides.Use(middleware.AuthMiddleware()) // Sadece authentication gerekli (normal kullanıcılar için)
+	userRides.Post("/", rideHandler.StartRide)
+	userRides.Get("/me", rideHandler.ListMyRides)
+	userRides.Get("/me/active", rideHandler.GetActiveRide) // devam eden sürüş, geçen süre ve anlık ücret
+	userRides.Post("/estimate", rideHandler.EstimateFare)
+	userRides.Put("/finish/:id", rideHandler.FinishRide)
+	userRides.Post("/:id/pause", rideHandler.PauseRide)                  // park modu: motor kilitlenir, kullanıcıya ayrılmış kalır
+	userRides.Post("/:id/resume", rideHandler.ResumeRide)                // park modundan çıkıp sürüşe devam eder
+	userRides.Post("/photo/:id", rideHandler.AddRidePhoto)               // multipart: photo (JPEG/PNG)
+	userRides.Get("/:id/photos", rideHandler.ListPhotos)                 // süreli imzalı bağlantılarla
+	userRides.Get("/:id/price-breakdown", rideHandler.GetPriceBreakdown) // rides.read yetkisi olan roller tüm sürüşleri, kullanıcı kendi sürüşünü görür
+	userRides.Get("/:id/events", rideHandler.ListEvents)                 // sürüşün durum geçmişi
+	userRides.Post("/:id/route", rideHandler.RecordRoute)                // devam eden sürüşe uygulamadan GPS noktaları ekler
+	userRides.Get("/:id/route", rideHandler.GetRoute)                    // Accept: application/geo+json (varsayılan) veya application/gpx+xml
+	userRides.Post("/:id/pay", paymentHandler.PayRide)                   // ödemesi alınamamış sürüşün ödemesini tekrar dener
+	userRides.Post("/:id/promo-code", promotionHandler.ApplyToRide)      // indirim sürüş bitirilirken uygulanır
+	userRides.Get("/:id/receipt", invoiceHandler.GetRideReceipt)         // ?format=pdf (varsayılan) veya html
+	userRides.Post("/:id/disputes", disputeHandler.Open)                 // multipart: reason, description, requested_amount, photos
+
 	adminRides := rides.Group("/")
+	adminRides.Use(middleware.AuthMiddleware())
+	adminRides.Get("/", requirePermission(model.PermRidesRead), rideHandler.List)
+	adminRides.Get("/user/:userID", requirePermission(model.PermRidesRead), rideHandler.ListRideByUserID)
+	adminRides.Get("/bike/:motorbikeID", requirePermission(model.PermRidesRead), rideHandler.ListRideByMotorbikeID)
+	adminRides.Get("/filtered-rides", requirePermission(model.PermRidesRead), rideHandler.ListByDateRange) // belirli tarih aralıklarındaki sürüşleri getirir -> /filtered-rides?start_time=2024-09-04&end_time=2024-09-05
+	adminRides.Get("/:id", requirePermission(model.PermRidesRead), rideHandler.GetByID)
+	adminRides.Get("/:id/adjustments", requirePermission(model.PermRidesRead), disputeHandler.ListRideAdjustments) // itiraz sonucu yapılan iadeler
+	adminRides.Delete("/:id", requirePermission(model.PermRidesDelete), rideHandler.Delete)
+
+	// Dispute routes
+	disputes := v1.Group("/disputes")
+	userDisputes := disputes.Group("/")
+	userDisputes.Use(middleware.AuthMiddleware()) // disputes.read yetkisi olan roller tüm itirazları, kullanıcı kendi itirazlarını görür
+	userDisputes.Get("/me", disputeHandler.ListMyDisputes)
+	userDisputes.Get("/:id<int>", disputeHandler.Get)
+	userDisputes.Get("/:id<int>/photos/:photoID<int>", disputeHandler.GetPhoto)
+	userDisputes.Post("/:id<int>/withdraw", disputeHandler.Withdraw)
+
+	adminDisputes := disputes.Group("/")
+	adminDisputes.Use(middleware.AuthMiddleware())
+	adminDisputes.Get("/", requirePermission(model.PermDisputesRead), disputeHandler.List) // destek kuyruğu, en eski itiraz başta
+	adminDisputes.Post("/:id/review", requirePermission(model.PermDisputesReview), disputeHandler.Review)
+	adminDisputes.Post("/:id/approve", requirePermission(model.PermRidesRefund), disputeHandler.Approve)  // {amount, destination: wallet|card, note}
+	adminDisputes.Post("/:id/reject", requirePermission(model.PermDisputesReview), disputeHandler.Reject) // {note}
 
-	adminRides.Use(middleware.AuthMiddleware(), middleware.AdminOnly()) // Admin yetkisi gerekli
-	adminRides.Get("/", rideHandler.List)
-	adminRides.Get("/user/:userID", rideHandler.ListRideByUserID)
-	adminRides.Get("/bike/:motorbikeID", rideHandler.ListRideByMotorbikeID)
-	adminRides.Get("/filtered-rides", rideHandler.ListByDateRange) // belirli tarih aralıklarındaki sürüşleri getirir -> /filtered-rides?start_time=2024-09-04&end_time=2024-09-05
-	adminRides.Get("/:id", rideHandler.GetByID)
-	adminRides.Put("/:id", rideHandler.Update)
-	adminRides.Delete("/:id", rideHandler.Delete)
-
-	rides.Use(middleware.AuthMiddleware()) // Sadece authentication gerekli (normal kullanıcılar için)
-	rides.Post("/", rideHandler.Create)
-	rides.Get("/me", rideHandler.ListMyRides)
-	rides.Put("/finish/:id", rideHandler.FinishRide)
-	rides.Post("/photo/:id", rideHandler.AddRidePhoto)
+	// Invoice routes
+	invoices := v1.Group("/invoices")
+	invoices.Use(middleware.AuthMiddleware()) // rides.read yetkisi olan roller tüm belgeleri, kullanıcı kendi belgelerini görür
+	invoices.Get("/", invoiceHandler.ListMyInvoices)
+	invoices.Get("/:id", invoiceHandler.GetInvoice) // ?format=pdf (varsayılan) veya html
 
 	// Motorbike routes
 	motorbike := v1.Group("/motorbike")
+	userMotorbike := motorbike.Group("/")
+	userMotorbike.Use(middleware.AuthMiddleware()) // Sadece authentication gerekli (normal kullanıcılar için)
+	userMotorbike.Get("/", motorbikeHandler.List)
+	userMotorbike.Get("/available", motorbikeHandler.GetAvailableMotors)
+	userMotorbike.Get("/nearby", motorbikeHandler.GetNearbyMotors) // /nearby?lat=41.01&lng=28.97&radius_m=500&limit=20
+	userMotorbike.Get("/:id<int>", motorbikeHandler.GetByID)
+	userMotorbike.Post("/:id<int>/reserve", reservationHandler.Reserve) // motoru kullanıcı yanına gidene kadar tutar
+	userMotorbike.Post("/:id<int>/issues", issueHandler.Report)         // multipart: category, severity, description, ride_id, photos
+
 	adminMotorbike := motorbike.Group("/")
+	adminMotorbike.Use(middleware.AuthMiddleware())
+	adminMotorbike.Post("/", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.Create)
+	adminMotorbike.Put("/:id", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.Update)
+	adminMotorbike.Delete("/:id", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.Delete)
+	adminMotorbike.Get("/maintenance", requirePermission(model.PermMotorbikesRead), motorbikeHandler.GetMaintenanceMotors)
+	adminMotorbike.Get("/rented-motorbikes", requirePermission(model.PermMotorbikesRead), motorbikeHandler.GetRentedMotors)
+	adminMotorbike.Get("/motorbike-photos/:id", requirePermission(model.PermMotorbikesRead), motorbikeHandler.GetPhotosByID)
+	adminMotorbike.Post("/:id/photos", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.UploadPhotos) // multipart: photos (birden fazla JPEG/PNG)
+	adminMotorbike.Put("/:id/photos/order", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.ReorderPhotos)
+	adminMotorbike.Put("/:id/photos/:photoID/primary", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.SetPrimaryPhoto)
+	adminMotorbike.Delete("/:id/photos/:photoID", requirePermission(model.PermMotorbikesWrite), motorbikeHandler.DeletePhoto)
+	adminMotorbike.Get("/:id/telemetry", requirePermission(model.PermMotorbikesRead), telemetryHandler.ListByMotorbike) // /:id/telemetry?from=2024-09-04T10:00:00Z&to=2024-09-04T12:00:00Z
+	adminMotorbike.Post("/:id/device-key", requirePermission(model.PermMotorbikesWrite), telemetryHandler.RotateDeviceKey)
+	adminMotorbike.Post("/:id/commands", requirePermission(model.PermMotorbikesCommand), commandHandler.Enqueue) // lock, unlock, beep, disable
+	adminMotorbike.Get("/:id/commands", requirePermission(model.PermMotorbikesRead), commandHandler.ListByMotorbike)
+	adminMotorbike.Get("/commands/:id", requirePermission(model.PermMotorbikesRead), commandHandler.GetByID)
+	adminMotorbike.Get("/:id/service-history", requirePermission(model.PermMaintenanceRead), maintenanceHandler.ServiceHistory) // iş emirleri, yeniden eskiye
+
+	// Maintenance routes
+	maintenance := v1.Group("/maintenance")
+	maintenance.Use(middleware.AuthMiddleware())
+	maintenance.Post("/work-orders", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.OpenWorkOrder) // motor bakıma alınır
+	maintenance.Get("/work-orders", requirePermission(model.PermMaintenanceRead), maintenanceHandler.ListWorkOrders)  // en acil iş emri başta
+	maintenance.Get("/work-orders/:id", requirePermission(model.PermMaintenanceRead), maintenanceHandler.GetWorkOrder)
+	maintenance.Put("/work-orders/:id", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.UpdateWorkOrder)
+	maintenance.Post("/work-orders/:id/start", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.StartWorkOrder)
+	maintenance.Post("/work-orders/:id/complete", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.CompleteWorkOrder) // {parts, labour_minutes, labour_cost, note}
+	maintenance.Post("/work-orders/:id/cancel", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.CancelWorkOrder)     // {note}
+	maintenance.Post("/schedules", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.CreateSchedule)
+	maintenance.Get("/schedules", requirePermission(model.PermMaintenanceRead), maintenanceHandler.ListSchedules)
+	maintenance.Get("/schedules/:id", requirePermission(model.PermMaintenanceRead), maintenanceHandler.GetSchedule)
+	maintenance.Put("/schedules/:id", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.UpdateSchedule)
+	maintenance.Delete("/schedules/:id", requirePermission(model.PermMaintenanceWrite), maintenanceHandler.DeleteSchedule)
+
+	// Issue routes
+	issues := v1.Group("/issues")
+	userIssues := issues.Group("/")
+	userIssues.Use(middleware.AuthMiddleware()) // issues.read yetkisi olan roller tüm bildirimleri, kullanıcı kendi bildirimlerini görür
+	userIssues.Get("/me", issueHandler.ListMyIssues)
+	userIssues.Get("/:id<int>", issueHandler.Get)
 
-	adminMotorbike.Use(middleware.AuthMiddleware(), middleware.AdminOnly()) // Admin yetkisi gerekli
-	adminMotorbike.Post("/", motorbikeHandler.Create)
-	adminMotorbike.Put("/:id", motorbikeHandler.Update)
-	adminMotorbike.Delete("/:id", motorbikeHandler.Delete)
-	adminMotorbike.Get("/maintenance", motorbikeHandler.GetMaintenanceMotors)
-	adminMotorbike.Get("/rented-motorbikes", motorbikeHandler.GetRentedMotors)
-	adminMotorbike.Get("/motorbike-photos/:id", motorbikeHandler.GetPhotosByID)
+	adminIssues := issues.Group("/")
+	adminIssues.Use(middleware.AuthMiddleware())
+	adminIssues.Get("/", requirePermission(model.PermIssuesRead), issueHandler.List)                          // triyaj kuyruğu, en ağır bildirim başta
+	adminIssues.Post("/:id/ride", requirePermission(model.PermIssuesTriage), issueHandler.LinkRide)           // {ride_id}
+	adminIssues.Post("/:id/duplicate", requirePermission(model.PermIssuesTriage), issueHandler.MarkDuplicate) // {duplicate_of_id, note}
+	adminIssues.Post("/:id/resolve", requirePermission(model.PermIssuesTriage), issueHandler.Resolve)         // {note}, bildirenlere e-posta gönderilir
 
-	motorbike.Use(middleware.AuthMiddleware()) // Sadece authentication gerekli (normal kullanıcılar için)
-	motorbike.Get("/", motorbikeHandler.List)
-	motorbike.Get("/available", motorbikeHandler.GetAvailableMotors)
-	motorbike.Get("/:id", motorbikeHandler.GetByID)
+	// Field operation routes
+	tasks := v1.Group("/ops/tasks")
+	operatorTasks := tasks.Group("/")
+	operatorTasks.Use(middleware.AuthMiddleware())
+	// Yetki route bazında istenir; grup middleware'i sonradan tanımlanan yönetim route'larını da yakalardı
+	operatorTasks.Get("/nearby", requirePermission(model.PermTasksWork), taskHandler.Nearby) // /nearby?lat=41.01&lng=28.97&radius_m=2000&limit=20
+	operatorTasks.Get("/me", requirePermission(model.PermTasksWork), taskHandler.ListMyTasks)
+	operatorTasks.Get("/:id<int>", requirePermission(model.PermTasksWork), taskHandler.Get)
+	operatorTasks.Post("/:id<int>/claim", requirePermission(model.PermTasksWork), taskHandler.Claim)
+	operatorTasks.Post("/:id<int>/release", requirePermission(model.PermTasksWork), taskHandler.Release)
+	operatorTasks.Post("/:id<int>/complete", requirePermission(model.PermTasksWork), taskHandler.Complete) // multipart: note, photos (en az bir fotoğraf)
+
+	adminTasks := tasks.Group("/")
+	adminTasks.Use(middleware.AuthMiddleware(), requirePermission(model.PermTasksManage))
+	adminTasks.Post("/", taskHandler.Create)
+	adminTasks.Get("/", taskHandler.List) // en yakın son tarih başta, ?overdue=true ile süresi geçenler
+	adminTasks.Put("/:id", taskHandler.Update)
+	adminTasks.Post("/:id/assign", taskHandler.Assign) // {operator_id}
+	adminTasks.Post("/:id/cancel", taskHandler.Cancel) // {note}
 
 	// Bluetooth routes
 	bluetooth := v1.Group("/bluetooth")
+	userBluetooth := bluetooth.Group("/")
+	userBluetooth.Use(middleware.AuthMiddleware())                          // Sadece authentication gerekli (normal kullanıcılar için)
+	userBluetooth.Get("/my-connections", bluetoothHandler.GetMyConnections) // userın tüm geçmiş connectionlarını getirir.
+	userBluetooth.Post("/connect", bluetoothHandler.Connect)                // motora bağlanır ve sürüşü başlatır
+	userBluetooth.Post("/disconnect", bluetoothHandler.Disconnect)          // sürüş bittikten sonra bağlantıyı keser, motoru kiralamaya açar
+
 	adminBluetooth := bluetooth.Group("/")
-	adminBluetooth.Use(middleware.AuthMiddleware(), middleware.AdminOnly()) // Admin yetkisi gerekli
-	adminBluetooth.Post("/", bluetoothHandler.Create)
-	adminBluetooth.Put("/:id", bluetoothHandler.Update)
-	adminBluetooth.Delete("/:id", bluetoothHandler.Delete)
-	adminBluetooth.Get("/", bluetoothHandler.List)
-	adminBluetooth.Get("/:id", bluetoothHandler.GetByID)
-
-	bluetooth.Use(middleware.AuthMiddleware())                          // Sadece authentication gerekli (normal kullanıcılar için)
-	bluetooth.Get("/my-connections", bluetoothHandler.GetMyConnections) // userın tüm geçmiş connectionlarını getirir.
-	bluetooth.Post("/connect", bluetoothHandler.Connect)                // userın tüm connectionlarını getirir.
-	bluetooth.Post("/disconnect", bluetoothHandler.Disconnect)
-	// todo: connect yapınca ride işlemini başlatsak mı acaba? yapıyı tekrar gözden geçir
+	adminBluetooth.Use(middleware.AuthMiddleware())
+	adminBluetooth.Post("/", requirePermission(model.PermBluetoothWrite), bluetoothHandler.Create)
+	adminBluetooth.Put("/:id", requirePermission(model.PermBluetoothWrite), bluetoothHandler.Update)
+	adminBluetooth.Delete("/:id", requirePermission(model.PermBluetoothWrite), bluetoothHandler.Delete)
+	adminBluetooth.Get("/", requirePermission(model.PermBluetoothRead), bluetoothHandler.List)
+	adminBluetooth.Get("/:id", requirePermission(model.PermBluetoothRead), bluetoothHandler.GetByID)
+
+	// Tariff routes
+	tariffs := v1.Group("/tariffs")
+	tariffs.Use(middleware.AuthMiddleware())
+	tariffs.Post("/", requirePermission(model.PermTariffsWrite), tariffHandler.Create)
+	tariffs.Get("/", requirePermission(model.PermTariffsRead), tariffHandler.List)
+	tariffs.Get("/:id", requirePermission(model.PermTariffsRead), tariffHandler.GetByID)
+	tariffs.Put("/:id", requirePermission(model.PermTariffsWrite), tariffHandler.Update)
+	tariffs.Delete("/:id", requirePermission(model.PermTariffsWrite), tariffHandler.Delete)
+
+	// Promotion routes
+	promotions := v1.Group("/promotions")
+	promotions.Use(middleware.AuthMiddleware())
+	promotions.Post("/", requirePermission(model.PermPromotionsWrite), promotionHandler.Create)
+	promotions.Get("/", requirePermission(model.PermPromotionsRead), promotionHandler.List)
+	promotions.Get("/usage", requirePermission(model.PermPromotionsRead), promotionHandler.UsageReport) // tüm kampanyaların ve davetlerin kullanım özeti
+	promotions.Get("/:id", requirePermission(model.PermPromotionsRead), promotionHandler.GetByID)
+	promotions.Get("/:id/usage", requirePermission(model.PermPromotionsRead), promotionHandler.Usage) // ?page=1&page_size=10
+	promotions.Put("/:id", requirePermission(model.PermPromotionsWrite), promotionHandler.Update)
+	promotions.Delete("/:id", requirePermission(model.PermPromotionsWrite), promotionHandler.Delete)
+
+	// Pass routes
+	passes := v1.Group("/passes")
+	userPasses := passes.Group("/")
+	userPasses.Use(middleware.AuthMiddleware())   // Sadece authentication gerekli (normal kullanıcılar için)
+	userPasses.Get("/", passHandler.ListProducts) // satıştaki paketler
+	userPasses.Post("/purchase", passHandler.Purchase)
+	userPasses.Get("/me", passHandler.GetMyPass)                     // aktif abonelik ve bugün kalan dahil dakikalar
+	userPasses.Delete("/me/auto-renew", passHandler.CancelAutoRenew) // abonelik dönem sonuna kadar geçerli kalır
+
+	adminPasses := passes.Group("/")
+	adminPasses.Use(middleware.AuthMiddleware())
+	adminPasses.Post("/products", requirePermission(model.PermPassesWrite), passHandler.CreateProduct)
+	adminPasses.Get("/products", requirePermission(model.PermPassesRead), passHandler.ListAllProducts)
+	adminPasses.Get("/products/:id", requirePermission(model.PermPassesRead), passHandler.GetProduct)
+	adminPasses.Put("/products/:id", requirePermission(model.PermPassesWrite), passHandler.UpdateProduct)
+	adminPasses.Delete("/products/:id", requirePermission(model.PermPassesWrite), passHandler.DeleteProduct)
+
+	// Reservation routes
+	reservations := v1.Group("/reservations")
+	reservations.Use(middleware.AuthMiddleware()) // Kullanıcı kendi rezervasyonunu, reservations.cancel yetkisi olan roller tüm rezervasyonları iptal edebilir
+	reservations.Get("/me", reservationHandler.GetMyActive)
+	reservations.Get("/me/history", reservationHandler.ListMyReservations)
+	reservations.Delete("/:id", reservationHandler.Cancel)
+
+	// Depodaki dosyalar; JWT yerine bağlantının imzası ve süresi doğrulanır
+	v1.Get("/files/*", fileHandler.Get)
+
+	// Device routes - motor üzerindeki cihazlar JWT yerine X-Device-Key ile doğrulanır
+	// Ödeme sağlayıcısı bildirimleri, istek imzası PaymentService'te doğrulanır
+	v1.Post("/payments/webhook", paymentHandler.Webhook)
+
+	devices := v1.Group("/devices")
+	devices.Use(middleware.DeviceAuthMiddleware(motorbikeService))
+	devices.Post("/telemetry", telemetryHandler.Ingest)
+	devices.Get("/commands", commandHandler.Poll) // /commands?wait=25 ile long polling
+	devices.Post("/commands/:id/ack", commandHandler.Ack)
+
+	// Zone routes
+	zones := v1.Group("/zones")
+	userZones := zones.Group("/")
+	userZones.Use(middleware.AuthMiddleware())
+	userZones.Get("/active", zoneHandler.ListActive) // haritada gösterilecek aktif alanlar
+	userZones.Get("/parking-check", zoneHandler.CheckParking)
+
+	adminZones := zones.Group("/")
+	adminZones.Use(middleware.AuthMiddleware())
+	adminZones.Post("/", requirePermission(model.PermZonesWrite), zoneHandler.Create)
+	adminZones.Get("/", requirePermission(model.PermZonesRead), zoneHandler.List)
+	adminZones.Get("/:id", requirePermission(model.PermZonesRead), zoneHandler.GetByID)
+	adminZones.Put("/:id", requirePermission(model.PermZonesWrite), zoneHandler.Update)
+	adminZones.Delete("/:id", requirePermission(model.PermZonesWrite), zoneHandler.Delete)
+
+	// Audit log routes
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), requirePermission(model.PermAuditRead))
+	admin.Get("/audit-logs", auditLogHandler.List) // ?actor_id=&action=&target_type=&target_id=&from=&to=
+	admin.Get("/audit-logs/export", auditLogHandler.Export)
+	admin.Get("/audit-logs/verify", auditLogHandler.Verify)
+}
+
+// newPaymentProvider yapılandırmadaki ödeme sağlayıcısını oluşturur. Bilinmeyen sağlayıcıyla sunucu başlatılmaz.
+func newPaymentProvider(cfg config.PaymentConfig) payment.PaymentProvider {
+	switch cfg.Provider {
+	case "fake":
+		provider := payment.NewFakeProvider(cfg.WebhookSecret)
+		provider.SetBehavior(payment.Behavior(cfg.FakeBehavior))
+		return provider
+	default:
+		panic(fmt.Sprintf("bilinmeyen ödeme sağlayıcısı: %q", cfg.Provider))
+	}
+}
+
+// newBlobStore yapılandırmadaki dosya deposunu oluşturur. Bilinmeyen sürücüyle sunucu başlatılmaz.
+func newBlobStore(cfg config.StorageConfig) storage.BlobStore {
+	switch cfg.Driver {
+	case "local":
+		return storage.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil)
+	default:
+		panic(fmt.Sprintf("bilinmeyen dosya deposu: %q", cfg.Driver))
+	}
+}
+
+// StartWorkers kayıtlı arka plan işlerini başlatır. ctx iptal edildiğinde işler durur.
+// SetupRoutes'tan sonra çağrılmalıdır.
+func (r *Router) StartWorkers(ctx context.Context) {
+	for _, worker := range r.workers {
+		go worker(ctx)
+	}
 }
 
 func (r *Router) GetApp() *fiber.App {
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+)
+
+// auditBatchSize dışa aktarma ve zincir doğrulamasında veritabanından tek seferde okunan kayıt sayısı
+const auditBatchSize = 1000
+
+// auditIgnoredFields güncelleme farkında gürültü olan alanlar; servislere gelen modellerde created_at çoğu zaman boştur
+var auditIgnoredFields = map[string]bool{"created_at": true, "updated_at": true}
+
+// Auditor servislerin yaptığı durum değişikliklerini denetim kaydına yazar. Kayıt işlemin sonucunu
+// değiştirmez; yazılamazsa hata loglanır. Bu yüzden işlem commit edildikten sonra çağrılmalıdır.
+type Auditor interface {
+	// RecordChange before ve after arasındaki farkı kaydeder; oluşturmada before, silmede after nil verilir
+	RecordChange(ctx context.Context, action, targetType string, targetID any, before, after any)
+}
+
+// NoopAuditor denetim kaydı tutulmayan ortamlar ve testler içindir
+type NoopAuditor struct{}
+
+func (NoopAuditor) RecordChange(ctx context.Context, action, targetType string, targetID any, before, after any) {
+}
+
+type AuditService struct {
+	auditRepo repository.IAuditLogRepository
+	txManager repository.ITransactionManager
+}
+
+func NewAuditService(auditRepo repository.IAuditLogRepository, txManager repository.ITransactionManager) *AuditService {
+	return &AuditService{auditRepo: auditRepo, txManager: txManager}
+}
+
+// Record kaydı zincirin sonuna ekler. Aktör, IP, user agent ve request id boş bırakılmışsa istek bağlamından alınır.
+// Zincire eklemeler advisory lock ile sıralanır; böylece her kaydın önceki özeti gerçekten bir önceki kayda aittir.
+func (s *AuditService) Record(ctx context.Context, entry model.AuditLog) error {
+	fillAuditContext(ctx, &entry)
+	sanitizeAuditLog(&entry)
+
+	var err error
+	if entry.Before, err = canonicalJSON(entry.Before); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if entry.After, err = canonicalJSON(entry.After); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.auditRepo.LockChain(ctx); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		last, err := s.auditRepo.GetLast(ctx)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if last != nil {
+			entry.PrevHash = last.Hash
+		}
+		// Zaman kilit alındıktan sonra verilir, böylece zincirdeki sıra ile zaman sırası aynı olur
+		entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		if entry.Hash, err = auditHash(entry); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if err = s.auditRepo.Create(ctx, &entry); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+}
+
+// RecordChange yalnızca değişen alanları kaydeder
+func (s *AuditService) RecordChange(ctx context.Context, action, targetType string, targetID any, before, after any) {
+	beforeDiff, afterDiff, err := diffAuditValues(before, after)
+	if err != nil {
+		logger.Error("Denetim farkı hesaplanamadı (%s %s/%v): %v", action, targetType, targetID, err)
+		return
+	}
+	entry := model.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   fmt.Sprint(targetID),
+		Before:     beforeDiff,
+		After:      afterDiff,
+	}
+	if err = s.Record(ctx, entry); err != nil {
+		logger.Error("Denetim kaydı yazılamadı (%s %s/%v): %v", action, targetType, targetID, err)
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, filter model.AuditLogFilter, pagination *query.Pagination) ([]model.AuditLog, error) {
+	logs, err := s.auditRepo.List(ctx, filter, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return logs, nil
+}
+
+// Export filtreye uyan kayıtları eskiden yeniye getirir. maxRows aşılırsa filtrenin daraltılması istenir.
+func (s *AuditService) Export(ctx context.Context, filter model.AuditLogFilter, maxRows int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	var afterID int64
+	for {
+		batch, err := s.auditRepo.ListAfter(ctx, filter, afterID, auditBatchSize)
+		if err != nil {
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		logs = append(logs, batch...)
+		if len(logs) > maxRows {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Dışa aktarılacak kayıt sayısı %d sınırını aşıyor, tarih aralığını daraltın", maxRows))
+		}
+		if len(batch) < auditBatchSize {
+			return logs, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// Verify zinciri baştan sona okuyup her kaydın özetini yeniden hesaplar. Değiştirilmiş bir kayıt kendi özetini,
+// silinmiş bir kayıt ise kendisinden sonraki kaydın önceki özet alanını tutmaz.
+func (s *AuditService) Verify(ctx context.Context) (*model.AuditVerification, error) {
+	result := &model.AuditVerification{Valid: true}
+	var prevHash string
+	var afterID int64
+	for {
+		batch, err := s.auditRepo.ListAfter(ctx, model.AuditLogFilter{}, afterID, auditBatchSize)
+		if err != nil {
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		for _, entry := range batch {
+			result.Checked++
+			if entry.PrevHash != prevHash {
+				result.Valid, result.BrokenAtID, result.Reason = false, entry.ID, "Önceki kaydın özeti eşleşmiyor, araya kayıt eklenmiş veya kayıt silinmiş"
+				return result, nil
+			}
+			hash, err := auditHash(entry)
+			if err != nil {
+				return nil, errorx.WrapErr(errorx.ErrInternal, err)
+			}
+			if hash != entry.Hash {
+				result.Valid, result.BrokenAtID, result.Reason = false, entry.ID, "Kaydın içeriği değiştirilmiş"
+				return result, nil
+			}
+			prevHash = entry.Hash
+		}
+		if len(batch) < auditBatchSize {
+			return result, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// auditHashPayload özete giren alanlar; alan sırası sabittir, değiştirilirse mevcut zincir doğrulanamaz
+type auditHashPayload struct {
+	PrevHash   string          `json:"prev_hash"`
+	CreatedAt  string          `json:"created_at"`
+	ActorID    *int64          `json:"actor_id"`
+	ActorRole  model.Role      `json:"actor_role"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	StatusCode int             `json:"status_code"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"user_agent"`
+	RequestID  string          `json:"request_id"`
+}
+
+func auditHash(entry model.AuditLog) (string, error) {
+	// jsonb alanlar veritabanından farklı biçimde dönebileceği için özet kanonik hallerinden hesaplanır
+	before, err := canonicalJSON(entry.Before)
+	if err != nil {
+		return "", err
+	}
+	after, err := canonicalJSON(entry.After)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(auditHashPayload{
+		PrevHash:   entry.PrevHash,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     before,
+		After:      after,
+		Method:     entry.Method,
+		Path:       entry.Path,
+		StatusCode: entry.StatusCode,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON nesne anahtarlarını sıralı, boşluksuz yazar; boş değer ve null için nil döner
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	value, err := decodeAuditJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(normalizeAuditNumbers(value))
+}
+
+// normalizeAuditNumbers üslü sayıları PostgreSQL'in jsonb çıktısındaki gibi ondalık yazar; aksi halde
+// okunan kaydın özeti yazılırken hesaplanandan farklı çıkardı
+func normalizeAuditNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeAuditNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeAuditNumbers(item)
+		}
+	case json.Number:
+		text := string(v)
+		exp := strings.IndexAny(text, "eE")
+		if exp < 0 {
+			return v
+		}
+		mantissa := text[:exp]
+		power, err := strconv.Atoi(text[exp+1:])
+		if err != nil {
+			return v
+		}
+		rat, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return v
+		}
+		scale := 0
+		if dot := strings.IndexByte(mantissa, '.'); dot >= 0 {
+			scale = len(mantissa) - dot - 1
+		}
+		return json.Number(rat.FloatString(max(scale-power, 0)))
+	}
+	return value
+}
+
+// decodeAuditJSON sayıları json.Number olarak okur; böylece büyük tamsayılar float'a çevrilirken bozulmaz
+func decodeAuditJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// auditFields değeri JSON alanlarına ayırır; nesne olmayan değerler "value" alanında tutulur
+func auditFields(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeAuditJSON(data)
+	if err != nil || decoded == nil {
+		return nil, err
+	}
+	if fields, ok := decoded.(map[string]any); ok {
+		return fields, nil
+	}
+	return map[string]any{"value": decoded}, nil
+}
+
+// diffAuditValues oluşturma ve silmede kaydın tamamını, güncellemede yalnızca değişen alanları döner
+func diffAuditValues(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if beforeFields == nil || afterFields == nil {
+		return marshalAuditFields(beforeFields, afterFields)
+	}
+
+	keys := make(map[string]bool, len(beforeFields))
+	for key := range beforeFields {
+		keys[key] = true
+	}
+	for key := range afterFields {
+		keys[key] = true
+	}
+	changedBefore, changedAfter := map[string]any{}, map[string]any{}
+	for key := range keys {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		oldValue, oldOK := beforeFields[key]
+		newValue, newOK := afterFields[key]
+		oldJSON, _ := json.Marshal(oldValue)
+		newJSON, _ := json.Marshal(newValue)
+		if oldOK == newOK && bytes.Equal(oldJSON, newJSON) {
+			continue
+		}
+		if oldOK {
+			changedBefore[key] = oldValue
+		}
+		if newOK {
+			changedAfter[key] = newValue
+		}
+	}
+	return marshalAuditFields(changedBefore, changedAfter)
+}
+
+func marshalAuditFields(before, after map[string]any) (json.RawMessage, json.RawMessage, error) {
+	var beforeJSON, afterJSON json.RawMessage
+	var err error
+	if before != nil {
+		if beforeJSON, err = json.Marshal(before); err != nil {
+			return nil, nil, err
+		}
+	}
+	if after != nil {
+		if afterJSON, err = json.Marshal(after); err != nil {
+			return nil, nil, err
+		}
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// fillAuditContext istek bağlamındaki kullanıcı ve istek bilgilerini kayda ekler. Değerler Fiber Locals olarak
+// AuthMiddleware, requestid ve AuditMiddleware tarafından yazılır; c.Context() üzerinden servislere ulaşır.
+func fillAuditContext(ctx context.Context, entry *model.AuditLog) {
+	if entry.ActorID == nil {
+		if userID, ok := ctx.Value("userID").(int64); ok {
+			entry.ActorID = &userID
+		}
+	}
+	if entry.ActorRole == "" {
+		entry.ActorRole, _ = ctx.Value("role").(model.Role)
+	}
+	if entry.IP == "" {
+		entry.IP, _ = ctx.Value("client_ip").(string)
+	}
+	if entry.UserAgent == "" {
+		entry.UserAgent, _ = ctx.Value("user_agent").(string)
+	}
+	if entry.RequestID == "" {
+		entry.RequestID, _ = ctx.Value("requestid").(string)
+	}
+}
+
+// sanitizeAuditLog istemciden gelen metinleri kolon boyutlarına sığdırır; geçersiz UTF-8 ve NUL
+// karakterleri PostgreSQL'in kaydı reddetmesine, dolayısıyla iz kaybına yol açardı
+func sanitizeAuditLog(entry *model.AuditLog) {
+	entry.Action = auditText(entry.Action, 150)
+	entry.TargetType = auditText(entry.TargetType, 50)
+	entry.TargetID = auditText(entry.TargetID, 100)
+	entry.Method = auditText(entry.Method, 10)
+	entry.Path = auditText(entry.Path, 2048)
+	entry.IP = auditText(entry.IP, 64)
+	entry.UserAgent = auditText(entry.UserAgent, 512)
+	entry.RequestID = auditText(entry.RequestID, 64)
+}
+
+func auditText(value string, maxRunes int) string {
+	value = strings.ReplaceAll(strings.ToValidUTF8(value, ""), "\x00", "")
+	if utf8.RuneCountInString(value) <= maxRunes {
+		return value
+	}
+	return string([]rune(value)[:maxRunes])
+}
@@ -10,18 +10,24 @@ import (
 )
 
 type AuthService struct {
-	authRepo repository.IAuthRepository
-	userRepo repository.IUserRepository
+	authRepo  repository.IAuthRepository
+	userRepo  repository.IUserRepository
+	referrals *ReferralService
+	txManager repository.ITransactionManager
 }
 
-func NewAuthService(a repository.IAuthRepository, u repository.IUserRepository) *AuthService {
+func NewAuthService(a repository.IAuthRepository, u repository.IUserRepository, referrals *ReferralService, txManager repository.ITransactionManager) *AuthService {
 	return &AuthService{
-		authRepo: a,
-		userRepo: u,
+		authRepo:  a,
+		userRepo:  u,
+		referrals: referrals,
+		txManager: txManager,
 	}
 }
 
-func (s *AuthService) Register(ctx context.Context, user model.User) error {
+// Register yeni kullanıcıyı kaydeder. Davet kodu verildiyse kullanıcı davet eden kullanıcıya bağlanır;
+// davet kredisi ilk sürüş tamamlandığında verilir.
+func (s *AuthService) Register(ctx context.Context, user model.User, referralCode string) error {
 	// Email kontrolü
 	exists, err := s.userRepo.ExistsByEmail(ctx, user.Email)
 	if err != nil {
@@ -31,9 +37,26 @@ func (s *AuthService) Register(ctx context.Context, user model.User) error {
 		return errorx.WrapMsg(errorx.ErrDuplicate, "Bu e-posta adresi zaten kullanımda")
 	}
 
+	// Davet kodu kontrolü
+	var referrer *model.ReferralCode
+	if referralCode != "" {
+		if referrer, err = s.referrals.Resolve(ctx, referralCode); err != nil {
+			return err
+		}
+	}
+
 	// Kullanıcıyı kaydet
-	if err = s.userRepo.Create(ctx, &user); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, &user); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if referrer != nil {
+			return s.referrals.Link(ctx, referrer, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
 	}
 
 	return nil
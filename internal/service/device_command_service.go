@@ -0,0 +1,474 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+)
+
+const (
+	// Worker'ın tek turda işleyeceği en fazla komut sayısı
+	deviceCommandSweepBatchSize = 100
+	// Admin ekranında listelenecek en fazla komut sayısı
+	deviceCommandListLimit = 50
+)
+
+// EnqueueCommand kuyruğa eklenecek komut bilgileri
+type EnqueueCommand struct {
+	MotorbikeID int64
+	Type        model.DeviceCommandType
+	RequestedBy *int64
+	RideID      *int64
+}
+
+// DeviceCommander motora komut gönderen akışların kullandığı arayüz
+type DeviceCommander interface {
+	Enqueue(ctx context.Context, cmd EnqueueCommand) (*model.DeviceCommand, error)
+}
+
+type DeviceCommandService struct {
+	commandRepo repository.IDeviceCommandRepository
+	motorRepo   repository.IMotorbikeRepository
+	txManager   repository.ITransactionManager
+	ackTimeout  time.Duration
+	maxAttempts int
+	ttl         time.Duration
+	notifier    *commandNotifier // motor bazında, yeni komut bekleyen cihaz sorgularını uyandırır
+	completions *commandNotifier // komut bazında, sonucu bekleyen akışları uyandırır
+}
+
+// DeviceCommandServiceDeps DeviceCommandService'in ihtiyaç duyduğu repository ve ayarlar
+type DeviceCommandServiceDeps struct {
+	CommandRepo   repository.IDeviceCommandRepository
+	MotorbikeRepo repository.IMotorbikeRepository
+	TxManager     repository.ITransactionManager
+	AckTimeout    time.Duration // her gönderimden sonra onay için beklenen süre
+	MaxAttempts   int
+	TTL           time.Duration // komutun toplam geçerlilik süresi
+}
+
+func NewDeviceCommandService(deps DeviceCommandServiceDeps) *DeviceCommandService {
+	maxAttempts := deps.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &DeviceCommandService{
+		commandRepo: deps.CommandRepo,
+		motorRepo:   deps.MotorbikeRepo,
+		txManager:   deps.TxManager,
+		ackTimeout:  deps.AckTimeout,
+		maxAttempts: maxAttempts,
+		ttl:         deps.TTL,
+		notifier:    newCommandNotifier(),
+		completions: newCommandNotifier(),
+	}
+}
+
+// Enqueue motor için yeni bir komut oluşturur ve bekleyen cihaz sorgularını uyandırır
+func (s *DeviceCommandService) Enqueue(ctx context.Context, cmd EnqueueCommand) (*model.DeviceCommand, error) {
+	if !cmd.Type.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz komut tipi")
+	}
+
+	command := &model.DeviceCommand{
+		MotorbikeID: cmd.MotorbikeID,
+		Type:        cmd.Type,
+		Status:      model.CommandPending,
+		MaxAttempts: s.maxAttempts,
+		ExpiresAt:   time.Now().UTC().Add(s.ttl),
+		RequestedBy: cmd.RequestedBy,
+		RideID:      cmd.RideID,
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.motorRepo.GetByID(ctx, cmd.MotorbikeID); err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		if err := s.commandRepo.Create(ctx, command); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return s.addEvent(ctx, command, "")
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	s.notifier.notify(cmd.MotorbikeID)
+	return command, nil
+}
+
+// Poll motora teslim edilecek komutları gönderildi olarak işaretleyip döner. Teslim edilecek komut yoksa
+// wait süresi boyunca yeni komut eklenmesini bekler (long polling). Bekleme yalnızca bu süreçte eklenen
+// komutlarla uyanır; diğer sunuculardan eklenen komutlar bir sonraki sorguda teslim edilir.
+func (s *DeviceCommandService) Poll(ctx context.Context, motorbikeID int64, wait time.Duration) ([]model.DeviceCommand, error) {
+	deadline := time.Now().Add(wait)
+
+	for {
+		// Komut kontrolü ile bekleme arasında eklenen komut kaçmasın diye önce abone olunur
+		wake, cancel := s.notifier.subscribe(motorbikeID)
+
+		commands, err := s.claim(ctx, motorbikeID)
+		if err != nil || len(commands) > 0 {
+			cancel()
+			return commands, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			cancel()
+			return []model.DeviceCommand{}, nil
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-wake:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			cancel()
+			return []model.DeviceCommand{}, nil
+		}
+		timer.Stop()
+		cancel()
+	}
+}
+
+// claim teslim edilebilir komutları kilitler, deneme sayısını artırır ve onay süresini başlatır
+func (s *DeviceCommandService) claim(ctx context.Context, motorbikeID int64) ([]model.DeviceCommand, error) {
+	var commands []model.DeviceCommand
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+		deliverable, err := s.commandRepo.ListDeliverable(ctx, motorbikeID, now)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		for i := range deliverable {
+			command := &deliverable[i]
+			ackDeadline := now.Add(s.ackTimeout)
+			command.Status = model.CommandSent
+			command.Attempts++
+			command.SentAt = &now
+			command.AckDeadline = &ackDeadline
+
+			if err = s.commandRepo.Update(ctx, command); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+			if err = s.addEvent(ctx, command, ""); err != nil {
+				return err
+			}
+		}
+
+		commands = deliverable
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return commands, nil
+}
+
+// Ack cihazın komut sonucunu işler. Başarılı lock/unlock/disable komutları motorun durumuna yansıtılır.
+// Başarısız sonuçta deneme hakkı varsa komut tekrar kuyruğa alınır, yoksa başarısız olarak kapatılır.
+// Aynı başarılı onayın tekrar gönderilmesi hata döndürmez.
+func (s *DeviceCommandService) Ack(ctx context.Context, motorbikeID, commandID int64, success bool, message string) (*model.DeviceCommand, error) {
+	var command *model.DeviceCommand
+	retry := false
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		command, err = s.commandRepo.GetByIDForUpdate(ctx, commandID)
+		if err != nil || command.MotorbikeID != motorbikeID {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Komut bulunamadı")
+		}
+
+		if command.Status == model.CommandAcknowledged && success {
+			return nil
+		}
+		if command.Status != model.CommandSent {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Komut onay beklemiyor")
+		}
+
+		now := time.Now().UTC()
+		if success {
+			command.Status = model.CommandAcknowledged
+			command.CompletedAt = &now
+			command.LastError = ""
+			if err = s.applyToMotorbike(ctx, command); err != nil {
+				return err
+			}
+		} else {
+			command.LastError = message
+			if command.Attempts < command.MaxAttempts && now.Before(command.ExpiresAt) {
+				command.Status = model.CommandPending
+				retry = true
+			} else {
+				command.Status = model.CommandFailed
+				command.CompletedAt = &now
+			}
+		}
+
+		if err = s.commandRepo.Update(ctx, command); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return s.addEvent(ctx, command, message)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	if retry {
+		s.notifier.notify(motorbikeID)
+	} else {
+		s.completions.notify(command.ID)
+	}
+	return command, nil
+}
+
+// applyToMotorbike onaylanan komutun sonucunu motorun kayıtlı durumuna yazar
+func (s *DeviceCommandService) applyToMotorbike(ctx context.Context, command *model.DeviceCommand) error {
+	motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, command.MotorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+
+	switch command.Type {
+	case model.CommandLock:
+		motorbike.LockStatus = model.Locked
+		err = s.motorRepo.UpdateColumns(ctx, motorbike, "lock_status")
+	case model.CommandUnlock:
+		motorbike.LockStatus = model.Unlocked
+		err = s.motorRepo.UpdateColumns(ctx, motorbike, "lock_status")
+	case model.CommandDisable:
+		// Devre dışı bırakılan motor kilitlenir ve admin tekrar açana kadar kiralanamaz
+		motorbike.LockStatus = model.Locked
+		motorbike.Status = model.BikeInMaintenance
+		err = s.motorRepo.UpdateColumns(ctx, motorbike, "lock_status", "status")
+	default:
+		return nil
+	}
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor durumu güncellenirken hata oluştu!")
+	}
+	return nil
+}
+
+// EnqueueAndWait komutu kuyruğa ekler ve cihaz sonucu bildirene kadar en fazla timeout kadar bekler.
+// Süre dolarsa komut iptal edilir ki cihaz daha sonra bağlanınca eski bir komutu uygulamasın.
+func (s *DeviceCommandService) EnqueueAndWait(ctx context.Context, cmd EnqueueCommand, timeout time.Duration) (*model.DeviceCommand, error) {
+	command, err := s.Enqueue(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Onay, abonelikten önce gelmiş olabilir; bu yüzden abone olduktan sonra durum tekrar okunur
+		done, cancel := s.completions.subscribe(command.ID)
+		current, err := s.commandRepo.GetByID(ctx, command.ID)
+		if err != nil {
+			cancel()
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if current.IsFinal() {
+			cancel()
+			return current, commandResultError(current)
+		}
+
+		select {
+		case <-done:
+			cancel()
+		case <-timer.C:
+			cancel()
+			return s.cancelAfterTimeout(ctx, command.ID)
+		case <-ctx.Done():
+			cancel()
+			return s.cancelAfterTimeout(context.WithoutCancel(ctx), command.ID)
+		}
+	}
+}
+
+// cancelAfterTimeout sonucu beklenen süre içinde gelmeyen komutu başarısız olarak kapatır.
+// Bu arada onay geldiyse onaylanan komut döner.
+func (s *DeviceCommandService) cancelAfterTimeout(ctx context.Context, commandID int64) (*model.DeviceCommand, error) {
+	var command *model.DeviceCommand
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		command, err = s.commandRepo.GetByIDForUpdate(ctx, commandID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if command.IsFinal() {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		command.Status = model.CommandFailed
+		command.LastError = "Cihaz yanıt süresi içinde komutu onaylamadı"
+		command.CompletedAt = &now
+		if err = s.commandRepo.Update(ctx, command); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return s.addEvent(ctx, command, command.LastError)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return command, commandResultError(command)
+}
+
+// commandResultError son durumdaki komutun sonucunu hata olarak döner, onaylandıysa nil
+func commandResultError(command *model.DeviceCommand) error {
+	switch command.Status {
+	case model.CommandAcknowledged:
+		return nil
+	case model.CommandExpired:
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor cihazına ulaşılamadı, komutun süresi doldu")
+	default:
+		if command.LastError != "" {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor cihazı komutu uygulayamadı: "+command.LastError)
+		}
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor cihazı komutu uygulayamadı")
+	}
+}
+
+// ListByMotorbikeID motorun son komutlarını durum geçmişiyle getirir
+func (s *DeviceCommandService) ListByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.DeviceCommand, error) {
+	commands, err := s.commandRepo.ListByMotorbikeID(ctx, motorbikeID, deviceCommandListLimit)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return commands, nil
+}
+
+func (s *DeviceCommandService) GetByID(ctx context.Context, id int64) (*model.DeviceCommand, error) {
+	command, err := s.commandRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Komut bulunamadı")
+	}
+	return command, nil
+}
+
+// SweepTimedOut geçerlilik süresi dolan komutları expired, son denemesi onaysız kalan komutları failed olarak kapatır
+func (s *DeviceCommandService) SweepTimedOut(ctx context.Context, now time.Time) (int, error) {
+	commands, err := s.commandRepo.ListTimedOut(ctx, now, deviceCommandSweepBatchSize)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	closed := 0
+	for _, candidate := range commands {
+		err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+			command, err := s.commandRepo.GetByIDForUpdate(ctx, candidate.ID)
+			if err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+
+			note := ""
+			switch {
+			case command.IsFinal():
+				return nil // bu arada onaylanmış olabilir
+			case !now.Before(command.ExpiresAt):
+				command.Status = model.CommandExpired
+			case command.Status == model.CommandSent && command.AckDeadline != nil && !now.Before(*command.AckDeadline) && command.Attempts >= command.MaxAttempts:
+				command.Status = model.CommandFailed
+				command.LastError = "Cihazdan onay alınamadı"
+				note = command.LastError
+			default:
+				return nil
+			}
+			command.CompletedAt = &now
+
+			if err = s.commandRepo.Update(ctx, command); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+			closed++
+			return s.addEvent(ctx, command, note)
+		})
+		if err != nil {
+			return closed, errorx.FromError(errorx.ErrInternal, err)
+		}
+		s.completions.notify(candidate.ID)
+	}
+	return closed, nil
+}
+
+// RunTimeoutWorker zaman aşımına uğrayan komutları ctx iptal edilene kadar belirli aralıklarla kapatır
+func (s *DeviceCommandService) RunTimeoutWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.SweepTimedOut(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Error("Cihaz komutu zaman aşımı kontrolü hatası: %v", err)
+				continue
+			}
+			if count > 0 {
+				logger.Info("%d cihaz komutu zaman aşımıyla kapatıldı", count)
+			}
+		}
+	}
+}
+
+func (s *DeviceCommandService) addEvent(ctx context.Context, command *model.DeviceCommand, note string) error {
+	event := &model.DeviceCommandEvent{CommandID: command.ID, Status: command.Status, Note: note}
+	if err := s.commandRepo.AddEvent(ctx, event); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Komut geçmişi kaydedilemedi")
+	}
+	return nil
+}
+
+// commandNotifier aynı süreçte bir anahtarı (motor veya komut ID'si) bekleyenleri uyandırır
+type commandNotifier struct {
+	mu      sync.Mutex
+	waiters map[int64]map[chan struct{}]struct{}
+}
+
+func newCommandNotifier() *commandNotifier {
+	return &commandNotifier{waiters: map[int64]map[chan struct{}]struct{}{}}
+}
+
+func (n *commandNotifier) subscribe(key int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	if n.waiters[key] == nil {
+		n.waiters[key] = map[chan struct{}]struct{}{}
+	}
+	n.waiters[key][ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.waiters[key], ch)
+		if len(n.waiters[key]) == 0 {
+			delete(n.waiters, key)
+		}
+	}
+}
+
+func (n *commandNotifier) notify(key int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.waiters[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+)
+
+type DisputeServiceDeps struct {
+	DisputeRepo  repository.IDisputeRepository
+	RideRepo     repository.IRideRepository
+	UserRepo     repository.IUserRepository
+	TxManager    repository.ITransactionManager
+	Wallet       *WalletService
+	Payments     *PaymentService
+	Mailer       Mailer            // nil ise bildirim gönderilmez
+	SupportEmail string            // boş değilse yeni itirazlar bu adrese de bildirilir
+	Window       time.Duration     // sürüş bittikten sonra itiraz açılabilecek süre
+	Permissions  PermissionChecker // başkasının itirazını görmek için disputes.read gerekir
+	Audit        Auditor
+}
+
+// DisputeService sürüş ücretlerine yapılan itirazları yönetir. Kullanıcı tamamlanmış sürüşüne itiraz açar, admin inceler
+// ve tamamını veya bir kısmını iade ederek onaylar ya da reddeder. İadeler deftere ve değiştirilemeyen ride_adjustments
+// tablosuna işlemi yapan admin ile birlikte yazılır; sürüşün kendisi değiştirilmez. Her adımda kullanıcıya e-posta gönderilir.
+type DisputeService struct {
+	disputeRepo  repository.IDisputeRepository
+	rideRepo     repository.IRideRepository
+	userRepo     repository.IUserRepository
+	txManager    repository.ITransactionManager
+	wallet       *WalletService
+	payments     *PaymentService
+	mailer       Mailer
+	supportEmail string
+	window       time.Duration
+	permissions  PermissionChecker
+	audit        Auditor
+}
+
+func NewDisputeService(deps DisputeServiceDeps) *DisputeService {
+	return &DisputeService{
+		disputeRepo:  deps.DisputeRepo,
+		rideRepo:     deps.RideRepo,
+		userRepo:     deps.UserRepo,
+		txManager:    deps.TxManager,
+		wallet:       deps.Wallet,
+		payments:     deps.Payments,
+		mailer:       deps.Mailer,
+		supportEmail: deps.SupportEmail,
+		window:       deps.Window,
+		permissions:  deps.Permissions,
+		audit:        deps.Audit,
+	}
+}
+
+// OpenDisputeInput kullanıcının açtığı itiraz. Fotoğraflar handler tarafından diske kaydedilmiş olmalıdır.
+type OpenDisputeInput struct {
+	RideID          int64
+	UserID          int64
+	Reason          model.DisputeReason
+	Description     string
+	RequestedAmount int64
+	Photos          []model.DisputePhoto
+}
+
+// ApproveDisputeInput admin'in onayladığı iade
+type ApproveDisputeInput struct {
+	DisputeID   int64
+	AdminID     int64
+	Amount      int64
+	Destination model.RefundDestination
+	Note        string
+}
+
+// Open kullanıcının tamamlanmış sürüşüne itiraz açar. Sürüşün bitişinden itibaren itiraz süresi geçmemiş, ücretinin
+// iade edilmemiş kısmı kalmış ve sürüşün sonuçlanmamış başka bir itirazı olmamalıdır.
+func (s *DisputeService) Open(ctx context.Context, input OpenDisputeInput) (*model.Dispute, error) {
+	if !input.Reason.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz itiraz nedeni")
+	}
+
+	ride, err := s.rideRepo.GetByID(ctx, input.RideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.UserID != input.UserID {
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+	}
+	if !ride.Status.HasEnded() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş henüz bitirilmedi")
+	}
+	if s.window > 0 && time.Since(*ride.EndTime) > s.window {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Sürüşe bittikten sonraki %d gün içinde itiraz edilebilir", int(s.window.Hours()/24)))
+	}
+
+	refundable, err := s.refundable(ctx, ride)
+	if err != nil {
+		return nil, err
+	}
+	if refundable <= 0 {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüşün iade edilebilecek ücreti yok")
+	}
+	if input.RequestedAmount < 0 || input.RequestedAmount > refundable {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Talep edilen tutar en fazla "+money.Format(refundable, ride.Currency)+" olabilir")
+	}
+
+	dispute := &model.Dispute{
+		RideID:          ride.ID,
+		UserID:          input.UserID,
+		Reason:          input.Reason,
+		Description:     strings.TrimSpace(input.Description),
+		RequestedAmount: input.RequestedAmount,
+		Status:          model.DisputeOpen,
+	}
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		active, err := s.disputeRepo.GetActiveByRideID(ctx, ride.ID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if active != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüş için sonuçlanmamış bir itiraz zaten var")
+		}
+
+		if err = s.disputeRepo.Create(ctx, dispute); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İtiraz kaydedilemedi")
+		}
+		for i := range input.Photos {
+			input.Photos[i].DisputeID = dispute.ID
+		}
+		if err = s.disputeRepo.CreatePhotos(ctx, input.Photos); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İtiraz fotoğrafları kaydedilemedi")
+		}
+		dispute.Photos = input.Photos
+		return s.recordEvent(ctx, dispute, "", input.UserID, "")
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	s.notify(ctx, dispute)
+	s.notifySupport(dispute)
+	return dispute, nil
+}
+
+// Get itirazı fotoğrafları ve durum geçmişiyle getirir. disputes.read yetkisi olmayan kullanıcı yalnızca kendi itirazını görebilir.
+func (s *DisputeService) Get(ctx context.Context, id, userID int64, role model.Role) (*model.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "İtiraz bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermDisputesRead, dispute.UserID, userID, "Bu itiraza erişim yetkiniz yok"); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetPhoto itirazın fotoğrafını getirir. Kullanıcı yalnızca kendi itirazının fotoğraflarını görebilir.
+func (s *DisputeService) GetPhoto(ctx context.Context, disputeID, photoID, userID int64, role model.Role) (*model.DisputePhoto, error) {
+	if _, err := s.Get(ctx, disputeID, userID, role); err != nil {
+		return nil, err
+	}
+	photo, err := s.disputeRepo.GetPhoto(ctx, disputeID, photoID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if photo == nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Fotoğraf bulunamadı")
+	}
+	return photo, nil
+}
+
+func (s *DisputeService) ListByUser(ctx context.Context, userID int64) ([]model.Dispute, error) {
+	disputes, err := s.disputeRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return disputes, nil
+}
+
+// List destek kuyruğunu filtreleyerek en eski itiraz başta olacak şekilde sayfalı getirir
+func (s *DisputeService) List(ctx context.Context, filter model.DisputeFilter, pagination *query.Pagination) ([]model.Dispute, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz itiraz durumu")
+	}
+	if filter.Reason != "" && !filter.Reason.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz itiraz nedeni")
+	}
+	disputes, err := s.disputeRepo.List(ctx, filter, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return disputes, nil
+}
+
+// ListRideAdjustments sürüşte yapılan iadeleri eskiden yeniye getirir
+func (s *DisputeService) ListRideAdjustments(ctx context.Context, rideID int64) ([]model.RideAdjustment, error) {
+	if _, err := s.rideRepo.GetByID(ctx, rideID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	adjustments, err := s.disputeRepo.ListAdjustmentsByRideID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return adjustments, nil
+}
+
+// Withdraw kullanıcının sonuçlanmamış itirazını geri çekmesidir
+func (s *DisputeService) Withdraw(ctx context.Context, id, userID int64, note string) (*model.Dispute, error) {
+	if _, err := s.Get(ctx, id, userID, model.UserRole); err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, id, userID, "disputes.withdraw", model.DisputeWithdrawn, note, nil, nil)
+}
+
+// StartReview itirazı inceleyen admin'e atar
+func (s *DisputeService) StartReview(ctx context.Context, id, adminID int64, note string) (*model.Dispute, error) {
+	return s.transition(ctx, id, adminID, "disputes.review", model.DisputeInReview, note, func(ctx context.Context, dispute *model.Dispute) error {
+		dispute.ReviewerID = &adminID
+		return nil
+	}, nil)
+}
+
+// Reject itirazı gerekçesiyle reddeder, para hareketi olmaz
+func (s *DisputeService) Reject(ctx context.Context, id, adminID int64, note string) (*model.Dispute, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Ret gerekçesi zorunludur")
+	}
+	return s.transition(ctx, id, adminID, "disputes.reject", model.DisputeRejected, note, func(ctx context.Context, dispute *model.Dispute) error {
+		dispute.ReviewerID = &adminID
+		dispute.ResolutionNote = note
+		return nil
+	}, nil)
+}
+
+// Approve itirazı onaylar ve tutarı cüzdana veya karta iade eder. Sürüşe yapılan tüm iadelerin toplamı sürüş ücretini,
+// karta yapılan iadelerin toplamı karttan tahsil edilen tutarı aşamaz. İade, deftere ve ride_adjustments tablosuna
+// admin ile birlikte yazılır. Karta iade sağlayıcıda onay kaydedildikten sonra yapılır; böylece sağlayıcı çağrısı
+// başarısız olursa veya sonucu yazılamazsa mutabakat için bekleyen bir iade kaydı kalır.
+func (s *DisputeService) Approve(ctx context.Context, input ApproveDisputeInput) (*model.Dispute, error) {
+	if input.Amount <= 0 {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "İade tutarı sıfırdan büyük olmalı")
+	}
+	if input.Destination != model.RefundToWallet && input.Destination != model.RefundToCard {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "İade cüzdana veya karta yapılabilir")
+	}
+
+	var adjustment *model.RideAdjustment
+	apply := func(ctx context.Context, dispute *model.Dispute) error {
+		// Aynı sürüşe yapılan iadeler sürüş satırı kilitlenerek sırayla yazılır
+		ride, err := s.rideRepo.GetByIDForUpdate(ctx, dispute.RideID)
+		if err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Sürüş alınamadı")
+		}
+		adjustments, err := s.disputeRepo.ListAdjustmentsByRideID(ctx, ride.ID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		var refunded, cardRefunded int64
+		for _, adjustment := range adjustments {
+			refunded += adjustment.Amount
+			if adjustment.Destination == model.RefundToCard {
+				cardRefunded += adjustment.Amount
+			}
+		}
+		if refundable := ride.Cost - refunded; input.Amount > refundable {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüş için en fazla "+money.Format(max(refundable, 0), ride.Currency)+" iade edilebilir")
+		}
+
+		var refundStatus model.ProviderRefundStatus
+		if input.Destination == model.RefundToCard {
+			if err = s.payments.CheckRefund(ctx, ride.ID, input.Amount, cardRefunded); err != nil {
+				return err
+			}
+			refundStatus = model.ProviderRefundPending
+		}
+
+		transaction, err := s.wallet.RefundRide(ctx, ride.UserID, ride.ID, input.Amount, ride.Currency, input.Destination == model.RefundToCard, input.AdminID)
+		if err != nil {
+			return err
+		}
+		disputeID := dispute.ID
+		adjustment = &model.RideAdjustment{
+			RideID:               ride.ID,
+			UserID:               ride.UserID,
+			DisputeID:            &disputeID,
+			Amount:               input.Amount,
+			Currency:             transaction.Currency,
+			Destination:          input.Destination,
+			LedgerTransactionID:  transaction.ID,
+			ProviderRefundStatus: refundStatus,
+			Reason:               adjustmentReason(dispute, input.Note),
+			CreatedBy:            input.AdminID,
+		}
+		if err = s.disputeRepo.CreateAdjustment(ctx, adjustment); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İade kaydı oluşturulamadı")
+		}
+
+		dispute.ReviewerID = &input.AdminID
+		dispute.ResolutionNote = strings.TrimSpace(input.Note)
+		dispute.RefundAmount = input.Amount
+		dispute.RefundDestination = input.Destination
+		return nil
+	}
+
+	return s.transition(ctx, input.DisputeID, input.AdminID, "disputes.approve", model.DisputeApproved, input.Note, apply,
+		func(ctx context.Context, dispute *model.Dispute) error {
+			if adjustment.Destination != model.RefundToCard {
+				return nil
+			}
+			return s.refundToCard(ctx, adjustment)
+		})
+}
+
+// refundToCard commit edilmiş iade kaydının tutarını sağlayıcıda karta iade eder ve sonucu ayrı bir transaction'da kayda
+// yazar. Sağlayıcı iadeyi yapmazsa kayıt failed olarak işaretlenir; sonuç yazılamazsa kayıt pending kalır.
+func (s *DisputeService) refundToCard(ctx context.Context, adjustment *model.RideAdjustment) error {
+	status := model.ProviderRefundSucceeded
+	refund, refundErr := s.payments.RefundRide(ctx, adjustment.RideID, adjustment.Amount)
+	refundID := ""
+	if refundErr != nil {
+		status = model.ProviderRefundFailed
+	} else {
+		refundID = refund.ID
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		return s.disputeRepo.CompleteAdjustmentRefund(ctx, adjustment.ID, status, refundID)
+	})
+	if err != nil {
+		logger.Error("Karta iade sonucu kaydedilemedi (adjustment_id=%d, refund_id=%s, status=%s): %v", adjustment.ID, refundID, status, err)
+	} else {
+		adjustment.ProviderRefundStatus = status
+		adjustment.ProviderRefundID = refundID
+	}
+
+	if refundErr != nil {
+		logger.Error("Karta iade yapılamadı, iade kaydı mutabakat bekliyor (adjustment_id=%d, amount=%d): %v", adjustment.ID, adjustment.Amount, refundErr)
+		return errorx.Wrap(errorx.ErrInternal, refundErr, "İtiraz onaylandı ancak karta iade yapılamadı, iade mutabakat için bekletiliyor")
+	}
+	return nil
+}
+
+// transition itirazı satır kilidiyle alır, geçişin mümkün olduğunu kontrol eder, apply ile değiştirir ve geçişi kaydeder.
+// apply hata dönerse hiçbir değişiklik yazılmaz. Geçiş kaydedildikten sonra denetim kaydı yazılır, varsa committed
+// çalıştırılır ve kullanıcıya bildirim gönderilir. committed hata dönerse geçiş geri alınmaz, itiraz hatayla birlikte
+// döner ve bildirim gönderilmez.
+func (s *DisputeService) transition(ctx context.Context, id, actorID int64, action string, next model.DisputeStatus, note string,
+	apply, committed func(ctx context.Context, dispute *model.Dispute) error) (*model.Dispute, error) {
+	var dispute *model.Dispute
+	var before model.Dispute
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		dispute, err = s.disputeRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "İtiraz bulunamadı")
+		}
+		before = *dispute
+		if !dispute.Status.CanTransitionTo(next) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("İtiraz %s durumundan %s durumuna geçirilemez", dispute.Status, next))
+		}
+		if apply != nil {
+			if err = apply(ctx, dispute); err != nil {
+				return err
+			}
+		}
+
+		previous := dispute.Status
+		dispute.Status = next
+		if next.IsClosed() {
+			now := time.Now()
+			dispute.ResolvedAt = &now
+		}
+		if err = s.disputeRepo.Update(ctx, dispute); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İtiraz güncellenemedi")
+		}
+		return s.recordEvent(ctx, dispute, previous, actorID, note)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	s.audit.RecordChange(ctx, action, "disputes", dispute.ID, before, dispute)
+	if committed != nil {
+		if err = committed(ctx, dispute); err != nil {
+			return dispute, err
+		}
+	}
+	s.notify(ctx, dispute)
+	return dispute, nil
+}
+
+func (s *DisputeService) recordEvent(ctx context.Context, dispute *model.Dispute, from model.DisputeStatus, actorID int64, note string) error {
+	event := &model.DisputeEvent{
+		DisputeID:  dispute.ID,
+		FromStatus: from,
+		ToStatus:   dispute.Status,
+		ActorID:    actorID,
+		Note:       strings.TrimSpace(note),
+	}
+	if err := s.disputeRepo.CreateEvent(ctx, event); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "İtiraz geçmişi kaydedilemedi")
+	}
+	dispute.Events = append(dispute.Events, *event)
+	return nil
+}
+
+// refundable sürüş ücretinin henüz iade edilmemiş kısmını döner
+func (s *DisputeService) refundable(ctx context.Context, ride *model.Ride) (int64, error) {
+	adjustments, err := s.disputeRepo.ListAdjustmentsByRideID(ctx, ride.ID)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	refundable := ride.Cost
+	for _, adjustment := range adjustments {
+		refundable -= adjustment.Amount
+	}
+	return refundable, nil
+}
+
+func adjustmentReason(dispute *model.Dispute, note string) string {
+	reason := fmt.Sprintf("İtiraz #%d onaylandı (%s)", dispute.ID, dispute.Reason)
+	if note = strings.TrimSpace(note); note != "" {
+		reason += ": " + note
+	}
+	return reason
+}
+
+// notify itirazın güncel durumunu kullanıcıya e-postayla bildirir. Bildirim gönderilemezse işlem geri alınmaz, hata loglanır.
+func (s *DisputeService) notify(ctx context.Context, dispute *model.Dispute) {
+	if s.mailer == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, dispute.UserID)
+	if err != nil {
+		logger.Error("İtiraz bildirimi için kullanıcı alınamadı (dispute_id=%d): %v", dispute.ID, err)
+		return
+	}
+
+	subject, body := disputeNotification(dispute)
+	if err = s.mailer.SendMessage(email.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		logger.Error("İtiraz bildirimi gönderilemedi (dispute_id=%d, status=%s): %v", dispute.ID, dispute.Status, err)
+	}
+}
+
+// notifySupport yeni açılan itirazı destek ekibine bildirir
+func (s *DisputeService) notifySupport(dispute *model.Dispute) {
+	if s.mailer == nil || s.supportEmail == "" {
+		return
+	}
+	msg := email.Message{
+		To:      s.supportEmail,
+		Subject: fmt.Sprintf("Yeni itiraz #%d (sürüş #%d)", dispute.ID, dispute.RideID),
+		Body: fmt.Sprintf("Kullanıcı #%d, sürüş #%d için itiraz açtı.\nNeden: %s\nAçıklama: %s\nFotoğraf sayısı: %d\n",
+			dispute.UserID, dispute.RideID, dispute.Reason, dispute.Description, len(dispute.Photos)),
+	}
+	if err := s.mailer.SendMessage(msg); err != nil {
+		logger.Error("Yeni itiraz destek ekibine bildirilemedi (dispute_id=%d): %v", dispute.ID, err)
+	}
+}
+
+// disputeNotification itirazın durumuna göre kullanıcıya gönderilecek e-postanın konusunu ve metnini döner
+func disputeNotification(dispute *model.Dispute) (string, string) {
+	var subject, body string
+	switch dispute.Status {
+	case model.DisputeOpen:
+		subject = "İtirazınız alındı"
+		body = fmt.Sprintf("Sürüş #%d için itirazınız alındı. Destek ekibimiz en kısa sürede inceleyecek.", dispute.RideID)
+	case model.DisputeInReview:
+		subject = "İtirazınız inceleniyor"
+		body = fmt.Sprintf("Sürüş #%d için itirazınız destek ekibimiz tarafından inceleniyor.", dispute.RideID)
+	case model.DisputeApproved:
+		subject = "İtirazınız onaylandı"
+		destination := "cüzdanınıza yüklendi"
+		if dispute.RefundDestination == model.RefundToCard {
+			destination = "kartınıza iade edildi. Tutarın hesabınıza yansıması bankanıza göre birkaç gün sürebilir"
+		}
+		body = fmt.Sprintf("Sürüş #%d için itirazınız onaylandı. %s %s.", dispute.RideID, money.Format(dispute.RefundAmount, money.DefaultCurrency), destination)
+	case model.DisputeRejected:
+		subject = "İtirazınız sonuçlandı"
+		body = fmt.Sprintf("Sürüş #%d için itirazınız incelendi ve reddedildi.", dispute.RideID)
+	case model.DisputeWithdrawn:
+		subject = "İtirazınız geri çekildi"
+		body = fmt.Sprintf("Sürüş #%d için itirazınız isteğiniz üzerine kapatıldı.", dispute.RideID)
+	}
+	if dispute.ResolutionNote != "" && dispute.Status.IsClosed() {
+		body += "\n\nAçıklama: " + dispute.ResolutionNote
+	}
+	return fmt.Sprintf("%s (#%d)", subject, dispute.ID), body + "\n"
+}
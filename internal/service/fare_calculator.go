@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+const minutesPerDay = 24 * 60
+
+// FareInput ücret hesaplamasında kullanılan sürüş bilgileri
+type FareInput struct {
+	StartTime      time.Time
+	EndTime        time.Time
+	ReservationFee int64             // sürüşe dönüşen rezervasyonun ücreti, günlük limite dahil edilmez
+	PassMinutes    int               // abonelikten kullanılabilecek dahil dakika, ücretsiz dakikalardan sonra düşülür
+	WaiveUnlockFee bool              // abonelik kilit açma ücretini kapsıyor
+	Pauses         []model.RidePause // park modu aralıkları; bitmemiş aralık EndTime'a kadar sayılır
+}
+
+// HasPass sürüşe abonelik avantajı uygulanıp uygulanmadığını döner
+func (in FareInput) HasPass() bool {
+	return in.PassMinutes > 0 || in.WaiveUnlockFee
+}
+
+// FareCalculator sürüş ücretini tarifeye göre hesaplar. Bitmiş sürüşler, devam eden sürüşler ve
+// fiyat teklifleri aynı hesaplamayı kullanır.
+type FareCalculator struct {
+	location *time.Location // gece/hafta sonu kontrolü bu saat dilimine göre yapılır
+}
+
+func NewFareCalculator(location *time.Location) *FareCalculator {
+	if location == nil {
+		location = time.UTC
+	}
+	return &FareCalculator{location: location}
+}
+
+// Calculate tarifeyi sürüşe uygular ve kalem kalem fiyat dökümünü döner.
+// Başlamış her dakika ücretlendirilir; ücretsiz dakikalar ve ardından abonelik dakikaları sürüşün başından düşülür,
+// günlük üst limit sürüş başlangıcından itibaren her 24 saatlik dilime ayrı uygulanır.
+// Abonelik kullanılan sürüşte minimum ücret uygulanmaz; kullanılan dahil dakikalar pass satırında gösterilir.
+// Başladığı an park modu aralığına düşen dakikalar gece/hafta sonu farkı olmadan park modu ücretiyle ücretlendirilir
+// ve her aralık dökümde ayrı bir pause satırı olarak gösterilir.
+func (c *FareCalculator) Calculate(tariff model.Tariff, in FareInput) *model.RidePriceBreakdown {
+	breakdown := &model.RidePriceBreakdown{
+		TariffID: tariff.ID,
+		Currency: tariff.Currency,
+		Lines:    []model.PriceLine{},
+	}
+
+	duration := in.EndTime.Sub(in.StartTime)
+	if duration < 0 {
+		duration = 0
+	}
+	totalMinutes := int(math.Ceil(duration.Minutes()))
+	freeMinutes := min(tariff.FreeMinutes, totalMinutes)
+	passMinutes := min(max(in.PassMinutes, 0), totalMinutes-freeMinutes)
+
+	breakdown.TotalMinutes = totalMinutes
+	breakdown.BillableMinutes = totalMinutes - freeMinutes - passMinutes
+
+	unlockFee := tariff.UnlockFee
+	if in.WaiveUnlockFee {
+		unlockFee = 0
+	}
+
+	dayCharges := make([]int64, totalMinutes/minutesPerDay+1)
+	dayCharges[0] += unlockFee
+
+	pausedMinutes := make([]int, len(in.Pauses))
+	var timeCharge, nightExtra, weekendExtra int64
+	var movingMinutes int
+	for i := freeMinutes + passMinutes; i < totalMinutes; i++ {
+		at := in.StartTime.Add(time.Duration(i) * time.Minute).In(c.location)
+		if p := pauseAt(in.Pauses, at, in.EndTime); p >= 0 {
+			pausedMinutes[p]++
+			dayCharges[i/minutesPerDay] += tariff.PausePerMinuteRate
+			continue
+		}
+
+		movingMinutes++
+		amount := tariff.PerMinuteRate
+		timeCharge += amount
+
+		if at.Weekday() == time.Saturday || at.Weekday() == time.Sunday {
+			withWeekend := money.ApplyPercent(amount, tariff.WeekendMultiplierPct)
+			weekendExtra += withWeekend - amount
+			amount = withWeekend
+		}
+		if tariff.IsNightHour(at.Hour()) {
+			withNight := money.ApplyPercent(amount, tariff.NightMultiplierPct)
+			nightExtra += withNight - amount
+			amount = withNight
+		}
+
+		dayCharges[i/minutesPerDay] += amount
+	}
+
+	if unlockFee > 0 {
+		breakdown.AddLine(model.PriceLine{Code: model.PriceLineUnlockFee, Description: "Kilit açma ücreti", Amount: unlockFee})
+	}
+	if in.HasPass() {
+		description := "Abonelik dahil dakikaları"
+		if in.WaiveUnlockFee {
+			description += ", kilit açma ücreti muaf"
+		}
+		breakdown.AddLine(model.PriceLine{Code: model.PriceLinePass, Description: description, Quantity: passMinutes})
+	}
+	if in.ReservationFee > 0 {
+		breakdown.AddLine(model.PriceLine{Code: model.PriceLineReservationFee, Description: "Rezervasyon ücreti", Amount: in.ReservationFee})
+	}
+	if movingMinutes > 0 {
+		breakdown.AddLine(model.PriceLine{
+			Code:        model.PriceLineTime,
+			Description: "Süre ücreti",
+			Quantity:    movingMinutes,
+			UnitAmount:  tariff.PerMinuteRate,
+			Amount:      timeCharge,
+		})
+	}
+	for i, pause := range in.Pauses {
+		if pausedMinutes[i] == 0 {
+			continue
+		}
+		breakdown.AddLine(model.PriceLine{
+			Code: model.PriceLinePause,
+			Description: fmt.Sprintf("Park modu (%s - %s)",
+				pause.StartedAt.In(c.location).Format("15:04"), pause.EndOr(in.EndTime).In(c.location).Format("15:04")),
+			Quantity:   pausedMinutes[i],
+			UnitAmount: tariff.PausePerMinuteRate,
+			Amount:     int64(pausedMinutes[i]) * tariff.PausePerMinuteRate,
+		})
+	}
+	if weekendExtra != 0 {
+		breakdown.AddLine(model.PriceLine{Code: model.PriceLineWeekendSurcharge, Description: "Hafta sonu tarifesi farkı", Amount: weekendExtra})
+	}
+	if nightExtra != 0 {
+		breakdown.AddLine(model.PriceLine{Code: model.PriceLineNightSurcharge, Description: "Gece tarifesi farkı", Amount: nightExtra})
+	}
+
+	if tariff.DailyCap > 0 {
+		var capDiscount int64
+		for _, charge := range dayCharges {
+			if charge > tariff.DailyCap {
+				capDiscount += charge - tariff.DailyCap
+			}
+		}
+		if capDiscount > 0 {
+			breakdown.AddLine(model.PriceLine{Code: model.PriceLineDailyCap, Description: "Günlük üst limit indirimi", Amount: -capDiscount})
+		}
+	}
+
+	if tariff.MinimumCharge > 0 && !in.HasPass() && breakdown.Total < tariff.MinimumCharge {
+		breakdown.AddLine(model.PriceLine{
+			Code:        model.PriceLineMinimumCharge,
+			Description: "Minimum ücret tamamlaması",
+			Amount:      tariff.MinimumCharge - breakdown.Total,
+		})
+	}
+
+	return breakdown
+}
+
+// pauseAt verilen anı kapsayan park modu aralığının sırasını, yoksa -1 döner
+func pauseAt(pauses []model.RidePause, at, end time.Time) int {
+	for i, pause := range pauses {
+		if !at.Before(pause.StartedAt) && at.Before(pause.EndOr(end)) {
+			return i
+		}
+	}
+	return -1
+}
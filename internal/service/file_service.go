@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+)
+
+// FileService BlobStore'daki dosyalar için süreli imzalı bağlantılar üretir ve bu bağlantılarla istenen dosyaları açar.
+// Dosyaya erişim yetkisi bağlantı üretilirken kontrol edilir; bağlantıyı taşıyan istek ayrıca doğrulanmaz.
+type FileService struct {
+	store  storage.BlobStore
+	signer *storage.URLSigner
+}
+
+func NewFileService(store storage.BlobStore, signer *storage.URLSigner) *FileService {
+	return &FileService{store: store, signer: signer}
+}
+
+// SignedURL anahtar için imzalı bağlantıyı ve son geçerlilik zamanını döner
+func (s *FileService) SignedURL(key string) (string, time.Time) {
+	return s.signer.Sign(key, time.Now())
+}
+
+// Open imzalı bağlantıyı doğrular ve dosyayı okumak için açar. Okuyucu çağıran tarafından kapatılmalıdır.
+func (s *FileService) Open(ctx context.Context, key, expires, signature string) (io.ReadCloser, *storage.Object, error) {
+	if err := s.signer.Verify(key, expires, signature, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrExpiredURL) {
+			return nil, nil, errorx.WrapMsg(errorx.ErrForbidden, "Dosya bağlantısının süresi dolmuş")
+		}
+		return nil, nil, errorx.WrapMsg(errorx.ErrForbidden, "Dosya bağlantısı geçersiz")
+	}
+
+	body, object, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		return nil, nil, errorx.WrapMsg(errorx.ErrNotFound, "Dosya bulunamadı")
+	}
+	if err != nil {
+		return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return body, object, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/pdf"
+)
+
+// CompanyInfo fiş ve faturalarda görünen şirket bilgileri
+type CompanyInfo struct {
+	Name      string
+	Address   string
+	TaxOffice string
+	TaxNumber string
+	Email     string
+}
+
+// DocumentField belgenin üst kısmındaki etiket/değer satırı, örn: "Başlangıç: 02.09.2024 12:00"
+type DocumentField struct {
+	Label string
+	Value string
+}
+
+// DocumentLine belgedeki kalem
+type DocumentLine struct {
+	Description string
+	Quantity    int
+	UnitAmount  int64
+	Amount      int64
+}
+
+// InvoiceDocument fiş veya faturanın biçimden bağımsız içeriği; aynı içerik HTML ve PDF olarak üretilir
+type InvoiceDocument struct {
+	Title         string
+	Number        string
+	IssuedAt      time.Time // belgede gösterilecek yerel saat
+	Company       CompanyInfo
+	CustomerName  string
+	CustomerEmail string
+	Fields        []DocumentField
+	Lines         []DocumentLine
+	NetAmount     int64
+	VATRatePct    int
+	VATAmount     int64
+	Total         int64
+	Currency      string
+}
+
+const documentTimeLayout = "02.01.2006 15:04"
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": money.Format,
+	"quantity": func(q int) string {
+		if q == 0 {
+			return ""
+		}
+		return strconv.Itoa(q)
+	},
+	"time": func(t time.Time) string { return t.Format(documentTimeLayout) },
+}).Parse(`<!DOCTYPE html>
+<html lang="tr">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; max-width: 720px; margin: 24px auto; }
+header { display: flex; justify-content: space-between; border-bottom: 1px solid #ccc; padding-bottom: 12px; }
+h1 { font-size: 20px; margin: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 16px; }
+th, td { padding: 6px 4px; border-bottom: 1px solid #eee; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { border: none; }
+tfoot tr.total td { font-weight: bold; border-top: 1px solid #ccc; }
+.muted { color: #666; font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<div>
+<h1>{{.Company.Name}}</h1>
+{{with .Company.Address}}<div class="muted">{{.}}</div>{{end}}
+{{with .Company.TaxOffice}}<div class="muted">Vergi Dairesi: {{.}}</div>{{end}}
+{{with .Company.TaxNumber}}<div class="muted">Vergi No: {{.}}</div>{{end}}
+{{with .Company.Email}}<div class="muted">{{.}}</div>{{end}}
+</div>
+<div style="text-align: right">
+<h1>{{.Title}}</h1>
+<div>No: {{.Number}}</div>
+<div>Tarih: {{time .IssuedAt}}</div>
+</div>
+</header>
+<p><strong>Müşteri:</strong> {{.CustomerName}}<br><span class="muted">{{.CustomerEmail}}</span></p>
+{{if .Fields}}<table>
+{{range .Fields}}<tr><td>{{.Label}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+<table>
+<thead><tr><th>Açıklama</th><th class="num">Miktar</th><th class="num">Birim Fiyat</th><th class="num">Tutar</th></tr></thead>
+<tbody>
+{{range .Lines}}<tr><td>{{.Description}}</td><td class="num">{{quantity .Quantity}}</td><td class="num">{{if .UnitAmount}}{{money .UnitAmount $.Currency}}{{end}}</td><td class="num">{{money .Amount $.Currency}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+<tr><td colspan="3" class="num">KDV Hariç Tutar</td><td class="num">{{money .NetAmount .Currency}}</td></tr>
+<tr><td colspan="3" class="num">KDV (%{{.VATRatePct}})</td><td class="num">{{money .VATAmount .Currency}}</td></tr>
+<tr class="total"><td colspan="3" class="num">Toplam</td><td class="num">{{money .Total .Currency}}</td></tr>
+</tfoot>
+</table>
+<p class="muted">Fiyatlara KDV dahildir.</p>
+</body>
+</html>
+`))
+
+// HTML belgeyi tek başına görüntülenebilen bir HTML sayfası olarak üretir
+func (d *InvoiceDocument) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// PDF sayfa düzeni (pt)
+const (
+	pdfMargin     = 50.0
+	pdfRight      = pdf.PageWidth - pdfMargin
+	pdfLineHeight = 16.0
+	pdfPageBottom = pdf.PageHeight - 70
+)
+
+// PDF belgeyi A4 PDF olarak üretir. Kalemler sayfaya sığmazsa yeni sayfaya geçilir.
+func (d *InvoiceDocument) PDF() []byte {
+	doc := pdf.New(d.Title + " " + d.Number)
+
+	y := 60.0
+	doc.Text(pdfMargin, y, pdf.HelveticaBold, 16, d.Company.Name)
+	doc.TextRight(pdfRight, y, pdf.HelveticaBold, 16, d.Title)
+	y += 18
+	doc.TextRight(pdfRight, y, pdf.Helvetica, 10, "No: "+d.Number)
+	doc.TextRight(pdfRight, y+14, pdf.Helvetica, 10, "Tarih: "+d.IssuedAt.Format(documentTimeLayout))
+	for _, line := range []string{d.Company.Address, taxLine(d.Company), d.Company.Email} {
+		if line == "" {
+			continue
+		}
+		doc.Text(pdfMargin, y, pdf.Helvetica, 9, line)
+		y += 12
+	}
+	y = max(y, 110) + 10
+	doc.Line(pdfMargin, y, pdfRight, y)
+
+	y += 24
+	doc.Text(pdfMargin, y, pdf.HelveticaBold, 10, "Müşteri:")
+	doc.Text(pdfMargin+60, y, pdf.Helvetica, 10, d.CustomerName)
+	y += 14
+	doc.Text(pdfMargin+60, y, pdf.Helvetica, 9, d.CustomerEmail)
+
+	y += 10
+	for _, field := range d.Fields {
+		y += pdfLineHeight
+		doc.Text(pdfMargin, y, pdf.Helvetica, 10, field.Label)
+		doc.Text(pdfMargin+120, y, pdf.Helvetica, 10, field.Value)
+	}
+
+	header := func() {
+		y += 28
+		doc.Text(pdfMargin, y, pdf.HelveticaBold, 10, "Açıklama")
+		doc.TextRight(pdfRight-200, y, pdf.HelveticaBold, 10, "Miktar")
+		doc.TextRight(pdfRight-100, y, pdf.HelveticaBold, 10, "Birim Fiyat")
+		doc.TextRight(pdfRight, y, pdf.HelveticaBold, 10, "Tutar")
+		y += 6
+		doc.Line(pdfMargin, y, pdfRight, y)
+	}
+	header()
+	for _, line := range d.Lines {
+		if y+pdfLineHeight > pdfPageBottom {
+			doc.AddPage()
+			y = 40
+			header()
+		}
+		y += pdfLineHeight
+		doc.Text(pdfMargin, y, pdf.Helvetica, 10, line.Description)
+		if line.Quantity != 0 {
+			doc.TextRight(pdfRight-200, y, pdf.Helvetica, 10, strconv.Itoa(line.Quantity))
+		}
+		if line.UnitAmount != 0 {
+			doc.TextRight(pdfRight-100, y, pdf.Helvetica, 10, money.Format(line.UnitAmount, d.Currency))
+		}
+		doc.TextRight(pdfRight, y, pdf.Helvetica, 10, money.Format(line.Amount, d.Currency))
+	}
+
+	if y+4*pdfLineHeight > pdfPageBottom {
+		doc.AddPage()
+		y = 40
+	}
+	y += 8
+	doc.Line(pdfMargin, y, pdfRight, y)
+	totals := []struct {
+		label  string
+		amount int64
+		font   pdf.Font
+	}{
+		{"KDV Hariç Tutar", d.NetAmount, pdf.Helvetica},
+		{"KDV (%" + strconv.Itoa(d.VATRatePct) + ")", d.VATAmount, pdf.Helvetica},
+		{"Toplam", d.Total, pdf.HelveticaBold},
+	}
+	for _, total := range totals {
+		y += pdfLineHeight
+		doc.TextRight(pdfRight-100, y, total.font, 10, total.label)
+		doc.TextRight(pdfRight, y, total.font, 10, money.Format(total.amount, d.Currency))
+	}
+
+	doc.Text(pdfMargin, y+30, pdf.Helvetica, 8, "Fiyatlara KDV dahildir.")
+	return doc.Bytes()
+}
+
+func taxLine(company CompanyInfo) string {
+	switch {
+	case company.TaxOffice != "" && company.TaxNumber != "":
+		return "Vergi Dairesi: " + company.TaxOffice + " / Vergi No: " + company.TaxNumber
+	case company.TaxNumber != "":
+		return "Vergi No: " + company.TaxNumber
+	default:
+		return ""
+	}
+}
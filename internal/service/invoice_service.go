@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+const (
+	invoiceEmailMaxAttempts = 5
+	invoiceEmailBatchSize   = 50
+)
+
+// Mailer belgeleri e-postayla gönderen arayüz, *email.Email tarafından sağlanır
+type Mailer interface {
+	SendMessage(msg email.Message) error
+}
+
+type InvoiceServiceDeps struct {
+	InvoiceRepo repository.IInvoiceRepository
+	RideRepo    repository.IRideRepository
+	UserRepo    repository.IUserRepository
+	Mailer      Mailer // nil ise belgeler e-postayla gönderilmez
+	Company     CompanyInfo
+	VATRatePct  int               // fiyatlara dahil KDV oranı
+	Location    *time.Location    // belgelerdeki saatler ve aylık fatura dönemleri bu saat dilimine göredir
+	Permissions PermissionChecker // fiş ve faturalar sürüşlere aittir; başkasının belgesini görmek için rides.read gerekir
+}
+
+// InvoiceService tamamlanan sürüşler için fiş, her ay için kullanıcı başına fatura keser ve bunları e-postayla gönderir.
+// Belgeler kesildikleri anda HTML ve PDF olarak saklanır.
+type InvoiceService struct {
+	invoiceRepo repository.IInvoiceRepository
+	rideRepo    repository.IRideRepository
+	userRepo    repository.IUserRepository
+	mailer      Mailer
+	company     CompanyInfo
+	vatRatePct  int
+	location    *time.Location
+	permissions PermissionChecker
+}
+
+func NewInvoiceService(deps InvoiceServiceDeps) *InvoiceService {
+	location := deps.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return &InvoiceService{
+		invoiceRepo: deps.InvoiceRepo,
+		rideRepo:    deps.RideRepo,
+		userRepo:    deps.UserRepo,
+		mailer:      deps.Mailer,
+		company:     deps.Company,
+		vatRatePct:  deps.VATRatePct,
+		location:    location,
+		permissions: deps.Permissions,
+	}
+}
+
+// IssueRideReceipt tamamlanan sürüşün fişini keser ve kullanıcıya gönderir. Fiş daha önce kesildiyse aynı fiş döner.
+func (s *InvoiceService) IssueRideReceipt(ctx context.Context, rideID int64) (*model.Invoice, error) {
+	existing, err := s.invoiceRepo.GetByRideID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if !ride.Status.HasEnded() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş henüz bitirilmedi")
+	}
+	breakdown, err := s.rideRepo.GetPriceBreakdown(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Bu sürüş için fiyat dökümü bulunamadı")
+	}
+	user, err := s.userRepo.GetByID(ctx, ride.UserID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+	}
+
+	invoice := &model.Invoice{
+		Kind:     model.InvoiceReceipt,
+		UserID:   ride.UserID,
+		RideID:   &ride.ID,
+		Total:    breakdown.Total,
+		Currency: breakdown.Currency,
+	}
+	doc := s.newDocument("Sürüş Fişi", user)
+	doc.Fields = []DocumentField{
+		{Label: "Sürüş No", Value: strconv.FormatInt(ride.ID, 10)},
+		{Label: "Başlangıç", Value: ride.StartTime.In(s.location).Format(documentTimeLayout)},
+		{Label: "Bitiş", Value: ride.EndTime.In(s.location).Format(documentTimeLayout)},
+		{Label: "Süre", Value: strconv.Itoa(breakdown.TotalMinutes) + " dk"},
+	}
+	if ride.DistanceMeters > 0 {
+		doc.Fields = append(doc.Fields, DocumentField{Label: "Mesafe", Value: fmt.Sprintf("%.2f km", float64(ride.DistanceMeters)/1000)})
+	}
+	for _, line := range breakdown.Lines {
+		doc.Lines = append(doc.Lines, DocumentLine{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+		})
+	}
+
+	if err = s.create(ctx, invoice, doc); err != nil {
+		// Aynı sürüşün fişi eşzamanlı olarak kesilmiş olabilir
+		if existing, _ = s.invoiceRepo.GetByRideID(ctx, rideID); existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	s.deliver(ctx, invoice, user)
+	return invoice, nil
+}
+
+// GetRideReceipt sürüşün fişini getirir; fiş henüz kesilmediyse keser. rides.read yetkisi olmayan kullanıcılar yalnızca kendi sürüşlerinin fişini görebilir.
+func (s *InvoiceService) GetRideReceipt(ctx context.Context, rideID, userID int64, role model.Role) (*model.Invoice, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, ride.UserID, userID, "Bu sürüşe erişim yetkiniz yok."); err != nil {
+		return nil, err
+	}
+	return s.IssueRideReceipt(ctx, rideID)
+}
+
+// IssueMonthlyInvoices month'un içinde bulunduğu ay için, o ay sürüşü biten her kullanıcıya fatura keser.
+// Daha önce kesilen faturalar tekrar kesilmez. Kesilen fatura sayısını döner.
+func (s *InvoiceService) IssueMonthlyInvoices(ctx context.Context, month time.Time) (int, error) {
+	y, m, _ := month.In(s.location).Date()
+	start := time.Date(y, m, 1, 0, 0, 0, 0, s.location)
+	end := start.AddDate(0, 1, 0)
+
+	userIDs, err := s.rideRepo.ListUserIDsWithCompletedRidesBetween(ctx, start, end)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	issued := 0
+	for _, userID := range userIDs {
+		ok, err := s.issueMonthly(ctx, userID, start, end)
+		if err != nil {
+			return issued, err
+		}
+		if ok {
+			issued++
+		}
+	}
+	return issued, nil
+}
+
+func (s *InvoiceService) issueMonthly(ctx context.Context, userID int64, start, end time.Time) (bool, error) {
+	existing, err := s.invoiceRepo.GetMonthly(ctx, userID, start)
+	if err != nil {
+		return false, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	rides, err := s.rideRepo.ListCompletedByUserIDBetween(ctx, userID, start, end)
+	if err != nil {
+		return false, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if len(rides) == 0 {
+		return false, nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+	}
+
+	periodStart, periodEnd := start.UTC(), end.UTC()
+	invoice := &model.Invoice{
+		Kind:        model.InvoiceMonthly,
+		UserID:      userID,
+		PeriodStart: &periodStart,
+		PeriodEnd:   &periodEnd,
+		Currency:    money.DefaultCurrency,
+	}
+	doc := s.newDocument("Aylık Fatura", user)
+	doc.Fields = []DocumentField{
+		{Label: "Dönem", Value: start.Format("01.2006")},
+		{Label: "Sürüş Sayısı", Value: strconv.Itoa(len(rides))},
+	}
+	for _, ride := range rides {
+		if ride.Currency != "" {
+			invoice.Currency = ride.Currency
+		}
+		invoice.Total += ride.Cost
+		doc.Lines = append(doc.Lines, DocumentLine{
+			Description: fmt.Sprintf("Sürüş #%d - %s", ride.ID, ride.StartTime.In(s.location).Format(documentTimeLayout)),
+			Amount:      ride.Cost,
+		})
+	}
+
+	if err = s.create(ctx, invoice, doc); err != nil {
+		// Aynı ayın faturası eşzamanlı olarak kesilmiş olabilir
+		if existing, _ = s.invoiceRepo.GetMonthly(ctx, userID, periodStart); existing != nil {
+			return false, nil
+		}
+		return false, err
+	}
+	s.deliver(ctx, invoice, user)
+	return true, nil
+}
+
+// ResendPending e-postası gönderilemeyen belgeleri tekrar gönderir, gönderilen belge sayısını döner
+func (s *InvoiceService) ResendPending(ctx context.Context) (int, error) {
+	if s.mailer == nil {
+		return 0, nil
+	}
+	invoices, err := s.invoiceRepo.ListUnsent(ctx, invoiceEmailMaxAttempts, invoiceEmailBatchSize)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	sent := 0
+	for i := range invoices {
+		user, err := s.userRepo.GetByID(ctx, invoices[i].UserID)
+		if err != nil {
+			logger.Error("Belge e-postası için kullanıcı alınamadı (invoice_id=%d): %v", invoices[i].ID, err)
+			continue
+		}
+		if s.deliver(ctx, &invoices[i], user) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// RunWorker ctx iptal edilene kadar belirtilen aralıklarla geçen ayın faturalarını keser ve gönderilemeyen e-postaları tekrar dener
+func (s *InvoiceService) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			y, m, _ := time.Now().In(s.location).Date()
+			previousMonth := time.Date(y, m-1, 1, 0, 0, 0, 0, s.location)
+			issued, err := s.IssueMonthlyInvoices(ctx, previousMonth)
+			if err != nil {
+				logger.Error("Aylık fatura kesme hatası: %v", err)
+			} else if issued > 0 {
+				logger.Info("%d aylık fatura kesildi", issued)
+			}
+
+			if _, err = s.ResendPending(ctx); err != nil {
+				logger.Error("Belge e-postaları tekrar gönderilemedi: %v", err)
+			}
+		}
+	}
+}
+
+// ListByUser kullanıcının fiş ve faturalarını belge içerikleri olmadan getirir
+func (s *InvoiceService) ListByUser(ctx context.Context, userID int64) ([]model.Invoice, error) {
+	invoices, err := s.invoiceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return invoices, nil
+}
+
+// Get belgeyi getirir. rides.read yetkisi olmayan kullanıcılar yalnızca kendi belgelerini görebilir.
+func (s *InvoiceService) Get(ctx context.Context, id, userID int64, role model.Role) (*model.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Belge bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, invoice.UserID, userID, "Bu belgeye erişim yetkiniz yok."); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceService) newDocument(title string, user *model.User) *InvoiceDocument {
+	return &InvoiceDocument{
+		Title:         title,
+		Company:       s.company,
+		CustomerName:  user.FirstName + " " + user.LastName,
+		CustomerEmail: user.Email,
+		VATRatePct:    s.vatRatePct,
+	}
+}
+
+// create belge numarasını alır, vergiyi ayrıştırır, belgeyi HTML ve PDF olarak üretip kaydeder
+func (s *InvoiceService) create(ctx context.Context, invoice *model.Invoice, doc *InvoiceDocument) error {
+	sequence, err := s.invoiceRepo.NextNumber(ctx, invoice.Kind)
+	if err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Belge numarası alınamadı")
+	}
+	issuedAt := time.Now().In(s.location)
+	invoice.Number = fmt.Sprintf("%s-%d-%06d", invoice.Kind.NumberPrefix(), issuedAt.Year(), sequence)
+	invoice.VATRatePct = s.vatRatePct
+	invoice.NetAmount, invoice.VATAmount = money.SplitInclusive(invoice.Total, s.vatRatePct)
+
+	doc.Number = invoice.Number
+	doc.IssuedAt = issuedAt
+	doc.NetAmount, doc.VATAmount, doc.Total = invoice.NetAmount, invoice.VATAmount, invoice.Total
+	doc.Currency = invoice.Currency
+
+	if invoice.HTML, err = doc.HTML(); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Belge oluşturulamadı")
+	}
+	invoice.PDF = doc.PDF()
+
+	if err = s.invoiceRepo.Create(ctx, invoice); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Belge kaydedilemedi")
+	}
+	return nil
+}
+
+// deliver belgeyi PDF eki ve HTML gövdeyle kullanıcıya gönderir ve gönderim durumunu kaydeder.
+// Gönderilemeyen belgeler worker tarafından tekrar denenir.
+func (s *InvoiceService) deliver(ctx context.Context, invoice *model.Invoice, user *model.User) bool {
+	if s.mailer == nil {
+		return false
+	}
+
+	title := "Sürüş fişiniz"
+	if invoice.Kind == model.InvoiceMonthly {
+		title = "Aylık faturanız"
+	}
+	err := s.mailer.SendMessage(email.Message{
+		To:       user.Email,
+		Subject:  fmt.Sprintf("%s - %s", title, invoice.Number),
+		Body:     fmt.Sprintf("Merhaba %s,\n\n%s (%s) ektedir. Toplam tutar: %s\n\n%s", user.FirstName, title, invoice.Number, money.Format(invoice.Total, invoice.Currency), s.company.Name),
+		HTMLBody: invoice.HTML,
+		Attachments: []email.Attachment{
+			{Filename: invoice.Filename("pdf"), ContentType: "application/pdf", Data: invoice.PDF},
+		},
+	})
+
+	invoice.EmailAttempts++
+	if err != nil {
+		logger.Error("Belge e-postası gönderilemedi (invoice_id=%d): %v", invoice.ID, err)
+		invoice.EmailError = err.Error()
+	} else {
+		now := time.Now().UTC()
+		invoice.EmailedAt = &now
+		invoice.EmailError = ""
+	}
+	if updateErr := s.invoiceRepo.UpdateEmailStatus(ctx, invoice); updateErr != nil {
+		logger.Error("Belge e-posta durumu kaydedilemedi (invoice_id=%d): %v", invoice.ID, updateErr)
+	}
+	return err == nil
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+)
+
+type IssueServiceDeps struct {
+	IssueRepo     repository.IIssueRepository
+	MotorbikeRepo repository.IMotorbikeRepository
+	RideRepo      repository.IRideRepository
+	UserRepo      repository.IUserRepository
+	TxManager     repository.ITransactionManager
+	Maintenance   *MaintenanceService
+	Store         storage.BlobStore
+	Mailer        Mailer // nil ise bildirim gönderilmez
+	MaxPhotos     int
+	MaxPhotoBytes int64
+	FlagThreshold int               // motoru bakıma alan, farklı kullanıcılardan gelen açık bildirim sayısı; 0 ise yalnızca kritik bildirimler alır
+	FlagWindow    time.Duration     // bildirimlerin sayıldığı süre
+	Permissions   PermissionChecker // başkasının bildirimini görmek için issues.read gerekir
+	Audit         Auditor
+}
+
+// IssueService kullanıcıların motorlarda gördüğü arıza ve hasar bildirimlerini yönetir. Kritik bir bildirim veya FlagWindow
+// içinde FlagThreshold farklı kullanıcıdan gelen bildirimler motoru MaintenanceService üzerinden bakıma alır; o sırada
+// kullanımda olan motor worker tarafından tekrar denenir. Admin bildirimleri sürüşe bağlar, kopyaları birleştirir ve
+// sonuçlandırır; sonuçlanan bildirim, kopyalarını gönderenler dahil tüm bildirenlere e-postayla haber verilir.
+type IssueService struct {
+	issueRepo     repository.IIssueRepository
+	motorRepo     repository.IMotorbikeRepository
+	rideRepo      repository.IRideRepository
+	userRepo      repository.IUserRepository
+	txManager     repository.ITransactionManager
+	maintenance   *MaintenanceService
+	store         storage.BlobStore
+	mailer        Mailer
+	maxPhotos     int
+	maxPhotoBytes int64
+	flagThreshold int
+	flagWindow    time.Duration
+	permissions   PermissionChecker
+	audit         Auditor
+}
+
+func NewIssueService(deps IssueServiceDeps) *IssueService {
+	return &IssueService{
+		issueRepo:     deps.IssueRepo,
+		motorRepo:     deps.MotorbikeRepo,
+		rideRepo:      deps.RideRepo,
+		userRepo:      deps.UserRepo,
+		txManager:     deps.TxManager,
+		maintenance:   deps.Maintenance,
+		store:         deps.Store,
+		mailer:        deps.Mailer,
+		maxPhotos:     deps.MaxPhotos,
+		maxPhotoBytes: deps.MaxPhotoBytes,
+		flagThreshold: deps.FlagThreshold,
+		flagWindow:    deps.FlagWindow,
+		permissions:   deps.Permissions,
+		audit:         deps.Audit,
+	}
+}
+
+// ReportIssueInput kullanıcının bildirimi. RideID verilirse kullanıcının bu motorla yaptığı bir sürüş olmalıdır.
+type ReportIssueInput struct {
+	MotorbikeID int64
+	UserID      int64
+	RideID      *int64
+	Category    model.IssueCategory
+	Severity    model.IssueSeverity
+	Description string
+	Photos      [][]byte
+}
+
+// Report kullanıcının motor için arıza bildirimini fotoğraflarıyla kaydeder ve motorun bakıma alınması gerekip
+// gerekmediğini kontrol eder. Herhangi bir fotoğraf geçersizse bildirim kaydedilmez. Kullanıcının aynı motor için
+// aynı kategoride açık bir bildirimi varsa yenisi açılmaz.
+func (s *IssueService) Report(ctx context.Context, input ReportIssueInput) (*model.Issue, error) {
+	if !input.Category.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bildirim kategorisi")
+	}
+	if !input.Severity.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz önem derecesi")
+	}
+	if len(input.Photos) > s.maxPhotos {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("En fazla %d fotoğraf eklenebilir", s.maxPhotos))
+	}
+	if _, err := s.motorRepo.GetByID(ctx, input.MotorbikeID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+	if input.RideID != nil {
+		ride, err := s.rideRepo.GetByID(ctx, *input.RideID)
+		if err != nil {
+			return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if ride.UserID != input.UserID {
+			return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+		}
+		if ride.MotorbikeID != input.MotorbikeID {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş bu motorla yapılmadı")
+		}
+	}
+
+	photos, keys, err := s.storePhotos(ctx, input.MotorbikeID, input.Photos)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &model.Issue{
+		MotorbikeID: input.MotorbikeID,
+		UserID:      input.UserID,
+		RideID:      input.RideID,
+		Category:    input.Category,
+		Severity:    input.Severity,
+		Description: strings.TrimSpace(input.Description),
+		Status:      model.IssueOpen,
+	}
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := s.issueRepo.GetOpenByUser(ctx, input.MotorbikeID, input.UserID, input.Category)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if existing != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bu motor için aynı konuda açık bir bildiriminiz zaten var (#%d)", existing.ID))
+		}
+
+		if err = s.issueRepo.Create(ctx, issue); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Bildirim kaydedilemedi")
+		}
+		for i := range photos {
+			photos[i].IssueID = issue.ID
+		}
+		if err = s.issueRepo.CreatePhotos(ctx, photos); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Bildirim fotoğrafları kaydedilemedi")
+		}
+		issue.Photos = photos
+		return nil
+	})
+	if err != nil {
+		s.remove(ctx, keys...)
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	// Bildirim kaydedildi; motor bakıma alınamazsa worker tekrar dener
+	if err = s.flagMotorbike(ctx, issue.MotorbikeID); err != nil {
+		logger.Error("Bildirim sonrası motor bakıma alınamadı (issue_id=%d, motorbike_id=%d): %v", issue.ID, issue.MotorbikeID, err)
+	}
+	return issue, nil
+}
+
+// storePhotos fotoğrafları doğrulayıp temizler ve BlobStore'a yazar. Hata olursa yazılan dosyalar silinir.
+func (s *IssueService) storePhotos(ctx context.Context, motorbikeID int64, files [][]byte) ([]model.IssuePhoto, []string, error) {
+	images := make([]*storage.Image, len(files))
+	for i, data := range files {
+		img, err := storage.ProcessImage(data, s.maxPhotoBytes, ridePhotoThumbSize)
+		switch {
+		case errors.Is(err, storage.ErrTooLarge):
+			return nil, nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, s.maxPhotoBytes>>20))
+		case errors.Is(err, storage.ErrInvalidImage):
+			return nil, nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf JPEG veya PNG formatında olmalıdır", i+1))
+		case err != nil:
+			return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		images[i] = img
+	}
+
+	photos := make([]model.IssuePhoto, 0, len(images))
+	var keys []string
+	for _, img := range images {
+		name, err := randomName()
+		if err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		photo := model.IssuePhoto{
+			Key:          fmt.Sprintf("issues/%d/%s%s", motorbikeID, name, img.Ext),
+			ThumbnailKey: fmt.Sprintf("issues/%d/%s_thumb.jpg", motorbikeID, name),
+			ContentType:  img.ContentType,
+			Size:         int64(len(img.Data)),
+			Width:        img.Width,
+			Height:       img.Height,
+		}
+		if err = s.store.Put(ctx, photo.Key, bytes.NewReader(img.Data), photo.Size, photo.ContentType); err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.Key)
+		if err = s.store.Put(ctx, photo.ThumbnailKey, bytes.NewReader(img.Thumbnail), int64(len(img.Thumbnail)), "image/jpeg"); err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.ThumbnailKey)
+		photos = append(photos, photo)
+	}
+	return photos, keys, nil
+}
+
+// flagMotorbike motorun iş emrine bağlanmamış açık bildirimlerinde kritik bir bildirim varsa veya FlagWindow içinde
+// FlagThreshold farklı kullanıcı bildirim yaptıysa motoru bakıma alır ve bu bildirimleri açılan iş emrine bağlar.
+// Kullanımdaki veya rezerve edilmiş motor atlanır, worker bir sonraki turda tekrar dener.
+func (s *IssueService) flagMotorbike(ctx context.Context, motorbikeID int64) error {
+	issues, err := s.issueRepo.ListUnflaggedByMotorbikeID(ctx, motorbikeID)
+	if err != nil || len(issues) == 0 {
+		return err
+	}
+	trigger := s.flagTrigger(issues, time.Now())
+	if trigger == nil {
+		return nil
+	}
+
+	motorbike, err := s.motorRepo.GetByID(ctx, motorbikeID)
+	if err != nil {
+		return err
+	}
+	if motorbike.Status != model.BikeAvailable && motorbike.Status != model.BikeInMaintenance {
+		return nil
+	}
+
+	order, err := s.maintenance.Flag(ctx, OpenWorkOrderInput{
+		MotorbikeID: motorbikeID,
+		Type:        trigger.Category.MaintenanceType(),
+		Priority:    trigger.Severity.WorkOrderPriority(),
+		Description: fmt.Sprintf("Kullanıcı bildirimi #%d (%s): %s", trigger.ID, trigger.Category, trigger.Description),
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	if err = s.issueRepo.SetWorkOrder(ctx, ids, order.ID); err != nil {
+		return err
+	}
+	logger.Info("Motor kullanıcı bildirimleri nedeniyle bakıma alındı (motorbike_id=%d, work_order_id=%d, issues=%v)", motorbikeID, order.ID, ids)
+	return nil
+}
+
+// flagTrigger motoru bakıma alacak bildirimi seçer: varsa ilk kritik bildirim, yoksa eşik aşıldıysa pencere içindeki
+// en ağır bildirim. Motorun bakıma alınması gerekmiyorsa nil döner.
+func (s *IssueService) flagTrigger(issues []model.Issue, now time.Time) *model.Issue {
+	for i := range issues {
+		if issues[i].Severity == model.SeverityCritical {
+			return &issues[i]
+		}
+	}
+	if s.flagThreshold <= 0 {
+		return nil
+	}
+
+	var trigger *model.Issue
+	reporters := map[int64]bool{}
+	for i := range issues {
+		if now.Sub(issues[i].CreatedAt) > s.flagWindow {
+			continue
+		}
+		reporters[issues[i].UserID] = true
+		if trigger == nil || issues[i].Severity.WorkOrderPriority().Exceeds(trigger.Severity.WorkOrderPriority()) {
+			trigger = &issues[i]
+		}
+	}
+	if len(reporters) < s.flagThreshold {
+		return nil
+	}
+	return trigger
+}
+
+// FlagPending iş emrine bağlanmamış açık bildirimi olan motorları tekrar kontrol eder
+func (s *IssueService) FlagPending(ctx context.Context) error {
+	ids, err := s.issueRepo.ListUnflaggedMotorbikeIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = s.flagMotorbike(ctx, id); err != nil {
+			logger.Error("Bildirimi olan motor bakıma alınamadı (motorbike_id=%d): %v", id, err)
+		}
+	}
+	return nil
+}
+
+// RunFlagWorker ctx iptal edilene kadar belirtilen aralıklarla bekleyen bildirimleri kontrol eder
+func (s *IssueService) RunFlagWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.FlagPending(ctx); err != nil {
+				logger.Error("Bildirim kontrolü hatası: %v", err)
+			}
+		}
+	}
+}
+
+// Get bildirimi fotoğraflarıyla getirir. issues.read yetkisi olmayan kullanıcı yalnızca kendi bildirimini görebilir.
+func (s *IssueService) Get(ctx context.Context, id, userID int64, role model.Role) (*model.Issue, error) {
+	issue, err := s.issueRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Bildirim bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermIssuesRead, issue.UserID, userID, "Bu bildirime erişim yetkiniz yok"); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+func (s *IssueService) ListByUser(ctx context.Context, userID int64) ([]model.Issue, error) {
+	issues, err := s.issueRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return issues, nil
+}
+
+// List triyaj kuyruğunu filtreleyerek en ağır bildirim başta olacak şekilde sayfalı getirir
+func (s *IssueService) List(ctx context.Context, filter model.IssueFilter, pagination *query.Pagination) ([]model.Issue, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bildirim durumu")
+	}
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bildirim kategorisi")
+	}
+	if filter.Severity != "" && !filter.Severity.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz önem derecesi")
+	}
+	issues, err := s.issueRepo.List(ctx, filter, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return issues, nil
+}
+
+// LinkRide bildirimi motorla yapılmış bir sürüşe bağlar
+func (s *IssueService) LinkRide(ctx context.Context, id, rideID int64) (*model.Issue, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	return s.update(ctx, id, "issues.link_ride", func(ctx context.Context, issue *model.Issue) error {
+		if ride.MotorbikeID != issue.MotorbikeID {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş bildirilen motorla yapılmadı")
+		}
+		issue.RideID = &ride.ID
+		return nil
+	})
+}
+
+// MarkDuplicate açık bildirimi aynı motorun başka bir bildiriminin kopyası olarak kapatır. Bildirimin kendi kopyaları
+// da asıl bildirime bağlanır; böylece kopyalar her zaman doğrudan asıl bildirime işaret eder.
+func (s *IssueService) MarkDuplicate(ctx context.Context, id, adminID, duplicateOfID int64, note string) (*model.Issue, error) {
+	if id == duplicateOfID {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Bildirim kendisinin kopyası olamaz")
+	}
+	return s.update(ctx, id, "issues.mark_duplicate", func(ctx context.Context, issue *model.Issue) error {
+		if issue.Status.IsClosed() {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sonuçlanmış bildirim değiştirilemez")
+		}
+		original, err := s.issueRepo.GetByIDForUpdate(ctx, duplicateOfID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Asıl bildirim bulunamadı")
+		}
+		if original.MotorbikeID != issue.MotorbikeID {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Asıl bildirim aynı motora ait olmalıdır")
+		}
+		if original.Status == model.IssueDuplicate {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bildirim #%d de bir kopya; asıl bildirimi (#%d) seçin", original.ID, *original.DuplicateOfID))
+		}
+		if err = s.issueRepo.ReassignDuplicates(ctx, issue.ID, original.ID); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		now := time.Now()
+		issue.Status = model.IssueDuplicate
+		issue.DuplicateOfID = &original.ID
+		issue.ResolvedBy = &adminID
+		issue.ResolvedAt = &now
+		issue.ResolutionNote = strings.TrimSpace(note)
+		return nil
+	})
+}
+
+// Resolve açık bildirimi çözüm açıklamasıyla kapatır ve bildireni, kopyalarını gönderenlerle birlikte e-postayla bilgilendirir
+func (s *IssueService) Resolve(ctx context.Context, id, adminID int64, note string) (*model.Issue, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Çözüm açıklaması zorunludur")
+	}
+	issue, err := s.update(ctx, id, "issues.resolve", func(ctx context.Context, issue *model.Issue) error {
+		if issue.Status.IsClosed() {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bildirim zaten sonuçlanmış")
+		}
+		now := time.Now()
+		issue.Status = model.IssueResolved
+		issue.ResolvedBy = &adminID
+		issue.ResolvedAt = &now
+		issue.ResolutionNote = note
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyResolved(ctx, issue)
+	return issue, nil
+}
+
+// update bildirimi satır kilidiyle alır ve apply ile değiştirir. apply hata dönerse hiçbir değişiklik yazılmaz;
+// değişiklik commit edildikten sonra action ile denetim kaydına yazılır.
+func (s *IssueService) update(ctx context.Context, id int64, action string, apply func(ctx context.Context, issue *model.Issue) error) (*model.Issue, error) {
+	var issue *model.Issue
+	var before model.Issue
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		issue, err = s.issueRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Bildirim bulunamadı")
+		}
+		before = *issue
+		if err = apply(ctx, issue); err != nil {
+			return err
+		}
+		if err = s.issueRepo.Update(ctx, issue); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Bildirim güncellenemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, action, "issues", issue.ID, before, issue)
+	return issue, nil
+}
+
+// notifyResolved sonuçlanan bildirimi bildirene ve kopyalarını gönderen kullanıcılara e-postayla haber verir.
+// Bildirim gönderilemezse işlem geri alınmaz, hata loglanır.
+func (s *IssueService) notifyResolved(ctx context.Context, issue *model.Issue) {
+	if s.mailer == nil {
+		return
+	}
+	recipients := []int64{issue.UserID}
+	duplicates, err := s.issueRepo.ListDuplicates(ctx, issue.ID)
+	if err != nil {
+		logger.Error("Bildirimin kopyaları alınamadı (issue_id=%d): %v", issue.ID, err)
+	}
+	for _, duplicate := range duplicates {
+		recipients = append(recipients, duplicate.UserID)
+	}
+
+	sent := map[int64]bool{}
+	for _, userID := range recipients {
+		if sent[userID] {
+			continue
+		}
+		sent[userID] = true
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			logger.Error("Bildirim sonucu için kullanıcı alınamadı (issue_id=%d, user_id=%d): %v", issue.ID, userID, err)
+			continue
+		}
+		msg := email.Message{
+			To:      user.Email,
+			Subject: fmt.Sprintf("Bildiriminiz sonuçlandı (#%d)", issue.ID),
+			Body: fmt.Sprintf("Motor #%d için yaptığınız arıza bildirimi incelendi ve sonuçlandı. Katkınız için teşekkür ederiz.\n\nAçıklama: %s\n",
+				issue.MotorbikeID, issue.ResolutionNote),
+		}
+		if err = s.mailer.SendMessage(msg); err != nil {
+			logger.Error("Bildirim sonucu gönderilemedi (issue_id=%d, user_id=%d): %v", issue.ID, userID, err)
+		}
+	}
+}
+
+// remove kaydı oluşturulamayan fotoğrafların dosyalarını siler, silinemeyenler loglanır
+func (s *IssueService) remove(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error("Kaydedilemeyen fotoğraf dosyası silinemedi (%s): %v", key, err)
+		}
+	}
+}
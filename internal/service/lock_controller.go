@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+)
+
+// LockController motor üzerindeki kilit donanımıyla konuşan arayüz. Bağlanma, sürüş bitirme ve
+// bağlantı kesme akışları motorun fiziksel durumunu bu arayüz üzerinden değiştirir.
+type LockController interface {
+	// Connect motorun cihazına ulaşılabildiğini doğrular
+	Connect(ctx context.Context, motorbikeID int64) error
+	Unlock(ctx context.Context, motorbikeID int64) error
+	Lock(ctx context.Context, motorbikeID int64) error
+	// Disconnect kullanıcının motorla bağlantısı kesilirken motoru kilitli bırakır
+	Disconnect(ctx context.Context, motorbikeID int64) error
+}
+
+// NoopLockController donanım olmadan çalışan ortamlar içindir; motorun durumunu yalnızca veritabanındaki
+// alanlar belirler. Motor uzaktan kilitlenemediği için Lock, kullanıcının motoru kendisinin kilitlemesini ister.
+type NoopLockController struct{}
+
+func (NoopLockController) Connect(ctx context.Context, motorbikeID int64) error { return nil }
+func (NoopLockController) Unlock(ctx context.Context, motorbikeID int64) error  { return nil }
+
+func (NoopLockController) Lock(ctx context.Context, motorbikeID int64) error {
+	return errorx.WrapMsg(errorx.ErrInvalidRequest, "Motorbike kilitlenmedi! Lütfen önce kilitleyin!")
+}
+
+func (NoopLockController) Disconnect(ctx context.Context, motorbikeID int64) error { return nil }
+
+// DeviceLockController kilit işlemlerini cihaz komut kanalı üzerinden yapar ve cihazın onayını bekler.
+// Cihaz son onlineWindow içinde telemetri göndermediyse motora ulaşılamıyor kabul edilir.
+type DeviceLockController struct {
+	commands     *DeviceCommandService
+	motorRepo    repository.IMotorbikeRepository
+	timeout      time.Duration
+	onlineWindow time.Duration
+}
+
+func NewDeviceLockController(commands *DeviceCommandService, motorRepo repository.IMotorbikeRepository, timeout, onlineWindow time.Duration) *DeviceLockController {
+	return &DeviceLockController{
+		commands:     commands,
+		motorRepo:    motorRepo,
+		timeout:      timeout,
+		onlineWindow: onlineWindow,
+	}
+}
+
+func (c *DeviceLockController) Connect(ctx context.Context, motorbikeID int64) error {
+	motorbike, err := c.motorRepo.GetByID(ctx, motorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+	if motorbike.LastSeenAt == nil || time.Since(*motorbike.LastSeenAt) > c.onlineWindow {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor ile bağlantı kurulamadı, cihaz çevrimdışı")
+	}
+	return nil
+}
+
+func (c *DeviceLockController) Unlock(ctx context.Context, motorbikeID int64) error {
+	return c.send(ctx, motorbikeID, model.CommandUnlock)
+}
+
+func (c *DeviceLockController) Lock(ctx context.Context, motorbikeID int64) error {
+	return c.send(ctx, motorbikeID, model.CommandLock)
+}
+
+func (c *DeviceLockController) Disconnect(ctx context.Context, motorbikeID int64) error {
+	return c.send(ctx, motorbikeID, model.CommandLock)
+}
+
+func (c *DeviceLockController) send(ctx context.Context, motorbikeID int64, commandType model.DeviceCommandType) error {
+	_, err := c.commands.EnqueueAndWait(ctx, EnqueueCommand{MotorbikeID: motorbikeID, Type: commandType}, c.timeout)
+	return err
+}
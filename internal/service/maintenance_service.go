@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+)
+
+type MaintenanceServiceDeps struct {
+	MaintenanceRepo repository.IMaintenanceRepository
+	MotorbikeRepo   repository.IMotorbikeRepository
+	RideRepo        repository.IRideRepository
+	UserRepo        repository.IUserRepository
+	TxManager       repository.ITransactionManager
+	Permissions     PermissionChecker // iş emri atanacak teknisyenin maintenance.write yetkisi kontrol edilir
+	Audit           Auditor
+}
+
+// MaintenanceService motorların bakım iş emirlerini ve koruyucu bakım planlarını yönetir. İş emri açıldığında motor
+// bakıma alınır, motorun kapanmamış son iş emri de kapandığında motor tekrar müsait olur. Planlar worker tarafından
+// düzenli aralıklarla kontrol edilir ve zamanı gelen bakımlar için iş emri açılır.
+type MaintenanceService struct {
+	maintenanceRepo repository.IMaintenanceRepository
+	motorRepo       repository.IMotorbikeRepository
+	rideRepo        repository.IRideRepository
+	userRepo        repository.IUserRepository
+	txManager       repository.ITransactionManager
+	permissions     PermissionChecker
+	audit           Auditor
+}
+
+func NewMaintenanceService(deps MaintenanceServiceDeps) *MaintenanceService {
+	return &MaintenanceService{
+		maintenanceRepo: deps.MaintenanceRepo,
+		motorRepo:       deps.MotorbikeRepo,
+		rideRepo:        deps.RideRepo,
+		userRepo:        deps.UserRepo,
+		txManager:       deps.TxManager,
+		permissions:     deps.Permissions,
+		audit:           deps.Audit,
+	}
+}
+
+// OpenWorkOrderInput yeni iş emri. Öncelik verilmezse normal kabul edilir.
+type OpenWorkOrderInput struct {
+	MotorbikeID  int64
+	Type         model.MaintenanceType
+	Priority     model.WorkOrderPriority
+	Description  string
+	TechnicianID *int64
+	OpenedBy     *int64 // plandan açılan iş emirlerinde boş
+	ScheduleID   *int64
+}
+
+// UpdateWorkOrderInput kapanmamış iş emrinin değiştirilebilen alanları
+type UpdateWorkOrderInput struct {
+	ID           int64
+	Priority     model.WorkOrderPriority
+	Description  string
+	TechnicianID *int64
+}
+
+// CompleteWorkOrderInput iş emrini kapatırken kaydedilen parçalar ve işçilik
+type CompleteWorkOrderInput struct {
+	ID            int64
+	ActorID       int64
+	Parts         []model.WorkOrderPart
+	LabourMinutes int
+	LabourCost    int64
+	Note          string
+}
+
+// Open motor için iş emri açar ve motoru bakıma alır. Kullanımdaki veya rezerve edilmiş motor bakıma alınamaz;
+// motorun aynı türde kapanmamış bir iş emri varsa yenisi açılmaz.
+func (s *MaintenanceService) Open(ctx context.Context, input OpenWorkOrderInput) (*model.WorkOrder, error) {
+	return s.open(ctx, input, false)
+}
+
+// Flag motoru hizmet dışı bırakıp bakıma alır. Motorun aynı türde kapanmamış bir iş emri varsa yenisi açılmaz, o iş emri
+// döner; verilen öncelik daha acilse iş emrinin önceliği yükseltilir.
+func (s *MaintenanceService) Flag(ctx context.Context, input OpenWorkOrderInput) (*model.WorkOrder, error) {
+	return s.open(ctx, input, true)
+}
+
+func (s *MaintenanceService) open(ctx context.Context, input OpenWorkOrderInput, join bool) (*model.WorkOrder, error) {
+	if !input.Type.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bakım türü")
+	}
+	if input.Priority == "" {
+		input.Priority = model.PriorityNormal
+	}
+	if !input.Priority.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz öncelik")
+	}
+	if err := s.checkTechnician(ctx, input.TechnicianID); err != nil {
+		return nil, err
+	}
+
+	order := &model.WorkOrder{
+		MotorbikeID:  input.MotorbikeID,
+		Type:         input.Type,
+		Priority:     input.Priority,
+		Status:       model.WorkOrderOpen,
+		Description:  strings.TrimSpace(input.Description),
+		TechnicianID: input.TechnicianID,
+		ScheduleID:   input.ScheduleID,
+		Currency:     money.DefaultCurrency,
+		OpenedBy:     input.OpenedBy,
+	}
+	var before *model.WorkOrder
+	changed := true
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, input.MotorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		if motorbike.Status != model.BikeAvailable && motorbike.Status != model.BikeInMaintenance {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kullanımdaki veya rezerve edilmiş motor bakıma alınamaz")
+		}
+
+		open, err := s.maintenanceRepo.ListOpenWorkOrdersByMotorbikeID(ctx, motorbike.ID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		var existing *model.WorkOrder
+		for i := range open {
+			if open[i].Type == input.Type {
+				existing = &open[i]
+			}
+		}
+
+		switch {
+		case existing != nil && !join:
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Motorun kapanmamış bir %s iş emri zaten var (#%d)", input.Type, existing.ID))
+		case existing != nil:
+			current := *existing
+			order, before, changed = existing, &current, false
+			if input.Priority.Exceeds(order.Priority) {
+				changed = true
+				order.Priority = input.Priority
+				if err = s.maintenanceRepo.UpdateWorkOrder(ctx, order); err != nil {
+					return errorx.Wrap(errorx.ErrInternal, err, "İş emri güncellenemedi")
+				}
+			}
+		default:
+			if order.RideCount, err = s.rideRepo.CountEndedByMotorbikeID(ctx, motorbike.ID); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+			order.OdometerMeters = motorbike.OdometerMeters
+			if err = s.maintenanceRepo.CreateWorkOrder(ctx, order); err != nil {
+				return errorx.Wrap(errorx.ErrInternal, err, "İş emri kaydedilemedi")
+			}
+		}
+
+		if motorbike.Status == model.BikeInMaintenance {
+			return nil
+		}
+		motorbike.Status = model.BikeInMaintenance
+		if err = s.motorRepo.UpdateColumns(ctx, motorbike, "status"); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor durumu güncellenirken hata oluştu!")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	switch {
+	case before == nil:
+		s.audit.RecordChange(ctx, "work_orders.create", "maintenance/work-orders", order.ID, nil, order)
+	case changed:
+		s.audit.RecordChange(ctx, "work_orders.update", "maintenance/work-orders", order.ID, before, order)
+	}
+	return order, nil
+}
+
+// Get iş emrini kullanılan parçalarla birlikte getirir
+func (s *MaintenanceService) Get(ctx context.Context, id int64) (*model.WorkOrder, error) {
+	order, err := s.maintenanceRepo.GetWorkOrder(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "İş emri bulunamadı")
+	}
+	return order, nil
+}
+
+// List iş emirlerini filtreleyerek en acil olan başta olacak şekilde sayfalı getirir
+func (s *MaintenanceService) List(ctx context.Context, filter model.WorkOrderFilter, pagination *query.Pagination) ([]model.WorkOrder, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz iş emri durumu")
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bakım türü")
+	}
+	orders, err := s.maintenanceRepo.ListWorkOrders(ctx, filter, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return orders, nil
+}
+
+// History motorun servis geçmişini yeniden eskiye getirir
+func (s *MaintenanceService) History(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error) {
+	if _, err := s.motorRepo.GetByID(ctx, motorbikeID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+	orders, err := s.maintenanceRepo.ListWorkOrdersByMotorbikeID(ctx, motorbikeID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return orders, nil
+}
+
+// Update kapanmamış iş emrinin önceliğini, açıklamasını ve teknisyenini değiştirir
+func (s *MaintenanceService) Update(ctx context.Context, input UpdateWorkOrderInput) (*model.WorkOrder, error) {
+	if !input.Priority.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz öncelik")
+	}
+	if err := s.checkTechnician(ctx, input.TechnicianID); err != nil {
+		return nil, err
+	}
+
+	var order *model.WorkOrder
+	var before model.WorkOrder
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.maintenanceRepo.GetWorkOrderForUpdate(ctx, input.ID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "İş emri bulunamadı")
+		}
+		if order.Status.IsClosed() {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kapanmış iş emri değiştirilemez")
+		}
+		before = *order
+
+		order.Priority = input.Priority
+		order.Description = strings.TrimSpace(input.Description)
+		order.TechnicianID = input.TechnicianID
+		if err = s.maintenanceRepo.UpdateWorkOrder(ctx, order); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İş emri güncellenemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "work_orders.update", "maintenance/work-orders", order.ID, before, order)
+	return order, nil
+}
+
+// Start iş emri üzerinde çalışılmaya başlandığını kaydeder. Teknisyen atanmamışsa işe başlayan kişi atanır.
+func (s *MaintenanceService) Start(ctx context.Context, id, actorID int64) (*model.WorkOrder, error) {
+	return s.transition(ctx, id, actorID, "work_orders.start", model.WorkOrderInProgress, func(ctx context.Context, order *model.WorkOrder) error {
+		now := time.Now()
+		order.StartedAt = &now
+		if order.TechnicianID == nil {
+			order.TechnicianID = &actorID
+		}
+		return nil
+	})
+}
+
+// Complete iş emrini kullanılan parçalar ve işçilikle kapatır. Toplam maliyet parçaların ve işçiliğin toplamıdır.
+func (s *MaintenanceService) Complete(ctx context.Context, input CompleteWorkOrderInput) (*model.WorkOrder, error) {
+	if input.LabourMinutes < 0 || input.LabourCost < 0 {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "İşçilik süresi ve ücreti negatif olamaz")
+	}
+	var partsCost int64
+	for i, part := range input.Parts {
+		if strings.TrimSpace(part.Name) == "" || part.Quantity <= 0 || part.UnitCost < 0 {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. parça geçersiz", i+1))
+		}
+		partsCost += int64(part.Quantity) * part.UnitCost
+	}
+
+	return s.transition(ctx, input.ID, input.ActorID, "work_orders.complete", model.WorkOrderCompleted, func(ctx context.Context, order *model.WorkOrder) error {
+		parts := make([]model.WorkOrderPart, len(input.Parts))
+		for i, part := range input.Parts {
+			parts[i] = model.WorkOrderPart{
+				WorkOrderID: order.ID,
+				Name:        strings.TrimSpace(part.Name),
+				PartNumber:  strings.TrimSpace(part.PartNumber),
+				Quantity:    part.Quantity,
+				UnitCost:    part.UnitCost,
+			}
+		}
+		if err := s.maintenanceRepo.ReplaceParts(ctx, order.ID, parts); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Kullanılan parçalar kaydedilemedi")
+		}
+
+		order.Parts = parts
+		order.LabourMinutes = input.LabourMinutes
+		order.LabourCost = input.LabourCost
+		order.PartsCost = partsCost
+		order.Cost = partsCost + input.LabourCost
+		order.ResolutionNote = strings.TrimSpace(input.Note)
+		return nil
+	})
+}
+
+// Cancel iş emrini gerekçesiyle iptal eder
+func (s *MaintenanceService) Cancel(ctx context.Context, id, actorID int64, note string) (*model.WorkOrder, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "İptal gerekçesi zorunludur")
+	}
+	return s.transition(ctx, id, actorID, "work_orders.cancel", model.WorkOrderCancelled, func(ctx context.Context, order *model.WorkOrder) error {
+		order.ResolutionNote = note
+		return nil
+	})
+}
+
+// transition iş emrini satır kilidiyle alır, geçişin mümkün olduğunu kontrol eder ve apply ile değiştirir.
+// İş emri kapandığında motorun başka açık iş emri yoksa motor müsait yapılır. apply hata dönerse hiçbir değişiklik yazılmaz.
+func (s *MaintenanceService) transition(ctx context.Context, id, actorID int64, action string, next model.WorkOrderStatus,
+	apply func(ctx context.Context, order *model.WorkOrder) error) (*model.WorkOrder, error) {
+	var order *model.WorkOrder
+	var before model.WorkOrder
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.maintenanceRepo.GetWorkOrderForUpdate(ctx, id)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "İş emri bulunamadı")
+		}
+		if !order.Status.CanTransitionTo(next) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("İş emri %s durumundan %s durumuna geçirilemez", order.Status, next))
+		}
+		before = *order
+		if err = apply(ctx, order); err != nil {
+			return err
+		}
+
+		order.Status = next
+		if next.IsClosed() {
+			now := time.Now()
+			order.ClosedAt = &now
+			order.ClosedBy = &actorID
+		}
+		if err = s.maintenanceRepo.UpdateWorkOrder(ctx, order); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "İş emri güncellenemedi")
+		}
+		if !next.IsClosed() {
+			return nil
+		}
+		return s.releaseMotorbike(ctx, order.MotorbikeID)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, action, "maintenance/work-orders", order.ID, before, order)
+	return order, nil
+}
+
+// releaseMotorbike motorun kapanmamış iş emri kalmadıysa motoru bakımdan çıkarıp müsait yapar
+func (s *MaintenanceService) releaseMotorbike(ctx context.Context, motorbikeID int64) error {
+	open, err := s.maintenanceRepo.ListOpenWorkOrdersByMotorbikeID(ctx, motorbikeID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if len(open) > 0 {
+		return nil
+	}
+
+	motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+	}
+	if motorbike.Status != model.BikeInMaintenance {
+		return nil
+	}
+	motorbike.Status = model.BikeAvailable
+	if err = s.motorRepo.UpdateColumns(ctx, motorbike, "status"); err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor durumu güncellenirken hata oluştu!")
+	}
+	return nil
+}
+
+// checkTechnician atanan teknisyenin rolünün iş emirlerini yönetme yetkisi olduğunu kontrol eder
+func (s *MaintenanceService) checkTechnician(ctx context.Context, technicianID *int64) error {
+	if technicianID == nil {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, *technicianID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Teknisyen bulunamadı")
+	}
+	ok, err := s.permissions.HasPermission(ctx, user.Role, model.PermMaintenanceWrite)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "İş emri yalnızca yetkili personele atanabilir")
+	}
+	return nil
+}
+
+func (s *MaintenanceService) CreateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error {
+	if err := validateSchedule(schedule); err != nil {
+		return err
+	}
+	if err := s.maintenanceRepo.CreateSchedule(ctx, schedule); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "maintenance_schedules.create", "maintenance/schedules", schedule.ID, nil, schedule)
+	return nil
+}
+
+func (s *MaintenanceService) GetSchedule(ctx context.Context, id int64) (*model.MaintenanceSchedule, error) {
+	schedule, err := s.maintenanceRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Bakım planı bulunamadı")
+	}
+	return schedule, nil
+}
+
+func (s *MaintenanceService) UpdateSchedule(ctx context.Context, schedule model.MaintenanceSchedule) error {
+	if err := validateSchedule(&schedule); err != nil {
+		return err
+	}
+	current, err := s.GetSchedule(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.maintenanceRepo.UpdateSchedule(ctx, &schedule); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "maintenance_schedules.update", "maintenance/schedules", schedule.ID, current, schedule)
+	return nil
+}
+
+func (s *MaintenanceService) DeleteSchedule(ctx context.Context, id int64) error {
+	current, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.maintenanceRepo.DeleteSchedule(ctx, id); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "maintenance_schedules.delete", "maintenance/schedules", id, current, nil)
+	return nil
+}
+
+func (s *MaintenanceService) ListSchedules(ctx context.Context) ([]model.MaintenanceSchedule, error) {
+	schedules, err := s.maintenanceRepo.ListSchedules(ctx, false)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return schedules, nil
+}
+
+func validateSchedule(schedule *model.MaintenanceSchedule) error {
+	if !schedule.Type.IsValid() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz bakım türü")
+	}
+	if schedule.Priority == "" {
+		schedule.Priority = model.PriorityNormal
+	}
+	if !schedule.Priority.IsValid() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz öncelik")
+	}
+	if schedule.IntervalMeters < 0 || schedule.IntervalRides < 0 || (schedule.IntervalMeters == 0 && schedule.IntervalRides == 0) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kilometre veya sürüş sayısı aralığından en az biri girilmelidir")
+	}
+	return nil
+}
+
+// OpenDue aktif planları tüm motorlar için kontrol eder ve zamanı gelen bakımlar için iş emri açar. Kullanımdaki
+// motorlar atlanır, bir sonraki turda tekrar kontrol edilir. Açılan iş emri sayısını döner.
+func (s *MaintenanceService) OpenDue(ctx context.Context) (int, error) {
+	schedules, err := s.maintenanceRepo.ListSchedules(ctx, true)
+	if err != nil || len(schedules) == 0 {
+		return 0, err
+	}
+	motorbikes, err := s.motorRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	opened := 0
+	for _, motorbike := range motorbikes {
+		if motorbike.Status != model.BikeAvailable && motorbike.Status != model.BikeInMaintenance {
+			continue
+		}
+		count, err := s.openDueForMotorbike(ctx, motorbike, schedules)
+		opened += count
+		if err != nil {
+			logger.Error("Koruyucu bakım kontrolü başarısız (motorbike_id=%d): %v", motorbike.ID, err)
+		}
+	}
+	return opened, nil
+}
+
+func (s *MaintenanceService) openDueForMotorbike(ctx context.Context, motorbike model.Motorbike, schedules []model.MaintenanceSchedule) (int, error) {
+	rideCount, err := s.rideRepo.CountEndedByMotorbikeID(ctx, motorbike.ID)
+	if err != nil {
+		return 0, err
+	}
+	open, err := s.maintenanceRepo.ListOpenWorkOrdersByMotorbikeID(ctx, motorbike.ID)
+	if err != nil {
+		return 0, err
+	}
+	pending := map[model.MaintenanceType]bool{}
+	for _, order := range open {
+		pending[order.Type] = true
+	}
+
+	opened := 0
+	for _, schedule := range schedules {
+		if !schedule.AppliesTo(motorbike) || pending[schedule.Type] {
+			continue
+		}
+		last, err := s.maintenanceRepo.GetLastCompletedWorkOrder(ctx, motorbike.ID, schedule.Type)
+		if err != nil {
+			return opened, err
+		}
+		if !schedule.IsDue(motorbike.OdometerMeters, rideCount, last) {
+			continue
+		}
+
+		scheduleID := schedule.ID
+		if _, err = s.Open(ctx, OpenWorkOrderInput{
+			MotorbikeID: motorbike.ID,
+			Type:        schedule.Type,
+			Priority:    schedule.Priority,
+			Description: "Koruyucu bakım: " + schedule.Name,
+			ScheduleID:  &scheduleID,
+		}); err != nil {
+			return opened, err
+		}
+		pending[schedule.Type] = true
+		opened++
+	}
+	return opened, nil
+}
+
+// RunScheduleWorker ctx iptal edilene kadar belirtilen aralıklarla koruyucu bakım planlarını kontrol eder
+func (s *MaintenanceService) RunScheduleWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.OpenDue(ctx)
+			if err != nil {
+				logger.Error("Koruyucu bakım kontrolü hatası: %v", err)
+				continue
+			}
+			if count > 0 {
+				logger.Info("Koruyucu bakım planlarından %d iş emri açıldı", count)
+			}
+		}
+	}
+}
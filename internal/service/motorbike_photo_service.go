@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+)
+
+// MotorbikePhotoService motorların fotoğraf galerisini yönetir. Yüklenen fotoğraflar RidePhotoService ile aynı şekilde
+// doğrulanıp temizlenir ve BlobStore'a yazılır. Galeri sırası ve kapak fotoğrafı motor satırı kilitlenerek değiştirilir;
+// böylece eşzamanlı yüklemeler aynı sırayı almaz ve motorun her zaman en fazla bir kapağı olur.
+type MotorbikePhotoService struct {
+	motorRepo repository.IMotorbikeRepository
+	txManager repository.ITransactionManager
+	store     storage.BlobStore
+	maxBytes  int64
+	maxPhotos int
+}
+
+func NewMotorbikePhotoService(motorRepo repository.IMotorbikeRepository, txManager repository.ITransactionManager, store storage.BlobStore, maxBytes int64, maxPhotos int) *MotorbikePhotoService {
+	return &MotorbikePhotoService{motorRepo: motorRepo, txManager: txManager, store: store, maxBytes: maxBytes, maxPhotos: maxPhotos}
+}
+
+// Upload fotoğrafları galerinin sonuna ekler. Herhangi bir fotoğraf geçersizse hiçbiri kaydedilmez.
+// Motorun kapak fotoğrafı yoksa ilk yüklenen fotoğraf kapak olur.
+func (s *MotorbikePhotoService) Upload(ctx context.Context, motorbikeID int64, files [][]byte) ([]model.MotorbikePhoto, error) {
+	if len(files) == 0 {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "En az bir fotoğraf yüklenmelidir")
+	}
+	if _, err := s.motorRepo.GetByID(ctx, motorbikeID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+
+	images := make([]*storage.Image, len(files))
+	for i, data := range files {
+		img, err := storage.ProcessImage(data, s.maxBytes, ridePhotoThumbSize)
+		switch {
+		case errors.Is(err, storage.ErrTooLarge):
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, s.maxBytes>>20))
+		case errors.Is(err, storage.ErrInvalidImage):
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf JPEG veya PNG formatında olmalıdır", i+1))
+		case err != nil:
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		images[i] = img
+	}
+
+	photos := make([]model.MotorbikePhoto, 0, len(images))
+	var keys []string
+	for _, img := range images {
+		name, err := randomName()
+		if err != nil {
+			s.remove(ctx, keys...)
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		photo := model.MotorbikePhoto{
+			MotorbikeID:  motorbikeID,
+			StorageKey:   fmt.Sprintf("motorbikes/%d/%s%s", motorbikeID, name, img.Ext),
+			ThumbnailKey: fmt.Sprintf("motorbikes/%d/%s_thumb.jpg", motorbikeID, name),
+			ContentType:  img.ContentType,
+			Size:         int64(len(img.Data)),
+			Width:        img.Width,
+			Height:       img.Height,
+		}
+		if err = s.store.Put(ctx, photo.StorageKey, bytes.NewReader(img.Data), photo.Size, photo.ContentType); err != nil {
+			s.remove(ctx, keys...)
+			return nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.StorageKey)
+		if err = s.store.Put(ctx, photo.ThumbnailKey, bytes.NewReader(img.Thumbnail), int64(len(img.Thumbnail)), "image/jpeg"); err != nil {
+			s.remove(ctx, keys...)
+			return nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.ThumbnailKey)
+		photos = append(photos, photo)
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID); err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		existing, err := s.motorRepo.ListPhotos(ctx, motorbikeID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if s.maxPhotos > 0 && len(existing)+len(photos) > s.maxPhotos {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bir motorun en fazla %d fotoğrafı olabilir", s.maxPhotos))
+		}
+
+		hasPrimary := false
+		for _, photo := range existing {
+			hasPrimary = hasPrimary || photo.IsPrimary
+		}
+		for i := range photos {
+			photos[i].Position = len(existing) + i
+		}
+		photos[0].IsPrimary = !hasPrimary
+
+		if err = s.motorRepo.CreatePhotos(ctx, photos); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		s.remove(ctx, keys...)
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return photos, nil
+}
+
+// List motorun galerisini sırasına göre getirir
+func (s *MotorbikePhotoService) List(ctx context.Context, motorbikeID int64) ([]model.MotorbikePhoto, error) {
+	photos, err := s.motorRepo.ListPhotos(ctx, motorbikeID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return photos, nil
+}
+
+// Covers verilen motorların kapak fotoğraflarını motor kimliğine göre döner
+func (s *MotorbikePhotoService) Covers(ctx context.Context, motorbikeIDs []int64) (map[int64]model.MotorbikePhoto, error) {
+	photos, err := s.motorRepo.ListPrimaryPhotos(ctx, motorbikeIDs)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	covers := make(map[int64]model.MotorbikePhoto, len(photos))
+	for _, photo := range photos {
+		covers[photo.MotorbikeID] = photo
+	}
+	return covers, nil
+}
+
+// Reorder galeriyi verilen sıraya dizer. Liste motorun tüm fotoğraflarını bir kez içermelidir.
+func (s *MotorbikePhotoService) Reorder(ctx context.Context, motorbikeID int64, photoIDs []int64) ([]model.MotorbikePhoto, error) {
+	var photos []model.MotorbikePhoto
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if photos, err = s.lockGallery(ctx, motorbikeID); err != nil {
+			return err
+		}
+		if len(photoIDs) != len(photos) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sıralama motorun tüm fotoğraflarını içermelidir")
+		}
+
+		positions := make(map[int64]int, len(photoIDs))
+		for i, id := range photoIDs {
+			if _, ok := positions[id]; ok {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sıralamada aynı fotoğraf birden fazla kez var")
+			}
+			positions[id] = i
+		}
+
+		for i := range photos {
+			position, ok := positions[photos[i].ID]
+			if !ok {
+				return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sıralama motorun tüm fotoğraflarını içermelidir")
+			}
+			if photos[i].Position == position {
+				continue
+			}
+			photos[i].Position = position
+			if err = s.motorRepo.UpdatePhotoColumns(ctx, &photos[i], "position"); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return s.List(ctx, motorbikeID)
+}
+
+// SetPrimary fotoğrafı motorun kapak fotoğrafı yapar
+func (s *MotorbikePhotoService) SetPrimary(ctx context.Context, motorbikeID, photoID int64) ([]model.MotorbikePhoto, error) {
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		photos, err := s.lockGallery(ctx, motorbikeID)
+		if err != nil {
+			return err
+		}
+		target := findMotorbikePhoto(photos, photoID)
+		if target == nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Fotoğraf bulunamadı")
+		}
+
+		// Motor başına tek kapak indeksi nedeniyle önce eski kapak kaldırılır
+		for i := range photos {
+			if photos[i].IsPrimary && photos[i].ID != photoID {
+				photos[i].IsPrimary = false
+				if err = s.motorRepo.UpdatePhotoColumns(ctx, &photos[i], "is_primary"); err != nil {
+					return errorx.WrapErr(errorx.ErrInternal, err)
+				}
+			}
+		}
+		if !target.IsPrimary {
+			target.IsPrimary = true
+			if err = s.motorRepo.UpdatePhotoColumns(ctx, target, "is_primary"); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return s.List(ctx, motorbikeID)
+}
+
+// Delete fotoğrafı galeriden kaldırır ve dosyalarını siler. Kalan fotoğraflar boşluk kalmayacak şekilde yeniden sıralanır;
+// silinen fotoğraf kapaksa galerinin ilk fotoğrafı kapak olur.
+func (s *MotorbikePhotoService) Delete(ctx context.Context, motorbikeID, photoID int64) error {
+	var deleted *model.MotorbikePhoto
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		photos, err := s.lockGallery(ctx, motorbikeID)
+		if err != nil {
+			return err
+		}
+		if deleted = findMotorbikePhoto(photos, photoID); deleted == nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Fotoğraf bulunamadı")
+		}
+		if err = s.motorRepo.DeletePhoto(ctx, photoID); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		position := 0
+		for i := range photos {
+			photo := &photos[i]
+			if photo.ID == photoID {
+				continue
+			}
+			columns := make([]string, 0, 2)
+			if photo.Position != position {
+				photo.Position = position
+				columns = append(columns, "position")
+			}
+			if deleted.IsPrimary && position == 0 {
+				photo.IsPrimary = true
+				columns = append(columns, "is_primary")
+			}
+			if len(columns) > 0 {
+				if err = s.motorRepo.UpdatePhotoColumns(ctx, photo, columns...); err != nil {
+					return errorx.WrapErr(errorx.ErrInternal, err)
+				}
+			}
+			position++
+		}
+		return nil
+	})
+	if err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	s.remove(ctx, deleted.StorageKey, deleted.ThumbnailKey)
+	return nil
+}
+
+// lockGallery motor satırını kilitleyip galeriyi getirir, transaction içinde kullanılmalıdır
+func (s *MotorbikePhotoService) lockGallery(ctx context.Context, motorbikeID int64) ([]model.MotorbikePhoto, error) {
+	if _, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+	photos, err := s.motorRepo.ListPhotos(ctx, motorbikeID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return photos, nil
+}
+
+// remove kaydı tutulmayan fotoğraf dosyalarını siler, silinemeyenler loglanır. Eski (yalnızca URL'li) kayıtların anahtarı boştur.
+func (s *MotorbikePhotoService) remove(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error("Motor fotoğrafı dosyası silinemedi (%s): %v", key, err)
+		}
+	}
+}
+
+func findMotorbikePhoto(photos []model.MotorbikePhoto, photoID int64) *model.MotorbikePhoto {
+	for i := range photos {
+		if photos[i].ID == photoID {
+			return &photos[i]
+		}
+	}
+	return nil
+}
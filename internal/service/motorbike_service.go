@@ -2,23 +2,30 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
 )
 
 type MotorbikeService struct {
 	motorbikeRepo repository.IMotorbikeRepository
+	audit         Auditor
 }
 
-func NewMotorbikeService(repo repository.IMotorbikeRepository) *MotorbikeService {
-	return &MotorbikeService{motorbikeRepo: repo}
+func NewMotorbikeService(repo repository.IMotorbikeRepository, audit Auditor) *MotorbikeService {
+	return &MotorbikeService{motorbikeRepo: repo, audit: audit}
 }
 
 func (s *MotorbikeService) Create(ctx context.Context, motorbike *model.Motorbike) error {
 	if err := s.motorbikeRepo.Create(ctx, motorbike); err != nil {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
+	s.audit.RecordChange(ctx, "motorbike.create", "motorbike", motorbike.ID, nil, motorbike)
 	return nil
 }
 
@@ -31,16 +38,26 @@ func (s *MotorbikeService) GetByID(ctx context.Context, id int64) (*model.Motorb
 }
 
 func (s *MotorbikeService) Update(ctx context.Context, motorbike model.Motorbike) error {
-	if err := s.motorbikeRepo.Update(ctx, &motorbike); err != nil {
+	current, err := s.GetByID(ctx, motorbike.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.motorbikeRepo.Update(ctx, &motorbike); err != nil {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
+	s.audit.RecordChange(ctx, "motorbike.update", "motorbike", motorbike.ID, current, motorbike)
 	return nil
 }
 
 func (s *MotorbikeService) Delete(ctx context.Context, id int64) error {
-	if err := s.motorbikeRepo.Delete(ctx, id); err != nil {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.motorbikeRepo.Delete(ctx, id); err != nil {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
+	s.audit.RecordChange(ctx, "motorbike.delete", "motorbike", id, current, nil)
 	return nil
 }
 
@@ -60,10 +77,68 @@ func (s *MotorbikeService) GetMotorsForStatus(ctx context.Context, status string
 	return motorbikes, nil
 }
 
-func (s *MotorbikeService) GetPhotosByID(ctx context.Context, motorbikeID string) ([]model.MotorbikePhoto, error) {
-	photos, err := s.motorbikeRepo.GetPhotosByID(ctx, motorbikeID)
+// NearbyMotorbike arama noktasına olan mesafesiyle birlikte motor
+type NearbyMotorbike struct {
+	Motorbike      model.Motorbike
+	DistanceMeters float64
+}
+
+// FindNearby merkez noktaya radiusMeters içindeki müsait motorları yakından uzağa sıralı döner.
+// Veritabanı dikdörtgen alanla ön filtreleme yapar, kesin mesafe haversine ile hesaplanır.
+func (s *MotorbikeService) FindNearby(ctx context.Context, center geo.Point, radiusMeters float64, limit int) ([]NearbyMotorbike, error) {
+	candidates, err := s.motorbikeRepo.ListAvailableInBounds(ctx, geo.BoundingBoxAround(center, radiusMeters))
 	if err != nil {
 		return nil, errorx.WrapErr(errorx.ErrInternal, err)
 	}
-	return photos, nil
+
+	nearby := make([]NearbyMotorbike, 0, len(candidates))
+	for _, m := range candidates {
+		distance := geo.HaversineMeters(center, geo.Point{Lat: m.LocationLatitude, Lng: m.LocationLongitude})
+		if distance <= radiusMeters {
+			nearby = append(nearby, NearbyMotorbike{Motorbike: m, DistanceMeters: distance})
+		}
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return nearby[i].DistanceMeters < nearby[j].DistanceMeters
+	})
+	if limit > 0 && len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// RotateDeviceKey motor için yeni bir cihaz anahtarı üretir ve eskisini geçersiz kılar.
+// Anahtarın kendisi saklanmaz, yalnızca bu çağrının sonucunda bir kez döner.
+func (s *MotorbikeService) RotateDeviceKey(ctx context.Context, motorbikeID int64) (string, error) {
+	motorbike, err := s.motorbikeRepo.GetByID(ctx, motorbikeID)
+	if err != nil {
+		return "", errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	key := hex.EncodeToString(buf)
+
+	motorbike.SetDeviceKey(key)
+	if err = s.motorbikeRepo.UpdateColumns(ctx, motorbike, "device_key_hash"); err != nil {
+		return "", errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	// Anahtar ve özeti kayda yazılmaz, yalnızca yenilendiği bilinir
+	s.audit.RecordChange(ctx, "motorbike.rotate_device_key", "motorbike", motorbikeID, nil, nil)
+	return key, nil
+}
+
+// AuthenticateDevice cihaz anahtarına ait motoru döner
+func (s *MotorbikeService) AuthenticateDevice(ctx context.Context, key string) (*model.Motorbike, error) {
+	if key == "" {
+		return nil, errorx.WrapMsg(errorx.ErrUnauthorized, "Cihaz anahtarı bulunamadı")
+	}
+	motorbike, err := s.motorbikeRepo.GetByDeviceKeyHash(ctx, model.HashDeviceKey(key))
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrUnauthorized, "Geçersiz cihaz anahtarı")
+	}
+	return motorbike, nil
 }
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+// ParkingDecision sürüşün bulunduğu noktada bitirilip bitirilemeyeceğini ve uygulanacak ek ücretleri tutar
+type ParkingDecision struct {
+	Allowed       bool
+	Reason        string            // Allowed false ise kullanıcıya gösterilecek sebep
+	Surcharges    []model.PriceLine // fiyat dökümüne eklenecek park ücretleri
+	PreferredZone *model.Zone       // nokta önerilen bir park alanındaysa
+}
+
+// EvaluateParking aktif alanlara göre verilen noktada park kurallarını uygular.
+// Tanımlı operasyon alanı varsa nokta bunlardan birinin içinde olmalıdır; dışındaysa operasyon alanlarının
+// en katı kuralı uygulanır. Nokta park yasağı olan bir alandaysa o alanın kuralı uygulanır.
+func EvaluateParking(zones []model.Zone, point geo.Point) (ParkingDecision, error) {
+	decision := ParkingDecision{Allowed: true}
+
+	var (
+		hasOperating, inOperating bool
+		outsideReject             bool
+		outsideSurcharge          int64
+	)
+
+	for i := range zones {
+		zone := zones[i]
+		area, err := geo.ParseGeoJSON(zone.Geometry)
+		if err != nil {
+			return decision, fmt.Errorf("alan %d: %w", zone.ID, err)
+		}
+		inside := area.Contains(point)
+
+		switch zone.Type {
+		case model.ZoneOperating:
+			hasOperating = true
+			if inside {
+				inOperating = true
+			}
+			if zone.Enforcement == model.ZoneReject {
+				outsideReject = true
+			} else if zone.Surcharge > outsideSurcharge {
+				outsideSurcharge = zone.Surcharge
+			}
+
+		case model.ZoneNoParking:
+			if !inside {
+				continue
+			}
+			if zone.Enforcement == model.ZoneReject {
+				decision.reject(fmt.Sprintf("%s park yasağı olan bir alan. Lütfen motoru başka bir yere park edin", zone.Name))
+				continue
+			}
+			decision.addSurcharge("Park yasağı olan alan ücreti: "+zone.Name, zone.Surcharge)
+
+		case model.ZonePreferredParking:
+			if inside && decision.PreferredZone == nil {
+				decision.PreferredZone = &zone
+			}
+		}
+	}
+
+	if hasOperating && !inOperating {
+		if outsideReject {
+			decision.reject("Sürüş hizmet alanı dışında bitirilemez. Lütfen hizmet alanına geri dönün")
+		} else {
+			decision.addSurcharge("Hizmet alanı dışında bırakma ücreti", outsideSurcharge)
+		}
+	}
+
+	return decision, nil
+}
+
+func (d *ParkingDecision) reject(reason string) {
+	if d.Allowed {
+		d.Allowed = false
+		d.Reason = reason
+	}
+}
+
+func (d *ParkingDecision) addSurcharge(description string, amount int64) {
+	if amount <= 0 {
+		return
+	}
+	d.Surcharges = append(d.Surcharges, model.PriceLine{
+		Code:        model.PriceLineParkingSurcharge,
+		Description: description,
+		Amount:      amount,
+	})
+}
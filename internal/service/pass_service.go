@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+)
+
+const subscriptionRenewalBatchSize = 100
+
+// PassService abonelik paketlerini ve kullanıcı aboneliklerini yönetir. Abonelik ücreti önce cüzdandan düşülür,
+// cüzdanın karşılamadığı kısım karttan tahsil edilir. Sürüş ücretlendirilirken aboneliğin o günkü dahil dakikaları
+// ücretli dakikalardan önce kullanılır.
+type PassService struct {
+	passRepo  repository.IPassRepository
+	wallet    *WalletService
+	payments  *PaymentService
+	txManager repository.ITransactionManager
+	location  *time.Location // günlük dahil dakikalar bu saat dilimindeki güne göre sayılır
+	audit     Auditor
+}
+
+func NewPassService(passRepo repository.IPassRepository, wallet *WalletService, payments *PaymentService, txManager repository.ITransactionManager, location *time.Location, audit Auditor) *PassService {
+	if location == nil {
+		location = time.UTC
+	}
+	return &PassService{
+		passRepo:  passRepo,
+		wallet:    wallet,
+		payments:  payments,
+		txManager: txManager,
+		location:  location,
+		audit:     audit,
+	}
+}
+
+func (s *PassService) CreateProduct(ctx context.Context, product *model.PassProduct) error {
+	if err := s.passRepo.CreateProduct(ctx, product); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "pass_products.create", "passes/products", product.ID, nil, product)
+	return nil
+}
+
+func (s *PassService) GetProduct(ctx context.Context, id int64) (*model.PassProduct, error) {
+	product, err := s.passRepo.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Abonelik paketi bulunamadı")
+	}
+	return product, nil
+}
+
+func (s *PassService) UpdateProduct(ctx context.Context, product model.PassProduct) error {
+	current, err := s.GetProduct(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.passRepo.UpdateProduct(ctx, &product); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "pass_products.update", "passes/products", product.ID, current, product)
+	return nil
+}
+
+func (s *PassService) DeleteProduct(ctx context.Context, id int64) error {
+	current, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.passRepo.DeleteProduct(ctx, id); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "pass_products.delete", "passes/products", id, current, nil)
+	return nil
+}
+
+// ListProducts paketleri fiyata göre sıralı getirir. activeOnly true ise yalnızca satıştaki paketler döner.
+func (s *PassService) ListProducts(ctx context.Context, activeOnly bool) ([]model.PassProduct, error) {
+	products, err := s.passRepo.ListProducts(ctx, activeOnly)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return products, nil
+}
+
+// Purchase kullanıcıya paketi satın alır ve ilk dönemin ücretini tahsil eder. Kullanıcının aynı anda tek aktif aboneliği olabilir.
+func (s *PassService) Purchase(ctx context.Context, userID, productID int64, autoRenew bool) (*model.Subscription, error) {
+	product, err := s.passRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Abonelik paketi bulunamadı")
+	}
+	if !product.IsActive {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu paket satışta değil")
+	}
+
+	active, err := s.passRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if active != nil {
+		return nil, errorx.WrapMsg(errorx.ErrDuplicate, "Zaten aktif bir aboneliğiniz var")
+	}
+
+	now := time.Now().UTC()
+	subscription := &model.Subscription{
+		UserID:             userID,
+		ProductID:          product.ID,
+		Status:             model.SubscriptionActive,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   product.Period.End(now),
+		AutoRenew:          autoRenew,
+	}
+	reference := fmt.Sprintf("pass-user-%d-%d", userID, now.UnixNano())
+	if err = s.chargePeriod(ctx, subscription, product, reference); err != nil {
+		return nil, err
+	}
+
+	subscription.Product = product
+	return subscription, nil
+}
+
+// PassStatus kullanıcının aktif aboneliği ve bugün kalan dahil dakikaları
+type PassStatus struct {
+	Subscription     *model.Subscription
+	RemainingMinutes int
+}
+
+// GetActive kullanıcının aktif aboneliğini ve bugün kalan dahil dakikalarını getirir
+func (s *PassService) GetActive(ctx context.Context, userID int64) (*PassStatus, error) {
+	subscription, err := s.passRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	now := time.Now().UTC()
+	if subscription == nil || !subscription.IsActiveAt(now) {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Aktif bir aboneliğiniz yok")
+	}
+
+	remaining, err := s.remainingMinutes(ctx, subscription, s.usageDay(now))
+	if err != nil {
+		return nil, err
+	}
+	return &PassStatus{Subscription: subscription, RemainingMinutes: remaining}, nil
+}
+
+// CancelAutoRenew aboneliğin otomatik yenilemesini kapatır; abonelik dönem sonuna kadar kullanılabilir
+func (s *PassService) CancelAutoRenew(ctx context.Context, userID int64) (*model.Subscription, error) {
+	var subscription *model.Subscription
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		subscription, err = s.passRepo.GetActiveByUserIDForUpdate(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if subscription == nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Aktif bir aboneliğiniz yok")
+		}
+		if !subscription.AutoRenew {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Aboneliğin otomatik yenilemesi zaten kapalı")
+		}
+
+		now := time.Now().UTC()
+		subscription.AutoRenew = false
+		subscription.CancelledAt = &now
+		if err = s.passRepo.UpdateSubscription(ctx, subscription); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	return subscription, nil
+}
+
+// PassAllowance sürüşe uygulanacak abonelik avantajı
+type PassAllowance struct {
+	Subscription   *model.Subscription
+	Day            time.Time // dahil dakikaların düşüleceği gün
+	Minutes        int       // o gün kalan dahil dakika
+	WaiveUnlockFee bool
+}
+
+// Apply avantajı ücret girdisine ekler; avantaj yoksa girdi değişmez
+func (a *PassAllowance) Apply(in *FareInput) {
+	if a == nil {
+		return
+	}
+	in.PassMinutes = a.Minutes
+	in.WaiveUnlockFee = a.WaiveUnlockFee
+}
+
+// Allowance kullanıcının sürüş başladığı anda geçerli aboneliğinden kullanabileceği avantajı döner, abonelik yoksa nil döner.
+// forUpdate true ise abonelik satırı kilitlenir; FinishRide içinde aynı günün dakikalarının iki sürüşe birden verilmesini önler.
+func (s *PassService) Allowance(ctx context.Context, userID int64, rideStart time.Time, forUpdate bool) (*PassAllowance, error) {
+	var subscription *model.Subscription
+	var err error
+	if forUpdate {
+		subscription, err = s.passRepo.GetActiveByUserIDForUpdate(ctx, userID)
+	} else {
+		subscription, err = s.passRepo.GetActiveByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Abonelik bilgisi alınamadı")
+	}
+	if subscription == nil || subscription.Product == nil || !subscription.IsActiveAt(rideStart) {
+		return nil, nil
+	}
+
+	day := s.usageDay(rideStart)
+	remaining, err := s.remainingMinutes(ctx, subscription, day)
+	if err != nil {
+		return nil, err
+	}
+	return &PassAllowance{
+		Subscription:   subscription,
+		Day:            day,
+		Minutes:        remaining,
+		WaiveUnlockFee: subscription.Product.WaiveUnlockFee,
+	}, nil
+}
+
+// RecordUsage sürüşte kullanılan dahil dakikaları kaydeder. FinishRide transaction'ı içinde çağrılır.
+func (s *PassService) RecordUsage(ctx context.Context, allowance *PassAllowance, rideID int64, breakdown *model.RidePriceBreakdown) error {
+	if allowance == nil {
+		return nil
+	}
+	for _, line := range breakdown.Lines {
+		if line.Code != model.PriceLinePass {
+			continue
+		}
+		usage := &model.PassUsage{
+			SubscriptionID: allowance.Subscription.ID,
+			RideID:         rideID,
+			UsageDate:      allowance.Day,
+			Minutes:        line.Quantity,
+			UnlockWaived:   allowance.WaiveUnlockFee,
+		}
+		if err := s.passRepo.CreateUsage(ctx, usage); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Abonelik kullanımı kaydedilemedi")
+		}
+	}
+	return nil
+}
+
+// RenewDue dönemi biten abonelikleri yeniler. Otomatik yenilemesi kapalı, paketi satıştan kaldırılmış veya ücreti
+// alınamayan abonelikler sona erer. Yenilenen ve sona eren abonelik sayılarını döner.
+func (s *PassService) RenewDue(ctx context.Context, now time.Time) (renewed, expired int, err error) {
+	due, err := s.passRepo.ListDueForRenewal(ctx, now, subscriptionRenewalBatchSize)
+	if err != nil {
+		return 0, 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	for _, item := range due {
+		ok, err := s.renew(ctx, item.ID, now)
+		if err != nil {
+			return renewed, expired, err
+		}
+		if ok {
+			renewed++
+		} else {
+			expired++
+		}
+	}
+	return renewed, expired, nil
+}
+
+// RunRenewalWorker ctx iptal edilene kadar belirtilen aralıklarla dönemi biten abonelikleri yeniler
+func (s *PassService) RunRenewalWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, expired, err := s.RenewDue(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Error("Abonelik yenileme hatası: %v", err)
+				continue
+			}
+			if renewed > 0 || expired > 0 {
+				logger.Info("%d abonelik yenilendi, %d abonelik sona erdi", renewed, expired)
+			}
+		}
+	}
+}
+
+// renew tek bir aboneliği yeniler; yenilenemezse sona erdirir ve false döner
+func (s *PassService) renew(ctx context.Context, subscriptionID int64, now time.Time) (bool, error) {
+	var subscription *model.Subscription
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		// Bu arada başka bir worker tarafından yenilenmiş olabilir
+		var err error
+		subscription, err = s.passRepo.GetSubscriptionByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, errorx.FromError(errorx.ErrInternal, err)
+	}
+	if subscription.Status != model.SubscriptionActive || subscription.CurrentPeriodEnd.After(now) {
+		return true, nil
+	}
+
+	product := subscription.Product
+	if !subscription.AutoRenew || product == nil || !product.IsActive {
+		return false, s.expire(ctx, subscription, "")
+	}
+
+	// Worker uzun süre çalışmadıysa geçmiş dönemler ücretlendirilmez, yeni dönem şimdi başlar
+	start := subscription.CurrentPeriodEnd
+	if !product.Period.End(start).After(now) {
+		start = now
+	}
+	previousStart, previousEnd := subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd
+	subscription.CurrentPeriodStart = start
+	subscription.CurrentPeriodEnd = product.Period.End(start)
+	subscription.RenewalFailure = ""
+
+	reference := fmt.Sprintf("subscription-%d-%d", subscription.ID, start.Unix())
+	if err = s.chargePeriod(ctx, subscription, product, reference); err != nil {
+		logger.Error("Abonelik yenilenemedi (subscription_id=%d): %v", subscription.ID, err)
+		subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd = previousStart, previousEnd
+		return false, s.expire(ctx, subscription, err.Error())
+	}
+	return true, nil
+}
+
+func (s *PassService) expire(ctx context.Context, subscription *model.Subscription, reason string) error {
+	subscription.Status = model.SubscriptionExpired
+	subscription.AutoRenew = false
+	subscription.RenewalFailure = reason
+	if err := s.passRepo.UpdateSubscription(ctx, subscription); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// chargePeriod aboneliğin güncel döneminin ücretini alır ve aboneliği kaydeder. Cüzdanın karşılamadığı kısım
+// transaction dışında karttan tahsil edilir; kayıt başarısız olursa kart tahsilatı iade edilir.
+func (s *PassService) chargePeriod(ctx context.Context, subscription *model.Subscription, product *model.PassProduct, reference string) error {
+	wallet, err := s.wallet.GetWallet(ctx, subscription.UserID)
+	if err != nil {
+		return err
+	}
+	if wallet.Currency != product.Currency {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Paket para birimi cüzdanla uyuşmuyor")
+	}
+
+	cardAmount := product.Price - max(wallet.Balance, 0)
+	var capture *payment.Capture
+	if cardAmount > 0 {
+		capture, err = s.payments.ChargeCard(ctx, subscription.UserID, cardAmount, product.Currency, reference, "Abonelik: "+product.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		cardAmount = 0
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if subscription.ID == 0 {
+			err = s.passRepo.CreateSubscription(ctx, subscription)
+		} else {
+			err = s.passRepo.UpdateSubscription(ctx, subscription)
+		}
+		if err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Abonelik kaydedilemedi")
+		}
+
+		transaction, err := s.wallet.ChargePass(ctx, subscription.UserID, product.Price, cardAmount, product.Currency, "Abonelik: "+product.Name)
+		if err != nil {
+			return err
+		}
+
+		charge := &model.SubscriptionCharge{
+			SubscriptionID: subscription.ID,
+			UserID:         subscription.UserID,
+			Amount:         product.Price,
+			CardAmount:     cardAmount,
+			Currency:       product.Currency,
+			PeriodStart:    subscription.CurrentPeriodStart,
+			PeriodEnd:      subscription.CurrentPeriodEnd,
+			TransactionID:  transaction.ID,
+		}
+		if err = s.passRepo.CreateCharge(ctx, charge); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Abonelik ücreti kaydedilemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		if capture != nil {
+			s.payments.RefundCard(ctx, capture)
+		}
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+func (s *PassService) remainingMinutes(ctx context.Context, subscription *model.Subscription, day time.Time) (int, error) {
+	if subscription.Product == nil || subscription.Product.IncludedMinutesPerDay == 0 {
+		return 0, nil
+	}
+	used, err := s.passRepo.SumUsageMinutes(ctx, subscription.ID, day)
+	if err != nil {
+		return 0, errorx.Wrap(errorx.ErrInternal, err, "Abonelik kullanımı alınamadı")
+	}
+	return max(subscription.Product.IncludedMinutesPerDay-used, 0), nil
+}
+
+// usageDay anın yerel saat dilimindeki gününü döner
+func (s *PassService) usageDay(t time.Time) time.Time {
+	y, m, d := t.In(s.location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,590 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+)
+
+// settlingClaimTimeout bu süreden uzun süredir settling durumunda kalan ödeme denemesi yarıda kalmış sayılır
+const settlingClaimTimeout = 5 * time.Minute
+
+const (
+	// settleProviderCalls Settle'ın sağlayıcıya yapabileceği en fazla çağrı sayısı (void, authorize, capture, refund)
+	settleProviderCalls = 4
+	// stuckSettlementBatchSize bir turda payment_failed durumuna geçirilen en fazla sürüş sayısı
+	stuckSettlementBatchSize = 100
+)
+
+// errSettlementStuck ödemesi kapatılamadan ending durumunda kalan sürüşün payment_failed notudur
+var errSettlementStuck = errors.New("sürüşün ödemesi sağlayıcı zaman aşımı içinde kapatılamadı")
+
+// PaymentService sürüşlerin kart ödemelerini yönetir. Sürüş başlarken karttan sabit bir tutar bloke edilir;
+// sürüş bitince ücret önce cüzdandan düşülür, cüzdanın karşılamadığı kısım provizyondan tahsil edilir
+// ve cüzdana kart yüklemesi olarak yazılır. Tahsil edilemeyen sürüş payment_failed durumuna geçer.
+type PaymentService struct {
+	provider    payment.PaymentProvider
+	paymentRepo repository.IRidePaymentRepository
+	rideRepo    repository.IRideRepository
+	wallet      *WalletService
+	txManager   repository.ITransactionManager
+	holdAmount  int64
+	timeout     time.Duration
+}
+
+func NewPaymentService(provider payment.PaymentProvider, paymentRepo repository.IRidePaymentRepository, rideRepo repository.IRideRepository,
+	wallet *WalletService, txManager repository.ITransactionManager, holdAmount int64, timeout time.Duration) *PaymentService {
+	return &PaymentService{
+		provider:    provider,
+		paymentRepo: paymentRepo,
+		rideRepo:    rideRepo,
+		wallet:      wallet,
+		txManager:   txManager,
+		holdAmount:  holdAmount,
+		timeout:     timeout,
+	}
+}
+
+// EnsureNoUnpaidRides ödemesi alınamamış sürüşü olan kullanıcının yeni sürüş başlatmasını engeller
+func (s *PaymentService) EnsureNoUnpaidRides(ctx context.Context, userID int64) error {
+	unpaid, err := s.rideRepo.HasPaymentFailedByUserID(ctx, userID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if unpaid {
+		return errorx.WrapMsg(errorx.ErrForbidden, "Ödemesi alınamamış bir sürüşünüz var. Yeni sürüş başlatmadan önce ödemeyi tamamlayın")
+	}
+	return nil
+}
+
+// Hold yeni başlayan sürüş için karttan provizyon alır ve ödeme kaydını oluşturur
+func (s *PaymentService) Hold(ctx context.Context, ride *model.Ride) (*model.RidePayment, error) {
+	currency := ride.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	auth, err := s.authorize(ctx, payment.AuthorizeRequest{
+		Amount:      s.holdAmount,
+		Currency:    currency,
+		CustomerID:  fmt.Sprintf("user-%d", ride.UserID),
+		Reference:   fmt.Sprintf("ride-%d", ride.ID),
+		Description: "Sürüş provizyonu",
+	})
+	if err != nil {
+		return nil, providerError(err, "Kartınızdan provizyon alınamadı")
+	}
+
+	ridePayment := &model.RidePayment{
+		RideID:           ride.ID,
+		UserID:           ride.UserID,
+		Provider:         s.provider.Name(),
+		AuthorizationID:  auth.ID,
+		AuthorizedAmount: auth.Amount,
+		Currency:         currency,
+		Status:           model.PaymentAuthorized,
+	}
+	if err = s.paymentRepo.Create(ctx, ridePayment); err != nil {
+		s.void(ctx, auth.ID)
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Ödeme kaydı oluşturulamadı")
+	}
+	return ridePayment, nil
+}
+
+// Release başlatılamayan sürüşün provizyonunu kaldırır
+func (s *PaymentService) Release(ctx context.Context, rideID int64) error {
+	ridePayment, err := s.paymentRepo.GetByRideID(ctx, rideID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ridePayment == nil || ridePayment.Status != model.PaymentAuthorized {
+		return nil
+	}
+
+	if err = s.withTimeout(ctx, func(ctx context.Context) error {
+		return s.provider.Void(ctx, ridePayment.AuthorizationID)
+	}); err != nil {
+		return providerError(err, "Provizyon kaldırılamadı")
+	}
+	ridePayment.Status = model.PaymentVoided
+	if err = s.paymentRepo.Update(ctx, ridePayment); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// Settle bitirilen ve ücreti cüzdandan düşülen sürüşün ödemesini kapatır. Cüzdan ücreti karşıladıysa provizyon kaldırılır;
+// karşılamadıysa eksik kalan tutar karttan tahsil edilir. Provizyon eksik tutara yetmiyorsa kaldırılıp eksik tutar için
+// yeni provizyon alınır. Ödeme alınınca sürüş completed olur; sağlayıcı reddederse veya cevap vermezse sürüş payment_failed
+// durumuna geçer, bu durumda hata dönmez. Karttan çekilen tutar kaydedilemezse tahsilat iade edilir ve sürüş yine payment_failed olur.
+func (s *PaymentService) Settle(ctx context.Context, ride *model.Ride) error {
+	ridePayment, err := s.paymentRepo.GetByRideID(ctx, ride.ID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return s.settle(ctx, ride, ridePayment, "")
+}
+
+// settle Settle'ın ödeme kaydı okunduktan sonraki kısmıdır. reference boş değilse yeni provizyon bu referansla alınır.
+func (s *PaymentService) settle(ctx context.Context, ride *model.Ride, ridePayment *model.RidePayment, reference string) error {
+	wallet, err := s.wallet.GetWallet(ctx, ride.UserID)
+	if err != nil {
+		return err
+	}
+	var due int64
+	if wallet.Balance < 0 {
+		due = min(-wallet.Balance, ride.Cost)
+	}
+
+	if due == 0 {
+		if ridePayment != nil && (ridePayment.Status == model.PaymentAuthorized || ridePayment.Status == model.PaymentSettling) {
+			if ridePayment.Status == model.PaymentAuthorized {
+				s.void(ctx, ridePayment.AuthorizationID)
+			}
+			ridePayment.Status = model.PaymentVoided
+			if err = s.paymentRepo.Update(ctx, ridePayment); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+		return s.markRide(ctx, ride.ID, model.RideCompleted, "")
+	}
+
+	capture, auth, err := s.capture(ctx, ride, ridePayment, due, reference)
+	if err != nil {
+		return s.fail(ctx, ride, ridePayment, err)
+	}
+
+	// Kayıt transaction'ı geri alınırsa ödeme kaydının ilk hali fail ile işaretlenebilsin diye kopyası üzerinde çalışılır
+	recorded := &model.RidePayment{RideID: ride.ID, UserID: ride.UserID, Provider: s.provider.Name()}
+	if ridePayment != nil {
+		copied := *ridePayment
+		recorded = &copied
+	}
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		recorded.AuthorizationID = auth.ID
+		recorded.AuthorizedAmount = auth.Amount
+		recorded.Currency = wallet.Currency
+		recorded.CaptureID = capture.ID
+		recorded.CapturedAmount = capture.Amount
+		recorded.Status = model.PaymentCaptured
+		recorded.FailureReason = ""
+		if err := s.savePayment(ctx, recorded); err != nil {
+			return err
+		}
+
+		if err := s.wallet.RecordCardPayment(ctx, ride.UserID, ride.ID, capture.Amount, wallet.Currency); err != nil {
+			return err
+		}
+		return s.markRide(ctx, ride.ID, model.RideCompleted, "")
+	})
+	if err != nil {
+		// Para karttan çekildi ama kaydedilemedi; tahsilat iade edilir ve sürüş tekrar denenmek üzere payment_failed olur.
+		// Provizyon tahsilatta kullanıldığı için fail tarafından kaldırılmaz.
+		logger.Error("Karttan tahsil edilen ödeme kaydedilemedi, tahsilat iade ediliyor (ride_id=%d, capture_id=%s): %v", ride.ID, capture.ID, err)
+		s.RefundCard(ctx, capture)
+		if ridePayment != nil {
+			ridePayment.Status = model.PaymentFailed
+		}
+		return s.fail(ctx, ride, ridePayment, err)
+	}
+	return nil
+}
+
+// FailSettlement ödemesi kapatılamadan ending durumunda kalan sürüşü payment_failed durumuna geçirir; kullanıcı ödemeyi
+// RetryRide ile tekrar dener. Açık provizyon kaldırılır. Sürüş bu arada ending durumundan çıktıysa bir şey yapmaz.
+func (s *PaymentService) FailSettlement(ctx context.Context, rideID int64, cause error) error {
+	ridePayment, err := s.paymentRepo.GetByRideID(ctx, rideID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ridePayment != nil && ridePayment.Status == model.PaymentAuthorized {
+		s.void(ctx, ridePayment.AuthorizationID)
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ride, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Sürüş bulunamadı")
+		}
+		if ride.Status != model.RideEnding {
+			return nil
+		}
+
+		ridePayment, err := s.paymentRepo.GetByRideID(ctx, rideID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if ridePayment != nil {
+			ridePayment.Status = model.PaymentFailed
+			ridePayment.FailureReason = cause.Error()
+			if err = s.paymentRepo.Update(ctx, ridePayment); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+		return transitionRide(ctx, s.rideRepo, ride, model.RidePaymentFailed, nil, "Ödeme kapatılamadı: "+cause.Error())
+	})
+}
+
+// FailStuckSettlements ödemesi kapatılamadan Settle'ın sağlayıcı zaman aşımlarından uzun süredir ending durumunda kalan
+// sürüşleri payment_failed durumuna geçirir. İşlenemeyen sürüş loglanır ve diğerlerine devam edilir.
+func (s *PaymentService) FailStuckSettlements(ctx context.Context, now time.Time) (int, error) {
+	rides, err := s.rideRepo.ListEndingBefore(ctx, now.Add(-s.settleTimeout()), stuckSettlementBatchSize)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	failed := 0
+	for _, ride := range rides {
+		if err = s.FailSettlement(ctx, ride.ID, errSettlementStuck); err != nil {
+			logger.Error("Ödemesi yarıda kalan sürüş payment_failed durumuna geçirilemedi (ride_id=%d): %v", ride.ID, err)
+			continue
+		}
+		failed++
+	}
+	return failed, nil
+}
+
+// RunSettlementWorker ctx iptal edilene kadar belirtilen aralıklarla ödemesi yarıda kalan sürüşleri payment_failed durumuna geçirir
+func (s *PaymentService) RunSettlementWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.FailStuckSettlements(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Error("Yarıda kalan ödeme kontrolü hatası: %v", err)
+				continue
+			}
+			if count > 0 {
+				logger.Info("Ödemesi yarıda kalan %d sürüş payment_failed durumuna geçirildi", count)
+			}
+		}
+	}
+}
+
+// RetryRide ödemesi alınamamış sürüşün ödemesini tekrar dener. Sağlayıcıya gitmeden önce sürüş satırı kilitlenir ve
+// ödeme kaydı settling durumuna alınır; böylece aynı sürüş için eş zamanlı ikinci deneme karttan tekrar çekim yapamaz.
+// Her deneme sağlayıcıya ride-<id>-retry-<n> referansıyla gider, aynı deneme tekrar gönderilirse yeni provizyon açılmaz.
+// Yarıda kalmış (settlingClaimTimeout'tan eski) deneme aynı referansla devralınır.
+func (s *PaymentService) RetryRide(ctx context.Context, rideID, userID int64) (*model.Ride, error) {
+	var ride *model.Ride
+	var ridePayment *model.RidePayment
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		ride, err = s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if ride.UserID != userID {
+			return errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+		}
+		if ride.Status != model.RidePaymentFailed {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüşün bekleyen bir ödemesi yok")
+		}
+
+		ridePayment, err = s.paymentRepo.GetByRideID(ctx, rideID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if ridePayment == nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Sürüşün ödeme kaydı bulunamadı")
+		}
+
+		now := time.Now().UTC()
+		switch {
+		case ridePayment.Status != model.PaymentSettling:
+			ridePayment.RetryCount++
+		case now.Sub(ridePayment.UpdatedAt) < settlingClaimTimeout:
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Bu sürüşün ödemesi şu anda deneniyor")
+		}
+		ridePayment.Status = model.PaymentSettling
+		ridePayment.UpdatedAt = now
+		return s.savePayment(ctx, ridePayment)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	if err = s.settle(ctx, ride, ridePayment, fmt.Sprintf("ride-%d-retry-%d", ride.ID, ridePayment.RetryCount)); err != nil {
+		return nil, err
+	}
+
+	updatedRide, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if updatedRide.Status == model.RidePaymentFailed {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Ödeme alınamadı, lütfen daha sonra tekrar deneyin")
+	}
+	return updatedRide, nil
+}
+
+// HandleWebhook sağlayıcıdan gelen imzalı olayı işler. Tahsilatın sonradan reddedilmesi durumunda cüzdana yazılan
+// kart yüklemesi geri alınır ve sürüş payment_failed olur. Bilinmeyen veya zaten işlenmiş olaylar yok sayılır.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return errorx.WrapMsg(errorx.ErrUnauthorized, "Webhook imzası geçersiz")
+		}
+		return errorx.Wrap(errorx.ErrInvalidRequest, err, "Webhook içeriği geçersiz")
+	}
+	if event.Type != payment.EventCaptureFailed {
+		return nil
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ridePayment, err := s.paymentRepo.GetByAuthorizationID(ctx, s.provider.Name(), event.AuthorizationID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if ridePayment == nil || ridePayment.Status != model.PaymentCaptured {
+			return nil
+		}
+
+		if err = s.wallet.ReverseCardPayment(ctx, ridePayment.UserID, ridePayment.RideID, ridePayment.CapturedAmount, ridePayment.Currency); err != nil {
+			return err
+		}
+		ridePayment.Status = model.PaymentFailed
+		ridePayment.FailureReason = event.Reason
+		if err = s.paymentRepo.Update(ctx, ridePayment); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return s.markRide(ctx, ridePayment.RideID, model.RidePaymentFailed, "Kart tahsilatı sağlayıcı tarafından reddedildi: "+event.Reason)
+	})
+	if err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// ChargeCard sürüşe bağlı olmayan bir tutarı (örn: abonelik) karttan provizyon alıp hemen tahsil ederek çeker.
+// Aynı referansla tekrar çağrılırsa sağlayıcı yeni provizyon açmaz.
+func (s *PaymentService) ChargeCard(ctx context.Context, userID, amount int64, currency, reference, description string) (*payment.Capture, error) {
+	auth, err := s.authorize(ctx, payment.AuthorizeRequest{
+		Amount:      amount,
+		Currency:    currency,
+		CustomerID:  fmt.Sprintf("user-%d", userID),
+		Reference:   reference,
+		Description: description,
+	})
+	if err != nil {
+		return nil, providerError(err, "Karttan ödeme alınamadı")
+	}
+
+	var capture *payment.Capture
+	err = s.withTimeout(ctx, func(ctx context.Context) error {
+		var err error
+		capture, err = s.provider.Capture(ctx, auth.ID, amount)
+		return err
+	})
+	if err != nil {
+		s.void(ctx, auth.ID)
+		return nil, providerError(err, "Karttan ödeme alınamadı")
+	}
+	return capture, nil
+}
+
+// RefundCard kaydedilemeyen kart tahsilatını iade eder. İade edilemeyen tahsilat elle düzeltilmek üzere loglanır.
+func (s *PaymentService) RefundCard(ctx context.Context, capture *payment.Capture) {
+	err := s.withTimeout(ctx, func(ctx context.Context) error {
+		_, err := s.provider.Refund(ctx, capture.ID, capture.Amount)
+		return err
+	})
+	if err != nil {
+		logger.Error("Kart tahsilatı iade edilemedi (capture_id=%s, amount=%d): %v", capture.ID, capture.Amount, err)
+	}
+}
+
+// CheckRefund sürüşte karttan tahsil edilen tutardan amount kadarının karta iade edilebileceğini doğrular. refunded aynı
+// tahsilattan daha önce iade edilen tutardır; toplam iade tahsil edilen tutarı aşamaz.
+func (s *PaymentService) CheckRefund(ctx context.Context, rideID, amount, refunded int64) error {
+	ridePayment, err := s.paymentRepo.GetByRideID(ctx, rideID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ridePayment == nil || ridePayment.Status != model.PaymentCaptured {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüşte karttan tahsilat yapılmadı, iade cüzdana yapılabilir")
+	}
+	if refundable := ridePayment.CapturedAmount - refunded; amount > refundable {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Karta en fazla "+money.Format(max(refundable, 0), ridePayment.Currency)+" iade edilebilir")
+	}
+	return nil
+}
+
+// RefundRide sürüşte karttan tahsil edilen tutarın bir kısmını sağlayıcıda karta iade eder. Tutar önceden CheckRefund
+// ile doğrulanmış olmalıdır.
+func (s *PaymentService) RefundRide(ctx context.Context, rideID, amount int64) (*payment.Refund, error) {
+	ridePayment, err := s.paymentRepo.GetByRideID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ridePayment == nil || ridePayment.Status != model.PaymentCaptured {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu sürüşte karttan tahsilat yapılmadı")
+	}
+
+	var refund *payment.Refund
+	err = s.withTimeout(ctx, func(ctx context.Context) error {
+		var err error
+		refund, err = s.provider.Refund(ctx, ridePayment.CaptureID, amount)
+		return err
+	})
+	if err != nil {
+		return nil, providerError(err, "Karta iade yapılamadı")
+	}
+	return refund, nil
+}
+
+// capture eksik tutarı tahsil eder. Açık provizyon yetiyorsa o kullanılır, yetmiyorsa veya yoksa yeni provizyon alınır.
+func (s *PaymentService) capture(ctx context.Context, ride *model.Ride, ridePayment *model.RidePayment, due int64, reference string) (*payment.Capture, *payment.Authorization, error) {
+	var auth *payment.Authorization
+	if ridePayment != nil && ridePayment.Status == model.PaymentAuthorized {
+		if due <= ridePayment.AuthorizedAmount {
+			auth = &payment.Authorization{ID: ridePayment.AuthorizationID, Amount: ridePayment.AuthorizedAmount}
+		} else {
+			s.void(ctx, ridePayment.AuthorizationID)
+		}
+	}
+
+	created := false
+	if auth == nil {
+		var err error
+		auth, err = s.authorize(ctx, payment.AuthorizeRequest{
+			Amount:      due,
+			Currency:    ride.Currency,
+			CustomerID:  fmt.Sprintf("user-%d", ride.UserID),
+			Reference:   reference,
+			Description: "Sürüş ücreti",
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		created = true
+	}
+
+	var capture *payment.Capture
+	err := s.withTimeout(ctx, func(ctx context.Context) error {
+		var err error
+		capture, err = s.provider.Capture(ctx, auth.ID, due)
+		return err
+	})
+	if err != nil {
+		if created {
+			s.void(ctx, auth.ID)
+		}
+		return nil, nil, err
+	}
+	return capture, auth, nil
+}
+
+// fail tahsil edilemeyen sürüşü payment_failed olarak işaretler. Açık provizyon kaldırılır; kullanıcı ödemeyi tekrar denediğinde yeni provizyon alınır.
+func (s *PaymentService) fail(ctx context.Context, ride *model.Ride, ridePayment *model.RidePayment, cause error) error {
+	logger.Error("Sürüş ödemesi alınamadı (ride_id=%d): %v", ride.ID, cause)
+
+	if ridePayment != nil && ridePayment.Status == model.PaymentAuthorized {
+		s.void(ctx, ridePayment.AuthorizationID)
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if ridePayment != nil {
+			ridePayment.Status = model.PaymentFailed
+			ridePayment.FailureReason = cause.Error()
+			if err := s.paymentRepo.Update(ctx, ridePayment); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+		return s.markRide(ctx, ride.ID, model.RidePaymentFailed, "Kart tahsilatı yapılamadı: "+cause.Error())
+	})
+}
+
+func (s *PaymentService) savePayment(ctx context.Context, ridePayment *model.RidePayment) error {
+	var err error
+	if ridePayment.ID == 0 {
+		err = s.paymentRepo.Create(ctx, ridePayment)
+	} else {
+		err = s.paymentRepo.Update(ctx, ridePayment)
+	}
+	if err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Ödeme kaydı güncellenemedi")
+	}
+	return nil
+}
+
+// markRide sürüşü ödeme sonucuna göre completed veya payment_failed durumuna geçirir; sürüş zaten o durumdaysa bir şey yapmaz
+func (s *PaymentService) markRide(ctx context.Context, rideID int64, status model.RideStatus, note string) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ride, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Sürüş bulunamadı")
+		}
+		if ride.Status == status {
+			return nil
+		}
+		return transitionRide(ctx, s.rideRepo, ride, status, nil, note)
+	})
+}
+
+func (s *PaymentService) authorize(ctx context.Context, req payment.AuthorizeRequest) (*payment.Authorization, error) {
+	if req.Currency == "" {
+		req.Currency = money.DefaultCurrency
+	}
+	var auth *payment.Authorization
+	err := s.withTimeout(ctx, func(ctx context.Context) error {
+		var err error
+		auth, err = s.provider.Authorize(ctx, req)
+		return err
+	})
+	return auth, err
+}
+
+// void provizyonu kaldırır. Kaldırılamayan provizyon sağlayıcıda süresi dolunca kendiliğinden düşer, bu yüzden hata yalnızca loglanır.
+func (s *PaymentService) void(ctx context.Context, authorizationID string) {
+	err := s.withTimeout(ctx, func(ctx context.Context) error {
+		return s.provider.Void(ctx, authorizationID)
+	})
+	if err != nil {
+		logger.Error("Provizyon kaldırılamadı (authorization_id=%s): %v", authorizationID, err)
+	}
+}
+
+// settleTimeout Settle'ın en uzun sürebileceği süredir; sağlayıcı zaman aşımı yoksa settlingClaimTimeout kullanılır
+func (s *PaymentService) settleTimeout() time.Duration {
+	if s.timeout <= 0 {
+		return settlingClaimTimeout
+	}
+	return s.timeout * settleProviderCalls
+}
+
+func (s *PaymentService) withTimeout(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// providerError sağlayıcı hatasını kullanıcıya dönecek hataya çevirir
+func providerError(err error, message string) *errorx.AppError {
+	switch {
+	case errors.Is(err, payment.ErrDeclined):
+		return errorx.Wrap(errorx.ErrInvalidRequest, err, message+": ödeme reddedildi")
+	case errors.Is(err, payment.ErrTimeout):
+		return errorx.Wrap(errorx.ErrInternal, err, message+": ödeme sağlayıcısı cevap vermedi")
+	default:
+		return errorx.Wrap(errorx.ErrInternal, err, message)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+)
+
+// PromotionService kampanya kodlarını yönetir. Kullanıcı kodu devam eden sürüşüne ekler; indirim sürüş
+// bitirilirken fiyat dökümüne satır olarak eklenir ve kullanım aynı transaction içinde kaydedilir.
+type PromotionService struct {
+	promotionRepo repository.IPromotionRepository
+	rideRepo      repository.IRideRepository
+	motorRepo     repository.IMotorbikeRepository
+	audit         Auditor
+}
+
+func NewPromotionService(promotionRepo repository.IPromotionRepository, rideRepo repository.IRideRepository, motorRepo repository.IMotorbikeRepository, audit Auditor) *PromotionService {
+	return &PromotionService{promotionRepo: promotionRepo, rideRepo: rideRepo, motorRepo: motorRepo, audit: audit}
+}
+
+func (s *PromotionService) Create(ctx context.Context, promotion *model.Promotion) error {
+	if err := s.validate(ctx, promotion); err != nil {
+		return err
+	}
+	if err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "promotions.create", "promotions", promotion.ID, nil, promotion)
+	return nil
+}
+
+func (s *PromotionService) GetByID(ctx context.Context, id int64) (*model.Promotion, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kampanya bulunamadı")
+	}
+	return promotion, nil
+}
+
+func (s *PromotionService) Update(ctx context.Context, promotion model.Promotion) error {
+	if err := s.validate(ctx, &promotion); err != nil {
+		return err
+	}
+	current, err := s.GetByID(ctx, promotion.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.promotionRepo.Update(ctx, &promotion); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "promotions.update", "promotions", promotion.ID, current, promotion)
+	return nil
+}
+
+func (s *PromotionService) Delete(ctx context.Context, id int64) error {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.promotionRepo.Delete(ctx, id); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "promotions.delete", "promotions", id, current, nil)
+	return nil
+}
+
+func (s *PromotionService) List(ctx context.Context) ([]model.Promotion, error) {
+	promotions, err := s.promotionRepo.List(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return promotions, nil
+}
+
+// UsageReport tüm kampanyaların kullanım özetini getirir
+func (s *PromotionService) UsageReport(ctx context.Context) ([]model.PromotionUsage, error) {
+	usage, err := s.promotionRepo.UsageReport(ctx, 0)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return usage, nil
+}
+
+// Usage kampanyanın kullanım özetini ve kullanımlarını sayfalı getirir
+func (s *PromotionService) Usage(ctx context.Context, id int64, pagination *query.Pagination) (*model.PromotionUsage, []model.PromotionRedemption, error) {
+	usage, err := s.promotionRepo.UsageReport(ctx, id)
+	if err != nil {
+		return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if len(usage) == 0 {
+		return nil, nil, errorx.WrapMsg(errorx.ErrNotFound, "Kampanya bulunamadı")
+	}
+
+	redemptions, err := s.promotionRepo.ListRedemptions(ctx, id, pagination)
+	if err != nil {
+		return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return &usage[0], redemptions, nil
+}
+
+// ApplyToRide kampanya kodunu kullanıcının devam eden sürüşüne ekler. Kod sürüş bitirilirken tekrar kontrol edilir;
+// o anda geçerliliğini yitirmişse sürüş indirimsiz ücretlendirilir.
+func (s *PromotionService) ApplyToRide(ctx context.Context, rideID, userID int64, code string) (*model.Promotion, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.UserID != userID {
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+	}
+	if !ride.Status.IsOngoing() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş bitirilmiş, kampanya kodu eklenemez")
+	}
+
+	promotion, err := s.promotionRepo.GetByCode(ctx, model.NormalizePromotionCode(code))
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if promotion == nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kampanya kodu bulunamadı")
+	}
+
+	motorbike, err := s.motorRepo.GetByID(ctx, ride.MotorbikeID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+	}
+	if err = s.checkEligibility(ctx, promotion, userID, motorbike.Model, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err = s.rideRepo.SetPromotion(ctx, ride.ID, &promotion.ID); err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return promotion, nil
+}
+
+// ApplyDiscount sürüşe eklenmiş kampanya kodunun indirimini fiyat dökümüne ekler ve kullanımı kaydeder.
+// FinishRide transaction'ı içinde, ücret kesinleşmeden önce çağrılır. Kod artık geçerli değilse indirim uygulanmaz.
+func (s *PromotionService) ApplyDiscount(ctx context.Context, ride *model.Ride, motorbikeModel string, breakdown *model.RidePriceBreakdown, now time.Time) error {
+	if ride.PromotionID == nil {
+		return nil
+	}
+
+	promotion, err := s.promotionRepo.GetByIDForUpdate(ctx, *ride.PromotionID)
+	if err != nil {
+		logger.Info("Sürüşe eklenen kampanya bulunamadı, indirim uygulanmadı (ride_id=%d): %v", ride.ID, err)
+		return nil
+	}
+
+	discount, err := s.discountFor(ctx, promotion, ride.UserID, motorbikeModel, breakdown, now)
+	if err != nil {
+		logger.Info("Kampanya kodu uygulanmadı (ride_id=%d, code=%s): %v", ride.ID, promotion.Code, err)
+		return nil
+	}
+	if discount == 0 {
+		return nil
+	}
+
+	breakdown.AddLine(promotionLine(promotion, discount))
+	redemption := &model.PromotionRedemption{
+		PromotionID: promotion.ID,
+		UserID:      ride.UserID,
+		RideID:      ride.ID,
+		Amount:      discount,
+		Currency:    breakdown.Currency,
+	}
+	if err = s.promotionRepo.CreateRedemption(ctx, redemption); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Kampanya kullanımı kaydedilemedi")
+	}
+	if err = s.promotionRepo.IncrementRedemptionCount(ctx, promotion.ID); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Kampanya kullanımı kaydedilemedi")
+	}
+	return nil
+}
+
+// PreviewDiscount devam eden sürüşün anlık ücretine kampanya indirimini kayıt oluşturmadan ekler
+func (s *PromotionService) PreviewDiscount(ctx context.Context, ride *model.Ride, motorbikeModel string, breakdown *model.RidePriceBreakdown, now time.Time) {
+	if ride.PromotionID == nil {
+		return
+	}
+	promotion, err := s.promotionRepo.GetByID(ctx, *ride.PromotionID)
+	if err != nil {
+		return
+	}
+	if discount, err := s.discountFor(ctx, promotion, ride.UserID, motorbikeModel, breakdown, now); err == nil && discount > 0 {
+		breakdown.AddLine(promotionLine(promotion, discount))
+	}
+}
+
+func (s *PromotionService) discountFor(ctx context.Context, promotion *model.Promotion, userID int64, motorbikeModel string, breakdown *model.RidePriceBreakdown, now time.Time) (int64, error) {
+	if err := s.checkEligibility(ctx, promotion, userID, motorbikeModel, now); err != nil {
+		return 0, err
+	}
+	if promotion.Currency != breakdown.Currency {
+		return 0, errorx.WrapMsg(errorx.ErrInvalidRequest, "Kampanya para birimi tarifeyle uyuşmuyor")
+	}
+	return promotion.Discount(breakdown.Total), nil
+}
+
+// checkEligibility kampanyanın geçerlilik aralığını, model kısıtını ve kullanım limitlerini kontrol eder
+func (s *PromotionService) checkEligibility(ctx context.Context, promotion *model.Promotion, userID int64, motorbikeModel string, now time.Time) error {
+	if !promotion.IsValidAt(now) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kampanya kodu geçerli değil veya süresi dolmuş")
+	}
+	if !promotion.AppliesToModel(motorbikeModel) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kampanya kodu bu motor modelinde geçerli değil")
+	}
+	if promotion.TotalLimit > 0 && promotion.RedemptionCount >= promotion.TotalLimit {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kampanya kodunun kullanım limiti doldu")
+	}
+
+	if promotion.PerUserLimit > 0 {
+		used, err := s.promotionRepo.CountUserRedemptions(ctx, promotion.ID, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if used >= promotion.PerUserLimit {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu kampanya kodunu kullanım hakkınız doldu")
+		}
+	}
+
+	if promotion.FirstRideOnly {
+		completed, err := s.rideRepo.CountCompletedByUserID(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if completed > 0 {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu kampanya kodu yalnızca ilk sürüşte geçerli")
+		}
+	}
+	return nil
+}
+
+// validate kodu normalize eder ve başka bir kampanyada kullanılmadığını kontrol eder
+func (s *PromotionService) validate(ctx context.Context, promotion *model.Promotion) error {
+	promotion.Code = model.NormalizePromotionCode(promotion.Code)
+	if promotion.Code == "" {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kampanya kodu boş olamaz")
+	}
+	if promotion.DiscountType == model.DiscountPercentage && promotion.DiscountValue > 100 {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Yüzde indirim 100'den büyük olamaz")
+	}
+	if promotion.ValidFrom != nil && promotion.ValidUntil != nil && !promotion.ValidUntil.After(*promotion.ValidFrom) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bitiş zamanı başlangıç zamanından sonra olmalı")
+	}
+
+	existing, err := s.promotionRepo.GetByCode(ctx, promotion.Code)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if existing != nil && existing.ID != promotion.ID {
+		return errorx.WrapMsg(errorx.ErrDuplicate, "Bu kampanya kodu zaten kullanımda")
+	}
+	return nil
+}
+
+func promotionLine(promotion *model.Promotion, discount int64) model.PriceLine {
+	return model.PriceLine{
+		Code:        model.PriceLinePromotion,
+		Description: "Kampanya kodu: " + promotion.Code,
+		Amount:      -discount,
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+)
+
+// PermissionChecker rolün bir yetkiye sahip olup olmadığını söyler; görev atanacak personeli ve başkasına ait kayıtlara erişimi doğrulamak için kullanılır
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error)
+}
+
+// authorizeOwnerOrPermission kaynak isteği yapan kullanıcıya ait değilse rolün yetkiye sahip olmasını ister
+func authorizeOwnerOrPermission(ctx context.Context, permissions PermissionChecker, role model.Role, permission model.Permission, ownerID, userID int64, message string) error {
+	if ownerID == userID {
+		return nil
+	}
+	ok, err := permissions.HasPermission(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorx.WrapMsg(errorx.ErrForbidden, message)
+	}
+	return nil
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type RBACService struct {
+	roleRepo  repository.IRoleRepository
+	txManager repository.ITransactionManager
+	audit     Auditor
+}
+
+func NewRBACService(roleRepo repository.IRoleRepository, txManager repository.ITransactionManager, audit Auditor) *RBACService {
+	return &RBACService{roleRepo: roleRepo, txManager: txManager, audit: audit}
+}
+
+// HasPermission rolün yetkiye sahip olup olmadığını döner. Rol yetkileri Redis'te önbelleklenir.
+func (s *RBACService) HasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error) {
+	if role.IsSuperuser() {
+		return true, nil
+	}
+	permissions, err := s.roleRepo.GetRolePermissions(ctx, role)
+	if err != nil {
+		return false, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// Authorize rol yetkiye sahip değilse ErrForbidden döner
+func (s *RBACService) Authorize(ctx context.Context, role model.Role, permission model.Permission) error {
+	ok, err := s.HasPermission(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorx.WrapMsg(errorx.ErrForbidden, fmt.Sprintf("Bu işlem için %s yetkisi gerekli", permission))
+	}
+	return nil
+}
+
+// ValidateRole kullanıcıya atanacak rolün tanımlı olduğunu kontrol eder
+func (s *RBACService) ValidateRole(ctx context.Context, role model.Role) error {
+	if _, err := s.roleRepo.GetRole(ctx, role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz rol")
+		}
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+func (s *RBACService) ListPermissions(ctx context.Context) ([]model.PermissionDefinition, error) {
+	permissions, err := s.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return permissions, nil
+}
+
+func (s *RBACService) ListRoles(ctx context.Context) ([]model.RoleDefinition, error) {
+	roles, err := s.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	for i := range roles {
+		if roles[i].Name.IsSuperuser() {
+			if roles[i].Permissions, err = s.allPermissions(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return roles, nil
+}
+
+func (s *RBACService) GetRole(ctx context.Context, name model.Role) (*model.RoleDefinition, error) {
+	role, err := s.roleRepo.GetRole(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WrapMsg(errorx.ErrNotFound, "Rol bulunamadı")
+		}
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if role.Name.IsSuperuser() {
+		if role.Permissions, err = s.allPermissions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return role, nil
+}
+
+// CreateRole yeni bir rolü verilen yetkilerle oluşturur
+func (s *RBACService) CreateRole(ctx context.Context, role *model.RoleDefinition) error {
+	if !roleNamePattern.MatchString(string(role.Name)) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Rol adı küçük harfle başlamalı; yalnızca küçük harf, rakam ve alt çizgi içerebilir")
+	}
+	permissions, err := s.checkPermissions(ctx, role.Permissions)
+	if err != nil {
+		return err
+	}
+	role.Permissions = permissions
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := s.roleRepo.GetRole(ctx, role.Name)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if existing != nil {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Bu isimde bir rol zaten var")
+		}
+		if err = s.roleRepo.CreateRole(ctx, role); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if err = s.roleRepo.SetRolePermissions(ctx, role.Name, role.Permissions); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.roleRepo.ClearPermissionCache(ctx, role.Name)
+	s.audit.RecordChange(ctx, "roles.create", "roles", role.Name, nil, role)
+	return nil
+}
+
+// UpdateRole rolün açıklamasını günceller ve yetkilerini verilen liste ile değiştirir
+func (s *RBACService) UpdateRole(ctx context.Context, role *model.RoleDefinition) error {
+	if role.Name.IsSuperuser() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "admin rolü tüm yetkilere sahiptir, değiştirilemez")
+	}
+	permissions, err := s.checkPermissions(ctx, role.Permissions)
+	if err != nil {
+		return err
+	}
+	role.Permissions = permissions
+
+	var current *model.RoleDefinition
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if current, err = s.GetRole(ctx, role.Name); err != nil {
+			return err
+		}
+		if err := s.roleRepo.UpdateRole(ctx, role); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if err := s.roleRepo.SetRolePermissions(ctx, role.Name, role.Permissions); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Cache commit'ten sonra temizlenir; aksi halde eş zamanlı bir istek eski yetkileri tekrar yazabilir
+	s.roleRepo.ClearPermissionCache(ctx, role.Name)
+	s.audit.RecordChange(ctx, "roles.update", "roles", role.Name, current, role)
+	return nil
+}
+
+// DeleteRole kullanıcısı olmayan, sonradan tanımlanmış bir rolü siler
+func (s *RBACService) DeleteRole(ctx context.Context, name model.Role) error {
+	if name.IsSystem() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sistem rolleri silinemez")
+	}
+
+	var current *model.RoleDefinition
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if current, err = s.GetRole(ctx, name); err != nil {
+			return err
+		}
+		count, err := s.roleRepo.CountUsers(ctx, name)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if count > 0 {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bu role sahip %d kullanıcı var, önce rolleri değiştirilmelidir", count))
+		}
+		if err = s.roleRepo.DeleteRole(ctx, name); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.roleRepo.ClearPermissionCache(ctx, name)
+	s.audit.RecordChange(ctx, "roles.delete", "roles", name, current, nil)
+	return nil
+}
+
+// checkPermissions yetkilerin katalogda olduğunu kontrol eder, tekrarları kaldırıp sıralı döner
+func (s *RBACService) checkPermissions(ctx context.Context, permissions []model.Permission) ([]model.Permission, error) {
+	known, err := s.allPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(known, permission) {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bilinmeyen yetki: %s", permission))
+		}
+	}
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+	return slices.Compact(permissions), nil
+}
+
+func (s *RBACService) allPermissions(ctx context.Context) ([]model.Permission, error) {
+	definitions, err := s.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]model.Permission, len(definitions))
+	for i, definition := range definitions {
+		permissions[i] = definition.Name
+	}
+	return permissions, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+)
+
+// referralCodeAlphabet karışabilecek karakterler (0/O, 1/I) çıkarılmış kod alfabesi
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const referralCodeLength = 8
+
+// ReferralService davet kodlarını ve davet kredilerini yönetir. Davet edilen kullanıcı ilk sürüşünü tamamladığında
+// iki tarafın cüzdanına da kredi yüklenir.
+type ReferralService struct {
+	referralRepo   repository.IReferralRepository
+	wallet         *WalletService
+	referrerCredit int64
+	refereeCredit  int64
+}
+
+func NewReferralService(referralRepo repository.IReferralRepository, wallet *WalletService, referrerCredit, refereeCredit int64) *ReferralService {
+	return &ReferralService{
+		referralRepo:   referralRepo,
+		wallet:         wallet,
+		referrerCredit: referrerCredit,
+		refereeCredit:  refereeCredit,
+	}
+}
+
+// GetCode kullanıcının davet kodunu getirir, yoksa oluşturur
+func (s *ReferralService) GetCode(ctx context.Context, userID int64) (*model.ReferralCode, error) {
+	code, err := s.referralRepo.GetCodeByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if code != nil {
+		return code, nil
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		candidate, err := generateReferralCode()
+		if err != nil {
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		existing, err := s.referralRepo.GetCode(ctx, candidate)
+		if err != nil {
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if existing != nil {
+			continue
+		}
+
+		code = &model.ReferralCode{UserID: userID, Code: candidate}
+		if err = s.referralRepo.CreateCode(ctx, code); err != nil {
+			return nil, errorx.Wrap(errorx.ErrInternal, err, "Davet kodu oluşturulamadı")
+		}
+		return code, nil
+	}
+	return nil, errorx.WrapMsg(errorx.ErrInternal, "Davet kodu oluşturulamadı")
+}
+
+// Credits davet eden ve davet edilen kullanıcıya verilecek kredileri döner
+func (s *ReferralService) Credits() (referrer, referee int64) {
+	return s.referrerCredit, s.refereeCredit
+}
+
+// Stats kullanıcının davet ettiği kişi sayısını ve kazandığı krediyi getirir
+func (s *ReferralService) Stats(ctx context.Context, userID int64) (*model.ReferralStats, error) {
+	stats, err := s.referralRepo.Stats(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return stats, nil
+}
+
+// Summary tüm davetlerin özetini getirir
+func (s *ReferralService) Summary(ctx context.Context) (*model.ReferralStats, error) {
+	return s.Stats(ctx, 0)
+}
+
+// Resolve davet kodunun sahibini bulur
+func (s *ReferralService) Resolve(ctx context.Context, code string) (*model.ReferralCode, error) {
+	referralCode, err := s.referralRepo.GetCode(ctx, model.NormalizePromotionCode(code))
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if referralCode == nil {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Davet kodu geçersiz")
+	}
+	return referralCode, nil
+}
+
+// Link yeni kaydolan kullanıcıyı davet eden kullanıcıya bağlar. Kredi ilk sürüş tamamlanınca verilir.
+func (s *ReferralService) Link(ctx context.Context, referrerCode *model.ReferralCode, refereeID int64) error {
+	if referrerCode.UserID == refereeID {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kendi davet kodunuzu kullanamazsınız")
+	}
+
+	referral := &model.Referral{
+		ReferrerID:     referrerCode.UserID,
+		RefereeID:      refereeID,
+		Status:         model.ReferralPending,
+		ReferrerCredit: s.referrerCredit,
+		RefereeCredit:  s.refereeCredit,
+	}
+	if err := s.referralRepo.Create(ctx, referral); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// RewardFirstRide davet edilen kullanıcının ilk tamamlanan sürüşünde iki tarafa da kredi yükler.
+// FinishRide transaction'ı içinde çalışır; bekleyen davet yoksa bir şey yapmaz.
+func (s *ReferralService) RewardFirstRide(ctx context.Context, ride *model.Ride) error {
+	referral, err := s.referralRepo.GetPendingByRefereeIDForUpdate(ctx, ride.UserID)
+	if err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Davet bilgisi alınamadı")
+	}
+	if referral == nil {
+		return nil
+	}
+
+	rideID := ride.ID
+	if err = s.wallet.CreditPromotion(ctx, referral.RefereeID, referral.RefereeCredit, "Davet kredisi", &rideID); err != nil {
+		return err
+	}
+	if err = s.wallet.CreditPromotion(ctx, referral.ReferrerID, referral.ReferrerCredit, "Arkadaş davet kredisi", &rideID); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	referral.Status = model.ReferralRewarded
+	referral.RideID = &rideID
+	referral.RewardedAt = &now
+	if err = s.referralRepo.Update(ctx, referral); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Davet güncellenemedi")
+	}
+	return nil
+}
+
+func generateReferralCode() (string, error) {
+	buf := make([]byte, referralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return string(buf), nil
+}
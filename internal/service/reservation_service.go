@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+// Worker'ın tek turda işleyeceği en fazla rezervasyon sayısı
+const reservationExpiryBatchSize = 100
+
+type ReservationService struct {
+	reservationRepo repository.IReservationRepository
+	motorRepo       repository.IMotorbikeRepository
+	userRepo        repository.IUserRepository
+	rideRepo        repository.IRideRepository
+	txManager       repository.ITransactionManager
+	holdDuration    time.Duration
+	fee             int64
+	permissions     PermissionChecker
+	audit           Auditor
+}
+
+// ReservationServiceDeps ReservationService'in ihtiyaç duyduğu repository ve ayarlar
+type ReservationServiceDeps struct {
+	ReservationRepo repository.IReservationRepository
+	MotorbikeRepo   repository.IMotorbikeRepository
+	UserRepo        repository.IUserRepository
+	RideRepo        repository.IRideRepository
+	TxManager       repository.ITransactionManager
+	HoldDuration    time.Duration
+	Fee             int64
+	Permissions     PermissionChecker // başkasının rezervasyonunu iptal etmek için reservations.cancel gerekir
+	Audit           Auditor
+}
+
+func NewReservationService(deps ReservationServiceDeps) *ReservationService {
+	return &ReservationService{
+		reservationRepo: deps.ReservationRepo,
+		motorRepo:       deps.MotorbikeRepo,
+		userRepo:        deps.UserRepo,
+		rideRepo:        deps.RideRepo,
+		txManager:       deps.TxManager,
+		holdDuration:    deps.HoldDuration,
+		fee:             deps.Fee,
+		permissions:     deps.Permissions,
+		audit:           deps.Audit,
+	}
+}
+
+// Reserve motoru kullanıcı adına rezervasyon süresi boyunca tutar.
+// Kullanıcının aynı anda tek bir aktif rezervasyonu olabilir ve açık sürüşü varken rezervasyon yapamaz.
+func (s *ReservationService) Reserve(ctx context.Context, userID, motorbikeID int64) (*model.Reservation, error) {
+	var reservation *model.Reservation
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByIDForUpdate(ctx, userID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+		}
+		if user.Status != model.StatusActive {
+			return errorx.WrapMsg(errorx.ErrForbidden, "Hesabınız aktif değil. Rezervasyon yapamazsınız")
+		}
+
+		now := time.Now().UTC()
+
+		existing, err := s.reservationRepo.GetActiveByUserID(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if existing != nil && !existing.IsExpired(now) {
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Zaten aktif bir rezervasyonunuz var")
+		}
+
+		activeRide, err := s.rideRepo.GetActiveByUserID(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if activeRide != nil {
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Devam eden bir sürüşünüz varken rezervasyon yapamazsınız")
+		}
+
+		// Süresi dolmuş ama worker'ın henüz kapatmadığı rezervasyon yenisinin önünü tıkamasın
+		if existing != nil {
+			if err = releaseReservation(ctx, s.reservationRepo, s.motorRepo, existing, model.ReservationExpired, now); err != nil {
+				return err
+			}
+		}
+
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		if motorbike.Status == model.BikeReserved {
+			if _, err = expireMotorbikeReservation(ctx, s.reservationRepo, motorbike, now); err != nil {
+				return err
+			}
+		}
+		if motorbike.Status != model.BikeAvailable {
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Bu Motorbisiklet şu anda müsait değil!")
+		}
+
+		reservation = &model.Reservation{
+			UserID:      userID,
+			MotorbikeID: motorbikeID,
+			Status:      model.ReservationActive,
+			ExpiresAt:   now.Add(s.holdDuration),
+			Fee:         s.fee,
+			Currency:    money.DefaultCurrency,
+		}
+		if err = s.reservationRepo.Create(ctx, reservation); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		motorbike.Status = model.BikeReserved
+		if err = s.motorRepo.Update(ctx, motorbike); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	return reservation, nil
+}
+
+// Cancel aktif rezervasyonu iptal eder ve motoru tekrar müsait yapar. reservations.cancel yetkisi olan roller tüm rezervasyonları iptal edebilir.
+func (s *ReservationService) Cancel(ctx context.Context, reservationID, userID int64, role model.Role) error {
+	var before, after model.Reservation
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		reservation, err := s.reservationRepo.GetByIDForUpdate(ctx, reservationID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Rezervasyon bulunamadı")
+		}
+		if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermReservationsCancel, reservation.UserID, userID, "Bu rezervasyona erişim yetkiniz yok."); err != nil {
+			return err
+		}
+		if reservation.Status != model.ReservationActive {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Rezervasyon aktif değil")
+		}
+
+		before = *reservation
+		if err = releaseReservation(ctx, s.reservationRepo, s.motorRepo, reservation, model.ReservationCancelled, time.Now().UTC()); err != nil {
+			return err
+		}
+		after = *reservation
+		return nil
+	})
+	if err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "reservations.cancel", "reservations", reservationID, before, after)
+	return nil
+}
+
+// GetMyActive kullanıcının aktif rezervasyonunu getirir
+func (s *ReservationService) GetMyActive(ctx context.Context, userID int64) (*model.Reservation, error) {
+	reservation, err := s.reservationRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if reservation == nil || reservation.IsExpired(time.Now().UTC()) {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Aktif bir rezervasyonunuz yok")
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) ListByUserID(ctx context.Context, userID int64) ([]model.Reservation, error) {
+	reservations, err := s.reservationRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return reservations, nil
+}
+
+// ExpireDue süresi dolan rezervasyonları kapatır ve motorları serbest bırakır, kapatılan rezervasyon sayısını döner
+func (s *ReservationService) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.reservationRepo.ListExpired(ctx, now, reservationExpiryBatchSize)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	expired := 0
+	for _, item := range due {
+		err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+			// Bu arada sürüşe dönüşmüş veya iptal edilmiş olabilir
+			reservation, err := s.reservationRepo.GetByIDForUpdate(ctx, item.ID)
+			if err != nil {
+				return err
+			}
+			if reservation.Status != model.ReservationActive || !reservation.IsExpired(now) {
+				return nil
+			}
+
+			expired++
+			return releaseReservation(ctx, s.reservationRepo, s.motorRepo, reservation, model.ReservationExpired, now)
+		})
+		if err != nil {
+			return expired, errorx.FromError(errorx.ErrInternal, err)
+		}
+	}
+
+	return expired, nil
+}
+
+// RunExpiryWorker ctx iptal edilene kadar belirtilen aralıklarla süresi dolan rezervasyonları kapatır
+func (s *ReservationService) RunExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.ExpireDue(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Error("Rezervasyon süre kontrolü hatası: %v", err)
+				continue
+			}
+			if count > 0 {
+				logger.Info("%d rezervasyonun süresi doldu", count)
+			}
+		}
+	}
+}
+
+// releaseReservation rezervasyonu verilen durumla kapatır ve motor hâlâ rezerveyse müsait yapar
+func releaseReservation(ctx context.Context, reservationRepo repository.IReservationRepository, motorRepo repository.IMotorbikeRepository,
+	reservation *model.Reservation, status model.ReservationStatus, now time.Time) error {
+	reservation.Status = status
+	reservation.EndedAt = &now
+	if err := reservationRepo.Update(ctx, reservation); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	motorbike, err := motorRepo.GetByIDForUpdate(ctx, reservation.MotorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+	}
+	if motorbike.Status != model.BikeReserved {
+		return nil
+	}
+
+	motorbike.Status = model.BikeAvailable
+	if err = motorRepo.Update(ctx, motorbike); err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+	}
+	return nil
+}
+
+// expireMotorbikeReservation rezerve görünen motorun geçerli rezervasyonunu döner. Rezervasyonun süresi dolmuşsa
+// (veya hiç yoksa) rezervasyon kapatılır ve motor bellekte müsait olarak işaretlenir; kaydetmek çağırana kalır.
+func expireMotorbikeReservation(ctx context.Context, reservationRepo repository.IReservationRepository, motorbike *model.Motorbike, now time.Time) (*model.Reservation, error) {
+	reservation, err := reservationRepo.GetActiveByMotorbikeID(ctx, motorbike.ID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if reservation != nil && !reservation.IsExpired(now) {
+		return reservation, nil
+	}
+
+	if reservation != nil {
+		reservation.Status = model.ReservationExpired
+		reservation.EndedAt = &now
+		if err = reservationRepo.Update(ctx, reservation); err != nil {
+			return nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+	}
+	motorbike.Status = model.BikeAvailable
+	return nil, nil
+}
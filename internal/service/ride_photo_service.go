@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+)
+
+// Küçük resimlerin en uzun kenarı (piksel)
+const ridePhotoThumbSize = 320
+
+// RidePhotoService sürüş sonunda çekilen park fotoğraflarını doğrular ve saklar. Fotoğrafın türü içeriğinden
+// belirlenir, EXIF üst verileri silinir ve bir küçük resim üretilir; dosyalar BlobStore'a, kaydı ride_photos tablosuna yazılır.
+type RidePhotoService struct {
+	rideRepo    repository.IRideRepository
+	store       storage.BlobStore
+	maxBytes    int64
+	permissions PermissionChecker
+}
+
+func NewRidePhotoService(rideRepo repository.IRideRepository, store storage.BlobStore, maxBytes int64, permissions PermissionChecker) *RidePhotoService {
+	return &RidePhotoService{rideRepo: rideRepo, store: store, maxBytes: maxBytes, permissions: permissions}
+}
+
+// Upload kullanıcının sürüşüne park fotoğrafı ekler. Dosya adı istemciden alınmaz, rastgele üretilir.
+func (s *RidePhotoService) Upload(ctx context.Context, rideID, userID int64, data []byte) (*model.RidePhoto, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.UserID != userID {
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+	}
+	if ride.Status == model.RideCancelled {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "İptal edilen sürüşe fotoğraf eklenemez")
+	}
+
+	img, err := storage.ProcessImage(data, s.maxBytes, ridePhotoThumbSize)
+	switch {
+	case errors.Is(err, storage.ErrTooLarge):
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Fotoğraf en fazla %d MB olabilir", s.maxBytes>>20))
+	case errors.Is(err, storage.ErrInvalidImage):
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Fotoğraf JPEG veya PNG formatında olmalıdır")
+	case err != nil:
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	photo := &model.RidePhoto{
+		RideID:       rideID,
+		UserID:       userID,
+		Key:          fmt.Sprintf("rides/%d/%s%s", rideID, name, img.Ext),
+		ThumbnailKey: fmt.Sprintf("rides/%d/%s_thumb.jpg", rideID, name),
+		ContentType:  img.ContentType,
+		Size:         int64(len(img.Data)),
+		Width:        img.Width,
+		Height:       img.Height,
+	}
+
+	if err = s.store.Put(ctx, photo.Key, bytes.NewReader(img.Data), photo.Size, photo.ContentType); err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+	}
+	if err = s.store.Put(ctx, photo.ThumbnailKey, bytes.NewReader(img.Thumbnail), int64(len(img.Thumbnail)), "image/jpeg"); err != nil {
+		s.remove(ctx, photo.Key)
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+	}
+	if err = s.rideRepo.CreatePhoto(ctx, photo); err != nil {
+		s.remove(ctx, photo.Key, photo.ThumbnailKey)
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+	}
+	return photo, nil
+}
+
+// List sürüşün fotoğraflarını getirir. rides.read yetkisi olmayan kullanıcı yalnızca kendi sürüşünün fotoğraflarını görebilir.
+func (s *RidePhotoService) List(ctx context.Context, rideID, userID int64, role model.Role) ([]model.RidePhoto, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, ride.UserID, userID, "Bu sürüşe erişim yetkiniz yok."); err != nil {
+		return nil, err
+	}
+
+	photos, err := s.rideRepo.ListPhotos(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return photos, nil
+}
+
+// remove kaydı oluşturulamayan fotoğrafın dosyalarını siler, silinemeyenler loglanır
+func (s *RidePhotoService) remove(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error("Kaydedilemeyen fotoğraf dosyası silinemedi (%s): %v", key, err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -2,29 +2,298 @@ package service
 
 import (
 	"context"
-	"strconv"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
 	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
 )
 
+// Worker'ın tek turda bitireceği en fazla park modundaki sürüş sayısı
+const ridePauseBatchSize = 100
+
 type RideService struct {
-	rideRepo  repository.IRideRepository
-	motorRepo repository.IMotorbikeRepository
+	rideRepo        repository.IRideRepository
+	motorRepo       repository.IMotorbikeRepository
+	userRepo        repository.IUserRepository
+	connRepo        repository.IBluetoothConnectionRepository
+	tariffRepo      repository.ITariffRepository
+	reservationRepo repository.IReservationRepository
+	zoneRepo        repository.IZoneRepository
+	txManager       repository.ITransactionManager
+	fareCalculator  *FareCalculator
+	commands        DeviceCommander
+	locks           LockController
+	wallet          *WalletService
+	payments        *PaymentService
+	promotions      *PromotionService
+	referrals       *ReferralService
+	passes          *PassService
+	invoices        *InvoiceService
+	audit           Auditor
+	permissions     PermissionChecker
+	maxPause        time.Duration
+}
+
+// RideServiceDeps RideService'in ihtiyaç duyduğu repository ve yardımcılar
+type RideServiceDeps struct {
+	RideRepo        repository.IRideRepository
+	MotorbikeRepo   repository.IMotorbikeRepository
+	UserRepo        repository.IUserRepository
+	ConnectionRepo  repository.IBluetoothConnectionRepository
+	TariffRepo      repository.ITariffRepository
+	ReservationRepo repository.IReservationRepository
+	ZoneRepo        repository.IZoneRepository
+	TxManager       repository.ITransactionManager
+	FareCalculator  *FareCalculator
+	Commands        DeviceCommander
+	Locks           LockController
+	Wallet          *WalletService
+	Payments        *PaymentService
+	Promotions      *PromotionService
+	Referrals       *ReferralService
+	Passes          *PassService
+	Invoices        *InvoiceService
+	Audit           Auditor
+	Permissions     PermissionChecker // başkasına ait sürüşü görüntülemek için rides.read gerekir
+	MaxPause        time.Duration     // park modunda geçirilebilecek en uzun süre, dolunca sürüş otomatik bitirilir
 }
 
-func NewRideService(rideRepo repository.IRideRepository, motorRepo repository.IMotorbikeRepository) *RideService {
+func NewRideService(deps RideServiceDeps) *RideService {
 	return &RideService{
-		rideRepo:  rideRepo,
-		motorRepo: motorRepo,
+		rideRepo:        deps.RideRepo,
+		motorRepo:       deps.MotorbikeRepo,
+		userRepo:        deps.UserRepo,
+		connRepo:        deps.ConnectionRepo,
+		tariffRepo:      deps.TariffRepo,
+		reservationRepo: deps.ReservationRepo,
+		zoneRepo:        deps.ZoneRepo,
+		txManager:       deps.TxManager,
+		fareCalculator:  deps.FareCalculator,
+		commands:        deps.Commands,
+		locks:           deps.Locks,
+		wallet:          deps.Wallet,
+		payments:        deps.Payments,
+		promotions:      deps.Promotions,
+		referrals:       deps.Referrals,
+		passes:          deps.Passes,
+		invoices:        deps.Invoices,
+		audit:           deps.Audit,
+		permissions:     deps.Permissions,
+		maxPause:        deps.MaxPause,
 	}
 }
 
-func (s *RideService) Create(ctx context.Context, ride *model.Ride) error {
-	if err := s.rideRepo.Create(ctx, ride); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
+// StartRide sürüşü tek bir transaction içinde başlatır: kullanıcı ve motor satırları kilitlenir,
+// motor müsait değilse veya kullanıcının açık bir sürüşü varsa işlem reddedilir.
+// Motor kullanıcının kendi rezervasyonundaysa rezervasyon sürüşe dönüştürülür.
+// Başarılı olursa sürüş oluşturulur, motor kiralandı/kilitsiz olarak işaretlenir ve bluetooth bağlantı kaydı açılır.
+// Sürüş reserved durumunda oluşturulur. Ödemesi alınamamış sürüşü olan kullanıcı yeni sürüş başlatamaz. Transaction sonrasında
+// karttan provizyon alınır, LockController ile motora bağlanılır ve motorun kilidi açılır; hepsi başarılıysa sürüş active olur,
+// biri başarısız olursa sürüş iptal edilir.
+func (s *RideService) StartRide(ctx context.Context, userID, motorbikeID int64) (*model.Ride, error) {
+	var ride *model.Ride
+
+	if err := s.payments.EnsureNoUnpaidRides(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByIDForUpdate(ctx, userID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+		}
+		if user.Status != model.StatusActive {
+			return errorx.WrapMsg(errorx.ErrForbidden, "Hesabınız aktif değil. Sürüş başlatamazsınız")
+		}
+
+		activeRide, err := s.rideRepo.GetActiveByUserID(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if activeRide != nil {
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Devam eden bir sürüşünüz var. Yeni sürüş başlatmadan önce bitirin")
+		}
+
+		now := time.Now().UTC()
+
+		// Kullanıcı başka bir motoru rezerve etmişse o rezervasyon bırakılır
+		userReservation, err := s.reservationRepo.GetActiveByUserID(ctx, userID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if userReservation != nil && userReservation.MotorbikeID != motorbikeID {
+			if err = releaseReservation(ctx, s.reservationRepo, s.motorRepo, userReservation, model.ReservationCancelled, now); err != nil {
+				return err
+			}
+		}
+
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+
+		var reservation *model.Reservation
+		if motorbike.Status == model.BikeReserved {
+			reservation, err = expireMotorbikeReservation(ctx, s.reservationRepo, motorbike, now)
+			if err != nil {
+				return err
+			}
+			if reservation != nil && reservation.UserID != userID {
+				return errorx.WrapMsg(errorx.ErrDuplicate, "Bu Motorbisiklet başka bir kullanıcı için rezerve edildi!")
+			}
+			if reservation != nil {
+				motorbike.Status = model.BikeAvailable
+			}
+		}
+		if motorbike.Status != model.BikeAvailable {
+			return errorx.WrapMsg(errorx.ErrDuplicate, "Bu Motorbisiklet şu anda müsait değil!")
+		}
+
+		ride = &model.Ride{
+			UserID:      userID,
+			MotorbikeID: motorbikeID,
+			Status:      model.RideReserved,
+			StartTime:   now,
+		}
+		if err = s.rideRepo.Create(ctx, ride); err != nil {
+			// Satır kilitlerine rağmen eşzamanlı başlatılan sürüşü veritabanındaki unique indeksler yakalar
+			if errors.Is(err, repository.ErrDuplicate) {
+				return errorx.Wrap(errorx.ErrDuplicate, err, "Motorun veya kullanıcının devam eden bir sürüşü var!")
+			}
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if err = recordRideEvent(ctx, s.rideRepo, ride.ID, "", model.RideReserved, &userID, ""); err != nil {
+			return err
+		}
+
+		// Rezervasyon sürüşe dönüşür, ücreti sürüş bitince sürüş ücretine eklenir
+		if reservation != nil {
+			reservation.Status = model.ReservationConverted
+			reservation.RideID = &ride.ID
+			reservation.EndedAt = &now
+			if err = s.reservationRepo.Update(ctx, reservation); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+		}
+
+		motorbike.Status = model.BikeRented
+		motorbike.LockStatus = model.Unlocked
+		if err = s.motorRepo.Update(ctx, motorbike); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+		}
+
+		connection := &model.BluetoothConnection{
+			UserID:      userID,
+			MotorbikeID: motorbikeID,
+			ConnectedAt: now,
+		}
+		if err = s.connRepo.Create(ctx, connection); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	if _, err = s.payments.Hold(ctx, ride); err != nil {
+		if abortErr := s.abortStart(ctx, ride, false, "Karttan provizyon alınamadı"); abortErr != nil {
+			logger.Error("Provizyonu alınamayan sürüş geri alınamadı (ride_id=%d): %v", ride.ID, abortErr)
+		}
+		return nil, err
+	}
+
+	if err = s.locks.Connect(ctx, motorbikeID); err != nil {
+		if releaseErr := s.payments.Release(ctx, ride.ID); releaseErr != nil {
+			logger.Error("Bağlanılamayan motorun sürüş provizyonu kaldırılamadı (ride_id=%d): %v", ride.ID, releaseErr)
+		}
+		if abortErr := s.abortStart(ctx, ride, false, "Motor ile bağlantı kurulamadı"); abortErr != nil {
+			logger.Error("Bağlanılamayan motorun sürüşü geri alınamadı (ride_id=%d): %v", ride.ID, abortErr)
+		}
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	if err = s.locks.Unlock(ctx, motorbikeID); err != nil {
+		if releaseErr := s.payments.Release(ctx, ride.ID); releaseErr != nil {
+			logger.Error("Kilidi açılamayan sürüşün provizyonu kaldırılamadı (ride_id=%d): %v", ride.ID, releaseErr)
+		}
+		if abortErr := s.abortStart(ctx, ride, true, "Motorun kilidi açılamadı"); abortErr != nil {
+			logger.Error("Kilidi açılamayan sürüş geri alınamadı (ride_id=%d): %v", ride.ID, abortErr)
+		}
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.rideRepo.GetByIDForUpdate(ctx, ride.ID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if err = transitionRide(ctx, s.rideRepo, locked, model.RideActive, &userID, ""); err != nil {
+			return err
+		}
+		ride.Status = locked.Status
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	return ride, nil
+}
+
+// abortStart provizyonu alınamayan, bağlanılamayan veya kilidi açılamayan motorun sürüşünü iptal eder: bağlantı kaydı kapatılır,
+// sürüşe dönüşen rezervasyon tekrar aktif olur ve motor müsait (veya rezerve) ve kilitli duruma döner.
+// connected ise transaction sonrasında cihazla kurulan bağlantı da LockController ile kesilir.
+func (s *RideService) abortStart(ctx context.Context, ride *model.Ride, connected bool, reason string) error {
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, ride.MotorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		motorbike.Status = model.BikeAvailable
+		motorbike.LockStatus = model.Locked
+
+		reservation, err := s.reservationRepo.GetByRideID(ctx, ride.ID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if reservation != nil {
+			reservation.Status = model.ReservationActive
+			reservation.RideID = nil
+			reservation.EndedAt = nil
+			if err = s.reservationRepo.Update(ctx, reservation); err != nil {
+				return errorx.WrapErr(errorx.ErrInternal, err)
+			}
+			motorbike.Status = model.BikeReserved
+		}
+
+		if err = s.motorRepo.Update(ctx, motorbike); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+		}
+		if err = s.connRepo.CloseOpenByMotorbikeID(ctx, ride.MotorbikeID, time.Now().UTC()); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		locked, err := s.rideRepo.GetByIDForUpdate(ctx, ride.ID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		return transitionRide(ctx, s.rideRepo, locked, model.RideCancelled, nil, reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	if connected {
+		if err = s.locks.Disconnect(ctx, ride.MotorbikeID); err != nil {
+			return errorx.FromError(errorx.ErrInternal, err)
+		}
 	}
 	return nil
 }
@@ -37,17 +306,20 @@ func (s *RideService) GetByID(ctx context.Context, id int64) (*model.Ride, error
 	return ride, nil
 }
 
-func (s *RideService) Update(ctx context.Context, ride model.Ride) error {
-	if err := s.rideRepo.Update(ctx, &ride); err != nil {
-		return errorx.WrapErr(errorx.ErrInternal, err)
+// Delete sürüş kaydını siler. Devam eden sürüş motoru kullanıcıda tuttuğu için silinemez.
+func (s *RideService) Delete(ctx context.Context, id int64) error {
+	ride, err := s.rideRepo.GetByID(ctx, id)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.Status.IsOngoing() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Devam eden sürüş silinemez")
 	}
-	return nil
-}
 
-func (s *RideService) Delete(ctx context.Context, id int64) error {
-	if err := s.rideRepo.Delete(ctx, id); err != nil {
+	if err = s.rideRepo.Delete(ctx, id); err != nil {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
+	s.audit.RecordChange(ctx, "rides.delete", "rides", id, ride, nil)
 	return nil
 }
 
@@ -85,73 +357,725 @@ func (s *RideService) GetByMotorbikeID(ctx context.Context, motorbikeID int64) (
 	return rides, nil
 }
 
+// FinishRide sürüşü bitirir, ücreti motorun tarifesine göre hesaplar ve fiyat dökümünü sürüşle aynı transaction içinde kaydeder.
+// Kaydedilen GPS noktalarından rota özeti (mesafe, hız, duraksama) hesaplanıp sürüşe yazılır.
+// Motorun konumu park kurallarına göre kontrol edilir; yasak alanda bitirilen sürüş reddedilir veya ek ücret alınır.
+// Kullanıcının aboneliği varsa o günkü dahil dakikaları ücretli dakikalardan önce kullanılır.
+// Motor kilitli değilse önce LockController ile kilitlenmesi istenir. Ücret aynı transaction içinde kullanıcının cüzdanından düşülür,
+// cüzdanın karşılamadığı kısım transaction sonrasında karttan tahsil edilir. Sürüş transaction içinde ending durumuna geçer,
+// tahsilat sonucuna göre completed veya payment_failed olur; ödeme kapatılamazsa da payment_failed olur. Son olarak sürüşün fişi kesilip kullanıcıya e-postayla gönderilir.
+// Park modundaki sürüş de bitirilebilir; açık park modu bitiş anında kapatılır.
 func (s *RideService) FinishRide(ctx context.Context, rideID int64, userID int64) (*model.Ride, error) {
+	if err := s.lockBeforeFinish(ctx, rideID, userID); err != nil {
+		return nil, err
+	}
+	return s.finish(ctx, rideID, userID, rideEnd{at: time.Now().UTC()})
+}
+
+// rideEnd sürüşün bitiş bilgisi. auto, park modu süresi dolduğu için sistemin bitirdiği sürüşlerdir; bu sürüşlerde
+// kullanıcı motorun başında olmadığı için kilit ve park yeri kontrolleri bitirmeyi engellemez, park ek ücretleri yine alınır.
+// Kullanıcı bağlantıyı kesmeyeceği için motor bitiş transaction'ı içinde kiralamaya açılır; motorun kilitlenmesi
+// transaction öncesinde endExpiredPause ile yapılır.
+type rideEnd struct {
+	at   time.Time
+	auto bool
+}
+
+func (s *RideService) finish(ctx context.Context, rideID, userID int64, end rideEnd) (*model.Ride, error) {
+	var finished *model.Ride
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ride, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if ride.UserID != userID {
+			return errorx.WrapMsg(errorx.ErrUnauthorized, "Bu sürüşe erişim yetkiniz yok.")
+		}
+		if err = checkRideTransition(ride.Status, model.RideEnding); err != nil {
+			return err
+		}
+
+		motorbike, err := s.motorRepo.GetByID(ctx, ride.MotorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+		}
+		if motorbike.LockStatus != model.Locked && !end.auto {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Motorbike kilitlenmedi! Lütfen önce kilitleyin!")
+		}
+
+		parking, err := s.checkParking(ctx, motorbike)
+		if err != nil {
+			return err
+		}
+		if !parking.Allowed && !end.auto {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, parking.Reason)
+		}
+
+		if err = s.closePause(ctx, ride.ID, end.at); err != nil {
+			return err
+		}
+
+		now := end.at
+		input, err := s.fareInputForRide(ctx, ride, now)
+		if err != nil {
+			return err
+		}
+		allowance, err := s.passes.Allowance(ctx, ride.UserID, ride.StartTime, true)
+		if err != nil {
+			return err
+		}
+		allowance.Apply(&input)
+		breakdown, err := s.calculateFare(ctx, motorbike.Model, input)
+		if err != nil {
+			return err
+		}
+		for _, line := range parking.Surcharges {
+			breakdown.AddLine(line)
+		}
+		if err = s.promotions.ApplyDiscount(ctx, ride, motorbike.Model, breakdown, now); err != nil {
+			return err
+		}
+
+		points, err := s.rideRepo.ListRoutePoints(ctx, ride.ID)
+		if err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Sürüş rotası alınamadı")
+		}
+		SummarizeRoute(points).Apply(ride)
+
+		actorID, note := &userID, ""
+		if end.auto {
+			actorID, note = nil, "Park modu süresi doldu, sürüş otomatik bitirildi"
+		}
+		if err = transitionRide(ctx, s.rideRepo, ride, model.RideEnding, actorID, note); err != nil {
+			return err
+		}
+		ride.EndTime = &now
+		ride.Duration = int64(now.Sub(ride.StartTime).Seconds())
+		ride.Cost = breakdown.Total
+		ride.Currency = breakdown.Currency
+
+		if err = s.rideRepo.Update(ctx, ride); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Sürüş Bitirilemedi!")
+		}
+
+		breakdown.RideID = ride.ID
+		if err = s.rideRepo.SavePriceBreakdown(ctx, breakdown); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Fiyat dökümü kaydedilemedi")
+		}
+		if err = s.passes.RecordUsage(ctx, allowance, ride.ID, breakdown); err != nil {
+			return err
+		}
+
+		if end.auto {
+			if err = s.releaseMotorbike(ctx, motorbike); err != nil {
+				return err
+			}
+		}
+
+		finished = ride
+		if err = s.wallet.ChargeRide(ctx, ride); err != nil {
+			return err
+		}
+		return s.referrals.RewardFirstRide(ctx, ride)
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	if err = s.payments.Settle(ctx, finished); err != nil {
+		// Sürüş ending durumunda kalmaz; kullanıcı ödemeyi RetryRide ile tekrar dener
+		logger.Error("Sürüşün ödemesi kapatılamadı (ride_id=%d): %v", rideID, err)
+		if failErr := s.payments.FailSettlement(ctx, rideID, err); failErr != nil {
+			logger.Error("Ödemesi kapatılamayan sürüş payment_failed durumuna geçirilemedi (ride_id=%d): %v", rideID, failErr)
+		}
+	}
+	// Fiş kesilemezse sürüş yine bitirilir; fiş ilk istendiğinde tekrar kesilmeye çalışılır
+	if _, err = s.invoices.IssueRideReceipt(ctx, rideID); err != nil {
+		logger.Error("Sürüş fişi kesilemedi (ride_id=%d): %v", rideID, err)
+	}
+
+	updatedRide, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return updatedRide, nil
+}
+
+// lockBeforeFinish bitirilecek sürüşün motoru kilitli değilse kilitler ve kilit durumunu kaydeder.
+// Sürüşün ve motorun son durumu FinishRide transaction'ı içinde tekrar kontrol edilir.
+func (s *RideService) lockBeforeFinish(ctx context.Context, rideID, userID int64) error {
 	ride, err := s.rideRepo.GetByID(ctx, rideID)
 	if err != nil {
-		return nil, errorx.WrapErr(errorx.ErrNotFound, err)
+		return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.UserID != userID || !ride.Status.CanTransitionTo(model.RideEnding) {
+		return nil
 	}
+	return s.lockMotorbike(ctx, ride.MotorbikeID)
+}
 
-	if ride == nil {
+// endExpiredPause park modu süresi dolan sürüşü sistem adına bitirir. Cihaz komutu bitiş transaction'ını beklemesin diye
+// motor transaction öncesinde kilitlenir; motor kilitlenemezse sürüş bitirilmez.
+func (s *RideService) endExpiredPause(ctx context.Context, ride *model.Ride, pause *model.RidePause) (*model.Ride, error) {
+	if err := s.lockMotorbike(ctx, ride.MotorbikeID); err != nil {
+		return nil, err
+	}
+	return s.finish(ctx, ride.ID, ride.UserID, rideEnd{at: pause.StartedAt.Add(s.maxPause), auto: true})
+}
+
+// releaseMotorbike sistemin bitirdiği sürüşün kilitlenmiş motorunu kiralamaya açar ve açık Bluetooth bağlantısını kapatır;
+// transaction içinde çağrılmalıdır. Motor bu arada tekrar açıldıysa hata döner ve sürüş bitirilmez.
+func (s *RideService) releaseMotorbike(ctx context.Context, motorbike *model.Motorbike) error {
+	if motorbike.LockStatus != model.Locked {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Motorbike kilitlenmedi! Sürüş otomatik bitirilemedi")
+	}
+
+	motorbike.Status = model.BikeAvailable
+	if err := s.motorRepo.UpdateColumns(ctx, motorbike, "status", "lock_status"); err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+	}
+	if err := s.connRepo.CloseOpenByMotorbikeID(ctx, motorbike.ID, time.Now().UTC()); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Bluetooth bağlantısı kapatılamadı")
+	}
+	return nil
+}
+
+// lockMotorbike motor kilitli değilse LockController ile kilitler ve kilit durumunu kaydeder
+func (s *RideService) lockMotorbike(ctx context.Context, motorbikeID int64) error {
+	motorbike, err := s.motorRepo.GetByID(ctx, motorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+	}
+	if motorbike.LockStatus == model.Locked {
+		return nil
+	}
+
+	if err = s.locks.Lock(ctx, motorbikeID); err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	motorbike.LockStatus = model.Locked
+	if err = s.motorRepo.UpdateColumns(ctx, motorbike, "lock_status"); err != nil {
+		return errorx.WrapMsg(errorx.ErrInternal, "Motor kilit durumu güncellenirken hata oluştu!")
+	}
+	return nil
+}
+
+// PauseRide sürüşü park moduna alır: motor LockController ile kilitlenir ve kiralık olarak kullanıcıya ayrılmış kalır.
+// Park modunda geçen dakikalar tarifenin park modu ücretiyle ücretlendirilir; park modu maxPause süresini aşarsa sürüş otomatik bitirilir.
+func (s *RideService) PauseRide(ctx context.Context, rideID, userID int64) (*model.Ride, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
 		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
 	}
 	if ride.UserID != userID {
-		return nil, errorx.WrapMsg(errorx.ErrUnauthorized, "Bu sürüşe erişim yetkiniz yok.")
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
 	}
-	if ride.EndTime != nil && !ride.EndTime.IsZero() {
-		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş zaten bitirildi!")
+	if err = checkRideTransition(ride.Status, model.RidePaused); err != nil {
+		return nil, err
 	}
 
-	now := time.Now().UTC()
-	startTime := ride.StartTime.UTC()
+	if err = s.lockMotorbike(ctx, ride.MotorbikeID); err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if err = transitionRide(ctx, s.rideRepo, locked, model.RidePaused, &userID, ""); err != nil {
+			return err
+		}
+		if err = s.rideRepo.CreatePause(ctx, &model.RidePause{RideID: rideID, StartedAt: time.Now().UTC()}); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Park modu kaydedilemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	return s.GetByID(ctx, rideID)
+}
+
+// ResumeRide park modundaki sürüşe devam eder: motorun kilidi açılır ve park modu kapatılır.
+// Park modu süresi dolmuş ama worker henüz bitirmemişse sürüş bitirilir ve hata döner.
+func (s *RideService) ResumeRide(ctx context.Context, rideID, userID int64) (*model.Ride, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if ride.UserID != userID {
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+	}
+	if ride.Status != model.RidePaused {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş park modunda değil")
+	}
+
+	pause, err := s.rideRepo.GetOpenPause(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if pause != nil && s.maxPause > 0 && time.Since(pause.StartedAt) > s.maxPause {
+		if _, err = s.endExpiredPause(ctx, ride, pause); err != nil {
+			return nil, err
+		}
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Park modu süresi dolduğu için sürüş bitirildi")
+	}
+
+	if err = s.locks.Unlock(ctx, ride.MotorbikeID); err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if err = transitionRide(ctx, s.rideRepo, locked, model.RideActive, &userID, ""); err != nil {
+			return err
+		}
+		if err = s.closePause(ctx, rideID, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, locked.MotorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+		}
+		motorbike.LockStatus = model.Unlocked
+		if err = s.motorRepo.UpdateColumns(ctx, motorbike, "lock_status"); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor kilit durumu güncellenirken hata oluştu!")
+		}
+		return nil
+	})
+	if err != nil {
+		// Motorun kilidi açıldı ama sürüş park modunda kaldı; motor tekrar kilitlenir
+		if lockErr := s.locks.Lock(ctx, ride.MotorbikeID); lockErr != nil {
+			logger.Error("Devam ettirilemeyen sürüşün motoru tekrar kilitlenemedi (ride_id=%d): %v", rideID, lockErr)
+		}
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	return s.GetByID(ctx, rideID)
+}
+
+// closePause sürüşün açık park modunu verilen zamanda kapatır, açık park modu yoksa bir şey yapmaz
+func (s *RideService) closePause(ctx context.Context, rideID int64, at time.Time) error {
+	pause, err := s.rideRepo.GetOpenPause(ctx, rideID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if pause == nil {
+		return nil
+	}
+
+	pause.EndedAt = &at
+	if err = s.rideRepo.UpdatePause(ctx, pause); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Park modu kapatılamadı")
+	}
+	return nil
+}
+
+// EndExpiredPauses park modu maxPause süresini aşan sürüşleri park modunun dolduğu anda bitirir, bitirilen sürüş sayısını döner.
+// Bitirilemeyen sürüşler loglanır ve bir sonraki turda tekrar denenir.
+func (s *RideService) EndExpiredPauses(ctx context.Context, now time.Time) (int, error) {
+	if s.maxPause <= 0 {
+		return 0, nil
+	}
+
+	due, err := s.rideRepo.ListOpenPausesStartedBefore(ctx, now.Add(-s.maxPause), ridePauseBatchSize)
+	if err != nil {
+		return 0, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	ended := 0
+	for _, pause := range due {
+		ride, err := s.rideRepo.GetByID(ctx, pause.RideID)
+		if err != nil {
+			logger.Error("Park modu süresi dolan sürüş alınamadı (ride_id=%d): %v", pause.RideID, err)
+			continue
+		}
+		if _, err = s.endExpiredPause(ctx, ride, &pause); err != nil {
+			logger.Error("Park modu süresi dolan sürüş bitirilemedi (ride_id=%d): %v", ride.ID, err)
+			continue
+		}
+		ended++
+	}
+	return ended, nil
+}
+
+// RunPauseWorker ctx iptal edilene kadar belirtilen aralıklarla park modu süresi dolan sürüşleri bitirir
+func (s *RideService) RunPauseWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.EndExpiredPauses(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Error("Park modu süre kontrolü hatası: %v", err)
+				continue
+			}
+			if count > 0 {
+				logger.Info("Park modu süresi dolan %d sürüş bitirildi", count)
+			}
+		}
+	}
+}
 
-	// Süreyi hesapla ve mutlak değerini al
-	duration := now.Sub(startTime)
-	if duration < 0 {
-		duration = -duration
+// GetPriceBreakdown sürüşün fiyat dökümünü getirir. rides.read yetkisi olmayan kullanıcılar yalnızca kendi sürüşlerini görebilir.
+func (s *RideService) GetPriceBreakdown(ctx context.Context, rideID, userID int64, role model.Role) (*model.RidePriceBreakdown, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, ride.UserID, userID, "Bu sürüşe erişim yetkiniz yok."); err != nil {
+		return nil, err
 	}
 
-	minutes := int(duration.Minutes())
-	if minutes < 0 {
-		minutes = 0
+	breakdown, err := s.rideRepo.GetPriceBreakdown(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Bu sürüş için fiyat dökümü bulunamadı")
+	}
+	return breakdown, nil
+}
+
+// ListEvents sürüşün durum geçmişini getirir. rides.read yetkisi olmayan kullanıcılar yalnızca kendi sürüşlerini görebilir.
+func (s *RideService) ListEvents(ctx context.Context, rideID, userID int64, role model.Role) ([]model.RideEvent, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, ride.UserID, userID, "Bu sürüşe erişim yetkiniz yok."); err != nil {
+		return nil, err
+	}
+
+	events, err := s.rideRepo.ListEvents(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return events, nil
+}
+
+// ActiveRide devam eden bir sürüşün anlık durumu
+type ActiveRide struct {
+	Ride      *model.Ride
+	Motorbike *model.Motorbike
+	Elapsed   time.Duration
+	Fare      *model.RidePriceBreakdown // şu an bitirilse ödenecek tutar
+	Parking   *ParkingDecision          // motorun şu anki konumunda bitirilebilir mi
+}
+
+// GetActiveRide kullanıcının devam eden sürüşünü, geçen süreyi ve FinishRide ile aynı hesaplamayla anlık ücreti döner
+func (s *RideService) GetActiveRide(ctx context.Context, userID int64) (*ActiveRide, error) {
+	ride, err := s.rideRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ride == nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Devam eden bir sürüşünüz yok")
 	}
-	ride.Duration = strconv.Itoa(int(duration.Seconds()))
-	ride.Cost = float64(minutes*3) + 10
-	ride.EndTime = &now
 
 	motorbike, err := s.motorRepo.GetByID(ctx, ride.MotorbikeID)
 	if err != nil {
 		return nil, errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
 	}
-	if motorbike == nil {
-		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+
+	now := time.Now().UTC()
+	input, err := s.fareInputForRide(ctx, ride, now)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := s.passes.Allowance(ctx, ride.UserID, ride.StartTime, false)
+	if err != nil {
+		return nil, err
 	}
+	allowance.Apply(&input)
+	fare, err := s.calculateFare(ctx, motorbike.Model, input)
+	if err != nil {
+		return nil, err
+	}
+	fare.RideID = ride.ID
 
-	if motorbike.LockStatus != model.Locked {
-		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Motorbike kilitlenmedi! Lütfen önce kilitleyin!")
+	parking, err := s.checkParking(ctx, motorbike)
+	if err != nil {
+		return nil, err
 	}
+	for _, line := range parking.Surcharges {
+		fare.AddLine(line)
+	}
+	s.promotions.PreviewDiscount(ctx, ride, motorbike.Model, fare, now)
+
+	return &ActiveRide{
+		Ride:      ride,
+		Motorbike: motorbike,
+		Elapsed:   now.Sub(ride.StartTime),
+		Fare:      fare,
+		Parking:   parking,
+	}, nil
+}
 
-	if err = s.rideRepo.Update(ctx, ride); err != nil {
-		return nil, errorx.WrapMsg(errorx.ErrInternal, "Sürüş Bitirilemedi!")
+// EstimateFare verilen motor modeli ve planlanan süre için fiyat teklifi hesaplar.
+// startTime boşsa teklif şimdiki zamana göre hesaplanır.
+func (s *RideService) EstimateFare(ctx context.Context, motorbikeModel string, duration time.Duration, startTime *time.Time) (*model.RidePriceBreakdown, error) {
+	start := time.Now().UTC()
+	if startTime != nil && !startTime.IsZero() {
+		start = startTime.UTC()
 	}
 
-	updatedRide, _ := s.rideRepo.GetByID(ctx, rideID)
-	return updatedRide, nil
+	return s.calculateFare(ctx, motorbikeModel, FareInput{StartTime: start, EndTime: start.Add(duration)})
 }
 
-func (s *RideService) HandleAfterPhotoUpload(ctx context.Context, rideID int) error {
-	ride, err := s.rideRepo.GetByID(ctx, int64(rideID))
+// RecordRoute devam eden sürüş için uygulamadan gelen GPS noktalarını kaydeder
+func (s *RideService) RecordRoute(ctx context.Context, rideID, userID int64, points []model.RidePoint) error {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
 	if err != nil {
 		return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
 	}
+	if ride.UserID != userID {
+		return errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+	}
+	if !ride.Status.IsOngoing() {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş bitirilmiş, rota noktası eklenemez")
+	}
 
-	if ride.Motorbike.LockStatus != model.Locked {
-		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Lütfen önce motoru kilitleyin")
+	latest := time.Now().UTC().Add(maxTelemetryClockSkew)
+	for i := range points {
+		point := &points[i]
+		if point.RecordedAt.Before(ride.StartTime) || point.RecordedAt.After(latest) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Rota noktasının zamanı sürüş süresi içinde olmalıdır")
+		}
+		point.RideID = ride.ID
+		point.RecordedAt = point.RecordedAt.UTC()
+		point.Source = model.RoutePointFromApp
 	}
 
-	// todo connectRepo yapınca hallet
-	/*if err = s.connRepo.Disconnect(ctx, int(ride.MotorbikeID)); err != nil {
-		return errorx.WrapMsg(errorx.ErrInternal, "Motor bağlantısı kesilemedi")
+	if err = s.rideRepo.CreateRoutePoints(ctx, points); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// RideRoute sürüşün rotası ve özeti
+type RideRoute struct {
+	Ride    *model.Ride
+	Points  []model.RidePoint
+	Summary RouteSummary
+}
+
+// GetRoute sürüşün GPS noktalarını ve rota özetini getirir. Bitmiş sürüşlerde kaydedilmiş özet,
+// devam eden sürüşlerde o ana kadarki noktalardan hesaplanan özet döner.
+// rides.read yetkisi olmayan kullanıcılar yalnızca kendi sürüşlerini görebilir.
+func (s *RideService) GetRoute(ctx context.Context, rideID, userID int64, role model.Role) (*RideRoute, error) {
+	ride, err := s.rideRepo.GetByID(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+	}
+	if err = authorizeOwnerOrPermission(ctx, s.permissions, role, model.PermRidesRead, ride.UserID, userID, "Bu sürüşe erişim yetkiniz yok."); err != nil {
+		return nil, err
+	}
+
+	points, err := s.rideRepo.ListRoutePoints(ctx, rideID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	route := &RideRoute{Ride: ride, Points: points}
+	if ride.Status.HasEnded() {
+		route.Summary = RouteSummary{
+			DistanceMeters: ride.DistanceMeters,
+			MaxSpeedKmh:    ride.MaxSpeedKmh,
+			AvgSpeedKmh:    ride.AvgSpeedKmh,
+			IdleSeconds:    ride.IdleSeconds,
+		}
+	} else {
+		route.Summary = SummarizeRoute(points)
+	}
+	return route, nil
+}
+
+// fareInputForRide sürüşün şu ana kadarki ücret girdisini hazırlar; sürüşe dönüşen rezervasyonun ücreti ve park modu aralıkları da eklenir
+func (s *RideService) fareInputForRide(ctx context.Context, ride *model.Ride, end time.Time) (FareInput, error) {
+	input := FareInput{StartTime: ride.StartTime, EndTime: end}
+
+	pauses, err := s.rideRepo.ListPauses(ctx, ride.ID)
+	if err != nil {
+		return input, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	input.Pauses = pauses
+
+	reservation, err := s.reservationRepo.GetByRideID(ctx, ride.ID)
+	if err != nil {
+		return input, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if reservation != nil {
+		input.ReservationFee = reservation.Fee
+	}
+	return input, nil
+}
+
+// checkParking motorun son bilinen konumunu aktif alanların park kurallarına göre değerlendirir
+func (s *RideService) checkParking(ctx context.Context, motorbike *model.Motorbike) (*ParkingDecision, error) {
+	zones, err := s.zoneRepo.ListActive(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	point := geo.Point{Lat: motorbike.LocationLatitude, Lng: motorbike.LocationLongitude}
+	decision, err := EvaluateParking(zones, point)
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Park alanları kontrol edilemedi")
+	}
+	return &decision, nil
+}
+
+// calculateFare motor modelinin aktif tarifesini bulur ve ücreti hesaplar
+func (s *RideService) calculateFare(ctx context.Context, motorbikeModel string, in FareInput) (*model.RidePriceBreakdown, error) {
+	tariff, err := s.tariffRepo.GetActiveForModel(ctx, motorbikeModel)
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Geçerli bir tarife bulunamadı")
+	}
+
+	return s.fareCalculator.Calculate(*tariff, in), nil
+}
+
+// HandleAfterPhotoUpload sürüş sonu fotoğrafı yüklendikten sonra motorun kilitli olduğunu doğrular ve motorun açık
+// Bluetooth bağlantısını kapatır; motor zaten kilitli olduğundan cihaza komut gönderilmez. Motorun bitmemiş bir sürüşü
+// varsa (fotoğraf sürüş bitirilmeden yüklendiyse veya motoru başka bir kullanıcı kiraladıysa) bağlantı kapatılmaz.
+func (s *RideService) HandleAfterPhotoUpload(ctx context.Context, rideID, userID int64) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ride, err := s.rideRepo.GetByIDForUpdate(ctx, rideID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Sürüş bulunamadı")
+		}
+		if ride.UserID != userID {
+			return errorx.WrapMsg(errorx.ErrForbidden, "Bu sürüşe erişim yetkiniz yok.")
+		}
+
+		motorbike, err := s.motorRepo.GetByID(ctx, ride.MotorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motorbike bilgileri alınamadı!")
+		}
+		if motorbike.LockStatus != model.Locked {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Lütfen önce motoru kilitleyin")
+		}
+
+		active, err := s.rideRepo.GetActiveByMotorbikeID(ctx, ride.MotorbikeID)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if active != nil {
+			return nil
+		}
+		if err = s.connRepo.CloseOpenByMotorbikeID(ctx, ride.MotorbikeID, time.Now().UTC()); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Bluetooth bağlantısı kapatılamadı")
+		}
+		return nil
+	})
+}
+
+// Disconnect kullanıcının motorla açık Bluetooth bağlantısını keser; motor LockController ile kilitli bırakılır ve
+// kiralamaya açılır. Motor kilitlenmeden bağlantı kapatılmaz; cihaz komutu satır kilidi tutulmadan transaction öncesinde
+// gönderilir, bağlantının ve motorun son durumu transaction içinde motor satırı kilitlenerek tekrar kontrol edilir.
+// Motorun bitmemiş bir sürüşü varsa veya bağlantı kullanıcıya ait değilse bağlantı kesilmez.
+func (s *RideService) Disconnect(ctx context.Context, userID, motorbikeID int64) error {
+	if _, err := s.openConnection(ctx, userID, motorbikeID); err != nil {
+		return err
+	}
+	if err := s.locks.Disconnect(ctx, motorbikeID); err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motor bulunamadı")
+		}
+		connection, err := s.openConnection(ctx, userID, motorbikeID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		connection.DisconnectedAt = &now
+		if err = s.connRepo.Update(ctx, connection); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Bağlantı kesilemedi!")
+		}
+
+		motorbike.LockStatus = model.Locked
+		if motorbike.Status == model.BikeRented {
+			motorbike.Status = model.BikeAvailable
+		}
+		if err = s.motorRepo.UpdateColumns(ctx, motorbike, "status", "lock_status"); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor status güncellenirken hata oluştu!")
+		}
+		return nil
+	})
+}
+
+// openConnection kullanıcının motorla kesilebilecek açık bağlantısını döner. Bağlantı yoksa, başka kullanıcıya aitse
+// veya motorun bitmemiş bir sürüşü varsa hata döner.
+func (s *RideService) openConnection(ctx context.Context, userID, motorbikeID int64) (*model.BluetoothConnection, error) {
+	connection, err := s.connRepo.GetOpenByMotorbikeID(ctx, motorbikeID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if connection == nil {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Motorla açık bir bağlantı yok")
+	}
+	if connection.UserID != userID {
+		return nil, errorx.WrapMsg(errorx.ErrForbidden, "Bu bağlantıyı kesme yetkiniz yok")
+	}
+
+	active, err := s.rideRepo.GetActiveByMotorbikeID(ctx, motorbikeID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if active != nil {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Devam eden sürüş var, önce sürüşü bitirin")
+	}
+	return connection, nil
+}
+
+// checkRideTransition sürüşün durum tablosuna göre from durumundan next durumuna geçip geçemeyeceğini kontrol eder
+func checkRideTransition(from, next model.RideStatus) error {
+	if from.CanTransitionTo(next) {
+		return nil
+	}
+	switch {
+	case from.HasEnded() && next == model.RideEnding:
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş zaten bitirildi!")
+	case from == model.RideCancelled:
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Sürüş iptal edilmiş")
+	default:
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Sürüş %s durumundan %s durumuna geçirilemez", from, next))
+	}
+}
+
+// transitionRide kilitlenmiş sürüşü next durumuna geçirir ve geçişi ride_events tablosuna yazar; transaction içinde çağrılmalıdır.
+// Sürüşün durumu yalnızca bu fonksiyonla değiştirilir, actorID sistem tarafından yapılan geçişlerde nil'dir.
+func transitionRide(ctx context.Context, rideRepo repository.IRideRepository, ride *model.Ride, next model.RideStatus, actorID *int64, note string) error {
+	if err := checkRideTransition(ride.Status, next); err != nil {
+		return err
+	}
+
+	from := ride.Status
+	if err := rideRepo.UpdateStatus(ctx, ride.ID, next); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Sürüşün durumu güncellenemedi")
+	}
+	ride.Status = next
+	return recordRideEvent(ctx, rideRepo, ride.ID, from, next, actorID, note)
+}
+
+func recordRideEvent(ctx context.Context, rideRepo repository.IRideRepository, rideID int64, from, to model.RideStatus, actorID *int64, note string) error {
+	event := &model.RideEvent{RideID: rideID, FromStatus: from, ToStatus: to, ActorID: actorID, Note: note}
+	if err := rideRepo.CreateEvent(ctx, event); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Sürüş geçmişi kaydedilemedi")
 	}
-	*/
 	return nil
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+const (
+	// Bu hızın altındaki aralıklar duraksama sayılır (GPS sapmaları nedeniyle sıfır kullanılmaz)
+	idleSpeedKmh = 3.0
+	// Bu hızı aşan aralıklar GPS sıçraması kabul edilir ve noktası yok sayılır
+	maxPlausibleSpeedKmh = 250.0
+)
+
+// RouteSummary sürüş rotasından hesaplanan özet
+type RouteSummary struct {
+	DistanceMeters int64
+	MaxSpeedKmh    float64
+	AvgSpeedKmh    float64
+	IdleSeconds    int64
+}
+
+// SummarizeRoute GPS noktalarından gidilen mesafeyi, en yüksek ve ortalama hızı ve duraksama süresini hesaplar.
+// Noktalar kayıt zamanına göre sıralanır; aynı zamanlı tekrar eden noktalar ve önceki noktaya göre
+// gerçek dışı hız gerektiren sıçramalar atlanır. Ortalama hız duraksamalar hariç hareket süresine göre hesaplanır.
+func SummarizeRoute(points []model.RidePoint) RouteSummary {
+	sorted := make([]model.RidePoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+
+	var summary RouteSummary
+	var distance, movingSeconds, idleSeconds float64
+
+	for i, prev := 1, 0; i < len(sorted); i++ {
+		from, to := sorted[prev], sorted[i]
+		seconds := to.RecordedAt.Sub(from.RecordedAt).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+
+		meters := geo.HaversineMeters(
+			geo.Point{Lat: from.Latitude, Lng: from.Longitude},
+			geo.Point{Lat: to.Latitude, Lng: to.Longitude},
+		)
+		speed := meters / seconds * 3.6
+		if speed > maxPlausibleSpeedKmh {
+			continue
+		}
+		prev = i
+
+		distance += meters
+		if speed < idleSpeedKmh {
+			idleSeconds += seconds
+		} else {
+			movingSeconds += seconds
+			summary.MaxSpeedKmh = math.Max(summary.MaxSpeedKmh, speed)
+		}
+		if to.SpeedKmh != nil && *to.SpeedKmh <= maxPlausibleSpeedKmh {
+			summary.MaxSpeedKmh = math.Max(summary.MaxSpeedKmh, *to.SpeedKmh)
+		}
+	}
+
+	summary.DistanceMeters = int64(math.Round(distance))
+	summary.IdleSeconds = int64(math.Round(idleSeconds))
+	summary.MaxSpeedKmh = roundTo(summary.MaxSpeedKmh, 1)
+	if movingSeconds > 0 {
+		summary.AvgSpeedKmh = roundTo(distance/movingSeconds*3.6, 1)
+	}
+	return summary
+}
+
+// Apply özeti sürüşe yazar
+func (s RouteSummary) Apply(ride *model.Ride) {
+	ride.DistanceMeters = s.DistanceMeters
+	ride.MaxSpeedKmh = s.MaxSpeedKmh
+	ride.AvgSpeedKmh = s.AvgSpeedKmh
+	ride.IdleSeconds = s.IdleSeconds
+}
+
+func roundTo(value float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(value*pow) / pow
+}
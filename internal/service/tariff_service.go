@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+)
+
+type TariffService struct {
+	tariffRepo repository.ITariffRepository
+	audit      Auditor
+}
+
+func NewTariffService(repo repository.ITariffRepository, audit Auditor) *TariffService {
+	return &TariffService{tariffRepo: repo, audit: audit}
+}
+
+func (s *TariffService) Create(ctx context.Context, tariff *model.Tariff) error {
+	if err := checkTariffCurrency(tariff); err != nil {
+		return err
+	}
+	if err := s.tariffRepo.Create(ctx, tariff); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "tariffs.create", "tariffs", tariff.ID, nil, tariff)
+	return nil
+}
+
+func (s *TariffService) GetByID(ctx context.Context, id int64) (*model.Tariff, error) {
+	tariff, err := s.tariffRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Tarife bulunamadı")
+	}
+	return tariff, nil
+}
+
+func (s *TariffService) Update(ctx context.Context, tariff model.Tariff) error {
+	current, err := s.GetByID(ctx, tariff.ID)
+	if err != nil {
+		return err
+	}
+	if err = checkTariffCurrency(&tariff); err != nil {
+		return err
+	}
+	if err = s.tariffRepo.Update(ctx, &tariff); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "tariffs.update", "tariffs", tariff.ID, current, tariff)
+	return nil
+}
+
+func (s *TariffService) Delete(ctx context.Context, id int64) error {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.tariffRepo.Delete(ctx, id); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "tariffs.delete", "tariffs", id, current, nil)
+	return nil
+}
+
+// checkTariffCurrency sürüş ücreti cüzdandan düşüldüğü için tarifenin cüzdanlarla aynı para biriminde olmasını ister.
+// Cüzdanlar money.DefaultCurrency ile açılır; başka para birimindeki tarifeyle bitirilen sürüş cüzdana yazılamaz.
+func checkTariffCurrency(tariff *model.Tariff) error {
+	if tariff.Currency != money.DefaultCurrency {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Tarife para birimi cüzdan para birimiyle ("+money.DefaultCurrency+") aynı olmalı")
+	}
+	return nil
+}
+
+func (s *TariffService) List(ctx context.Context) ([]model.Tariff, error) {
+	tariffs, err := s.tariffRepo.List(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return tariffs, nil
+}
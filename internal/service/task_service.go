@@ -0,0 +1,583 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/logger"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+)
+
+type TaskServiceDeps struct {
+	TaskRepo          repository.ITaskRepository
+	MotorbikeRepo     repository.IMotorbikeRepository
+	RideRepo          repository.IRideRepository
+	UserRepo          repository.IUserRepository
+	ZoneRepo          repository.IZoneRepository
+	TxManager         repository.ITransactionManager
+	Permissions       PermissionChecker // görev atanacak kullanıcının tasks.work yetkisi kontrol edilir
+	Audit             Auditor
+	Store             storage.BlobStore
+	MaxPhotos         int
+	MaxPhotoBytes     int64
+	SLAs              map[model.TaskType]time.Duration // görev türlerine göre varsayılan tamamlanma süreleri
+	LowBatteryPercent int                              // 0 ise batarya görevleri otomatik açılmaz
+	IdleAfter         time.Duration                    // 0 ise konum değişikliği görevleri otomatik açılmaz
+}
+
+// TaskService saha ekibinin görevlerini yönetir. Admin görev açar ve operatöre atar; operatör yakındaki görevleri
+// görür, üstlenir ve fotoğraflı kanıtla tamamlar. Worker bataryası azalan ve uzun süredir kiralanmayan müsait
+// motorlar için otomatik görev açar. Bir motor için aynı türde aynı anda yalnızca bir açık görev olabilir.
+type TaskService struct {
+	taskRepo          repository.ITaskRepository
+	motorRepo         repository.IMotorbikeRepository
+	rideRepo          repository.IRideRepository
+	userRepo          repository.IUserRepository
+	zoneRepo          repository.IZoneRepository
+	txManager         repository.ITransactionManager
+	permissions       PermissionChecker
+	audit             Auditor
+	store             storage.BlobStore
+	maxPhotos         int
+	maxPhotoBytes     int64
+	slas              map[model.TaskType]time.Duration
+	lowBatteryPercent int
+	idleAfter         time.Duration
+}
+
+func NewTaskService(deps TaskServiceDeps) *TaskService {
+	return &TaskService{
+		taskRepo:          deps.TaskRepo,
+		motorRepo:         deps.MotorbikeRepo,
+		rideRepo:          deps.RideRepo,
+		userRepo:          deps.UserRepo,
+		zoneRepo:          deps.ZoneRepo,
+		txManager:         deps.TxManager,
+		permissions:       deps.Permissions,
+		audit:             deps.Audit,
+		store:             deps.Store,
+		maxPhotos:         deps.MaxPhotos,
+		maxPhotoBytes:     deps.MaxPhotoBytes,
+		slas:              deps.SLAs,
+		lowBatteryPercent: deps.LowBatteryPercent,
+		idleAfter:         deps.IdleAfter,
+	}
+}
+
+// CreateTaskInput admin'in açtığı görev. DueAt verilmezse görev türünün SLA süresi kullanılır.
+type CreateTaskInput struct {
+	Type         model.TaskType
+	MotorbikeID  int64
+	TargetZoneID *int64 // yalnızca konum değişikliği görevlerinde
+	Note         string
+	AssigneeID   *int64
+	DueAt        *time.Time
+	CreatedBy    *int64
+}
+
+// Create motor için görev açar. Görevin konumu motorun bilinen son konumudur.
+func (s *TaskService) Create(ctx context.Context, input CreateTaskInput) (*model.Task, error) {
+	if !input.Type.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz görev türü")
+	}
+	if err := s.checkTarget(ctx, input.Type, input.TargetZoneID); err != nil {
+		return nil, err
+	}
+	if err := s.checkOperator(ctx, input.AssigneeID); err != nil {
+		return nil, err
+	}
+	if input.DueAt != nil && !input.DueAt.After(time.Now()) {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Son tarih gelecekte olmalıdır")
+	}
+
+	motorbike, err := s.motorRepo.GetByID(ctx, input.MotorbikeID)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+
+	task := &model.Task{
+		Type:         input.Type,
+		Source:       model.TaskSourceManual,
+		TargetZoneID: input.TargetZoneID,
+		Note:         strings.TrimSpace(input.Note),
+		AssigneeID:   input.AssigneeID,
+		CreatedBy:    input.CreatedBy,
+	}
+	if input.DueAt != nil {
+		task.DueAt = *input.DueAt
+	}
+	if err = s.create(ctx, motorbike, task); err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "tasks.create", "ops/tasks", task.ID, nil, task)
+	return task, nil
+}
+
+// create motorun aynı türde açık görevi yoksa görevi kaydeder. DueAt boşsa SLA'ya göre belirlenir.
+func (s *TaskService) create(ctx context.Context, motorbike *model.Motorbike, task *model.Task) error {
+	task.MotorbikeID = motorbike.ID
+	task.Latitude = motorbike.LocationLatitude
+	task.Longitude = motorbike.LocationLongitude
+	task.Status = model.TaskOpen
+	if task.DueAt.IsZero() {
+		task.DueAt = time.Now().Add(s.slas[task.Type])
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		// Aynı motor için eşzamanlı görev açılmasını önlemek amacıyla motor satırı kilitlenir
+		if _, err := s.motorRepo.GetByIDForUpdate(ctx, motorbike.ID); err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		latest, err := s.taskRepo.GetLatestByMotorbikeID(ctx, motorbike.ID, task.Type)
+		if err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		if latest != nil && !latest.Status.IsClosed() {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Bu motor için aynı türde açık bir görev var (#%d)", latest.ID))
+		}
+		if err = s.taskRepo.Create(ctx, task); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Görev oluşturulamadı")
+		}
+		return nil
+	})
+}
+
+// UpdateTaskInput kapanmamış görevin değiştirilebilen alanları; DueAt boşsa son tarih değişmez
+type UpdateTaskInput struct {
+	ID           int64
+	TargetZoneID *int64
+	Note         string
+	DueAt        time.Time
+}
+
+func (s *TaskService) Update(ctx context.Context, input UpdateTaskInput) (*model.Task, error) {
+	return s.update(ctx, input.ID, "tasks.update", func(ctx context.Context, task *model.Task) error {
+		if task.Status.IsClosed() {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Kapanmış görev değiştirilemez")
+		}
+		if err := s.checkTarget(ctx, task.Type, input.TargetZoneID); err != nil {
+			return err
+		}
+		task.TargetZoneID = input.TargetZoneID
+		task.Note = strings.TrimSpace(input.Note)
+		if !input.DueAt.IsZero() {
+			task.DueAt = input.DueAt
+		}
+		return nil
+	})
+}
+
+// Assign açık görevi bir operatöre atar; görevi yalnızca atanan operatör üstlenebilir
+func (s *TaskService) Assign(ctx context.Context, id, operatorID int64) (*model.Task, error) {
+	if err := s.checkOperator(ctx, &operatorID); err != nil {
+		return nil, err
+	}
+	return s.update(ctx, id, "tasks.assign", func(ctx context.Context, task *model.Task) error {
+		if task.Status != model.TaskOpen {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Yalnızca açık görevler atanabilir")
+		}
+		task.AssigneeID = &operatorID
+		return nil
+	})
+}
+
+// Claim operatörün görevi üstlenmesini sağlar. Başka bir operatöre atanmış görev üstlenilemez.
+func (s *TaskService) Claim(ctx context.Context, id, operatorID int64) (*model.Task, error) {
+	return s.update(ctx, id, "tasks.claim", func(ctx context.Context, task *model.Task) error {
+		if !task.Status.CanTransitionTo(model.TaskClaimed) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Görev üstlenilemez, durumu: "+string(task.Status))
+		}
+		if task.AssigneeID != nil && *task.AssigneeID != operatorID {
+			return errorx.WrapMsg(errorx.ErrForbidden, "Görev başka bir operatöre atanmış")
+		}
+		now := time.Now()
+		task.Status = model.TaskClaimed
+		task.AssigneeID = &operatorID
+		task.ClaimedAt = &now
+		return nil
+	})
+}
+
+// Release üstlenilen görevi bırakır; görev tekrar tüm operatörlere açılır
+func (s *TaskService) Release(ctx context.Context, id, operatorID int64) (*model.Task, error) {
+	return s.update(ctx, id, "tasks.release", func(ctx context.Context, task *model.Task) error {
+		if err := checkClaimedBy(task, operatorID); err != nil {
+			return err
+		}
+		task.Status = model.TaskOpen
+		task.AssigneeID = nil
+		task.ClaimedAt = nil
+		return nil
+	})
+}
+
+// CompleteTaskInput görevin tamamlandığını gösteren en az bir fotoğraf içermelidir
+type CompleteTaskInput struct {
+	ID         int64
+	OperatorID int64
+	Note       string
+	Photos     [][]byte
+}
+
+// Complete operatörün üstlendiği görevi fotoğraflı kanıtla kapatır. Hedef alanı olan konum değişikliği görevi, motorun
+// bilinen son konumu bu alanın içinde değilse tamamlanamaz. Herhangi bir fotoğraf geçersizse görev kapanmaz.
+func (s *TaskService) Complete(ctx context.Context, input CompleteTaskInput) (*model.Task, error) {
+	if len(input.Photos) == 0 {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Görevin tamamlandığını gösteren en az bir fotoğraf eklenmelidir")
+	}
+	if len(input.Photos) > s.maxPhotos {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("En fazla %d fotoğraf eklenebilir", s.maxPhotos))
+	}
+
+	photos, keys, err := s.storePhotos(ctx, input.ID, input.Photos)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.update(ctx, input.ID, "tasks.complete", func(ctx context.Context, task *model.Task) error {
+		if err := checkClaimedBy(task, input.OperatorID); err != nil {
+			return err
+		}
+		if err := s.checkArrived(ctx, task); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		task.Status = model.TaskCompleted
+		task.ResolutionNote = strings.TrimSpace(input.Note)
+		task.ClosedBy = &input.OperatorID
+		task.ClosedAt = &now
+
+		for i := range photos {
+			photos[i].TaskID = task.ID
+		}
+		if err := s.taskRepo.CreatePhotos(ctx, photos); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Görev fotoğrafları kaydedilemedi")
+		}
+		task.Photos = photos
+		return nil
+	})
+	if err != nil {
+		s.remove(ctx, keys...)
+		return nil, err
+	}
+	return task, nil
+}
+
+// Cancel kapanmamış görevi iptal eder
+func (s *TaskService) Cancel(ctx context.Context, id, adminID int64, note string) (*model.Task, error) {
+	return s.update(ctx, id, "tasks.cancel", func(ctx context.Context, task *model.Task) error {
+		if !task.Status.CanTransitionTo(model.TaskCancelled) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Görev iptal edilemez, durumu: "+string(task.Status))
+		}
+		now := time.Now()
+		task.Status = model.TaskCancelled
+		task.ResolutionNote = strings.TrimSpace(note)
+		task.ClosedBy = &adminID
+		task.ClosedAt = &now
+		return nil
+	})
+}
+
+func (s *TaskService) Get(ctx context.Context, id int64) (*model.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Görev bulunamadı")
+	}
+	return task, nil
+}
+
+// ListByOperator operatöre atanmış veya operatörün üstlendiği kapanmamış görevleri getirir
+func (s *TaskService) ListByOperator(ctx context.Context, operatorID int64) ([]model.Task, error) {
+	tasks, err := s.taskRepo.ListByAssigneeID(ctx, operatorID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return tasks, nil
+}
+
+func (s *TaskService) List(ctx context.Context, filter model.TaskFilter, pagination *query.Pagination) ([]model.Task, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz görev durumu")
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz görev türü")
+	}
+	tasks, err := s.taskRepo.List(ctx, filter, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return tasks, nil
+}
+
+// NearbyTask arama noktasına olan mesafesiyle birlikte görev
+type NearbyTask struct {
+	Task           model.Task
+	DistanceMeters float64
+}
+
+// FindNearby merkez noktaya radiusMeters içindeki, operatörün üstlenebileceği açık görevleri yakından uzağa sıralı döner.
+// Başka bir operatöre atanmış görevler listelenmez.
+func (s *TaskService) FindNearby(ctx context.Context, operatorID int64, center geo.Point, radiusMeters float64, limit int) ([]NearbyTask, error) {
+	candidates, err := s.taskRepo.ListOpenInBounds(ctx, geo.BoundingBoxAround(center, radiusMeters))
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+
+	nearby := make([]NearbyTask, 0, len(candidates))
+	for _, task := range candidates {
+		if task.AssigneeID != nil && *task.AssigneeID != operatorID {
+			continue
+		}
+		distance := geo.HaversineMeters(center, geo.Point{Lat: task.Latitude, Lng: task.Longitude})
+		if distance <= radiusMeters {
+			nearby = append(nearby, NearbyTask{Task: task, DistanceMeters: distance})
+		}
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return nearby[i].DistanceMeters < nearby[j].DistanceMeters
+	})
+	if limit > 0 && len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// Generate müsait motorlar için otomatik görevleri açar: bataryası LowBatteryPercent'in altına düşen motora batarya
+// değişimi, IdleAfter boyunca kiralanmayan motora konum değişikliği görevi. Motorun aynı türde açık görevi varsa veya
+// son görevi kapandıktan sonra durumu değişmediyse yeni görev açılmaz.
+func (s *TaskService) Generate(ctx context.Context) error {
+	motorbikes, err := s.motorRepo.GetMotorsForStatus(ctx, string(model.BikeAvailable))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var ridden []int64
+	if s.idleAfter > 0 {
+		if ridden, err = s.rideRepo.ListMotorbikeIDsRiddenSince(ctx, now.Add(-s.idleAfter)); err != nil {
+			return err
+		}
+	}
+
+	for i := range motorbikes {
+		motorbike := &motorbikes[i]
+		if s.lowBatteryPercent > 0 && motorbike.BatteryLevel != nil && *motorbike.BatteryLevel < s.lowBatteryPercent {
+			// Görev kapandıktan sonra cihazdan yeni ölçüm gelmediyse eski seviyeye göre tekrar görev açılmaz
+			var since time.Time
+			if motorbike.LastSeenAt != nil {
+				since = *motorbike.LastSeenAt
+			}
+			s.generate(ctx, motorbike, model.TaskBatterySwap, model.TaskSourceLowBattery, since,
+				fmt.Sprintf("Batarya seviyesi %%%d", *motorbike.BatteryLevel))
+		}
+		if s.idleAfter > 0 && motorbike.CreatedAt.Before(now.Add(-s.idleAfter)) && !slices.Contains(ridden, motorbike.ID) {
+			// Boşta kalma süresi görevin kapandığı andan itibaren tekrar sayılır
+			s.generate(ctx, motorbike, model.TaskRebalance, model.TaskSourceIdle, now.Add(-s.idleAfter),
+				fmt.Sprintf("%d saattir kiralanmadı", int(s.idleAfter.Hours())))
+		}
+	}
+	return nil
+}
+
+// generate motorun kapanmamış veya since'den sonra kapanmış aynı türde görevi yoksa otomatik görev açar
+func (s *TaskService) generate(ctx context.Context, motorbike *model.Motorbike, taskType model.TaskType, source model.TaskSource, since time.Time, note string) {
+	latest, err := s.taskRepo.GetLatestByMotorbikeID(ctx, motorbike.ID, taskType)
+	if err != nil {
+		logger.Error("Motorun son görevi alınamadı (motorbike_id=%d, type=%s): %v", motorbike.ID, taskType, err)
+		return
+	}
+	if latest != nil && (!latest.Status.IsClosed() || (latest.ClosedAt != nil && latest.ClosedAt.After(since))) {
+		return
+	}
+
+	task := &model.Task{Type: taskType, Source: source, Note: note}
+	if err = s.create(ctx, motorbike, task); err != nil {
+		logger.Error("Otomatik görev açılamadı (motorbike_id=%d, type=%s): %v", motorbike.ID, taskType, err)
+		return
+	}
+	logger.Info("Otomatik görev açıldı (task_id=%d, motorbike_id=%d, type=%s)", task.ID, motorbike.ID, taskType)
+}
+
+// RunGenerateWorker ctx iptal edilene kadar belirtilen aralıklarla otomatik görevleri açar
+func (s *TaskService) RunGenerateWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Generate(ctx); err != nil {
+				logger.Error("Otomatik görev kontrolü hatası: %v", err)
+			}
+		}
+	}
+}
+
+// update görevi satır kilidiyle alır ve apply ile değiştirir. apply hata dönerse hiçbir değişiklik yazılmaz;
+// değişiklik commit edildikten sonra action ile denetim kaydına yazılır.
+func (s *TaskService) update(ctx context.Context, id int64, action string, apply func(ctx context.Context, task *model.Task) error) (*model.Task, error) {
+	var task *model.Task
+	var before model.Task
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.taskRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Görev bulunamadı")
+		}
+		before = *task
+		if err = apply(ctx, task); err != nil {
+			return err
+		}
+		if err = s.taskRepo.Update(ctx, task); err != nil {
+			return errorx.Wrap(errorx.ErrInternal, err, "Görev güncellenemedi")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, action, "ops/tasks", task.ID, before, task)
+	return task, nil
+}
+
+// checkClaimedBy görevin operatör tarafından üstlenilmiş olduğunu kontrol eder
+func checkClaimedBy(task *model.Task, operatorID int64) error {
+	if task.Status != model.TaskClaimed {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Görev üstlenilmemiş, durumu: "+string(task.Status))
+	}
+	if task.AssigneeID == nil || *task.AssigneeID != operatorID {
+		return errorx.WrapMsg(errorx.ErrForbidden, "Görev başka bir operatör tarafından üstlenilmiş")
+	}
+	return nil
+}
+
+// checkTarget hedef alanın yalnızca konum değişikliği görevinde verildiğini ve aktif bir alan olduğunu kontrol eder
+func (s *TaskService) checkTarget(ctx context.Context, taskType model.TaskType, zoneID *int64) error {
+	if zoneID == nil {
+		return nil
+	}
+	if taskType != model.TaskRebalance {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Hedef alan yalnızca konum değişikliği görevlerinde verilebilir")
+	}
+	zone, err := s.zoneRepo.GetByID(ctx, *zoneID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Hedef alan bulunamadı")
+	}
+	if !zone.IsActive {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Hedef alan aktif değil")
+	}
+	return nil
+}
+
+// checkArrived hedef alanı olan görevde motorun bilinen son konumunun alan içinde olduğunu kontrol eder
+func (s *TaskService) checkArrived(ctx context.Context, task *model.Task) error {
+	if task.TargetZoneID == nil {
+		return nil
+	}
+	zone, err := s.zoneRepo.GetByID(ctx, *task.TargetZoneID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Hedef alan bulunamadı")
+	}
+	area, err := geo.ParseGeoJSON(zone.Geometry)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	motorbike, err := s.motorRepo.GetByID(ctx, task.MotorbikeID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+	}
+	if !area.Contains(geo.Point{Lat: motorbike.LocationLatitude, Lng: motorbike.LocationLongitude}) {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("Motor hedef alanda (%s) görünmüyor; cihazın konumu güncellendikten sonra tekrar deneyin", zone.Name))
+	}
+	return nil
+}
+
+// checkOperator atanan kullanıcının rolünün saha görevi yapma yetkisi olduğunu kontrol eder
+func (s *TaskService) checkOperator(ctx context.Context, userID *int64) error {
+	if userID == nil {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Operatör bulunamadı")
+	}
+	ok, err := s.permissions.HasPermission(ctx, user.Role, model.PermTasksWork)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "Görev yalnızca operatöre atanabilir")
+	}
+	return nil
+}
+
+// storePhotos fotoğrafları doğrulayıp temizler ve BlobStore'a yazar. Hata olursa yazılan dosyalar silinir.
+func (s *TaskService) storePhotos(ctx context.Context, taskID int64, files [][]byte) ([]model.TaskPhoto, []string, error) {
+	images := make([]*storage.Image, len(files))
+	for i, data := range files {
+		img, err := storage.ProcessImage(data, s.maxPhotoBytes, ridePhotoThumbSize)
+		switch {
+		case errors.Is(err, storage.ErrTooLarge):
+			return nil, nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf en fazla %d MB olabilir", i+1, s.maxPhotoBytes>>20))
+		case errors.Is(err, storage.ErrInvalidImage):
+			return nil, nil, errorx.WrapMsg(errorx.ErrInvalidRequest, fmt.Sprintf("%d. fotoğraf JPEG veya PNG formatında olmalıdır", i+1))
+		case err != nil:
+			return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		images[i] = img
+	}
+
+	photos := make([]model.TaskPhoto, 0, len(images))
+	var keys []string
+	for _, img := range images {
+		name, err := randomName()
+		if err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.WrapErr(errorx.ErrInternal, err)
+		}
+		photo := model.TaskPhoto{
+			Key:          fmt.Sprintf("tasks/%d/%s%s", taskID, name, img.Ext),
+			ThumbnailKey: fmt.Sprintf("tasks/%d/%s_thumb.jpg", taskID, name),
+			ContentType:  img.ContentType,
+			Size:         int64(len(img.Data)),
+			Width:        img.Width,
+			Height:       img.Height,
+		}
+		if err = s.store.Put(ctx, photo.Key, bytes.NewReader(img.Data), photo.Size, photo.ContentType); err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.Key)
+		if err = s.store.Put(ctx, photo.ThumbnailKey, bytes.NewReader(img.Thumbnail), int64(len(img.Thumbnail)), "image/jpeg"); err != nil {
+			s.remove(ctx, keys...)
+			return nil, nil, errorx.Wrap(errorx.ErrInternal, err, "Fotoğraf kaydedilemedi")
+		}
+		keys = append(keys, photo.ThumbnailKey)
+		photos = append(photos, photo)
+	}
+	return photos, keys, nil
+}
+
+// remove kaydı oluşturulamayan fotoğrafların dosyalarını siler, silinemeyenler loglanır
+func (s *TaskService) remove(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error("Kaydedilemeyen fotoğraf dosyası silinemedi (%s): %v", key, err)
+		}
+	}
+}
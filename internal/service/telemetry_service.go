@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+)
+
+const (
+	// Cihaz saatindeki küçük kaymalara izin verilir, daha ileri tarihli ölçümler reddedilir
+	maxTelemetryClockSkew = 5 * time.Minute
+	maxTelemetryRange     = 7 * 24 * time.Hour
+	maxTelemetryResults   = 5000
+)
+
+type TelemetryService struct {
+	telemetryRepo repository.ITelemetryRepository
+	motorRepo     repository.IMotorbikeRepository
+	rideRepo      repository.IRideRepository
+	txManager     repository.ITransactionManager
+}
+
+func NewTelemetryService(telemetryRepo repository.ITelemetryRepository, motorRepo repository.IMotorbikeRepository, rideRepo repository.IRideRepository, txManager repository.ITransactionManager) *TelemetryService {
+	return &TelemetryService{
+		telemetryRepo: telemetryRepo,
+		motorRepo:     motorRepo,
+		rideRepo:      rideRepo,
+		txManager:     txManager,
+	}
+}
+
+// Ingest cihazdan gelen ölçüm paketini kaydeder ve motorun anlık konum, kilit ve batarya durumunu
+// paketteki en yeni ölçüme göre günceller. Motorun bilinen son ölçümünden eski ölçümler
+// kaydedilir ama anlık durumu değiştirmez (paketler sırasız gelebilir).
+// Motorun devam eden bir sürüşü varsa sürüş başladıktan sonraki ölçümler sürüşün rotasına da eklenir.
+func (s *TelemetryService) Ingest(ctx context.Context, motorbikeID int64, samples []model.MotorbikeTelemetry) error {
+	now := time.Now().UTC()
+	var latest *model.MotorbikeTelemetry
+
+	for i := range samples {
+		sample := &samples[i]
+		if sample.RecordedAt.After(now.Add(maxTelemetryClockSkew)) {
+			return errorx.WrapMsg(errorx.ErrInvalidRequest, "Ölçüm zamanı ileri bir tarih olamaz")
+		}
+		sample.MotorbikeID = motorbikeID
+		sample.RecordedAt = sample.RecordedAt.UTC()
+		if latest == nil || sample.RecordedAt.After(latest.RecordedAt) {
+			latest = sample
+		}
+	}
+	if latest == nil {
+		return errorx.WrapMsg(errorx.ErrInvalidRequest, "En az bir ölçüm gönderilmelidir")
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.telemetryRepo.CreateBatch(ctx, samples); err != nil {
+			return errorx.WrapErr(errorx.ErrInternal, err)
+		}
+
+		if err := s.recordRidePoints(ctx, motorbikeID, samples); err != nil {
+			return err
+		}
+
+		motorbike, err := s.motorRepo.GetByIDForUpdate(ctx, motorbikeID)
+		if err != nil {
+			return errorx.WrapMsg(errorx.ErrNotFound, "Motorbike bulunamadı")
+		}
+		if motorbike.LastSeenAt != nil && !latest.RecordedAt.After(*motorbike.LastSeenAt) {
+			return nil
+		}
+
+		columns := []string{"location_latitude", "location_longitude", "last_seen_at"}
+		motorbike.LocationLatitude = latest.Latitude
+		motorbike.LocationLongitude = latest.Longitude
+		motorbike.LastSeenAt = &latest.RecordedAt
+		if latest.LockStatus != "" {
+			motorbike.LockStatus = latest.LockStatus
+			columns = append(columns, "lock_status")
+		}
+		if latest.BatteryLevel != nil {
+			motorbike.BatteryLevel = latest.BatteryLevel
+			columns = append(columns, "battery_level")
+		}
+		if latest.OdometerMeters != nil {
+			motorbike.OdometerMeters = latest.OdometerMeters
+			columns = append(columns, "odometer_meters")
+		}
+
+		if err = s.motorRepo.UpdateColumns(ctx, motorbike, columns...); err != nil {
+			return errorx.WrapMsg(errorx.ErrInternal, "Motor durumu güncellenirken hata oluştu!")
+		}
+		return nil
+	})
+	if err != nil {
+		return errorx.FromError(errorx.ErrInternal, err)
+	}
+	return nil
+}
+
+// recordRidePoints ölçümleri motorun devam eden sürüşünün rotasına ekler
+func (s *TelemetryService) recordRidePoints(ctx context.Context, motorbikeID int64, samples []model.MotorbikeTelemetry) error {
+	ride, err := s.rideRepo.GetActiveByMotorbikeID(ctx, motorbikeID)
+	if err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if ride == nil {
+		return nil
+	}
+
+	points := make([]model.RidePoint, 0, len(samples))
+	for _, sample := range samples {
+		if sample.RecordedAt.Before(ride.StartTime) {
+			continue
+		}
+		speed := sample.SpeedKmh
+		points = append(points, model.RidePoint{
+			RideID:     ride.ID,
+			RecordedAt: sample.RecordedAt,
+			Latitude:   sample.Latitude,
+			Longitude:  sample.Longitude,
+			SpeedKmh:   &speed,
+			Source:     model.RoutePointFromDevice,
+		})
+	}
+
+	if err = s.rideRepo.CreateRoutePoints(ctx, points); err != nil {
+		return errorx.Wrap(errorx.ErrInternal, err, "Sürüş rotası kaydedilemedi")
+	}
+	return nil
+}
+
+// List motorun verilen zaman aralığındaki ölçümlerini getirir. Aralık en fazla 7 gün olabilir.
+func (s *TelemetryService) List(ctx context.Context, motorbikeID int64, from, to time.Time) ([]model.MotorbikeTelemetry, error) {
+	if !from.Before(to) {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "from, to'dan önce olmalıdır")
+	}
+	if to.Sub(from) > maxTelemetryRange {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Zaman aralığı en fazla 7 gün olabilir")
+	}
+
+	samples, err := s.telemetryRepo.ListByMotorbikeID(ctx, motorbikeID, from, to, maxTelemetryResults)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return samples, nil
+}
@@ -9,11 +9,13 @@ import (
 
 type UserService struct {
 	userRepo repository.IUserRepository
+	audit    Auditor
 }
 
-func NewUserService(u repository.IUserRepository) *UserService {
+func NewUserService(u repository.IUserRepository, audit Auditor) *UserService {
 	return &UserService{
 		userRepo: u,
+		audit:    audit,
 	}
 }
 
@@ -31,6 +33,7 @@ func (s *UserService) Create(ctx context.Context, user model.User) error {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
 
+	s.audit.RecordChange(ctx, "users.create", "users", user.ID, nil, user)
 	return nil
 }
 
@@ -75,12 +78,13 @@ func (s *UserService) Update(ctx context.Context, id int64, updatedUser model.Us
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
 
+	s.audit.RecordChange(ctx, "users.update", "users", id, user, updatedUser)
 	return nil
 }
 
 func (s *UserService) Delete(ctx context.Context, id int64) error {
 	// Önce kullanıcının var olup olmadığını kontrol et
-	_, err := s.userRepo.GetByID(ctx, id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return errorx.WrapMsg(errorx.ErrNotFound, "Silinecek kullanıcı bulunamadı")
 	}
@@ -89,5 +93,6 @@ func (s *UserService) Delete(ctx context.Context, id int64) error {
 		return errorx.WrapErr(errorx.ErrInternal, err)
 	}
 
+	s.audit.RecordChange(ctx, "users.delete", "users", id, user, nil)
 	return nil
 }
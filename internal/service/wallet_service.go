@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+)
+
+// WalletService kullanıcı cüzdanlarını çift taraflı defter üzerinden yönetir. Her para hareketi bir kullanıcı
+// cüzdanı ile bir sistem hesabı arasında, toplamı sıfır olan iki kayıtla yazılır.
+type WalletService struct {
+	ledgerRepo repository.ILedgerRepository
+	userRepo   repository.IUserRepository
+	txManager  repository.ITransactionManager
+	audit      Auditor
+}
+
+func NewWalletService(ledgerRepo repository.ILedgerRepository, userRepo repository.IUserRepository, txManager repository.ITransactionManager, audit Auditor) *WalletService {
+	return &WalletService{ledgerRepo: ledgerRepo, userRepo: userRepo, txManager: txManager, audit: audit}
+}
+
+// WalletPosting cüzdana yazılacak para hareketi. Amount cüzdan açısından işaretlidir: pozitif tutar bakiyeyi artırır.
+type WalletPosting struct {
+	UserID      int64
+	Kind        model.LedgerTransactionKind
+	Amount      int64
+	Currency    string
+	Description string
+	RideID      *int64
+	CreatedBy   *int64
+}
+
+// counterAccounts işlem türüne göre paranın geldiği veya gittiği sistem hesabı
+var counterAccounts = map[model.LedgerTransactionKind]string{
+	model.LedgerTopUp:        model.SystemAccountCash,
+	model.LedgerRideCharge:   model.SystemAccountRideRevenue,
+	model.LedgerRefund:       model.SystemAccountRideRevenue,
+	model.LedgerPromoCredit:  model.SystemAccountPromotions,
+	model.LedgerAdjustment:   model.SystemAccountAdjustments,
+	model.LedgerPassPurchase: model.SystemAccountPassRevenue,
+}
+
+// GetWallet kullanıcının bakiyesini getirir. Hiç hareketi olmayan kullanıcının bakiyesi sıfırdır.
+func (s *WalletService) GetWallet(ctx context.Context, userID int64) (*model.Wallet, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+	}
+
+	account, err := s.ledgerRepo.GetUserAccount(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if account == nil {
+		return &model.Wallet{UserID: userID, Currency: money.DefaultCurrency}, nil
+	}
+
+	balance, err := s.ledgerRepo.GetBalance(ctx, account.ID)
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Bakiye hesaplanamadı")
+	}
+	return &model.Wallet{UserID: userID, Balance: balance, Currency: account.Currency}, nil
+}
+
+// ListTransactions kullanıcının cüzdan hareketlerini yeniden eskiye sayfalı getirir
+func (s *WalletService) ListTransactions(ctx context.Context, userID int64, pagination *query.Pagination) ([]model.WalletTransaction, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+	}
+
+	account, err := s.ledgerRepo.GetUserAccount(ctx, userID)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	if account == nil {
+		return []model.WalletTransaction{}, nil
+	}
+
+	transactions, err := s.ledgerRepo.ListAccountTransactions(ctx, account.ID, pagination)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return transactions, nil
+}
+
+// Adjust admin'in cüzdana bakiye yüklemesi, iade, promosyon kredisi veya düzeltme yazmasıdır.
+// Düzeltme dışındaki işlemler bakiyeyi yalnızca artırabilir; bakiyeyi eksiye düşüren düzeltme reddedilir.
+func (s *WalletService) Adjust(ctx context.Context, posting WalletPosting) (*model.LedgerTransaction, error) {
+	switch posting.Kind {
+	case model.LedgerTopUp, model.LedgerRefund, model.LedgerPromoCredit:
+		if posting.Amount <= 0 {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Tutar sıfırdan büyük olmalı")
+		}
+	case model.LedgerAdjustment:
+		if posting.Amount == 0 {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Düzeltme tutarı sıfır olamaz")
+		}
+	default:
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Geçersiz işlem türü")
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, posting.UserID); err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Kullanıcı bulunamadı")
+	}
+
+	var transaction *model.LedgerTransaction
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.post(ctx, posting, posting.Kind != model.LedgerAdjustment)
+		return err
+	})
+	if err != nil {
+		return nil, errorx.FromError(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "wallets.adjust", "users", posting.UserID, nil, transaction)
+	return transaction, nil
+}
+
+// ChargeRide bitirilen sürüşün ücretini cüzdandan düşer. Çağıranın transaction'ı içinde çalışır,
+// böylece sürüş ancak ücret deftere yazılırsa kapanır. Bakiye yetersizse cüzdan eksiye düşer.
+func (s *WalletService) ChargeRide(ctx context.Context, ride *model.Ride) error {
+	if ride.Cost <= 0 {
+		return nil
+	}
+	rideID := ride.ID
+	_, err := s.post(ctx, WalletPosting{
+		UserID:      ride.UserID,
+		Kind:        model.LedgerRideCharge,
+		Amount:      -ride.Cost,
+		Currency:    ride.Currency,
+		Description: "Sürüş ücreti",
+		RideID:      &rideID,
+	}, true)
+	return err
+}
+
+// RecordCardPayment sürüş ücretinin cüzdanın karşılamadığı ve karttan tahsil edilen kısmını cüzdana yükleme olarak yazar.
+// Çağıranın transaction'ı içinde çalışır.
+func (s *WalletService) RecordCardPayment(ctx context.Context, userID, rideID, amount int64, currency string) error {
+	_, err := s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerTopUp,
+		Amount:      amount,
+		Currency:    currency,
+		Description: "Kart ile ödeme",
+		RideID:      &rideID,
+	}, true)
+	return err
+}
+
+// ReverseCardPayment sağlayıcının sonradan reddettiği kart tahsilatını ters kayıtla geri alır; cüzdan eksiye düşebilir
+func (s *WalletService) ReverseCardPayment(ctx context.Context, userID, rideID, amount int64, currency string) error {
+	_, err := s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerTopUp,
+		Amount:      -amount,
+		Currency:    currency,
+		Description: "Kart ödemesi geri alındı",
+		RideID:      &rideID,
+	}, true)
+	return err
+}
+
+// RefundRide itirazı onaylanan sürüşün iadesini admin adına deftere yazar ve iade işlemini döner. Tutar sürüş gelirinden
+// cüzdana iade olarak girer; karta iade edildiyse aynı tutar kart ödemesinin ters kaydıyla cüzdandan çıkar, böylece bakiye
+// değişmez. Çağıranın transaction'ı içinde çalışır.
+func (s *WalletService) RefundRide(ctx context.Context, userID, rideID, amount int64, currency string, toCard bool, adminID int64) (*model.LedgerTransaction, error) {
+	transaction, err := s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerRefund,
+		Amount:      amount,
+		Currency:    currency,
+		Description: "Sürüş ücreti iadesi",
+		RideID:      &rideID,
+		CreatedBy:   &adminID,
+	}, true)
+	if err != nil || !toCard {
+		return transaction, err
+	}
+
+	if _, err = s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerTopUp,
+		Amount:      -amount,
+		Currency:    currency,
+		Description: "Kart iadesi",
+		RideID:      &rideID,
+		CreatedBy:   &adminID,
+	}, true); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// ChargePass abonelik ücretini cüzdandan düşer. Ücretin karttan tahsil edilen kısmı (cardAmount) önce cüzdana
+// yükleme olarak yazılır; bakiye ücreti karşılamıyorsa işlem reddedilir. Çağıranın transaction'ı içinde çalışır.
+func (s *WalletService) ChargePass(ctx context.Context, userID, amount, cardAmount int64, currency, description string) (*model.LedgerTransaction, error) {
+	if cardAmount > 0 {
+		if _, err := s.post(ctx, WalletPosting{
+			UserID:      userID,
+			Kind:        model.LedgerTopUp,
+			Amount:      cardAmount,
+			Currency:    currency,
+			Description: "Kart ile ödeme",
+		}, true); err != nil {
+			return nil, err
+		}
+	}
+	return s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerPassPurchase,
+		Amount:      -amount,
+		Currency:    currency,
+		Description: description,
+	}, false)
+}
+
+// CreditPromotion kampanya veya davet kredisini cüzdana yükler. Çağıranın transaction'ı içinde çalışır.
+func (s *WalletService) CreditPromotion(ctx context.Context, userID, amount int64, description string, rideID *int64) error {
+	if amount <= 0 {
+		return nil
+	}
+	_, err := s.post(ctx, WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerPromoCredit,
+		Amount:      amount,
+		Description: description,
+		RideID:      rideID,
+	}, true)
+	return err
+}
+
+// post işlemi cüzdan ve karşı sistem hesabına yazar. allowNegative false ise bakiyeyi eksiye düşüren işlem reddedilir.
+// Cüzdan satırı kilitlendiği için aynı cüzdana yazan işlemler sırayla çalışır.
+func (s *WalletService) post(ctx context.Context, posting WalletPosting, allowNegative bool) (*model.LedgerTransaction, error) {
+	currency := posting.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	wallet, err := s.ledgerRepo.EnsureUserAccount(ctx, posting.UserID, currency)
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Cüzdan hesabı açılamadı")
+	}
+	if wallet.Currency != currency {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Cüzdan para birimi işlemle uyuşmuyor")
+	}
+
+	if !allowNegative && posting.Amount < 0 {
+		balance, err := s.ledgerRepo.GetBalance(ctx, wallet.ID)
+		if err != nil {
+			return nil, errorx.Wrap(errorx.ErrInternal, err, "Bakiye hesaplanamadı")
+		}
+		if balance+posting.Amount < 0 {
+			return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Bakiye yetersiz")
+		}
+	}
+
+	counter, err := s.ledgerRepo.EnsureSystemAccount(ctx, counterAccounts[posting.Kind], currency)
+	if err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Sistem hesabı açılamadı")
+	}
+
+	transaction := &model.LedgerTransaction{
+		Kind:        posting.Kind,
+		Currency:    currency,
+		Description: posting.Description,
+		RideID:      posting.RideID,
+		CreatedBy:   posting.CreatedBy,
+		Entries: []model.LedgerEntry{
+			{AccountID: wallet.ID, Amount: posting.Amount},
+			{AccountID: counter.ID, Amount: -posting.Amount},
+		},
+	}
+	if !transaction.IsBalanced() {
+		return nil, errorx.WrapMsg(errorx.ErrInvalidRequest, "Dengesiz defter kaydı")
+	}
+	if err = s.ledgerRepo.CreateTransaction(ctx, transaction); err != nil {
+		return nil, errorx.Wrap(errorx.ErrInternal, err, "Cüzdan hareketi kaydedilemedi")
+	}
+	return transaction, nil
+}
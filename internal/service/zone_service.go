@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+type ZoneService struct {
+	zoneRepo repository.IZoneRepository
+	audit    Auditor
+}
+
+func NewZoneService(repo repository.IZoneRepository, audit Auditor) *ZoneService {
+	return &ZoneService{zoneRepo: repo, audit: audit}
+}
+
+func (s *ZoneService) Create(ctx context.Context, zone *model.Zone) error {
+	if _, err := geo.ParseGeoJSON(zone.Geometry); err != nil {
+		return errorx.Wrap(errorx.ErrInvalidRequest, err, "Alan geometrisi geçersiz")
+	}
+	if err := s.zoneRepo.Create(ctx, zone); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "zones.create", "zones", zone.ID, nil, zone)
+	return nil
+}
+
+func (s *ZoneService) GetByID(ctx context.Context, id int64) (*model.Zone, error) {
+	zone, err := s.zoneRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.WrapMsg(errorx.ErrNotFound, "Alan bulunamadı")
+	}
+	return zone, nil
+}
+
+func (s *ZoneService) Update(ctx context.Context, zone model.Zone) error {
+	if _, err := geo.ParseGeoJSON(zone.Geometry); err != nil {
+		return errorx.Wrap(errorx.ErrInvalidRequest, err, "Alan geometrisi geçersiz")
+	}
+	current, err := s.GetByID(ctx, zone.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.zoneRepo.Update(ctx, &zone); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "zones.update", "zones", zone.ID, current, zone)
+	return nil
+}
+
+func (s *ZoneService) Delete(ctx context.Context, id int64) error {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.zoneRepo.Delete(ctx, id); err != nil {
+		return errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	s.audit.RecordChange(ctx, "zones.delete", "zones", id, current, nil)
+	return nil
+}
+
+func (s *ZoneService) List(ctx context.Context) ([]model.Zone, error) {
+	zones, err := s.zoneRepo.List(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return zones, nil
+}
+
+// ListActive kullanıcı haritasında gösterilecek aktif alanları getirir
+func (s *ZoneService) ListActive(ctx context.Context) ([]model.Zone, error) {
+	zones, err := s.zoneRepo.ListActive(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return zones, nil
+}
+
+// CheckParking verilen noktada sürüş bitirilirse uygulanacak park kurallarını döner
+func (s *ZoneService) CheckParking(ctx context.Context, point geo.Point) (*ParkingDecision, error) {
+	zones, err := s.zoneRepo.ListActive(ctx)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	decision, err := EvaluateParking(zones, point)
+	if err != nil {
+		return nil, errorx.WrapErr(errorx.ErrInternal, err)
+	}
+	return &decision, nil
+}
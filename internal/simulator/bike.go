@@ -0,0 +1,263 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Fault simüle edilen motora enjekte edilebilen arıza
+type Fault string
+
+const (
+	FaultLowBattery     Fault = "low_battery"     // batarya %10'un altına düşer
+	FaultLostConnection Fault = "lost_connection" // cihaz telemetri göndermez ve komutlara cevap vermez
+	FaultTamper         Fault = "tamper"          // kilit zorlanmış; motor kilitliyken hareket eder ve kilitsiz görünür
+	FaultLockJam        Fault = "lock_jam"        // kilit mekanizması takılı; lock/unlock başarısız olur
+)
+
+var AllFaults = []Fault{FaultLowBattery, FaultLostConnection, FaultTamper, FaultLockJam}
+
+var (
+	ErrOffline     = errors.New("cihaz çevrimdışı")
+	ErrLockJammed  = errors.New("kilit mekanizması takıldı")
+	ErrUnknownBike = errors.New("simülatörde böyle bir motor yok")
+)
+
+const (
+	lowBatteryLevel = 10
+	tamperSpeedKmh  = 4
+	// Tam dolu bataryayla gidilebilen mesafe
+	batteryRangeMeters = 60000
+)
+
+// ParseFault metin olarak verilen arızayı doğrular
+func ParseFault(s string) (Fault, error) {
+	f := Fault(strings.TrimSpace(s))
+	for _, known := range AllFaults {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("bilinmeyen arıza %q", s)
+}
+
+// Bike sanal motor. Kilidi açıkken güzergah boyunca ilerler, ölçüm üretir ve cihaz komutlarını uygular.
+// Tüm metotlar eşzamanlı çağrılara karşı güvenlidir.
+type Bike struct {
+	ID  int64
+	Key string
+
+	mu       sync.Mutex
+	route    Route
+	speedKmh float64
+	distance float64 // güzergah başından itibaren alınan yol
+	odometer float64
+	battery  float64
+	lock     model.LockStatus
+	disabled bool // disable komutuyla kilitlenip bakıma alındı
+	faults   map[Fault]bool
+}
+
+func NewBike(id int64, key string, route Route, speedKmh float64) *Bike {
+	return &Bike{
+		ID:       id,
+		Key:      key,
+		route:    route,
+		speedKmh: speedKmh,
+		battery:  100,
+		lock:     model.Locked,
+		faults:   map[Fault]bool{},
+	}
+}
+
+// SetPosition motoru güzergah başından distance metre ileriye yerleştirir. Aynı güzergahı paylaşan
+// motorlar farklı noktalardan başlatılabilsin diye kullanılır.
+func (b *Bike) SetPosition(distance float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.distance = distance
+}
+
+// SetFault arızayı etkinleştirir
+func (b *Bike) SetFault(f Fault) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.faults[f] = true
+	if f == FaultLowBattery && b.battery > lowBatteryLevel {
+		b.battery = lowBatteryLevel
+	}
+}
+
+// ClearFault arızayı giderir. Boş değer tüm arızaları temizler.
+func (b *Bike) ClearFault(f Fault) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if f == "" {
+		b.faults = map[Fault]bool{}
+		return
+	}
+	delete(b.faults, f)
+}
+
+// Faults etkin arızaları döner
+func (b *Bike) Faults() []Fault {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result []Fault
+	for _, f := range AllFaults {
+		if b.faults[f] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Online cihazın sunucuyla konuşabildiğini belirtir
+func (b *Bike) Online() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.faults[FaultLostConnection]
+}
+
+func (b *Bike) LockStatus() model.LockStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reportedLock()
+}
+
+func (b *Bike) Battery() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int(b.battery)
+}
+
+func (b *Bike) Disabled() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.disabled
+}
+
+// Connect kullanıcının cihazla bağlantı kurmasını simüle eder
+func (b *Bike) Connect() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.faults[FaultLostConnection] {
+		return ErrOffline
+	}
+	return nil
+}
+
+func (b *Bike) Unlock() error {
+	return b.setLock(model.Unlocked)
+}
+
+func (b *Bike) Lock() error {
+	return b.setLock(model.Locked)
+}
+
+func (b *Bike) setLock(status model.LockStatus) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.faults[FaultLostConnection]:
+		return ErrOffline
+	case b.faults[FaultLockJam]:
+		return ErrLockJammed
+	}
+	b.lock = status
+	// Devre dışı bırakılan motor ancak kilidi tekrar açıldığında çalışır
+	if status == model.Unlocked {
+		b.disabled = false
+	}
+	return nil
+}
+
+// Execute sunucudan gelen cihaz komutunu uygular
+func (b *Bike) Execute(commandType model.DeviceCommandType) error {
+	switch commandType {
+	case model.CommandLock:
+		return b.Lock()
+	case model.CommandUnlock:
+		return b.Unlock()
+	case model.CommandBeep:
+		if !b.Online() {
+			return ErrOffline
+		}
+		return nil
+	case model.CommandDisable:
+		if err := b.Lock(); err != nil {
+			return err
+		}
+		b.mu.Lock()
+		b.disabled = true
+		b.mu.Unlock()
+		return nil
+	default:
+		return fmt.Errorf("desteklenmeyen komut %q", commandType)
+	}
+}
+
+// Step motoru dt kadar ilerletir. Kilidi açık motor güzergahta sabit hızla gider,
+// kilidi zorlanmış motor ise kilitli olsa da yavaşça sürüklenir.
+func (b *Bike) Step(dt time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	meters := b.currentSpeed() / 3.6 * dt.Seconds()
+	if meters <= 0 {
+		return
+	}
+	b.distance += meters
+	b.odometer += meters
+
+	b.battery -= meters / batteryRangeMeters * 100
+	if b.faults[FaultLowBattery] && b.battery > lowBatteryLevel {
+		b.battery = lowBatteryLevel
+	}
+	if b.battery < 0 {
+		b.battery = 0
+	}
+}
+
+// Sample motorun anlık durumunu telemetri ölçümü olarak verir
+func (b *Bike) Sample(now time.Time) dto.TelemetrySampleRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	point := b.route.PointAt(b.distance)
+	battery := int(b.battery)
+	odometer := int64(b.odometer)
+	return dto.TelemetrySampleRequest{
+		RecordedAt:     now.UTC(),
+		Latitude:       &point.Lat,
+		Longitude:      &point.Lng,
+		SpeedKmh:       b.currentSpeed(),
+		BatteryLevel:   &battery,
+		OdometerMeters: &odometer,
+		LockStatus:     string(b.reportedLock()),
+	}
+}
+
+func (b *Bike) currentSpeed() float64 {
+	switch {
+	case b.battery <= 0:
+		return 0
+	case b.lock == model.Unlocked:
+		return b.speedKmh
+	case b.faults[FaultTamper]:
+		return tamperSpeedKmh
+	default:
+		return 0
+	}
+}
+
+func (b *Bike) reportedLock() model.LockStatus {
+	if b.faults[FaultTamper] {
+		return model.Unlocked
+	}
+	return b.lock
+}
@@ -0,0 +1,123 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+)
+
+const deviceKeyHeader = "X-Device-Key"
+
+// Client cihaz uç noktalarıyla (/devices) konuşan HTTP istemcisi. Gerçek cihazın kullandığı
+// protokolün aynısıdır: telemetri gönderme, long polling ile komut alma ve komut onayı.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient baseURL API önekini içermelidir, örneğin http://localhost:3005/api/v1
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), http: httpClient}
+}
+
+// envelope response.Response ile aynı yapıdadır
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Message any             `json:"message"`
+}
+
+func (c *Client) SendTelemetry(ctx context.Context, deviceKey string, samples []dto.TelemetrySampleRequest) error {
+	body := dto.TelemetryBatchRequest{Samples: samples}
+	return c.do(ctx, http.MethodPost, "/devices/telemetry", deviceHeaders(deviceKey), body, nil)
+}
+
+// PollCommands bekleyen komutları alır; komut yoksa sunucu isteği wait kadar açık tutar
+func (c *Client) PollCommands(ctx context.Context, deviceKey string, wait time.Duration) ([]dto.DeviceCommandDelivery, error) {
+	var commands []dto.DeviceCommandDelivery
+	path := "/devices/commands?wait=" + strconv.Itoa(int(wait.Seconds()))
+	if err := c.do(ctx, http.MethodGet, path, deviceHeaders(deviceKey), nil, &commands); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+func (c *Client) Ack(ctx context.Context, deviceKey string, commandID int64, success bool, message string) error {
+	body := dto.CommandAckRequest{Success: &success, Message: message}
+	path := fmt.Sprintf("/devices/commands/%d/ack", commandID)
+	return c.do(ctx, http.MethodPost, path, deviceHeaders(deviceKey), body, nil)
+}
+
+// RotateDeviceKey admin token'ı ile motora yeni cihaz anahtarı üretir
+func (c *Client) RotateDeviceKey(ctx context.Context, adminToken string, motorbikeID int64) (string, error) {
+	var result dto.DeviceKeyResponse
+	path := fmt.Sprintf("/motorbike/%d/device-key", motorbikeID)
+	headers := map[string]string{"Authorization": "Bearer " + adminToken}
+	if err := c.do(ctx, http.MethodPost, path, headers, nil, &result); err != nil {
+		return "", err
+	}
+	return result.DeviceKey, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, headers map[string]string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var env envelope
+	if err = json.Unmarshal(data, &env); err != nil {
+		return fmt.Errorf("%s %s: geçersiz yanıt: %w", method, path, err)
+	}
+	if !env.Success {
+		return fmt.Errorf("%s %s: %v", method, path, env.Message)
+	}
+	if out != nil && len(env.Data) > 0 {
+		return json.Unmarshal(env.Data, out)
+	}
+	return nil
+}
+
+func deviceHeaders(deviceKey string) map[string]string {
+	return map[string]string{deviceKeyHeader: deviceKey}
+}
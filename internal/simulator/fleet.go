@@ -0,0 +1,75 @@
+package simulator
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+)
+
+var _ service.LockController = (*Fleet)(nil)
+
+// Fleet simüle edilen motorların kümesi. Sunucuyla aynı süreçte çalışırken LockController olarak
+// doğrudan RideService'e verilebilir; ayrı süreçte cihaz komut kanalı üzerinden konuşulur (bkz. RunBike).
+type Fleet struct {
+	mu    sync.RWMutex
+	bikes map[int64]*Bike
+}
+
+func NewFleet(bikes ...*Bike) *Fleet {
+	f := &Fleet{bikes: map[int64]*Bike{}}
+	for _, b := range bikes {
+		f.bikes[b.ID] = b
+	}
+	return f
+}
+
+func (f *Fleet) Add(b *Bike) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bikes[b.ID] = b
+}
+
+// Bike id'si verilen motoru döner, yoksa nil
+func (f *Fleet) Bike(id int64) *Bike {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.bikes[id]
+}
+
+// Bikes motorları id sırasıyla döner
+func (f *Fleet) Bikes() []*Bike {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	result := make([]*Bike, 0, len(f.bikes))
+	for _, b := range f.bikes {
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func (f *Fleet) Connect(ctx context.Context, motorbikeID int64) error {
+	return f.with(motorbikeID, (*Bike).Connect)
+}
+
+func (f *Fleet) Unlock(ctx context.Context, motorbikeID int64) error {
+	return f.with(motorbikeID, (*Bike).Unlock)
+}
+
+func (f *Fleet) Lock(ctx context.Context, motorbikeID int64) error {
+	return f.with(motorbikeID, (*Bike).Lock)
+}
+
+func (f *Fleet) Disconnect(ctx context.Context, motorbikeID int64) error {
+	return f.with(motorbikeID, (*Bike).Lock)
+}
+
+func (f *Fleet) with(motorbikeID int64, fn func(b *Bike) error) error {
+	b := f.Bike(motorbikeID)
+	if b == nil {
+		return ErrUnknownBike
+	}
+	return fn(b)
+}
@@ -0,0 +1,128 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+)
+
+var ErrInvalidRoute = errors.New("geçersiz rota")
+
+const metersPerDegreeLat = 111320.0
+
+// Route simüle edilen motorun sürekli tekrar ettiği kapalı güzergah. Son noktadan sonra ilk noktaya dönülür.
+type Route []geo.Point
+
+// Length güzergahın bir turunun metre cinsinden uzunluğu
+func (r Route) Length() float64 {
+	if len(r) < 2 {
+		return 0
+	}
+	total := 0.0
+	for i := range r {
+		total += geo.HaversineMeters(r[i], r[(i+1)%len(r)])
+	}
+	return total
+}
+
+// PointAt başlangıçtan distance metre sonra bulunulan noktayı verir. Tur uzunluğunu aşan mesafeler başa sarar,
+// iki köşe arasındaki konum doğrusal olarak hesaplanır.
+func (r Route) PointAt(distance float64) geo.Point {
+	if len(r) == 0 {
+		return geo.Point{}
+	}
+	length := r.Length()
+	if length == 0 {
+		return r[0]
+	}
+
+	remaining := math.Mod(distance, length)
+	if remaining < 0 {
+		remaining += length
+	}
+	for i := range r {
+		from, to := r[i], r[(i+1)%len(r)]
+		segment := geo.HaversineMeters(from, to)
+		if remaining <= segment {
+			if segment == 0 {
+				return from
+			}
+			ratio := remaining / segment
+			return geo.Point{
+				Lat: from.Lat + (to.Lat-from.Lat)*ratio,
+				Lng: from.Lng + (to.Lng-from.Lng)*ratio,
+			}
+		}
+		remaining -= segment
+	}
+	return r[0]
+}
+
+// CircleRoute merkez etrafında radiusMeters yarıçaplı, segments köşeli bir güzergah üretir
+func CircleRoute(center geo.Point, radiusMeters float64, segments int) Route {
+	if segments < 3 {
+		segments = 3
+	}
+	metersPerDegreeLng := metersPerDegreeLat * math.Cos(center.Lat*math.Pi/180)
+
+	route := make(Route, segments)
+	for i := 0; i < segments; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		route[i] = geo.Point{
+			Lat: center.Lat + radiusMeters*math.Sin(angle)/metersPerDegreeLat,
+			Lng: center.Lng + radiusMeters*math.Cos(angle)/metersPerDegreeLng,
+		}
+	}
+	return route
+}
+
+type geoJSONLine struct {
+	Type        string        `json:"type"`
+	Coordinates [][]float64   `json:"coordinates"`
+	Geometry    *geoJSONLine  `json:"geometry"`
+	Features    []geoJSONLine `json:"features"`
+}
+
+// LoadRoute LineString, Feature veya FeatureCollection içeren GeoJSON'dan güzergah okur.
+// FeatureCollection'da ilk LineString kullanılır. GeoJSON koordinatları [boylam, enlem] sırasındadır.
+func LoadRoute(data []byte) (Route, error) {
+	var obj geoJSONLine
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRoute, err)
+	}
+	return obj.toRoute()
+}
+
+func (o geoJSONLine) toRoute() (Route, error) {
+	switch o.Type {
+	case "LineString":
+		if len(o.Coordinates) < 2 {
+			return nil, fmt.Errorf("%w: en az 2 nokta gerekli", ErrInvalidRoute)
+		}
+		route := make(Route, len(o.Coordinates))
+		for i, c := range o.Coordinates {
+			if len(c) < 2 {
+				return nil, fmt.Errorf("%w: koordinat [boylam, enlem] olmalı", ErrInvalidRoute)
+			}
+			route[i] = geo.Point{Lat: c[1], Lng: c[0]}
+		}
+		return route, nil
+	case "Feature":
+		if o.Geometry == nil {
+			return nil, fmt.Errorf("%w: geometry eksik", ErrInvalidRoute)
+		}
+		return o.Geometry.toRoute()
+	case "FeatureCollection":
+		for _, f := range o.Features {
+			if route, err := f.toRoute(); err == nil {
+				return route, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: LineString bulunamadı", ErrInvalidRoute)
+	default:
+		return nil, fmt.Errorf("%w: desteklenmeyen tip %q", ErrInvalidRoute, o.Type)
+	}
+}
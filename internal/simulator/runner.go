@@ -0,0 +1,103 @@
+package simulator
+
+import (
+	"context"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/dto"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+)
+
+// Logf simülatörün olay kaydı için kullandığı fonksiyon
+type Logf func(format string, args ...any)
+
+const (
+	pollWait       = 25 * time.Second
+	retryBackoff   = 2 * time.Second
+	requestTimeout = 10 * time.Second
+)
+
+// RunBike motoru ctx iptal edilene kadar çalıştırır: her interval'de motoru ilerletip ölçüm gönderir,
+// paralelde komutları long polling ile alıp uygular ve sonucunu onaylar.
+// Bağlantı koptu arızası varken ölçümler biriktirilmez ve komutlar cevapsız kalır; sunucu onay süresi
+// dolunca komutu tekrar dener.
+func RunBike(ctx context.Context, client *Client, bike *Bike, interval time.Duration, logf Logf) {
+	go runCommands(ctx, client, bike, logf)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		bike.Step(interval)
+		if !bike.Online() {
+			continue
+		}
+
+		sample := bike.Sample(time.Now())
+		reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		err := client.SendTelemetry(reqCtx, bike.Key, []dto.TelemetrySampleRequest{sample})
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			logf("motor %d: telemetri gönderilemedi: %v", bike.ID, err)
+		}
+	}
+}
+
+func runCommands(ctx context.Context, client *Client, bike *Bike, logf Logf) {
+	for ctx.Err() == nil {
+		if !bike.Online() {
+			sleep(ctx, retryBackoff)
+			continue
+		}
+
+		commands, err := client.PollCommands(ctx, bike.Key, pollWait)
+		if err != nil {
+			if ctx.Err() == nil {
+				logf("motor %d: komutlar alınamadı: %v", bike.ID, err)
+				sleep(ctx, retryBackoff)
+			}
+			continue
+		}
+
+		for _, cmd := range commands {
+			// Komut alındıktan sonra bağlantı koptuysa onay gönderilmez
+			if !bike.Online() {
+				logf("motor %d: %s komutu (#%d) çevrimdışı olduğu için cevapsız kaldı", bike.ID, cmd.Type, cmd.ID)
+				continue
+			}
+
+			execErr := bike.Execute(model.DeviceCommandType(cmd.Type))
+			message := ""
+			if execErr != nil {
+				message = execErr.Error()
+			}
+			logf("motor %d: %s komutu (#%d, deneme %d) -> %s", bike.ID, cmd.Type, cmd.ID, cmd.Attempt, resultText(execErr))
+
+			reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+			err = client.Ack(reqCtx, bike.Key, cmd.ID, execErr == nil, message)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				logf("motor %d: komut #%d onaylanamadı: %v", bike.ID, cmd.ID, err)
+			}
+		}
+	}
+}
+
+func resultText(err error) string {
+	if err != nil {
+		return "hata: " + err.Error()
+	}
+	return "başarılı"
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -57,6 +57,338 @@ func init() {
 				DROP TABLE IF EXISTS rides CASCADE;
 			`,
 		},
+		{
+			Version: "000006",
+			Up:      readSQLFile("000006_create_bluetooth.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS set_updated_at ON bluetooth_connections;
+				DROP FUNCTION IF EXISTS update_bluetooth_connections_updated_at();
+				DROP TABLE IF EXISTS bluetooth_connections CASCADE;
+			`,
+		},
+		{
+			Version: "000007",
+			Up:      readSQLFile("000007_add_active_ride_constraints.sql"),
+			Down: `
+				DROP INDEX IF EXISTS idx_bluetooth_connections_user_id;
+				DROP INDEX IF EXISTS idx_bluetooth_connections_motorbike_id;
+				DROP INDEX IF EXISTS uq_rides_active_motorbike;
+				DROP INDEX IF EXISTS uq_rides_active_user;
+			`,
+		},
+		{
+			Version: "000008",
+			Up:      readSQLFile("000008_create_tariffs.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_ride_price_breakdowns_updated_at ON ride_price_breakdowns;
+				DROP TRIGGER IF EXISTS update_tariffs_updated_at ON tariffs;
+				DROP FUNCTION IF EXISTS update_tariff_updated_at();
+				DROP TABLE IF EXISTS ride_price_breakdowns CASCADE;
+				DROP TABLE IF EXISTS tariffs CASCADE;
+				ALTER TABLE rides DROP COLUMN IF EXISTS currency;
+				ALTER TABLE rides ALTER COLUMN cost DROP DEFAULT;
+				ALTER TABLE rides ALTER COLUMN cost TYPE FLOAT8 USING cost / 100.0;
+			`,
+		},
+		{
+			Version: "000009",
+			Up:      readSQLFile("000009_create_reservations.sql"),
+			// Enum'dan değer silinemediği için 'reserved' tipte kalır, rezerve motorlar müsaite çekilir
+			Down: `
+				DROP TRIGGER IF EXISTS update_reservations_updated_at ON reservations;
+				DROP FUNCTION IF EXISTS update_reservations_updated_at();
+				DROP TABLE IF EXISTS reservations CASCADE;
+				UPDATE motorbikes SET status = 'available' WHERE status = 'reserved';
+			`,
+		},
+		{
+			Version: "000010",
+			Up:      readSQLFile("000010_create_zones.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_zones_updated_at ON zones;
+				DROP FUNCTION IF EXISTS update_zones_updated_at();
+				DROP TABLE IF EXISTS zones CASCADE;
+			`,
+		},
+		{
+			Version: "000011",
+			Up:      readSQLFile("000011_add_motorbike_location_index.sql"),
+			Down: `
+				DROP INDEX IF EXISTS idx_motorbikes_available_location;
+			`,
+		},
+		{
+			Version: "000012",
+			Up:      readSQLFile("000012_create_motorbike_telemetry.sql"),
+			Down: `
+				DROP TABLE IF EXISTS motorbike_telemetry CASCADE;
+				DROP INDEX IF EXISTS uq_motorbikes_device_key_hash;
+				ALTER TABLE motorbikes DROP COLUMN IF EXISTS device_key_hash;
+				ALTER TABLE motorbikes DROP COLUMN IF EXISTS last_seen_at;
+				ALTER TABLE motorbikes DROP COLUMN IF EXISTS battery_level;
+			`,
+		},
+		{
+			Version: "000013",
+			Up:      readSQLFile("000013_create_ride_points.sql"),
+			Down: `
+				DROP TABLE IF EXISTS ride_points CASCADE;
+				ALTER TABLE rides DROP COLUMN IF EXISTS idle_seconds;
+				ALTER TABLE rides DROP COLUMN IF EXISTS avg_speed_kmh;
+				ALTER TABLE rides DROP COLUMN IF EXISTS max_speed_kmh;
+				ALTER TABLE rides DROP COLUMN IF EXISTS distance_meters;
+			`,
+		},
+		{
+			Version: "000014",
+			Up:      readSQLFile("000014_create_device_commands.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_device_commands_updated_at ON device_commands;
+				DROP FUNCTION IF EXISTS update_device_commands_updated_at();
+				DROP TABLE IF EXISTS device_command_events CASCADE;
+				DROP TABLE IF EXISTS device_commands CASCADE;
+			`,
+		},
+		{
+			Version: "000015",
+			Up:      readSQLFile("000015_create_ledger.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS check_ledger_transaction_balanced ON ledger_entries;
+				DROP TRIGGER IF EXISTS prevent_ledger_entries_modification ON ledger_entries;
+				DROP TRIGGER IF EXISTS prevent_ledger_transactions_modification ON ledger_transactions;
+				DROP FUNCTION IF EXISTS check_ledger_transaction_balanced();
+				DROP FUNCTION IF EXISTS prevent_ledger_modification();
+				DROP TABLE IF EXISTS ledger_entries CASCADE;
+				DROP TABLE IF EXISTS ledger_transactions CASCADE;
+				DROP TABLE IF EXISTS ledger_accounts CASCADE;
+			`,
+		},
+		{
+			Version: "000016",
+			Up:      readSQLFile("000016_create_ride_payments.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_ride_payments_updated_at ON ride_payments;
+				DROP FUNCTION IF EXISTS update_ride_payments_updated_at();
+				DROP TABLE IF EXISTS ride_payments CASCADE;
+				DROP INDEX IF EXISTS idx_rides_payment_failed;
+				ALTER TABLE rides DROP COLUMN IF EXISTS payment_status;
+			`,
+		},
+		{
+			Version: "000017",
+			Up:      readSQLFile("000017_create_promotions.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_referrals_updated_at ON referrals;
+				DROP TRIGGER IF EXISTS update_promotions_updated_at ON promotions;
+				DROP FUNCTION IF EXISTS update_promotions_updated_at();
+				DROP TABLE IF EXISTS referrals CASCADE;
+				DROP TABLE IF EXISTS referral_codes CASCADE;
+				ALTER TABLE rides DROP COLUMN IF EXISTS promotion_id;
+				DROP TABLE IF EXISTS promotion_redemptions CASCADE;
+				DROP TABLE IF EXISTS promotions CASCADE;
+			`,
+		},
+		{
+			Version: "000018",
+			Up:      readSQLFile("000018_create_passes.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_subscriptions_updated_at ON subscriptions;
+				DROP TRIGGER IF EXISTS update_pass_products_updated_at ON pass_products;
+				DROP FUNCTION IF EXISTS update_passes_updated_at();
+				DROP TABLE IF EXISTS pass_usages CASCADE;
+				DROP TABLE IF EXISTS subscription_charges CASCADE;
+				DROP TABLE IF EXISTS subscriptions CASCADE;
+				DROP TABLE IF EXISTS pass_products CASCADE;
+				-- Defter kayıtları silinemediği için eski kısıt mevcut pass_purchase kayıtlarını kontrol etmez
+				ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS ledger_transactions_kind_check;
+				ALTER TABLE ledger_transactions ADD CONSTRAINT ledger_transactions_kind_check
+					CHECK (kind IN ('top_up', 'ride_charge', 'refund', 'promo_credit', 'adjustment')) NOT VALID;
+			`,
+		},
+		{
+			Version: "000019",
+			Up:      readSQLFile("000019_create_invoices.sql"),
+			Down: `
+				DROP TABLE IF EXISTS invoices CASCADE;
+				DROP SEQUENCE IF EXISTS invoice_number_seq;
+				DROP SEQUENCE IF EXISTS receipt_number_seq;
+			`,
+		},
+		{
+			Version: "000020",
+			Up:      readSQLFile("000020_create_disputes.sql"),
+			Down: `
+				DROP TABLE IF EXISTS ride_adjustments CASCADE;
+				DROP TABLE IF EXISTS dispute_events CASCADE;
+				DROP TABLE IF EXISTS dispute_photos CASCADE;
+				DROP TABLE IF EXISTS disputes CASCADE;
+				DROP FUNCTION IF EXISTS update_disputes_updated_at();
+			`,
+		},
+		{
+			Version: "000021",
+			Up:      readSQLFile("000021_create_ride_events.sql"),
+			Down: `
+				DROP TABLE IF EXISTS ride_events CASCADE;
+				ALTER TABLE rides ALTER COLUMN duration DROP NOT NULL;
+				ALTER TABLE rides ALTER COLUMN duration DROP DEFAULT;
+				ALTER TABLE rides ALTER COLUMN duration TYPE VARCHAR(255) USING duration::TEXT;
+				DROP INDEX IF EXISTS uq_rides_active_motorbike;
+				DROP INDEX IF EXISTS uq_rides_active_user;
+				DROP INDEX IF EXISTS idx_rides_payment_failed;
+				ALTER TABLE rides ADD COLUMN IF NOT EXISTS payment_status VARCHAR(16) CHECK (payment_status IN ('pending', 'paid', 'payment_failed'));
+				UPDATE rides SET payment_status = CASE
+					WHEN status = 'payment_failed' THEN 'payment_failed'
+					WHEN status IN ('completed', 'ending') THEN 'paid'
+					ELSE 'pending'
+				END;
+				CREATE INDEX idx_rides_payment_failed ON rides(user_id) WHERE payment_status = 'payment_failed';
+				-- Eski şemada açık sürüş bitiş zamanı boş olandır; iptal edilen sürüşler kapatılır
+				UPDATE rides SET end_time = updated_at WHERE status = 'cancelled' AND end_time IS NULL;
+				CREATE UNIQUE INDEX uq_rides_active_user ON rides(user_id) WHERE end_time IS NULL AND deleted_at IS NULL;
+				CREATE UNIQUE INDEX uq_rides_active_motorbike ON rides(motorbike_id) WHERE end_time IS NULL AND deleted_at IS NULL;
+				ALTER TABLE rides DROP COLUMN IF EXISTS status;
+			`,
+		},
+		{
+			Version: "000022",
+			Up:      readSQLFile("000022_create_ride_pauses.sql"),
+			// Park modundaki sürüşler devam ediyor olarak kalır
+			Down: `
+				DROP TABLE IF EXISTS ride_pauses CASCADE;
+				ALTER TABLE tariffs DROP COLUMN IF EXISTS pause_per_minute_rate;
+				UPDATE rides SET status = 'active' WHERE status = 'paused';
+			`,
+		},
+		{
+			Version: "000023",
+			Up:      readSQLFile("000023_create_ride_photos.sql"),
+			Down:    `DROP TABLE IF EXISTS ride_photos CASCADE;`,
+		},
+		{
+			Version: "000024",
+			Up:      readSQLFile("000024_motorbike_photo_gallery.sql"),
+			// Depoya yüklenmiş fotoğrafların eski şemada karşılığı olmadığı için silinir
+			Down: `
+				DELETE FROM motorbike_photos WHERE photo_url IS NULL;
+				DROP INDEX IF EXISTS uq_motorbike_photos_primary;
+				DROP INDEX IF EXISTS idx_motorbike_photos_motorbike_id;
+				CREATE INDEX idx_motorbike_photos_motorbike_id ON motorbike_photos(motorbike_id);
+				ALTER TABLE motorbike_photos
+					DROP CONSTRAINT IF EXISTS motorbike_photos_source_check,
+					DROP COLUMN IF EXISTS storage_key,
+					DROP COLUMN IF EXISTS thumbnail_key,
+					DROP COLUMN IF EXISTS content_type,
+					DROP COLUMN IF EXISTS size,
+					DROP COLUMN IF EXISTS width,
+					DROP COLUMN IF EXISTS height,
+					DROP COLUMN IF EXISTS position,
+					DROP COLUMN IF EXISTS is_primary;
+				ALTER TABLE motorbike_photos ALTER COLUMN photo_url SET NOT NULL;
+			`,
+		},
+		{
+			Version: "000025",
+			Up:      readSQLFile("000025_create_maintenance.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_work_orders_updated_at ON work_orders;
+				DROP TRIGGER IF EXISTS update_maintenance_schedules_updated_at ON maintenance_schedules;
+				DROP FUNCTION IF EXISTS update_maintenance_updated_at();
+				DROP TABLE IF EXISTS work_order_parts CASCADE;
+				DROP TABLE IF EXISTS work_orders CASCADE;
+				DROP TABLE IF EXISTS maintenance_schedules CASCADE;
+				ALTER TABLE motorbikes DROP COLUMN IF EXISTS odometer_meters;
+			`,
+		},
+		{
+			Version: "000026",
+			Up:      readSQLFile("000026_create_issues.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS update_issues_updated_at ON issues;
+				DROP FUNCTION IF EXISTS update_issues_updated_at();
+				DROP TABLE IF EXISTS issue_photos CASCADE;
+				DROP TABLE IF EXISTS issues CASCADE;
+				DELETE FROM work_order_parts WHERE work_order_id IN (SELECT id FROM work_orders WHERE type = 'inspection');
+				DELETE FROM work_orders WHERE type = 'inspection';
+				DELETE FROM maintenance_schedules WHERE type = 'inspection';
+				ALTER TABLE work_orders DROP CONSTRAINT IF EXISTS work_orders_type_check;
+				ALTER TABLE work_orders ADD CONSTRAINT work_orders_type_check CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork'));
+				ALTER TABLE maintenance_schedules DROP CONSTRAINT IF EXISTS maintenance_schedules_type_check;
+				ALTER TABLE maintenance_schedules ADD CONSTRAINT maintenance_schedules_type_check CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork'));
+			`,
+		},
+		{
+			Version: "000027",
+			Up:      readSQLFile("000027_create_tasks.sql"),
+			// Enum'dan değer silinemediği için 'operator' tipte kalır, operatörler kullanıcıya çevrilir
+			Down: `
+				DROP TRIGGER IF EXISTS update_tasks_updated_at ON tasks;
+				DROP FUNCTION IF EXISTS update_tasks_updated_at();
+				DROP TABLE IF EXISTS task_photos CASCADE;
+				DROP TABLE IF EXISTS tasks CASCADE;
+				UPDATE users SET role = 'user' WHERE role = 'operator';
+			`,
+		},
+		{
+			Version: "000028",
+			Up:      readSQLFile("000028_create_rbac.sql"),
+			// Sonradan tanımlanan rollere sahip kullanıcılar enum'a sığmadığı için kullanıcıya çevrilir
+			Down: `
+				DROP INDEX IF EXISTS idx_users_role;
+				ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+				UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'user', 'operator');
+				CREATE TYPE user_role AS ENUM ('admin', 'user', 'operator');
+				ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+				ALTER TABLE users ALTER COLUMN role TYPE user_role USING role::user_role;
+				ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+				DROP TRIGGER IF EXISTS update_roles_updated_at ON roles;
+				DROP FUNCTION IF EXISTS update_roles_updated_at();
+				DROP TABLE IF EXISTS role_permissions CASCADE;
+				DROP TABLE IF EXISTS permissions CASCADE;
+				DROP TABLE IF EXISTS roles CASCADE;
+			`,
+		},
+		{
+			Version: "000029",
+			Up:      readSQLFile("000029_create_audit_logs.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+				DROP TRIGGER IF EXISTS audit_logs_no_update ON audit_logs;
+				DROP TABLE IF EXISTS audit_logs CASCADE;
+				DROP FUNCTION IF EXISTS audit_logs_append_only();
+				DELETE FROM permissions WHERE name = 'audit.read';
+			`,
+		},
+		{
+			Version: "000030",
+			Up:      readSQLFile("000030_add_reservations_cancel_permission.sql"),
+			Down:    `DELETE FROM permissions WHERE name = 'reservations.cancel';`,
+		},
+		{
+			Version: "000031",
+			Up:      readSQLFile("000031_add_ride_payment_retries.sql"),
+			Down: `
+				ALTER TABLE ride_payments DROP COLUMN IF EXISTS retry_count;
+				UPDATE ride_payments SET status = 'failed' WHERE status = 'settling';
+				ALTER TABLE ride_payments DROP CONSTRAINT IF EXISTS ride_payments_status_check;
+				ALTER TABLE ride_payments ADD CONSTRAINT ride_payments_status_check
+					CHECK (status IN ('authorized', 'captured', 'voided', 'failed'));
+			`,
+		},
+		{
+			Version: "000032",
+			Up:      readSQLFile("000032_add_adjustment_refund_status.sql"),
+			Down: `
+				DROP TRIGGER IF EXISTS prevent_ride_adjustments_modification ON ride_adjustments;
+				DROP FUNCTION IF EXISTS prevent_ride_adjustments_modification();
+				DROP INDEX IF EXISTS idx_ride_adjustments_provider_refund_status;
+				ALTER TABLE ride_adjustments DROP COLUMN IF EXISTS provider_refund_status;
+				CREATE TRIGGER prevent_ride_adjustments_modification
+					BEFORE UPDATE OR DELETE ON ride_adjustments
+					FOR EACH ROW
+					EXECUTE FUNCTION prevent_ledger_modification();
+			`,
+		},
 	}
 
 	Migrations = append(Migrations, migrations...)
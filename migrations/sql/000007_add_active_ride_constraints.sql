@@ -0,0 +1,7 @@
+-- Bir kullanıcının ve bir motorun aynı anda yalnızca bir açık sürüşü olabilir.
+-- Servis katmanındaki satır kilitlerine ek olarak veritabanı seviyesinde de garanti altına alınır.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_rides_active_user ON rides(user_id) WHERE end_time IS NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_rides_active_motorbike ON rides(motorbike_id) WHERE end_time IS NULL AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bluetooth_connections_motorbike_id ON bluetooth_connections(motorbike_id);
+CREATE INDEX IF NOT EXISTS idx_bluetooth_connections_user_id ON bluetooth_connections(user_id);
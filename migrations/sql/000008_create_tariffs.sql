@@ -0,0 +1,68 @@
+CREATE TABLE IF NOT EXISTS tariffs (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    motorbike_model VARCHAR(255), -- NULL ise varsayılan tarife
+    currency CHAR(3) NOT NULL DEFAULT 'TRY',
+    unlock_fee BIGINT NOT NULL DEFAULT 0,
+    per_minute_rate BIGINT NOT NULL DEFAULT 0,
+    minimum_charge BIGINT NOT NULL DEFAULT 0,
+    daily_cap BIGINT NOT NULL DEFAULT 0,
+    free_minutes INT NOT NULL DEFAULT 0,
+    night_multiplier_pct INT NOT NULL DEFAULT 100,
+    night_start_hour INT NOT NULL DEFAULT 0,
+    night_end_hour INT NOT NULL DEFAULT 0,
+    weekend_multiplier_pct INT NOT NULL DEFAULT 100,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_tariffs_night_hours CHECK (night_start_hour BETWEEN 0 AND 23 AND night_end_hour BETWEEN 0 AND 23)
+);
+
+CREATE TABLE IF NOT EXISTS ride_price_breakdowns (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL,
+    tariff_id BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    total_minutes INT NOT NULL DEFAULT 0,
+    billable_minutes INT NOT NULL DEFAULT 0,
+    lines JSONB NOT NULL DEFAULT '[]',
+    total BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_ride FOREIGN KEY (ride_id) REFERENCES rides(id),
+    CONSTRAINT fk_tariff FOREIGN KEY (tariff_id) REFERENCES tariffs(id),
+    CONSTRAINT ride_price_breakdowns_ride_unique UNIQUE (ride_id)
+);
+
+-- Sürüş ücretleri artık kuruş cinsinden tam sayı olarak tutuluyor
+ALTER TABLE rides ALTER COLUMN cost TYPE BIGINT USING ROUND(COALESCE(cost, 0) * 100)::BIGINT;
+ALTER TABLE rides ALTER COLUMN cost SET DEFAULT 0;
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS currency CHAR(3);
+
+-- İndeksler
+CREATE INDEX idx_tariffs_motorbike_model ON tariffs(motorbike_model);
+CREATE INDEX idx_tariffs_is_active ON tariffs(is_active);
+
+-- Varsayılan tarife (eski sabit hesaplama: 10 TL açılış + dakikası 3 TL)
+INSERT INTO tariffs (name, currency, unlock_fee, per_minute_rate)
+VALUES ('Varsayılan', 'TRY', 1000, 300);
+
+CREATE OR REPLACE FUNCTION update_tariff_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_tariffs_updated_at
+    BEFORE UPDATE ON tariffs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_tariff_updated_at();
+
+CREATE TRIGGER update_ride_price_breakdowns_updated_at
+    BEFORE UPDATE ON ride_price_breakdowns
+    FOR EACH ROW
+    EXECUTE FUNCTION update_tariff_updated_at();
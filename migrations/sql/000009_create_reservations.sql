@@ -0,0 +1,38 @@
+-- Motorlar artık rezerve edilebilir
+ALTER TYPE motorbike_status ADD VALUE IF NOT EXISTS 'reserved';
+
+CREATE TABLE reservations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'converted', 'cancelled', 'expired')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    ride_id BIGINT REFERENCES rides(id),
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- Bir kullanıcının ve bir motorun aynı anda yalnızca bir aktif rezervasyonu olabilir
+CREATE UNIQUE INDEX uq_reservations_active_user ON reservations(user_id) WHERE status = 'active' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_reservations_active_motorbike ON reservations(motorbike_id) WHERE status = 'active' AND deleted_at IS NULL;
+
+-- Süresi dolan rezervasyonları tarayan worker için
+CREATE INDEX idx_reservations_active_expires_at ON reservations(expires_at) WHERE status = 'active';
+CREATE INDEX idx_reservations_ride_id ON reservations(ride_id);
+
+CREATE OR REPLACE FUNCTION update_reservations_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_reservations_updated_at
+    BEFORE UPDATE ON reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_reservations_updated_at();
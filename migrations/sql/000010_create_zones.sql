@@ -0,0 +1,30 @@
+-- Hizmet alanları, park yasağı olan alanlar, yavaş alanlar ve önerilen park noktaları.
+-- Geometri GeoJSON olarak saklanır; nokta-çokgen kontrolü uygulama tarafında yapılır (PostGIS gerekmez).
+CREATE TABLE zones (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('operating', 'no_parking', 'slow', 'preferred_parking')),
+    geometry JSONB NOT NULL,
+    enforcement VARCHAR(20) NOT NULL DEFAULT 'reject' CHECK (enforcement IN ('reject', 'surcharge')),
+    surcharge BIGINT NOT NULL DEFAULT 0,
+    max_speed_kmh INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_zones_active_type ON zones(type) WHERE is_active = true AND deleted_at IS NULL;
+
+CREATE OR REPLACE FUNCTION update_zones_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_zones_updated_at
+    BEFORE UPDATE ON zones
+    FOR EACH ROW
+    EXECUTE FUNCTION update_zones_updated_at();
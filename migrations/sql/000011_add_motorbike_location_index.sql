@@ -0,0 +1,4 @@
+-- Yakındaki motor araması dikdörtgen alan ön filtresi kullanır; sadece müsait motorlar aranır
+CREATE INDEX IF NOT EXISTS idx_motorbikes_available_location
+    ON motorbikes(location_latitude, location_longitude)
+    WHERE status = 'available' AND deleted_at IS NULL;
@@ -0,0 +1,22 @@
+-- Motor üzerindeki cihazın anlık durumu
+ALTER TABLE motorbikes ADD COLUMN IF NOT EXISTS battery_level INT;
+ALTER TABLE motorbikes ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+ALTER TABLE motorbikes ADD COLUMN IF NOT EXISTS device_key_hash VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_motorbikes_device_key_hash ON motorbikes(device_key_hash) WHERE device_key_hash IS NOT NULL;
+
+-- Cihazdan gelen ölçümler. Yüksek hacimli olduğu için soft delete ve updated_at tutulmaz.
+CREATE TABLE motorbike_telemetry (
+    id BIGSERIAL PRIMARY KEY,
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    recorded_at TIMESTAMPTZ NOT NULL,
+    latitude FLOAT8 NOT NULL,
+    longitude FLOAT8 NOT NULL,
+    speed_kmh FLOAT8 NOT NULL DEFAULT 0,
+    battery_level INT,
+    fuel_level INT,
+    odometer_meters BIGINT,
+    lock_status lock_status,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_motorbike_telemetry_motorbike_recorded_at ON motorbike_telemetry(motorbike_id, recorded_at);
@@ -0,0 +1,19 @@
+-- Sürüş rota özeti
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS distance_meters BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS max_speed_kmh FLOAT8 NOT NULL DEFAULT 0;
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS avg_speed_kmh FLOAT8 NOT NULL DEFAULT 0;
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS idle_seconds BIGINT NOT NULL DEFAULT 0;
+
+-- Sürüş sırasında uygulamadan veya motordan gelen GPS noktaları
+CREATE TABLE ride_points (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id) ON DELETE CASCADE,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    latitude FLOAT8 NOT NULL,
+    longitude FLOAT8 NOT NULL,
+    speed_kmh FLOAT8,
+    source VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ride_points_ride_recorded_at ON ride_points(ride_id, recorded_at);
@@ -0,0 +1,46 @@
+-- Motor cihazına gönderilen uzaktan komutlar
+CREATE TABLE device_commands (
+    id BIGSERIAL PRIMARY KEY,
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    type VARCHAR(16) NOT NULL CHECK (type IN ('lock', 'unlock', 'beep', 'disable')),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'sent', 'acknowledged', 'failed', 'expired')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ,
+    ack_deadline TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    last_error TEXT,
+    requested_by BIGINT REFERENCES users(id),
+    ride_id BIGINT REFERENCES rides(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- Cihaz sorgusu ve zaman aşımı taraması yalnızca açık komutlara bakar
+CREATE INDEX idx_device_commands_open ON device_commands(motorbike_id, created_at) WHERE status IN ('pending', 'sent');
+CREATE INDEX idx_device_commands_motorbike_id ON device_commands(motorbike_id, id DESC);
+
+CREATE TABLE device_command_events (
+    id BIGSERIAL PRIMARY KEY,
+    command_id BIGINT NOT NULL REFERENCES device_commands(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_device_command_events_command_id ON device_command_events(command_id);
+
+CREATE OR REPLACE FUNCTION update_device_commands_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_device_commands_updated_at
+    BEFORE UPDATE ON device_commands
+    FOR EACH ROW
+    EXECUTE FUNCTION update_device_commands_updated_at();
@@ -0,0 +1,76 @@
+-- Çift taraflı muhasebe defteri: hesaplar, işlemler ve işlemlerin hesaplara etkisi.
+-- Bakiye tutulmaz, hesabın kayıtlarının toplamıdır. İşlemler ve kayıtlar değiştirilemez ve silinemez.
+CREATE TABLE ledger_accounts (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('user_wallet', 'system')),
+    code VARCHAR(32),
+    user_id BIGINT REFERENCES users(id),
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((type = 'user_wallet' AND user_id IS NOT NULL AND code IS NULL) OR (type = 'system' AND code IS NOT NULL AND user_id IS NULL))
+);
+
+-- Kullanıcı başına tek cüzdan, para birimi başına tek sistem hesabı
+CREATE UNIQUE INDEX idx_ledger_accounts_user ON ledger_accounts(user_id) WHERE type = 'user_wallet';
+CREATE UNIQUE INDEX idx_ledger_accounts_system ON ledger_accounts(code, currency) WHERE type = 'system';
+
+CREATE TABLE ledger_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('top_up', 'ride_charge', 'refund', 'promo_credit', 'adjustment')),
+    currency VARCHAR(3) NOT NULL,
+    description TEXT,
+    ride_id BIGINT REFERENCES rides(id),
+    created_by BIGINT REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_transactions_ride_id ON ledger_transactions(ride_id) WHERE ride_id IS NOT NULL;
+
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES ledger_transactions(id),
+    account_id BIGINT NOT NULL REFERENCES ledger_accounts(id),
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_entries_account_id ON ledger_entries(account_id, id);
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+
+-- Defter yalnızca eklemeye açıktır
+CREATE OR REPLACE FUNCTION prevent_ledger_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger kayıtları değiştirilemez (%)', TG_TABLE_NAME;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER prevent_ledger_transactions_modification
+    BEFORE UPDATE OR DELETE ON ledger_transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+CREATE TRIGGER prevent_ledger_entries_modification
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+-- İşlemin kayıtlarının toplamı sıfır olmalıdır. Kayıtlar tek tek eklendiği için kontrol transaction sonunda yapılır.
+CREATE OR REPLACE FUNCTION check_ledger_transaction_balanced()
+RETURNS TRIGGER AS $$
+DECLARE
+    total BIGINT;
+BEGIN
+    SELECT COALESCE(SUM(amount), 0) INTO total FROM ledger_entries WHERE transaction_id = NEW.transaction_id;
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger işlemi % dengesiz: toplam %', NEW.transaction_id, total;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE CONSTRAINT TRIGGER check_ledger_transaction_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION check_ledger_transaction_balanced();
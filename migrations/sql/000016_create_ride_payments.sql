@@ -0,0 +1,38 @@
+-- Sürüşün ödeme durumu, ödemesi alınamayan sürüşü olan kullanıcı yeni sürüş başlatamaz
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS payment_status VARCHAR(16) CHECK (payment_status IN ('pending', 'paid', 'payment_failed'));
+
+CREATE INDEX idx_rides_payment_failed ON rides(user_id) WHERE payment_status = 'payment_failed';
+
+-- Sürüş başında alınan provizyon ve sürüş sonunda karttan yapılan tahsilat
+CREATE TABLE ride_payments (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    provider VARCHAR(32) NOT NULL,
+    authorization_id VARCHAR(128) NOT NULL,
+    authorized_amount BIGINT NOT NULL CHECK (authorized_amount > 0),
+    capture_id VARCHAR(128),
+    captured_amount BIGINT NOT NULL DEFAULT 0 CHECK (captured_amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('authorized', 'captured', 'voided', 'failed')),
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_ride_payments_ride_id ON ride_payments(ride_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_ride_payments_authorization ON ride_payments(provider, authorization_id);
+
+CREATE OR REPLACE FUNCTION update_ride_payments_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_ride_payments_updated_at
+    BEFORE UPDATE ON ride_payments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_ride_payments_updated_at();
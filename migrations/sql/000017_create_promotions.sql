@@ -0,0 +1,83 @@
+-- Kampanya kodları
+CREATE TABLE promotions (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    description TEXT,
+    discount_type VARCHAR(16) NOT NULL CHECK (discount_type IN ('percentage', 'fixed')),
+    discount_value BIGINT NOT NULL CHECK (discount_value > 0),
+    max_discount BIGINT NOT NULL DEFAULT 0 CHECK (max_discount >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'TRY',
+    first_ride_only BOOLEAN NOT NULL DEFAULT FALSE,
+    per_user_limit INT NOT NULL DEFAULT 0 CHECK (per_user_limit >= 0),
+    total_limit INT NOT NULL DEFAULT 0 CHECK (total_limit >= 0),
+    redemption_count INT NOT NULL DEFAULT 0,
+    motorbike_models TEXT[],
+    valid_from TIMESTAMPTZ,
+    valid_until TIMESTAMPTZ,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT chk_promotions_percentage CHECK (discount_type <> 'percentage' OR discount_value <= 100),
+    CONSTRAINT chk_promotions_total_limit CHECK (total_limit = 0 OR redemption_count <= total_limit)
+);
+
+CREATE UNIQUE INDEX idx_promotions_code ON promotions(code) WHERE deleted_at IS NULL;
+
+-- Kampanya kodunun kullanıldığı sürüşler
+CREATE TABLE promotion_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    promotion_id BIGINT NOT NULL REFERENCES promotions(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    ride_id BIGINT NOT NULL REFERENCES rides(id) UNIQUE,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_promotion_redemptions_promotion_user ON promotion_redemptions(promotion_id, user_id);
+
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS promotion_id BIGINT REFERENCES promotions(id);
+
+-- Davet kodları ve davetler
+CREATE TABLE referral_codes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL UNIQUE REFERENCES users(id),
+    code VARCHAR(16) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE referrals (
+    id BIGSERIAL PRIMARY KEY,
+    referrer_id BIGINT NOT NULL REFERENCES users(id),
+    referee_id BIGINT NOT NULL UNIQUE REFERENCES users(id),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'rewarded')),
+    referrer_credit BIGINT NOT NULL,
+    referee_credit BIGINT NOT NULL,
+    ride_id BIGINT REFERENCES rides(id),
+    rewarded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CHECK (referrer_id <> referee_id)
+);
+
+CREATE INDEX idx_referrals_referrer_id ON referrals(referrer_id);
+
+CREATE OR REPLACE FUNCTION update_promotions_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_promotions_updated_at();
+
+CREATE TRIGGER update_referrals_updated_at
+    BEFORE UPDATE ON referrals
+    FOR EACH ROW
+    EXECUTE FUNCTION update_promotions_updated_at();
@@ -0,0 +1,87 @@
+-- Abonelik paketleri, kullanıcı abonelikleri, dönem ücretleri ve sürüşlerde kullanılan dahil dakikalar
+ALTER TABLE ledger_transactions DROP CONSTRAINT IF EXISTS ledger_transactions_kind_check;
+ALTER TABLE ledger_transactions ADD CONSTRAINT ledger_transactions_kind_check
+    CHECK (kind IN ('top_up', 'ride_charge', 'refund', 'promo_credit', 'adjustment', 'pass_purchase'));
+
+CREATE TABLE pass_products (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    period VARCHAR(8) NOT NULL CHECK (period IN ('day', 'week', 'month')),
+    price BIGINT NOT NULL CHECK (price > 0),
+    currency VARCHAR(3) NOT NULL,
+    included_minutes_per_day INT NOT NULL DEFAULT 0 CHECK (included_minutes_per_day >= 0),
+    waive_unlock_fee BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    product_id BIGINT NOT NULL REFERENCES pass_products(id),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('active', 'expired')),
+    current_period_start TIMESTAMPTZ NOT NULL,
+    current_period_end TIMESTAMPTZ NOT NULL,
+    auto_renew BOOLEAN NOT NULL DEFAULT TRUE,
+    cancelled_at TIMESTAMPTZ,
+    renewal_failure TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CHECK (current_period_end > current_period_start)
+);
+
+-- Kullanıcının aynı anda tek aktif aboneliği olabilir
+CREATE UNIQUE INDEX uq_subscriptions_active_user ON subscriptions(user_id) WHERE status = 'active' AND deleted_at IS NULL;
+-- Dönemi biten abonelikleri tarayan yenileme worker'ı için
+CREATE INDEX idx_subscriptions_active_period_end ON subscriptions(current_period_end) WHERE status = 'active';
+
+CREATE TABLE subscription_charges (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    card_amount BIGINT NOT NULL DEFAULT 0 CHECK (card_amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    transaction_id BIGINT NOT NULL REFERENCES ledger_transactions(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Aynı dönem iki kez ücretlendirilemez
+CREATE UNIQUE INDEX uq_subscription_charges_period ON subscription_charges(subscription_id, period_start);
+
+CREATE TABLE pass_usages (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    usage_date DATE NOT NULL,
+    minutes INT NOT NULL CHECK (minutes >= 0),
+    unlock_waived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uq_pass_usages_ride_id ON pass_usages(ride_id);
+CREATE INDEX idx_pass_usages_subscription_date ON pass_usages(subscription_id, usage_date);
+
+CREATE OR REPLACE FUNCTION update_passes_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_pass_products_updated_at
+    BEFORE UPDATE ON pass_products
+    FOR EACH ROW
+    EXECUTE FUNCTION update_passes_updated_at();
+
+CREATE TRIGGER update_subscriptions_updated_at
+    BEFORE UPDATE ON subscriptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_passes_updated_at();
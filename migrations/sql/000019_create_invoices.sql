@@ -0,0 +1,33 @@
+-- Sürüş fişleri ve aylık faturalar
+CREATE SEQUENCE receipt_number_seq;
+CREATE SEQUENCE invoice_number_seq;
+
+CREATE TABLE invoices (
+    id BIGSERIAL PRIMARY KEY,
+    number VARCHAR(32) NOT NULL UNIQUE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('receipt', 'monthly')),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    ride_id BIGINT REFERENCES rides(id),
+    period_start TIMESTAMPTZ,
+    period_end TIMESTAMPTZ,
+    net_amount BIGINT NOT NULL,
+    vat_rate_pct INT NOT NULL CHECK (vat_rate_pct >= 0),
+    vat_amount BIGINT NOT NULL,
+    total BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    html TEXT NOT NULL,
+    pdf BYTEA NOT NULL,
+    emailed_at TIMESTAMPTZ,
+    email_attempts INT NOT NULL DEFAULT 0,
+    email_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (net_amount + vat_amount = total),
+    CHECK ((kind = 'receipt' AND ride_id IS NOT NULL) OR (kind = 'monthly' AND period_start IS NOT NULL AND period_end IS NOT NULL))
+);
+
+-- Her sürüş için tek fiş, her kullanıcı ve ay için tek fatura kesilir
+CREATE UNIQUE INDEX uq_invoices_ride ON invoices(ride_id) WHERE kind = 'receipt';
+CREATE UNIQUE INDEX uq_invoices_user_period ON invoices(user_id, period_start) WHERE kind = 'monthly';
+CREATE INDEX idx_invoices_user_id ON invoices(user_id, created_at DESC);
+-- E-postası gönderilemeyen belgeleri tarayan worker için
+CREATE INDEX idx_invoices_unsent ON invoices(id) WHERE emailed_at IS NULL;
@@ -0,0 +1,89 @@
+-- Kullanıcının tamamlanmış sürüşün ücretine itirazı
+CREATE TABLE disputes (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('overcharged', 'bike_issue', 'end_ride_issue', 'unauthorized', 'other')),
+    description TEXT NOT NULL,
+    requested_amount BIGINT NOT NULL DEFAULT 0 CHECK (requested_amount >= 0),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('open', 'in_review', 'approved', 'rejected', 'withdrawn')),
+    refund_amount BIGINT NOT NULL DEFAULT 0 CHECK (refund_amount >= 0),
+    refund_destination VARCHAR(16) CHECK (refund_destination IN ('wallet', 'card')),
+    reviewer_id BIGINT REFERENCES users(id),
+    resolution_note TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- Bir sürüş için aynı anda tek açık itiraz olabilir
+CREATE UNIQUE INDEX idx_disputes_active_ride ON disputes(ride_id) WHERE status IN ('open', 'in_review') AND deleted_at IS NULL;
+CREATE INDEX idx_disputes_status ON disputes(status, created_at);
+CREATE INDEX idx_disputes_user_id ON disputes(user_id);
+
+CREATE TABLE dispute_photos (
+    id BIGSERIAL PRIMARY KEY,
+    dispute_id BIGINT NOT NULL REFERENCES disputes(id),
+    path TEXT NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_dispute_photos_dispute_id ON dispute_photos(dispute_id);
+
+-- İtirazın durum geçmişi
+CREATE TABLE dispute_events (
+    id BIGSERIAL PRIMARY KEY,
+    dispute_id BIGINT NOT NULL REFERENCES disputes(id),
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    actor_id BIGINT NOT NULL REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_dispute_events_dispute_id ON dispute_events(dispute_id, id);
+
+-- Sürüş ücretinde yapılan iadeler; her kayıt bir defter işlemine bağlıdır
+CREATE TABLE ride_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    dispute_id BIGINT REFERENCES disputes(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    destination VARCHAR(16) NOT NULL CHECK (destination IN ('wallet', 'card')),
+    ledger_transaction_id BIGINT NOT NULL REFERENCES ledger_transactions(id),
+    provider_refund_id VARCHAR(128),
+    reason TEXT NOT NULL,
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ride_adjustments_ride_id ON ride_adjustments(ride_id);
+
+-- İade kayıtları ve itiraz geçmişi defter kayıtları gibi değiştirilemez
+CREATE TRIGGER prevent_ride_adjustments_modification
+    BEFORE UPDATE OR DELETE ON ride_adjustments
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+CREATE TRIGGER prevent_dispute_events_modification
+    BEFORE UPDATE OR DELETE ON dispute_events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+CREATE OR REPLACE FUNCTION update_disputes_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_disputes_updated_at
+    BEFORE UPDATE ON disputes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_disputes_updated_at();
@@ -0,0 +1,45 @@
+-- Sürüşün durumu bitiş zamanından çıkarılmak yerine açıkça tutulur; ödeme durumu da bu alana taşınır
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('reserved', 'active', 'paused', 'ending', 'completed', 'cancelled', 'payment_failed'));
+
+UPDATE rides SET status = CASE
+    WHEN payment_status = 'payment_failed' THEN 'payment_failed'
+    WHEN end_time IS NULL THEN 'active'
+    ELSE 'completed'
+END;
+
+ALTER TABLE rides ALTER COLUMN status DROP DEFAULT;
+
+DROP INDEX IF EXISTS idx_rides_payment_failed;
+ALTER TABLE rides DROP COLUMN IF EXISTS payment_status;
+CREATE INDEX idx_rides_payment_failed ON rides(user_id) WHERE status = 'payment_failed';
+
+-- Bir kullanıcının ve bir motorun aynı anda yalnızca bir bitirilmemiş sürüşü olabilir
+DROP INDEX IF EXISTS uq_rides_active_user;
+DROP INDEX IF EXISTS uq_rides_active_motorbike;
+CREATE UNIQUE INDEX uq_rides_active_user ON rides(user_id) WHERE status IN ('reserved', 'active', 'paused') AND deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_rides_active_motorbike ON rides(motorbike_id) WHERE status IN ('reserved', 'active', 'paused') AND deleted_at IS NULL;
+
+-- Süre saniye cinsinden metin olarak tutuluyordu
+ALTER TABLE rides ALTER COLUMN duration TYPE BIGINT USING NULLIF(duration, '')::BIGINT;
+UPDATE rides SET duration = 0 WHERE duration IS NULL;
+ALTER TABLE rides ALTER COLUMN duration SET DEFAULT 0;
+ALTER TABLE rides ALTER COLUMN duration SET NOT NULL;
+
+-- Sürüşün durum geçişleri; kayıtlar değiştirilemez
+CREATE TABLE ride_events (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    actor_id BIGINT REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ride_events_ride_id ON ride_events(ride_id, id);
+
+CREATE TRIGGER prevent_ride_events_modification
+    BEFORE UPDATE OR DELETE ON ride_events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
@@ -0,0 +1,17 @@
+-- Park modu: kullanıcı sürüşü bitirmeden motoru kilitleyip ara verebilir, bu süre ayrı dakika ücretiyle ücretlendirilir
+ALTER TABLE tariffs ADD COLUMN IF NOT EXISTS pause_per_minute_rate BIGINT NOT NULL DEFAULT 0 CHECK (pause_per_minute_rate >= 0);
+UPDATE tariffs SET pause_per_minute_rate = per_minute_rate / 2;
+
+CREATE TABLE ride_pauses (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_ride_pauses_ride_id ON ride_pauses(ride_id, started_at);
+-- Bir sürüşün aynı anda tek bir açık park modu olabilir; worker süresi dolanları bu indeksle bulur
+CREATE UNIQUE INDEX uq_ride_pauses_open ON ride_pauses(ride_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_ride_pauses_open_started_at ON ride_pauses(started_at) WHERE ended_at IS NULL;
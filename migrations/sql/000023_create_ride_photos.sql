@@ -0,0 +1,15 @@
+-- Sürüş sonu park fotoğrafları. Dosyalar BlobStore'da (yerel disk veya S3) saklanır, tablo anahtarlarını tutar.
+CREATE TABLE ride_photos (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id BIGINT NOT NULL REFERENCES rides(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    key VARCHAR(512) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    width INT NOT NULL CHECK (width > 0),
+    height INT NOT NULL CHECK (height > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ride_photos_ride_id ON ride_photos(ride_id);
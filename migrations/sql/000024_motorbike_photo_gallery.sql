@@ -0,0 +1,26 @@
+-- Motor fotoğrafları artık BlobStore'a yüklenir; eski kayıtlar yalnızca photo_url ile kalır
+ALTER TABLE motorbike_photos ALTER COLUMN photo_url DROP NOT NULL;
+ALTER TABLE motorbike_photos
+    ADD COLUMN storage_key VARCHAR(512) UNIQUE,
+    ADD COLUMN thumbnail_key VARCHAR(512),
+    ADD COLUMN content_type VARCHAR(100),
+    ADD COLUMN size BIGINT,
+    ADD COLUMN width INT,
+    ADD COLUMN height INT,
+    ADD COLUMN position INT NOT NULL DEFAULT 0,
+    ADD COLUMN is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT motorbike_photos_source_check CHECK (photo_url IS NOT NULL OR storage_key IS NOT NULL);
+
+-- Mevcut fotoğraflar eklenme sırasına göre dizilir, her motorun ilk fotoğrafı kapak olur
+UPDATE motorbike_photos mp SET position = ordered.rn - 1, is_primary = (ordered.rn = 1)
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY motorbike_id ORDER BY id) AS rn
+    FROM motorbike_photos
+    WHERE deleted_at IS NULL
+) ordered
+WHERE mp.id = ordered.id;
+
+-- Her motorun en fazla bir kapak fotoğrafı olabilir
+CREATE UNIQUE INDEX uq_motorbike_photos_primary ON motorbike_photos(motorbike_id) WHERE is_primary AND deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_motorbike_photos_motorbike_id;
+CREATE INDEX idx_motorbike_photos_motorbike_id ON motorbike_photos(motorbike_id, position) WHERE deleted_at IS NULL;
@@ -0,0 +1,89 @@
+-- Cihazdan gelen son kilometre; koruyucu bakım planları bu değere göre tetiklenir
+ALTER TABLE motorbikes ADD COLUMN IF NOT EXISTS odometer_meters BIGINT;
+UPDATE motorbikes m SET odometer_meters = latest.odometer_meters
+FROM (
+    SELECT DISTINCT ON (motorbike_id) motorbike_id, odometer_meters
+    FROM motorbike_telemetry
+    WHERE odometer_meters IS NOT NULL
+    ORDER BY motorbike_id, recorded_at DESC
+) latest
+WHERE m.id = latest.motorbike_id;
+
+-- Koruyucu bakım planları
+CREATE TABLE maintenance_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork')),
+    motorbike_model VARCHAR(255),
+    interval_meters BIGINT NOT NULL DEFAULT 0 CHECK (interval_meters >= 0),
+    interval_rides INT NOT NULL DEFAULT 0 CHECK (interval_rides >= 0),
+    priority VARCHAR(16) NOT NULL CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT maintenance_schedules_interval_check CHECK (interval_meters > 0 OR interval_rides > 0)
+);
+
+-- Bakım iş emirleri
+CREATE TABLE work_orders (
+    id BIGSERIAL PRIMARY KEY,
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    type VARCHAR(16) NOT NULL CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork')),
+    priority VARCHAR(16) NOT NULL CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('open', 'in_progress', 'completed', 'cancelled')),
+    description TEXT NOT NULL,
+    technician_id BIGINT REFERENCES users(id),
+    schedule_id BIGINT REFERENCES maintenance_schedules(id),
+    odometer_meters BIGINT,
+    ride_count INT NOT NULL DEFAULT 0,
+    labour_minutes INT NOT NULL DEFAULT 0 CHECK (labour_minutes >= 0),
+    labour_cost BIGINT NOT NULL DEFAULT 0 CHECK (labour_cost >= 0),
+    parts_cost BIGINT NOT NULL DEFAULT 0 CHECK (parts_cost >= 0),
+    cost BIGINT NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    currency VARCHAR(3) NOT NULL,
+    resolution_note TEXT,
+    opened_by BIGINT REFERENCES users(id),
+    closed_by BIGINT REFERENCES users(id),
+    started_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- Bir motorun aynı türde tek açık iş emri olabilir; plan worker'ı aynı bakımı tekrar açmaz
+CREATE UNIQUE INDEX uq_work_orders_open_type ON work_orders(motorbike_id, type) WHERE status IN ('open', 'in_progress') AND deleted_at IS NULL;
+CREATE INDEX idx_work_orders_motorbike_id ON work_orders(motorbike_id, created_at);
+CREATE INDEX idx_work_orders_status ON work_orders(status, priority);
+CREATE INDEX idx_work_orders_technician_id ON work_orders(technician_id);
+
+CREATE TABLE work_order_parts (
+    id BIGSERIAL PRIMARY KEY,
+    work_order_id BIGINT NOT NULL REFERENCES work_orders(id),
+    name VARCHAR(255) NOT NULL,
+    part_number VARCHAR(64),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_cost BIGINT NOT NULL CHECK (unit_cost >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_work_order_parts_work_order_id ON work_order_parts(work_order_id);
+
+CREATE OR REPLACE FUNCTION update_maintenance_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_work_orders_updated_at
+    BEFORE UPDATE ON work_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_maintenance_updated_at();
+
+CREATE TRIGGER update_maintenance_schedules_updated_at
+    BEFORE UPDATE ON maintenance_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_maintenance_updated_at();
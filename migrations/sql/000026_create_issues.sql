@@ -0,0 +1,58 @@
+-- Kullanıcı bildirimlerinden açılan, belirli bir parçaya bağlanamayan arızalar için genel kontrol türü
+ALTER TABLE work_orders DROP CONSTRAINT IF EXISTS work_orders_type_check;
+ALTER TABLE work_orders ADD CONSTRAINT work_orders_type_check CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork', 'inspection'));
+ALTER TABLE maintenance_schedules DROP CONSTRAINT IF EXISTS maintenance_schedules_type_check;
+ALTER TABLE maintenance_schedules ADD CONSTRAINT maintenance_schedules_type_check CHECK (type IN ('tyre', 'brakes', 'battery', 'bodywork', 'inspection'));
+
+-- Kullanıcıların arıza ve hasar bildirimleri
+CREATE TABLE issues (
+    id BIGSERIAL PRIMARY KEY,
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    ride_id BIGINT REFERENCES rides(id),
+    category VARCHAR(16) NOT NULL CHECK (category IN ('brakes', 'tyre', 'battery', 'bodywork', 'electrical', 'other')),
+    severity VARCHAR(16) NOT NULL CHECK (severity IN ('low', 'medium', 'high', 'critical')),
+    description TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('open', 'resolved', 'duplicate')),
+    duplicate_of_id BIGINT REFERENCES issues(id),
+    work_order_id BIGINT REFERENCES work_orders(id),
+    resolved_by BIGINT REFERENCES users(id),
+    resolution_note TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT issues_duplicate_check CHECK ((status = 'duplicate') = (duplicate_of_id IS NOT NULL))
+);
+
+CREATE INDEX idx_issues_motorbike_id ON issues(motorbike_id, status, created_at);
+CREATE INDEX idx_issues_user_id ON issues(user_id, created_at);
+CREATE INDEX idx_issues_status ON issues(status, severity, created_at);
+CREATE INDEX idx_issues_duplicate_of_id ON issues(duplicate_of_id);
+
+CREATE TABLE issue_photos (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id BIGINT NOT NULL REFERENCES issues(id),
+    key VARCHAR(512) NOT NULL,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_issue_photos_issue_id ON issue_photos(issue_id);
+
+CREATE OR REPLACE FUNCTION update_issues_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_issues_updated_at
+    BEFORE UPDATE ON issues
+    FOR EACH ROW
+    EXECUTE FUNCTION update_issues_updated_at();
@@ -0,0 +1,58 @@
+-- Saha ekibi rolü
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'operator';
+
+-- Saha ekibi görevleri: motorun yerini değiştirme, batarya değişimi, hatalı parkı toplama, kontrol
+CREATE TABLE tasks (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('rebalance', 'battery_swap', 'collect', 'inspect')),
+    source VARCHAR(16) NOT NULL CHECK (source IN ('manual', 'low_battery', 'idle')),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('open', 'claimed', 'completed', 'cancelled')),
+    motorbike_id BIGINT NOT NULL REFERENCES motorbikes(id),
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    target_zone_id BIGINT REFERENCES zones(id),
+    note TEXT,
+    assignee_id BIGINT REFERENCES users(id),
+    due_at TIMESTAMPTZ NOT NULL,
+    claimed_at TIMESTAMPTZ,
+    resolution_note TEXT,
+    created_by BIGINT REFERENCES users(id),
+    closed_by BIGINT REFERENCES users(id),
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT tasks_claimed_check CHECK (status <> 'claimed' OR assignee_id IS NOT NULL)
+);
+
+CREATE INDEX idx_tasks_status ON tasks(status, due_at);
+CREATE INDEX idx_tasks_motorbike_id ON tasks(motorbike_id, type, created_at);
+CREATE INDEX idx_tasks_assignee_id ON tasks(assignee_id, status);
+CREATE INDEX idx_tasks_location ON tasks(latitude, longitude) WHERE status = 'open';
+
+CREATE TABLE task_photos (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES tasks(id),
+    key VARCHAR(512) NOT NULL,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_task_photos_task_id ON task_photos(task_id);
+
+CREATE OR REPLACE FUNCTION update_tasks_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_tasks_updated_at
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_tasks_updated_at();
@@ -0,0 +1,79 @@
+-- Roller ve yetkiler; admin rolü tüm yetkilere sahip olduğundan yetkileri tabloda tutulmaz
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]*$'),
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Tüm yetkilere sahip yönetici'),
+    ('user', 'Motor kiralayan kullanıcı'),
+    ('operator', 'Saha ekibi; görevleri üstlenir ve tamamlar');
+
+INSERT INTO permissions (name, description) VALUES
+    ('users.read', 'Kullanıcıları ve cüzdanlarını görüntüleme'),
+    ('users.write', 'Kullanıcı oluşturma ve güncelleme'),
+    ('users.ban', 'Kullanıcı durumunu değiştirme (engelleme)'),
+    ('users.delete', 'Kullanıcı silme'),
+    ('wallets.adjust', 'Cüzdana bakiye yükleme, iade ve düzeltme'),
+    ('rides.read', 'Tüm sürüşleri görüntüleme'),
+    ('rides.delete', 'Sürüş silme'),
+    ('rides.refund', 'İtirazı onaylayıp sürüş ücretini iade etme'),
+    ('disputes.read', 'İtiraz kuyruğunu görüntüleme'),
+    ('disputes.review', 'İtirazı incelemeye alma ve reddetme'),
+    ('motorbikes.read', 'Motor listeleri, fotoğrafları, telemetri ve servis geçmişi'),
+    ('motorbikes.write', 'Motor ve fotoğraf yönetimi, cihaz anahtarı üretme'),
+    ('motorbikes.command', 'Motora kilitleme, açma, ses ve devre dışı bırakma komutu gönderme'),
+    ('maintenance.read', 'İş emirlerini ve bakım planlarını görüntüleme'),
+    ('maintenance.write', 'İş emri ve bakım planı yönetimi; iş emri atanabilir'),
+    ('issues.read', 'Arıza bildirimi kuyruğunu görüntüleme'),
+    ('issues.triage', 'Arıza bildirimlerini sonuçlandırma'),
+    ('tasks.work', 'Saha görevlerini üstlenme ve tamamlama; görev atanabilir'),
+    ('tasks.manage', 'Saha görevi açma, atama ve iptal etme'),
+    ('bluetooth.read', 'Bluetooth bağlantılarını görüntüleme'),
+    ('bluetooth.write', 'Bluetooth bağlantısı yönetimi'),
+    ('tariffs.read', 'Tarifeleri görüntüleme'),
+    ('tariffs.write', 'Tarife yönetimi'),
+    ('promotions.read', 'Kampanyaları ve kullanım raporlarını görüntüleme'),
+    ('promotions.write', 'Kampanya yönetimi'),
+    ('passes.read', 'Abonelik paketlerini görüntüleme'),
+    ('passes.write', 'Abonelik paketi yönetimi'),
+    ('zones.read', 'Alanları görüntüleme'),
+    ('zones.write', 'Alan yönetimi'),
+    ('roles.manage', 'Rol ve yetki yönetimi, kullanıcılara rol atama');
+
+INSERT INTO role_permissions (role, permission) VALUES ('operator', 'tasks.work');
+
+-- Kullanıcı rolü enum yerine roles tablosuna bağlanır
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50) USING role::text;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
+DROP TYPE user_role;
+
+CREATE INDEX idx_users_role ON users(role);
+
+CREATE OR REPLACE FUNCTION update_roles_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_roles_updated_at
+    BEFORE UPDATE ON roles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_roles_updated_at();
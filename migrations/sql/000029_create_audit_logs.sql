@@ -0,0 +1,47 @@
+-- Denetim kayıtları; yalnızca eklenebilir, her kayıt bir öncekinin özetini içerir (hash zinciri).
+-- Aktör ve hedef için foreign key tanımlanmaz: silinen kullanıcının ve kaydın izi kalmalıdır.
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    actor_id BIGINT,
+    actor_role VARCHAR(50),
+    action VARCHAR(150) NOT NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(100),
+    before JSONB,
+    after JSONB,
+    method VARCHAR(10),
+    path TEXT,
+    status_code INT,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    request_id VARCHAR(64),
+    prev_hash CHAR(64),
+    hash CHAR(64) NOT NULL,
+    CONSTRAINT audit_logs_hash_unique UNIQUE (hash)
+);
+
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_target ON audit_logs(target_type, target_id, created_at);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action, created_at);
+
+CREATE OR REPLACE FUNCTION audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs yalnızca eklemeye açıktır';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_logs_no_update
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_logs_append_only();
+
+CREATE TRIGGER audit_logs_no_truncate
+    BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION audit_logs_append_only();
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit.read', 'Denetim kayıtlarını görüntüleme, dışa aktarma ve doğrulama');
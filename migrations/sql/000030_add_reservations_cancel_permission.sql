@@ -0,0 +1,2 @@
+INSERT INTO permissions (name, description) VALUES
+    ('reservations.cancel', 'Başka kullanıcıların rezervasyonunu iptal etme');
@@ -0,0 +1,7 @@
+-- Tekrar denenen ödeme sağlayıcıya gitmeden önce settling durumuna alınır; aynı anda ikinci deneme yapılamaz
+ALTER TABLE ride_payments DROP CONSTRAINT IF EXISTS ride_payments_status_check;
+ALTER TABLE ride_payments ADD CONSTRAINT ride_payments_status_check
+    CHECK (status IN ('authorized', 'captured', 'voided', 'failed', 'settling'));
+
+-- Her deneme sağlayıcıya ride-<id>-retry-<n> referansıyla gider; aynı deneme tekrar gönderilirse yeni provizyon açılmaz
+ALTER TABLE ride_payments ADD COLUMN IF NOT EXISTS retry_count INT NOT NULL DEFAULT 0;
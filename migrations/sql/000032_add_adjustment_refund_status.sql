@@ -0,0 +1,28 @@
+-- Karta iade, itiraz onayı ve iade kaydı commit edildikten sonra sağlayıcıda yapılır. Kayıt pending olarak yazılır ve
+-- sağlayıcının sonucu ayrı bir transaction'da eklenir; pending veya failed kalan kayıtlar mutabakat gerektirir.
+DROP TRIGGER IF EXISTS prevent_ride_adjustments_modification ON ride_adjustments;
+
+ALTER TABLE ride_adjustments ADD COLUMN IF NOT EXISTS provider_refund_status VARCHAR(16)
+    CHECK (provider_refund_status IN ('pending', 'succeeded', 'failed'));
+UPDATE ride_adjustments SET provider_refund_status = 'succeeded' WHERE destination = 'card';
+
+CREATE INDEX idx_ride_adjustments_provider_refund_status ON ride_adjustments(provider_refund_status)
+    WHERE provider_refund_status IN ('pending', 'failed');
+
+-- İade kayıtları değiştirilemez; yalnızca bekleyen karta iadenin sonucu bir kez yazılabilir
+CREATE OR REPLACE FUNCTION prevent_ride_adjustments_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.provider_refund_status = 'pending'
+        AND to_jsonb(NEW) - 'provider_refund_id' - 'provider_refund_status'
+            = to_jsonb(OLD) - 'provider_refund_id' - 'provider_refund_status' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'ledger kayıtları değiştirilemez (%)', TG_TABLE_NAME;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER prevent_ride_adjustments_modification
+    BEFORE UPDATE OR DELETE ON ride_adjustments
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ride_adjustments_modification();
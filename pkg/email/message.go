@@ -0,0 +1,137 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Attachment e-postaya eklenecek dosya
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message düz metin, isteğe bağlı HTML gövde ve ekler içeren e-posta
+type Message struct {
+	To          string
+	Subject     string
+	Body        string // text/plain
+	HTMLBody    string // boş değilse düz metinle birlikte multipart/alternative olarak gönderilir
+	Attachments []Attachment
+}
+
+// SendMessage mesajı MIME multipart olarak gönderir
+func (e *Email) SendMessage(msg Message) error {
+	raw, err := msg.Bytes(e.From, time.Now())
+	if err != nil {
+		return err
+	}
+	auth := smtp.PlainAuth("", e.From, e.Password, e.SMTPHost)
+	return smtp.SendMail(e.SMTPHost+":"+e.SMTPPort, auth, e.From, []string{msg.To}, raw)
+}
+
+// Bytes mesajı RFC 5322 formatında döner. Gövde multipart/mixed olup ilk parça metin (veya metin ve HTML),
+// sonraki parçalar base64 kodlanmış eklerdir.
+func (m Message) Bytes(from string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	header("From", from)
+	header("To", m.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()}))
+	buf.WriteString("\r\n")
+
+	if err := m.writeBody(mixed); err != nil {
+		return nil, err
+	}
+	for _, attachment := range m.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = writeBase64(part, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m Message) writeBody(mixed *multipart.Writer) error {
+	if m.HTMLBody == "" {
+		return writeTextPart(mixed, "text/plain", m.Body)
+	}
+
+	var alternative bytes.Buffer
+	inner := multipart.NewWriter(&alternative)
+	if err := writeTextPart(inner, "text/plain", m.Body); err != nil {
+		return err
+	}
+	if err := writeTextPart(inner, "text/html", m.HTMLBody); err != nil {
+		return err
+	}
+	if err := inner.Close(); err != nil {
+		return err
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": inner.Boundary()})},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(alternative.Bytes())
+	return err
+}
+
+func writeTextPart(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	return writeBase64(part, []byte(body))
+}
+
+// writeBase64 veriyi RFC 2045'e uygun olarak 76 karakterlik satırlara bölerek yazar
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+	_, err := w.Write([]byte(b.String()))
+	return err
+}
@@ -1,6 +1,7 @@
 package errorx
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -67,3 +68,13 @@ func Wrap(base *AppError, err error, customMessage string) *AppError {
 		Err:     err,
 	}
 }
+
+// FromError err zaten bir AppError ise olduğu gibi döner, değilse base ile sarmalar.
+// Transaction gibi hem AppError hem ham hata dönebilen çağrıların sonucunu tek tipe indirmek için kullanılır.
+func FromError(base *AppError, err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return WrapErr(base, err)
+}
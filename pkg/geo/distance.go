@@ -0,0 +1,51 @@
+package geo
+
+import "math"
+
+const earthRadiusMeters = 6371000.0
+
+// BoundingBox enlem/boylam sınırları olan dikdörtgen alan
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// HaversineMeters iki nokta arasındaki büyük daire mesafesini metre cinsinden döner
+func HaversineMeters(a, b Point) float64 {
+	lat1, lat2 := toRadians(a.Lat), toRadians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBoxAround merkezden radiusMeters uzaklıktaki tüm noktaları içeren dikdörtgeni döner.
+// Veritabanında indeksli ön filtre olarak kullanılır; kesin mesafe HaversineMeters ile kontrol edilmelidir.
+func BoundingBoxAround(center Point, radiusMeters float64) BoundingBox {
+	latDelta := radiusMeters / earthRadiusMeters * 180 / math.Pi
+
+	box := BoundingBox{
+		MinLat: math.Max(center.Lat-latDelta, -90),
+		MaxLat: math.Min(center.Lat+latDelta, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	// Kutuplara yakın veya 180. meridyeni aşan durumlarda boylam filtresi uygulanmaz
+	cosLat := math.Cos(toRadians(center.Lat))
+	if cosLat <= 0 || box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+	lngDelta := latDelta / cosLat
+	if center.Lng-lngDelta < -180 || center.Lng+lngDelta > 180 {
+		return box
+	}
+	box.MinLng = center.Lng - lngDelta
+	box.MaxLng = center.Lng + lngDelta
+	return box
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,55 @@
+package geo
+
+// Point enlem/boylam çifti (WGS84, derece)
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Ring kapalı bir çokgen halkası. İlk ve son noktanın aynı olması gerekmez.
+type Ring []Point
+
+// Polygon ilk halkası dış sınır, diğer halkaları delik olan çokgen
+type Polygon []Ring
+
+// MultiPolygon birden fazla parçadan oluşan alan
+type MultiPolygon []Polygon
+
+// Contains noktanın halkanın içinde olup olmadığını ışın gönderme (ray casting) yöntemiyle belirler.
+// Şehir ölçeğindeki alanlar için düzlemsel hesap yeterlidir.
+func (r Ring) Contains(p Point) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) {
+			crossLng := a.Lng + (p.Lat-a.Lat)*(b.Lng-a.Lng)/(b.Lat-a.Lat)
+			if p.Lng < crossLng {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Contains nokta dış sınırın içinde ve deliklerin dışındaysa true döner
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) == 0 || !p[0].Contains(pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.Contains(pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains nokta parçalardan herhangi birinin içindeyse true döner
+func (m MultiPolygon) Contains(pt Point) bool {
+	for _, polygon := range m {
+		if polygon.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
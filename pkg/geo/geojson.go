@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidGeoJSON = errors.New("geçersiz GeoJSON")
+
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    json.RawMessage `json:"geometry"`
+	Features    []geoJSONObject `json:"features"`
+}
+
+// ParseGeoJSON Polygon, MultiPolygon, Feature veya FeatureCollection içeren GeoJSON'u MultiPolygon'a çevirir.
+// GeoJSON koordinatları [boylam, enlem] sırasındadır.
+func ParseGeoJSON(data []byte) (MultiPolygon, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+
+	result, err := obj.toMultiPolygon()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: alan bulunamadı", ErrInvalidGeoJSON)
+	}
+	return result, nil
+}
+
+func (o geoJSONObject) toMultiPolygon() (MultiPolygon, error) {
+	switch o.Type {
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(o.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		polygon, err := toPolygon(coords)
+		if err != nil {
+			return nil, err
+		}
+		return MultiPolygon{polygon}, nil
+
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(o.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		result := make(MultiPolygon, 0, len(coords))
+		for _, c := range coords {
+			polygon, err := toPolygon(c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, polygon)
+		}
+		return result, nil
+
+	case "Feature":
+		var geometry geoJSONObject
+		if err := json.Unmarshal(o.Geometry, &geometry); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		return geometry.toMultiPolygon()
+
+	case "FeatureCollection":
+		var result MultiPolygon
+		for _, feature := range o.Features {
+			part, err := feature.toMultiPolygon()
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, part...)
+		}
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("%w: desteklenmeyen tip %q", ErrInvalidGeoJSON, o.Type)
+	}
+}
+
+func toPolygon(coords [][][]float64) (Polygon, error) {
+	if len(coords) == 0 {
+		return nil, fmt.Errorf("%w: çokgenin dış sınırı yok", ErrInvalidGeoJSON)
+	}
+
+	polygon := make(Polygon, 0, len(coords))
+	for _, ringCoords := range coords {
+		if len(ringCoords) < 3 {
+			return nil, fmt.Errorf("%w: halka en az 3 noktadan oluşmalı", ErrInvalidGeoJSON)
+		}
+		ring := make(Ring, 0, len(ringCoords))
+		for _, c := range ringCoords {
+			if len(c) < 2 {
+				return nil, fmt.Errorf("%w: koordinat [boylam, enlem] olmalı", ErrInvalidGeoJSON)
+			}
+			if c[0] < -180 || c[0] > 180 || c[1] < -90 || c[1] > 90 {
+				return nil, fmt.Errorf("%w: koordinat aralık dışında", ErrInvalidGeoJSON)
+			}
+			ring = append(ring, Point{Lat: c[1], Lng: c[0]})
+		}
+		polygon = append(polygon, ring)
+	}
+	return polygon, nil
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// TrackPoint zaman damgalı konum
+type TrackPoint struct {
+	Point
+	Time time.Time
+}
+
+type geoJSONLineString struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string             `json:"type"`
+	Geometry   *geoJSONLineString `json:"geometry"`
+	Properties map[string]any     `json:"properties"`
+}
+
+// MarshalLineString izi GeoJSON Feature (LineString) olarak döner. Nokta zamanları "coordTimes"
+// özelliğinde koordinatlarla aynı sırada verilir. LineString en az iki nokta gerektirdiğinden
+// daha kısa izlerde geometry null döner.
+func MarshalLineString(points []TrackPoint, properties map[string]any) ([]byte, error) {
+	feature := geoJSONFeature{Type: "Feature", Properties: map[string]any{}}
+	for k, v := range properties {
+		feature.Properties[k] = v
+	}
+
+	times := make([]string, len(points))
+	for i, p := range points {
+		times[i] = p.Time.UTC().Format(time.RFC3339)
+	}
+	feature.Properties["coordTimes"] = times
+
+	if len(points) >= 2 {
+		line := &geoJSONLineString{Type: "LineString", Coordinates: make([][]float64, len(points))}
+		for i, p := range points {
+			line.Coordinates[i] = []float64{p.Lng, p.Lat}
+		}
+		feature.Geometry = line
+	}
+
+	return json.Marshal(feature)
+}
+
+type gpxDocument struct {
+	XMLName xml.Name `xml:"gpx"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"creator,attr"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Track   gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name    string     `xml:"name"`
+	Segment gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// MarshalGPX izi tek parçalı bir GPX 1.1 track olarak döner
+func MarshalGPX(name string, points []TrackPoint) ([]byte, error) {
+	doc := gpxDocument{
+		Version: "1.1",
+		Creator: "motorbike-rental-backend",
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Track: gpxTrack{
+			Name:    name,
+			Segment: gpxSegment{Points: make([]gpxPoint, len(points))},
+		},
+	}
+	for i, p := range points {
+		doc.Track.Segment.Points[i] = gpxPoint{Lat: p.Lat, Lon: p.Lng, Time: p.Time.UTC().Format(time.RFC3339)}
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
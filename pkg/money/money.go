@@ -0,0 +1,34 @@
+package money
+
+import (
+	"fmt"
+	"math"
+)
+
+// Para tutarları tüm sistemde para biriminin en küçük biriminde (kuruş, cent) int64 olarak tutulur
+const DefaultCurrency = "TRY"
+
+// Format en küçük birimdeki tutarı okunabilir hale getirir, örn: 1250 -> "12.50 TRY"
+func Format(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, currency)
+}
+
+// ApplyPercent tutara yüzde uygular ve en yakın tam birime yuvarlar, örn: ApplyPercent(300, 150) -> 450
+func ApplyPercent(amount int64, percent int) int64 {
+	return int64(math.Round(float64(amount) * float64(percent) / 100))
+}
+
+// SplitInclusive vergi dahil tutarı vergisiz tutar ve vergiye ayırır, örn: SplitInclusive(1200, 20) -> 1000, 200.
+// Yuvarlama farkı vergiye yansır; iki tutarın toplamı her zaman amount'a eşittir.
+func SplitInclusive(amount int64, ratePct int) (net, tax int64) {
+	if ratePct <= 0 {
+		return amount, 0
+	}
+	net = int64(math.Round(float64(amount) * 100 / float64(100+ratePct)))
+	return net, amount - net
+}
@@ -0,0 +1,259 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Behavior sahte sağlayıcının bir işleme vereceği cevap
+type Behavior string
+
+const (
+	Succeed Behavior = "succeed"
+	Decline Behavior = "decline"
+	Timeout Behavior = "timeout"
+)
+
+// Operation sahte sağlayıcıda davranışı ayarlanabilen işlem
+type Operation string
+
+const (
+	OpAuthorize Operation = "authorize"
+	OpCapture   Operation = "capture"
+	OpVoid      Operation = "void"
+	OpRefund    Operation = "refund"
+)
+
+// FakeProvider ağ bağlantısı olmadan çalışan, deterministik ödeme sağlayıcısı. Yerel geliştirme ve testlerde
+// kullanılır. Varsayılan davranış tüm işlemler için geçerlidir; Next ile sıradaki çağrıların sonucu ayrıca belirlenir.
+// Kimlikler sıralı üretilir (fake_auth_1, fake_cap_1, ...).
+type FakeProvider struct {
+	mu             sync.Mutex
+	secret         []byte
+	behavior       Behavior
+	queued         map[Operation][]Behavior
+	seq            int
+	authorizations map[string]*fakeAuthorization
+	references     map[string]string
+	captures       map[string]*fakeCapture
+	calls          map[Operation]int
+}
+
+type fakeAuthorization struct {
+	Authorization
+	captured int64
+}
+
+type fakeCapture struct {
+	Capture
+	refunded int64
+}
+
+var _ PaymentProvider = (*FakeProvider)(nil)
+
+// NewFakeProvider webhook imzaları için secret kullanan, varsayılan olarak her işlemi başarılı sayan sağlayıcı oluşturur
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:         []byte(secret),
+		behavior:       Succeed,
+		queued:         map[Operation][]Behavior{},
+		authorizations: map[string]*fakeAuthorization{},
+		references:     map[string]string{},
+		captures:       map[string]*fakeCapture{},
+		calls:          map[Operation]int{},
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// SetBehavior sırada bekleyen davranışı olmayan tüm işlemlerin sonucunu belirler
+func (p *FakeProvider) SetBehavior(b Behavior) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.behavior = b
+}
+
+// Next işlemin sıradaki çağrılarının sonuçlarını sırayla belirler
+func (p *FakeProvider) Next(op Operation, behaviors ...Behavior) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued[op] = append(p.queued[op], behaviors...)
+}
+
+// Calls işlemin kaç kez çağrıldığını döner
+func (p *FakeProvider) Calls(op Operation) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[op]
+}
+
+// GetAuthorization provizyonun güncel durumunu döner
+func (p *FakeProvider) GetAuthorization(id string) (*Authorization, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	auth, ok := p.authorizations[id]
+	if !ok {
+		return nil, false
+	}
+	cp := auth.Authorization
+	return &cp, true
+}
+
+func (p *FakeProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.outcome(ctx, OpAuthorize); err != nil {
+		return nil, err
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: tutar sıfırdan büyük olmalı", ErrDeclined)
+	}
+
+	if req.Reference != "" {
+		if id, ok := p.references[req.Reference]; ok {
+			cp := p.authorizations[id].Authorization
+			return &cp, nil
+		}
+	}
+
+	auth := &fakeAuthorization{Authorization: Authorization{
+		ID:       p.nextID("fake_auth"),
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Status:   AuthorizationOpen,
+	}}
+	p.authorizations[auth.ID] = auth
+	if req.Reference != "" {
+		p.references[req.Reference] = auth.ID
+	}
+	cp := auth.Authorization
+	return &cp, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, authorizationID string, amount int64) (*Capture, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.outcome(ctx, OpCapture); err != nil {
+		return nil, err
+	}
+
+	auth, ok := p.authorizations[authorizationID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if auth.Status != AuthorizationOpen {
+		return nil, ErrInvalidState
+	}
+	if amount <= 0 || amount > auth.Amount {
+		return nil, ErrAmountExceeded
+	}
+
+	auth.Status = AuthorizationCaptured
+	auth.captured = amount
+	capture := &fakeCapture{Capture: Capture{ID: p.nextID("fake_cap"), AuthorizationID: authorizationID, Amount: amount}}
+	p.captures[capture.ID] = capture
+	cp := capture.Capture
+	return &cp, nil
+}
+
+func (p *FakeProvider) Void(ctx context.Context, authorizationID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.outcome(ctx, OpVoid); err != nil {
+		return err
+	}
+
+	auth, ok := p.authorizations[authorizationID]
+	if !ok {
+		return ErrNotFound
+	}
+	switch auth.Status {
+	case AuthorizationVoided:
+		return nil
+	case AuthorizationCaptured:
+		return ErrInvalidState
+	}
+	auth.Status = AuthorizationVoided
+	return nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, captureID string, amount int64) (*Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.outcome(ctx, OpRefund); err != nil {
+		return nil, err
+	}
+
+	capture, ok := p.captures[captureID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if amount <= 0 || capture.refunded+amount > capture.Amount {
+		return nil, ErrAmountExceeded
+	}
+	capture.refunded += amount
+	return &Refund{ID: p.nextID("fake_ref"), CaptureID: captureID, Amount: amount}, nil
+}
+
+// ParseWebhook HMAC-SHA256 imzasını (hex) doğrular ve JSON olayı çözer
+func (p *FakeProvider) ParseWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.sign(payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err = json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("%w: id ve type zorunlu", ErrInvalidWebhook)
+	}
+	return &event, nil
+}
+
+// SignWebhook sahte sağlayıcının göndereceği olay için imza üretir
+func (p *FakeProvider) SignWebhook(payload []byte) string {
+	return hex.EncodeToString(p.sign(payload))
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// outcome işlemin bu çağrıdaki sonucunu belirler; p.mu tutulurken çağrılmalıdır
+func (p *FakeProvider) outcome(ctx context.Context, op Operation) error {
+	p.calls[op]++
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTimeout, err)
+	}
+
+	behavior := p.behavior
+	if queue := p.queued[op]; len(queue) > 0 {
+		behavior = queue[0]
+		p.queued[op] = queue[1:]
+	}
+
+	switch behavior {
+	case Decline:
+		return ErrDeclined
+	case Timeout:
+		return ErrTimeout
+	default:
+		return nil
+	}
+}
+
+func (p *FakeProvider) nextID(prefix string) string {
+	p.seq++
+	return fmt.Sprintf("%s_%d", prefix, p.seq)
+}
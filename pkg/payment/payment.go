@@ -0,0 +1,84 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+// Sağlayıcıdan bağımsız hata türleri. Sağlayıcı implementasyonları kendi hatalarını bunlara sarmalar.
+var (
+	ErrDeclined         = errors.New("ödeme reddedildi")
+	ErrTimeout          = errors.New("ödeme sağlayıcısı zaman aşımına uğradı")
+	ErrNotFound         = errors.New("ödeme kaydı bulunamadı")
+	ErrInvalidState     = errors.New("ödeme bu işlem için uygun durumda değil")
+	ErrAmountExceeded   = errors.New("tutar izin verilen miktarı aşıyor")
+	ErrInvalidSignature = errors.New("webhook imzası geçersiz")
+	ErrInvalidWebhook   = errors.New("webhook içeriği geçersiz")
+)
+
+// AuthorizeRequest karttan tutarın bloke edilmesi (ön provizyon) isteği. Tutarlar en küçük para biriminde.
+type AuthorizeRequest struct {
+	Amount      int64
+	Currency    string
+	CustomerID  string
+	Reference   string // aynı referansla tekrar gelen istek yeni provizyon açmaz
+	Description string
+}
+
+type AuthorizationStatus string
+
+const (
+	AuthorizationOpen     AuthorizationStatus = "authorized"
+	AuthorizationCaptured AuthorizationStatus = "captured"
+	AuthorizationVoided   AuthorizationStatus = "voided"
+)
+
+type Authorization struct {
+	ID       string
+	Amount   int64
+	Currency string
+	Status   AuthorizationStatus
+}
+
+type Capture struct {
+	ID              string
+	AuthorizationID string
+	Amount          int64
+}
+
+type Refund struct {
+	ID        string
+	CaptureID string
+	Amount    int64
+}
+
+type EventType string
+
+const (
+	EventCaptureSucceeded    EventType = "capture.succeeded"
+	EventCaptureFailed       EventType = "capture.failed" // sonradan gelen ret, örn: banka itirazı
+	EventAuthorizationVoided EventType = "authorization.voided"
+	EventRefundSucceeded     EventType = "refund.succeeded"
+)
+
+// WebhookEvent sağlayıcının asenkron bildirdiği olay
+type WebhookEvent struct {
+	ID              string    `json:"id"`
+	Type            EventType `json:"type"`
+	AuthorizationID string    `json:"authorization_id"`
+	Amount          int64     `json:"amount"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+// PaymentProvider kart ödemelerini alan sağlayıcının arayüzü. Akış: Authorize ile tutar bloke edilir,
+// sürüş bitince Capture ile kesin tutar tahsil edilir veya Void ile blokaj kaldırılır; tahsil edilen tutar Refund ile iade edilir.
+type PaymentProvider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	// Capture provizyonun tamamını veya bir kısmını tahsil eder, kalan blokaj kaldırılır
+	Capture(ctx context.Context, authorizationID string, amount int64) (*Capture, error)
+	Void(ctx context.Context, authorizationID string) error
+	Refund(ctx context.Context, captureID string, amount int64) (*Refund, error)
+	// ParseWebhook imzayı doğrular ve olayı çözer
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
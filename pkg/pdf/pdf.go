@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 sayfa boyutu (pt)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font belgede kullanılabilen standart PDF fontları. Standart fontlar dosyaya gömülmez, her okuyucuda bulunur.
+type Font string
+
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+)
+
+// Document harici bağımlılık olmadan metin ve çizgiden oluşan basit PDF belgeleri üretir.
+// Koordinatlar sayfanın sol üst köşesinden itibaren pt cinsindendir.
+type Document struct {
+	pages []*bytes.Buffer
+	title string
+}
+
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.AddPage()
+	return d
+}
+
+// AddPage yeni bir sayfa açar; sonraki çizimler bu sayfaya yapılır
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text metni x noktasından başlayarak yazar
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, encode(s))
+}
+
+// TextRight metni x noktasında bitecek şekilde sağa hizalı yazar
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(s, size), y, font, size, s)
+}
+
+// Line iki nokta arasına ince bir çizgi çizer
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes belgeyi PDF 1.4 formatında döner
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: katalog, 2: sayfa ağacı, 3-4: fontlar, 5: bilgi, sonrasında her sayfa için içerik ve sayfa nesnesi
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2+1)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (motorbike-rental-backend-v2) >>", encode(d.title)))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents %d 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> >>",
+			PageWidth, PageHeight, firstPage+i*2))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// WinAnsiEncoding'de bulunmayan Türkçe harfler en yakın karşılıklarıyla yazılır
+var transliterations = map[rune]string{
+	'ğ': "g", 'Ğ': "G", 'ş': "s", 'Ş': "S", 'ı': "i", 'İ': "I", '₺': "TL",
+}
+
+// encode metni WinAnsiEncoding'e çevirir ve PDF string literal'i için kaçış karakterlerini ekler
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if t, ok := transliterations[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 aralığı WinAnsiEncoding ile aynıdır (ç, ö, ü, Ç, Ö, Ü ...)
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Helvetica karakter genişlikleri (1/1000 em), ASCII 32-126
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// TextWidth metnin Helvetica ile yazıldığında kaplayacağı genişliği yaklaşık olarak hesaplar.
+// Kalın yazı biraz daha geniştir ancak hizalama için bu yaklaşım yeterlidir.
+func TextWidth(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += helveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// Kabul edilen fotoğraf türleri ve kaydedildikleri dosya uzantıları
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// Çözülmeden önce reddedilen en büyük piksel sayısı; küçük bir dosyanın bellekte devasa bir görüntüye açılmasını önler
+const maxImagePixels = 50_000_000
+
+// Image doğrulanmış ve yeniden kodlanmış fotoğraf. Yeniden kodlama EXIF dahil tüm üst verileri (konum, cihaz bilgisi)
+// siler; EXIF'teki yön bilgisi silinmeden önce piksellere uygulanır.
+type Image struct {
+	ContentType string
+	Ext         string
+	Data        []byte
+	Width       int
+	Height      int
+	Thumbnail   []byte // her zaman JPEG
+}
+
+// ProcessImage fotoğrafın türünü içeriğinden belirler, boyutunu kontrol eder, üst verilerini temizler ve
+// en uzun kenarı thumbSize pikseli geçmeyen bir küçük resim üretir
+func ProcessImage(data []byte, maxBytes int64, thumbSize int) (*Image, error) {
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return nil, ErrInvalidImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrInvalidImage
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	img := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	var out bytes.Buffer
+	if contentType == "image/png" {
+		err = png.Encode(&out, img)
+	} else {
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var thumb bytes.Buffer
+	if err = jpeg.Encode(&thumb, thumbnail(img, thumbSize), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		ContentType: contentType,
+		Ext:         ext,
+		Data:        out.Bytes(),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Thumbnail:   thumb.Bytes(),
+	}, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// orient EXIF yön değerine (1-8) göre görüntüyü döndürür/aynalar
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// thumbnail görüntüyü oranını koruyarak en uzun kenarı size pikseli geçmeyecek şekilde küçültür (kutu filtresi)
+func thumbnail(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	tw, th = max(tw, 1), max(th, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := ty*h/th, max((ty+1)*h/th, ty*h/th+1)
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := tx*w/tw, max((tx+1)*w/tw, tx*w/tw+1)
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					i := src.PixOffset(x, y)
+					for c := 0; c < 4; c++ {
+						sum[c] += int(src.Pix[i+c])
+					}
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			i := dst.PixOffset(tx, ty)
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// jpegOrientation JPEG dosyasının EXIF (APP1) bölümündeki yön değerini okur; bulunamazsa 1 döner
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // görüntü verisi başladı
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation TIFF yapısındaki ilk IFD'den yön (0x0112) etiketini okur
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// URLSigner depodaki dosyalar için süreli, imzalı bağlantılar üretir ve doğrular. Bağlantı, anahtarı ve son
+// geçerlilik zamanını içerir; imza secret ile HMAC-SHA256 olarak hesaplanır. Böylece dosyalar kimlik doğrulaması
+// olmadan (örn. <img> etiketinden) indirilebilir ama bağlantı paylaşılsa bile ttl sonunda geçersiz olur.
+type URLSigner struct {
+	secret  []byte
+	baseURL string // örn. "/api/v1/files"
+	ttl     time.Duration
+}
+
+func NewURLSigner(secret, baseURL string, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: []byte(secret), baseURL: baseURL, ttl: ttl}
+}
+
+// Sign anahtar için now+ttl zamanına kadar geçerli bağlantıyı ve son geçerlilik zamanını döner
+func (s *URLSigner) Sign(key string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(key, expires))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, uriEncodePath(key), query.Encode()), expiresAt
+}
+
+// Verify bağlantıdaki anahtar, son geçerlilik zamanı ve imzayı doğrular
+func (s *URLSigner) Verify(key, expires, signature string, now time.Time) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidURL
+	}
+	expected := s.signature(key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidURL
+	}
+	if now.Unix() > unix {
+		return ErrExpiredURL
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// Depolama arka ucundan bağımsız hata türleri
+var (
+	ErrNotFound     = errors.New("dosya bulunamadı")
+	ErrInvalidKey   = errors.New("dosya anahtarı geçersiz")
+	ErrInvalidURL   = errors.New("dosya bağlantısı geçersiz")
+	ErrExpiredURL   = errors.New("dosya bağlantısının süresi dolmuş")
+	ErrInvalidImage = errors.New("geçersiz veya desteklenmeyen fotoğraf")
+	ErrTooLarge     = errors.New("dosya boyutu sınırı aşıyor")
+)
+
+// Object depodaki bir dosyanın üst bilgileri
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+}
+
+// BlobStore dosyaları anahtar (örn. "rides/12/abc.jpg") ile saklayan depolama arayüzü.
+// Anahtarlar "/" ile ayrılmış göreli yollardır; ".." ve mutlak yollar kabul edilmez.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get dosyayı okumak için açar; dosya yoksa ErrNotFound döner. Okuyucu çağıran tarafından kapatılmalıdır.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Delete dosyayı siler; dosya yoksa hata dönmez
+	Delete(ctx context.Context, key string) error
+}
+
+// CleanKey anahtarı normalize eder ve depo dışına çıkan anahtarları reddeder
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
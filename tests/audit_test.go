@@ -0,0 +1,300 @@
+package tests
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/handler"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/middleware"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuditFixture() (*service.AuditService, *fakeAuditLogRepo) {
+	repo := &fakeAuditLogRepo{}
+	return service.NewAuditService(repo, &fakeTxManager{}), repo
+}
+
+func auditTestApp(audit *service.AuditService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			var appErr *errorx.AppError
+			if errors.As(err, &appErr) {
+				return c.SendStatus(appErr.Code)
+			}
+			return c.SendStatus(fiber.StatusInternalServerError)
+		},
+	})
+	app.Use(requestid.New())
+	// AuthMiddleware yerine kullanıcı header'dan alınır
+	app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("userID", int64(7))
+			c.Locals("role", model.Role(role))
+		}
+		return c.Next()
+	})
+	app.Use(middleware.AuditMiddleware(audit))
+	return app
+}
+
+func TestAuditChain(t *testing.T) {
+	ctx := context.Background()
+	actorID := int64(1)
+
+	t.Run("Verify Detects Tampering", func(t *testing.T) {
+		audit, repo := newAuditFixture()
+		for i := 0; i < 3; i++ {
+			err := audit.Record(ctx, model.AuditLog{
+				ActorID:    &actorID,
+				Action:     "users.update",
+				TargetType: "users",
+				TargetID:   "5",
+				Before:     json.RawMessage(`{"status":"active","credit":1e21}`),
+				After:      json.RawMessage(`{"status": "banned", "credit": 0.5}`),
+			})
+			assert.NoError(t, err)
+		}
+		assert.Empty(t, repo.logs[0].PrevHash)
+		assert.Equal(t, repo.logs[0].Hash, repo.logs[1].PrevHash)
+		assert.Equal(t, repo.logs[1].Hash, repo.logs[2].PrevHash)
+
+		result, err := audit.Verify(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuditVerification{Checked: 3, Valid: true}, *result)
+
+		repo.logs[1].After = json.RawMessage(`{"status":"active"}`)
+		result, err = audit.Verify(ctx)
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), result.BrokenAtID)
+		assert.Equal(t, 2, result.Checked)
+	})
+
+	t.Run("Verify Detects Deleted Entry", func(t *testing.T) {
+		audit, repo := newAuditFixture()
+		for _, action := range []string{"users.create", "users.update", "users.delete"} {
+			assert.NoError(t, audit.Record(ctx, model.AuditLog{Action: action, TargetType: "users", TargetID: "5"}))
+		}
+		repo.logs = append(repo.logs[:1], repo.logs[2])
+
+		result, err := audit.Verify(ctx)
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(3), result.BrokenAtID)
+	})
+}
+
+func TestAuditRecordChange(t *testing.T) {
+	ctx := context.Background()
+	audit, repo := newAuditFixture()
+
+	before := testUser(5, model.StatusActive)
+	before.Email = "ali@example.com"
+	before.Password = "hash"
+	before.UpdatedAt = time.Now().Add(-time.Hour)
+	after := before
+	after.Status = model.StatusBanned
+	after.Password = "new-hash"
+	after.UpdatedAt = time.Now()
+
+	audit.RecordChange(ctx, "users.update", "users", before.ID, before, after)
+	audit.RecordChange(ctx, "users.create", "users", int64(6), nil, testUser(6, model.StatusActive))
+	audit.RecordChange(ctx, "users.delete", "users", before.ID, after, nil)
+	if !assert.Len(t, repo.logs, 3) {
+		return
+	}
+
+	update := repo.logs[0]
+	assert.Equal(t, "5", update.TargetID)
+	assert.JSONEq(t, `{"status":"active"}`, string(update.Before))
+	assert.JSONEq(t, `{"status":"banned"}`, string(update.After))
+
+	create := repo.logs[1]
+	assert.Nil(t, create.Before)
+	var created map[string]any
+	assert.NoError(t, json.Unmarshal(create.After, &created))
+	assert.Equal(t, "active", created["status"])
+	assert.NotContains(t, created, "password")
+
+	deleted := repo.logs[2]
+	assert.Nil(t, deleted.After)
+	assert.Contains(t, string(deleted.Before), "ali@example.com")
+
+	result, err := audit.Verify(ctx)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestServiceChangesAreAudited(t *testing.T) {
+	ctx := context.Background()
+	f := newRideFixture([]model.User{invoiceUser(1)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+	audit, auditLogs := newAuditFixture()
+	wallet := service.NewWalletService(f.ledger, f.users, &fakeTxManager{}, audit)
+	disputes := newDisputeService(f, &fakeDisputeRepo{}, audit)
+	adminID := int64(testAdminID)
+	_, err := wallet.Adjust(ctx, service.WalletPosting{
+		UserID: 1, Kind: model.LedgerTopUp, Amount: 5000, Description: "test yüklemesi", CreatedBy: &adminID,
+	})
+	assert.NoError(t, err)
+
+	ride := finishedTestRide(t, f, 1, 10)
+	dispute := openTestDispute(t, disputes, ride)
+	_, err = disputes.StartReview(ctx, dispute.ID, testAdminID, "")
+	assert.NoError(t, err)
+	_, err = disputes.Approve(ctx, service.ApproveDisputeInput{
+		DisputeID: dispute.ID, AdminID: testAdminID, Amount: ride.Cost, Destination: model.RefundToWallet,
+	})
+	assert.NoError(t, err)
+
+	byAction := map[string]model.AuditLog{}
+	for _, log := range auditLogs.logs {
+		byAction[log.Action] = log
+	}
+	adjust, ok := byAction["wallets.adjust"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "users", adjust.TargetType)
+		assert.Equal(t, "1", adjust.TargetID)
+		assert.Contains(t, string(adjust.After), "top_up")
+	}
+	approve, ok := byAction["disputes.approve"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "disputes", approve.TargetType)
+		var before, after map[string]any
+		assert.NoError(t, json.Unmarshal(approve.Before, &before))
+		assert.NoError(t, json.Unmarshal(approve.After, &after))
+		assert.Equal(t, string(model.DisputeInReview), before["status"])
+		assert.Equal(t, string(model.DisputeApproved), after["status"])
+	}
+	assert.Contains(t, byAction, "disputes.review")
+
+	result, err := audit.Verify(ctx)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	audit, repo := newAuditFixture()
+	app := auditTestApp(audit)
+	app.Get("/api/v1/users/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Put("/api/v1/users/:id", func(c *fiber.Ctx) error {
+		audit.RecordChange(c.Context(), "users.update", "users", c.Params("id"),
+			map[string]any{"status": "active"}, map[string]any{"status": "banned"})
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Delete("/api/v1/ops/tasks/:id<int>", func(c *fiber.Ctx) error {
+		return errorx.WrapMsg(errorx.ErrNotFound, "Görev bulunamadı")
+	})
+	app.Post("/api/v1/devices/telemetry", func(c *fiber.Ctx) error {
+		c.Locals("motorbikeID", int64(10))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	send := func(method, path string) *http.Response {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("X-Role", string(model.AdminRole))
+		req.Header.Set(fiber.HeaderUserAgent, "audit-test/1.0")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	send(fiber.MethodGet, "/api/v1/users/5")
+	send(fiber.MethodPost, "/api/v1/devices/telemetry")
+	assert.Empty(t, repo.logs)
+
+	resp := send(fiber.MethodPut, "/api/v1/users/5")
+	requestID := resp.Header.Get(fiber.HeaderXRequestID)
+	assert.NotEmpty(t, requestID)
+	if !assert.Len(t, repo.logs, 2) {
+		return
+	}
+
+	// Servisin yazdığı değişiklik ve isteğin kendisi aynı request id ile ilişkilenir
+	change, request := repo.logs[0], repo.logs[1]
+	assert.Equal(t, "users.update", change.Action)
+	assert.Equal(t, requestID, change.RequestID)
+	assert.Equal(t, int64(7), *change.ActorID)
+	assert.Equal(t, "audit-test/1.0", change.UserAgent)
+
+	assert.Equal(t, "PUT /api/v1/users/:id", request.Action)
+	assert.Equal(t, "users", request.TargetType)
+	assert.Equal(t, "5", request.TargetID)
+	assert.Equal(t, "/api/v1/users/5", request.Path)
+	assert.Equal(t, http.StatusOK, request.StatusCode)
+	assert.Equal(t, requestID, request.RequestID)
+	assert.Equal(t, model.AdminRole, request.ActorRole)
+	assert.NotEmpty(t, request.IP)
+
+	resp = send(fiber.MethodDelete, "/api/v1/ops/tasks/3")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	if !assert.Len(t, repo.logs, 3) {
+		return
+	}
+	assert.Equal(t, "ops/tasks", repo.logs[2].TargetType)
+	assert.Equal(t, "3", repo.logs[2].TargetID)
+	assert.Equal(t, http.StatusNotFound, repo.logs[2].StatusCode)
+}
+
+func TestAuditLogExport(t *testing.T) {
+	ctx := context.Background()
+	audit, _ := newAuditFixture()
+	admin, operator := int64(1), int64(2)
+	for _, entry := range []model.AuditLog{
+		{ActorID: &admin, Action: "users.update", TargetType: "users", TargetID: "5", After: json.RawMessage(`{"status":"banned"}`)},
+		{ActorID: &operator, Action: "POST /api/v1/ops/tasks/:id/claim", TargetType: "ops/tasks", TargetID: "3", UserAgent: "=HYPERLINK(\"x\")"},
+		{ActorID: &admin, Action: "zones.delete", TargetType: "zones", TargetID: "9"},
+	} {
+		assert.NoError(t, audit.Record(ctx, entry))
+	}
+
+	app := auditTestApp(audit)
+	h := handler.NewAuditLogHandler(audit, 2)
+	app.Get("/admin/audit-logs/export", h.Export)
+
+	get := func(path string) *http.Response {
+		resp, err := app.Test(auditGetRequest(path))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := get("/admin/audit-logs/export")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "sınırı aşan dışa aktarma reddedilmeli")
+
+	resp = get("/admin/audit-logs/export?actor_id=2")
+	if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), "text/csv"))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), "attachment")
+
+	body, _ := io.ReadAll(resp.Body)
+	records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	assert.NoError(t, err)
+	if !assert.Len(t, records, 2) {
+		return
+	}
+	assert.Equal(t, "id", records[0][0])
+	assert.Equal(t, []string{"2", "2", "POST /api/v1/ops/tasks/:id/claim", "ops/tasks", "3"},
+		[]string{records[1][0], records[1][2], records[1][4], records[1][5], records[1][6]})
+	assert.Equal(t, `'=HYPERLINK("x")`, records[1][13], "formül olarak açılmamalı")
+
+	resp = get("/admin/audit-logs/export?from=not-a-date")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func auditGetRequest(path string) *http.Request {
+	req, _ := http.NewRequest(fiber.MethodGet, path, nil)
+	return req
+}
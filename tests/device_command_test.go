@@ -0,0 +1,269 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCommandService(repo *fakeDeviceCommandRepo, motorbikes *fakeMotorbikeRepo, ackTimeout time.Duration, maxAttempts int) *service.DeviceCommandService {
+	return service.NewDeviceCommandService(service.DeviceCommandServiceDeps{
+		CommandRepo:   repo,
+		MotorbikeRepo: motorbikes,
+		TxManager:     &fakeTxManager{},
+		AckTimeout:    ackTimeout,
+		MaxAttempts:   maxAttempts,
+		TTL:           time.Hour,
+	})
+}
+
+// fakeDevice motor üzerindeki cihazı taklit eder: komutları long polling ile alır ve respond'un
+// döndüğü sonuca göre onaylar. respond false dönerse komut onaylanmaz.
+type fakeDevice struct {
+	mu       sync.Mutex
+	received []model.DeviceCommand
+}
+
+func startFakeDevice(t *testing.T, svc *service.DeviceCommandService, motorbikeID int64,
+	respond func(cmd model.DeviceCommand) (ack bool, success bool, message string)) *fakeDevice {
+	ctx, cancel := context.WithCancel(context.Background())
+	device := &fakeDevice{}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	go func() {
+		defer close(done)
+		for ctx.Err() == nil {
+			commands, err := svc.Poll(ctx, motorbikeID, 200*time.Millisecond)
+			if err != nil {
+				return
+			}
+			for _, cmd := range commands {
+				device.mu.Lock()
+				device.received = append(device.received, cmd)
+				device.mu.Unlock()
+
+				if ack, success, message := respond(cmd); ack {
+					_, _ = svc.Ack(ctx, motorbikeID, cmd.ID, success, message)
+				}
+			}
+		}
+	}()
+	return device
+}
+
+func (d *fakeDevice) receivedCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.received)
+}
+
+func commandStatus(repo *fakeDeviceCommandRepo, id int64) model.DeviceCommandStatus {
+	command, _ := repo.GetByID(context.Background(), id)
+	return command.Status
+}
+
+func eventStatuses(command *model.DeviceCommand) []model.DeviceCommandStatus {
+	statuses := make([]model.DeviceCommandStatus, len(command.Events))
+	for i, e := range command.Events {
+		statuses[i] = e.Status
+	}
+	return statuses
+}
+
+func TestDeviceCommandLoop(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Lock Acknowledged By Device", func(t *testing.T) {
+		bike := testMotorbike(1, model.BikeRented)
+		bike.LockStatus = model.Unlocked
+		motorbikes := newFakeMotorbikeRepo(bike)
+		repo := newFakeDeviceCommandRepo()
+		svc := newCommandService(repo, motorbikes, time.Second, 3)
+
+		startFakeDevice(t, svc, 1, func(cmd model.DeviceCommand) (bool, bool, string) { return true, true, "" })
+
+		command, err := svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandLock})
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return commandStatus(repo, command.ID) == model.CommandAcknowledged
+		}, 2*time.Second, 5*time.Millisecond)
+
+		m, _ := motorbikes.GetByID(ctx, 1)
+		assert.Equal(t, model.Locked, m.LockStatus)
+
+		stored, _ := svc.GetByID(ctx, command.ID)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.NotNil(t, stored.CompletedAt)
+		assert.Equal(t, []model.DeviceCommandStatus{model.CommandPending, model.CommandSent, model.CommandAcknowledged}, eventStatuses(stored))
+	})
+
+	t.Run("Device Failure Is Retried", func(t *testing.T) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable))
+		repo := newFakeDeviceCommandRepo()
+		svc := newCommandService(repo, motorbikes, time.Second, 3)
+
+		startFakeDevice(t, svc, 1, func(cmd model.DeviceCommand) (bool, bool, string) {
+			if cmd.Attempts == 1 {
+				return true, false, "kilit sensörü yanıt vermedi"
+			}
+			return true, true, ""
+		})
+
+		command, err := svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandUnlock})
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return commandStatus(repo, command.ID) == model.CommandAcknowledged
+		}, 2*time.Second, 5*time.Millisecond)
+
+		stored, _ := svc.GetByID(ctx, command.ID)
+		assert.Equal(t, 2, stored.Attempts)
+		assert.Empty(t, stored.LastError)
+		assert.Equal(t, []model.DeviceCommandStatus{
+			model.CommandPending, model.CommandSent, model.CommandPending, model.CommandSent, model.CommandAcknowledged,
+		}, eventStatuses(stored))
+		assert.Equal(t, "kilit sensörü yanıt vermedi", stored.Events[2].Note)
+
+		m, _ := motorbikes.GetByID(ctx, 1)
+		assert.Equal(t, model.Unlocked, m.LockStatus)
+	})
+
+	t.Run("Unacknowledged Command Is Resent Then Failed", func(t *testing.T) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable))
+		repo := newFakeDeviceCommandRepo()
+		svc := newCommandService(repo, motorbikes, 20*time.Millisecond, 2)
+
+		device := startFakeDevice(t, svc, 1, func(cmd model.DeviceCommand) (bool, bool, string) { return false, false, "" })
+
+		command, err := svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandBeep})
+		assert.NoError(t, err)
+
+		// Onay gelmediği için komut onay süresi dolunca tekrar gönderilir, deneme hakkı bitince gönderilmez
+		assert.Eventually(t, func() bool { return device.receivedCount() == 2 }, 2*time.Second, 5*time.Millisecond)
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, 2, device.receivedCount())
+		assert.Equal(t, model.CommandSent, commandStatus(repo, command.ID))
+
+		closed, err := svc.SweepTimedOut(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, closed)
+
+		stored, _ := svc.GetByID(ctx, command.ID)
+		assert.Equal(t, model.CommandFailed, stored.Status)
+		assert.NotEmpty(t, stored.LastError)
+
+		// Kapanan komutun geç gelen onayı reddedilir
+		_, err = svc.Ack(ctx, 1, command.ID, true, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Expired Command Is Not Delivered", func(t *testing.T) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable))
+		repo := newFakeDeviceCommandRepo()
+		svc := service.NewDeviceCommandService(service.DeviceCommandServiceDeps{
+			CommandRepo:   repo,
+			MotorbikeRepo: motorbikes,
+			TxManager:     &fakeTxManager{},
+			AckTimeout:    time.Second,
+			MaxAttempts:   3,
+			TTL:           10 * time.Millisecond,
+		})
+
+		command, err := svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandLock})
+		assert.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+
+		commands, err := svc.Poll(ctx, 1, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+
+		closed, err := svc.SweepTimedOut(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, closed)
+		assert.Equal(t, model.CommandExpired, commandStatus(repo, command.ID))
+	})
+
+	t.Run("Long Poll Wakes On Enqueue", func(t *testing.T) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable))
+		svc := newCommandService(newFakeDeviceCommandRepo(), motorbikes, time.Second, 3)
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			_, _ = svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandBeep})
+		}()
+
+		started := time.Now()
+		commands, err := svc.Poll(ctx, 1, 5*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, commands, 1)
+		assert.Less(t, time.Since(started), 2*time.Second)
+	})
+
+	t.Run("Ack Rules", func(t *testing.T) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable), testMotorbike(2, model.BikeAvailable))
+		svc := newCommandService(newFakeDeviceCommandRepo(), motorbikes, time.Second, 3)
+
+		command, err := svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: model.CommandDisable})
+		assert.NoError(t, err)
+
+		// Henüz gönderilmemiş komut onaylanamaz
+		_, err = svc.Ack(ctx, 1, command.ID, true, "")
+		assert.Error(t, err)
+
+		_, err = svc.Poll(ctx, 1, 0)
+		assert.NoError(t, err)
+
+		// Başka motorun cihazı komutu onaylayamaz
+		_, err = svc.Ack(ctx, 2, command.ID, true, "")
+		assert.Error(t, err)
+
+		_, err = svc.Ack(ctx, 1, command.ID, true, "")
+		assert.NoError(t, err)
+		_, err = svc.Ack(ctx, 1, command.ID, true, "")
+		assert.NoError(t, err, "tekrar gelen başarılı onay hata vermemeli")
+
+		m, _ := motorbikes.GetByID(ctx, 1)
+		assert.Equal(t, model.BikeInMaintenance, m.Status)
+		assert.Equal(t, model.Locked, m.LockStatus)
+
+		_, err = svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 1, Type: "explode"})
+		assert.Error(t, err)
+		_, err = svc.Enqueue(ctx, service.EnqueueCommand{MotorbikeID: 99, Type: model.CommandBeep})
+		assert.Error(t, err)
+	})
+
+	t.Run("Ride Photo Upload Closes Connection", func(t *testing.T) {
+		locks := &fakeLockController{}
+		f := newRideFixtureWithLocks([]model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, locks)
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+
+		// Sürüş bitmeden yüklenen fotoğraf bağlantıyı kapatmaz
+		assert.NoError(t, f.service.HandleAfterPhotoUpload(ctx, ride.ID, 1))
+		if !assert.Len(t, f.bluetooth.connections, 1) {
+			return
+		}
+		assert.Nil(t, f.bluetooth.connections[0].DisconnectedAt)
+
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assertAppErrorCode(t, f.service.HandleAfterPhotoUpload(ctx, ride.ID, 2), errorx.ErrForbidden)
+		assert.Nil(t, f.bluetooth.connections[0].DisconnectedAt)
+
+		assert.NoError(t, f.service.HandleAfterPhotoUpload(ctx, ride.ID, 1))
+		assert.NotNil(t, f.bluetooth.connections[0].DisconnectedAt)
+
+		// Motor zaten kilitli olduğundan cihaza başlatmadan sonra komut gönderilmez
+		assert.Equal(t, []string{"connect", "unlock"}, locks.Calls())
+	})
+}
@@ -0,0 +1,338 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDisputeWindow = 30 * 24 * time.Hour
+	testAdminID       = 99
+)
+
+// finishedTestRide kullanıcının karttan ödenmiş, tamamlanmış bir sürüşünü döner (cüzdan boş olduğu için ücret karttan alınır)
+func finishedTestRide(t *testing.T, f *rideFixture, userID, motorbikeID int64) *model.Ride {
+	ride := startTestRide(t, f, userID, motorbikeID, 10*time.Minute-time.Second)
+	finished, err := f.service.FinishRide(context.Background(), ride.ID, userID)
+	assert.NoError(t, err)
+	assert.Greater(t, finished.Cost, int64(0))
+	return finished
+}
+
+// newDisputeService ride fixture'ının servislerini paylaşan bir itiraz servisi oluşturur
+func newDisputeService(f *rideFixture, repo *fakeDisputeRepo, audit service.Auditor) *service.DisputeService {
+	return service.NewDisputeService(service.DisputeServiceDeps{
+		DisputeRepo: repo,
+		RideRepo:    f.rides,
+		UserRepo:    f.users,
+		TxManager:   &fakeTxManager{},
+		Wallet:      f.wallet,
+		Payments:    f.payment,
+		Mailer:      f.mailer,
+		Window:      testDisputeWindow,
+		Permissions: f.rbac,
+		Audit:       audit,
+	})
+}
+
+func openTestDispute(t *testing.T, disputes *service.DisputeService, ride *model.Ride) *model.Dispute {
+	dispute, err := disputes.Open(context.Background(), service.OpenDisputeInput{
+		RideID:      ride.ID,
+		UserID:      ride.UserID,
+		Reason:      model.DisputeBikeIssue,
+		Description: "Motor yolda durdu",
+		Photos:      []model.DisputePhoto{{Path: "uploads/disputes/test.jpg", ContentType: "image/jpeg", Size: 1024}},
+	})
+	assert.NoError(t, err)
+	return dispute
+}
+
+func TestDisputeStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to model.DisputeStatus
+		allowed  bool
+	}{
+		{model.DisputeOpen, model.DisputeInReview, true},
+		{model.DisputeOpen, model.DisputeApproved, true},
+		{model.DisputeOpen, model.DisputeWithdrawn, true},
+		{model.DisputeInReview, model.DisputeRejected, true},
+		{model.DisputeInReview, model.DisputeOpen, false},
+		{model.DisputeApproved, model.DisputeRejected, false},
+		{model.DisputeRejected, model.DisputeApproved, false},
+		{model.DisputeWithdrawn, model.DisputeInReview, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+	assert.True(t, model.DisputeApproved.IsClosed())
+	assert.False(t, model.DisputeInReview.IsClosed())
+}
+
+func TestOpenDispute(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{invoiceUser(1), invoiceUser(2)}
+	bikes := func() []model.Motorbike {
+		return []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)}
+	}
+
+	t.Run("Opens With Photos And Notifies User", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputes := newDisputeService(f, &fakeDisputeRepo{}, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		sentBefore := len(f.mailer.messages())
+
+		dispute := openTestDispute(t, disputes, ride)
+		assert.Equal(t, model.DisputeOpen, dispute.Status)
+
+		stored, err := disputes.Get(ctx, dispute.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		assert.Len(t, stored.Photos, 1)
+		if assert.Len(t, stored.Events, 1) {
+			assert.Equal(t, model.DisputeOpen, stored.Events[0].ToStatus)
+			assert.Equal(t, int64(1), stored.Events[0].ActorID)
+		}
+
+		sent := f.mailer.messages()[sentBefore:]
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, "user1@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Subject, "İtirazınız alındı")
+		}
+
+		_, err = disputes.Get(ctx, dispute.ID, 2, model.UserRole)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		photo, err := disputes.GetPhoto(ctx, dispute.ID, stored.Photos[0].ID, testAdminID, model.AdminRole)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/jpeg", photo.ContentType)
+	})
+
+	t.Run("Validates Ride", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputes := newDisputeService(f, &fakeDisputeRepo{}, service.NoopAuditor{})
+		active := startTestRide(t, f, 1, 10, 5*time.Minute)
+		_, err := disputes.Open(ctx, service.OpenDisputeInput{RideID: active.ID, UserID: 1, Reason: model.DisputeOther, Description: "x"})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		ride := finishedTestRide(t, f, 2, 11)
+		_, err = disputes.Open(ctx, service.OpenDisputeInput{RideID: ride.ID, UserID: 1, Reason: model.DisputeOther, Description: "x"})
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+
+		_, err = disputes.Open(ctx, service.OpenDisputeInput{RideID: ride.ID, UserID: 2, Reason: model.DisputeOther, Description: "x", RequestedAmount: ride.Cost + 1})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		openTestDispute(t, disputes, ride)
+		_, err = disputes.Open(ctx, service.OpenDisputeInput{RideID: ride.ID, UserID: 2, Reason: model.DisputeOther, Description: "x"})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Rejects After Window", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputes := newDisputeService(f, &fakeDisputeRepo{}, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		ended := time.Now().Add(-testDisputeWindow - time.Hour)
+		ride.EndTime = &ended
+		assert.NoError(t, f.rides.Update(ctx, ride))
+
+		_, err := disputes.Open(ctx, service.OpenDisputeInput{RideID: ride.ID, UserID: 1, Reason: model.DisputeOvercharged, Description: "x"})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+}
+
+func TestResolveDispute(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{invoiceUser(1), invoiceUser(2)}
+	bikes := func() []model.Motorbike { return []model.Motorbike{testMotorbike(10, model.BikeAvailable)} }
+
+	t.Run("Partial Refund To Wallet", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputeRepo := &fakeDisputeRepo{}
+		disputes := newDisputeService(f, disputeRepo, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		dispute := openTestDispute(t, disputes, ride)
+
+		_, err := disputes.StartReview(ctx, dispute.ID, testAdminID, "")
+		assert.NoError(t, err)
+		refund := ride.Cost / 2
+		approved, err := disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: dispute.ID, AdminID: testAdminID, Amount: refund, Destination: model.RefundToWallet, Note: "Arıza kaydı doğrulandı",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.DisputeApproved, approved.Status)
+		assert.Equal(t, refund, approved.RefundAmount)
+		assert.NotNil(t, approved.ResolvedAt)
+		assert.Equal(t, int64(testAdminID), *approved.ReviewerID)
+
+		assert.Equal(t, refund, walletBalance(t, f, 1))
+		if assert.Len(t, disputeRepo.adjustments, 1) {
+			adjustment := disputeRepo.adjustments[0]
+			assert.Equal(t, refund, adjustment.Amount)
+			assert.Equal(t, int64(testAdminID), adjustment.CreatedBy)
+			assert.Empty(t, adjustment.ProviderRefundID)
+
+			transaction := f.ledger.transactions[adjustment.LedgerTransactionID-1]
+			assert.Equal(t, model.LedgerRefund, transaction.Kind)
+			assert.Equal(t, int64(testAdminID), *transaction.CreatedBy)
+			assert.Equal(t, ride.ID, *transaction.RideID)
+		}
+
+		// Sürüşün kendisi değişmez
+		stored, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, ride.Cost, stored.Cost)
+
+		// Kalan tutardan fazlası ikinci itirazla iade edilemez
+		second := openTestDispute(t, disputes, ride)
+		_, err = disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: second.ID, AdminID: testAdminID, Amount: ride.Cost - refund + 1, Destination: model.RefundToWallet,
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: second.ID, AdminID: testAdminID, Amount: ride.Cost - refund, Destination: model.RefundToWallet,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, ride.Cost, walletBalance(t, f, 1))
+	})
+
+	t.Run("Full Refund To Card", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputeRepo := &fakeDisputeRepo{}
+		disputes := newDisputeService(f, disputeRepo, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		dispute := openTestDispute(t, disputes, ride)
+		revenueBefore := f.ledger.systemBalance(model.SystemAccountRideRevenue)
+
+		approved, err := disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: dispute.ID, AdminID: testAdminID, Amount: ride.Cost, Destination: model.RefundToCard,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.RefundToCard, approved.RefundDestination)
+		assert.Equal(t, 1, f.provider.Calls(payment.OpRefund))
+
+		// Para karta gider; cüzdan bakiyesi değişmez, sürüş geliri iade kadar azalır
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+		assert.Equal(t, revenueBefore-ride.Cost, f.ledger.systemBalance(model.SystemAccountRideRevenue))
+		if assert.Len(t, disputeRepo.adjustments, 1) {
+			assert.NotEmpty(t, disputeRepo.adjustments[0].ProviderRefundID)
+			assert.Equal(t, model.ProviderRefundSucceeded, disputeRepo.adjustments[0].ProviderRefundStatus)
+		}
+
+		sent := f.mailer.messages()
+		assert.Contains(t, sent[len(sent)-1].Subject, "İtirazınız onaylandı")
+		assert.Contains(t, sent[len(sent)-1].Body, "kartınıza iade edildi")
+
+		_, err = disputes.Reject(ctx, dispute.ID, testAdminID, "Geç kaldı")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Card Refund Declined After Approval", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputeRepo := &fakeDisputeRepo{}
+		disputes := newDisputeService(f, disputeRepo, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		dispute := openTestDispute(t, disputes, ride)
+		f.provider.Next(payment.OpRefund, payment.Decline)
+		sentBefore := len(f.mailer.messages())
+
+		// Sağlayıcı onay kaydedildikten sonra çağrılır; reddedilen iade mutabakat için kayıtta kalır
+		_, err := disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: dispute.ID, AdminID: testAdminID, Amount: ride.Cost, Destination: model.RefundToCard,
+		})
+		assertAppErrorCode(t, err, errorx.ErrInternal)
+		if assert.Len(t, disputeRepo.adjustments, 1) {
+			assert.Equal(t, model.ProviderRefundFailed, disputeRepo.adjustments[0].ProviderRefundStatus)
+			assert.Empty(t, disputeRepo.adjustments[0].ProviderRefundID)
+		}
+		assert.Len(t, f.mailer.messages(), sentBefore)
+
+		stored, _ := disputes.Get(ctx, dispute.ID, 1, model.UserRole)
+		assert.Equal(t, model.DisputeApproved, stored.Status)
+	})
+
+	t.Run("Card Refund Without Capture", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputeRepo := &fakeDisputeRepo{}
+		disputes := newDisputeService(f, disputeRepo, service.NoopAuditor{})
+		topUp(t, f, 1, 100000) // ücret cüzdandan ödenir, karttan tahsilat yapılmaz
+		ride := finishedTestRide(t, f, 1, 10)
+		dispute := openTestDispute(t, disputes, ride)
+
+		_, err := disputes.Approve(ctx, service.ApproveDisputeInput{
+			DisputeID: dispute.ID, AdminID: testAdminID, Amount: ride.Cost, Destination: model.RefundToCard,
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Empty(t, disputeRepo.adjustments)
+		assert.Equal(t, 0, f.provider.Calls(payment.OpRefund))
+
+		stored, _ := disputes.Get(ctx, dispute.ID, 1, model.UserRole)
+		assert.Equal(t, model.DisputeOpen, stored.Status)
+	})
+
+	t.Run("Reject And Withdraw", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		disputes := newDisputeService(f, &fakeDisputeRepo{}, service.NoopAuditor{})
+		ride := finishedTestRide(t, f, 1, 10)
+		dispute := openTestDispute(t, disputes, ride)
+
+		_, err := disputes.Reject(ctx, dispute.ID, testAdminID, " ")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		rejected, err := disputes.Reject(ctx, dispute.ID, testAdminID, "Sürüş kayıtları normal")
+		assert.NoError(t, err)
+		assert.Equal(t, model.DisputeRejected, rejected.Status)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+		assert.Contains(t, f.mailer.messages()[len(f.mailer.messages())-1].Body, "Sürüş kayıtları normal")
+
+		second := openTestDispute(t, disputes, ride)
+		_, err = disputes.Withdraw(ctx, second.ID, 2, "")
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		withdrawn, err := disputes.Withdraw(ctx, second.ID, 1, "Yanlışlıkla açtım")
+		assert.NoError(t, err)
+		assert.Equal(t, model.DisputeWithdrawn, withdrawn.Status)
+		_, err = disputes.StartReview(ctx, second.ID, testAdminID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		stored, _ := disputes.Get(ctx, second.ID, 1, model.UserRole)
+		if assert.Len(t, stored.Events, 2) {
+			assert.Equal(t, model.DisputeOpen, stored.Events[1].FromStatus)
+			assert.Equal(t, model.DisputeWithdrawn, stored.Events[1].ToStatus)
+			assert.Equal(t, "Yanlışlıkla açtım", stored.Events[1].Note)
+		}
+	})
+}
+
+func TestListDisputes(t *testing.T) {
+	ctx := context.Background()
+	f := newRideFixture(
+		[]model.User{invoiceUser(1), invoiceUser(2)},
+		[]model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)},
+	)
+	disputes := newDisputeService(f, &fakeDisputeRepo{}, service.NoopAuditor{})
+	first := openTestDispute(t, disputes, finishedTestRide(t, f, 1, 10))
+	openTestDispute(t, disputes, finishedTestRide(t, f, 2, 11))
+	_, err := disputes.StartReview(ctx, first.ID, testAdminID, "")
+	assert.NoError(t, err)
+
+	pagination := &query.Pagination{Page: 1, PageSize: 10}
+	open, err := disputes.List(ctx, model.DisputeFilter{Status: model.DisputeOpen}, pagination)
+	assert.NoError(t, err)
+	if assert.Len(t, open, 1) {
+		assert.Equal(t, int64(2), open[0].UserID)
+	}
+
+	all, err := disputes.List(ctx, model.DisputeFilter{Reason: model.DisputeBikeIssue}, pagination)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, int64(2), pagination.TotalRows)
+
+	_, err = disputes.List(ctx, model.DisputeFilter{Status: "closed"}, pagination)
+	assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+	mine, err := disputes.ListByUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, mine, 1)
+}
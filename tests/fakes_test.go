@@ -0,0 +1,2358 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/repository"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// Servis testleri için bellek içi repository implementasyonları.
+// Arayüzler gömülü olduğundan testlerde kullanılmayan metodlar çağrılırsa panic oluşur.
+
+// fakeTxManager transaction'ları tek bir kilit ile sıralar; veritabanındaki satır kilitlerini taklit eder
+type fakeTxManager struct {
+	mu sync.Mutex
+}
+
+type fakeTxKey struct{}
+
+// WithTx gerçek transaction yöneticisi gibi zaten transaction içindeyken yenisini açmaz
+func (m *fakeTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(fakeTxKey{}) != nil {
+		return fn(ctx)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return fn(context.WithValue(ctx, fakeTxKey{}, true))
+}
+
+// fleetFixture motor, sürüş ve kullanıcı repository'leriyle çalışan servis testlerinin ortak kurulumudur. Servis
+// fixture'ları bunu gömer ve yalnızca kendi kullandıkları bağımlılıkları ekler.
+type fleetFixture struct {
+	motorbikes *fakeMotorbikeRepo
+	rides      *fakeRideRepo
+	users      *fakeUserRepo
+	roles      *fakeRoleRepo
+	rbac       *service.RBACService
+}
+
+func newFleetFixture(users []model.User, motorbikes []model.Motorbike) fleetFixture {
+	roles := newFakeRoleRepo()
+	return fleetFixture{
+		motorbikes: newFakeMotorbikeRepo(motorbikes...),
+		rides:      newFakeRideRepo(),
+		users:      newFakeUserRepo(users...),
+		roles:      roles,
+		rbac:       service.NewRBACService(roles, &fakeTxManager{}, service.NoopAuditor{}),
+	}
+}
+
+// newMaintenanceService fixture'ın repository'lerini kullanan bir bakım servisi oluşturur
+func (f *fleetFixture) newMaintenanceService(repo *fakeMaintenanceRepo) *service.MaintenanceService {
+	return service.NewMaintenanceService(service.MaintenanceServiceDeps{
+		MaintenanceRepo: repo,
+		MotorbikeRepo:   f.motorbikes,
+		RideRepo:        f.rides,
+		UserRepo:        f.users,
+		TxManager:       &fakeTxManager{},
+		Permissions:     f.rbac,
+		Audit:           service.NoopAuditor{},
+	})
+}
+
+func (f *fleetFixture) motorbikeStatus(t *testing.T, id int64) model.MotorBikeStatus {
+	motorbike, err := f.motorbikes.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	return motorbike.Status
+}
+
+// testStaff verilen rolde aktif bir kullanıcı döner
+func testStaff(id int64, role model.Role) model.User {
+	user := testUser(id, model.StatusActive)
+	user.Role = role
+	return user
+}
+
+type fakeUserRepo struct {
+	repository.IUserRepository
+	mu    sync.Mutex
+	users map[int64]*model.User
+}
+
+func newFakeUserRepo(users ...model.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: map[int64]*model.User{}}
+	for i := range users {
+		u := users[i]
+		r.users[u.ID] = &u
+	}
+	return r
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func (r *fakeUserRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.User, error) {
+	return r.GetByID(ctx, id)
+}
+
+type fakeMotorbikeRepo struct {
+	repository.IMotorbikeRepository
+	mu         sync.Mutex
+	motorbikes map[int64]*model.Motorbike
+	photos     []model.MotorbikePhoto
+	photoSeq   int64
+}
+
+func newFakeMotorbikeRepo(motorbikes ...model.Motorbike) *fakeMotorbikeRepo {
+	r := &fakeMotorbikeRepo{motorbikes: map[int64]*model.Motorbike{}}
+	for i := range motorbikes {
+		m := motorbikes[i]
+		r.motorbikes[m.ID] = &m
+	}
+	return r
+}
+
+func (r *fakeMotorbikeRepo) GetByID(ctx context.Context, id int64) (*model.Motorbike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.motorbikes[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (r *fakeMotorbikeRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Motorbike, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeMotorbikeRepo) List(ctx context.Context) ([]model.Motorbike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.Motorbike
+	for _, m := range r.motorbikes {
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *fakeMotorbikeRepo) GetMotorsForStatus(ctx context.Context, status string) ([]model.Motorbike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.Motorbike
+	for _, m := range r.motorbikes {
+		if string(m.Status) == status {
+			result = append(result, *m)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *fakeMotorbikeRepo) ListAvailableInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Motorbike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.Motorbike
+	for _, m := range r.motorbikes {
+		if m.Status == model.BikeAvailable &&
+			m.LocationLatitude >= box.MinLat && m.LocationLatitude <= box.MaxLat &&
+			m.LocationLongitude >= box.MinLng && m.LocationLongitude <= box.MaxLng {
+			result = append(result, *m)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeMotorbikeRepo) Update(ctx context.Context, motorbike *model.Motorbike) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *motorbike
+	r.motorbikes[motorbike.ID] = &cp
+	return nil
+}
+
+func (r *fakeMotorbikeRepo) UpdateColumns(ctx context.Context, motorbike *model.Motorbike, columns ...string) error {
+	return r.Update(ctx, motorbike)
+}
+
+func (r *fakeMotorbikeRepo) ListPhotos(ctx context.Context, motorbikeID int64) ([]model.MotorbikePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var photos []model.MotorbikePhoto
+	for _, photo := range r.photos {
+		if photo.MotorbikeID == motorbikeID {
+			photos = append(photos, photo)
+		}
+	}
+	sort.SliceStable(photos, func(i, j int) bool { return photos[i].Position < photos[j].Position })
+	return photos, nil
+}
+
+func (r *fakeMotorbikeRepo) ListPrimaryPhotos(ctx context.Context, motorbikeIDs []int64) ([]model.MotorbikePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var photos []model.MotorbikePhoto
+	for _, photo := range r.photos {
+		if photo.IsPrimary && slices.Contains(motorbikeIDs, photo.MotorbikeID) {
+			photos = append(photos, photo)
+		}
+	}
+	return photos, nil
+}
+
+func (r *fakeMotorbikeRepo) CreatePhotos(ctx context.Context, photos []model.MotorbikePhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range photos {
+		r.photoSeq++
+		photos[i].ID = r.photoSeq
+		r.photos = append(r.photos, photos[i])
+	}
+	return nil
+}
+
+func (r *fakeMotorbikeRepo) UpdatePhotoColumns(ctx context.Context, photo *model.MotorbikePhoto, columns ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.photos {
+		if r.photos[i].ID == photo.ID {
+			r.photos[i] = *photo
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeMotorbikeRepo) DeletePhoto(ctx context.Context, photoID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.photos = slices.DeleteFunc(r.photos, func(photo model.MotorbikePhoto) bool { return photo.ID == photoID })
+	return nil
+}
+
+func (r *fakeMotorbikeRepo) GetByDeviceKeyHash(ctx context.Context, hash string) (*model.Motorbike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.motorbikes {
+		if m.DeviceKeyHash != "" && m.DeviceKeyHash == hash {
+			cp := *m
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+type fakeRideRepo struct {
+	repository.IRideRepository
+	mu         sync.Mutex
+	nextID     int64
+	rides      map[int64]*model.Ride
+	breakdowns map[int64]*model.RidePriceBreakdown
+	points     []model.RidePoint
+	events     []model.RideEvent
+	pauses     []*model.RidePause
+	photos     []model.RidePhoto
+}
+
+func newFakeRideRepo() *fakeRideRepo {
+	return &fakeRideRepo{rides: map[int64]*model.Ride{}}
+}
+
+// Create veritabanındaki uq_rides_active_motorbike ve uq_rides_active_user indekslerini taklit eder
+func (r *fakeRideRepo) Create(ctx context.Context, ride *model.Ride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.rides {
+		if ride.Status.IsOngoing() && existing.Status.IsOngoing() &&
+			(existing.MotorbikeID == ride.MotorbikeID || existing.UserID == ride.UserID) {
+			return repository.ErrDuplicate
+		}
+	}
+	r.nextID++
+	ride.ID = r.nextID
+	cp := *ride
+	r.rides[ride.ID] = &cp
+	return nil
+}
+
+func (r *fakeRideRepo) GetByID(ctx context.Context, id int64) (*model.Ride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ride, ok := r.rides[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *ride
+	return &cp, nil
+}
+
+func (r *fakeRideRepo) Update(ctx context.Context, ride *model.Ride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *ride
+	r.rides[ride.ID] = &cp
+	return nil
+}
+
+func (r *fakeRideRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rides, id)
+	return nil
+}
+
+func (r *fakeRideRepo) UpdateStatus(ctx context.Context, id int64, status model.RideStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ride, ok := r.rides[id]; ok {
+		ride.Status = status
+	}
+	return nil
+}
+
+func (r *fakeRideRepo) ListMotorbikeIDsRiddenSince(ctx context.Context, since time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []int64
+	for _, ride := range r.rides {
+		ridden := !ride.StartTime.Before(since) || (ride.EndTime != nil && !ride.EndTime.Before(since))
+		if ridden && !slices.Contains(ids, ride.MotorbikeID) {
+			ids = append(ids, ride.MotorbikeID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeRideRepo) CreateEvent(ctx context.Context, event *model.RideEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = int64(len(r.events) + 1)
+	event.CreatedAt = time.Now()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeRideRepo) ListEvents(ctx context.Context, rideID int64) ([]model.RideEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []model.RideEvent
+	for _, event := range r.events {
+		if event.RideID == rideID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeRideRepo) CreatePause(ctx context.Context, pause *model.RidePause) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pause.ID = int64(len(r.pauses) + 1)
+	pause.CreatedAt = time.Now()
+	copied := *pause
+	r.pauses = append(r.pauses, &copied)
+	return nil
+}
+
+func (r *fakeRideRepo) GetOpenPause(ctx context.Context, rideID int64) (*model.RidePause, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pause := range r.pauses {
+		if pause.RideID == rideID && pause.EndedAt == nil {
+			copied := *pause
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRideRepo) UpdatePause(ctx context.Context, pause *model.RidePause) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.pauses {
+		if existing.ID == pause.ID {
+			copied := *pause
+			r.pauses[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (r *fakeRideRepo) ListPauses(ctx context.Context, rideID int64) ([]model.RidePause, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pauses []model.RidePause
+	for _, pause := range r.pauses {
+		if pause.RideID == rideID {
+			pauses = append(pauses, *pause)
+		}
+	}
+	return pauses, nil
+}
+
+func (r *fakeRideRepo) ListOpenPausesStartedBefore(ctx context.Context, before time.Time, limit int) ([]model.RidePause, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pauses []model.RidePause
+	for _, pause := range r.pauses {
+		if pause.EndedAt == nil && pause.StartedAt.Before(before) && len(pauses) < limit {
+			pauses = append(pauses, *pause)
+		}
+	}
+	return pauses, nil
+}
+
+func (r *fakeRideRepo) ListEndingBefore(ctx context.Context, before time.Time, limit int) ([]model.Ride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rides []model.Ride
+	for _, event := range r.events {
+		ride, ok := r.rides[event.RideID]
+		if ok && ride.Status == model.RideEnding && event.ToStatus == model.RideEnding && !event.CreatedAt.After(before) && len(rides) < limit {
+			rides = append(rides, *ride)
+		}
+	}
+	return rides, nil
+}
+
+func (r *fakeRideRepo) CreatePhoto(ctx context.Context, photo *model.RidePhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	photo.ID = int64(len(r.photos) + 1)
+	photo.CreatedAt = time.Now()
+	r.photos = append(r.photos, *photo)
+	return nil
+}
+
+func (r *fakeRideRepo) ListPhotos(ctx context.Context, rideID int64) ([]model.RidePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var photos []model.RidePhoto
+	for _, photo := range r.photos {
+		if photo.RideID == rideID {
+			photos = append(photos, photo)
+		}
+	}
+	return photos, nil
+}
+
+// backdatePause sürüşün açık park modunu geçmişe taşır
+func (r *fakeRideRepo) backdatePause(rideID int64, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pause := range r.pauses {
+		if pause.RideID == rideID && pause.EndedAt == nil {
+			pause.StartedAt = time.Now().UTC().Add(-elapsed)
+		}
+	}
+}
+
+func (r *fakeRideRepo) HasPaymentFailedByUserID(ctx context.Context, userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ride := range r.rides {
+		if ride.UserID == userID && ride.Status == model.RidePaymentFailed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRideRepo) SetPromotion(ctx context.Context, id int64, promotionID *int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ride, ok := r.rides[id]; ok {
+		ride.PromotionID = promotionID
+	}
+	return nil
+}
+
+func (r *fakeRideRepo) CountCompletedByUserID(ctx context.Context, userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, ride := range r.rides {
+		if ride.UserID == userID && ride.Status.HasEnded() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRideRepo) CountEndedByMotorbikeID(ctx context.Context, motorbikeID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, ride := range r.rides {
+		if ride.MotorbikeID == motorbikeID && ride.Status.HasEnded() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRideRepo) ListCompletedByUserIDBetween(ctx context.Context, userID int64, from, to time.Time) ([]model.Ride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rides []model.Ride
+	for _, ride := range r.rides {
+		if ride.UserID == userID && ride.EndTime != nil && !ride.EndTime.Before(from) && ride.EndTime.Before(to) {
+			rides = append(rides, *ride)
+		}
+	}
+	sort.Slice(rides, func(i, j int) bool { return rides[i].EndTime.Before(*rides[j].EndTime) })
+	return rides, nil
+}
+
+func (r *fakeRideRepo) ListUserIDsWithCompletedRidesBetween(ctx context.Context, from, to time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := map[int64]bool{}
+	var userIDs []int64
+	for _, ride := range r.rides {
+		if ride.EndTime != nil && !ride.EndTime.Before(from) && ride.EndTime.Before(to) && !seen[ride.UserID] {
+			seen[ride.UserID] = true
+			userIDs = append(userIDs, ride.UserID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs, nil
+}
+
+func (r *fakeRideRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Ride, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeRideRepo) SavePriceBreakdown(ctx context.Context, breakdown *model.RidePriceBreakdown) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.breakdowns == nil {
+		r.breakdowns = map[int64]*model.RidePriceBreakdown{}
+	}
+	cp := *breakdown
+	r.breakdowns[breakdown.RideID] = &cp
+	return nil
+}
+
+func (r *fakeRideRepo) GetPriceBreakdown(ctx context.Context, rideID int64) (*model.RidePriceBreakdown, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.breakdowns[rideID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r *fakeRideRepo) CreateRoutePoints(ctx context.Context, points []model.RidePoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.points = append(r.points, points...)
+	return nil
+}
+
+func (r *fakeRideRepo) ListRoutePoints(ctx context.Context, rideID int64) ([]model.RidePoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.RidePoint
+	for _, p := range r.points {
+		if p.RideID == rideID {
+			result = append(result, p)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].RecordedAt.Before(result[j].RecordedAt) })
+	return result, nil
+}
+
+func (r *fakeRideRepo) GetActiveByUserID(ctx context.Context, userID int64) (*model.Ride, error) {
+	return r.findActive(func(ride *model.Ride) bool { return ride.UserID == userID }), nil
+}
+
+func (r *fakeRideRepo) GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Ride, error) {
+	return r.findActive(func(ride *model.Ride) bool { return ride.MotorbikeID == motorbikeID }), nil
+}
+
+func (r *fakeRideRepo) findActive(match func(ride *model.Ride) bool) *model.Ride {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ride := range r.rides {
+		if ride.Status.IsOngoing() && match(ride) {
+			cp := *ride
+			return &cp
+		}
+	}
+	return nil
+}
+
+func (r *fakeRideRepo) countActive() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, ride := range r.rides {
+		if ride.Status.IsOngoing() {
+			count++
+		}
+	}
+	return count
+}
+
+type fakeBluetoothRepo struct {
+	repository.IBluetoothConnectionRepository
+	mu          sync.Mutex
+	nextID      int64
+	connections []model.BluetoothConnection
+}
+
+func (r *fakeBluetoothRepo) Create(ctx context.Context, conn *model.BluetoothConnection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	conn.ID = r.nextID
+	r.connections = append(r.connections, *conn)
+	return nil
+}
+
+func (r *fakeBluetoothRepo) GetOpenByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.BluetoothConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *model.BluetoothConnection
+	for i := range r.connections {
+		conn := r.connections[i]
+		if conn.MotorbikeID == motorbikeID && conn.DisconnectedAt == nil && (latest == nil || conn.ConnectedAt.After(latest.ConnectedAt)) {
+			latest = &conn
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakeBluetoothRepo) Update(ctx context.Context, conn *model.BluetoothConnection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.connections {
+		if r.connections[i].ID == conn.ID {
+			r.connections[i] = *conn
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeBluetoothRepo) CloseOpenByMotorbikeID(ctx context.Context, motorbikeID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.connections {
+		if r.connections[i].MotorbikeID == motorbikeID && r.connections[i].DisconnectedAt == nil {
+			closedAt := at
+			r.connections[i].DisconnectedAt = &closedAt
+		}
+	}
+	return nil
+}
+
+type fakeTariffRepo struct {
+	repository.ITariffRepository
+	tariffs []model.Tariff
+}
+
+// GetActiveForModel modele özel aktif tarifeyi, yoksa varsayılanı döner
+func (r *fakeTariffRepo) GetActiveForModel(ctx context.Context, motorbikeModel string) (*model.Tariff, error) {
+	var fallback *model.Tariff
+	for i := range r.tariffs {
+		t := r.tariffs[i]
+		if !t.IsActive {
+			continue
+		}
+		if t.MotorbikeModel == motorbikeModel {
+			return &t, nil
+		}
+		if t.MotorbikeModel == "" && fallback == nil {
+			fallback = &t
+		}
+	}
+	if fallback == nil {
+		return nil, sql.ErrNoRows
+	}
+	return fallback, nil
+}
+
+func (r *fakeTariffRepo) Create(ctx context.Context, tariff *model.Tariff) error {
+	tariff.ID = int64(len(r.tariffs) + 1)
+	r.tariffs = append(r.tariffs, *tariff)
+	return nil
+}
+
+func (r *fakeTariffRepo) GetByID(ctx context.Context, id int64) (*model.Tariff, error) {
+	for i := range r.tariffs {
+		if r.tariffs[i].ID == id {
+			t := r.tariffs[i]
+			return &t, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeTariffRepo) Update(ctx context.Context, tariff *model.Tariff) error {
+	for i := range r.tariffs {
+		if r.tariffs[i].ID == tariff.ID {
+			r.tariffs[i] = *tariff
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+type fakeReservationRepo struct {
+	repository.IReservationRepository
+	mu           sync.Mutex
+	nextID       int64
+	reservations map[int64]*model.Reservation
+}
+
+func newFakeReservationRepo() *fakeReservationRepo {
+	return &fakeReservationRepo{reservations: map[int64]*model.Reservation{}}
+}
+
+func (r *fakeReservationRepo) Create(ctx context.Context, reservation *model.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	reservation.ID = r.nextID
+	cp := *reservation
+	r.reservations[reservation.ID] = &cp
+	return nil
+}
+
+func (r *fakeReservationRepo) GetByID(ctx context.Context, id int64) (*model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *reservation
+	return &cp, nil
+}
+
+func (r *fakeReservationRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Reservation, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeReservationRepo) Update(ctx context.Context, reservation *model.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *reservation
+	r.reservations[reservation.ID] = &cp
+	return nil
+}
+
+func (r *fakeReservationRepo) GetActiveByUserID(ctx context.Context, userID int64) (*model.Reservation, error) {
+	return r.find(func(res *model.Reservation) bool {
+		return res.Status == model.ReservationActive && res.UserID == userID
+	}), nil
+}
+
+func (r *fakeReservationRepo) GetActiveByMotorbikeID(ctx context.Context, motorbikeID int64) (*model.Reservation, error) {
+	return r.find(func(res *model.Reservation) bool {
+		return res.Status == model.ReservationActive && res.MotorbikeID == motorbikeID
+	}), nil
+}
+
+func (r *fakeReservationRepo) GetByRideID(ctx context.Context, rideID int64) (*model.Reservation, error) {
+	return r.find(func(res *model.Reservation) bool { return res.RideID != nil && *res.RideID == rideID }), nil
+}
+
+func (r *fakeReservationRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.Reservation
+	for _, res := range r.reservations {
+		if res.Status == model.ReservationActive && res.IsExpired(now) && len(result) < limit {
+			result = append(result, *res)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeReservationRepo) find(match func(res *model.Reservation) bool) *model.Reservation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, res := range r.reservations {
+		if match(res) {
+			cp := *res
+			return &cp
+		}
+	}
+	return nil
+}
+
+type fakeZoneRepo struct {
+	repository.IZoneRepository
+	zones []model.Zone
+}
+
+func (r *fakeZoneRepo) GetByID(ctx context.Context, id int64) (*model.Zone, error) {
+	for _, zone := range r.zones {
+		if zone.ID == id {
+			return &zone, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeZoneRepo) ListActive(ctx context.Context) ([]model.Zone, error) {
+	var active []model.Zone
+	for _, zone := range r.zones {
+		if zone.IsActive {
+			active = append(active, zone)
+		}
+	}
+	return active, nil
+}
+
+type fakeTelemetryRepo struct {
+	repository.ITelemetryRepository
+	mu      sync.Mutex
+	samples []model.MotorbikeTelemetry
+}
+
+func (r *fakeTelemetryRepo) CreateBatch(ctx context.Context, samples []model.MotorbikeTelemetry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sample := range samples {
+		sample.ID = int64(len(r.samples) + 1)
+		r.samples = append(r.samples, sample)
+	}
+	return nil
+}
+
+func (r *fakeTelemetryRepo) ListByMotorbikeID(ctx context.Context, motorbikeID int64, from, to time.Time, limit int) ([]model.MotorbikeTelemetry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.MotorbikeTelemetry
+	for _, sample := range r.samples {
+		if sample.MotorbikeID == motorbikeID && !sample.RecordedAt.Before(from) && !sample.RecordedAt.After(to) {
+			result = append(result, sample)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RecordedAt.Before(result[j].RecordedAt) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+type fakeDeviceCommandRepo struct {
+	repository.IDeviceCommandRepository
+	mu       sync.Mutex
+	nextID   int64
+	commands map[int64]*model.DeviceCommand
+	events   []model.DeviceCommandEvent
+}
+
+func newFakeDeviceCommandRepo() *fakeDeviceCommandRepo {
+	return &fakeDeviceCommandRepo{commands: map[int64]*model.DeviceCommand{}}
+}
+
+func (r *fakeDeviceCommandRepo) Create(ctx context.Context, command *model.DeviceCommand) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	command.ID = r.nextID
+	command.CreatedAt = time.Now().UTC()
+	cp := *command
+	r.commands[command.ID] = &cp
+	return nil
+}
+
+func (r *fakeDeviceCommandRepo) GetByID(ctx context.Context, id int64) (*model.DeviceCommand, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	command, ok := r.commands[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *command
+	cp.Events = nil
+	for _, e := range r.events {
+		if e.CommandID == id {
+			cp.Events = append(cp.Events, e)
+		}
+	}
+	return &cp, nil
+}
+
+func (r *fakeDeviceCommandRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.DeviceCommand, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeDeviceCommandRepo) ListDeliverable(ctx context.Context, motorbikeID int64, now time.Time) ([]model.DeviceCommand, error) {
+	return r.list(func(c *model.DeviceCommand) bool {
+		if c.MotorbikeID != motorbikeID || !c.ExpiresAt.After(now) {
+			return false
+		}
+		return c.Status == model.CommandPending ||
+			(c.Status == model.CommandSent && !c.AckDeadline.After(now) && c.Attempts < c.MaxAttempts)
+	}), nil
+}
+
+func (r *fakeDeviceCommandRepo) ListTimedOut(ctx context.Context, now time.Time, limit int) ([]model.DeviceCommand, error) {
+	return r.list(func(c *model.DeviceCommand) bool {
+		if c.Status != model.CommandPending && c.Status != model.CommandSent {
+			return false
+		}
+		return !c.ExpiresAt.After(now) ||
+			(c.Status == model.CommandSent && !c.AckDeadline.After(now) && c.Attempts >= c.MaxAttempts)
+	}), nil
+}
+
+func (r *fakeDeviceCommandRepo) Update(ctx context.Context, command *model.DeviceCommand) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *command
+	r.commands[command.ID] = &cp
+	return nil
+}
+
+func (r *fakeDeviceCommandRepo) AddEvent(ctx context.Context, event *model.DeviceCommandEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = int64(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeDeviceCommandRepo) list(match func(c *model.DeviceCommand) bool) []model.DeviceCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []model.DeviceCommand
+	for _, c := range r.commands {
+		if match(c) {
+			result = append(result, *c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+type fakeLedgerRepo struct {
+	repository.ILedgerRepository
+	mu           sync.Mutex
+	accounts     []model.LedgerAccount
+	transactions []model.LedgerTransaction
+	entries      []model.LedgerEntry
+}
+
+func (r *fakeLedgerRepo) GetUserAccount(ctx context.Context, userID int64) (*model.LedgerAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.findAccount(func(a model.LedgerAccount) bool {
+		return a.Type == model.AccountUserWallet && *a.UserID == userID
+	}), nil
+}
+
+func (r *fakeLedgerRepo) EnsureUserAccount(ctx context.Context, userID int64, currency string) (*model.LedgerAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match := func(a model.LedgerAccount) bool { return a.Type == model.AccountUserWallet && *a.UserID == userID }
+	return r.ensure(match, model.LedgerAccount{Type: model.AccountUserWallet, UserID: &userID, Currency: currency}), nil
+}
+
+func (r *fakeLedgerRepo) EnsureSystemAccount(ctx context.Context, code, currency string) (*model.LedgerAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match := func(a model.LedgerAccount) bool {
+		return a.Type == model.AccountSystem && a.Code == code && a.Currency == currency
+	}
+	return r.ensure(match, model.LedgerAccount{Type: model.AccountSystem, Code: code, Currency: currency}), nil
+}
+
+func (r *fakeLedgerRepo) CreateTransaction(ctx context.Context, transaction *model.LedgerTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transaction.ID = int64(len(r.transactions) + 1)
+	transaction.CreatedAt = time.Now().UTC()
+	for i := range transaction.Entries {
+		transaction.Entries[i].ID = int64(len(r.entries) + 1)
+		transaction.Entries[i].TransactionID = transaction.ID
+		r.entries = append(r.entries, transaction.Entries[i])
+	}
+	r.transactions = append(r.transactions, *transaction)
+	return nil
+}
+
+func (r *fakeLedgerRepo) GetBalance(ctx context.Context, accountID int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var balance int64
+	for _, e := range r.entries {
+		if e.AccountID == accountID {
+			balance += e.Amount
+		}
+	}
+	return balance, nil
+}
+
+func (r *fakeLedgerRepo) ListAccountTransactions(ctx context.Context, accountID int64, pagination *query.Pagination) ([]model.WalletTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []model.WalletTransaction
+	var balance int64
+	for _, e := range r.entries {
+		if e.AccountID != accountID {
+			continue
+		}
+		balance += e.Amount
+		t := r.transactions[e.TransactionID-1]
+		all = append(all, model.WalletTransaction{
+			TransactionID: t.ID,
+			Kind:          t.Kind,
+			Description:   t.Description,
+			RideID:        t.RideID,
+			Amount:        e.Amount,
+			BalanceAfter:  balance,
+			CreatedAt:     t.CreatedAt,
+		})
+	}
+
+	pagination.TotalRows = int64(len(all))
+	pagination.TotalPages = (len(all) + pagination.PageSize - 1) / pagination.PageSize
+
+	result := []model.WalletTransaction{}
+	for i := len(all) - 1 - (pagination.Page-1)*pagination.PageSize; i >= 0 && len(result) < pagination.PageSize; i-- {
+		result = append(result, all[i])
+	}
+	return result, nil
+}
+
+// systemBalance sistem hesabının bakiyesini döner
+func (r *fakeLedgerRepo) systemBalance(code string) int64 {
+	r.mu.Lock()
+	account := r.findAccount(func(a model.LedgerAccount) bool { return a.Type == model.AccountSystem && a.Code == code })
+	r.mu.Unlock()
+	if account == nil {
+		return 0
+	}
+	balance, _ := r.GetBalance(context.Background(), account.ID)
+	return balance
+}
+
+func (r *fakeLedgerRepo) ensure(match func(a model.LedgerAccount) bool, account model.LedgerAccount) *model.LedgerAccount {
+	if existing := r.findAccount(match); existing != nil {
+		return existing
+	}
+	account.ID = int64(len(r.accounts) + 1)
+	r.accounts = append(r.accounts, account)
+	cp := account
+	return &cp
+}
+
+func (r *fakeLedgerRepo) findAccount(match func(a model.LedgerAccount) bool) *model.LedgerAccount {
+	for _, a := range r.accounts {
+		if match(a) {
+			cp := a
+			return &cp
+		}
+	}
+	return nil
+}
+
+// fakeRidePaymentRepo failStatus durumundaki kayıtları yazmayı reddeder; ödeme kaydedilemeyen durumları taklit eder.
+// failOnce ise yalnızca ilk yazma reddedilir.
+type fakeRidePaymentRepo struct {
+	repository.IRidePaymentRepository
+	mu         sync.Mutex
+	nextID     int64
+	payments   []model.RidePayment
+	failStatus model.RidePaymentStatus
+	failOnce   bool
+}
+
+func (r *fakeRidePaymentRepo) Create(ctx context.Context, payment *model.RidePayment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rejects(payment) {
+		return errors.New("ödeme kaydı yazılamadı")
+	}
+	r.nextID++
+	payment.ID = r.nextID
+	r.payments = append(r.payments, *payment)
+	return nil
+}
+
+func (r *fakeRidePaymentRepo) GetByRideID(ctx context.Context, rideID int64) (*model.RidePayment, error) {
+	return r.find(func(p model.RidePayment) bool { return p.RideID == rideID }), nil
+}
+
+func (r *fakeRidePaymentRepo) GetByAuthorizationID(ctx context.Context, provider, authorizationID string) (*model.RidePayment, error) {
+	return r.find(func(p model.RidePayment) bool { return p.Provider == provider && p.AuthorizationID == authorizationID }), nil
+}
+
+func (r *fakeRidePaymentRepo) Update(ctx context.Context, payment *model.RidePayment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rejects(payment) {
+		return errors.New("ödeme kaydı yazılamadı")
+	}
+	for i := range r.payments {
+		if r.payments[i].ID == payment.ID {
+			r.payments[i] = *payment
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeRidePaymentRepo) rejects(payment *model.RidePayment) bool {
+	if r.failStatus == "" || payment.Status != r.failStatus {
+		return false
+	}
+	if r.failOnce {
+		r.failStatus = ""
+	}
+	return true
+}
+
+func (r *fakeRidePaymentRepo) find(match func(p model.RidePayment) bool) *model.RidePayment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.payments {
+		if match(p) {
+			cp := p
+			return &cp
+		}
+	}
+	return nil
+}
+
+type fakePromotionRepo struct {
+	repository.IPromotionRepository
+	mu          sync.Mutex
+	nextID      int64
+	promotions  []model.Promotion
+	redemptions []model.PromotionRedemption
+}
+
+func (r *fakePromotionRepo) Create(ctx context.Context, promotion *model.Promotion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	promotion.ID = r.nextID
+	r.promotions = append(r.promotions, *promotion)
+	return nil
+}
+
+func (r *fakePromotionRepo) GetByID(ctx context.Context, id int64) (*model.Promotion, error) {
+	if p := r.find(func(p model.Promotion) bool { return p.ID == id }); p != nil {
+		return p, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakePromotionRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Promotion, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakePromotionRepo) GetByCode(ctx context.Context, code string) (*model.Promotion, error) {
+	return r.find(func(p model.Promotion) bool { return p.Code == code }), nil
+}
+
+func (r *fakePromotionRepo) Update(ctx context.Context, promotion *model.Promotion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.promotions {
+		if r.promotions[i].ID == promotion.ID {
+			cp := *promotion
+			cp.RedemptionCount = r.promotions[i].RedemptionCount
+			r.promotions[i] = cp
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakePromotionRepo) IncrementRedemptionCount(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.promotions {
+		if r.promotions[i].ID == id {
+			r.promotions[i].RedemptionCount++
+		}
+	}
+	return nil
+}
+
+func (r *fakePromotionRepo) CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, redemption := range r.redemptions {
+		if redemption.PromotionID == promotionID && redemption.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakePromotionRepo) CreateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	redemption.ID = int64(len(r.redemptions) + 1)
+	r.redemptions = append(r.redemptions, *redemption)
+	return nil
+}
+
+func (r *fakePromotionRepo) find(match func(p model.Promotion) bool) *model.Promotion {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.promotions {
+		if match(p) {
+			cp := p
+			return &cp
+		}
+	}
+	return nil
+}
+
+type fakeReferralRepo struct {
+	repository.IReferralRepository
+	mu        sync.Mutex
+	codes     []model.ReferralCode
+	referrals []model.Referral
+}
+
+func (r *fakeReferralRepo) GetCodeByUserID(ctx context.Context, userID int64) (*model.ReferralCode, error) {
+	return r.findCode(func(c model.ReferralCode) bool { return c.UserID == userID }), nil
+}
+
+func (r *fakeReferralRepo) GetCode(ctx context.Context, code string) (*model.ReferralCode, error) {
+	return r.findCode(func(c model.ReferralCode) bool { return c.Code == code }), nil
+}
+
+func (r *fakeReferralRepo) CreateCode(ctx context.Context, code *model.ReferralCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code.ID = int64(len(r.codes) + 1)
+	r.codes = append(r.codes, *code)
+	return nil
+}
+
+func (r *fakeReferralRepo) Create(ctx context.Context, referral *model.Referral) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	referral.ID = int64(len(r.referrals) + 1)
+	r.referrals = append(r.referrals, *referral)
+	return nil
+}
+
+func (r *fakeReferralRepo) GetPendingByRefereeIDForUpdate(ctx context.Context, refereeID int64) (*model.Referral, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, referral := range r.referrals {
+		if referral.RefereeID == refereeID && referral.Status == model.ReferralPending {
+			cp := referral
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeReferralRepo) Update(ctx context.Context, referral *model.Referral) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.referrals {
+		if r.referrals[i].ID == referral.ID {
+			r.referrals[i] = *referral
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeReferralRepo) findCode(match func(c model.ReferralCode) bool) *model.ReferralCode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.codes {
+		if match(c) {
+			cp := c
+			return &cp
+		}
+	}
+	return nil
+}
+
+type fakePassRepo struct {
+	repository.IPassRepository
+	mu            sync.Mutex
+	nextID        int64
+	products      []model.PassProduct
+	subscriptions []model.Subscription
+	charges       []model.SubscriptionCharge
+	usages        []model.PassUsage
+}
+
+func (r *fakePassRepo) CreateProduct(ctx context.Context, product *model.PassProduct) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	product.ID = r.nextID
+	r.products = append(r.products, *product)
+	return nil
+}
+
+func (r *fakePassRepo) GetProductByID(ctx context.Context, id int64) (*model.PassProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.product(id)
+}
+
+func (r *fakePassRepo) UpdateProduct(ctx context.Context, product *model.PassProduct) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.products {
+		if r.products[i].ID == product.ID {
+			r.products[i] = *product
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakePassRepo) CreateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	subscription.ID = r.nextID
+	cp := *subscription
+	cp.Product = nil
+	r.subscriptions = append(r.subscriptions, cp)
+	return nil
+}
+
+func (r *fakePassRepo) GetSubscriptionByIDForUpdate(ctx context.Context, id int64) (*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subscriptions {
+		if sub.ID == id {
+			return r.withProduct(sub), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakePassRepo) GetActiveByUserID(ctx context.Context, userID int64) (*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subscriptions {
+		if sub.UserID == userID && sub.Status == model.SubscriptionActive {
+			return r.withProduct(sub), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePassRepo) GetActiveByUserIDForUpdate(ctx context.Context, userID int64) (*model.Subscription, error) {
+	return r.GetActiveByUserID(ctx, userID)
+}
+
+func (r *fakePassRepo) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscription.ID {
+			cp := *subscription
+			cp.Product = nil
+			r.subscriptions[i] = cp
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakePassRepo) ListDueForRenewal(ctx context.Context, now time.Time, limit int) ([]model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []model.Subscription
+	for _, sub := range r.subscriptions {
+		if sub.Status == model.SubscriptionActive && !sub.CurrentPeriodEnd.After(now) && len(due) < limit {
+			due = append(due, sub)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakePassRepo) CreateCharge(ctx context.Context, charge *model.SubscriptionCharge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	charge.ID = int64(len(r.charges) + 1)
+	r.charges = append(r.charges, *charge)
+	return nil
+}
+
+func (r *fakePassRepo) CreateUsage(ctx context.Context, usage *model.PassUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage.ID = int64(len(r.usages) + 1)
+	r.usages = append(r.usages, *usage)
+	return nil
+}
+
+func (r *fakePassRepo) SumUsageMinutes(ctx context.Context, subscriptionID int64, day time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, usage := range r.usages {
+		if usage.SubscriptionID == subscriptionID && usage.UsageDate.Equal(day) {
+			total += usage.Minutes
+		}
+	}
+	return total, nil
+}
+
+// product r.mu tutulurken çağrılmalıdır
+func (r *fakePassRepo) product(id int64) (*model.PassProduct, error) {
+	for _, p := range r.products {
+		if p.ID == id {
+			cp := p
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// withProduct r.mu tutulurken çağrılmalıdır
+func (r *fakePassRepo) withProduct(sub model.Subscription) *model.Subscription {
+	sub.Product, _ = r.product(sub.ProductID)
+	return &sub
+}
+
+type fakeInvoiceRepo struct {
+	repository.IInvoiceRepository
+	mu       sync.Mutex
+	invoices []model.Invoice
+	numbers  map[model.InvoiceKind]int64
+}
+
+func (r *fakeInvoiceRepo) Create(ctx context.Context, invoice *model.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invoice.ID = int64(len(r.invoices) + 1)
+	invoice.CreatedAt = time.Now().UTC()
+	r.invoices = append(r.invoices, *invoice)
+	return nil
+}
+
+func (r *fakeInvoiceRepo) GetByID(ctx context.Context, id int64) (*model.Invoice, error) {
+	return r.find(func(invoice *model.Invoice) bool { return invoice.ID == id }, sql.ErrNoRows)
+}
+
+func (r *fakeInvoiceRepo) GetByRideID(ctx context.Context, rideID int64) (*model.Invoice, error) {
+	return r.find(func(invoice *model.Invoice) bool {
+		return invoice.Kind == model.InvoiceReceipt && invoice.RideID != nil && *invoice.RideID == rideID
+	}, nil)
+}
+
+func (r *fakeInvoiceRepo) GetMonthly(ctx context.Context, userID int64, periodStart time.Time) (*model.Invoice, error) {
+	return r.find(func(invoice *model.Invoice) bool {
+		return invoice.Kind == model.InvoiceMonthly && invoice.UserID == userID && invoice.PeriodStart.Equal(periodStart)
+	}, nil)
+}
+
+func (r *fakeInvoiceRepo) ListByUserID(ctx context.Context, userID int64) ([]model.Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var invoices []model.Invoice
+	for i := len(r.invoices) - 1; i >= 0; i-- {
+		if r.invoices[i].UserID == userID {
+			invoices = append(invoices, r.invoices[i])
+		}
+	}
+	return invoices, nil
+}
+
+func (r *fakeInvoiceRepo) ListUnsent(ctx context.Context, maxAttempts, limit int) ([]model.Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var invoices []model.Invoice
+	for _, invoice := range r.invoices {
+		if invoice.EmailedAt == nil && invoice.EmailAttempts < maxAttempts && len(invoices) < limit {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices, nil
+}
+
+func (r *fakeInvoiceRepo) UpdateEmailStatus(ctx context.Context, invoice *model.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.invoices {
+		if r.invoices[i].ID == invoice.ID {
+			r.invoices[i].EmailedAt = invoice.EmailedAt
+			r.invoices[i].EmailAttempts = invoice.EmailAttempts
+			r.invoices[i].EmailError = invoice.EmailError
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeInvoiceRepo) NextNumber(ctx context.Context, kind model.InvoiceKind) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.numbers == nil {
+		r.numbers = map[model.InvoiceKind]int64{}
+	}
+	r.numbers[kind]++
+	return r.numbers[kind], nil
+}
+
+func (r *fakeInvoiceRepo) find(match func(invoice *model.Invoice) bool, notFound error) (*model.Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.invoices {
+		if match(&r.invoices[i]) {
+			cp := r.invoices[i]
+			return &cp, nil
+		}
+	}
+	return nil, notFound
+}
+
+// fakeMailer gönderilen mesajları saklar; fail true ise gönderim başarısız olur
+type fakeMailer struct {
+	mu   sync.Mutex
+	fail bool
+	sent []email.Message
+}
+
+func (m *fakeMailer) SendMessage(msg email.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return errors.New("smtp bağlantısı kurulamadı")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *fakeMailer) messages() []email.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]email.Message(nil), m.sent...)
+}
+
+type fakeDisputeRepo struct {
+	repository.IDisputeRepository
+	mu          sync.Mutex
+	disputes    []model.Dispute
+	photos      []model.DisputePhoto
+	events      []model.DisputeEvent
+	adjustments []model.RideAdjustment
+}
+
+func (r *fakeDisputeRepo) Create(ctx context.Context, dispute *model.Dispute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dispute.ID = int64(len(r.disputes) + 1)
+	dispute.CreatedAt = time.Now()
+	r.disputes = append(r.disputes, *dispute)
+	return nil
+}
+
+func (r *fakeDisputeRepo) GetByID(ctx context.Context, id int64) (*model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, dispute := range r.disputes {
+		if dispute.ID != id {
+			continue
+		}
+		for _, photo := range r.photos {
+			if photo.DisputeID == id {
+				dispute.Photos = append(dispute.Photos, photo)
+			}
+		}
+		for _, event := range r.events {
+			if event.DisputeID == id {
+				dispute.Events = append(dispute.Events, event)
+			}
+		}
+		return &dispute, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeDisputeRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Dispute, error) {
+	return r.find(func(d model.Dispute) bool { return d.ID == id }, sql.ErrNoRows)
+}
+
+func (r *fakeDisputeRepo) GetActiveByRideID(ctx context.Context, rideID int64) (*model.Dispute, error) {
+	return r.find(func(d model.Dispute) bool { return d.RideID == rideID && !d.Status.IsClosed() }, nil)
+}
+
+func (r *fakeDisputeRepo) Update(ctx context.Context, dispute *model.Dispute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.disputes {
+		if r.disputes[i].ID == dispute.ID {
+			r.disputes[i] = *dispute
+			r.disputes[i].Photos, r.disputes[i].Events = nil, nil
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeDisputeRepo) List(ctx context.Context, filter model.DisputeFilter, pagination *query.Pagination) ([]model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []model.Dispute
+	for _, d := range r.disputes {
+		if (filter.Status == "" || d.Status == filter.Status) && (filter.Reason == "" || d.Reason == filter.Reason) &&
+			(filter.UserID == 0 || d.UserID == filter.UserID) && (filter.RideID == 0 || d.RideID == filter.RideID) {
+			matched = append(matched, d)
+		}
+	}
+	pagination.TotalRows = int64(len(matched))
+	pagination.TotalPages = (len(matched) + pagination.PageSize - 1) / pagination.PageSize
+	start := min((pagination.Page-1)*pagination.PageSize, len(matched))
+	return matched[start:min(start+pagination.PageSize, len(matched))], nil
+}
+
+func (r *fakeDisputeRepo) ListByUserID(ctx context.Context, userID int64) ([]model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var disputes []model.Dispute
+	for i := len(r.disputes) - 1; i >= 0; i-- {
+		if r.disputes[i].UserID == userID {
+			disputes = append(disputes, r.disputes[i])
+		}
+	}
+	return disputes, nil
+}
+
+func (r *fakeDisputeRepo) CreatePhotos(ctx context.Context, photos []model.DisputePhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range photos {
+		photos[i].ID = int64(len(r.photos) + 1)
+		r.photos = append(r.photos, photos[i])
+	}
+	return nil
+}
+
+func (r *fakeDisputeRepo) GetPhoto(ctx context.Context, disputeID, photoID int64) (*model.DisputePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, photo := range r.photos {
+		if photo.ID == photoID && photo.DisputeID == disputeID {
+			return &photo, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDisputeRepo) CreateEvent(ctx context.Context, event *model.DisputeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = int64(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeDisputeRepo) CreateAdjustment(ctx context.Context, adjustment *model.RideAdjustment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	adjustment.ID = int64(len(r.adjustments) + 1)
+	r.adjustments = append(r.adjustments, *adjustment)
+	return nil
+}
+
+func (r *fakeDisputeRepo) CompleteAdjustmentRefund(ctx context.Context, id int64, status model.ProviderRefundStatus, refundID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.adjustments {
+		if r.adjustments[i].ID == id && r.adjustments[i].ProviderRefundStatus == model.ProviderRefundPending {
+			r.adjustments[i].ProviderRefundStatus = status
+			r.adjustments[i].ProviderRefundID = refundID
+		}
+	}
+	return nil
+}
+
+func (r *fakeDisputeRepo) ListAdjustmentsByRideID(ctx context.Context, rideID int64) ([]model.RideAdjustment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var adjustments []model.RideAdjustment
+	for _, adjustment := range r.adjustments {
+		if adjustment.RideID == rideID {
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+	return adjustments, nil
+}
+
+func (r *fakeDisputeRepo) find(match func(d model.Dispute) bool, notFound error) (*model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.disputes {
+		if match(d) {
+			return &d, nil
+		}
+	}
+	return nil, notFound
+}
+
+type fakeMaintenanceRepo struct {
+	repository.IMaintenanceRepository
+	mu        sync.Mutex
+	orders    []model.WorkOrder
+	parts     []model.WorkOrderPart
+	schedules []model.MaintenanceSchedule
+}
+
+func (r *fakeMaintenanceRepo) CreateWorkOrder(ctx context.Context, order *model.WorkOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order.ID = int64(len(r.orders) + 1)
+	order.CreatedAt = time.Now()
+	r.orders = append(r.orders, *order)
+	return nil
+}
+
+func (r *fakeMaintenanceRepo) GetWorkOrder(ctx context.Context, id int64) (*model.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.ID == id {
+			order.Parts = r.partsOf(id)
+			return &order, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeMaintenanceRepo) GetWorkOrderForUpdate(ctx context.Context, id int64) (*model.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.ID == id {
+			return &order, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeMaintenanceRepo) UpdateWorkOrder(ctx context.Context, order *model.WorkOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.orders {
+		if r.orders[i].ID == order.ID {
+			r.orders[i] = *order
+			r.orders[i].Parts = nil
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeMaintenanceRepo) ListWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []model.WorkOrder
+	for i := len(r.orders) - 1; i >= 0; i-- {
+		if order := r.orders[i]; order.MotorbikeID == motorbikeID {
+			order.Parts = r.partsOf(order.ID)
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeMaintenanceRepo) ListOpenWorkOrdersByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []model.WorkOrder
+	for _, order := range r.orders {
+		if order.MotorbikeID == motorbikeID && !order.Status.IsClosed() {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeMaintenanceRepo) GetLastCompletedWorkOrder(ctx context.Context, motorbikeID int64, orderType model.MaintenanceType) (*model.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.orders) - 1; i >= 0; i-- {
+		if order := r.orders[i]; order.MotorbikeID == motorbikeID && order.Type == orderType && order.Status == model.WorkOrderCompleted {
+			return &order, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeMaintenanceRepo) ReplaceParts(ctx context.Context, workOrderID int64, parts []model.WorkOrderPart) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parts = slices.DeleteFunc(r.parts, func(part model.WorkOrderPart) bool { return part.WorkOrderID == workOrderID })
+	for i := range parts {
+		parts[i].ID = int64(len(r.parts) + 1)
+		r.parts = append(r.parts, parts[i])
+	}
+	return nil
+}
+
+func (r *fakeMaintenanceRepo) partsOf(workOrderID int64) []model.WorkOrderPart {
+	var parts []model.WorkOrderPart
+	for _, part := range r.parts {
+		if part.WorkOrderID == workOrderID {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func (r *fakeMaintenanceRepo) CreateSchedule(ctx context.Context, schedule *model.MaintenanceSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedule.ID = int64(len(r.schedules) + 1)
+	r.schedules = append(r.schedules, *schedule)
+	return nil
+}
+
+func (r *fakeMaintenanceRepo) ListSchedules(ctx context.Context, activeOnly bool) ([]model.MaintenanceSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schedules []model.MaintenanceSchedule
+	for _, schedule := range r.schedules {
+		if !activeOnly || schedule.IsActive {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+type fakeIssueRepo struct {
+	repository.IIssueRepository
+	mu     sync.Mutex
+	issues []model.Issue
+	photos []model.IssuePhoto
+}
+
+func (r *fakeIssueRepo) Create(ctx context.Context, issue *model.Issue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	issue.ID = int64(len(r.issues) + 1)
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = time.Now()
+	}
+	r.issues = append(r.issues, *issue)
+	return nil
+}
+
+func (r *fakeIssueRepo) GetByID(ctx context.Context, id int64) (*model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, issue := range r.issues {
+		if issue.ID == id {
+			for _, photo := range r.photos {
+				if photo.IssueID == id {
+					issue.Photos = append(issue.Photos, photo)
+				}
+			}
+			return &issue, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeIssueRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, issue := range r.issues {
+		if issue.ID == id {
+			return &issue, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeIssueRepo) Update(ctx context.Context, issue *model.Issue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.issues {
+		if r.issues[i].ID == issue.ID {
+			r.issues[i] = *issue
+			r.issues[i].Photos = nil
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeIssueRepo) ListByUserID(ctx context.Context, userID int64) ([]model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var issues []model.Issue
+	for i := len(r.issues) - 1; i >= 0; i-- {
+		if r.issues[i].UserID == userID {
+			issues = append(issues, r.issues[i])
+		}
+	}
+	return issues, nil
+}
+
+func (r *fakeIssueRepo) ListDuplicates(ctx context.Context, issueID int64) ([]model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var issues []model.Issue
+	for _, issue := range r.issues {
+		if issue.DuplicateOfID != nil && *issue.DuplicateOfID == issueID {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+func (r *fakeIssueRepo) ReassignDuplicates(ctx context.Context, fromID, toID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.issues {
+		if r.issues[i].DuplicateOfID != nil && *r.issues[i].DuplicateOfID == fromID {
+			id := toID
+			r.issues[i].DuplicateOfID = &id
+		}
+	}
+	return nil
+}
+
+func (r *fakeIssueRepo) GetOpenByUser(ctx context.Context, motorbikeID, userID int64, category model.IssueCategory) (*model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, issue := range r.issues {
+		if issue.MotorbikeID == motorbikeID && issue.UserID == userID && issue.Category == category && issue.Status == model.IssueOpen {
+			return &issue, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeIssueRepo) ListUnflaggedByMotorbikeID(ctx context.Context, motorbikeID int64) ([]model.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var issues []model.Issue
+	for _, issue := range r.issues {
+		if issue.MotorbikeID == motorbikeID && issue.Status == model.IssueOpen && issue.WorkOrderID == nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+func (r *fakeIssueRepo) ListUnflaggedMotorbikeIDs(ctx context.Context) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []int64
+	for _, issue := range r.issues {
+		if issue.Status == model.IssueOpen && issue.WorkOrderID == nil && !slices.Contains(ids, issue.MotorbikeID) {
+			ids = append(ids, issue.MotorbikeID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeIssueRepo) SetWorkOrder(ctx context.Context, issueIDs []int64, workOrderID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.issues {
+		if slices.Contains(issueIDs, r.issues[i].ID) {
+			id := workOrderID
+			r.issues[i].WorkOrderID = &id
+		}
+	}
+	return nil
+}
+
+func (r *fakeIssueRepo) CreatePhotos(ctx context.Context, photos []model.IssuePhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range photos {
+		photos[i].ID = int64(len(r.photos) + 1)
+		r.photos = append(r.photos, photos[i])
+	}
+	return nil
+}
+
+type fakeTaskRepo struct {
+	repository.ITaskRepository
+	mu     sync.Mutex
+	tasks  []model.Task
+	photos []model.TaskPhoto
+}
+
+func (r *fakeTaskRepo) Create(ctx context.Context, task *model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.ID = int64(len(r.tasks) + 1)
+	task.CreatedAt = time.Now()
+	r.tasks = append(r.tasks, *task)
+	return nil
+}
+
+func (r *fakeTaskRepo) GetByID(ctx context.Context, id int64) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range r.tasks {
+		if task.ID == id {
+			for _, photo := range r.photos {
+				if photo.TaskID == id {
+					task.Photos = append(task.Photos, photo)
+				}
+			}
+			return &task, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeTaskRepo) GetByIDForUpdate(ctx context.Context, id int64) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range r.tasks {
+		if task.ID == id {
+			return &task, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, task *model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.tasks {
+		if r.tasks[i].ID == task.ID {
+			r.tasks[i] = *task
+			r.tasks[i].Photos = nil
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeTaskRepo) ListByAssigneeID(ctx context.Context, assigneeID int64) ([]model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []model.Task
+	for _, task := range r.tasks {
+		if task.AssigneeID != nil && *task.AssigneeID == assigneeID && !task.Status.IsClosed() {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DueAt.Before(tasks[j].DueAt) })
+	return tasks, nil
+}
+
+func (r *fakeTaskRepo) ListOpenInBounds(ctx context.Context, box geo.BoundingBox) ([]model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []model.Task
+	for _, task := range r.tasks {
+		if task.Status == model.TaskOpen &&
+			task.Latitude >= box.MinLat && task.Latitude <= box.MaxLat &&
+			task.Longitude >= box.MinLng && task.Longitude <= box.MaxLng {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeTaskRepo) GetLatestByMotorbikeID(ctx context.Context, motorbikeID int64, taskType model.TaskType) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.tasks) - 1; i >= 0; i-- {
+		if r.tasks[i].MotorbikeID == motorbikeID && r.tasks[i].Type == taskType {
+			task := r.tasks[i]
+			return &task, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) CreatePhotos(ctx context.Context, photos []model.TaskPhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range photos {
+		photos[i].ID = int64(len(r.photos) + 1)
+		r.photos = append(r.photos, photos[i])
+	}
+	return nil
+}
+
+type fakeRoleRepo struct {
+	repository.IRoleRepository
+	mu          sync.Mutex
+	permissions []model.PermissionDefinition
+	roles       map[model.Role]*model.RoleDefinition
+	grants      map[model.Role][]model.Permission
+	userCounts  map[model.Role]int
+	cleared     []model.Role
+}
+
+// newFakeRoleRepo migration'daki rollerle ve yetki kataloğuyla başlar
+func newFakeRoleRepo() *fakeRoleRepo {
+	r := &fakeRoleRepo{
+		roles:      map[model.Role]*model.RoleDefinition{},
+		grants:     map[model.Role][]model.Permission{model.OperatorRole: {model.PermTasksWork}},
+		userCounts: map[model.Role]int{},
+	}
+	for _, role := range []model.Role{model.AdminRole, model.UserRole, model.OperatorRole} {
+		r.roles[role] = &model.RoleDefinition{Name: role}
+	}
+	for _, permission := range []model.Permission{
+		model.PermUsersRead, model.PermUsersWrite, model.PermUsersBan, model.PermUsersDelete, model.PermWalletsAdjust,
+		model.PermRidesRead, model.PermRidesDelete, model.PermRidesRefund, model.PermDisputesRead, model.PermDisputesReview,
+		model.PermReservationsCancel,
+		model.PermMotorbikesRead, model.PermMotorbikesWrite, model.PermMotorbikesCommand,
+		model.PermMaintenanceRead, model.PermMaintenanceWrite, model.PermIssuesRead, model.PermIssuesTriage,
+		model.PermTasksWork, model.PermTasksManage, model.PermBluetoothRead, model.PermBluetoothWrite,
+		model.PermTariffsRead, model.PermTariffsWrite, model.PermPromotionsRead, model.PermPromotionsWrite,
+		model.PermPassesRead, model.PermPassesWrite, model.PermZonesRead, model.PermZonesWrite, model.PermRolesManage,
+		model.PermAuditRead,
+	} {
+		r.permissions = append(r.permissions, model.PermissionDefinition{Name: permission})
+	}
+	sort.Slice(r.permissions, func(i, j int) bool { return r.permissions[i].Name < r.permissions[j].Name })
+	return r
+}
+
+func (r *fakeRoleRepo) ListPermissions(ctx context.Context) ([]model.PermissionDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.permissions), nil
+}
+
+func (r *fakeRoleRepo) ListRoles(ctx context.Context) ([]model.RoleDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var roles []model.RoleDefinition
+	for _, role := range r.roles {
+		cp := *role
+		cp.Permissions = slices.Clone(r.grants[role.Name])
+		roles = append(roles, cp)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) GetRole(ctx context.Context, name model.Role) (*model.RoleDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *role
+	cp.Permissions = slices.Clone(r.grants[name])
+	return &cp, nil
+}
+
+func (r *fakeRoleRepo) CreateRole(ctx context.Context, role *model.RoleDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role.CreatedAt = time.Now()
+	cp := *role
+	r.roles[role.Name] = &cp
+	return nil
+}
+
+func (r *fakeRoleRepo) UpdateRole(ctx context.Context, role *model.RoleDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roles[role.Name].Description = role.Description
+	return nil
+}
+
+func (r *fakeRoleRepo) DeleteRole(ctx context.Context, name model.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.roles, name)
+	delete(r.grants, name)
+	return nil
+}
+
+func (r *fakeRoleRepo) CountUsers(ctx context.Context, name model.Role) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.userCounts[name], nil
+}
+
+func (r *fakeRoleRepo) GetRolePermissions(ctx context.Context, name model.Role) ([]model.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.grants[name]), nil
+}
+
+func (r *fakeRoleRepo) SetRolePermissions(ctx context.Context, name model.Role, permissions []model.Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.grants[name] = slices.Clone(permissions)
+	return nil
+}
+
+func (r *fakeRoleRepo) ClearPermissionCache(ctx context.Context, name model.Role) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleared = append(r.cleared, name)
+}
+
+type fakeAuditLogRepo struct {
+	repository.IAuditLogRepository
+	mu   sync.Mutex
+	logs []model.AuditLog
+}
+
+func (r *fakeAuditLogRepo) LockChain(ctx context.Context) error { return nil }
+
+func (r *fakeAuditLogRepo) GetLast(ctx context.Context) (*model.AuditLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.logs) == 0 {
+		return nil, nil
+	}
+	last := r.logs[len(r.logs)-1]
+	return &last, nil
+}
+
+// Create jsonb alanları PostgreSQL gibi farklı biçimde saklar; özet bu farka rağmen tutmalıdır
+func (r *fakeAuditLogRepo) Create(ctx context.Context, log *model.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	log.ID = int64(len(r.logs) + 1)
+	stored := *log
+	stored.Before, stored.After = reformatJSON(log.Before), reformatJSON(log.After)
+	r.logs = append(r.logs, stored)
+	return nil
+}
+
+func (r *fakeAuditLogRepo) List(ctx context.Context, filter model.AuditLogFilter, pagination *query.Pagination) ([]model.AuditLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []model.AuditLog
+	for i := len(r.logs) - 1; i >= 0; i-- {
+		if matchesAuditFilter(r.logs[i], filter) {
+			matched = append(matched, r.logs[i])
+		}
+	}
+	pagination.TotalRows = int64(len(matched))
+	pagination.TotalPages = (len(matched) + pagination.PageSize - 1) / pagination.PageSize
+	start := min((pagination.Page-1)*pagination.PageSize, len(matched))
+	return matched[start:min(start+pagination.PageSize, len(matched))], nil
+}
+
+func (r *fakeAuditLogRepo) ListAfter(ctx context.Context, filter model.AuditLogFilter, afterID int64, limit int) ([]model.AuditLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var logs []model.AuditLog
+	for _, log := range r.logs {
+		if log.ID > afterID && matchesAuditFilter(log, filter) && len(logs) < limit {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func matchesAuditFilter(log model.AuditLog, filter model.AuditLogFilter) bool {
+	return (filter.ActorID == 0 || (log.ActorID != nil && *log.ActorID == filter.ActorID)) &&
+		(filter.Action == "" || log.Action == filter.Action) &&
+		(filter.TargetType == "" || log.TargetType == filter.TargetType) &&
+		(filter.TargetID == "" || log.TargetID == filter.TargetID) &&
+		(filter.From.IsZero() || !log.CreatedAt.Before(filter.From)) &&
+		(filter.To.IsZero() || log.CreatedAt.Before(filter.To))
+}
+
+func reformatJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// fakeLockController LockController çağrılarını sırasıyla kaydeder; failing içindeki işlemler hata döner.
+// Transaction içinden yapılan çağrılar ayrıca insideTx'e yazılır.
+type fakeLockController struct {
+	mu       sync.Mutex
+	calls    []string
+	insideTx []string
+	failing  map[string]bool
+}
+
+func (c *fakeLockController) Connect(ctx context.Context, motorbikeID int64) error {
+	return c.call(ctx, "connect")
+}
+
+func (c *fakeLockController) Unlock(ctx context.Context, motorbikeID int64) error {
+	return c.call(ctx, "unlock")
+}
+
+func (c *fakeLockController) Lock(ctx context.Context, motorbikeID int64) error {
+	return c.call(ctx, "lock")
+}
+
+func (c *fakeLockController) Disconnect(ctx context.Context, motorbikeID int64) error {
+	return c.call(ctx, "disconnect")
+}
+
+func (c *fakeLockController) call(ctx context.Context, op string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, op)
+	if ctx.Value(fakeTxKey{}) != nil {
+		c.insideTx = append(c.insideTx, op)
+	}
+	if c.failing[op] {
+		return errors.New("cihaz " + op + " işlemini yapamadı")
+	}
+	return nil
+}
+
+func (c *fakeLockController) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
+
+func (c *fakeLockController) InsideTx() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.insideTx)
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func baseTariff() model.Tariff {
+	return model.Tariff{
+		BaseModel:            model.BaseModel{ID: 1},
+		Name:                 "Test",
+		Currency:             "TRY",
+		UnlockFee:            1000,
+		PerMinuteRate:        300,
+		NightMultiplierPct:   100,
+		WeekendMultiplierPct: 100,
+		IsActive:             true,
+	}
+}
+
+// Pazartesi öğlen, gece ve hafta sonu aralıklarının dışında
+var weekdayNoon = time.Date(2024, 9, 2, 12, 0, 0, 0, time.UTC)
+
+func lineAmount(b *model.RidePriceBreakdown, code string) int64 {
+	for _, line := range b.Lines {
+		if line.Code == code {
+			return line.Amount
+		}
+	}
+	return 0
+}
+
+func TestFareCalculator(t *testing.T) {
+	calc := service.NewFareCalculator(time.UTC)
+
+	t.Run("Unlock Fee Plus Per Minute", func(t *testing.T) {
+		b := calc.Calculate(baseTariff(), service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(10 * time.Minute)})
+		assert.Equal(t, 10, b.BillableMinutes)
+		assert.Equal(t, int64(1000+10*300), b.Total)
+		assert.Equal(t, "TRY", b.Currency)
+	})
+
+	t.Run("Started Minute Is Charged", func(t *testing.T) {
+		b := calc.Calculate(baseTariff(), service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(10*time.Minute + time.Second)})
+		assert.Equal(t, 11, b.TotalMinutes)
+	})
+
+	t.Run("Free Minutes", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.FreeMinutes = 5
+		b := calc.Calculate(tariff, service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(3 * time.Minute)})
+		assert.Equal(t, 0, b.BillableMinutes)
+		assert.Equal(t, int64(1000), b.Total)
+	})
+
+	t.Run("Minimum Charge", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.MinimumCharge = 2000
+		b := calc.Calculate(tariff, service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(time.Minute)})
+		assert.Equal(t, int64(2000), b.Total)
+		assert.Equal(t, int64(700), lineAmount(b, model.PriceLineMinimumCharge))
+	})
+
+	t.Run("Night Multiplier Across Midnight", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.NightMultiplierPct = 150
+		tariff.NightStartHour = 22
+		tariff.NightEndHour = 6
+		start := time.Date(2024, 9, 2, 21, 50, 0, 0, time.UTC)
+		b := calc.Calculate(tariff, service.FareInput{StartTime: start, EndTime: start.Add(20 * time.Minute)})
+		// 10 dakika gündüz, 10 dakika gece
+		assert.Equal(t, int64(10*150), lineAmount(b, model.PriceLineNightSurcharge))
+		assert.Equal(t, int64(1000+20*300+10*150), b.Total)
+	})
+
+	t.Run("Weekend Multiplier Uses Configured Timezone", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.WeekendMultiplierPct = 200
+		istanbul := time.FixedZone("TRT", 3*60*60)
+		// UTC'de cuma 22:00, İstanbul'da cumartesi 01:00
+		start := time.Date(2024, 9, 6, 22, 0, 0, 0, time.UTC)
+		input := service.FareInput{StartTime: start, EndTime: start.Add(10 * time.Minute)}
+
+		utcResult := service.NewFareCalculator(time.UTC).Calculate(tariff, input)
+		localResult := service.NewFareCalculator(istanbul).Calculate(tariff, input)
+		assert.Equal(t, int64(0), lineAmount(utcResult, model.PriceLineWeekendSurcharge))
+		assert.Equal(t, int64(10*300), lineAmount(localResult, model.PriceLineWeekendSurcharge))
+	})
+
+	t.Run("Daily Cap Per 24 Hours", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.DailyCap = 200000
+		b := calc.Calculate(tariff, service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(30 * time.Hour)})
+		// İlk 24 saat limite takılır, kalan 6 saat (360 dakika) limitin altında kalır
+		assert.Equal(t, int64(200000+360*300), b.Total)
+	})
+}
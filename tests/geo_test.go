@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Kadıköy civarında kabaca bir kare, ortasında delik
+const squareWithHoleGeoJSON = `{
+	"type": "Polygon",
+	"coordinates": [
+		[[29.00, 40.98], [29.04, 40.98], [29.04, 41.00], [29.00, 41.00], [29.00, 40.98]],
+		[[29.015, 40.985], [29.025, 40.985], [29.025, 40.995], [29.015, 40.995], [29.015, 40.985]]
+	]
+}`
+
+func TestPointInPolygon(t *testing.T) {
+	area, err := geo.ParseGeoJSON([]byte(squareWithHoleGeoJSON))
+	assert.NoError(t, err)
+
+	t.Run("Inside", func(t *testing.T) {
+		assert.True(t, area.Contains(geo.Point{Lat: 40.99, Lng: 29.005}))
+	})
+
+	t.Run("Outside", func(t *testing.T) {
+		assert.False(t, area.Contains(geo.Point{Lat: 41.01, Lng: 29.02}))
+		assert.False(t, area.Contains(geo.Point{Lat: 40.99, Lng: 28.99}))
+	})
+
+	t.Run("Inside Hole", func(t *testing.T) {
+		assert.False(t, area.Contains(geo.Point{Lat: 40.99, Lng: 29.02}))
+	})
+
+	t.Run("Concave Polygon", func(t *testing.T) {
+		// U şeklinde alan; ortadaki boşluk dışarıda kalmalı
+		u, err := geo.ParseGeoJSON([]byte(`{"type":"Polygon","coordinates":[[[0,0],[3,0],[3,3],[2,3],[2,1],[1,1],[1,3],[0,3]]]}`))
+		assert.NoError(t, err)
+		assert.True(t, u.Contains(geo.Point{Lat: 2, Lng: 0.5}))
+		assert.True(t, u.Contains(geo.Point{Lat: 2, Lng: 2.5}))
+		assert.False(t, u.Contains(geo.Point{Lat: 2, Lng: 1.5}))
+	})
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	t.Run("Feature Collection Of Multi Polygons", func(t *testing.T) {
+		area, err := geo.ParseGeoJSON([]byte(`{
+			"type": "FeatureCollection",
+			"features": [
+				{"type": "Feature", "geometry": {"type": "MultiPolygon", "coordinates": [
+					[[[0,0],[1,0],[1,1],[0,1]]],
+					[[[5,5],[6,5],[6,6],[5,6]]]
+				]}}
+			]
+		}`))
+		assert.NoError(t, err)
+		assert.Len(t, area, 2)
+		assert.True(t, area.Contains(geo.Point{Lat: 5.5, Lng: 5.5}))
+	})
+
+	t.Run("Rejects Invalid Input", func(t *testing.T) {
+		inputs := []string{
+			`not json`,
+			`{"type":"Point","coordinates":[29,41]}`,
+			`{"type":"Polygon","coordinates":[[[0,0],[1,1]]]}`,
+			`{"type":"Polygon","coordinates":[[[200,0],[1,0],[1,1]]]}`,
+			`{"type":"Polygon","coordinates":[]}`,
+		}
+		for _, input := range inputs {
+			_, err := geo.ParseGeoJSON([]byte(input))
+			assert.ErrorIs(t, err, geo.ErrInvalidGeoJSON, input)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/email"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/money"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+const testVATRatePct = 20
+
+func TestSplitInclusive(t *testing.T) {
+	net, tax := money.SplitInclusive(1200, 20)
+	assert.Equal(t, int64(1000), net)
+	assert.Equal(t, int64(200), tax)
+
+	net, tax = money.SplitInclusive(1000, 20)
+	assert.Equal(t, int64(833), net)
+	assert.Equal(t, int64(167), tax)
+
+	net, tax = money.SplitInclusive(500, 0)
+	assert.Equal(t, int64(500), net)
+	assert.Equal(t, int64(0), tax)
+}
+
+func TestPDFDocument(t *testing.T) {
+	doc := pdf.New("Fiş")
+	doc.Text(50, 50, pdf.HelveticaBold, 12, "Müşteri (test)")
+	doc.AddPage()
+	doc.TextRight(545, 50, pdf.Helvetica, 10, "12.50 TRY")
+	out := doc.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	// ü WinAnsiEncoding ile, ş en yakın karşılığıyla yazılır; parantezler kaçırılır
+	assert.Contains(t, string(out), `(M\374steri \(test\))`)
+
+	// xref tablosundaki ofsetler nesnelerin başlangıcını göstermeli
+	var xref int
+	_, err := fmt.Sscanf(string(out[bytes.LastIndex(out, []byte("startxref"))+len("startxref\n"):]), "%d", &xref)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out[xref:], []byte("xref")))
+	var first int
+	_, err = fmt.Sscanf(string(out[xref:]), "xref\n0 %d\n0000000000 65535 f \n%010d", new(int), &first)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out[first:], []byte("1 0 obj")))
+}
+
+func TestEmailMessage(t *testing.T) {
+	data := bytes.Repeat([]byte("%PDF-1.4 içerik "), 20)
+	msg := email.Message{
+		To:          "user@example.com",
+		Subject:     "Sürüş fişiniz",
+		Body:        "Merhaba",
+		HTMLBody:    "<p>Merhaba</p>",
+		Attachments: []email.Attachment{{Filename: "FIS-2026-000001.pdf", ContentType: "application/pdf", Data: data}},
+	}
+	raw, err := msg.Bytes("noreply@example.com", time.Now())
+	assert.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sürüş fişiniz", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	body, err := reader.NextPart()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body.Header.Get("Content-Type"), "multipart/alternative"))
+
+	attachment, err := reader.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "FIS-2026-000001.pdf", attachment.FileName())
+	encoded, err := io.ReadAll(attachment)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decodeBase64Lines(t, encoded))
+
+	_, err = reader.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func decodeBase64Lines(t *testing.T, encoded []byte) []byte {
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(strings.TrimSpace(string(encoded)), "\r\n", ""))
+	assert.NoError(t, err)
+	return decoded
+}
+
+func invoiceUser(id int64) model.User {
+	user := testUser(id, model.StatusActive)
+	user.FirstName = "Ayşe"
+	user.LastName = "Yılmaz"
+	user.Email = fmt.Sprintf("user%d@example.com", id)
+	return user
+}
+
+func TestRideReceipt(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{invoiceUser(1), invoiceUser(2)}
+	bikes := func() []model.Motorbike { return []model.Motorbike{testMotorbike(10, model.BikeAvailable)} }
+
+	t.Run("Issued And Emailed On Finish", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		if !assert.Len(t, f.invoiceRepo.invoices, 1) {
+			return
+		}
+		receipt := f.invoiceRepo.invoices[0]
+		assert.Equal(t, model.InvoiceReceipt, receipt.Kind)
+		assert.Equal(t, fmt.Sprintf("FIS-%d-000001", time.Now().Year()), receipt.Number)
+		assert.Equal(t, finished.Cost, receipt.Total)
+		assert.Equal(t, receipt.Total, receipt.NetAmount+receipt.VATAmount)
+		assert.Equal(t, testVATRatePct, receipt.VATRatePct)
+		assert.NotNil(t, receipt.EmailedAt)
+		assert.True(t, bytes.HasPrefix(receipt.PDF, []byte("%PDF-")))
+		assert.Contains(t, receipt.HTML, "Test Kiralama A.Ş.")
+		assert.Contains(t, receipt.HTML, money.Format(receipt.VATAmount, receipt.Currency))
+
+		sent := f.mailer.messages()
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, "user1@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Subject, receipt.Number)
+			assert.Equal(t, receipt.Number+".pdf", sent[0].Attachments[0].Filename)
+			assert.Equal(t, receipt.PDF, sent[0].Attachments[0].Data)
+		}
+	})
+
+	t.Run("Access And Idempotency", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 5*time.Minute)
+		_, err := f.invoices.GetRideReceipt(ctx, ride.ID, 1, model.UserRole)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		_, err = f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		receipt, err := f.invoices.GetRideReceipt(ctx, ride.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		_, err = f.invoices.GetRideReceipt(ctx, ride.ID, 2, model.UserRole)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		again, err := f.invoices.GetRideReceipt(ctx, ride.ID, 2, model.AdminRole)
+		assert.NoError(t, err)
+		assert.Equal(t, receipt.Number, again.Number)
+		assert.Len(t, f.invoiceRepo.invoices, 1)
+		assert.Len(t, f.mailer.messages(), 1)
+	})
+
+	t.Run("Failed Email Is Retried", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		f.mailer.fail = true
+		ride := startTestRide(t, f, 1, 10, 5*time.Minute)
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		receipt := f.invoiceRepo.invoices[0]
+		assert.Nil(t, receipt.EmailedAt)
+		assert.Equal(t, 1, receipt.EmailAttempts)
+		assert.NotEmpty(t, receipt.EmailError)
+
+		f.mailer.fail = false
+		sent, err := f.invoices.ResendPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.NotNil(t, f.invoiceRepo.invoices[0].EmailedAt)
+
+		sent, err = f.invoices.ResendPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	})
+}
+
+func TestMonthlyInvoices(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{invoiceUser(1), invoiceUser(2)}
+	bikes := []model.Motorbike{
+		testMotorbike(10, model.BikeAvailable),
+		testMotorbike(11, model.BikeAvailable),
+		testMotorbike(12, model.BikeAvailable),
+	}
+	f := newRideFixture(users, bikes)
+
+	var total int64
+	for _, bikeID := range []int64{10, 11} {
+		ride := startTestRide(t, f, 1, bikeID, 10*time.Minute-time.Second)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		total += finished.Cost
+	}
+	// Bitmemiş sürüş faturaya girmez
+	startTestRide(t, f, 2, 12, 5*time.Minute)
+
+	issued, err := f.invoices.IssueMonthlyInvoices(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, issued)
+
+	invoices, err := f.invoices.ListByUser(ctx, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, invoices, 3) {
+		monthly := invoices[0]
+		assert.Equal(t, model.InvoiceMonthly, monthly.Kind)
+		assert.Equal(t, fmt.Sprintf("FTR-%d-000001", time.Now().Year()), monthly.Number)
+		assert.Equal(t, total, monthly.Total)
+		assert.Equal(t, total, monthly.NetAmount+monthly.VATAmount)
+		y, m, _ := time.Now().UTC().Date()
+		assert.Equal(t, time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), *monthly.PeriodStart)
+	}
+
+	// Aynı ay için tekrar fatura kesilmez
+	issued, err = f.invoices.IssueMonthlyInvoices(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, issued)
+	assert.Len(t, f.mailer.messages(), 3)
+}
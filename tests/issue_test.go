@@ -0,0 +1,305 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type issueFixture struct {
+	fleetFixture
+	service     *service.IssueService
+	issues      *fakeIssueRepo
+	maintenance *fakeMaintenanceRepo
+	mailer      *fakeMailer
+	dir         string
+}
+
+func newIssueFixture(t *testing.T, motorbikes ...model.Motorbike) *issueFixture {
+	f := &issueFixture{
+		fleetFixture: newFleetFixture([]model.User{invoiceUser(1), invoiceUser(2), invoiceUser(3)}, motorbikes),
+		issues:       &fakeIssueRepo{},
+		maintenance:  &fakeMaintenanceRepo{},
+		mailer:       &fakeMailer{},
+		dir:          t.TempDir(),
+	}
+	f.service = service.NewIssueService(service.IssueServiceDeps{
+		IssueRepo:     f.issues,
+		MotorbikeRepo: f.motorbikes,
+		RideRepo:      f.rides,
+		UserRepo:      f.users,
+		TxManager:     &fakeTxManager{},
+		Maintenance:   f.newMaintenanceService(f.maintenance),
+		Store:         storage.NewLocalStore(f.dir),
+		Mailer:        f.mailer,
+		MaxPhotos:     2,
+		MaxPhotoBytes: 1 << 20,
+		FlagThreshold: 2,
+		FlagWindow:    24 * time.Hour,
+		Permissions:   f.rbac,
+		Audit:         service.NoopAuditor{},
+	})
+	return f
+}
+
+func (f *issueFixture) report(t *testing.T, motorbikeID, userID int64, category model.IssueCategory, severity model.IssueSeverity) *model.Issue {
+	issue, err := f.service.Report(context.Background(), service.ReportIssueInput{
+		MotorbikeID: motorbikeID, UserID: userID, Category: category, Severity: severity, Description: "Sorun var",
+	})
+	assert.NoError(t, err)
+	return issue
+}
+
+func (f *issueFixture) issue(t *testing.T, id int64) *model.Issue {
+	issue, err := f.issues.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	return issue
+}
+
+func TestIssueReporting(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Critical Report Takes Motorbike Into Maintenance", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		issue := f.report(t, 10, 1, model.IssueBrakes, model.SeverityCritical)
+		assert.Equal(t, model.IssueOpen, issue.Status)
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+
+		assert.Len(t, f.maintenance.orders, 1)
+		order := f.maintenance.orders[0]
+		assert.Equal(t, model.MaintenanceBrakes, order.Type)
+		assert.Equal(t, model.PriorityUrgent, order.Priority)
+		assert.Equal(t, &order.ID, f.issue(t, issue.ID).WorkOrderID)
+	})
+
+	t.Run("Threshold Of Distinct Reporters Flags Motorbike", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		first := f.report(t, 10, 1, model.IssueElectrical, model.SeverityLow)
+		f.report(t, 10, 1, model.IssueTyre, model.SeverityMedium)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+		assert.Nil(t, f.issue(t, first.ID).WorkOrderID)
+
+		f.report(t, 10, 2, model.IssueElectrical, model.SeverityHigh)
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+		assert.Len(t, f.maintenance.orders, 1)
+		assert.Equal(t, model.MaintenanceInspection, f.maintenance.orders[0].Type)
+		assert.Equal(t, model.PriorityHigh, f.maintenance.orders[0].Priority)
+		for _, issue := range f.issues.issues {
+			assert.NotNil(t, issue.WorkOrderID)
+		}
+	})
+
+	t.Run("Reports Outside Window Are Not Counted", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		f.report(t, 10, 1, model.IssueTyre, model.SeverityMedium)
+		f.issues.mu.Lock()
+		f.issues.issues[0].CreatedAt = time.Now().Add(-48 * time.Hour)
+		f.issues.mu.Unlock()
+
+		f.report(t, 10, 2, model.IssueTyre, model.SeverityMedium)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+		assert.Empty(t, f.maintenance.orders)
+	})
+
+	t.Run("Report Joins Open Work Order Of Same Type", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		f.report(t, 10, 1, model.IssueTyre, model.SeverityMedium)
+		f.report(t, 10, 2, model.IssueTyre, model.SeverityMedium)
+		assert.Len(t, f.maintenance.orders, 1)
+		assert.Equal(t, model.PriorityNormal, f.maintenance.orders[0].Priority)
+
+		issue := f.report(t, 10, 3, model.IssueTyre, model.SeverityCritical)
+		assert.Len(t, f.maintenance.orders, 1)
+		assert.Equal(t, model.PriorityUrgent, f.maintenance.orders[0].Priority)
+		assert.Equal(t, f.maintenance.orders[0].ID, *f.issue(t, issue.ID).WorkOrderID)
+	})
+
+	t.Run("Duplicate Open Report Is Rejected", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		f.report(t, 10, 1, model.IssueBattery, model.SeverityLow)
+		_, err := f.service.Report(ctx, service.ReportIssueInput{
+			MotorbikeID: 10, UserID: 1, Category: model.IssueBattery, Severity: model.SeverityHigh, Description: "Yine",
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Len(t, f.issues.issues, 1)
+
+		f.report(t, 10, 1, model.IssueBrakes, model.SeverityLow)
+	})
+
+	t.Run("Motorbike In Use Is Flagged After It Becomes Available", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeRented))
+
+		issue := f.report(t, 10, 1, model.IssueBrakes, model.SeverityCritical)
+		assert.Equal(t, model.BikeRented, f.motorbikeStatus(t, 10))
+		assert.Empty(t, f.maintenance.orders)
+
+		assert.NoError(t, f.service.FlagPending(ctx))
+		assert.Empty(t, f.maintenance.orders)
+
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		motorbike.Status = model.BikeAvailable
+		assert.NoError(t, f.motorbikes.Update(ctx, motorbike))
+
+		assert.NoError(t, f.service.FlagPending(ctx))
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+		assert.NotNil(t, f.issue(t, issue.ID).WorkOrderID)
+	})
+
+	t.Run("Photos Are Stored With The Report", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		issue, err := f.service.Report(ctx, service.ReportIssueInput{
+			MotorbikeID: 10, UserID: 1, Category: model.IssueBodywork, Severity: model.SeverityLow, Description: "Çizik",
+			Photos: [][]byte{testJPEG(t, 64, 32, 1)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, countFiles(t, f.dir))
+
+		photos := f.issue(t, issue.ID).Photos
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "image/jpeg", photos[0].ContentType)
+		assert.Equal(t, 64, photos[0].Width)
+	})
+
+	t.Run("Invalid Photo Rejects Report Without Leaving Files", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+
+		_, err := f.service.Report(ctx, service.ReportIssueInput{
+			MotorbikeID: 10, UserID: 1, Category: model.IssueBodywork, Severity: model.SeverityLow, Description: "Çizik",
+			Photos: [][]byte{testJPEG(t, 64, 32, 1), []byte("not an image")},
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, 0, countFiles(t, f.dir))
+		assert.Empty(t, f.issues.issues)
+
+		_, err = f.service.Report(ctx, service.ReportIssueInput{
+			MotorbikeID: 10, UserID: 1, Category: model.IssueBodywork, Severity: model.SeverityLow, Description: "Çizik",
+			Photos: [][]byte{testJPEG(t, 8, 8, 1), testJPEG(t, 8, 8, 1), testJPEG(t, 8, 8, 1)},
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Ride Must Belong To Reporter And Motorbike", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable))
+		own := &model.Ride{MotorbikeID: 10, UserID: 1, Status: model.RideCompleted}
+		other := &model.Ride{MotorbikeID: 10, UserID: 2, Status: model.RideCompleted}
+		elsewhere := &model.Ride{MotorbikeID: 11, UserID: 1, Status: model.RideCompleted}
+		for _, ride := range []*model.Ride{own, other, elsewhere} {
+			assert.NoError(t, f.rides.Create(ctx, ride))
+		}
+
+		input := service.ReportIssueInput{MotorbikeID: 10, UserID: 1, Category: model.IssueTyre, Severity: model.SeverityLow, Description: "Patlak"}
+		input.RideID = &other.ID
+		_, err := f.service.Report(ctx, input)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+
+		input.RideID = &elsewhere.ID
+		_, err = f.service.Report(ctx, input)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		input.RideID = &own.ID
+		issue, err := f.service.Report(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, own.ID, *issue.RideID)
+	})
+
+	t.Run("Reporter Or Admin Can View", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+		issue := f.report(t, 10, 1, model.IssueTyre, model.SeverityLow)
+
+		_, err := f.service.Get(ctx, issue.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		_, err = f.service.Get(ctx, issue.ID, 2, model.UserRole)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		_, err = f.service.Get(ctx, issue.ID, testAdminID, model.AdminRole)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIssueTriage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Link Ride Requires Same Motorbike", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable))
+		issue := f.report(t, 10, 1, model.IssueTyre, model.SeverityLow)
+		ride := &model.Ride{MotorbikeID: 11, UserID: 2, Status: model.RideCompleted}
+		assert.NoError(t, f.rides.Create(ctx, ride))
+
+		_, err := f.service.LinkRide(ctx, issue.ID, ride.ID)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		ride = &model.Ride{MotorbikeID: 10, UserID: 2, Status: model.RideCompleted}
+		assert.NoError(t, f.rides.Create(ctx, ride))
+		linked, err := f.service.LinkRide(ctx, issue.ID, ride.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, ride.ID, *linked.RideID)
+	})
+
+	t.Run("Mark Duplicate Rules", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable))
+		original := f.report(t, 10, 1, model.IssueTyre, model.SeverityLow)
+		duplicate := f.report(t, 10, 2, model.IssueBattery, model.SeverityLow)
+		third := f.report(t, 10, 3, model.IssueBodywork, model.SeverityLow)
+		elsewhere := f.report(t, 11, 1, model.IssueTyre, model.SeverityLow)
+
+		_, err := f.service.MarkDuplicate(ctx, duplicate.ID, testAdminID, duplicate.ID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.service.MarkDuplicate(ctx, duplicate.ID, testAdminID, elsewhere.ID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		// third -> duplicate, sonra duplicate -> original: third de original'e bağlanır
+		_, err = f.service.MarkDuplicate(ctx, third.ID, testAdminID, duplicate.ID, "Aynı arıza")
+		assert.NoError(t, err)
+		marked, err := f.service.MarkDuplicate(ctx, duplicate.ID, testAdminID, original.ID, "Aynı arıza")
+		assert.NoError(t, err)
+		assert.Equal(t, model.IssueDuplicate, marked.Status)
+		assert.Equal(t, original.ID, *marked.DuplicateOfID)
+		assert.Equal(t, original.ID, *f.issue(t, third.ID).DuplicateOfID)
+
+		_, err = f.service.MarkDuplicate(ctx, duplicate.ID, testAdminID, original.ID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		fourth := f.report(t, 10, 3, model.IssueBrakes, model.SeverityLow)
+		_, err = f.service.MarkDuplicate(ctx, fourth.ID, testAdminID, duplicate.ID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Resolve Notifies Reporter And Duplicate Reporters", func(t *testing.T) {
+		f := newIssueFixture(t, testMotorbike(10, model.BikeAvailable))
+		original := f.report(t, 10, 1, model.IssueTyre, model.SeverityLow)
+		duplicate := f.report(t, 10, 2, model.IssueBattery, model.SeverityLow)
+		_, err := f.service.MarkDuplicate(ctx, duplicate.ID, testAdminID, original.ID, "")
+		assert.NoError(t, err)
+
+		_, err = f.service.Resolve(ctx, original.ID, testAdminID, "  ")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		resolved, err := f.service.Resolve(ctx, original.ID, testAdminID, "Lastik değiştirildi")
+		assert.NoError(t, err)
+		assert.Equal(t, model.IssueResolved, resolved.Status)
+		assert.Equal(t, int64(testAdminID), *resolved.ResolvedBy)
+		assert.NotNil(t, resolved.ResolvedAt)
+
+		messages := f.mailer.messages()
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "user1@example.com", messages[0].To)
+		assert.Equal(t, "user2@example.com", messages[1].To)
+		assert.Contains(t, messages[0].Body, "Lastik değiştirildi")
+
+		_, err = f.service.Resolve(ctx, original.ID, testAdminID, "Tekrar")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.service.Resolve(ctx, duplicate.ID, testAdminID, "Kopya")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+}
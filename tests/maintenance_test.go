@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+type maintenanceFixture struct {
+	fleetFixture
+	service     *service.MaintenanceService
+	maintenance *fakeMaintenanceRepo
+}
+
+func newMaintenanceFixture(motorbikes ...model.Motorbike) *maintenanceFixture {
+	f := &maintenanceFixture{
+		fleetFixture: newFleetFixture([]model.User{testStaff(testAdminID, model.AdminRole), testUser(1, model.StatusActive)}, motorbikes),
+		maintenance:  &fakeMaintenanceRepo{},
+	}
+	f.service = f.newMaintenanceService(f.maintenance)
+	return f
+}
+
+func (f *maintenanceFixture) endRides(t *testing.T, motorbikeID int64, count int) {
+	for i := 0; i < count; i++ {
+		assert.NoError(t, f.rides.Create(context.Background(), &model.Ride{MotorbikeID: motorbikeID, UserID: 1, Status: model.RideCompleted}))
+	}
+}
+
+func (f *maintenanceFixture) setOdometer(motorbikeID, meters int64) {
+	f.motorbikes.mu.Lock()
+	defer f.motorbikes.mu.Unlock()
+	f.motorbikes.motorbikes[motorbikeID].OdometerMeters = &meters
+}
+
+func TestMaintenanceWorkOrders(t *testing.T) {
+	ctx := context.Background()
+	adminID := int64(testAdminID)
+
+	t.Run("Open Takes Motorbike Into Maintenance", func(t *testing.T) {
+		f := newMaintenanceFixture(testMotorbike(10, model.BikeAvailable))
+		f.setOdometer(10, 12500)
+		f.endRides(t, 10, 3)
+
+		order, err := f.service.Open(ctx, service.OpenWorkOrderInput{
+			MotorbikeID: 10, Type: model.MaintenanceTyre, Description: "Arka lastik aşınmış", OpenedBy: &adminID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.WorkOrderOpen, order.Status)
+		assert.Equal(t, model.PriorityNormal, order.Priority)
+		assert.Equal(t, 3, order.RideCount)
+		assert.Equal(t, int64(12500), *order.OdometerMeters)
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+
+		_, err = f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceTyre, Description: "Tekrar"})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		_, err = f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceBrakes, Description: "Fren sesi"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rejects Rented Motorbike And Non Admin Technician", func(t *testing.T) {
+		f := newMaintenanceFixture(testMotorbike(10, model.BikeRented), testMotorbike(11, model.BikeAvailable))
+
+		_, err := f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceBattery, Description: "Şarj tutmuyor"})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, model.BikeRented, f.motorbikeStatus(t, 10))
+
+		technicianID := int64(1)
+		_, err = f.service.Open(ctx, service.OpenWorkOrderInput{
+			MotorbikeID: 11, Type: model.MaintenanceBattery, Description: "Şarj tutmuyor", TechnicianID: &technicianID,
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 11))
+	})
+
+	t.Run("Complete Records Costs And Releases Motorbike After Last Order", func(t *testing.T) {
+		f := newMaintenanceFixture(testMotorbike(10, model.BikeAvailable))
+		tyre, err := f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceTyre, Description: "Lastik"})
+		assert.NoError(t, err)
+		brakes, err := f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceBrakes, Description: "Fren"})
+		assert.NoError(t, err)
+
+		started, err := f.service.Start(ctx, tyre.ID, adminID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.WorkOrderInProgress, started.Status)
+		assert.Equal(t, adminID, *started.TechnicianID)
+		assert.NotNil(t, started.StartedAt)
+
+		completed, err := f.service.Complete(ctx, service.CompleteWorkOrderInput{
+			ID: tyre.ID, ActorID: adminID, LabourMinutes: 45, LabourCost: 30000, Note: "Arka lastik değişti",
+			Parts: []model.WorkOrderPart{{Name: "Arka lastik", Quantity: 1, UnitCost: 120000}, {Name: "Sibop", Quantity: 2, UnitCost: 1500}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.WorkOrderCompleted, completed.Status)
+		assert.Equal(t, int64(123000), completed.PartsCost)
+		assert.Equal(t, int64(153000), completed.Cost)
+		assert.Equal(t, adminID, *completed.ClosedBy)
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+
+		_, err = f.service.Complete(ctx, service.CompleteWorkOrderInput{ID: tyre.ID, ActorID: adminID})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		_, err = f.service.Cancel(ctx, brakes.ID, adminID, " ")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		cancelled, err := f.service.Cancel(ctx, brakes.ID, adminID, "Fren sorunu bulunamadı")
+		assert.NoError(t, err)
+		assert.Equal(t, model.WorkOrderCancelled, cancelled.Status)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+
+		history, err := f.service.History(ctx, 10)
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, brakes.ID, history[0].ID)
+		assert.Len(t, history[1].Parts, 2)
+	})
+
+	t.Run("Rejects Invalid Parts", func(t *testing.T) {
+		f := newMaintenanceFixture(testMotorbike(10, model.BikeAvailable))
+		order, err := f.service.Open(ctx, service.OpenWorkOrderInput{MotorbikeID: 10, Type: model.MaintenanceBodywork, Description: "Çizik"})
+		assert.NoError(t, err)
+
+		_, err = f.service.Complete(ctx, service.CompleteWorkOrderInput{
+			ID: order.ID, ActorID: adminID, Parts: []model.WorkOrderPart{{Name: "Boya", Quantity: 0, UnitCost: 100}},
+		})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+	})
+}
+
+func TestMaintenanceScheduleIsDue(t *testing.T) {
+	meters := func(m int64) *int64 { return &m }
+	schedule := model.MaintenanceSchedule{IntervalMeters: 1000, IntervalRides: 10, IsActive: true}
+
+	assert.False(t, schedule.IsDue(meters(999), 9, nil))
+	assert.True(t, schedule.IsDue(meters(1000), 0, nil))
+	assert.True(t, schedule.IsDue(nil, 10, nil))
+
+	last := &model.WorkOrder{OdometerMeters: meters(5000), RideCount: 20}
+	assert.False(t, schedule.IsDue(meters(5999), 29, last))
+	assert.True(t, schedule.IsDue(meters(6000), 29, last))
+	assert.True(t, schedule.IsDue(meters(5100), 30, last))
+
+	ridesOnly := model.MaintenanceSchedule{IntervalRides: 10, IsActive: true}
+	assert.False(t, ridesOnly.IsDue(meters(1_000_000), 5, nil))
+}
+
+func TestMaintenanceOpenDue(t *testing.T) {
+	ctx := context.Background()
+
+	f := newMaintenanceFixture(testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeRented), testMotorbike(12, model.BikeAvailable))
+	assert.NoError(t, f.service.CreateSchedule(ctx, &model.MaintenanceSchedule{
+		Name: "Lastik kontrolü", Type: model.MaintenanceTyre, IntervalMeters: 5000, IsActive: true,
+	}))
+	assert.NoError(t, f.service.CreateSchedule(ctx, &model.MaintenanceSchedule{
+		Name: "Fren kontrolü", Type: model.MaintenanceBrakes, IntervalRides: 3, Priority: model.PriorityHigh, IsActive: true,
+	}))
+	assert.NoError(t, f.service.CreateSchedule(ctx, &model.MaintenanceSchedule{
+		Name: "Başka model", Type: model.MaintenanceBattery, MotorbikeModel: "other", IntervalRides: 1, IsActive: true,
+	}))
+	f.setOdometer(10, 5200)
+	f.setOdometer(11, 9000)
+	f.endRides(t, 12, 3)
+
+	opened, err := f.service.OpenDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, opened)
+	assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 10))
+	assert.Equal(t, model.BikeRented, f.motorbikeStatus(t, 11))
+	assert.Equal(t, model.BikeInMaintenance, f.motorbikeStatus(t, 12))
+
+	open, err := f.maintenance.ListOpenWorkOrdersByMotorbikeID(ctx, 12)
+	assert.NoError(t, err)
+	if assert.Len(t, open, 1) {
+		assert.Equal(t, model.MaintenanceBrakes, open[0].Type)
+		assert.Equal(t, model.PriorityHigh, open[0].Priority)
+		assert.NotNil(t, open[0].ScheduleID)
+		assert.Nil(t, open[0].OpenedBy)
+	}
+
+	opened, err = f.service.OpenDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, opened)
+
+	_, err = f.service.Complete(ctx, service.CompleteWorkOrderInput{ID: open[0].ID, ActorID: testAdminID})
+	assert.NoError(t, err)
+	assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 12))
+	opened, err = f.service.OpenDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, opened)
+
+	f.endRides(t, 12, 3)
+	opened, err = f.service.OpenDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, opened)
+}
+
+func TestMaintenanceScheduleValidation(t *testing.T) {
+	f := newMaintenanceFixture()
+	err := f.service.CreateSchedule(context.Background(), &model.MaintenanceSchedule{Name: "Boş", Type: model.MaintenanceTyre, IsActive: true})
+	assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+}
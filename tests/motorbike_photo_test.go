@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMaxMotorbikePhotos = 4
+
+func newMotorbikePhotoFixture(t *testing.T) (*service.MotorbikePhotoService, *fakeMotorbikeRepo, string) {
+	dir := t.TempDir()
+	motorbikes := newFakeMotorbikeRepo(testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable))
+	photos := service.NewMotorbikePhotoService(motorbikes, &fakeTxManager{}, storage.NewLocalStore(dir), 1<<20, testMaxMotorbikePhotos)
+	return photos, motorbikes, dir
+}
+
+func countFiles(t *testing.T, dir string) int {
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	return count
+}
+
+func photoIDs(photos []model.MotorbikePhoto) []int64 {
+	ids := make([]int64, len(photos))
+	for i, photo := range photos {
+		ids[i] = photo.ID
+	}
+	return ids
+}
+
+func primaryPhotoID(photos []model.MotorbikePhoto) int64 {
+	var id int64
+	for _, photo := range photos {
+		if photo.IsPrimary {
+			id = photo.ID
+		}
+	}
+	return id
+}
+
+func TestMotorbikePhotoGallery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Upload Appends And Sets Cover", func(t *testing.T) {
+		photos, _, dir := newMotorbikePhotoFixture(t)
+
+		uploaded, err := photos.Upload(ctx, 10, [][]byte{testJPEG(t, 64, 32, 1), testJPEG(t, 32, 64, 1)})
+		assert.NoError(t, err)
+		assert.Len(t, uploaded, 2)
+		assert.True(t, uploaded[0].IsPrimary)
+		assert.False(t, uploaded[1].IsPrimary)
+		assert.Equal(t, 4, countFiles(t, dir))
+
+		more, err := photos.Upload(ctx, 10, [][]byte{testJPEG(t, 16, 16, 1)})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, more[0].Position)
+		assert.False(t, more[0].IsPrimary)
+
+		covers, err := photos.Covers(ctx, []int64{10, 11})
+		assert.NoError(t, err)
+		assert.Len(t, covers, 1)
+		assert.Equal(t, uploaded[0].ID, covers[10].ID)
+	})
+
+	t.Run("Rejects Whole Batch", func(t *testing.T) {
+		photos, _, dir := newMotorbikePhotoFixture(t)
+
+		_, err := photos.Upload(ctx, 10, [][]byte{testJPEG(t, 16, 16, 1), []byte("not an image")})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = photos.Upload(ctx, 99, [][]byte{testJPEG(t, 16, 16, 1)})
+		assertAppErrorCode(t, err, errorx.ErrNotFound)
+
+		batch := make([][]byte, testMaxMotorbikePhotos+1)
+		for i := range batch {
+			batch[i] = testJPEG(t, 16, 16, 1)
+		}
+		_, err = photos.Upload(ctx, 10, batch)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		gallery, _ := photos.List(ctx, 10)
+		assert.Empty(t, gallery)
+		assert.Equal(t, 0, countFiles(t, dir))
+	})
+
+	t.Run("Reorder And Set Primary", func(t *testing.T) {
+		photos, _, _ := newMotorbikePhotoFixture(t)
+		uploaded, err := photos.Upload(ctx, 10, [][]byte{testJPEG(t, 16, 16, 1), testJPEG(t, 16, 16, 1), testJPEG(t, 16, 16, 1)})
+		assert.NoError(t, err)
+		a, b, c := uploaded[0].ID, uploaded[1].ID, uploaded[2].ID
+
+		_, err = photos.Reorder(ctx, 10, []int64{c, a})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = photos.Reorder(ctx, 10, []int64{c, a, a})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		gallery, err := photos.Reorder(ctx, 10, []int64{c, a, b})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{c, a, b}, photoIDs(gallery))
+
+		gallery, err = photos.SetPrimary(ctx, 10, b)
+		assert.NoError(t, err)
+		assert.Equal(t, b, primaryPhotoID(gallery))
+		assert.Equal(t, []int64{c, a, b}, photoIDs(gallery))
+
+		_, err = photos.SetPrimary(ctx, 11, b)
+		assertAppErrorCode(t, err, errorx.ErrNotFound)
+	})
+
+	t.Run("Delete Compacts And Promotes Cover", func(t *testing.T) {
+		photos, _, dir := newMotorbikePhotoFixture(t)
+		uploaded, err := photos.Upload(ctx, 10, [][]byte{testJPEG(t, 16, 16, 1), testJPEG(t, 16, 16, 1), testJPEG(t, 16, 16, 1)})
+		assert.NoError(t, err)
+
+		assertAppErrorCode(t, photos.Delete(ctx, 11, uploaded[0].ID), errorx.ErrNotFound)
+		assert.NoError(t, photos.Delete(ctx, 10, uploaded[0].ID))
+
+		gallery, _ := photos.List(ctx, 10)
+		assert.Equal(t, []int64{uploaded[1].ID, uploaded[2].ID}, photoIDs(gallery))
+		assert.Equal(t, 0, gallery[0].Position)
+		assert.Equal(t, 1, gallery[1].Position)
+		assert.Equal(t, uploaded[1].ID, primaryPhotoID(gallery))
+		assert.Equal(t, 4, countFiles(t, dir))
+	})
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Taksim Meydanı
+var taksim = geo.Point{Lat: 41.0370, Lng: 28.9850}
+
+func TestHaversine(t *testing.T) {
+	t.Run("Known Distance", func(t *testing.T) {
+		// Taksim - Kadıköy iskelesi kuş uçuşu yaklaşık 5.9 km
+		kadikoy := geo.Point{Lat: 40.9923, Lng: 29.0237}
+		assert.InDelta(t, 5900, geo.HaversineMeters(taksim, kadikoy), 200)
+	})
+
+	t.Run("Same Point", func(t *testing.T) {
+		assert.Equal(t, 0.0, geo.HaversineMeters(taksim, taksim))
+	})
+
+	t.Run("Bounding Box Contains Radius", func(t *testing.T) {
+		box := geo.BoundingBoxAround(taksim, 1000)
+		north := geo.Point{Lat: box.MaxLat, Lng: taksim.Lng}
+		east := geo.Point{Lat: taksim.Lat, Lng: box.MaxLng}
+		assert.InDelta(t, 1000, geo.HaversineMeters(taksim, north), 1)
+		assert.InDelta(t, 1000, geo.HaversineMeters(taksim, east), 1)
+	})
+
+	t.Run("Bounding Box Across Antimeridian Skips Longitude Filter", func(t *testing.T) {
+		box := geo.BoundingBoxAround(geo.Point{Lat: 0, Lng: 179.999}, 1000)
+		assert.Equal(t, -180.0, box.MinLng)
+		assert.Equal(t, 180.0, box.MaxLng)
+	})
+}
+
+func TestFindNearby(t *testing.T) {
+	ctx := context.Background()
+
+	bikeAt := func(id int64, status model.MotorBikeStatus, lat, lng float64) model.Motorbike {
+		m := testMotorbike(id, status)
+		m.LocationLatitude = lat
+		m.LocationLongitude = lng
+		return m
+	}
+
+	repo := newFakeMotorbikeRepo(
+		bikeAt(1, model.BikeAvailable, 41.0390, 28.9850),     // ~220 m
+		bikeAt(2, model.BikeAvailable, 41.0371, 28.9851),     // ~15 m
+		bikeAt(3, model.BikeRented, 41.0371, 28.9850),        // kiralık
+		bikeAt(4, model.BikeAvailable, 41.0450, 28.9850),     // ~890 m
+		bikeAt(5, model.BikeAvailable, 41.0370, 28.9970),     // ~1.01 km, yarıçapın hemen dışında
+		bikeAt(6, model.BikeAvailable, 41.0460, 28.9970),     // kutu içinde, daire dışında
+		bikeAt(7, model.BikeInMaintenance, 41.0370, 28.9851), // bakımda
+	)
+	svc := service.NewMotorbikeService(repo, service.NoopAuditor{})
+
+	t.Run("Sorted By Distance Within Radius", func(t *testing.T) {
+		nearby, err := svc.FindNearby(ctx, taksim, 1000, 10)
+		assert.NoError(t, err)
+
+		ids := make([]int64, len(nearby))
+		for i, n := range nearby {
+			ids[i] = n.Motorbike.ID
+		}
+		assert.Equal(t, []int64{2, 1, 4}, ids)
+		for i := 1; i < len(nearby); i++ {
+			assert.LessOrEqual(t, nearby[i-1].DistanceMeters, nearby[i].DistanceMeters)
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		nearby, err := svc.FindNearby(ctx, taksim, 1000, 2)
+		assert.NoError(t, err)
+		assert.Len(t, nearby, 2)
+		assert.Equal(t, int64(2), nearby[0].Motorbike.ID)
+	})
+
+	t.Run("Nothing In Range", func(t *testing.T) {
+		nearby, err := svc.FindNearby(ctx, geo.Point{Lat: 39.92, Lng: 32.85}, 1000, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, nearby)
+	})
+}
@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPassPrice = 5000
+
+func createPassProduct(t *testing.T, f *rideFixture, includedMinutes int, waiveUnlock bool) *model.PassProduct {
+	product := &model.PassProduct{
+		Name:                  "Günlük Paket",
+		Period:                model.PassDaily,
+		Price:                 testPassPrice,
+		Currency:              "TRY",
+		IncludedMinutesPerDay: includedMinutes,
+		WaiveUnlockFee:        waiveUnlock,
+		IsActive:              true,
+	}
+	assert.NoError(t, f.passes.CreateProduct(context.Background(), product))
+	return product
+}
+
+func passLine(breakdown *model.RidePriceBreakdown) *model.PriceLine {
+	for i := range breakdown.Lines {
+		if breakdown.Lines[i].Code == model.PriceLinePass {
+			return &breakdown.Lines[i]
+		}
+	}
+	return nil
+}
+
+func TestFareCalculatorWithPass(t *testing.T) {
+	calc := service.NewFareCalculator(time.UTC)
+
+	t.Run("Included Minutes After Free Minutes", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.FreeMinutes = 2
+		b := calc.Calculate(tariff, service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(10 * time.Minute), PassMinutes: 5})
+		assert.Equal(t, 3, b.BillableMinutes)
+		assert.Equal(t, int64(1000+3*300), b.Total)
+		if line := passLine(b); assert.NotNil(t, line) {
+			assert.Equal(t, 5, line.Quantity)
+			assert.Equal(t, int64(0), line.Amount)
+		}
+	})
+
+	t.Run("Included Minutes Capped By Ride Length", func(t *testing.T) {
+		b := calc.Calculate(baseTariff(), service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(4 * time.Minute), PassMinutes: 30})
+		assert.Equal(t, 0, b.BillableMinutes)
+		assert.Equal(t, 4, passLine(b).Quantity)
+	})
+
+	t.Run("Unlock Fee Waived And Minimum Charge Skipped", func(t *testing.T) {
+		tariff := baseTariff()
+		tariff.MinimumCharge = 2000
+		b := calc.Calculate(tariff, service.FareInput{StartTime: weekdayNoon, EndTime: weekdayNoon.Add(time.Minute), WaiveUnlockFee: true})
+		assert.Equal(t, int64(0), lineAmount(b, model.PriceLineUnlockFee))
+		assert.Equal(t, int64(0), lineAmount(b, model.PriceLineMinimumCharge))
+		assert.Equal(t, int64(300), b.Total)
+	})
+}
+
+func TestPassPurchase(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive)}
+
+	t.Run("Paid From Wallet", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		product := createPassProduct(t, f, 30, true)
+		topUp(t, f, 1, 8000)
+
+		sub, err := f.passes.Purchase(ctx, 1, product.ID, true)
+		assert.NoError(t, err)
+		assert.Equal(t, model.SubscriptionActive, sub.Status)
+		assert.Equal(t, sub.CurrentPeriodStart.AddDate(0, 0, 1), sub.CurrentPeriodEnd)
+		assert.Equal(t, int64(3000), walletBalance(t, f, 1))
+		assert.Equal(t, 0, f.provider.Calls(payment.OpAuthorize))
+		assert.Equal(t, int64(testPassPrice), f.ledger.systemBalance(model.SystemAccountPassRevenue))
+		assertLedgerBalanced(t, f.ledger)
+
+		if assert.Len(t, f.passRepo.charges, 1) {
+			assert.Equal(t, int64(0), f.passRepo.charges[0].CardAmount)
+		}
+	})
+
+	t.Run("Card Pays Wallet Shortfall", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		product := createPassProduct(t, f, 30, true)
+		topUp(t, f, 1, 1000)
+
+		_, err := f.passes.Purchase(ctx, 1, product.ID, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+		assert.Equal(t, 1, f.provider.Calls(payment.OpCapture))
+		assert.Equal(t, int64(4000), f.passRepo.charges[0].CardAmount)
+		assert.Equal(t, int64(-(1000 + 4000)), f.ledger.systemBalance(model.SystemAccountCash))
+		assertLedgerBalanced(t, f.ledger)
+	})
+
+	t.Run("Declined Card Creates No Subscription", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		product := createPassProduct(t, f, 30, true)
+		f.provider.Next(payment.OpAuthorize, payment.Decline)
+
+		_, err := f.passes.Purchase(ctx, 1, product.ID, true)
+		assert.Error(t, err)
+		assert.Empty(t, f.passRepo.subscriptions)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+	})
+
+	t.Run("Second Active Pass Rejected", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		product := createPassProduct(t, f, 30, true)
+		topUp(t, f, 1, 2*testPassPrice)
+
+		_, err := f.passes.Purchase(ctx, 1, product.ID, true)
+		assert.NoError(t, err)
+		_, err = f.passes.Purchase(ctx, 1, product.ID, true)
+		assertAppErrorCode(t, err, errorx.ErrDuplicate)
+		assert.Equal(t, int64(testPassPrice), walletBalance(t, f, 1))
+	})
+
+	t.Run("Cancel Auto Renew", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		product := createPassProduct(t, f, 30, true)
+		topUp(t, f, 1, testPassPrice)
+		_, err := f.passes.Purchase(ctx, 1, product.ID, true)
+		assert.NoError(t, err)
+
+		sub, err := f.passes.CancelAutoRenew(ctx, 1)
+		assert.NoError(t, err)
+		assert.False(t, sub.AutoRenew)
+		assert.NotNil(t, sub.CancelledAt)
+
+		_, err = f.passes.CancelAutoRenew(ctx, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		// Dönem sonuna kadar abonelik kullanılabilir
+		status, err := f.passes.GetActive(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 30, status.RemainingMinutes)
+	})
+}
+
+func TestPassRidePricing(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive)}
+	bikes := []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)}
+
+	f := newRideFixture(users, bikes)
+	product := createPassProduct(t, f, 15, true)
+	topUp(t, f, 1, testPassPrice)
+	_, err := f.passes.Purchase(ctx, 1, product.ID, true)
+	assert.NoError(t, err)
+	// Sürüşler abonelik döneminin içinde başlamalı
+	f.passRepo.subscriptions[0].CurrentPeriodStart = time.Now().UTC().Add(-time.Hour)
+
+	first := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+	finished, err := f.service.FinishRide(ctx, first.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), finished.Cost)
+
+	breakdown, err := f.rides.GetPriceBreakdown(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, passLine(breakdown).Quantity)
+	assert.Equal(t, int64(0), lineAmount(breakdown, model.PriceLineUnlockFee))
+
+	// Aynı gün ikinci sürüşte yalnızca kalan 5 dakika dahildir
+	second := startTestRide(t, f, 1, 11, 10*time.Minute-time.Second)
+	finished, err = f.service.FinishRide(ctx, second.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5*300), finished.Cost)
+
+	breakdown, err = f.rides.GetPriceBreakdown(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, passLine(breakdown).Quantity)
+	assert.Len(t, f.passRepo.usages, 2)
+
+	status, err := f.passes.GetActive(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, status.RemainingMinutes)
+}
+
+func TestPassRenewal(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive)}
+
+	purchase := func(t *testing.T, f *rideFixture, autoRenew bool) (*model.Subscription, time.Time) {
+		product := createPassProduct(t, f, 30, false)
+		topUp(t, f, 1, testPassPrice)
+		sub, err := f.passes.Purchase(ctx, 1, product.ID, autoRenew)
+		assert.NoError(t, err)
+		return sub, sub.CurrentPeriodEnd.Add(time.Minute)
+	}
+
+	t.Run("Renews And Charges Next Period", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		sub, now := purchase(t, f, true)
+		topUp(t, f, 1, 1000)
+
+		renewed, expired, err := f.passes.RenewDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, renewed)
+		assert.Equal(t, 0, expired)
+
+		updated := f.passRepo.subscriptions[0]
+		assert.Equal(t, model.SubscriptionActive, updated.Status)
+		assert.Equal(t, sub.CurrentPeriodEnd, updated.CurrentPeriodStart)
+		assert.Len(t, f.passRepo.charges, 2)
+		assert.Equal(t, int64(4000), f.passRepo.charges[1].CardAmount)
+		assert.Equal(t, int64(2*testPassPrice), f.ledger.systemBalance(model.SystemAccountPassRevenue))
+		assertLedgerBalanced(t, f.ledger)
+
+		// Aynı dönem ikinci kez ücretlendirilmez
+		renewed, _, err = f.passes.RenewDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, renewed)
+	})
+
+	t.Run("Expires When Auto Renew Off", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		_, now := purchase(t, f, false)
+
+		renewed, expired, err := f.passes.RenewDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, renewed)
+		assert.Equal(t, 1, expired)
+		assert.Equal(t, model.SubscriptionExpired, f.passRepo.subscriptions[0].Status)
+		assert.Len(t, f.passRepo.charges, 1)
+	})
+
+	t.Run("Expires When Card Declined", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		sub, now := purchase(t, f, true)
+		f.provider.Next(payment.OpAuthorize, payment.Decline)
+
+		_, expired, err := f.passes.RenewDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, expired)
+
+		updated := f.passRepo.subscriptions[0]
+		assert.Equal(t, model.SubscriptionExpired, updated.Status)
+		assert.NotEmpty(t, updated.RenewalFailure)
+		assert.Equal(t, sub.CurrentPeriodEnd, updated.CurrentPeriodEnd)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+	})
+}
@@ -0,0 +1,349 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWebhookSecret = "test-secret"
+	testHoldAmount    = 5000
+)
+
+func ridePayment(t *testing.T, f *rideFixture, rideID int64) *model.RidePayment {
+	p, err := f.payments.GetByRideID(context.Background(), rideID)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	return p
+}
+
+func signedEvent(t *testing.T, f *rideFixture, event payment.WebhookEvent) ([]byte, string) {
+	payload, err := json.Marshal(event)
+	assert.NoError(t, err)
+	return payload, f.provider.SignWebhook(payload)
+}
+
+func TestFakePaymentProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Authorize Capture Refund", func(t *testing.T) {
+		p := payment.NewFakeProvider(testWebhookSecret)
+		auth, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: 1000, Currency: "TRY", Reference: "ride-1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "fake_auth_1", auth.ID)
+
+		// Aynı referans yeni provizyon açmaz
+		again, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: 1000, Currency: "TRY", Reference: "ride-1"})
+		assert.NoError(t, err)
+		assert.Equal(t, auth.ID, again.ID)
+
+		_, err = p.Capture(ctx, auth.ID, 1500)
+		assert.ErrorIs(t, err, payment.ErrAmountExceeded)
+
+		capture, err := p.Capture(ctx, auth.ID, 600)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, p.Void(ctx, auth.ID), payment.ErrInvalidState)
+
+		_, err = p.Refund(ctx, capture.ID, 400)
+		assert.NoError(t, err)
+		_, err = p.Refund(ctx, capture.ID, 300)
+		assert.ErrorIs(t, err, payment.ErrAmountExceeded)
+	})
+
+	t.Run("Scripted Failures", func(t *testing.T) {
+		p := payment.NewFakeProvider(testWebhookSecret)
+		p.Next(payment.OpAuthorize, payment.Decline, payment.Timeout)
+
+		_, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: 1000})
+		assert.ErrorIs(t, err, payment.ErrDeclined)
+		_, err = p.Authorize(ctx, payment.AuthorizeRequest{Amount: 1000})
+		assert.ErrorIs(t, err, payment.ErrTimeout)
+		_, err = p.Authorize(ctx, payment.AuthorizeRequest{Amount: 1000})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, p.Calls(payment.OpAuthorize))
+	})
+
+	t.Run("Webhook Signature", func(t *testing.T) {
+		p := payment.NewFakeProvider(testWebhookSecret)
+		payload := []byte(`{"id":"evt_1","type":"capture.failed","authorization_id":"fake_auth_1"}`)
+
+		event, err := p.ParseWebhook(payload, p.SignWebhook(payload))
+		assert.NoError(t, err)
+		assert.Equal(t, payment.EventCaptureFailed, event.Type)
+
+		_, err = p.ParseWebhook(payload, payment.NewFakeProvider("other").SignWebhook(payload))
+		assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+	})
+}
+
+func TestRidePayments(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive)}
+	bikes := func() []model.Motorbike { return []model.Motorbike{testMotorbike(10, model.BikeAvailable)} }
+
+	t.Run("Start Authorizes Hold", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideActive, ride.Status)
+
+		p := ridePayment(t, f, ride.ID)
+		assert.Equal(t, model.PaymentAuthorized, p.Status)
+		assert.Equal(t, int64(testHoldAmount), p.AuthorizedAmount)
+	})
+
+	t.Run("Declined Hold Rolls Back Start", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		f.provider.Next(payment.OpAuthorize, payment.Decline)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		var appErr *errorx.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errorx.ErrInvalidRequest.Code, appErr.Code)
+
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeAvailable, motorbike.Status)
+		assert.Equal(t, 0, f.rides.countActive())
+	})
+
+	t.Run("Wallet Covers Fare Voids Hold", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		topUp(t, f, 1, 10000)
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, finished.Status)
+
+		p := ridePayment(t, f, ride.ID)
+		assert.Equal(t, model.PaymentVoided, p.Status)
+		assert.Equal(t, 0, f.provider.Calls(payment.OpCapture))
+		assert.Equal(t, 10000-finished.Cost, walletBalance(t, f, 1))
+	})
+
+	t.Run("Captures Shortfall From Hold", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		topUp(t, f, 1, 1000)
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, finished.Status)
+
+		p := ridePayment(t, f, ride.ID)
+		assert.Equal(t, model.PaymentCaptured, p.Status)
+		assert.Equal(t, finished.Cost-1000, p.CapturedAmount)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+		assert.Equal(t, -(1000 + p.CapturedAmount), f.ledger.systemBalance(model.SystemAccountCash))
+		assertLedgerBalanced(t, f.ledger)
+	})
+
+	t.Run("Unrecorded Capture Is Refunded", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		f.payments.failStatus = model.PaymentCaptured
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RidePaymentFailed, finished.Status)
+		assert.Equal(t, 1, f.provider.Calls(payment.OpCapture))
+		assert.Equal(t, 1, f.provider.Calls(payment.OpRefund))
+
+		// Tahsilat kaydedilmediği için cüzdana yükleme yazılmaz, kullanıcı ödemeyi tekrar dener
+		assert.Equal(t, model.PaymentFailed, ridePayment(t, f, ride.ID).Status)
+		assert.Equal(t, -finished.Cost, walletBalance(t, f, 1))
+		assertLedgerBalanced(t, f.ledger)
+
+		f.payments.failStatus = ""
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+	})
+
+	t.Run("Settle Failure Marks Ride Payment Failed", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		// Sağlayıcı cevap vermez, Settle de bu sonucu kaydedemez
+		f.provider.Next(payment.OpCapture, payment.Timeout)
+		f.payments.failStatus, f.payments.failOnce = model.PaymentFailed, true
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RidePaymentFailed, finished.Status)
+		assert.Equal(t, model.PaymentFailed, ridePayment(t, f, ride.ID).Status)
+
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+	})
+
+	t.Run("Sweep Fails Rides Stuck In Ending", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		f.provider.Next(payment.OpCapture, payment.Timeout)
+		f.payments.failStatus = model.PaymentFailed
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideEnding, finished.Status)
+		f.payments.failStatus = ""
+
+		// Sağlayıcı zaman aşımları dolmadan sürüşe dokunulmaz
+		count, err := f.payment.FailStuckSettlements(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		count, err = f.payment.FailStuckSettlements(ctx, time.Now().UTC().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		stuck, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RidePaymentFailed, stuck.Status)
+		assert.Equal(t, model.PaymentFailed, ridePayment(t, f, ride.ID).Status)
+
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+	})
+
+	t.Run("Shortfall Above Hold Reauthorizes", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 30*time.Minute-time.Second)
+		hold := ridePayment(t, f, ride.ID)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Greater(t, finished.Cost, int64(testHoldAmount))
+		assert.Equal(t, model.RideCompleted, finished.Status)
+
+		auth, _ := f.provider.GetAuthorization(hold.AuthorizationID)
+		assert.Equal(t, payment.AuthorizationVoided, auth.Status)
+
+		p := ridePayment(t, f, ride.ID)
+		assert.NotEqual(t, hold.AuthorizationID, p.AuthorizationID)
+		assert.Equal(t, finished.Cost, p.CapturedAmount)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+	})
+
+	t.Run("Failed Capture Blocks New Rides Until Paid", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		f.provider.Next(payment.OpCapture, payment.Timeout)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, finished.EndTime)
+		assert.Equal(t, model.RidePaymentFailed, finished.Status)
+		assert.Equal(t, model.PaymentFailed, ridePayment(t, f, ride.ID).Status)
+		assert.Equal(t, -finished.Cost, walletBalance(t, f, 1))
+
+		_, err = f.service.StartRide(ctx, 1, 11)
+		var appErr *errorx.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errorx.ErrForbidden.Code, appErr.Code)
+
+		f.provider.Next(payment.OpAuthorize, payment.Decline)
+		_, err = f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.Error(t, err)
+
+		_, err = f.payment.RetryRide(ctx, ride.ID, 2)
+		assert.Error(t, err)
+
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+		assert.Equal(t, int64(0), walletBalance(t, f, 1))
+		assertLedgerBalanced(t, f.ledger)
+
+		_, err = f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.Error(t, err)
+
+		_, err = f.service.StartRide(ctx, 1, 11)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Retry Claims Payment Before Charging", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		f.provider.Next(payment.OpCapture, payment.Decline)
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		// Devam eden bir deneme varken ikinci deneme sağlayıcıya gitmeden reddedilir
+		inFlight := ridePayment(t, f, ride.ID)
+		inFlight.Status = model.PaymentSettling
+		inFlight.RetryCount = 1
+		inFlight.UpdatedAt = time.Now().UTC()
+		assert.NoError(t, f.payments.Update(ctx, inFlight))
+		_, err = f.payment.RetryRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrDuplicate)
+		assert.Equal(t, model.PaymentSettling, ridePayment(t, f, ride.ID).Status)
+
+		// Yarıda kalmış deneme aynı referansla devralınır
+		inFlight.UpdatedAt = time.Now().UTC().Add(-time.Hour)
+		assert.NoError(t, f.payments.Update(ctx, inFlight))
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+
+		p := ridePayment(t, f, ride.ID)
+		assert.Equal(t, model.PaymentCaptured, p.Status)
+		assert.Equal(t, 1, p.RetryCount)
+		auth, err := f.provider.Authorize(ctx, payment.AuthorizeRequest{Amount: p.AuthorizedAmount, Reference: fmt.Sprintf("ride-%d-retry-1", ride.ID)})
+		assert.NoError(t, err)
+		assert.Equal(t, p.AuthorizationID, auth.ID, "deneme idempotency referansıyla gönderilmeli")
+
+		_, err = f.payment.RetryRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Unlock Failure Voids Hold", func(t *testing.T) {
+		f := newRideFixtureWithLocks(users, bikes(), failingUnlock{})
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+
+		assert.Len(t, f.payments.payments, 1)
+		assert.Equal(t, model.PaymentVoided, f.payments.payments[0].Status)
+	})
+
+	t.Run("Capture Failed Webhook Reverses Payment", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		p := ridePayment(t, f, ride.ID)
+
+		event := payment.WebhookEvent{ID: "evt_1", Type: payment.EventCaptureFailed, AuthorizationID: p.AuthorizationID, Reason: "chargeback"}
+		payload, signature := signedEvent(t, f, event)
+
+		assert.Error(t, f.payment.HandleWebhook(ctx, payload, "deadbeef"))
+		assert.NoError(t, f.payment.HandleWebhook(ctx, payload, signature))
+		// Aynı olay tekrar gelirse bakiye ikinci kez düşmez
+		assert.NoError(t, f.payment.HandleWebhook(ctx, payload, signature))
+
+		updated, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RidePaymentFailed, updated.Status)
+		assert.Equal(t, model.PaymentFailed, ridePayment(t, f, ride.ID).Status)
+		assert.Equal(t, -finished.Cost, walletBalance(t, f, 1))
+		assertLedgerBalanced(t, f.ledger)
+	})
+}
+
+// failingUnlock motorun kilidini açamayan LockController
+type failingUnlock struct {
+	service.NoopLockController
+}
+
+func (failingUnlock) Unlock(ctx context.Context, motorbikeID int64) error {
+	return errorx.WrapMsg(errorx.ErrInternal, "Motor kilidi açılamadı")
+}
@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testReferrerCredit = 2500
+	testRefereeCredit  = 1500
+)
+
+func createPromotion(t *testing.T, f *rideFixture, p model.Promotion) *model.Promotion {
+	if p.Currency == "" {
+		p.Currency = "TRY"
+	}
+	p.IsActive = true
+	assert.NoError(t, f.promotion.Create(context.Background(), &p))
+	return &p
+}
+
+func promotionLine(breakdown *model.RidePriceBreakdown) *model.PriceLine {
+	for i := range breakdown.Lines {
+		if breakdown.Lines[i].Code == model.PriceLinePromotion {
+			return &breakdown.Lines[i]
+		}
+	}
+	return nil
+}
+
+func assertAppErrorCode(t *testing.T, err error, expected *errorx.AppError) {
+	var appErr *errorx.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, expected.Code, appErr.Code)
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion model.Promotion
+		amount    int64
+		expected  int64
+	}{
+		{"Percentage", model.Promotion{DiscountType: model.DiscountPercentage, DiscountValue: 20}, 4000, 800},
+		{"Percentage Capped", model.Promotion{DiscountType: model.DiscountPercentage, DiscountValue: 50, MaxDiscount: 1000}, 4000, 1000},
+		{"Fixed", model.Promotion{DiscountType: model.DiscountFixed, DiscountValue: 1500}, 4000, 1500},
+		{"Fixed Above Fare", model.Promotion{DiscountType: model.DiscountFixed, DiscountValue: 5000}, 4000, 4000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.promotion.Discount(tt.amount))
+		})
+	}
+}
+
+func TestPromotions(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+	bikes := func() []model.Motorbike {
+		return []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable), testMotorbike(12, model.BikeAvailable)}
+	}
+
+	t.Run("Discount Applied At Finish", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		promotion := createPromotion(t, f, model.Promotion{Code: "yaz20", DiscountType: model.DiscountPercentage, DiscountValue: 20})
+		assert.Equal(t, "YAZ20", promotion.Code)
+
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 1, "yaz20")
+		assert.NoError(t, err)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		breakdown, err := f.rides.GetPriceBreakdown(ctx, ride.ID)
+		assert.NoError(t, err)
+		line := promotionLine(breakdown)
+		if assert.NotNil(t, line) {
+			gross := breakdown.Total - line.Amount
+			assert.Equal(t, -gross*20/100, line.Amount)
+		}
+		assert.Equal(t, breakdown.Total, finished.Cost)
+
+		assert.Len(t, f.promotions.redemptions, 1)
+		assert.Equal(t, -line.Amount, f.promotions.redemptions[0].Amount)
+		updated, _ := f.promotions.GetByID(ctx, promotion.ID)
+		assert.Equal(t, 1, updated.RedemptionCount)
+	})
+
+	t.Run("Unknown Code", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 1, "YOK")
+		assertAppErrorCode(t, err, errorx.ErrNotFound)
+	})
+
+	t.Run("Other Users Ride", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "HEDIYE", DiscountType: model.DiscountFixed, DiscountValue: 500})
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 2, "HEDIYE")
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+	})
+
+	t.Run("Duplicate Code", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "HEDIYE", DiscountType: model.DiscountFixed, DiscountValue: 500})
+		err := f.promotion.Create(ctx, &model.Promotion{Code: "hediye", DiscountType: model.DiscountFixed, DiscountValue: 100, Currency: "TRY"})
+		assertAppErrorCode(t, err, errorx.ErrDuplicate)
+	})
+
+	t.Run("Validity Window", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		past := time.Now().UTC().Add(-time.Hour)
+		createPromotion(t, f, model.Promotion{Code: "ESKI", DiscountType: model.DiscountFixed, DiscountValue: 500, ValidUntil: &past})
+		future := time.Now().UTC().Add(time.Hour)
+		createPromotion(t, f, model.Promotion{Code: "YAKINDA", DiscountType: model.DiscountFixed, DiscountValue: 500, ValidFrom: &future})
+
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 1, "ESKI")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.promotion.ApplyToRide(ctx, ride.ID, 1, "YAKINDA")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Motorbike Model Restriction", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "ELEKTRIK", DiscountType: model.DiscountFixed, DiscountValue: 500, MotorbikeModels: []string{"e-scooter"}})
+		createPromotion(t, f, model.Promotion{Code: "TEST", DiscountType: model.DiscountFixed, DiscountValue: 500, MotorbikeModels: []string{"test"}})
+
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 1, "ELEKTRIK")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.promotion.ApplyToRide(ctx, ride.ID, 1, "TEST")
+		assert.NoError(t, err)
+	})
+
+	t.Run("First Ride Only", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "ILKSURUS", DiscountType: model.DiscountFixed, DiscountValue: 500, FirstRideOnly: true})
+
+		first := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, first.ID, 1, "ILKSURUS")
+		assert.NoError(t, err)
+		_, err = f.service.FinishRide(ctx, first.ID, 1)
+		assert.NoError(t, err)
+
+		second := startTestRide(t, f, 1, 11, time.Minute)
+		_, err = f.promotion.ApplyToRide(ctx, second.ID, 1, "ILKSURUS")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Per User Limit", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "BIRKEZ", DiscountType: model.DiscountFixed, DiscountValue: 500, PerUserLimit: 1})
+
+		first := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, first.ID, 1, "BIRKEZ")
+		assert.NoError(t, err)
+		_, err = f.service.FinishRide(ctx, first.ID, 1)
+		assert.NoError(t, err)
+
+		second := startTestRide(t, f, 1, 11, time.Minute)
+		_, err = f.promotion.ApplyToRide(ctx, second.ID, 1, "BIRKEZ")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		other := startTestRide(t, f, 2, 12, time.Minute)
+		_, err = f.promotion.ApplyToRide(ctx, other.ID, 2, "BIRKEZ")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Total Limit Reached Before Finish", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "TEK", DiscountType: model.DiscountFixed, DiscountValue: 500, TotalLimit: 1})
+
+		first := startTestRide(t, f, 1, 10, time.Minute)
+		second := startTestRide(t, f, 2, 11, time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, first.ID, 1, "TEK")
+		assert.NoError(t, err)
+		_, err = f.promotion.ApplyToRide(ctx, second.ID, 2, "TEK")
+		assert.NoError(t, err)
+
+		_, err = f.service.FinishRide(ctx, first.ID, 1)
+		assert.NoError(t, err)
+		// Limit dolduğu için ikinci sürüş indirimsiz ücretlendirilir
+		_, err = f.service.FinishRide(ctx, second.ID, 2)
+		assert.NoError(t, err)
+
+		breakdown, _ := f.rides.GetPriceBreakdown(ctx, second.ID)
+		assert.Nil(t, promotionLine(breakdown))
+		assert.Len(t, f.promotions.redemptions, 1)
+	})
+
+	t.Run("Active Ride Shows Discount Without Redeeming", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		createPromotion(t, f, model.Promotion{Code: "HEDIYE", DiscountType: model.DiscountFixed, DiscountValue: 500})
+		ride := startTestRide(t, f, 1, 10, 5*time.Minute)
+		_, err := f.promotion.ApplyToRide(ctx, ride.ID, 1, "HEDIYE")
+		assert.NoError(t, err)
+
+		active, err := f.service.GetActiveRide(ctx, 1)
+		assert.NoError(t, err)
+		line := promotionLine(active.Fare)
+		if assert.NotNil(t, line) {
+			assert.Equal(t, int64(-500), line.Amount)
+		}
+		assert.Empty(t, f.promotions.redemptions)
+	})
+}
+
+func TestReferrals(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+	bikes := []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)}
+
+	t.Run("Code Is Stable", func(t *testing.T) {
+		f := newRideFixture(users, bikes)
+		code, err := f.referral.GetCode(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, code.Code, 8)
+
+		again, err := f.referral.GetCode(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, code.Code, again.Code)
+	})
+
+	t.Run("Invalid And Self Referral", func(t *testing.T) {
+		f := newRideFixture(users, bikes)
+		_, err := f.referral.Resolve(ctx, "YOKBOYLE")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		code, _ := f.referral.GetCode(ctx, 1)
+		assertAppErrorCode(t, f.referral.Link(ctx, code, 1), errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Both Users Credited After First Ride", func(t *testing.T) {
+		f := newRideFixture(users, bikes)
+		code, _ := f.referral.GetCode(ctx, 1)
+		resolved, err := f.referral.Resolve(ctx, code.Code)
+		assert.NoError(t, err)
+		assert.NoError(t, f.referral.Link(ctx, resolved, 2))
+
+		topUp(t, f, 2, 10000)
+		ride := startTestRide(t, f, 2, 10, 5*time.Minute)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 2)
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(testReferrerCredit), walletBalance(t, f, 1))
+		assert.Equal(t, 10000-finished.Cost+testRefereeCredit, walletBalance(t, f, 2))
+		assert.Equal(t, model.ReferralRewarded, f.referrals.referrals[0].Status)
+		assert.Equal(t, &ride.ID, f.referrals.referrals[0].RideID)
+		assert.Equal(t, -int64(testReferrerCredit+testRefereeCredit), f.ledger.systemBalance(model.SystemAccountPromotions))
+		assertLedgerBalanced(t, f.ledger)
+
+		// Sonraki sürüşlerde tekrar kredi verilmez
+		second := startTestRide(t, f, 2, 11, 5*time.Minute)
+		_, err = f.service.FinishRide(ctx, second.ID, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(testReferrerCredit), walletBalance(t, f, 1))
+	})
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReservationService ride fixture'ının repository'lerini paylaşan bir rezervasyon servisi oluşturur
+func newReservationService(f *rideFixture, hold time.Duration, fee int64) *service.ReservationService {
+	return service.NewReservationService(service.ReservationServiceDeps{
+		ReservationRepo: f.reservations,
+		MotorbikeRepo:   f.motorbikes,
+		UserRepo:        f.users,
+		RideRepo:        f.rides,
+		TxManager:       &fakeTxManager{},
+		HoldDuration:    hold,
+		Fee:             fee,
+		Permissions:     f.rbac,
+		Audit:           service.NoopAuditor{},
+	})
+}
+
+func TestReservation(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+
+	t.Run("Holds Motorbike For Owner", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 0)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ReservationActive, reservation.Status)
+		assert.Equal(t, model.BikeReserved, f.motorbikeStatus(t, 10))
+
+		_, err = reservations.Reserve(ctx, 2, 10)
+		assert.Error(t, err)
+		_, err = f.service.StartRide(ctx, 2, 10)
+		assert.Error(t, err)
+
+		// Kullanıcı başına tek aktif rezervasyon
+		_, err = reservations.Reserve(ctx, 1, 11)
+		assert.Error(t, err)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 11))
+	})
+
+	t.Run("Converts To Ride And Charges Fee", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 500)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		converted, _ := f.reservations.GetByID(ctx, reservation.ID)
+		assert.Equal(t, model.ReservationConverted, converted.Status)
+		assert.Equal(t, ride.ID, *converted.RideID)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000+500+10*300), finished.Cost)
+	})
+
+	t.Run("Starting Another Bike Releases Reservation", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 500)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		_, err = f.service.StartRide(ctx, 1, 11)
+		assert.NoError(t, err)
+		released, _ := f.reservations.GetByID(ctx, reservation.ID)
+		assert.Equal(t, model.ReservationCancelled, released.Status)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 0)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		assert.Error(t, reservations.Cancel(ctx, reservation.ID, 2, model.UserRole))
+		assert.NoError(t, reservations.Cancel(ctx, reservation.ID, 1, model.UserRole))
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+		assert.Error(t, reservations.Cancel(ctx, reservation.ID, 1, model.UserRole))
+	})
+
+	t.Run("Worker Expires Due Reservations", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 0)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		count, err := reservations.ExpireDue(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		count, err = reservations.ExpireDue(ctx, time.Now().UTC().Add(11*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		expired, _ := f.reservations.GetByID(ctx, reservation.ID)
+		assert.Equal(t, model.ReservationExpired, expired.Status)
+		assert.Equal(t, model.BikeAvailable, f.motorbikeStatus(t, 10))
+	})
+
+	t.Run("Expired Reservation Does Not Block Others", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		reservations := newReservationService(f, 0, 500)
+
+		reservation, err := reservations.Reserve(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		// Worker henüz çalışmadı, motor hâlâ rezerve görünüyor
+		ride := startTestRide(t, f, 2, 10, time.Minute-time.Second)
+		expired, _ := f.reservations.GetByID(ctx, reservation.ID)
+		assert.Equal(t, model.ReservationExpired, expired.Status)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000+300), finished.Cost)
+	})
+
+	t.Run("Concurrent Reservations Same Motorbike", func(t *testing.T) {
+		const workers = 20
+		many := make([]model.User, workers)
+		for i := range many {
+			many[i] = testUser(int64(i+1), model.StatusActive)
+		}
+		f := newRideFixture(many, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		reservations := newReservationService(f, 10*time.Minute, 0)
+
+		wins := runConcurrently(workers, func(i int) error {
+			_, err := reservations.Reserve(ctx, int64(i+1), 10)
+			return err
+		})
+		assert.Equal(t, 1, wins)
+	})
+}
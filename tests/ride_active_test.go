@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActiveRide(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Returns Elapsed Time Fare And Location", func(t *testing.T) {
+		bike := testMotorbike(10, model.BikeAvailable)
+		bike.LocationLatitude = 41.01
+		bike.LocationLongitude = 28.97
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{bike})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+
+		active, err := f.service.GetActiveRide(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, ride.ID, active.Ride.ID)
+		assert.InDelta(t, (10 * time.Minute).Seconds(), active.Elapsed.Seconds(), 2)
+		assert.Equal(t, 41.01, active.Motorbike.LocationLatitude)
+		assert.Equal(t, int64(1000+10*300), active.Fare.Total)
+	})
+
+	t.Run("Matches Finish Ride Cost", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 25*time.Minute-time.Second)
+
+		active, err := f.service.GetActiveRide(ctx, 1)
+		assert.NoError(t, err)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, finished.Cost, active.Fare.Total)
+	})
+
+	t.Run("No Active Ride", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+
+		_, err := f.service.GetActiveRide(ctx, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestEstimateFare(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Uses Default Tariff", func(t *testing.T) {
+		f := newRideFixture(nil, nil)
+
+		estimate, err := f.service.EstimateFare(ctx, "unknown", 15*time.Minute, &weekdayNoon)
+		assert.NoError(t, err)
+		assert.Equal(t, 15, estimate.BillableMinutes)
+		assert.Equal(t, int64(1000+15*300), estimate.Total)
+	})
+
+	t.Run("Uses Model Tariff", func(t *testing.T) {
+		f := newRideFixture(nil, nil)
+		premium := baseTariff()
+		premium.ID = 2
+		premium.MotorbikeModel = "premium"
+		premium.UnlockFee = 0
+		premium.PerMinuteRate = 500
+		f.tariffs.tariffs = append(f.tariffs.tariffs, premium)
+
+		estimate, err := f.service.EstimateFare(ctx, "premium", 15*time.Minute, &weekdayNoon)
+		assert.NoError(t, err)
+		assert.Equal(t, premium.ID, estimate.TariffID)
+		assert.Equal(t, int64(15*500), estimate.Total)
+	})
+
+	t.Run("No Active Tariff", func(t *testing.T) {
+		f := newRideFixture(nil, nil)
+		f.tariffs.tariffs = nil
+
+		_, err := f.service.EstimateFare(ctx, "test", 15*time.Minute, nil)
+		assert.Error(t, err)
+	})
+}
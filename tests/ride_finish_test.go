@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+// startTestRide motoru kilitli halde, belirtilen süre önce başlamış bir sürüş oluşturur
+func startTestRide(t *testing.T, f *rideFixture, userID, motorbikeID int64, elapsed time.Duration) *model.Ride {
+	ctx := context.Background()
+	ride, err := f.service.StartRide(ctx, userID, motorbikeID)
+	assert.NoError(t, err)
+
+	ride.StartTime = time.Now().UTC().Add(-elapsed)
+	_ = f.rides.Update(ctx, ride)
+
+	motorbike, _ := f.motorbikes.GetByID(ctx, motorbikeID)
+	motorbike.LockStatus = model.Locked
+	_ = f.motorbikes.Update(ctx, motorbike)
+	return ride
+}
+
+func TestDisconnect(t *testing.T) {
+	ctx := context.Background()
+	f := newRideFixture(
+		[]model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)},
+		[]model.Motorbike{testMotorbike(10, model.BikeAvailable)},
+	)
+	// Önceki kullanıcıdan kapatılmamış eski bir bağlantı kalmış
+	stale := &model.BluetoothConnection{UserID: 2, MotorbikeID: 10, ConnectedAt: time.Now().UTC().Add(-24 * time.Hour)}
+	assert.NoError(t, f.bluetooth.Create(ctx, stale))
+	ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+
+	assertAppErrorCode(t, f.service.Disconnect(ctx, 1, 10), errorx.ErrInvalidRequest)
+	_, err := f.service.FinishRide(ctx, ride.ID, 1)
+	assert.NoError(t, err)
+	assertAppErrorCode(t, f.service.Disconnect(ctx, 2, 10), errorx.ErrForbidden)
+
+	// En son açılan bağlantı kullanıcının bağlantısıdır
+	assert.NoError(t, f.service.Disconnect(ctx, 1, 10))
+	if assert.Len(t, f.bluetooth.connections, 2) {
+		assert.Nil(t, f.bluetooth.connections[0].DisconnectedAt)
+		assert.NotNil(t, f.bluetooth.connections[1].DisconnectedAt)
+	}
+	motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+	assert.Equal(t, model.BikeAvailable, motorbike.Status)
+	assert.Equal(t, model.Locked, motorbike.LockStatus)
+}
+
+func TestDisconnectSendsDeviceCommandBeforeClosing(t *testing.T) {
+	ctx := context.Background()
+	locks := &fakeLockController{failing: map[string]bool{"disconnect": true}}
+	f := newRideFixtureWithLocks(
+		[]model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)},
+		[]model.Motorbike{testMotorbike(10, model.BikeAvailable)},
+		locks,
+	)
+	assert.NoError(t, f.bluetooth.Create(ctx, &model.BluetoothConnection{UserID: 1, MotorbikeID: 10, ConnectedAt: time.Now().UTC()}))
+
+	assertAppErrorCode(t, f.service.Disconnect(ctx, 2, 10), errorx.ErrForbidden)
+	assert.Empty(t, locks.Calls(), "yetkisiz istekte cihaza komut gönderilmemeli")
+
+	// Cihaz kilitlenemezse bağlantı açık kalır
+	assert.Error(t, f.service.Disconnect(ctx, 1, 10))
+	assert.Equal(t, []string{"disconnect"}, locks.Calls())
+	if assert.Len(t, f.bluetooth.connections, 1) {
+		assert.Nil(t, f.bluetooth.connections[0].DisconnectedAt)
+	}
+}
+
+func TestFinishRidePricing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Persists Cost And Breakdown", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, finished.EndTime)
+		assert.Equal(t, "TRY", finished.Currency)
+
+		breakdown, err := f.service.GetPriceBreakdown(ctx, ride.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		assert.Equal(t, finished.Cost, breakdown.Total)
+		assert.Equal(t, int64(1000+10*300), breakdown.Total)
+	})
+
+	t.Run("Uses Motorbike Model Override", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		premium := baseTariff()
+		premium.ID = 2
+		premium.MotorbikeModel = "test"
+		premium.PerMinuteRate = 500
+		f.tariffs.tariffs = append(f.tariffs.tariffs, premium)
+
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000+10*500), finished.Cost)
+	})
+
+	t.Run("Rejects Unlocked Motorbike", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride, _ := f.service.StartRide(ctx, 1, 10)
+
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("Other Users Cannot See Breakdown", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, time.Minute)
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		_, err = f.service.GetPriceBreakdown(ctx, ride.ID, 2, model.UserRole)
+		assert.Error(t, err)
+		_, err = f.service.GetPriceBreakdown(ctx, ride.ID, 2, model.AdminRole)
+		assert.NoError(t, err)
+	})
+}
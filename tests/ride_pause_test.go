@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/simulator"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMaxPause = 30 * time.Minute
+
+func lineQuantity(b *model.RidePriceBreakdown, code string) int {
+	quantity := 0
+	for _, line := range b.Lines {
+		if line.Code == code {
+			quantity += line.Quantity
+		}
+	}
+	return quantity
+}
+
+func TestFareCalculatorPause(t *testing.T) {
+	calc := service.NewFareCalculator(time.UTC)
+	tariff := baseTariff()
+	tariff.PausePerMinuteRate = 100
+
+	pauseEnd := weekdayNoon.Add(15 * time.Minute)
+	b := calc.Calculate(tariff, service.FareInput{
+		StartTime: weekdayNoon,
+		EndTime:   weekdayNoon.Add(20 * time.Minute),
+		Pauses:    []model.RidePause{{StartedAt: weekdayNoon.Add(5 * time.Minute), EndedAt: &pauseEnd}},
+	})
+
+	assert.Equal(t, 10, lineQuantity(b, model.PriceLineTime))
+	assert.Equal(t, int64(3000), lineAmount(b, model.PriceLineTime))
+	assert.Equal(t, 10, lineQuantity(b, model.PriceLinePause))
+	assert.Equal(t, int64(1000), lineAmount(b, model.PriceLinePause))
+	assert.Equal(t, int64(1000+3000+1000), b.Total)
+	assert.Equal(t, 20, b.BillableMinutes)
+}
+
+func TestRidePause(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+	bikes := func() []model.Motorbike { return []model.Motorbike{testMotorbike(10, model.BikeAvailable)} }
+
+	t.Run("Pause And Resume", func(t *testing.T) {
+		bike := simulator.NewBike(10, "key", simulator.CircleRoute(simCenter, 500, 36), 20)
+		f := newRideFixtureWithLocks(users, bikes(), simulator.NewFleet(bike))
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		_, err = f.service.PauseRide(ctx, ride.ID, 2)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+
+		paused, err := f.service.PauseRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RidePaused, paused.Status)
+		assert.Equal(t, model.Locked, bike.LockStatus())
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.Locked, motorbike.LockStatus)
+		assert.Equal(t, model.BikeRented, motorbike.Status)
+
+		_, err = f.service.PauseRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		resumed, err := f.service.ResumeRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideActive, resumed.Status)
+		assert.Equal(t, model.Unlocked, bike.LockStatus())
+		motorbike, _ = f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.Unlocked, motorbike.LockStatus)
+
+		_, err = f.service.ResumeRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		pauses, _ := f.rides.ListPauses(ctx, ride.ID)
+		assert.Len(t, pauses, 1)
+		assert.NotNil(t, pauses[0].EndedAt)
+		assert.Equal(t, []model.RideStatus{model.RideReserved, model.RideActive, model.RidePaused, model.RideActive}, rideStatusHistory(t, f, ride.ID))
+	})
+
+	t.Run("Finish While Paused Closes Pause", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+
+		_, err := f.service.PauseRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, finished.Status)
+
+		open, _ := f.rides.GetOpenPause(ctx, ride.ID)
+		assert.Nil(t, open)
+	})
+
+	t.Run("Auto Ends After Max Pause", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		tariff := baseTariff()
+		tariff.PausePerMinuteRate = 100
+		f.tariffs.tariffs = []model.Tariff{tariff}
+
+		ride := startTestRide(t, f, 1, 10, 50*time.Minute)
+		_, err := f.service.PauseRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+
+		count, err := f.service.EndExpiredPauses(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		f.rides.backdatePause(ride.ID, 40*time.Minute)
+		count, err = f.service.EndExpiredPauses(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		ended, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RideCompleted, ended.Status)
+		assert.InDelta(t, int64(40*60), ended.Duration, 1)
+
+		breakdown, err := f.service.GetPriceBreakdown(ctx, ride.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		assert.Equal(t, 30, lineQuantity(breakdown, model.PriceLinePause))
+		assert.Equal(t, int64(3000), lineAmount(breakdown, model.PriceLinePause))
+
+		events, _ := f.service.ListEvents(ctx, ride.ID, 1, model.UserRole)
+		assert.Nil(t, events[len(events)-2].ActorID)
+
+		// Kullanıcı bağlantıyı kesmeyeceği için motor kilitlenip kiralamaya açılır
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeAvailable, motorbike.Status)
+		assert.Equal(t, model.Locked, motorbike.LockStatus)
+		if assert.Len(t, f.bluetooth.connections, 1) {
+			assert.NotNil(t, f.bluetooth.connections[0].DisconnectedAt)
+		}
+	})
+
+	t.Run("Auto End Locks Device Before Transaction", func(t *testing.T) {
+		locks := &fakeLockController{}
+		f := newRideFixtureWithLocks(users, bikes(), locks)
+		ride := startTestRide(t, f, 1, 10, 50*time.Minute)
+		_, err := f.service.PauseRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		f.rides.backdatePause(ride.ID, 40*time.Minute)
+
+		// Park modundaki motorun kilidi zorlanmış
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		motorbike.LockStatus = model.Unlocked
+		_ = f.motorbikes.Update(ctx, motorbike)
+
+		locks.failing = map[string]bool{"lock": true}
+		count, err := f.service.EndExpiredPauses(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		paused, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RidePaused, paused.Status)
+
+		locks.failing = nil
+		count, err = f.service.EndExpiredPauses(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		motorbike, _ = f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeAvailable, motorbike.Status)
+		assert.Equal(t, model.Locked, motorbike.LockStatus)
+		assert.Empty(t, locks.InsideTx())
+	})
+
+	t.Run("Missing Ride Does Not Stop Batch", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)})
+		missing := startTestRide(t, f, 1, 10, 50*time.Minute)
+		ride := startTestRide(t, f, 2, 11, 50*time.Minute)
+		for _, r := range []*model.Ride{missing, ride} {
+			_, err := f.service.PauseRide(ctx, r.ID, r.UserID)
+			assert.NoError(t, err)
+			f.rides.backdatePause(r.ID, 40*time.Minute)
+		}
+		assert.NoError(t, f.rides.Delete(ctx, missing.ID))
+
+		count, err := f.service.EndExpiredPauses(ctx, time.Now().UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		ended, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RideCompleted, ended.Status)
+	})
+
+	t.Run("Resume After Max Pause Ends Ride", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 50*time.Minute)
+		_, err := f.service.PauseRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		f.rides.backdatePause(ride.ID, 40*time.Minute)
+
+		_, err = f.service.ResumeRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+		ended, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Equal(t, model.RideCompleted, ended.Status)
+	})
+}
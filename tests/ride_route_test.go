@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// routePoint başlangıçtan offset kadar sonra, başlangıç noktasının northMeters kuzeyinde bir nokta üretir
+func routePoint(start time.Time, offset time.Duration, northMeters float64) model.RidePoint {
+	// 1 derece enlem yaklaşık 111.195 km
+	return model.RidePoint{
+		RecordedAt: start.Add(offset),
+		Latitude:   41.0 + northMeters/111195,
+		Longitude:  29.0,
+	}
+}
+
+func TestSummarizeRoute(t *testing.T) {
+	start := time.Date(2024, 9, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Distance Speed And Idle", func(t *testing.T) {
+		points := []model.RidePoint{
+			routePoint(start, 0, 0),
+			routePoint(start, time.Minute, 500),    // 30 km/s
+			routePoint(start, 2*time.Minute, 500),  // bekleme
+			routePoint(start, 3*time.Minute, 500),  // bekleme
+			routePoint(start, 4*time.Minute, 1500), // 60 km/s
+		}
+
+		summary := service.SummarizeRoute(points)
+		assert.InDelta(t, 1500, summary.DistanceMeters, 2)
+		assert.Equal(t, int64(120), summary.IdleSeconds)
+		assert.InDelta(t, 60, summary.MaxSpeedKmh, 0.2)
+		assert.InDelta(t, 45, summary.AvgSpeedKmh, 0.2) // 1.5 km / 2 dk hareket
+	})
+
+	t.Run("Unordered Points And GPS Jump", func(t *testing.T) {
+		points := []model.RidePoint{
+			routePoint(start, time.Minute, 500),
+			routePoint(start, 0, 0),
+			routePoint(start, 90*time.Second, 20000), // 30 sn'de 19.5 km, sıçrama
+			routePoint(start, 2*time.Minute, 1000),
+		}
+
+		summary := service.SummarizeRoute(points)
+		assert.InDelta(t, 1000, summary.DistanceMeters, 2)
+		assert.InDelta(t, 30, summary.MaxSpeedKmh, 0.2)
+	})
+
+	t.Run("Reported Speed Counts Towards Max", func(t *testing.T) {
+		fast := 72.5
+		second := routePoint(start, time.Minute, 500)
+		second.SpeedKmh = &fast
+
+		summary := service.SummarizeRoute([]model.RidePoint{routePoint(start, 0, 0), second})
+		assert.Equal(t, 72.5, summary.MaxSpeedKmh)
+	})
+
+	t.Run("Empty Route", func(t *testing.T) {
+		assert.Equal(t, service.RouteSummary{}, service.SummarizeRoute(nil))
+	})
+}
+
+func TestRideRoute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Finish Stores Summary", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+
+		err := f.service.RecordRoute(ctx, ride.ID, 1, []model.RidePoint{
+			routePoint(ride.StartTime, time.Minute, 0),
+			routePoint(ride.StartTime, 2*time.Minute, 800),
+		})
+		assert.NoError(t, err)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.InDelta(t, 800, finished.DistanceMeters, 2)
+		assert.InDelta(t, 48, finished.AvgSpeedKmh, 0.2)
+
+		// Bitmiş sürüşe nokta eklenemez
+		err = f.service.RecordRoute(ctx, ride.ID, 1, []model.RidePoint{routePoint(ride.StartTime, 3*time.Minute, 900)})
+		assert.Error(t, err)
+
+		route, err := f.service.GetRoute(ctx, ride.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		assert.Len(t, route.Points, 2)
+		assert.Equal(t, finished.DistanceMeters, route.Summary.DistanceMeters)
+	})
+
+	t.Run("Rejects Points Outside Ride And Other Users", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+
+		err := f.service.RecordRoute(ctx, ride.ID, 1, []model.RidePoint{routePoint(ride.StartTime, -time.Minute, 0)})
+		assert.Error(t, err)
+
+		err = f.service.RecordRoute(ctx, ride.ID, 1, []model.RidePoint{routePoint(time.Now(), time.Hour, 0)})
+		assert.Error(t, err)
+
+		err = f.service.RecordRoute(ctx, ride.ID, 2, []model.RidePoint{routePoint(ride.StartTime, time.Minute, 0)})
+		assert.Error(t, err)
+
+		_, err = f.service.GetRoute(ctx, ride.ID, 2, model.UserRole)
+		assert.Error(t, err)
+
+		_, err = f.service.GetRoute(ctx, ride.ID, 2, model.AdminRole)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Device Telemetry Feeds Active Ride", func(t *testing.T) {
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+		telemetry := service.NewTelemetryService(&fakeTelemetryRepo{}, f.motorbikes, f.rides, &fakeTxManager{})
+
+		err := telemetry.Ingest(ctx, 10, []model.MotorbikeTelemetry{
+			{RecordedAt: ride.StartTime.Add(-time.Minute), Latitude: 41.0, Longitude: 29.0}, // sürüş öncesi
+			{RecordedAt: ride.StartTime.Add(time.Minute), Latitude: 41.0, Longitude: 29.0, SpeedKmh: 12},
+		})
+		assert.NoError(t, err)
+
+		route, err := f.service.GetRoute(ctx, ride.ID, 1, model.UserRole)
+		assert.NoError(t, err)
+		assert.Len(t, route.Points, 1)
+		assert.Equal(t, model.RoutePointFromDevice, route.Points[0].Source)
+	})
+}
+
+func TestRouteExport(t *testing.T) {
+	start := time.Date(2024, 9, 4, 10, 0, 0, 0, time.UTC)
+	track := []geo.TrackPoint{
+		{Point: geo.Point{Lat: 41.0, Lng: 29.0}, Time: start},
+		{Point: geo.Point{Lat: 41.01, Lng: 29.02}, Time: start.Add(time.Minute)},
+	}
+
+	t.Run("GeoJSON LineString", func(t *testing.T) {
+		body, err := geo.MarshalLineString(track, map[string]any{"ride_id": 7})
+		assert.NoError(t, err)
+
+		var feature struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string      `json:"type"`
+				Coordinates [][]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &feature))
+		assert.Equal(t, "Feature", feature.Type)
+		assert.Equal(t, "LineString", feature.Geometry.Type)
+		assert.Equal(t, []float64{29.02, 41.01}, feature.Geometry.Coordinates[1]) // [boylam, enlem]
+		assert.Equal(t, float64(7), feature.Properties["ride_id"])
+		assert.Len(t, feature.Properties["coordTimes"], 2)
+	})
+
+	t.Run("Single Point Has No Geometry", func(t *testing.T) {
+		body, err := geo.MarshalLineString(track[:1], nil)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"geometry":null`)
+	})
+
+	t.Run("GPX", func(t *testing.T) {
+		body, err := geo.MarshalGPX("Sürüş #7", track)
+		assert.NoError(t, err)
+		gpx := string(body)
+		assert.True(t, strings.HasPrefix(gpx, "<?xml"))
+		assert.Contains(t, gpx, `<trkpt lat="41.01" lon="29.02">`)
+		assert.Contains(t, gpx, "<time>2024-09-04T10:01:00Z</time>")
+	})
+}
@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+type rideFixture struct {
+	fleetFixture
+	service      *service.RideService
+	bluetooth    *fakeBluetoothRepo
+	tariffs      *fakeTariffRepo
+	reservations *fakeReservationRepo
+	zones        *fakeZoneRepo
+	ledger       *fakeLedgerRepo
+	wallet       *service.WalletService
+	payments     *fakeRidePaymentRepo
+	provider     *payment.FakeProvider
+	payment      *service.PaymentService
+	promotions   *fakePromotionRepo
+	referrals    *fakeReferralRepo
+	promotion    *service.PromotionService
+	referral     *service.ReferralService
+	passRepo     *fakePassRepo
+	passes       *service.PassService
+	invoiceRepo  *fakeInvoiceRepo
+	mailer       *fakeMailer
+	invoices     *service.InvoiceService
+}
+
+func newRideFixture(users []model.User, motorbikes []model.Motorbike) *rideFixture {
+	return newRideFixtureWithLocks(users, motorbikes, service.NoopLockController{})
+}
+
+// newRideFixtureWithLocks yalnızca RideService'in ihtiyaç duyduğu servisleri kurar; denetim kayıtları tutulmaz
+func newRideFixtureWithLocks(users []model.User, motorbikes []model.Motorbike, locks service.LockController) *rideFixture {
+	f := &rideFixture{
+		fleetFixture: newFleetFixture(users, motorbikes),
+		bluetooth:    &fakeBluetoothRepo{},
+		tariffs:      &fakeTariffRepo{tariffs: []model.Tariff{baseTariff()}},
+		reservations: newFakeReservationRepo(),
+		zones:        &fakeZoneRepo{},
+		ledger:       &fakeLedgerRepo{},
+		payments:     &fakeRidePaymentRepo{},
+		provider:     payment.NewFakeProvider(testWebhookSecret),
+		promotions:   &fakePromotionRepo{},
+		referrals:    &fakeReferralRepo{},
+		passRepo:     &fakePassRepo{},
+		invoiceRepo:  &fakeInvoiceRepo{},
+		mailer:       &fakeMailer{},
+	}
+	f.wallet = service.NewWalletService(f.ledger, f.users, &fakeTxManager{}, service.NoopAuditor{})
+	f.payment = service.NewPaymentService(f.provider, f.payments, f.rides, f.wallet, &fakeTxManager{}, testHoldAmount, time.Second)
+	f.promotion = service.NewPromotionService(f.promotions, f.rides, f.motorbikes, service.NoopAuditor{})
+	f.referral = service.NewReferralService(f.referrals, f.wallet, testReferrerCredit, testRefereeCredit)
+	f.passes = service.NewPassService(f.passRepo, f.wallet, f.payment, &fakeTxManager{}, time.UTC, service.NoopAuditor{})
+	f.invoices = service.NewInvoiceService(service.InvoiceServiceDeps{
+		InvoiceRepo: f.invoiceRepo,
+		RideRepo:    f.rides,
+		UserRepo:    f.users,
+		Mailer:      f.mailer,
+		Company:     service.CompanyInfo{Name: "Test Kiralama A.Ş.", TaxNumber: "1234567890"},
+		VATRatePct:  testVATRatePct,
+		Location:    time.UTC,
+		Permissions: f.rbac,
+	})
+	f.service = service.NewRideService(service.RideServiceDeps{
+		RideRepo:        f.rides,
+		MotorbikeRepo:   f.motorbikes,
+		UserRepo:        f.users,
+		ConnectionRepo:  f.bluetooth,
+		TariffRepo:      f.tariffs,
+		ReservationRepo: f.reservations,
+		ZoneRepo:        f.zones,
+		TxManager:       &fakeTxManager{},
+		FareCalculator:  service.NewFareCalculator(time.UTC),
+		Locks:           locks,
+		Wallet:          f.wallet,
+		Payments:        f.payment,
+		Promotions:      f.promotion,
+		Referrals:       f.referral,
+		Passes:          f.passes,
+		Invoices:        f.invoices,
+		Audit:           service.NoopAuditor{},
+		Permissions:     f.rbac,
+		MaxPause:        testMaxPause,
+	})
+	return f
+}
+
+func testUser(id int64, status model.Status) model.User {
+	return model.User{BaseModel: model.BaseModel{ID: id}, Status: status, Role: model.UserRole}
+}
+
+func testMotorbike(id int64, status model.MotorBikeStatus) model.Motorbike {
+	return model.Motorbike{BaseModel: model.BaseModel{ID: id}, Model: "test", Status: status, LockStatus: model.Locked}
+}
+
+func TestStartRide(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reserves Motorbike And Opens Connection", func(t *testing.T) {
+		f := newRideFixture(
+			[]model.User{testUser(1, model.StatusActive)},
+			[]model.Motorbike{testMotorbike(10, model.BikeAvailable)},
+		)
+
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.NotNil(t, ride)
+		assert.Equal(t, int64(1), ride.UserID)
+		assert.Nil(t, ride.EndTime)
+
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeRented, motorbike.Status)
+		assert.Equal(t, model.Unlocked, motorbike.LockStatus)
+		assert.Len(t, f.bluetooth.connections, 1)
+	})
+
+	t.Run("Rejects Unavailable Motorbike", func(t *testing.T) {
+		f := newRideFixture(
+			[]model.User{testUser(1, model.StatusActive)},
+			[]model.Motorbike{testMotorbike(10, model.BikeInMaintenance)},
+		)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Equal(t, 0, f.rides.countActive())
+	})
+
+	t.Run("Connects To Device Only After Checks", func(t *testing.T) {
+		locks := &fakeLockController{}
+		f := newRideFixtureWithLocks(
+			[]model.User{testUser(1, model.StatusActive)},
+			[]model.Motorbike{testMotorbike(10, model.BikeInMaintenance), testMotorbike(11, model.BikeAvailable)},
+			locks,
+		)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Empty(t, locks.Calls(), "müsait olmayan motora bağlanılmamalı")
+
+		_, err = f.service.StartRide(ctx, 1, 11)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"connect", "unlock"}, locks.Calls())
+	})
+
+	t.Run("Failed Unlock Disconnects Device", func(t *testing.T) {
+		locks := &fakeLockController{failing: map[string]bool{"unlock": true}}
+		f := newRideFixtureWithLocks([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, locks)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Equal(t, []string{"connect", "unlock", "disconnect"}, locks.Calls())
+		assert.Equal(t, 0, f.rides.countActive())
+	})
+
+	t.Run("Rejects Inactive User", func(t *testing.T) {
+		f := newRideFixture(
+			[]model.User{testUser(1, model.StatusBanned)},
+			[]model.Motorbike{testMotorbike(10, model.BikeAvailable)},
+		)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Equal(t, 0, f.rides.countActive())
+	})
+
+	t.Run("Rejects Second Open Ride", func(t *testing.T) {
+		f := newRideFixture(
+			[]model.User{testUser(1, model.StatusActive)},
+			[]model.Motorbike{testMotorbike(10, model.BikeAvailable), testMotorbike(11, model.BikeAvailable)},
+		)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+		_, err = f.service.StartRide(ctx, 1, 11)
+		assert.Error(t, err)
+
+		motorbike, _ := f.motorbikes.GetByID(ctx, 11)
+		assert.Equal(t, model.BikeAvailable, motorbike.Status)
+	})
+}
+
+// TestStartRideUnderFakeLocks aynı anda gelen başlatma isteklerini fakeTxManager'ın sıraladığı transaction'larla çalıştırır.
+// Veritabanındaki satır kilitlerini değil servisin kilit altındaki kontrollerini doğrular; kilitlerin kaçırdığı
+// çakışmayı unique indeksin yakalaması "Active Ride Index Conflict" ile test edilir.
+func TestStartRideUnderFakeLocks(t *testing.T) {
+	ctx := context.Background()
+	const workers = 20
+
+	t.Run("Same Motorbike Different Users", func(t *testing.T) {
+		users := make([]model.User, workers)
+		for i := range users {
+			users[i] = testUser(int64(i+1), model.StatusActive)
+		}
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+
+		wins := runConcurrently(workers, func(i int) error {
+			_, err := f.service.StartRide(ctx, int64(i+1), 10)
+			return err
+		})
+
+		assert.Equal(t, 1, wins)
+		assert.Equal(t, 1, f.rides.countActive())
+		assert.Len(t, f.bluetooth.connections, 1)
+	})
+
+	t.Run("Same User Different Motorbikes", func(t *testing.T) {
+		motorbikes := make([]model.Motorbike, workers)
+		for i := range motorbikes {
+			motorbikes[i] = testMotorbike(int64(i+1), model.BikeAvailable)
+		}
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, motorbikes)
+
+		wins := runConcurrently(workers, func(i int) error {
+			_, err := f.service.StartRide(ctx, 1, int64(i+1))
+			return err
+		})
+
+		assert.Equal(t, 1, wins)
+		assert.Equal(t, 1, f.rides.countActive())
+
+		rented := 0
+		for i := range motorbikes {
+			m, _ := f.motorbikes.GetByID(ctx, int64(i+1))
+			if m.Status == model.BikeRented {
+				rented++
+			}
+		}
+		assert.Equal(t, 1, rented)
+	})
+
+	t.Run("Active Ride Index Conflict", func(t *testing.T) {
+		f := newRideFixture(
+			[]model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)},
+			[]model.Motorbike{testMotorbike(10, model.BikeAvailable)},
+		)
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		// Motor satırı müsait okunsa bile ikinci sürüşü unique indeks reddeder; 500 değil 409 dönmelidir
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		motorbike.Status = model.BikeAvailable
+		_ = f.motorbikes.Update(ctx, motorbike)
+
+		_, err = f.service.StartRide(ctx, 2, 10)
+		assertAppErrorCode(t, err, errorx.ErrDuplicate)
+		assert.Equal(t, 1, f.rides.countActive())
+		assert.Len(t, f.bluetooth.connections, 1)
+	})
+}
+
+// runConcurrently fn'i n goroutine'de aynı anda çalıştırır ve başarılı çağrı sayısını döner
+func runConcurrently(n int, fn func(i int) error) int {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		wins  int
+		start = make(chan struct{})
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			if fn(i) == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+	return wins
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func rideStatusHistory(t *testing.T, f *rideFixture, rideID int64) []model.RideStatus {
+	events, err := f.service.ListEvents(context.Background(), rideID, testAdminID, model.AdminRole)
+	assert.NoError(t, err)
+	statuses := make([]model.RideStatus, len(events))
+	for i, event := range events {
+		statuses[i] = event.ToStatus
+	}
+	return statuses
+}
+
+func TestRideStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to model.RideStatus
+		allowed  bool
+	}{
+		{model.RideReserved, model.RideActive, true},
+		{model.RideReserved, model.RideCancelled, true},
+		{model.RideReserved, model.RideEnding, false},
+		{model.RideActive, model.RidePaused, true},
+		{model.RideActive, model.RideEnding, true},
+		{model.RideActive, model.RideCompleted, false},
+		{model.RidePaused, model.RideActive, true},
+		{model.RideEnding, model.RideCompleted, true},
+		{model.RideEnding, model.RidePaymentFailed, true},
+		{model.RideCompleted, model.RidePaymentFailed, true},
+		{model.RideCompleted, model.RideActive, false},
+		{model.RidePaymentFailed, model.RideCompleted, true},
+		{model.RideCancelled, model.RideActive, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestRideLifecycle(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+	bikes := func() []model.Motorbike { return []model.Motorbike{testMotorbike(10, model.BikeAvailable)} }
+
+	t.Run("Records Every Transition", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+		assert.Equal(t, model.RideActive, ride.Status)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, finished.Status)
+		assert.InDelta(t, int64(600), finished.Duration, 1)
+		assert.Equal(t, []model.RideStatus{model.RideReserved, model.RideActive, model.RideEnding, model.RideCompleted}, rideStatusHistory(t, f, ride.ID))
+
+		events, _ := f.service.ListEvents(ctx, ride.ID, 1, model.UserRole)
+		assert.Empty(t, events[0].FromStatus)
+		assert.Equal(t, model.RideEnding, events[3].FromStatus)
+		assert.Equal(t, int64(1), *events[2].ActorID)
+		assert.Nil(t, events[3].ActorID)
+
+		_, err = f.service.ListEvents(ctx, ride.ID, 2, model.UserRole)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+	})
+
+	t.Run("Rejects Illegal Moves", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 5*time.Minute)
+
+		assertAppErrorCode(t, f.service.Delete(ctx, ride.ID), errorx.ErrInvalidRequest)
+
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		_, err = f.service.FinishRide(ctx, ride.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Len(t, rideStatusHistory(t, f, ride.ID), 4)
+
+		assert.NoError(t, f.service.Delete(ctx, ride.ID))
+	})
+
+	t.Run("Failed Hold Cancels Ride", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		f.provider.Next(payment.OpAuthorize, payment.Decline)
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+
+		cancelled, err := f.rides.GetByID(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCancelled, cancelled.Status)
+		assert.Equal(t, []model.RideStatus{model.RideReserved, model.RideCancelled}, rideStatusHistory(t, f, cancelled.ID))
+
+		// İptal edilen sürüş motoru ve kullanıcıyı bağlamaz
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideActive, ride.Status)
+	})
+
+	t.Run("Payment Failure And Retry", func(t *testing.T) {
+		f := newRideFixture(users, bikes())
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute)
+		f.provider.Next(payment.OpCapture, payment.Decline)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RidePaymentFailed, finished.Status)
+
+		paid, err := f.payment.RetryRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.RideCompleted, paid.Status)
+		assert.Equal(t, []model.RideStatus{
+			model.RideReserved, model.RideActive, model.RideEnding, model.RidePaymentFailed, model.RideCompleted,
+		}, rideStatusHistory(t, f, ride.ID))
+	})
+}
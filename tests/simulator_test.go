@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/simulator"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+var simCenter = geo.Point{Lat: 41.0082, Lng: 28.9784}
+
+func TestSimulatorRoute(t *testing.T) {
+	t.Run("Circle Length And Wrap Around", func(t *testing.T) {
+		route := simulator.CircleRoute(simCenter, 500, 72)
+		// 72 köşeli çokgenin çevresi daireninkine çok yakındır
+		assert.InDelta(t, 2*math.Pi*500, route.Length(), 5)
+
+		start := route.PointAt(0)
+		assert.InDelta(t, 0, geo.HaversineMeters(start, route.PointAt(route.Length())), 0.01)
+		assert.InDelta(t, 0, geo.HaversineMeters(route.PointAt(100), route.PointAt(route.Length()+100)), 0.01)
+	})
+
+	t.Run("Interpolates Between Vertices", func(t *testing.T) {
+		north := geo.Point{Lat: simCenter.Lat + 1000/111195.0, Lng: simCenter.Lng}
+		route := simulator.Route{simCenter, north}
+		mid := route.PointAt(250)
+		assert.InDelta(t, 250, geo.HaversineMeters(simCenter, mid), 1)
+	})
+
+	t.Run("Loads GeoJSON LineString", func(t *testing.T) {
+		route, err := simulator.LoadRoute([]byte(`{"type":"Feature","geometry":{"type":"LineString","coordinates":[[28.97,41.00],[28.98,41.01]]}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, simulator.Route{{Lat: 41.00, Lng: 28.97}, {Lat: 41.01, Lng: 28.98}}, route)
+
+		_, err = simulator.LoadRoute([]byte(`{"type":"LineString","coordinates":[[28.97,41.00]]}`))
+		assert.ErrorIs(t, err, simulator.ErrInvalidRoute)
+	})
+}
+
+func TestSimulatedBike(t *testing.T) {
+	route := simulator.CircleRoute(simCenter, 500, 36)
+
+	t.Run("Moves Only While Unlocked", func(t *testing.T) {
+		bike := simulator.NewBike(1, "key", route, 36)
+		start := bike.Sample(time.Now())
+
+		bike.Step(10 * time.Second)
+		assert.Equal(t, *start.Latitude, *bike.Sample(time.Now()).Latitude)
+
+		assert.NoError(t, bike.Unlock())
+		bike.Step(10 * time.Second)
+		sample := bike.Sample(time.Now())
+		assert.Equal(t, int64(100), *sample.OdometerMeters)
+		assert.Equal(t, 36.0, sample.SpeedKmh)
+		assert.Equal(t, "unlocked", sample.LockStatus)
+	})
+
+	t.Run("Faults", func(t *testing.T) {
+		bike := simulator.NewBike(1, "key", route, 20)
+
+		bike.SetFault(simulator.FaultLowBattery)
+		assert.LessOrEqual(t, *bike.Sample(time.Now()).BatteryLevel, 10)
+
+		bike.SetFault(simulator.FaultLockJam)
+		assert.ErrorIs(t, bike.Unlock(), simulator.ErrLockJammed)
+
+		bike.SetFault(simulator.FaultLostConnection)
+		assert.False(t, bike.Online())
+		assert.ErrorIs(t, bike.Connect(), simulator.ErrOffline)
+
+		bike.ClearFault("")
+		assert.True(t, bike.Online())
+		assert.Empty(t, bike.Faults())
+
+		// Kilidi zorlanan motor kilitliyken hareket eder ve kilitsiz görünür
+		bike.SetFault(simulator.FaultTamper)
+		bike.Step(time.Minute)
+		sample := bike.Sample(time.Now())
+		assert.Equal(t, "unlocked", sample.LockStatus)
+		assert.Greater(t, *sample.OdometerMeters, int64(0))
+	})
+
+	t.Run("Executes Device Commands", func(t *testing.T) {
+		bike := simulator.NewBike(1, "key", route, 20)
+		assert.NoError(t, bike.Execute(model.CommandUnlock))
+		assert.Equal(t, model.Unlocked, bike.LockStatus())
+
+		assert.NoError(t, bike.Execute(model.CommandDisable))
+		assert.Equal(t, model.Locked, bike.LockStatus())
+		assert.True(t, bike.Disabled())
+
+		assert.Error(t, bike.Execute("explode"))
+	})
+}
+
+func TestFleetAsLockController(t *testing.T) {
+	ctx := context.Background()
+	route := simulator.CircleRoute(simCenter, 500, 36)
+	users := []model.User{testUser(1, model.StatusActive)}
+
+	t.Run("Unlocks On Start And Locks On Finish", func(t *testing.T) {
+		bike := simulator.NewBike(10, "key", route, 20)
+		f := newRideFixtureWithLocks(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, simulator.NewFleet(bike))
+
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Unlocked, bike.LockStatus())
+
+		_, err = f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Locked, bike.LockStatus())
+
+		m, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.Locked, m.LockStatus)
+	})
+
+	t.Run("Offline Bike Cannot Be Rented", func(t *testing.T) {
+		bike := simulator.NewBike(10, "key", route, 20)
+		bike.SetFault(simulator.FaultLostConnection)
+		f := newRideFixtureWithLocks(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, simulator.NewFleet(bike))
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Equal(t, 0, f.rides.countActive())
+
+		m, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeAvailable, m.Status)
+		if assert.Len(t, f.bluetooth.connections, 1) {
+			assert.NotNil(t, f.bluetooth.connections[0].DisconnectedAt)
+		}
+	})
+
+	t.Run("Jammed Lock Aborts Started Ride", func(t *testing.T) {
+		bike := simulator.NewBike(10, "key", route, 20)
+		bike.SetFault(simulator.FaultLockJam)
+		f := newRideFixtureWithLocks(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, simulator.NewFleet(bike))
+
+		_, err := f.service.StartRide(ctx, 1, 10)
+		assert.Error(t, err)
+		assert.Equal(t, 0, f.rides.countActive())
+
+		m, _ := f.motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.BikeAvailable, m.Status)
+		assert.Equal(t, model.Locked, m.LockStatus)
+		assert.NotNil(t, f.bluetooth.connections[0].DisconnectedAt)
+	})
+
+	t.Run("Jammed Lock Blocks Finish", func(t *testing.T) {
+		bike := simulator.NewBike(10, "key", route, 20)
+		f := newRideFixtureWithLocks(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)}, simulator.NewFleet(bike))
+
+		ride, err := f.service.StartRide(ctx, 1, 10)
+		assert.NoError(t, err)
+
+		bike.SetFault(simulator.FaultLockJam)
+		_, err = f.service.FinishRide(ctx, ride.ID, 1)
+		assert.Error(t, err)
+		assert.Equal(t, 1, f.rides.countActive())
+
+		bike.ClearFault(simulator.FaultLockJam)
+		_, err = f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeviceLockController(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	newController := func(lastSeen *time.Time) (*service.DeviceLockController, *service.DeviceCommandService, *fakeDeviceCommandRepo, *fakeMotorbikeRepo) {
+		bike := testMotorbike(10, model.BikeAvailable)
+		bike.LastSeenAt = lastSeen
+		motorbikes := newFakeMotorbikeRepo(bike)
+		commands := newFakeDeviceCommandRepo()
+		svc := newCommandService(commands, motorbikes, time.Minute, 3)
+		return service.NewDeviceLockController(svc, motorbikes, 300*time.Millisecond, time.Minute), svc, commands, motorbikes
+	}
+
+	t.Run("Connect Requires Recent Telemetry", func(t *testing.T) {
+		stale := now.Add(-time.Hour)
+		controller, _, _, _ := newController(&stale)
+		assert.Error(t, controller.Connect(ctx, 10))
+
+		controller, _, _, _ = newController(&now)
+		assert.NoError(t, controller.Connect(ctx, 10))
+	})
+
+	t.Run("Unlock Waits For Acknowledgement", func(t *testing.T) {
+		controller, svc, _, motorbikes := newController(&now)
+		startFakeDevice(t, svc, 10, func(cmd model.DeviceCommand) (bool, bool, string) { return true, true, "" })
+
+		assert.NoError(t, controller.Unlock(ctx, 10))
+		m, _ := motorbikes.GetByID(ctx, 10)
+		assert.Equal(t, model.Unlocked, m.LockStatus)
+	})
+
+	t.Run("Device Failure Is Returned", func(t *testing.T) {
+		controller, svc, _, _ := newController(&now)
+		startFakeDevice(t, svc, 10, func(cmd model.DeviceCommand) (bool, bool, string) { return true, false, "kilit takıldı" })
+
+		assert.Error(t, controller.Lock(ctx, 10))
+	})
+
+	t.Run("Silent Device Times Out", func(t *testing.T) {
+		controller, svc, commands, _ := newController(&now)
+		startFakeDevice(t, svc, 10, func(cmd model.DeviceCommand) (bool, bool, string) { return false, false, "" })
+
+		assert.Error(t, controller.Unlock(ctx, 10))
+		assert.Equal(t, model.CommandFailed, commandStatus(commands, 1))
+	})
+}
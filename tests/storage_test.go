@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// testJPEG width x height boyutunda, EXIF bölümünde verilen yön değeri ve bir konum işareti bulunan JPEG üretir
+func testJPEG(t *testing.T, width, height, orientation int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	// TIFF: little endian başlık, tek girdili IFD0 (0x0112 yön) ve ardından silinmesi gereken bir işaret
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, byte(orientation), 0, 0, 0, 0, 0, 0, 0}
+	tiff = append(tiff, []byte("GPS-SECRET")...)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := encoded.Bytes()
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+func TestProcessImage(t *testing.T) {
+	t.Run("Strips EXIF And Applies Orientation", func(t *testing.T) {
+		data := testJPEG(t, 800, 400, 6)
+		assert.Contains(t, string(data), "GPS-SECRET")
+
+		img, err := storage.ProcessImage(data, 1<<20, 320)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/jpeg", img.ContentType)
+		assert.Equal(t, ".jpg", img.Ext)
+		assert.NotContains(t, string(img.Data), "GPS-SECRET")
+		assert.NotContains(t, string(img.Data), "Exif")
+		// 90 derece döndürülmüş
+		assert.Equal(t, 400, img.Width)
+		assert.Equal(t, 800, img.Height)
+
+		thumb, err := jpeg.DecodeConfig(bytes.NewReader(img.Thumbnail))
+		assert.NoError(t, err)
+		assert.Equal(t, 160, thumb.Width)
+		assert.Equal(t, 320, thumb.Height)
+	})
+
+	t.Run("Rejects Invalid Files", func(t *testing.T) {
+		_, err := storage.ProcessImage([]byte("<html>not an image</html>"), 1<<20, 320)
+		assert.ErrorIs(t, err, storage.ErrInvalidImage)
+
+		_, err = storage.ProcessImage(testJPEG(t, 800, 400, 1), 100, 320)
+		assert.ErrorIs(t, err, storage.ErrTooLarge)
+
+		// İmzası JPEG olan ama çözülemeyen dosya
+		_, err = storage.ProcessImage([]byte("\xFF\xD8\xFF\xE0garbage"), 1<<20, 320)
+		assert.ErrorIs(t, err, storage.ErrInvalidImage)
+	})
+}
+
+func testBlobStore(t *testing.T, store storage.BlobStore) {
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "rides/1/a b.jpg", strings.NewReader("photo"), 5, "image/jpeg"))
+	body, object, err := store.Get(ctx, "rides/1/a b.jpg")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "photo", string(content))
+	assert.Equal(t, "image/jpeg", object.ContentType)
+	assert.Equal(t, int64(5), object.Size)
+
+	assert.NoError(t, store.Delete(ctx, "rides/1/a b.jpg"))
+	assert.NoError(t, store.Delete(ctx, "rides/1/a b.jpg"))
+	_, _, err = store.Get(ctx, "rides/1/a b.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	for _, key := range []string{"", "../secret", "rides/../../secret", "/etc/passwd"} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), storage.ErrInvalidKey, key)
+	}
+}
+
+// fakeS3 yalnızca PUT/GET/DELETE destekleyen, imza başlığını kontrol eden S3 taklidi
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestBlobStores(t *testing.T) {
+	t.Run("Local", func(t *testing.T) {
+		testBlobStore(t, storage.NewLocalStore(t.TempDir()))
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		backend := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+		server := httptest.NewServer(backend)
+		defer server.Close()
+
+		store := storage.NewS3Store(storage.S3Config{Endpoint: server.URL, Bucket: "bucket", AccessKey: "minio", SecretKey: "secret"}, server.Client())
+		testBlobStore(t, store)
+
+		assert.NoError(t, store.Put(context.Background(), "rides/2/x.jpg", strings.NewReader("x"), 1, "image/jpeg"))
+		assert.Contains(t, backend.objects, "/bucket/rides/2/x.jpg")
+
+		denied := storage.NewS3Store(storage.S3Config{Endpoint: server.URL, Bucket: "bucket", AccessKey: "other"}, server.Client())
+		assert.Error(t, denied.Put(context.Background(), "x", strings.NewReader("x"), 1, "text/plain"))
+	})
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := storage.NewURLSigner("secret", "/api/v1/files", time.Minute)
+	now := time.Now()
+
+	link, expiresAt := signer.Sign("rides/1/a.jpg", now)
+	assert.True(t, strings.HasPrefix(link, "/api/v1/files/rides/1/a.jpg?"))
+	assert.WithinDuration(t, now.Add(time.Minute), expiresAt, time.Second)
+
+	parsed, _ := url.Parse(link)
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+	assert.NoError(t, signer.Verify("rides/1/a.jpg", expires, signature, now))
+	assert.ErrorIs(t, signer.Verify("rides/2/a.jpg", expires, signature, now), storage.ErrInvalidURL)
+	assert.ErrorIs(t, signer.Verify("rides/1/a.jpg", expires+"0", signature, now), storage.ErrInvalidURL)
+	assert.ErrorIs(t, signer.Verify("rides/1/a.jpg", expires, signature, now.Add(2*time.Minute)), storage.ErrExpiredURL)
+	assert.ErrorIs(t, storage.NewURLSigner("other", "/api/v1/files", time.Minute).Verify("rides/1/a.jpg", expires, signature, now), storage.ErrInvalidURL)
+}
+
+func TestRidePhotoUpload(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive), testUser(2, model.StatusActive)}
+	f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+	ride := startTestRide(t, f, 1, 10, 5*time.Minute)
+
+	store := storage.NewLocalStore(t.TempDir())
+	photos := service.NewRidePhotoService(f.rides, store, 1<<20, f.rbac)
+	files := service.NewFileService(store, storage.NewURLSigner("secret", "/api/v1/files", time.Minute))
+
+	_, err := photos.Upload(ctx, ride.ID, 2, testJPEG(t, 64, 32, 1))
+	assertAppErrorCode(t, err, errorx.ErrForbidden)
+	_, err = photos.Upload(ctx, ride.ID, 1, []byte("not an image"))
+	assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+
+	photo, err := photos.Upload(ctx, ride.ID, 1, testJPEG(t, 64, 32, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 64, photo.Width)
+	assert.True(t, strings.HasPrefix(photo.Key, "rides/1/"))
+
+	listed, err := photos.List(ctx, ride.ID, 2, model.UserRole)
+	assertAppErrorCode(t, err, errorx.ErrForbidden)
+	listed, err = photos.List(ctx, ride.ID, testAdminID, model.AdminRole)
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+
+	link, _ := files.SignedURL(photo.ThumbnailKey)
+	parsed, _ := url.Parse(link)
+	body, object, err := files.Open(ctx, photo.ThumbnailKey, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	assert.NoError(t, err)
+	body.Close()
+	assert.Equal(t, "image/jpeg", object.ContentType)
+
+	_, _, err = files.Open(ctx, photo.Key, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	assertAppErrorCode(t, err, errorx.ErrForbidden)
+}
@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTariffCurrencyMustMatchWallets(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeTariffRepo{}
+	svc := service.NewTariffService(repo, service.NoopAuditor{})
+
+	// Sürüş ücreti cüzdandan düşüldüğü için başka para birimindeki tarife sürüşü hiç bitiremezdi
+	tariff := baseTariff()
+	tariff.ID = 0
+	tariff.Currency = "EUR"
+	assertAppErrorCode(t, svc.Create(ctx, &tariff), errorx.ErrInvalidRequest)
+	assert.Empty(t, repo.tariffs)
+
+	tariff.Currency = "TRY"
+	assert.NoError(t, svc.Create(ctx, &tariff))
+
+	tariff.Currency = "EUR"
+	assertAppErrorCode(t, svc.Update(ctx, tariff), errorx.ErrInvalidRequest)
+	stored, _ := repo.GetByID(ctx, tariff.ID)
+	assert.Equal(t, "TRY", stored.Currency)
+}
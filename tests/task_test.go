@@ -0,0 +1,351 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/errorx"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOperatorID      = 50
+	testOtherOperatorID = 51
+)
+
+type taskFixture struct {
+	fleetFixture
+	service *service.TaskService
+	tasks   *fakeTaskRepo
+	dir     string
+}
+
+func newTaskFixture(t *testing.T, motorbikes ...model.Motorbike) *taskFixture {
+	users := []model.User{
+		testStaff(testAdminID, model.AdminRole),
+		testStaff(testOperatorID, model.OperatorRole),
+		testStaff(testOtherOperatorID, model.OperatorRole),
+		testUser(1, model.StatusActive),
+	}
+	f := &taskFixture{
+		fleetFixture: newFleetFixture(users, motorbikes),
+		tasks:        &fakeTaskRepo{},
+		dir:          t.TempDir(),
+	}
+	// Hedef alan: 41.00-41.01 enlem, 29.00-29.01 boylam; pasif alan: 2
+	target := boxZone(1, model.ZonePreferredParking, model.ZoneReject, 0, 29.0, 41.0, 29.01, 41.01)
+	inactive := boxZone(2, model.ZonePreferredParking, model.ZoneReject, 0, 29.0, 41.0, 29.01, 41.01)
+	inactive.IsActive = false
+
+	f.service = service.NewTaskService(service.TaskServiceDeps{
+		TaskRepo:      f.tasks,
+		MotorbikeRepo: f.motorbikes,
+		RideRepo:      f.rides,
+		UserRepo:      f.users,
+		ZoneRepo:      &fakeZoneRepo{zones: []model.Zone{target, inactive}},
+		TxManager:     &fakeTxManager{},
+		Permissions:   f.rbac,
+		Audit:         service.NoopAuditor{},
+		Store:         storage.NewLocalStore(f.dir),
+		MaxPhotos:     2,
+		MaxPhotoBytes: 1 << 20,
+		SLAs: map[model.TaskType]time.Duration{
+			model.TaskRebalance:   4 * time.Hour,
+			model.TaskBatterySwap: 2 * time.Hour,
+			model.TaskCollect:     time.Hour,
+			model.TaskInspect:     8 * time.Hour,
+		},
+		LowBatteryPercent: 20,
+		IdleAfter:         72 * time.Hour,
+	})
+	return f
+}
+
+// taskMotorbike verilen konumda müsait bir motor döner
+func taskMotorbike(id int64, lat, lng float64) model.Motorbike {
+	m := testMotorbike(id, model.BikeAvailable)
+	m.LocationLatitude = lat
+	m.LocationLongitude = lng
+	m.CreatedAt = time.Now().Add(-24 * 365 * time.Hour)
+	return m
+}
+
+func (f *taskFixture) create(t *testing.T, taskType model.TaskType, motorbikeID int64) *model.Task {
+	adminID := int64(testAdminID)
+	task, err := f.service.Create(context.Background(), service.CreateTaskInput{Type: taskType, MotorbikeID: motorbikeID, CreatedBy: &adminID})
+	assert.NoError(t, err)
+	return task
+}
+
+func (f *taskFixture) complete(taskID, operatorID int64, photos ...[]byte) (*model.Task, error) {
+	return f.service.Complete(context.Background(), service.CompleteTaskInput{ID: taskID, OperatorID: operatorID, Note: "Tamam", Photos: photos})
+}
+
+func (f *taskFixture) moveMotorbike(t *testing.T, id int64, lat, lng float64) {
+	motorbike, err := f.motorbikes.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	motorbike.LocationLatitude = lat
+	motorbike.LocationLongitude = lng
+	assert.NoError(t, f.motorbikes.Update(context.Background(), motorbike))
+}
+
+func TestTaskLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create Uses Motorbike Location And SLA", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+
+		task := f.create(t, model.TaskBatterySwap, 10)
+		assert.Equal(t, model.TaskOpen, task.Status)
+		assert.Equal(t, model.TaskSourceManual, task.Source)
+		assert.Equal(t, 41.02, task.Latitude)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), task.DueAt, time.Minute)
+		assert.False(t, task.IsOverdue(time.Now()))
+		assert.True(t, task.IsOverdue(time.Now().Add(3*time.Hour)))
+
+		_, err := f.service.Create(ctx, service.CreateTaskInput{Type: model.TaskBatterySwap, MotorbikeID: 10})
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		f.create(t, model.TaskInspect, 10)
+	})
+
+	t.Run("Create Validates Target And Assignee", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+		zoneID, inactiveID, missingID := int64(1), int64(2), int64(9)
+		userID, operatorID := int64(1), int64(testOperatorID)
+		past := time.Now().Add(-time.Minute)
+
+		for _, input := range []service.CreateTaskInput{
+			{Type: "wash", MotorbikeID: 10},
+			{Type: model.TaskCollect, MotorbikeID: 10, TargetZoneID: &zoneID},
+			{Type: model.TaskRebalance, MotorbikeID: 10, TargetZoneID: &inactiveID},
+			{Type: model.TaskRebalance, MotorbikeID: 10, AssigneeID: &userID},
+			{Type: model.TaskRebalance, MotorbikeID: 10, DueAt: &past},
+		} {
+			_, err := f.service.Create(ctx, input)
+			assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		}
+		_, err := f.service.Create(ctx, service.CreateTaskInput{Type: model.TaskRebalance, MotorbikeID: 10, TargetZoneID: &missingID})
+		assertAppErrorCode(t, err, errorx.ErrNotFound)
+		_, err = f.service.Create(ctx, service.CreateTaskInput{Type: model.TaskRebalance, MotorbikeID: 99})
+		assertAppErrorCode(t, err, errorx.ErrNotFound)
+
+		task, err := f.service.Create(ctx, service.CreateTaskInput{
+			Type: model.TaskRebalance, MotorbikeID: 10, TargetZoneID: &zoneID, AssigneeID: &operatorID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, operatorID, *task.AssigneeID)
+	})
+
+	t.Run("Claim Release And Assignment", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+		task := f.create(t, model.TaskCollect, 10)
+
+		_, err := f.service.Assign(ctx, task.ID, 1)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.service.Assign(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+
+		_, err = f.service.Claim(ctx, task.ID, testOtherOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		claimed, err := f.service.Claim(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskClaimed, claimed.Status)
+		assert.NotNil(t, claimed.ClaimedAt)
+
+		_, err = f.service.Claim(ctx, task.ID, testOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.service.Assign(ctx, task.ID, testOtherOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.service.Release(ctx, task.ID, testOtherOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+
+		mine, err := f.service.ListByOperator(ctx, testOperatorID)
+		assert.NoError(t, err)
+		assert.Len(t, mine, 1)
+
+		released, err := f.service.Release(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskOpen, released.Status)
+		assert.Nil(t, released.AssigneeID)
+
+		_, err = f.service.Claim(ctx, task.ID, testOtherOperatorID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Complete Requires Photo Proof From Claimer", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+		task := f.create(t, model.TaskBatterySwap, 10)
+		photo := testJPEG(t, 64, 32, 1)
+
+		_, err := f.complete(task.ID, testOperatorID, photo)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, 0, countFiles(t, f.dir))
+
+		_, err = f.service.Claim(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+		_, err = f.complete(task.ID, testOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.complete(task.ID, testOperatorID, photo, []byte("not an image"))
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		_, err = f.complete(task.ID, testOtherOperatorID, photo)
+		assertAppErrorCode(t, err, errorx.ErrForbidden)
+		assert.Equal(t, 0, countFiles(t, f.dir))
+
+		completed, err := f.complete(task.ID, testOperatorID, photo)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskCompleted, completed.Status)
+		assert.Equal(t, int64(testOperatorID), *completed.ClosedBy)
+		assert.Equal(t, 2, countFiles(t, f.dir))
+
+		stored, err := f.service.Get(ctx, task.ID)
+		assert.NoError(t, err)
+		assert.Len(t, stored.Photos, 1)
+
+		_, err = f.service.Cancel(ctx, task.ID, testAdminID, "")
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+	})
+
+	t.Run("Rebalance Completes Only Inside Target Zone", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+		zoneID := int64(1)
+		task, err := f.service.Create(ctx, service.CreateTaskInput{Type: model.TaskRebalance, MotorbikeID: 10, TargetZoneID: &zoneID})
+		assert.NoError(t, err)
+		_, err = f.service.Claim(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+
+		_, err = f.complete(task.ID, testOperatorID, testJPEG(t, 16, 16, 1))
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		assert.Equal(t, 0, countFiles(t, f.dir))
+
+		f.moveMotorbike(t, 10, 41.005, 29.005)
+		completed, err := f.complete(task.ID, testOperatorID, testJPEG(t, 16, 16, 1))
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskCompleted, completed.Status)
+	})
+
+	t.Run("Cancel Closes Task And Allows New One", func(t *testing.T) {
+		f := newTaskFixture(t, taskMotorbike(10, 41.02, 29.02))
+		task := f.create(t, model.TaskInspect, 10)
+
+		cancelled, err := f.service.Cancel(ctx, task.ID, testAdminID, "Gerek kalmadı")
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskCancelled, cancelled.Status)
+		assert.Equal(t, "Gerek kalmadı", cancelled.ResolutionNote)
+
+		_, err = f.service.Claim(ctx, task.ID, testOperatorID)
+		assertAppErrorCode(t, err, errorx.ErrInvalidRequest)
+		f.create(t, model.TaskInspect, 10)
+	})
+}
+
+func TestNearbyTasks(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture(t,
+		taskMotorbike(10, 41.0, 29.0),
+		taskMotorbike(11, 41.005, 29.0), // ~556 m
+		taskMotorbike(12, 41.002, 29.0), // ~222 m
+		taskMotorbike(13, 41.1, 29.0),   // ~11 km
+		taskMotorbike(14, 41.001, 29.0),
+	)
+	near := f.create(t, model.TaskCollect, 10)
+	farther := f.create(t, model.TaskCollect, 11)
+	middle := f.create(t, model.TaskCollect, 12)
+	f.create(t, model.TaskCollect, 13)
+	assigned := f.create(t, model.TaskCollect, 14)
+	_, err := f.service.Assign(ctx, assigned.ID, testOtherOperatorID)
+	assert.NoError(t, err)
+	claimed := f.create(t, model.TaskInspect, 10)
+	_, err = f.service.Claim(ctx, claimed.ID, testOtherOperatorID)
+	assert.NoError(t, err)
+
+	nearby, err := f.service.FindNearby(ctx, testOperatorID, geo.Point{Lat: 41.0, Lng: 29.0}, 1000, 10)
+	assert.NoError(t, err)
+	ids := make([]int64, len(nearby))
+	for i, item := range nearby {
+		ids[i] = item.Task.ID
+	}
+	assert.Equal(t, []int64{near.ID, middle.ID, farther.ID}, ids)
+	assert.InDelta(t, 222, nearby[1].DistanceMeters, 5)
+
+	nearby, err = f.service.FindNearby(ctx, testOtherOperatorID, geo.Point{Lat: 41.0, Lng: 29.0}, 1000, 2)
+	assert.NoError(t, err)
+	assert.Len(t, nearby, 2)
+	assert.Equal(t, assigned.ID, nearby[1].Task.ID)
+}
+
+func TestGenerateTasks(t *testing.T) {
+	ctx := context.Background()
+	level := func(percent int) *int { return &percent }
+
+	t.Run("Low Battery Opens Battery Swap Once", func(t *testing.T) {
+		low := taskMotorbike(10, 41.0, 29.0)
+		low.BatteryLevel = level(12)
+		seen := time.Now().Add(-time.Minute)
+		low.LastSeenAt = &seen
+		full := taskMotorbike(11, 41.0, 29.0)
+		full.BatteryLevel = level(80)
+		rented := taskMotorbike(12, 41.0, 29.0)
+		rented.Status = model.BikeRented
+		rented.BatteryLevel = level(5)
+		f := newTaskFixture(t, low, full, rented)
+		for _, id := range []int64{10, 11, 12} {
+			assert.NoError(t, f.rides.Create(ctx, &model.Ride{MotorbikeID: id, UserID: 1, StartTime: time.Now().Add(-time.Hour)}))
+		}
+
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 1)
+		task := f.tasks.tasks[0]
+		assert.Equal(t, model.TaskBatterySwap, task.Type)
+		assert.Equal(t, model.TaskSourceLowBattery, task.Source)
+		assert.Nil(t, task.CreatedBy)
+		assert.Equal(t, int64(10), task.MotorbikeID)
+
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 1)
+
+		// Görev tamamlandı ama cihazdan yeni ölçüm gelmedi: tekrar açılmaz
+		_, err := f.service.Claim(ctx, task.ID, testOperatorID)
+		assert.NoError(t, err)
+		_, err = f.complete(task.ID, testOperatorID, testJPEG(t, 16, 16, 1))
+		assert.NoError(t, err)
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 1)
+
+		// Yeni ölçüm hâlâ düşük: yeni görev açılır
+		motorbike, _ := f.motorbikes.GetByID(ctx, 10)
+		seen = time.Now().Add(time.Second)
+		motorbike.LastSeenAt = &seen
+		assert.NoError(t, f.motorbikes.Update(ctx, motorbike))
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 2)
+	})
+
+	t.Run("Idle Motorbike Opens Rebalance", func(t *testing.T) {
+		idle := taskMotorbike(10, 41.0, 29.0)
+		busy := taskMotorbike(11, 41.0, 29.0)
+		fresh := taskMotorbike(12, 41.0, 29.0)
+		fresh.CreatedAt = time.Now().Add(-time.Hour)
+		f := newTaskFixture(t, idle, busy, fresh)
+		ended := time.Now().Add(-2 * time.Hour)
+		assert.NoError(t, f.rides.Create(ctx, &model.Ride{MotorbikeID: 11, UserID: 1, StartTime: time.Now().Add(-100 * time.Hour), EndTime: &ended}))
+		assert.NoError(t, f.rides.Create(ctx, &model.Ride{MotorbikeID: 10, UserID: 1, StartTime: time.Now().Add(-100 * time.Hour)}))
+
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 1)
+		task := f.tasks.tasks[0]
+		assert.Equal(t, model.TaskRebalance, task.Type)
+		assert.Equal(t, model.TaskSourceIdle, task.Source)
+		assert.Equal(t, int64(10), task.MotorbikeID)
+
+		// Yeni kapanan görevden sonra boşta kalma süresi yeniden başlar
+		_, err := f.service.Cancel(ctx, task.ID, testAdminID, "")
+		assert.NoError(t, err)
+		assert.NoError(t, f.service.Generate(ctx))
+		assert.Len(t, f.tasks.tasks, 1)
+	})
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelemetryIngest(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func() (*service.TelemetryService, *fakeTelemetryRepo, *fakeMotorbikeRepo) {
+		motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable))
+		telemetry := &fakeTelemetryRepo{}
+		return service.NewTelemetryService(telemetry, motorbikes, newFakeRideRepo(), &fakeTxManager{}), telemetry, motorbikes
+	}
+
+	sample := func(at time.Time, lat, lng float64) model.MotorbikeTelemetry {
+		return model.MotorbikeTelemetry{RecordedAt: at, Latitude: lat, Longitude: lng}
+	}
+
+	t.Run("Latest Sample Updates Motorbike State", func(t *testing.T) {
+		svc, telemetry, motorbikes := setup()
+		now := time.Now().UTC()
+		battery := 64
+
+		latest := sample(now, 41.03, 28.98)
+		latest.LockStatus = model.Unlocked
+		latest.BatteryLevel = &battery
+
+		// Paket içinde sıra karışık gelebilir, en yeni ölçüm esas alınır
+		err := svc.Ingest(ctx, 1, []model.MotorbikeTelemetry{latest, sample(now.Add(-time.Minute), 41.01, 28.95)})
+		assert.NoError(t, err)
+		assert.Len(t, telemetry.samples, 2)
+		assert.Equal(t, int64(1), telemetry.samples[1].MotorbikeID)
+
+		m, _ := motorbikes.GetByID(ctx, 1)
+		assert.Equal(t, 41.03, m.LocationLatitude)
+		assert.Equal(t, 28.98, m.LocationLongitude)
+		assert.Equal(t, model.Unlocked, m.LockStatus)
+		assert.Equal(t, 64, *m.BatteryLevel)
+		assert.True(t, m.LastSeenAt.Equal(now))
+	})
+
+	t.Run("Late Batch Does Not Overwrite Newer State", func(t *testing.T) {
+		svc, telemetry, motorbikes := setup()
+		now := time.Now().UTC()
+
+		assert.NoError(t, svc.Ingest(ctx, 1, []model.MotorbikeTelemetry{sample(now, 41.03, 28.98)}))
+
+		old := sample(now.Add(-10*time.Minute), 40.99, 29.02)
+		old.LockStatus = model.Unlocked
+		assert.NoError(t, svc.Ingest(ctx, 1, []model.MotorbikeTelemetry{old}))
+
+		// Eski ölçüm geçmişe kaydedilir ama motorun anlık durumu değişmez
+		assert.Len(t, telemetry.samples, 2)
+		m, _ := motorbikes.GetByID(ctx, 1)
+		assert.Equal(t, 41.03, m.LocationLatitude)
+		assert.Equal(t, model.Locked, m.LockStatus)
+		assert.True(t, m.LastSeenAt.Equal(now))
+	})
+
+	t.Run("Future Sample Rejected", func(t *testing.T) {
+		svc, telemetry, _ := setup()
+
+		err := svc.Ingest(ctx, 1, []model.MotorbikeTelemetry{sample(time.Now().Add(time.Hour), 41.03, 28.98)})
+		assert.Error(t, err)
+		assert.Empty(t, telemetry.samples)
+	})
+
+	t.Run("List Range Validation", func(t *testing.T) {
+		svc, _, _ := setup()
+		now := time.Now().UTC()
+
+		_, err := svc.List(ctx, 1, now, now.Add(-time.Hour))
+		assert.Error(t, err)
+
+		_, err = svc.List(ctx, 1, now.Add(-8*24*time.Hour), now)
+		assert.Error(t, err)
+
+		assert.NoError(t, svc.Ingest(ctx, 1, []model.MotorbikeTelemetry{
+			sample(now.Add(-2*time.Hour), 41.00, 28.90),
+			sample(now.Add(-30*time.Minute), 41.01, 28.91),
+		}))
+		samples, err := svc.List(ctx, 1, now.Add(-time.Hour), now)
+		assert.NoError(t, err)
+		assert.Len(t, samples, 1)
+	})
+}
+
+func TestDeviceKey(t *testing.T) {
+	ctx := context.Background()
+	motorbikes := newFakeMotorbikeRepo(testMotorbike(1, model.BikeAvailable), testMotorbike(2, model.BikeAvailable))
+	svc := service.NewMotorbikeService(motorbikes, service.NoopAuditor{})
+
+	key, err := svc.RotateDeviceKey(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	// Anahtarın kendisi saklanmaz
+	stored, _ := motorbikes.GetByID(ctx, 1)
+	assert.NotEqual(t, key, stored.DeviceKeyHash)
+
+	m, err := svc.AuthenticateDevice(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), m.ID)
+
+	_, err = svc.AuthenticateDevice(ctx, "")
+	assert.Error(t, err)
+
+	// Yeni anahtar üretildiğinde eskisi geçersiz olur
+	newKey, err := svc.RotateDeviceKey(ctx, 1)
+	assert.NoError(t, err)
+	_, err = svc.AuthenticateDevice(ctx, key)
+	assert.Error(t, err)
+	_, err = svc.AuthenticateDevice(ctx, newKey)
+	assert.NoError(t, err)
+}
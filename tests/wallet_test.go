@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/payment"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func topUp(t *testing.T, f *rideFixture, userID, amount int64) {
+	adminID := int64(99)
+	_, err := f.wallet.Adjust(context.Background(), service.WalletPosting{
+		UserID:      userID,
+		Kind:        model.LedgerTopUp,
+		Amount:      amount,
+		Description: "test yüklemesi",
+		CreatedBy:   &adminID,
+	})
+	assert.NoError(t, err)
+}
+
+func walletBalance(t *testing.T, f *rideFixture, userID int64) int64 {
+	wallet, err := f.wallet.GetWallet(context.Background(), userID)
+	assert.NoError(t, err)
+	return wallet.Balance
+}
+
+// assertLedgerBalanced her işlemin ve tüm defterin toplamının sıfır olduğunu doğrular
+func assertLedgerBalanced(t *testing.T, ledger *fakeLedgerRepo) {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	totals := map[int64]int64{}
+	var total int64
+	for _, e := range ledger.entries {
+		totals[e.TransactionID] += e.Amount
+		total += e.Amount
+	}
+	for id, sum := range totals {
+		assert.Zero(t, sum, "işlem %d dengesiz", id)
+	}
+	assert.Zero(t, total)
+}
+
+func TestWallet(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{testUser(1, model.StatusActive)}
+
+	t.Run("Empty Wallet", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		wallet, err := f.wallet.GetWallet(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), wallet.Balance)
+		assert.Equal(t, "TRY", wallet.Currency)
+
+		transactions, err := f.wallet.ListTransactions(ctx, 1, &query.Pagination{Page: 1, PageSize: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, transactions)
+
+		_, err = f.wallet.GetWallet(ctx, 42)
+		assert.Error(t, err)
+	})
+
+	t.Run("Finish Ride Debits Wallet", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+		topUp(t, f, 1, 10000)
+
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Greater(t, finished.Cost, int64(0))
+
+		assert.Equal(t, 10000-finished.Cost, walletBalance(t, f, 1))
+		assert.Equal(t, finished.Cost, f.ledger.systemBalance(model.SystemAccountRideRevenue))
+		assertLedgerBalanced(t, f.ledger)
+
+		transactions, err := f.wallet.ListTransactions(ctx, 1, &query.Pagination{Page: 1, PageSize: 10})
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 2)
+		assert.Equal(t, model.LedgerRideCharge, transactions[0].Kind)
+		assert.Equal(t, -finished.Cost, transactions[0].Amount)
+		assert.Equal(t, ride.ID, *transactions[0].RideID)
+		assert.Equal(t, 10000-finished.Cost, transactions[0].BalanceAfter)
+	})
+
+	t.Run("Ride Charge May Overdraw", func(t *testing.T) {
+		f := newRideFixture(users, []model.Motorbike{testMotorbike(10, model.BikeAvailable)})
+
+		ride := startTestRide(t, f, 1, 10, 10*time.Minute-time.Second)
+		f.provider.Next(payment.OpCapture, payment.Decline)
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, -finished.Cost, walletBalance(t, f, 1))
+	})
+
+	t.Run("Adjustments", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		topUp(t, f, 1, 500)
+
+		_, err := f.wallet.Adjust(ctx, service.WalletPosting{UserID: 1, Kind: model.LedgerTopUp, Amount: -100})
+		assert.Error(t, err)
+		_, err = f.wallet.Adjust(ctx, service.WalletPosting{UserID: 1, Kind: model.LedgerRideCharge, Amount: 100})
+		assert.Error(t, err)
+
+		// Düzeltme bakiyeyi eksiye düşüremez
+		_, err = f.wallet.Adjust(ctx, service.WalletPosting{UserID: 1, Kind: model.LedgerAdjustment, Amount: -600})
+		assert.Error(t, err)
+		_, err = f.wallet.Adjust(ctx, service.WalletPosting{UserID: 1, Kind: model.LedgerAdjustment, Amount: -200})
+		assert.NoError(t, err)
+
+		_, err = f.wallet.Adjust(ctx, service.WalletPosting{UserID: 1, Kind: model.LedgerPromoCredit, Amount: 300})
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(600), walletBalance(t, f, 1))
+		assert.Equal(t, int64(-300), f.ledger.systemBalance(model.SystemAccountPromotions))
+		assertLedgerBalanced(t, f.ledger)
+	})
+
+	t.Run("Paginates Newest First", func(t *testing.T) {
+		f := newRideFixture(users, nil)
+		for i := int64(1); i <= 5; i++ {
+			topUp(t, f, 1, i*100)
+		}
+
+		pagination := &query.Pagination{Page: 2, PageSize: 2}
+		transactions, err := f.wallet.ListTransactions(ctx, 1, pagination)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), pagination.TotalRows)
+		assert.Equal(t, 3, pagination.TotalPages)
+		assert.Len(t, transactions, 2)
+		assert.Equal(t, int64(300), transactions[0].Amount)
+		assert.Equal(t, int64(600), transactions[0].BalanceAfter)
+		assert.Equal(t, int64(200), transactions[1].Amount)
+	})
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/model"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/internal/service"
+	"github.com/Furkanturan8/motorbike-rental-backend-v2/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// boxZone [minLng, minLat] - [maxLng, maxLat] dikdörtgeni olan bir alan oluşturur
+func boxZone(id int64, zoneType model.ZoneType, enforcement model.ZoneEnforcement, surcharge int64, minLng, minLat, maxLng, maxLat float64) model.Zone {
+	geometry, _ := json.Marshal(map[string]interface{}{
+		"type": "Polygon",
+		"coordinates": [][][]float64{{
+			{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+		}},
+	})
+	return model.Zone{
+		BaseModel:   model.BaseModel{ID: id},
+		Name:        string(zoneType),
+		Type:        zoneType,
+		Geometry:    geometry,
+		Enforcement: enforcement,
+		Surcharge:   surcharge,
+		IsActive:    true,
+	}
+}
+
+func TestEvaluateParking(t *testing.T) {
+	city := boxZone(1, model.ZoneOperating, model.ZoneReject, 0, 28.9, 40.9, 29.1, 41.1)
+	square := boxZone(2, model.ZoneNoParking, model.ZoneReject, 0, 28.95, 40.95, 28.96, 40.96)
+	sidewalk := boxZone(3, model.ZoneNoParking, model.ZoneSurcharge, 2500, 29.0, 41.0, 29.01, 41.01)
+	dock := boxZone(4, model.ZonePreferredParking, model.ZoneReject, 0, 29.05, 41.05, 29.06, 41.06)
+	zones := []model.Zone{city, square, sidewalk, dock}
+
+	t.Run("No Zones Allows Anywhere", func(t *testing.T) {
+		decision, err := service.EvaluateParking(nil, geo.Point{Lat: 10, Lng: 10})
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("Inside Operating Area", func(t *testing.T) {
+		decision, err := service.EvaluateParking(zones, geo.Point{Lat: 41.02, Lng: 29.02})
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Empty(t, decision.Surcharges)
+	})
+
+	t.Run("Outside Operating Area Rejected", func(t *testing.T) {
+		decision, err := service.EvaluateParking(zones, geo.Point{Lat: 41.5, Lng: 29.02})
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+	})
+
+	t.Run("Outside Operating Area Surcharged", func(t *testing.T) {
+		lenient := boxZone(1, model.ZoneOperating, model.ZoneSurcharge, 5000, 28.9, 40.9, 29.1, 41.1)
+		decision, err := service.EvaluateParking([]model.Zone{lenient}, geo.Point{Lat: 41.5, Lng: 29.02})
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Len(t, decision.Surcharges, 1)
+		assert.Equal(t, int64(5000), decision.Surcharges[0].Amount)
+	})
+
+	t.Run("No Parking Zone Rejected", func(t *testing.T) {
+		decision, err := service.EvaluateParking(zones, geo.Point{Lat: 40.955, Lng: 28.955})
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+	})
+
+	t.Run("No Parking Zone Surcharged", func(t *testing.T) {
+		decision, err := service.EvaluateParking(zones, geo.Point{Lat: 41.005, Lng: 29.005})
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.PriceLineParkingSurcharge, decision.Surcharges[0].Code)
+		assert.Equal(t, int64(2500), decision.Surcharges[0].Amount)
+	})
+
+	t.Run("Preferred Parking", func(t *testing.T) {
+		decision, err := service.EvaluateParking(zones, geo.Point{Lat: 41.055, Lng: 29.055})
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, int64(4), decision.PreferredZone.ID)
+	})
+}
+
+func TestFinishRideZones(t *testing.T) {
+	ctx := context.Background()
+
+	newFixture := func(lat, lng float64, zones ...model.Zone) *rideFixture {
+		bike := testMotorbike(10, model.BikeAvailable)
+		bike.LocationLatitude = lat
+		bike.LocationLongitude = lng
+		f := newRideFixture([]model.User{testUser(1, model.StatusActive)}, []model.Motorbike{bike})
+		f.zones.zones = zones
+		return f
+	}
+
+	t.Run("Rejects Ride Ended In No Parking Zone", func(t *testing.T) {
+		f := newFixture(41.005, 29.005, boxZone(1, model.ZoneNoParking, model.ZoneReject, 0, 29.0, 41.0, 29.01, 41.01))
+		ride := startTestRide(t, f, 1, 10, time.Minute-time.Second)
+
+		_, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.Error(t, err)
+		stillOpen, _ := f.rides.GetByID(ctx, ride.ID)
+		assert.Nil(t, stillOpen.EndTime)
+	})
+
+	t.Run("Adds Surcharge To Cost", func(t *testing.T) {
+		f := newFixture(41.005, 29.005, boxZone(1, model.ZoneNoParking, model.ZoneSurcharge, 2500, 29.0, 41.0, 29.01, 41.01))
+		ride := startTestRide(t, f, 1, 10, time.Minute-time.Second)
+
+		finished, err := f.service.FinishRide(ctx, ride.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000+300+2500), finished.Cost)
+
+		breakdown, _ := f.service.GetPriceBreakdown(ctx, ride.ID, 1, model.UserRole)
+		assert.Equal(t, int64(2500), lineAmount(breakdown, model.PriceLineParkingSurcharge))
+	})
+
+	t.Run("Active Ride Shows Parking Status", func(t *testing.T) {
+		f := newFixture(41.5, 29.02, boxZone(1, model.ZoneOperating, model.ZoneReject, 0, 28.9, 40.9, 29.1, 41.1))
+		startTestRide(t, f, 1, 10, time.Minute-time.Second)
+
+		active, err := f.service.GetActiveRide(ctx, 1)
+		assert.NoError(t, err)
+		assert.False(t, active.Parking.Allowed)
+		assert.NotEmpty(t, active.Parking.Reason)
+	})
+}